@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/curtbushko/zoom-to-box/internal/config"
+	"github.com/curtbushko/zoom-to-box/internal/download"
+	"github.com/curtbushko/zoom-to-box/internal/processor"
+	"github.com/curtbushko/zoom-to-box/internal/progress"
+	"github.com/curtbushko/zoom-to-box/internal/timefmt"
+)
+
+// createCleanupCommand creates the subcommand that removes local recordings already verified in Box
+func createCleanupCommand() *cobra.Command {
+	var (
+		statusFile string
+		olderThan  string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "cleanup [--older-than <age>]",
+		Short: "Remove local recordings whose Box upload was verified",
+		Long: `Remove the local MP4 recordings, and their metadata files, that the download
+status file records as uploaded to Box and verified (size and checksum) before
+--older-than. Recordings still downloading, with a failed or unverified upload,
+or outside the download output directory are kept.
+
+--older-than takes a relative age (30d, 4w, 6m, 1y) or a date (YYYY-MM-DD).
+The status file keeps the uploads indexed, so later runs do not download the
+removed recordings again. Use --dry-run to list the files without removing them.`,
+		Example: `  zoom-to-box cleanup --older-than 30d --dry-run
+  zoom-to-box cleanup --older-than 2024-06-01
+  zoom-to-box cleanup --status-file downloads/status.json --older-than 2w`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			configPath := "config.yaml"
+			if configFile != "" {
+				configPath = configFile
+			}
+
+			cfg, err := loadConfig(configPath)
+			if err != nil {
+				return fmt.Errorf("failed to load configuration: %w", err)
+			}
+			if outputDir != "" {
+				cfg.Download.OutputDir = outputDir
+			}
+
+			before, err := config.ParseDateValue(olderThan, timefmt.Now())
+			if err != nil || before == nil {
+				return fmt.Errorf("invalid --older-than %q: expected a relative age like 30d or a date (YYYY-MM-DD)", olderThan)
+			}
+
+			if statusFile == "" {
+				statusFile = cfg.Download.StatusFilePath()
+			}
+			if _, err := os.Stat(statusFile); err != nil {
+				return fmt.Errorf("status file %s not found: %w", statusFile, err)
+			}
+
+			return runCleanup(cmd, cfg, statusFile, *before)
+		},
+	}
+
+	cmd.Flags().StringVar(&statusFile, "status-file", "", "download status file recording the uploads (default: download.status_file)")
+	cmd.Flags().StringVar(&olderThan, "older-than", "30d", "only remove recordings uploaded before this age or date")
+
+	return cmd
+}
+
+// runCleanup removes the local recordings verified in Box before the given time
+func runCleanup(cmd *cobra.Command, cfg *config.Config, statusFile string, before time.Time) error {
+	statusTracker, err := download.NewStatusTracker(statusFile)
+	if err != nil {
+		return fmt.Errorf("failed to open status file: %w", err)
+	}
+
+	var sidecars []string
+	if serializer, err := processor.MetadataSerializerFor(cfg.Metadata.Format); err == nil && serializer != nil {
+		sidecars = append(sidecars, serializer.Extension())
+	}
+
+	summary := download.CleanupUploaded(statusTracker, download.CleanupOptions{
+		BaseDir:           cfg.Download.OutputDir,
+		Before:            before,
+		SidecarExtensions: sidecars,
+		DryRun:            dryRun,
+	})
+
+	verb := "Removed"
+	if dryRun {
+		verb = "DRY RUN: would remove"
+	}
+	for _, file := range summary.Files {
+		if verbose || dryRun {
+			cmd.Printf("  %s (%s, uploaded %s)\n", file.Path, progress.FormatBytes(file.Size), timefmt.Format(file.UploadDate))
+		}
+	}
+	cmd.Printf("%s %d files uploaded before %s, %s\n", verb, len(summary.Files), before.Format("2006-01-02"), progress.FormatBytes(summary.Bytes))
+	if summary.Unverified > 0 {
+		cmd.Printf("Kept %d uploaded recordings that Box did not verify\n", summary.Unverified)
+	}
+	for _, cleanupErr := range summary.Errors {
+		cmd.Printf("  %v\n", cleanupErr)
+	}
+	if len(summary.Errors) > 0 {
+		return fmt.Errorf("%d files could not be removed", len(summary.Errors))
+	}
+	return nil
+}
@@ -0,0 +1,642 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// createConfigCommand creates the config help subcommand
+func createConfigCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Show configuration file structure and examples",
+		Long:  "Display the required configuration file structure, authentication methods, environment variables, and comprehensive examples",
+		Run: func(cmd *cobra.Command, args []string) {
+			configHelp := `Configuration File Structure (config.yaml):
+
+ZOOM API CONFIGURATION (Required):
+=================================
+zoom:
+  account_id: "your_zoom_account_id"       # Zoom Account ID from Server-to-Server OAuth app
+  client_id: "your_zoom_client_id"         # Client ID from Server-to-Server OAuth app  
+  client_secret: "your_zoom_client_secret" # Client Secret from Server-to-Server OAuth app
+  client_secret_next: ""                   # Optional next secret, tried if client_secret is rejected (for rotation)
+  region: "us"                             # Zoom cloud: us, eu (EU data residency) or gov (Zoom for Government)
+  base_url: ""                             # Zoom API base URL (default: from region, https://api.zoom.us/v2 for us)
+  oauth_url: ""                            # OAuth token endpoint (default: from region, https://zoomgov.com/oauth/token for gov)
+  include_webinars: false                  # Also migrate recordings of webinars each user hosted (needs webinar:read)
+  account_recordings: false                # List the whole account's recordings once per run instead of per user (needs recording:read:admin)
+  page_size: 300                           # Recordings per list recordings API page, 1-300 (default: 300)
+  include_trash: false                     # Also migrate meeting recordings users moved to the Zoom trash (kept 30 days)
+  restore_trash: false                     # Restore trash recordings before downloading them (needs recording:write:admin)
+  disable_passcodes: false                 # Remove the passcode of recordings whose download shows the passcode page,
+                                           # restoring it right after the download (needs recording:write:admin). The
+                                           # passcode is saved in the status file first; a run that stops before
+                                           # restoring it sets it again when the next run starts
+  rate_tier: "auto"                        # Zoom plan tier for API pacing: auto, free, pro or business (default: auto)
+  requests_per_second: 0                   # Override the tier's API request rate (default: 0 = from tier)
+  max_concurrent_requests: 0               # Override the tier's API requests in flight (default: 0 = from tier)
+  # auto asks Zoom for the account's per-second limit at startup and assumes pro if it is not reported.
+  # Tier defaults use 80% of Zoom's Medium API limit: free 1.6/s x1, pro 16/s x4, business 48/s x8.
+  # Requests slow down automatically after 429 responses and recover gradually.
+
+# REQUIRED SCOPES: recording:read, user:read, meeting:read
+# Uses Server-to-Server OAuth (account-level access, no user tokens needed)
+
+DOWNLOAD CONFIGURATION:
+======================
+download:
+  output_dir: "./downloads"        # Local download directory (default: ./downloads)
+  concurrent_limit: 3              # Recording files of a user downloaded at the same time; uploads and uploads.csv
+                                   # entries stay in recording order (default: 3, range: 1-10)
+  retry_attempts: 3                # Max retry attempts for failed downloads (default: 3)
+  timeout_seconds: 300             # Download timeout in seconds (default: 300 = 5 minutes)
+  per_file_timeout: "2h"           # Fail a file whose download, retries included, takes longer (default: no limit)
+  size_tolerance_percent: 1        # Reject downloads whose size differs from Zoom's reported size by more (default: 1)
+  from_date: "2024-01-01"          # Earliest recording date, YYYY-MM-DD or relative like 90d (default: 2020-06-30)
+  to_date: "7d"                    # Latest recording date, YYYY-MM-DD or relative like 7d (default: today)
+  checksum_algorithm: "sha256"     # Checksum for downloaded files: sha256 or blake3 (default: sha256)
+  thumbnails: false                # Also archive recording thumbnails and reference them in metadata (default: false)
+  transcript_formats: []           # Download WebVTT transcripts and captions with the recordings and convert them to
+                                   # these sidecars next to them, uploaded alongside: srt, txt (default: [] = none)
+  preview_minutes: 0               # Download only about the first N minutes of each MP4 as <name>-preview.mp4 (default: 0 = full files)
+  # Preview sizes are estimated from the file size and duration and fetched with HTTP range requests,
+  # for a low-cost triage archive before a full migration. Previews may not play in every player.
+  stream: false                    # Experimental: pipe MP4s of 20 MB and up from Zoom straight into a Box upload
+                                   # session, buffering one part at a time, with no local copy (default: false)
+  progress_file: false             # Rewrite <output_dir>/progress.json every 5 seconds with the current user, file,
+                                   # percent, counts and ETA, for dashboards and scripts (default: false)
+  disk_reserve_gb: 0               # Keep this many GB free on the output disk; a download that does not fit stops
+                                   # the run cleanly with exit code 75 instead of failing mid-write (default: 0 = not checked)
+  disk_wait: "0s"                  # Pause this long for space to be freed before stopping (default: 0s = stop immediately)
+  max_user_gb: 0                   # Cap each user's local recordings; the user's remaining files are left for the
+                                   # next run, e.g. once --delete-after-upload has freed space (default: 0 = no cap)
+  chunk_size: 65536                # Bytes written to disk per read of a download, 4096-16777216 (default: 65536)
+  read_buffer_size: 0              # Read buffer of download connections in bytes, up to 16777216; raise to 1048576
+                                   # or more on fast links (default: 0 = Go's 4096)
+  status_file: ""                  # Per-file download and upload status; an interrupted run resumes after the last
+                                   # file recorded as uploaded without checking Box again, and retry-uploads reads
+                                   # failed uploads from it. Users whose files are all recorded as uploaded to their
+                                   # current folder skip Box entirely (default: <output_dir>/.status.json)
+
+LOGGING CONFIGURATION:
+=====================
+logging:
+  level: "info"                    # Log level: debug, info, warn, error (default: info)
+  file: "./zoom-downloader.log"    # Log file path (default: ./zoom-downloader.log)
+  console: true                    # Enable console output (default: true)
+  json_format: false               # Use JSON log format (default: false)
+  rotation:                        # Log file rotation (default: one growing file)
+    max_size_mb: 100               # Rotate before the file grows past this size (0 = no limit)
+    interval: "24h"                # Rotate when a new UTC-aligned interval starts (0 = never)
+    max_backups: 7                 # Rotated files kept (0 = all)
+    max_age: "720h"                # Remove rotated files older than this (0 = never)
+    compress: true                 # Gzip rotated files
+  json_schema:                     # JSON key names, e.g. Elastic Common Schema for ELK
+    timestamp_key: "@timestamp"    # Default: timestamp
+    level_key: "log.level"         # Default: level
+    message_key: "message"         # Default: message
+    request_id_key: "trace.id"     # Default: request_id
+    static_fields:                 # Added to every JSON line
+      service.name: "zoom-to-box"
+
+BOX INTEGRATION (Optional):
+==========================
+box:
+  enabled: false                   # Enable Box uploads (default: false)
+  client_id: "your_box_client_id"  # Box OAuth 2.0 client ID
+  client_secret: "your_box_client_secret" # Box OAuth 2.0 client secret
+  client_secret_next: ""           # Optional next secret, tried if client_secret is rejected (for rotation)
+  enterprise_id: "your_box_enterprise_id" # Box enterprise ID for client credentials auth
+  upload_concurrency: 4            # Chunked upload parts in flight per file (1-16, default: 4)
+  upload_part_size: 0              # Chunked upload part size in bytes, 8388608-134217728, used when Box's upload
+                                   # session does not assign one; Box's own size always wins (default: 0 = 8 MiB)
+  upload_buffer_size: 0            # Write buffer of Box connections in bytes, up to 16777216 (default: 0 = Go's 4096)
+  cleanup_empty_folders: false     # Remove empty date folders this run created when uploads failed (default: false)
+  upload_as_user: false            # Create folders and upload as each Box user (As-User header) so they own the files (default: false)
+  create_zoom_folder_if_missing: false # Create a user's missing "zoom" folder, owned by the user, instead of failing them (default: false)
+  destination_folder_id: ""        # Upload into <folder>/<user>/<YYYY>/<MM>/<DD> below this shared folder instead of each
+                                   # user's "zoom" folder; user folders are created as needed (default: none)
+  search_existing: false           # Check for existing files with one Box search of the user's zoom folder instead of
+                                   # listing each date folder; falls back to listing if search fails (default: false)
+  create_shared_links: ""          # Share each uploaded MP4 and record the link: open, company or collaborators (default: none)
+  metadata_template:               # Attach a Box metadata template instance to each uploaded recording (optional)
+    scope: "enterprise"            # Template scope: enterprise or global (default: enterprise)
+    template_key: "zoomRecording"  # Template key ("" = no template)
+    # Fields: uuid, meeting_id, topic, host_id, host_email, account_id, start_time, duration,
+    #         recording_start, recording_end, file_type, file_size, recording_type
+    fields:                        # Template key -> recording field
+      meetingUuid: "uuid"
+      host: "host_email"
+      startTime: "start_time"
+      duration: "duration"
+  auth_mode: "client_credentials"  # client_credentials (enterprise app) or user (individual account)
+  token_file: "box-token.json"     # Refresh token saved by 'zoom-to-box box login' (auth_mode: user)
+  redirect_url: "http://localhost:8085/callback" # Redirect URI registered on the Box app for 'box login'
+  # Note: Files are uploaded to user-specific folders within the service account's root folder
+  # With auth_mode: user, run 'zoom-to-box box login' once; tokens are refreshed and saved automatically
+
+GOOGLE DRIVE INTEGRATION (Optional, alternative to Box):
+=======================================================
+google_drive:
+  enabled: false                   # Enable Google Drive uploads (default: false, cannot be combined with box)
+  credentials_file: "./service-account.json" # Service account key with domain-wide delegation (drive scope)
+  root_folder: "zoom"              # Folder in each user's My Drive holding <year>/<month>/<day> (default: zoom)
+
+AMAZON S3 INTEGRATION (Optional, alternative to Box):
+====================================================
+s3:
+  enabled: false                   # Enable S3 uploads (default: false, cannot be combined with box or google_drive)
+  bucket: "zoom-recordings"        # Destination bucket
+  region: "us-east-1"              # AWS region (default: from the AWS credential chain)
+  key_template: "{user}/{year}/{month}/{day}/{filename}" # Object key; also supports {email} (default shown)
+  server_side_encryption: "aws:kms" # Optional: AES256 or aws:kms
+  kms_key_id: "alias/zoom"         # Optional KMS key for aws:kms (default: AWS managed key)
+  endpoint: ""                     # Optional endpoint for S3-compatible services
+  use_path_style: false            # Use path-style addressing (for S3-compatible services)
+  # Credentials come from the standard AWS chain: AWS_* environment variables,
+  # ~/.aws/config and ~/.aws/credentials (AWS_PROFILE), SSO, web identity or instance roles.
+  # Files larger than 16MB are sent as multipart uploads.
+
+SFTP INTEGRATION (Optional, alternative to Box, for on-prem archival):
+=====================================================================
+sftp:
+  enabled: false                   # Enable SFTP uploads (default: false, only one destination can be enabled)
+  host: "nas.example.com"          # SFTP server
+  port: 22                         # SSH port (default: 22)
+  user: "zoom-archive"             # Remote login
+  private_key_file: "/etc/zoom-to-box/id_ed25519" # Key-based authentication only; passwords are never used
+  known_hosts_file: "./known_hosts" # Host keys the server is verified against (default: ~/.ssh/known_hosts)
+  root_path: "zoom"                # Remote directory holding <user>/<year>/<month>/<day> (default: zoom)
+  # Uses the OpenSSH sftp client, which must be installed. Unknown or changed host keys fail the run;
+  # add the server with: ssh-keyscan nas.example.com >> known_hosts (and check the fingerprint).
+
+WEBDAV INTEGRATION (Optional, alternative to Box, for on-prem archival):
+=======================================================================
+webdav:
+  enabled: false                   # Enable WebDAV uploads (default: false, only one destination can be enabled)
+  url: "https://nas.example.com/dav/zoom" # Collection holding <user>/<year>/<month>/<day>
+  username: "zoom-archive"         # Basic auth user (optional)
+  password: ""                     # Basic auth password (prefer WEBDAV_PASSWORD)
+
+ACTIVE USERS FILTERING (Optional):
+=================================
+active_users:
+  file: "./active_users.txt"       # Path to active users list file
+  check_enabled: true              # Enable user filtering (default: true)
+
+# Active users file format (one email per line):
+# john.doe@company.com
+# jane.smith@company.com
+# # Lines starting with # are comments
+# admin@company.com
+#
+# For different Zoom and Box emails, use comma separation:
+# john.doe@zoomaccount.com,john.doe@company.com
+# admin@zoomaccount.com,admin@company.com
+#
+# Users whose Zoom email changed can be listed by their Zoom user ID instead:
+# KDcuGIm1QgePTO8WbOqwIQ,jane.doe@company.com
+#
+# An optional 4th column sends a user's Box uploads to an existing folder, by ID or
+# by path from the Box root, instead of their zoom folder:
+# sales.rep@company.com,sales.rep@company.com,false,123456789
+# support@company.com,support@company.com,false,Shared/Recordings/Support
+#
+# Generate or update the file from Zoom (keeps existing upload_complete flags):
+#   zoom-to-box users sync [--group <group-id>] [--role-id <role-id>] [--prune]
+#
+# Report Zoom users with recordings who are missing from the file:
+#   zoom-to-box users unmanaged [--from 180d] [--output unmanaged-hosts.csv]
+
+RETRY POLICIES (Optional):
+=========================
+retry:
+  zoom_api:                        # Zoom REST API calls (default: 500ms doubling up to 5s)
+    max_attempts: 4                # Total attempts including the first (default: download.retry_attempts + 1)
+    base_delay: "500ms"            # Delay before the first retry
+    multiplier: 2                  # Growth factor per retry (1 = fixed delay)
+    max_delay: "5s"                # Upper bound for a single delay
+  zoom_download:                   # Recording downloads (default: fixed 1s, download.retry_attempts + 1 attempts)
+    base_delay: "1s"
+  box_api:                         # Box API calls on 429/5xx (default: 3 attempts, 500ms doubling up to 10s)
+    max_attempts: 3
+    jitter: 0.5                    # Fraction of each delay that is randomized, 0-1 (default: 0.5 for Box, 0 for Zoom)
+  box_upload:                      # Box chunked upload parts on 429/5xx (default: 3 attempts, 500ms doubling up to 30s);
+    max_attempts: 3                # a longer Retry-After from Box is honored
+  box_circuit_breaker:             # Pause all Box requests after consecutive network errors, 429s or 5xx responses
+    failure_threshold: 10          # Failed requests in a row that pause Box traffic (default: 10)
+    cooldown: "30s"                # Pause before Box is tried again; another failure pauses again (default: 30s)
+
+RECORDING FILTERS (Optional):
+============================
+filters:
+  min_duration_minutes: 5          # Skip recordings shorter than this (default: 0, no minimum)
+  min_file_size: 1024              # Skip files Zoom reports as smaller than this many bytes (default: 0, no minimum)
+  topic_regex: ""                  # Only archive recordings whose topic matches
+  exclude_topic_regex: "(?i)standup" # Skip recordings whose topic matches
+  meeting_types: [2, 8]            # Only archive these Zoom meeting types (default: all)
+  # Zoom meeting types: 1 instant, 2 scheduled, 3 recurring (no fixed time),
+  # 4 personal meeting ID, 8 recurring (fixed time)
+
+UPLOAD BEHAVIOR (Optional):
+===========================
+upload:
+  metadata_order: "after"          # Upload the metadata JSON "after" (default) or "before" the MP4
+  # With "before", automations that trigger on the JSON sidecar see it first; if it fails
+  # to upload the MP4 is held back and the recording is only tracked once both are present.
+  conflict_policy: "version"       # Box file with the same name but a different size (e.g. a truncated upload):
+                                   # "version" uploads a new version (default), "replace" deletes and re-uploads,
+                                   # "report" keeps it and counts the upload as failed
+  delete_requires_verification: true # With --delete-after-upload, only delete local files once Box reports
+                                   # the same size and SHA1; files that cannot be verified are kept
+  migration_report: false          # Upload MIGRATION-REPORT.md to each user's zoom folder: dates searched, files
+                                   # migrated and known gaps, so users can check their migration themselves
+
+METADATA SIDECAR (Optional):
+============================
+metadata:
+  format: "json"                   # Sidecar saved and uploaded next to each MP4: json, yaml (<name>.yaml) or none
+                                   # for no sidecar at all (default: json)
+  include_participants: false      # Add "host" (ID, email, display name) and "participants" (name, email, join and
+                                   # leave times, seconds attended) to each recording's metadata sidecar (default: false)
+  # Costs two extra Zoom API calls per recording and needs the report:read:admin scope; lookups that
+  # fail are logged and the metadata is saved without them.
+
+RUN SUMMARY CSV (Optional):
+===========================
+tracking:
+  run_summary: false               # Write one row per recording file of the run: user, meeting topic, date, size,
+                                   # download and upload seconds, status and error (default: false)
+  run_summary_file: ""             # Summary CSV path ("" = <output_dir>/run-summary.csv)
+  run_summary_mode: "overwrite"    # "overwrite" starts the file over each run (default); "append" keeps earlier runs
+  # Dry runs do not write the summary.
+
+RUN LIMITS (Optional):
+=====================
+limits:
+  max_run_duration: "6h"           # Stop starting new files after this long (default: no limit)
+  # In-flight transfers finish and progress is saved; the process exits with
+  # status 75 so the next run resumes where this one stopped.
+
+processor:
+  user_timeout: "4h"               # Fail a user that takes longer and continue with the next (default: no limit)
+  # The user's in-flight file fails and its remaining recordings are retried next run.
+  transient_retries: 1             # Retry users that failed on rate limits, 5xx or network errors at the end of the run (default: 0)
+  transient_retry_delay: "1m"      # Wait before each retry pass (default: 1m)
+
+RESOURCE MONITOR (Optional, for long runs):
+==========================================
+monitor:
+  interval: "5m"                   # Log memory, goroutine and open file counts this often (default: disabled)
+  max_heap_mb: 1536                # Heap limit in MB (default: no limit)
+  max_goroutines: 0                # Goroutine limit (default: no limit)
+  max_open_files: 0                # Open file descriptor limit (default: no limit)
+  restart_on_limit: true           # Finish in-flight transfers and exit with status 75 when a limit is exceeded
+  # Steady growth across consecutive samples is logged as a possible leak. Set max_heap_mb below
+  # the container memory limit so the run checkpoints and restarts instead of being OOM-killed.
+
+TIMESTAMPS (Optional):
+=====================
+time:
+  timezone: "UTC"                  # UTC, Local or an IANA name such as America/Toronto (default: UTC)
+  format: "rfc3339"                # rfc3339, rfc3339nano, datetime, datetime-tz or a Go layout (default: rfc3339)
+  # Applies to log lines, tracking CSV upload dates, run reports, progress.json and the
+  # year/month/day download folders, so entries from every output line up.
+
+filename:
+  template: "{{.Topic}}-{{.Time}}" # Go text/template for recording file names, without extension
+  # Fields: .Topic .Date .Time .Year .Month .Day .HostEmail .RecordingType .FileType .MeetingID .FileID
+  # and .Start (a time, e.g. {{.Start.Format "150405"}}). Each field is sanitized separately;
+  # a meeting's files of the same type get their recording type appended (e.g. -gallery_view,
+  # unless the template includes .RecordingType) and a -2, -3, ... sequence when that repeats;
+  # other names that collide in a folder get a -2, -3, ... suffix.
+  windows_safe: false              # Windows-safe local paths: reserved names such as CON or LPT1 get a "_",
+  # file names are shortened to keep paths within preflight.max_path_length (default: 259) and
+  # long paths use the \\?\ prefix. Changes the names of affected files, also in the destination.
+
+directory:
+  layout: "{{.User}}/{{.Year}}/{{.Month}}/{{.Day}}" # Folders below output_dir, e.g. "{{.Year}}/{{.Month}}/{{.User}}" or "{{.User}}" (flat)
+  # Fields: .User .Year .Month .Day .Date; must contain {{.User}}. The same folders are created in the
+  # upload destination below each user's root folder (a leading {{.User}} folder is only used locally).
+
+preflight:
+  min_free_mb: 0                   # Free space required on the output filesystem at startup (0 = not checked)
+  min_free_inodes: 0               # Free inodes required, e.g. 100000 for large migrations on ext4 (0 = not checked)
+  max_path_length: 0               # Path length limit when lower than the OS limit, e.g. 260 (0 = OS limit)
+  # The deepest planned path (output_dir + directory.layout + filename.template with the longest
+  # username and topic) is always checked against the filesystem's path and name length limits.
+
+token_cache:
+  enabled: false                   # Reuse Zoom and Box access tokens between runs
+  file: "token-cache.json"         # Tokens are encrypted with the client secret and redacted from logs
+
+tracing:
+  enabled: false                   # Export OpenTelemetry spans (run, user, recording file, stage, download, HTTP call)
+  endpoint: "http://localhost:4318"  # OTLP/HTTP endpoint ("" = OTEL_EXPORTER_OTLP_ENDPOINT)
+  # headers:                       # Extra headers for the endpoint, e.g. an API key
+  #   x-api-key: "..."
+  service_name: "zoom-to-box"
+  sample_ratio: 1.0                # Fraction of runs (and webhook events) traced
+
+NETWORK (Optional, for corporate proxies):
+network:
+  proxy_url: ""                    # http://, https://, socks5:// or socks5h:// proxy for Zoom, Box and downloads
+                                   # ("" = HTTP_PROXY/HTTPS_PROXY; NO_PROXY hosts always connect directly)
+  ca_cert_file: ""                 # PEM bundle trusted in addition to the system CAs, e.g. the proxy's CA
+  min_tls_version: "1.2"           # Oldest TLS version accepted: 1.0, 1.1, 1.2 or 1.3 (default: 1.2)
+  insecure_skip_verify: false      # DANGEROUS: accept any certificate; only to debug TLS interception
+
+ENCRYPTION AT REST (Optional, for shared staging hosts):
+encryption:
+  enabled: false                   # Encrypt downloaded MP4s on disk with AES-256-GCM
+  key: ""                          # 32-byte key as hex or base64 (or ENCRYPTION_KEY), e.g. openssl rand -hex 32
+  key_file: ""                     # ...or a file holding the key
+  kms_encrypted_key: ""            # ...or an AWS KMS encrypted data key (base64 CiphertextBlob)
+  kms_region: ""                   # Overrides the AWS chain region for KMS
+  temp_dir: ""                     # Decrypted copies are staged here during uploads ("" = OS temp dir)
+
+NOTIFICATIONS (Optional):
+notifications:
+  slack:
+    webhook_url: ""                # Slack incoming webhook (or SLACK_WEBHOOK_URL)
+  teams:
+    webhook_url: ""                # Microsoft Teams incoming webhook (or TEAMS_WEBHOOK_URL)
+  on_failure_only: false           # Only post when a run aborts, stops early or has failed users
+  email:
+    smtp_host: ""                  # SMTP server ("" = no email)
+    smtp_port: 587                 # Default: 587 (STARTTLS when offered)
+    implicit_tls: false            # Use TLS from the first byte, usually with port 465
+    username: ""                   # SMTP login ("" = no authentication)
+    password: ""                   # SMTP password (or SMTP_PASSWORD)
+    from: "zoom-to-box@company.com"
+    to: ["it-ops@company.com"]
+    subject: "zoom-to-box run {{.Status}}{{if .Reason}}: {{.Reason}}{{end}}"
+                                   # Template fields: .Status, .Reason, .Date, .FailedUsers
+    attach: ["json", "csv"]        # Attach the run report as JSON and/or one CSV row per file
+  # A summary with the totals and the failed users and their errors is sent when a run ends.
+
+HOOKS (Optional):
+hooks:
+  post_upload:                     # Commands run after each uploaded file and after each user
+    - "./notify.sh {{.User}} {{.File}}"
+  post_upload_url: ""              # Receives each event as a JSON POST ("" = none)
+  timeout: 30s                     # Limit for each command and post
+  # Commands run without a shell and get the event as JSON on stdin and its type in
+  # ZOOM_TO_BOX_EVENT (upload or user). Write template fields without spaces, e.g. {{.File}};
+  # fields: .Event, .User, .BoxUser, .File, .Path, .Folder, .FileID, .Size, .Topic,
+  # .MeetingUUID, .SharedLink and, for user events, .Downloaded, .Uploaded, .Errors.
+  # A failing hook is logged and never fails the upload.
+
+DAEMON MODE (Optional, for 'zoom-to-box daemon'):
+daemon:
+  schedule: "0 2 * * *"            # Cron expression in time.timezone (or @hourly, @daily, ...)
+  report_dir: ""                   # Per-run JSON reports ("" = <output_dir>/reports)
+
+WEBHOOK MODE (Optional, for 'zoom-to-box serve'):
+================================================
+webhook:
+  listen_address: ":8080"          # Address the webhook listener binds to (default: :8080)
+  path: "/zoom/webhook"            # Event notification endpoint path (default: /zoom/webhook)
+  secret_token: "your_secret_token" # Secret token from the Zoom app's event subscription (required for serve)
+  queue_size: 100                  # Recordings that can wait for processing (default: 100)
+  # Subscribe the Zoom app to the recording.completed event. Hosts must be listed in the
+  # active users file when it exists; otherwise the Zoom email is used as the Box email.
+
+CONTROL API (Optional, for 'zoom-to-box serve'):
+===============================================
+control:
+  enabled: true                    # Serve the control API on the webhook listener (default: false)
+  token: "your_api_token"          # Bearer token required on every request (required when enabled)
+  # Endpoints (all under /api/v1/, "Authorization: Bearer <token>"):
+  #   POST /users {"zoom_email", "box_email"}  enqueue a user
+  #   GET  /users, /users/{email}               per-user progress
+  #   POST /pause, /resume                      pause or resume processing
+  #   GET  /status                              queue and pause state
+  #   GET  /events                              server-sent progress events
+
+PROFILES (Optional, for several Zoom accounts in one file):
+==========================================================
+profiles:
+  prod:                            # Selected with --profile prod
+    zoom:
+      account_id: "prod_account_id"
+      client_id: "prod_client_id"
+      client_secret: "prod_client_secret"
+    box:
+      enterprise_id: "prod_enterprise_id"
+    download:
+      output_dir: "/data/prod"
+  edu:                             # Selected with --profile edu
+    zoom:
+      account_id: "edu_account_id"
+      client_id: "edu_client_id"
+      client_secret: "edu_client_secret"
+    download:
+      output_dir: "/data/edu"
+  # A profile may contain any top-level section. Its keys replace the top-level values;
+  # everything it does not mention is shared. Environment variables still override both.
+
+ENVIRONMENT VARIABLES:
+=====================
+
+Required Zoom API credentials (override config file):
+  ZOOM_ACCOUNT_ID     - Your Zoom account ID
+  ZOOM_CLIENT_ID      - Your Zoom OAuth app client ID
+  ZOOM_CLIENT_SECRET  - Your Zoom OAuth app client secret
+  ZOOM_CLIENT_SECRET_NEXT - Next Zoom client secret during rotation (optional)
+  ZOOM_BASE_URL       - Zoom API base URL (optional)
+  ZOOM_REGION         - Zoom cloud: us, eu or gov (optional)
+  ZOOM_OAUTH_URL      - Zoom OAuth token endpoint (optional)
+  ZOOM_RATE_TIER      - Zoom rate tier: auto, free, pro or business (optional)
+
+Optional Box integration:
+  BOX_CLIENT_ID     - Box OAuth 2.0 client ID
+  BOX_CLIENT_SECRET - Box OAuth 2.0 client secret
+  BOX_CLIENT_SECRET_NEXT - Next Box client secret during rotation
+  BOX_ENTERPRISE_ID - Box enterprise ID for client credentials auth
+  BOX_AUTH_MODE     - Box auth mode (client_credentials or user)
+  BOX_TOKEN_FILE    - Token file written by 'zoom-to-box box login'
+
+Optional Google Drive integration:
+  GOOGLE_DRIVE_CREDENTIALS_FILE - Service account key file with domain-wide delegation
+
+Optional S3 integration:
+  S3_BUCKET - Destination bucket (credentials use the standard AWS_* variables)
+
+Optional SFTP and WebDAV integration:
+  SFTP_PRIVATE_KEY_FILE - Private key for the SFTP login
+  WEBDAV_USERNAME       - WebDAV basic auth user
+  WEBDAV_PASSWORD       - WebDAV basic auth password
+
+Optional webhook mode:
+  ZOOM_WEBHOOK_SECRET_TOKEN - Secret token used to verify Zoom webhook signatures
+  CONTROL_API_TOKEN         - Bearer token for the control API
+
+Other settings:
+  DOWNLOAD_OUTPUT_DIR  - Base download directory
+  DOWNLOAD_DISK_RESERVE_GB - Free space to keep on the output disk, in GB
+  LIMITS_MAX_RUN_DURATION - Maximum run duration, e.g. 6h
+  UPLOAD_METADATA_ORDER - Metadata JSON upload order: after or before
+  UPLOAD_CONFLICT_POLICY - Same-named file with a different size: version, replace or report
+  MONITOR_INTERVAL - Resource usage sampling interval, e.g. 5m
+  TIME_TIMEZONE - Timezone for displayed timestamps and date folders
+  TIME_FORMAT - Timestamp format for logs, CSVs and reports
+  FILENAME_TEMPLATE - Template for recording file names
+  DIRECTORY_LAYOUT - Template for recording folders
+  PREFLIGHT_MIN_FREE_MB - Free space required at startup
+  PREFLIGHT_MIN_FREE_INODES - Free inodes required at startup
+  TOKEN_CACHE_FILE - Encrypted cache of Zoom and Box access tokens
+  TRACING_ENDPOINT - OTLP/HTTP endpoint for OpenTelemetry traces
+  NETWORK_PROXY_URL - Proxy for all HTTP requests (HTTP_PROXY, HTTPS_PROXY and NO_PROXY also apply)
+  NETWORK_CA_CERT_FILE - Extra trusted CA certificates (PEM)
+  NETWORK_INSECURE_SKIP_VERIFY - Skip TLS certificate verification (true/false; dangerous)
+  DAEMON_SCHEDULE - Cron expression for daemon runs
+  ENCRYPTION_KEY - Key for encrypting downloaded recordings at rest
+  SLACK_WEBHOOK_URL - Slack incoming webhook for run summaries
+  TEAMS_WEBHOOK_URL - Microsoft Teams incoming webhook for run summaries
+  SMTP_PASSWORD - Password for emailed run summaries
+
+SECRET REFERENCES:
+=================
+Credential settings (and their environment variables) can name a secret instead of holding it;
+it is fetched when the config loads and never written to disk:
+
+  client_secret: "vault:secret/zoom#client_secret"   # HashiCorp Vault KV v1/v2 (VAULT_ADDR, VAULT_TOKEN, VAULT_NAMESPACE)
+  client_secret: "aws-sm:zoom-creds#client_secret"   # AWS Secrets Manager JSON key (standard AWS credential chain)
+  client_secret: "aws-sm:zoom-client-secret"         # Whole AWS Secrets Manager secret string
+
+Supported settings: zoom account_id, client_id, client_secret(_next); box client_id,
+client_secret(_next), enterprise_id; webdav username/password; webhook.secret_token;
+control.token; encryption key/kms_encrypted_key; notification webhook URLs and SMTP login;
+network.proxy_url.
+
+AUTHENTICATION METHODS:
+======================
+
+1. Server-to-Server OAuth (Recommended):
+   - Account-level access to all users and recordings
+   - No user consent required
+   - Uses JWT-based authentication
+   - Required scopes: recording:read, user:read, meeting:read
+
+2. Environment Variables:
+   - Set ZOOM_ACCOUNT_ID, ZOOM_CLIENT_ID, ZOOM_CLIENT_SECRET
+   - Overrides any values in config.yaml
+   - Useful for CI/CD and containerized deployments
+
+EXAMPLE USAGE:
+=============
+
+1. Using configuration file:
+   cp config.example.yaml config.yaml
+   # Edit config.yaml with your credentials
+   zoom-to-box
+
+2. Using environment variables:
+   export ZOOM_ACCOUNT_ID="your-account-id"
+   export ZOOM_CLIENT_ID="your-client-id"
+   export ZOOM_CLIENT_SECRET="your-client-secret"
+   zoom-to-box
+
+3. With additional options:
+   zoom-to-box --meta-only --verbose
+   zoom-to-box --output-dir ./recordings --dry-run
+   zoom-to-box --from=90d --to=2024-12-31
+
+4. Single user processing:
+   zoom-to-box --zoom-user=john.doe@company.com --box-user=john.doe@company.com
+   zoom-to-box --zoom-user=john.doe@zoomaccount.com --box-user=john.doe@company.com
+   zoom-to-box --zoom-user-id=KDcuGIm1QgePTO8WbOqwIQ --box-user=john.doe@company.com
+
+5. Box integration:
+   # Set Box OAuth 2.0 credentials in config.yaml or environment variables
+   # Enable in config.yaml: box.enabled = true
+   export BOX_CLIENT_ID="your_box_client_id"
+   export BOX_CLIENT_SECRET="your_box_client_secret"
+   zoom-to-box --config config.yaml
+
+   # Individual Box account without an enterprise app (box.auth_mode: user):
+   zoom-to-box box login
+
+6. Webhook mode (process recordings as soon as Zoom finishes them):
+   export ZOOM_WEBHOOK_SECRET_TOKEN="your-secret-token"
+   zoom-to-box serve --listen :8080
+
+   # With control.enabled, an orchestrator can enqueue users:
+   curl -H "Authorization: Bearer $CONTROL_API_TOKEN" \
+     -d '{"zoom_email": "jane@example.com"}' http://localhost:8080/api/v1/users
+
+7. Generate the active users file from Zoom:
+   zoom-to-box users sync --prune
+   zoom-to-box users unmanaged   # Report hosts with recordings missing from the file
+
+8. Write a JSON report for dashboards:
+   zoom-to-box --report-file run-report.json
+
+9. Replay failed operations after fixing the cause:
+   zoom-to-box --run-report run-report.json
+   zoom-to-box replay --run-report run-report.json
+
+10. Retry failed Box uploads recorded in the download status file:
+   zoom-to-box retry-uploads --status-file downloads/status.json --max-retries 5
+
+11. Audit the local downloads and tracked uploads against Box:
+   zoom-to-box verify --checksums
+
+12. Run on a schedule in one long-lived process (SIGHUP reloads the config):
+   zoom-to-box daemon --schedule "0 2 * * *"
+
+13. Download during the day and upload overnight:
+   zoom-to-box --download-only
+   zoom-to-box upload --path ./downloads --delete-after-upload
+
+14. Check the configuration and credentials before the first run:
+   zoom-to-box config validate --live
+
+15. Reclaim disk space from recordings Box has had for a month:
+   zoom-to-box cleanup --older-than 30d --dry-run
+
+16. Keep cron runs with different output directories from overlapping:
+   zoom-to-box --lock-file /var/run/zoom-to-box.lock --output-dir ./team-a
+
+17. Run from CI and parse the results (errors and logs go to stderr):
+   zoom-to-box --output json > result.json
+   zoom-to-box --quiet
+
+DIRECTORY STRUCTURE:
+==================
+Downloaded files are organized as:
+downloads/
+├── user.email/
+│   └── YYYY/
+│       └── MM/
+│           └── DD/
+│               ├── meeting-topic-HHMM.mp4
+│               └── meeting-topic-HHMM.json
+
+Box uploads are organized as:
+<service-account-root>/
+├── username/
+│   └── YYYY/
+│       └── MM/
+│           └── DD/
+│               ├── meeting-topic-HHMM.mp4
+│               └── meeting-topic-HHMM.json
+
+TROUBLESHOOTING:
+===============
+- Ensure your Zoom app has Server-to-Server OAuth enabled
+- Verify required scopes are granted: recording:read, user:read, meeting:read
+- Check account_id matches your Zoom account (not user ID)
+- For Box integration, ensure OAuth 2.0 client credentials are valid
+- Check a configuration before a run: zoom-to-box config validate --live
+- "x509: certificate signed by unknown authority" behind a TLS-inspecting proxy: set network.ca_cert_file
+
+For more information, visit: https://github.com/curtbushko/zoom-to-box
+`
+			cmd.Print(configHelp)
+		},
+	}
+	cmd.AddCommand(createConfigValidateCommand())
+	return cmd
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/curtbushko/zoom-to-box/internal/config"
+	"github.com/curtbushko/zoom-to-box/internal/runlock"
+	"github.com/curtbushko/zoom-to-box/internal/schedule"
+	"github.com/curtbushko/zoom-to-box/internal/timefmt"
+)
+
+// createDaemonCommand creates the scheduled batch run subcommand
+func createDaemonCommand() *cobra.Command {
+	var (
+		scheduleExpr string
+		reportDir    string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "daemon",
+		Short: "Run the batch download and upload on a cron schedule",
+		Long: `Stay running and start the batch pipeline, as running zoom-to-box would, each
+time the cron schedule fires. The schedule has the standard five fields (minute
+hour day-of-month month day-of-week) or a shorthand such as @daily, and is
+evaluated in time.timezone.
+
+Each run writes its JSON report to <report-dir>/run-<timestamp>.json. Runs take
+a lock file in the output directory, as one-off runs of zoom-to-box do, so a
+scheduled run is skipped while another run is still working on it. A run that
+is still going when the schedule fires delays the next run instead of overlapping it.
+
+Send SIGHUP to reload the configuration file; the new settings, including
+daemon.schedule, apply from the next run. SIGINT or SIGTERM stops the current
+run with its progress saved and exits.`,
+		Example: `  zoom-to-box daemon --schedule "0 2 * * *"
+  zoom-to-box daemon --schedule @hourly --report-dir /var/log/zoom-to-box
+  kill -HUP <pid>   # reload config.yaml`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			configPath := "config.yaml"
+			if configFile != "" {
+				configPath = configFile
+			}
+
+			cfg, err := loadConfig(configPath)
+			if err != nil {
+				return fmt.Errorf("failed to load configuration: %w", err)
+			}
+
+			return runDaemon(cmd, configPath, cfg, scheduleExpr, reportDir)
+		},
+	}
+
+	cmd.Flags().StringVar(&scheduleExpr, "schedule", "", "cron expression for the runs, e.g. \"0 2 * * *\" (overrides daemon.schedule)")
+	cmd.Flags().StringVar(&reportDir, "report-dir", "", "directory for the per-run JSON reports (overrides daemon.report_dir)")
+
+	return cmd
+}
+
+// runDaemon starts a batch run each time the schedule fires until interrupted
+func runDaemon(cmd *cobra.Command, configPath string, cfg *config.Config, scheduleFlag, reportDirFlag string) error {
+	sched, err := daemonSchedule(cfg, scheduleFlag)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := shutdownContext()
+	defer stop()
+
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+	defer signal.Stop(reload)
+
+	for {
+		next := sched.Next(timefmt.Now())
+		if next.IsZero() {
+			return fmt.Errorf("schedule %q does not fire in the next five years", sched)
+		}
+		cmd.Printf("Next run at %s (schedule %q)\n", timefmt.Format(next), sched)
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			cmd.Printf("Daemon stopped\n")
+			return nil
+		case <-reload:
+			timer.Stop()
+			cfg, sched = reloadDaemonConfig(cmd, configPath, cfg, sched, scheduleFlag)
+			continue
+		case <-timer.C:
+		}
+
+		runScheduled(ctx, cmd, cfg, reportDirFlag)
+		if ctx.Err() != nil {
+			cmd.Printf("Daemon stopped; progress is saved and the next start resumes it\n")
+			return nil
+		}
+	}
+}
+
+// daemonSchedule parses --schedule, or daemon.schedule when the flag is not given
+func daemonSchedule(cfg *config.Config, scheduleFlag string) (*schedule.Schedule, error) {
+	expr := cfg.Daemon.Schedule
+	if scheduleFlag != "" {
+		expr = scheduleFlag
+	}
+	if expr == "" {
+		return nil, fmt.Errorf("a schedule is required: use --schedule or daemon.schedule")
+	}
+
+	sched, err := schedule.Parse(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schedule: %w", err)
+	}
+	return sched, nil
+}
+
+// reloadDaemonConfig re-reads the configuration file after SIGHUP
+// The current configuration and schedule are kept when the file no longer loads.
+func reloadDaemonConfig(cmd *cobra.Command, configPath string, cfg *config.Config, sched *schedule.Schedule, scheduleFlag string) (*config.Config, *schedule.Schedule) {
+	newCfg, err := loadConfig(configPath)
+	if err != nil {
+		cmd.Printf("Reload failed, keeping the current configuration: %v\n", err)
+		return cfg, sched
+	}
+	newSched, err := daemonSchedule(newCfg, scheduleFlag)
+	if err != nil {
+		cmd.Printf("Reload failed, keeping the current configuration: %v\n", err)
+		return cfg, sched
+	}
+
+	cmd.Printf("Configuration reloaded from %s\n", configPath)
+	return newCfg, newSched
+}
+
+// runScheduled performs one batch run with its report written to the report directory
+// Failures are printed rather than returned so the daemon keeps to its schedule.
+func runScheduled(ctx context.Context, cmd *cobra.Command, cfg *config.Config, reportDirFlag string) {
+	startedAt := timefmt.Now()
+
+	dir := reportDirFlag
+	if dir == "" {
+		dir = cfg.Daemon.ReportDir
+	}
+	if dir == "" {
+		base := cfg.Download.OutputDir
+		if outputDir != "" {
+			base = outputDir
+		}
+		dir = filepath.Join(base, "reports")
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		cmd.Printf("Skipping scheduled run: failed to create report directory: %v\n", err)
+		return
+	}
+	reportFile = filepath.Join(dir, "run-"+startedAt.Format("20060102-150405")+".json")
+
+	cmd.Printf("Starting scheduled run at %s\n", timefmt.Format(startedAt))
+	err := runDownloadWithProgress(ctx, cmd, cfg)
+	switch {
+	case err == nil:
+		cmd.Printf("Scheduled run finished in %v\n", time.Since(startedAt).Round(time.Second))
+	case errors.Is(err, runlock.ErrLocked):
+		cmd.Printf("Skipping scheduled run: %v\n", err)
+	case errors.Is(err, errRunInterrupted):
+	case errors.Is(err, errRunTimeBoxed) || errors.Is(err, errRunStoppedByMonitor) || errors.Is(err, errRunDiskFull):
+		cmd.Printf("Scheduled run stopped early: %v; the next run resumes it\n", err)
+	default:
+		cmd.Printf("Scheduled run failed: %v\n", err)
+	}
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/curtbushko/zoom-to-box/internal/directory"
+	"github.com/curtbushko/zoom-to-box/internal/filename"
+	"github.com/curtbushko/zoom-to-box/internal/processor"
+	"github.com/curtbushko/zoom-to-box/internal/users"
+	"github.com/curtbushko/zoom-to-box/internal/verify"
+	"github.com/curtbushko/zoom-to-box/internal/zoom"
+)
+
+// createDiffCommand creates the subcommand that compares the Zoom recordings with Box
+func createDiffCommand() *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Compare each user's Zoom recordings with their Box zoom folder",
+		Long: `List every user's Zoom MP4 recordings and the recordings in their Box zoom
+folder and write a reconciliation report
+(kind,zoom_email,box_email,box_path,recording_uuid,file_id,topic,zoom_size_bytes,box_size_bytes,detail).
+
+Difference kinds:
+  missing_in_box   Zoom recording with no file at its expected Box path
+  missing_in_zoom  Box recording that matches no Zoom recording in the date range
+  size_mismatch    Box has the file with a different size than Zoom reports
+  lookup_failed    Zoom or Box could not be listed for the user
+
+Zoom recordings are expected where a run would upload them: the directory.layout
+folder of the recording date and the filename.template name. The recording
+filters and --from/--to apply; Box folders of other dates, previews and
+non-MP4 files are ignored. Nothing is downloaded, uploaded or changed, so the
+report can be run before and after a migration. Users come from
+--zoom-user/--box-user or the active users file. The command fails when
+differences are found.`,
+		Example: `  zoom-to-box diff
+  zoom-to-box diff --from 2024-01-01 --to 2024-12-31 --output /reports/diff.csv
+  zoom-to-box diff --zoom-user jane@company.com --box-user jane@company.com`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			configPath := "config.yaml"
+			if configFile != "" {
+				configPath = configFile
+			}
+
+			cfg, err := loadConfig(configPath)
+			if err != nil {
+				return fmt.Errorf("failed to load configuration: %w", err)
+			}
+			if !cfg.Box.Enabled {
+				return fmt.Errorf("box.enabled must be true to compare recordings with Box")
+			}
+			if outputDir != "" {
+				cfg.Download.OutputDir = outputDir
+			}
+			if output == "" {
+				output = filepath.Join(cfg.Download.OutputDir, "diff-report.csv")
+			}
+
+			if fromDate != "" {
+				cfg.Download.FromDate = fromDate
+			}
+			if toDate != "" {
+				cfg.Download.ToDate = toDate
+			}
+			from, to, err := cfg.Download.DateRange(time.Now())
+			if err != nil {
+				return fmt.Errorf("invalid date range: %w", err)
+			}
+			if from == nil {
+				from = getFromDate()
+			}
+			if to == nil {
+				to = getToDate()
+			}
+
+			var diffUsers []verify.User
+			switch {
+			case zoomUser != "" && boxUser != "":
+				diffUsers = []verify.User{{ZoomEmail: zoomUser, BoxEmail: boxUser}}
+			case zoomUser != "" || boxUser != "":
+				return fmt.Errorf("--zoom-user and --box-user must be used together")
+			default:
+				usersPath := cfg.ActiveUsers.File
+				if activeUsersFile != "" {
+					usersPath = activeUsersFile
+				}
+				if usersPath == "" {
+					return fmt.Errorf("no users to compare; use --zoom-user/--box-user, --active-users-file or active_users.file")
+				}
+				usersFile, err := users.LoadActiveUsersFile(usersPath)
+				if err != nil {
+					return err
+				}
+				for _, entry := range usersFile.Entries {
+					diffUsers = append(diffUsers, verify.User{ZoomEmail: entry.ZoomEmail, BoxEmail: entry.BoxEmail})
+				}
+			}
+
+			layout, err := directory.NewLayout(cfg.Directory.Layout)
+			if err != nil {
+				return err
+			}
+			sanitizer := filename.NewFileSanitizer(filename.FileSanitizerOptions{WindowsSafe: cfg.Filename.WindowsSafe})
+			template, err := filename.NewTemplate(cfg.Filename.Template, sanitizer)
+			if err != nil {
+				return fmt.Errorf("filename.template: %w", err)
+			}
+			recordingFilter, err := processor.NewRecordingFilter(cfg.Filters.MinDurationMinutes, cfg.Filters.TopicRegex, cfg.Filters.ExcludeTopicRegex, cfg.Filters.MeetingTypes)
+			if err != nil {
+				return fmt.Errorf("invalid recording filters: %w", err)
+			}
+			boxClient, err := buildBoxClient(cfg)
+			if err != nil {
+				return err
+			}
+
+			ctx, stop := shutdownContext()
+			defer stop()
+
+			report, err := verify.Diff(ctx, buildZoomClient(cfg), boxClient, verify.DiffOptions{
+				Layout:    layout,
+				Template:  template,
+				Sanitizer: sanitizer,
+				Users:     diffUsers,
+				From:      from,
+				To:        to,
+				PageSize:  cfg.Zoom.PageSize,
+				Skip:      func(recording *zoom.Recording) bool { return recordingFilter.SkipReason(recording) != "" },
+			})
+			if err != nil {
+				return fmt.Errorf("diff failed: %w", err)
+			}
+
+			if err := os.MkdirAll(filepath.Dir(output), 0755); err != nil {
+				return fmt.Errorf("failed to create report directory: %w", err)
+			}
+			file, err := os.Create(output)
+			if err != nil {
+				return fmt.Errorf("failed to create diff report: %w", err)
+			}
+			if err := verify.WriteDiffCSV(file, report.Entries); err != nil {
+				file.Close()
+				return err
+			}
+			if err := file.Close(); err != nil {
+				return fmt.Errorf("failed to write diff report: %w", err)
+			}
+
+			counts := make(map[verify.Kind]int)
+			for _, entry := range report.Entries {
+				counts[entry.Kind]++
+				cmd.Printf("! %s: %s %s\n", entry.Kind, entry.ZoomEmail, entry.BoxPath)
+			}
+			cmd.Printf("Compared %d Zoom files with %d Box files for %d users: %d matched, %d missing in Box, %d missing in Zoom, %d size mismatches, %d lookup failures\n",
+				report.ZoomFiles, report.BoxFiles, len(diffUsers), report.Matched, counts[verify.KindMissingInBox], counts[verify.KindMissingInZoom],
+				counts[verify.KindSizeMismatch], counts[verify.KindLookupFailed])
+			cmd.Printf("Report written to %s\n", output)
+
+			if len(report.Entries) > 0 {
+				return fmt.Errorf("%d differences found, see %s", len(report.Entries), output)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&output, "output", "", "CSV report to write (default: <output_dir>/diff-report.csv)")
+
+	return cmd
+}
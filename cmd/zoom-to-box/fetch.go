@@ -0,0 +1,135 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/curtbushko/zoom-to-box/internal/config"
+	"github.com/curtbushko/zoom-to-box/internal/logging"
+	"github.com/curtbushko/zoom-to-box/internal/progress"
+	"github.com/curtbushko/zoom-to-box/internal/zoom"
+)
+
+// createFetchCommand creates the subcommand that streams a single recording file
+func createFetchCommand() *cobra.Command {
+	var (
+		meetingUUID string
+		fileID      string
+		toStdout    bool
+		output      string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "fetch --meeting <uuid> --file <id> (--stdout | --output <path>)",
+		Short: "Stream a single recording file to stdout or a file",
+		Long: `Download one recording file using the configured Zoom credentials, without
+the download directory, status tracking or uploads.
+
+With --stdout the file is streamed to standard output without touching the disk,
+so it can be piped into other tools; logs go to standard error. Run without
+--file to list the files of the meeting recording.`,
+		Example: `  zoom-to-box fetch --meeting "4444AAAiAAAAAiAiAiiAii==" --file 6a5d0a5c-... --stdout | ffprobe -
+  zoom-to-box fetch --meeting "4444AAAiAAAAAiAiAiiAii==" --file 6a5d0a5c-... --stdout | aws s3 cp - s3://bucket/meeting.mp4
+  zoom-to-box fetch --meeting "4444AAAiAAAAAiAiAiiAii==" --file 6a5d0a5c-... --output meeting.mp4`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if meetingUUID == "" {
+				return fmt.Errorf("--meeting is required")
+			}
+			if fileID != "" && toStdout == (output != "") {
+				return fmt.Errorf("exactly one of --stdout or --output is required")
+			}
+
+			configPath := "config.yaml"
+			if configFile != "" {
+				configPath = configFile
+			}
+
+			cfg, err := loadConfig(configPath)
+			if err != nil {
+				return fmt.Errorf("failed to load configuration: %w", err)
+			}
+
+			return runFetch(cmd, cfg, meetingUUID, fileID, output)
+		},
+	}
+
+	cmd.Flags().StringVar(&meetingUUID, "meeting", "", "meeting UUID of the recording")
+	cmd.Flags().StringVar(&fileID, "file", "", "recording file ID to fetch (omit to list the files)")
+	cmd.Flags().BoolVar(&toStdout, "stdout", false, "stream the file to standard output")
+	cmd.Flags().StringVar(&output, "output", "", "write the file to this path instead of standard output")
+
+	return cmd
+}
+
+// runFetch streams a single recording file to output, or to stdout when output is ""
+// Without a fileID the meeting's recording files are listed on stderr.
+func runFetch(cmd *cobra.Command, cfg *config.Config, meetingUUID, fileID, output string) error {
+	// Keep stdout for the file content
+	logging.SetConsoleOutput(os.Stderr)
+	defer logging.SetConsoleOutput(os.Stdout)
+	if err := logging.InitializeLogging(cfg.Logging); err != nil {
+		return fmt.Errorf("failed to initialize logging: %w", err)
+	}
+	defer func() {
+		if logger := logging.GetDefaultLogger(); logger != nil {
+			logger.Close()
+		}
+	}()
+
+	ctx, stop := shutdownContext()
+	defer stop()
+
+	zoomClient := buildZoomClient(cfg)
+	recording, err := zoomClient.GetMeetingRecordings(ctx, meetingUUID)
+	if err != nil {
+		return fmt.Errorf("failed to get recordings for meeting %s: %w", meetingUUID, err)
+	}
+
+	stderr := cmd.ErrOrStderr()
+	if fileID == "" {
+		fmt.Fprintf(stderr, "Recording files for %q (%s):\n", recording.Topic, meetingUUID)
+		for _, file := range recording.RecordingFiles {
+			fmt.Fprintf(stderr, "  %s  %-4s  %-10s  %s\n", file.ID, file.FileType, progress.FormatBytes(file.FileSize), file.RecordingType)
+		}
+		return nil
+	}
+
+	var recordingFile *zoom.RecordingFile
+	for i := range recording.RecordingFiles {
+		if recording.RecordingFiles[i].ID == fileID {
+			recordingFile = &recording.RecordingFiles[i]
+			break
+		}
+	}
+	if recordingFile == nil {
+		return fmt.Errorf("recording file %s not found in meeting %s", fileID, meetingUUID)
+	}
+	if recordingFile.DownloadURL == "" {
+		return fmt.Errorf("recording file %s has no download URL", fileID)
+	}
+
+	logging.Info("Fetching %s (%s, %s) from meeting %s", recordingFile.ID, recordingFile.FileType, progress.FormatBytes(recordingFile.FileSize), meetingUUID)
+	if output == "" {
+		if err := zoomClient.DownloadRecordingFile(ctx, recordingFile.DownloadURL, cmd.OutOrStdout()); err != nil {
+			return fmt.Errorf("failed to fetch recording file %s: %w", fileID, err)
+		}
+		return nil
+	}
+
+	file, err := os.Create(output)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", output, err)
+	}
+	err = zoomClient.DownloadRecordingFile(ctx, recordingFile.DownloadURL, file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(output)
+		return fmt.Errorf("failed to fetch recording file %s: %w", fileID, err)
+	}
+	fmt.Fprintf(stderr, "Wrote %s to %s\n", progress.FormatBytes(recordingFile.FileSize), output)
+	return nil
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/curtbushko/zoom-to-box/internal/box"
+)
+
+// createBoxCommand creates the subcommand for Box account setup
+func createBoxCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "box",
+		Short: "Manage the Box connection",
+	}
+
+	cmd.AddCommand(createBoxLoginCommand())
+
+	return cmd
+}
+
+// createBoxLoginCommand creates the subcommand that authorizes an individual Box account
+func createBoxLoginCommand() *cobra.Command {
+	var noBrowser bool
+
+	cmd := &cobra.Command{
+		Use:   "login",
+		Short: "Authorize an individual Box account with the OAuth 2.0 authorization-code flow",
+		Long: `Sign in to Box as a normal user, for teams without a Box enterprise app.
+
+A browser is opened on the Box consent page and the redirect is captured on
+box.redirect_url, which must be an http://localhost address registered as a
+redirect URI on the Box app. The resulting refresh token is saved to
+box.token_file (mode 0600). Set box.auth_mode to "user" to upload with it;
+access tokens are refreshed automatically and the rotated refresh token is
+saved after every refresh.`,
+		Example: `  zoom-to-box box login
+  zoom-to-box box login --no-browser`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			configPath := "config.yaml"
+			if configFile != "" {
+				configPath = configFile
+			}
+
+			cfg, err := loadConfig(configPath)
+			if err != nil {
+				return fmt.Errorf("failed to load configuration: %w", err)
+			}
+			if cfg.Box.ClientID == "" || cfg.Box.ClientSecret == "" {
+				return fmt.Errorf("box.client_id and box.client_secret are required for box login")
+			}
+
+			flow := box.NewAuthCodeFlow(cfg.Box.ClientID, cfg.Box.ClientSecret, cfg.Box.RedirectURL, nil)
+			token, err := flow.Login(cmd.Context(), func(authURL string) error {
+				cmd.Printf("Open this URL to authorize zoom-to-box:\n\n  %s\n\nWaiting for Box to redirect to %s ...\n", authURL, cfg.Box.RedirectURL)
+				if noBrowser {
+					return nil
+				}
+				if err := box.OpenBrowser(authURL); err != nil {
+					cmd.Printf("Could not open a browser (%v); open the URL manually\n", err)
+				}
+				return nil
+			})
+			if err != nil {
+				return fmt.Errorf("box login failed: %w", err)
+			}
+
+			if err := box.SaveUserToken(cfg.Box.TokenFile, token); err != nil {
+				return err
+			}
+			cmd.Printf("Box login complete; tokens saved to %s\n", cfg.Box.TokenFile)
+			if cfg.Box.AuthMode != "user" {
+				cmd.Printf("Set box.auth_mode to \"user\" to upload with this account\n")
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&noBrowser, "no-browser", false, "print the authorization URL instead of opening a browser")
+
+	return cmd
+}
@@ -3,25 +3,48 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"regexp"
 	"strings"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/curtbushko/zoom-to-box/internal/atrest"
 	"github.com/curtbushko/zoom-to-box/internal/box"
+	"github.com/curtbushko/zoom-to-box/internal/chaos"
 	"github.com/curtbushko/zoom-to-box/internal/config"
 	"github.com/curtbushko/zoom-to-box/internal/directory"
 	"github.com/curtbushko/zoom-to-box/internal/download"
+	"github.com/curtbushko/zoom-to-box/internal/email"
+	"github.com/curtbushko/zoom-to-box/internal/errclass"
 	"github.com/curtbushko/zoom-to-box/internal/filename"
+	"github.com/curtbushko/zoom-to-box/internal/hooks"
 	"github.com/curtbushko/zoom-to-box/internal/logging"
+	"github.com/curtbushko/zoom-to-box/internal/network"
+	"github.com/curtbushko/zoom-to-box/internal/notify"
+	"github.com/curtbushko/zoom-to-box/internal/preflight"
 	"github.com/curtbushko/zoom-to-box/internal/processor"
+	"github.com/curtbushko/zoom-to-box/internal/progress"
+	"github.com/curtbushko/zoom-to-box/internal/runlock"
+	"github.com/curtbushko/zoom-to-box/internal/runreport"
+	"github.com/curtbushko/zoom-to-box/internal/selfupdate"
+	"github.com/curtbushko/zoom-to-box/internal/storage"
+	"github.com/curtbushko/zoom-to-box/internal/timefmt"
+	"github.com/curtbushko/zoom-to-box/internal/tokencache"
+	"github.com/curtbushko/zoom-to-box/internal/tracing"
 	"github.com/curtbushko/zoom-to-box/internal/tracking"
 	"github.com/curtbushko/zoom-to-box/internal/users"
+	"github.com/curtbushko/zoom-to-box/internal/watchdog"
 	"github.com/curtbushko/zoom-to-box/internal/zoom"
 )
 
@@ -30,6 +53,10 @@ var (
 	version   = "dev"
 	commit    = "unknown"
 	buildDate = "unknown"
+
+	// releasePublicKey is the base64 Ed25519 key that signs release checksums, set with
+	// -ldflags "-X main.releasePublicKey=..."; self-update refuses to install without it
+	releasePublicKey = ""
 	
 	// Global flags
 	configFile        string
@@ -38,13 +65,78 @@ var (
 	dryRun            bool
 	metaOnly          bool
 	zoomUser          string
+	zoomUserID        string
 	boxUser           string
 	deleteAfterUpload bool
 	continueOnError   bool
 	activeUsersFile   string
 	limit             int
+	fromDate          string
+	toDate            string
+	runReportFile     string
+	maxRunDuration    time.Duration
+	reportFile        string
+	previewMinutes    int
+	noProgress        bool
+	profile           string
+	downloadOnly      bool
+	stream            bool
+	runLockFile       string
+	chaosRate         float64
+	quiet             bool
+	outputFormat      string
+)
+
+// exitCodeTimeBoxed is returned when a run stops at limits.max_run_duration, a monitor limit
+// or download.disk_reserve_gb; re-running resumes it (EX_TEMPFAIL, so schedulers can tell it apart from a failure)
+const exitCodeTimeBoxed = 75
+
+// errRunTimeBoxed reports that the run stopped at limits.max_run_duration with work left
+var errRunTimeBoxed = errors.New("run stopped at limits.max_run_duration")
+
+// exitCodeInterrupted is returned when SIGINT or SIGTERM stops a run (128 + SIGINT, as shells report it)
+const exitCodeInterrupted = 130
+
+// errRunInterrupted reports that a signal stopped the run; re-running resumes it
+var errRunInterrupted = errors.New("run interrupted")
+
+// errRunDiskFull reports that the run stopped because the output disk fell below download.disk_reserve_gb
+var errRunDiskFull = errors.New("run stopped: output disk is below download.disk_reserve_gb")
+
+// errRunStoppedByMonitor reports that a monitor limit stopped the run with monitor.restart_on_limit
+var errRunStoppedByMonitor = errors.New("run stopped at a monitor limit")
+
+// Exit codes for failed runs, by the class of the error that failed them, so wrapper scripts can
+// branch on the failure type; unclassified failures exit with 1
+const (
+	exitCodeAuthError      = 3 // Zoom or Box credentials were rejected
+	exitCodeRateLimitError = 4 // Zoom or Box kept throttling requests
+	exitCodeNotFoundError  = 5 // A user, folder or recording does not exist
+	exitCodeQuotaError     = 6 // Box storage quota or the local disk is full
+	exitCodeNetworkError   = 7 // Zoom or Box could not be reached
+	exitCodePasscodeError  = 8 // A recording download was refused for its passcode
 )
 
+// exitCodeFor returns the exit code for a run that failed with err
+func exitCodeFor(err error) int {
+	switch errclass.Of(err) {
+	case errclass.Auth:
+		return exitCodeAuthError
+	case errclass.RateLimit:
+		return exitCodeRateLimitError
+	case errclass.NotFound:
+		return exitCodeNotFoundError
+	case errclass.Quota:
+		return exitCodeQuotaError
+	case errclass.Network:
+		return exitCodeNetworkError
+	case errclass.Passcode:
+		return exitCodePasscodeError
+	default:
+		return 1
+	}
+}
+
 // SingleUserConfig holds configuration for single user mode
 type SingleUserConfig struct {
 	Enabled   bool
@@ -57,6 +149,32 @@ type DownloadStats struct {
 	SuccessCount int
 	ErrorCount   int
 	SkippedCount int
+	TimeBoxed    bool // Stopped at limits.max_run_duration with work left
+
+	BytesPlanned int64 // Bytes a dry run would download and upload
+
+	StopReason string // Monitor limit that stopped the run early ("" = not stopped by the monitor)
+
+	UploadCount int  // Files uploaded to the destination
+	Interrupted bool // Stopped by SIGINT or SIGTERM
+	DiskFull    bool // Stopped because the output disk fell below download.disk_reserve_gb
+
+	Report *processor.RunReport // Per-user results for notifications (nil = no users were processed)
+}
+
+// Formats of the root command's --output flag
+const (
+	outputText = "text" // Progress and summaries for humans
+	outputJSON = "json" // One JSON document with the run's outcome and report on stdout
+)
+
+// runOutput is the document --output json prints on stdout when a run ends
+type runOutput struct {
+	Status   string `json:"status"` // completed, failed, interrupted or stopped (time-boxed, resumable)
+	ExitCode int    `json:"exit_code"`
+	Error    string `json:"error,omitempty"`
+
+	*processor.RunReport // Per-user and per-file results (absent when no users were processed)
 }
 
 // buildRootCommand creates and configures the root command
@@ -73,7 +191,11 @@ This tool helps you:
 - Resume interrupted downloads automatically  
 - Save metadata in JSON format alongside recordings
 - Upload recordings to Box (optional)
-- Manage downloads with configurable retry logic`,
+- Manage downloads with configurable retry logic
+
+Exit codes: 1 failure, 3 authentication, 4 rate limit, 5 not found,
+6 quota or disk full, 7 network, 8 passcode protected recording,
+75 time-boxed (resumable), 130 interrupted`,
 		Run: func(cmd *cobra.Command, args []string) {
 			// Check if configuration exists and provide helpful guidance
 			configPath := "config.yaml"
@@ -82,7 +204,7 @@ This tool helps you:
 			}
 
 			// Try to load configuration to provide helpful feedback
-			cfg, err := config.LoadConfig(configPath)
+			cfg, err := loadConfig(configPath)
 			if err != nil {
 				cmd.Printf("Configuration Issue Detected\n\n")
 				
@@ -119,40 +241,85 @@ This tool helps you:
 
 				cmd.Printf("For detailed help: zoom-to-box config\n")
 				cmd.Printf("For general usage: zoom-to-box --help\n")
+				if outputFormat == outputJSON {
+					printRunOutput(os.Stdout, nil, err)
+					os.Exit(runExitCode(err))
+				}
 				return
 			}
 
 			// Configuration loaded successfully - now run the download operation
-			ctx := context.Background()
+			ctx, stop := shutdownContext()
+			defer stop()
 			if err := runDownloadWithProgress(ctx, cmd, cfg); err != nil {
-				cmd.Printf("Download failed: %v\n", err)
-				os.Exit(1)
+				if errors.Is(err, errRunInterrupted) {
+					cmd.PrintErrf("\nINTERRUPTED: progress is saved, run the same command again to resume\n")
+					os.Exit(exitCodeInterrupted)
+				}
+				if errors.Is(err, errRunTimeBoxed) || errors.Is(err, errRunStoppedByMonitor) {
+					cmd.PrintErrf("\nTIME-BOXED: %v; progress is saved, run again to resume\n", err)
+					os.Exit(exitCodeTimeBoxed)
+				}
+				if errors.Is(err, errRunDiskFull) {
+					cmd.PrintErrf("\nDISK FULL: %v; progress is saved, free up space and run again to resume\n", err)
+					os.Exit(exitCodeTimeBoxed)
+				}
+				cmd.PrintErrf("Download failed: %v\n", err)
+				os.Exit(exitCodeFor(err))
 			}
 		},
 	}
 
 	// Add subcommands
 	rootCmd.AddCommand(createVersionCommand())
+	rootCmd.AddCommand(createSelfUpdateCommand())
 	rootCmd.AddCommand(createConfigCommand())
+	rootCmd.AddCommand(createServeCommand())
+	rootCmd.AddCommand(createDaemonCommand())
+	rootCmd.AddCommand(createReplayCommand())
+	rootCmd.AddCommand(createFetchCommand())
+	rootCmd.AddCommand(createRetryUploadsCommand())
+	rootCmd.AddCommand(createCleanupCommand())
+	rootCmd.AddCommand(createUploadCommand())
+	rootCmd.AddCommand(createVerifyCommand())
+	rootCmd.AddCommand(createDiffCommand())
+	rootCmd.AddCommand(createUsersCommand())
+	rootCmd.AddCommand(createBoxCommand())
 
 	// Global flags
 	rootCmd.PersistentFlags().StringVar(&configFile, "config", "", "configuration file path (default: config.yaml)")
+	rootCmd.PersistentFlags().StringVar(&profile, "profile", "", "named profile from the config file's profiles section to apply (e.g. prod)")
 	rootCmd.PersistentFlags().StringVar(&outputDir, "output-dir", "", "base download directory (overrides config)")
 	rootCmd.PersistentFlags().BoolVar(&verbose, "verbose", false, "verbose logging")
-	rootCmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "show what would be downloaded without downloading")
+	rootCmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "show what would be downloaded and uploaded, with Box folders and sizes, without changing anything")
 	rootCmd.PersistentFlags().BoolVar(&metaOnly, "meta-only", false, "download only JSON metadata files")
 	rootCmd.PersistentFlags().StringVar(&zoomUser, "zoom-user", "", "process recordings for specific Zoom user email")
+	rootCmd.PersistentFlags().StringVar(&zoomUserID, "zoom-user-id", "", "process recordings for a specific Zoom user ID, for users whose email lookups fail (instead of --zoom-user)")
 	rootCmd.PersistentFlags().StringVar(&boxUser, "box-user", "", "corresponding Box user email for uploads (requires --zoom-user)")
 	rootCmd.PersistentFlags().BoolVar(&deleteAfterUpload, "delete-after-upload", false, "delete local MP4 files after successful Box upload")
 	rootCmd.PersistentFlags().BoolVar(&continueOnError, "continue-on-error", true, "continue processing next user even if current user fails")
 	rootCmd.PersistentFlags().StringVar(&activeUsersFile, "active-users-file", "", "path to active users file with upload tracking (overrides config)")
 	rootCmd.PersistentFlags().IntVar(&limit, "limit", 0, "limit number of recordings to process per user (0 = no limit)")
+	rootCmd.PersistentFlags().StringVar(&fromDate, "from", "", "only process recordings on or after this date (YYYY-MM-DD or relative, e.g. 90d)")
+	rootCmd.PersistentFlags().StringVar(&toDate, "to", "", "only process recordings on or before this date (YYYY-MM-DD or relative, e.g. 7d)")
+	rootCmd.PersistentFlags().DurationVar(&maxRunDuration, "max-run-duration", 0, "stop starting new files after this long, e.g. 6h (overrides limits.max_run_duration)")
+	rootCmd.PersistentFlags().StringVar(&reportFile, "report-file", "", "write a JSON report with per-user and per-file results to this file after the run")
+	rootCmd.PersistentFlags().BoolVar(&noProgress, "no-progress", false, "disable the interactive progress bars (they are also disabled when stdout is not a terminal)")
+	rootCmd.PersistentFlags().IntVar(&previewMinutes, "preview-minutes", 0, "download only about the first N minutes of each MP4 as <name>-preview.mp4 (overrides download.preview_minutes)")
+	rootCmd.PersistentFlags().StringVar(&runReportFile, "run-report", "", "write failed downloads and uploads to this JSON file so they can be replayed")
+	rootCmd.PersistentFlags().BoolVar(&downloadOnly, "download-only", false, "only download; skip Box and every other upload destination for this run even if enabled")
+	rootCmd.PersistentFlags().StringVar(&runLockFile, "lock-file", "", "refuse to start while another run holding this lock file is in progress, e.g. to keep cron runs with different output directories from overlapping")
+	rootCmd.Flags().BoolVar(&quiet, "quiet", false, "print only errors: no progress bars, informational logs or summaries")
+	rootCmd.Flags().StringVar(&outputFormat, "output", outputText, "output format: text, or json to print the run's outcome and per-user and per-file results as one JSON document on stdout")
+	rootCmd.PersistentFlags().BoolVar(&stream, "stream", false, "experimental: pipe MP4s from Zoom straight into Box upload sessions without local copies (overrides download.stream)")
+	// Developer flag for resilience testing, hidden from --help
+	rootCmd.PersistentFlags().Float64Var(&chaosRate, "chaos", 0, "fail this fraction (0-1) of Zoom and Box requests with random 429s, 500s and timeouts")
+	rootCmd.PersistentFlags().MarkHidden("chaos")
 
 	// Add flag validation
 	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
-		// Validate single user flags
-		if (zoomUser != "" && boxUser == "") || (zoomUser == "" && boxUser != "") {
-			return fmt.Errorf("both --zoom-user and --box-user must be provided together")
+		if outputFormat != outputText && outputFormat != outputJSON {
+			return fmt.Errorf("invalid --output format %q: must be text or json", outputFormat)
 		}
 
 		// Validate email format for zoom-user
@@ -160,11 +327,40 @@ This tool helps you:
 			return fmt.Errorf("invalid email format for --zoom-user: %s", zoomUser)
 		}
 
+		// A Zoom user ID stands in for the Zoom email; the Zoom API accepts either
+		if zoomUserID != "" {
+			if zoomUser != "" {
+				return fmt.Errorf("--zoom-user and --zoom-user-id cannot be used together")
+			}
+			if !users.IsZoomUserID(zoomUserID) {
+				return fmt.Errorf("invalid Zoom user ID for --zoom-user-id: %s", zoomUserID)
+			}
+			zoomUser = zoomUserID
+		}
+
+		// Validate single user flags
+		if (zoomUser != "" && boxUser == "") || (zoomUser == "" && boxUser != "") {
+			return fmt.Errorf("both --zoom-user (or --zoom-user-id) and --box-user must be provided together")
+		}
+
 		// Validate email format for box-user
 		if boxUser != "" && !isValidEmail(boxUser) {
 			return fmt.Errorf("invalid email format for --box-user: %s", boxUser)
 		}
 
+		if downloadOnly && deleteAfterUpload {
+			return fmt.Errorf("--download-only cannot be combined with --delete-after-upload")
+		}
+		if downloadOnly && stream {
+			return fmt.Errorf("--download-only cannot be combined with --stream")
+		}
+
+		// Validate date range flags
+		dateRange := config.DownloadConfig{FromDate: fromDate, ToDate: toDate}
+		if _, _, err := dateRange.DateRange(time.Now()); err != nil {
+			return fmt.Errorf("invalid date range: %w", err)
+		}
+
 		return nil
 	}
 
@@ -173,175 +369,142 @@ This tool helps you:
 
 // createVersionCommand creates the version subcommand
 func createVersionCommand() *cobra.Command {
-	return &cobra.Command{
+	var check bool
+
+	cmd := &cobra.Command{
 		Use:   "version",
 		Short: "Show version information",
-		Long:  "Display version, commit, and build information for zoom-to-box",
-		Run: func(cmd *cobra.Command, args []string) {
+		Long: `Display version, commit, and build information for zoom-to-box
+
+With --check the latest release is looked up on GitHub and compared with this
+build. Set GITHUB_TOKEN to avoid GitHub's anonymous rate limit; HTTPS_PROXY is
+honored.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
 			cmd.Printf("zoom-to-box version %s\n", version)
 			cmd.Printf("Commit: %s\n", commit)
 			cmd.Printf("Build date: %s\n", buildDate)
+			if !check {
+				return nil
+			}
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), time.Minute)
+			defer cancel()
+			release, err := releaseClient().Latest(ctx)
+			if err != nil {
+				return err
+			}
+			cmd.Printf("\nLatest release: %s (published %s)\n", release.TagName, release.PublishedAt.Format("2006-01-02"))
+			cmd.Printf("Release notes: %s\n", release.URL)
+
+			newer, err := selfupdate.Newer(version, release.TagName)
+			switch {
+			case err != nil:
+				cmd.Printf("This is a development build; run 'zoom-to-box self-update --force' to install %s\n", release.TagName)
+			case newer:
+				cmd.Printf("Update available: run 'zoom-to-box self-update' to install %s\n", release.TagName)
+			default:
+				cmd.Printf("zoom-to-box is up to date\n")
+			}
+			return nil
 		},
 	}
+
+	cmd.Flags().BoolVar(&check, "check", false, "look up the latest release on GitHub and report whether an update is available")
+
+	return cmd
 }
 
-// createConfigCommand creates the config help subcommand
-func createConfigCommand() *cobra.Command {
-	return &cobra.Command{
-		Use:   "config",
-		Short: "Show configuration file structure and examples",
-		Long:  "Display the required configuration file structure, authentication methods, environment variables, and comprehensive examples",
-		Run: func(cmd *cobra.Command, args []string) {
-			configHelp := `Configuration File Structure (config.yaml):
-
-ZOOM API CONFIGURATION (Required):
-=================================
-zoom:
-  account_id: "your_zoom_account_id"       # Zoom Account ID from Server-to-Server OAuth app
-  client_id: "your_zoom_client_id"         # Client ID from Server-to-Server OAuth app  
-  client_secret: "your_zoom_client_secret" # Client Secret from Server-to-Server OAuth app
-  base_url: "https://api.zoom.us/v2"       # Zoom API base URL (default: https://api.zoom.us/v2)
-
-# REQUIRED SCOPES: recording:read, user:read, meeting:read
-# Uses Server-to-Server OAuth (account-level access, no user tokens needed)
-
-DOWNLOAD CONFIGURATION:
-======================
-download:
-  output_dir: "./downloads"        # Local download directory (default: ./downloads)
-  concurrent_limit: 3              # Max concurrent downloads (default: 3, range: 1-10)
-  retry_attempts: 3                # Max retry attempts for failed downloads (default: 3)
-  timeout_seconds: 300             # Download timeout in seconds (default: 300 = 5 minutes)
-
-LOGGING CONFIGURATION:
-=====================
-logging:
-  level: "info"                    # Log level: debug, info, warn, error (default: info)
-  file: "./zoom-downloader.log"    # Log file path (default: ./zoom-downloader.log)
-  console: true                    # Enable console output (default: true)
-  json_format: false               # Use JSON log format (default: false)
-
-BOX INTEGRATION (Optional):
-==========================
-box:
-  enabled: false                   # Enable Box uploads (default: false)
-  client_id: "your_box_client_id"  # Box OAuth 2.0 client ID
-  client_secret: "your_box_client_secret" # Box OAuth 2.0 client secret
-  enterprise_id: "your_box_enterprise_id" # Box enterprise ID for client credentials auth
-  # Note: Files are uploaded to user-specific folders within the service account's root folder
-
-ACTIVE USERS FILTERING (Optional):
-=================================
-active_users:
-  file: "./active_users.txt"       # Path to active users list file
-  check_enabled: true              # Enable user filtering (default: true)
-
-# Active users file format (one email per line):
-# john.doe@company.com
-# jane.smith@company.com
-# # Lines starting with # are comments
-# admin@company.com
-#
-# For different Zoom and Box emails, use comma separation:
-# john.doe@zoomaccount.com,john.doe@company.com
-# admin@zoomaccount.com,admin@company.com
-
-ENVIRONMENT VARIABLES:
-=====================
-
-Required Zoom API credentials (override config file):
-  ZOOM_ACCOUNT_ID     - Your Zoom account ID
-  ZOOM_CLIENT_ID      - Your Zoom OAuth app client ID
-  ZOOM_CLIENT_SECRET  - Your Zoom OAuth app client secret
-  ZOOM_BASE_URL       - Zoom API base URL (optional)
-
-Optional Box integration:
-  BOX_CLIENT_ID     - Box OAuth 2.0 client ID
-  BOX_CLIENT_SECRET - Box OAuth 2.0 client secret
-  BOX_ENTERPRISE_ID - Box enterprise ID for client credentials auth
-
-Other settings:
-  DOWNLOAD_OUTPUT_DIR  - Base download directory
-
-AUTHENTICATION METHODS:
-======================
-
-1. Server-to-Server OAuth (Recommended):
-   - Account-level access to all users and recordings
-   - No user consent required
-   - Uses JWT-based authentication
-   - Required scopes: recording:read, user:read, meeting:read
-
-2. Environment Variables:
-   - Set ZOOM_ACCOUNT_ID, ZOOM_CLIENT_ID, ZOOM_CLIENT_SECRET
-   - Overrides any values in config.yaml
-   - Useful for CI/CD and containerized deployments
-
-EXAMPLE USAGE:
-=============
-
-1. Using configuration file:
-   cp config.example.yaml config.yaml
-   # Edit config.yaml with your credentials
-   zoom-to-box
-
-2. Using environment variables:
-   export ZOOM_ACCOUNT_ID="your-account-id"
-   export ZOOM_CLIENT_ID="your-client-id"
-   export ZOOM_CLIENT_SECRET="your-client-secret"
-   zoom-to-box
-
-3. With additional options:
-   zoom-to-box --meta-only --verbose
-   zoom-to-box --output-dir ./recordings --dry-run
-
-4. Single user processing:
-   zoom-to-box --zoom-user=john.doe@company.com --box-user=john.doe@company.com
-   zoom-to-box --zoom-user=john.doe@zoomaccount.com --box-user=john.doe@company.com
-
-5. Box integration:
-   # Set Box OAuth 2.0 credentials in config.yaml or environment variables
-   # Enable in config.yaml: box.enabled = true
-   export BOX_CLIENT_ID="your_box_client_id"
-   export BOX_CLIENT_SECRET="your_box_client_secret"
-   zoom-to-box --config config.yaml
-
-DIRECTORY STRUCTURE:
-==================
-Downloaded files are organized as:
-downloads/
-├── user.email/
-│   └── YYYY/
-│       └── MM/
-│           └── DD/
-│               ├── meeting-topic-HHMM.mp4
-│               └── meeting-topic-HHMM.json
-
-Box uploads are organized as:
-<service-account-root>/
-├── username/
-│   └── YYYY/
-│       └── MM/
-│           └── DD/
-│               ├── meeting-topic-HHMM.mp4
-│               └── meeting-topic-HHMM.json
-
-TROUBLESHOOTING:
-===============
-- Ensure your Zoom app has Server-to-Server OAuth enabled
-- Verify required scopes are granted: recording:read, user:read, meeting:read
-- Check account_id matches your Zoom account (not user ID)
-- For Box integration, ensure OAuth 2.0 client credentials are valid
-
-For more information, visit: https://github.com/curtbushko/zoom-to-box
-`
-			cmd.Print(configHelp)
-		},
+// buildBoxClient creates the Box client for the configured auth mode
+func buildBoxClient(cfg *config.Config) (box.BoxClient, error) {
+	// Validate Box configuration
+	if cfg.Box.ClientID == "" {
+		return nil, fmt.Errorf("box.client_id is required when Box is enabled")
+	}
+	if cfg.Box.ClientSecret == "" {
+		return nil, fmt.Errorf("box.client_secret is required when Box is enabled")
+	}
+
+	// Create Box client
+	credentials := &box.OAuth2Credentials{
+		ClientID:         cfg.Box.ClientID,
+		ClientSecret:     cfg.Box.ClientSecret,
+		ClientSecretNext: cfg.Box.ClientSecretNext,
+		EnterpriseID:     cfg.Box.EnterpriseID,
 	}
+
+	var base http.RoundTripper
+	if cfg.Box.UploadBufferSize > 0 {
+		// Upload parts are written through this buffer; a larger one means fewer writes on fast links
+		if transport, ok := http.DefaultTransport.(*http.Transport); ok {
+			tuned := transport.Clone()
+			tuned.WriteBufferSize = cfg.Box.UploadBufferSize
+			base = tuned
+		}
+	}
+	httpClient := &http.Client{
+		Timeout:   30 * time.Second,
+		Transport: tracing.NewTransport(base),
+	}
+
+	var auth box.Authenticator
+	if cfg.Box.AuthMode == "user" {
+		// Individual account: use the refresh token saved by 'zoom-to-box box login'
+		userAuth, err := box.NewUserAuthenticator(cfg.Box.ClientID, cfg.Box.ClientSecret, cfg.Box.TokenFile, httpClient)
+		if err != nil {
+			return nil, err
+		}
+		auth = userAuth
+	} else {
+		auth = box.NewOAuth2Authenticator(credentials, httpClient)
+		if cfg.TokenCache.Enabled {
+			box.UseTokenCache(auth, tokencache.NewFileCache(cfg.TokenCache.File))
+		}
+	}
+	boxClient := box.NewBoxClientWithOptions(auth, httpClient, box.ClientOptions{
+		UploadConcurrency: cfg.Box.UploadConcurrency,
+		UploadPartSize:    cfg.Box.UploadPartSize,
+		APIRetry:          cfg.Retry.BoxAPI,
+		UploadRetry:       cfg.Retry.BoxUpload,
+		CircuitBreaker:    cfg.Retry.BoxCircuitBreaker,
+
+		DestinationFolderID: cfg.Box.DestinationFolderID,
+	})
+	if cfg.Box.CleanupEmptyFolders {
+		// Track folders created by this run so empty ones can be removed after each user
+		boxClient = box.NewFolderTrackingClient(boxClient)
+	}
+	return boxClient, nil
 }
 
 // runDownloadWithProgress executes the download operation with progress reporting
-func runDownloadWithProgress(ctx context.Context, cmd *cobra.Command, cfg *config.Config) error {
+func runDownloadWithProgress(ctx context.Context, cmd *cobra.Command, cfg *config.Config) (err error) {
+	// With --quiet or --output json only errors, or the JSON document, are printed; console
+	// logs move to stderr so stdout stays parseable
+	var stats *DownloadStats
+	if !humanOutput() {
+		cmd.SetOut(io.Discard)
+		logging.SetConsoleOutput(os.Stderr)
+		defer logging.SetConsoleOutput(os.Stdout)
+	}
+	if quiet {
+		logging.SetConsoleLevel(logging.ErrorLevel)
+		defer logging.SetConsoleLevel(logging.DebugLevel)
+	}
+	if outputFormat == outputJSON {
+		defer func() {
+			printRunOutput(os.Stdout, stats, err)
+		}()
+	}
+
+	// Draw progress bars on interactive terminals, with console logs printed above them
+	var reporter progress.Reporter
+	if !noProgress && humanOutput() && progress.IsTerminal(os.Stdout) {
+		display := progress.NewDisplay(os.Stdout)
+		logging.SetConsoleOutput(display)
+		defer logging.SetConsoleOutput(os.Stdout)
+		reporter = display
+	}
+
 	// Initialize logging first
 	if err := logging.InitializeLogging(cfg.Logging); err != nil {
 		return fmt.Errorf("failed to initialize logging: %w", err)
@@ -352,6 +515,14 @@ func runDownloadWithProgress(ctx context.Context, cmd *cobra.Command, cfg *confi
 		}
 	}()
 
+	stopTracing, err := startTracing(cfg)
+	if err != nil {
+		return err
+	}
+	defer stopTracing()
+	ctx, runSpan := tracing.Start(ctx, "run")
+	defer runSpan.End()
+
 	logger := logging.GetDefaultLogger()
 
 	// Apply command-line overrides to config
@@ -364,6 +535,66 @@ func runDownloadWithProgress(ctx context.Context, cmd *cobra.Command, cfg *confi
 		cfg.ActiveUsers.File = activeUsersFile
 	}
 
+	// Override date range if provided
+	if fromDate != "" {
+		cfg.Download.FromDate = fromDate
+	}
+	if toDate != "" {
+		cfg.Download.ToDate = toDate
+	}
+
+	// Override the preview length if provided
+	if previewMinutes > 0 {
+		cfg.Download.PreviewMinutes = previewMinutes
+	}
+
+	// Pipe recordings into Box without local copies if requested
+	if stream {
+		cfg.Download.Stream = true
+	}
+	if cfg.Download.Stream && !cfg.Box.Enabled && logger != nil {
+		logger.WarnWithContext(ctx, "Stream mode needs Box uploads; recordings are downloaded to disk")
+	}
+
+	// Override the run time limit if provided
+	if maxRunDuration > 0 {
+		cfg.Limits.MaxRunDuration = maxRunDuration
+	}
+
+	// Keep the recordings local for a later upload run
+	if downloadOnly {
+		cfg.DisableUploads()
+		if logger != nil {
+			logger.InfoWithContext(ctx, "Download-only run: uploads are disabled")
+		}
+	}
+
+	// Refuse to overlap another run holding the same --lock-file, even a dry run
+	if runLockFile != "" {
+		lock, err := runlock.Acquire(runLockFile)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err := lock.Release(); err != nil {
+				logging.Warn("%v", err)
+			}
+		}()
+	}
+
+	// Refuse to overlap another run, e.g. a daemon run, on the same output directory
+	if !dryRun {
+		lock, err := runlock.Acquire(filepath.Join(cfg.Download.OutputDir, runlock.FileName))
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err := lock.Release(); err != nil {
+				logging.Warn("%v", err)
+			}
+		}()
+	}
+
 	// Handle single user mode
 	singleUserConfig := SingleUserConfig{
 		Enabled:   zoomUser != "" && boxUser != "",
@@ -395,6 +626,9 @@ func runDownloadWithProgress(ctx context.Context, cmd *cobra.Command, cfg *confi
 			"output_dir":       cfg.Download.OutputDir,
 			"single_user_mode": singleUserConfig.Enabled,
 		}
+		if cfg.Profile != "" {
+			sessionInfo["profile"] = cfg.Profile
+		}
 
 		if singleUserConfig.Enabled {
 			sessionInfo["single_zoom_email"] = singleUserConfig.ZoomEmail
@@ -405,15 +639,47 @@ func runDownloadWithProgress(ctx context.Context, cmd *cobra.Command, cfg *confi
 	}
 
 	if dryRun {
-		cmd.Printf("DRY RUN: Showing what would be downloaded (no files will be saved)\n\n")
+		cmd.Printf("DRY RUN: Showing what would be downloaded and uploaded (no files or folders will be created)\n\n")
+	}
+
+	// Fail fast when the output filesystem cannot hold the run
+	if err := checkOutputFilesystem(ctx, cfg, singleUserConfig); err != nil {
+		notifyRun(ctx, cfg, nil, err)
+		return err
 	}
 
 	// Execute download operations
-	stats, err := performDownloads(ctx, cfg, singleUserConfig)
+	// Keep a progress file up to date for external dashboards
+	if cfg.Download.ProgressFile {
+		fileReporter := progress.NewFileReporter(filepath.Join(cfg.Download.OutputDir, "progress.json"), progress.DefaultFileInterval)
+		defer func() {
+			if err := fileReporter.Close(); err != nil {
+				logging.Warn("Failed to write final progress file: %v", err)
+			}
+		}()
+		reporter = progress.Multi(reporter, fileReporter)
+	}
+
+	stats, err = performDownloads(ctx, cfg, singleUserConfig, reporter)
+	notifyRun(ctx, cfg, stats, err)
 	if err != nil {
 		return fmt.Errorf("download operation failed: %w", err)
 	}
 
+	if stats.Interrupted {
+		cmd.Printf("\nINTERRUPTED\n")
+		cmd.Printf("Summary before the interrupt:\n")
+		cmd.Printf("- Downloaded: %d\n", stats.SuccessCount)
+		cmd.Printf("- Uploaded: %d\n", stats.UploadCount)
+		cmd.Printf("- Skipped: %d\n", stats.SkippedCount)
+		cmd.Printf("- Failed: %d\n", stats.ErrorCount)
+		cmd.Printf("Partial downloads were removed and unfinished users stay incomplete\n")
+		if logger != nil {
+			logger.InfoWithContext(ctx, "Run interrupted by signal, remaining work is left for the next run")
+		}
+		return errRunInterrupted
+	}
+
 	// Display results
 	if dryRun {
 		cmd.Printf("\nDRY RUN COMPLETED\n")
@@ -421,6 +687,10 @@ func runDownloadWithProgress(ctx context.Context, cmd *cobra.Command, cfg *confi
 			cmd.Printf("Errors encountered: %d\n", stats.ErrorCount)
 		} else {
 			cmd.Printf("Would have processed %d recordings\n", stats.SuccessCount+stats.SkippedCount)
+			cmd.Printf("- Would transfer: %d files, %s\n", stats.SuccessCount, progress.FormatBytes(stats.BytesPlanned))
+			if stats.SkippedCount > 0 {
+				cmd.Printf("- Already present or skipped: %d files\n", stats.SkippedCount)
+			}
 			if metaOnly {
 				cmd.Printf("Would have downloaded metadata files only\n")
 			}
@@ -443,32 +713,591 @@ func runDownloadWithProgress(ctx context.Context, cmd *cobra.Command, cfg *confi
 				cmd.Printf("- Failed: %d\n", stats.ErrorCount)
 			}
 		}
+		if verbose && stats.Report != nil && stats.Report.Summary.Transfers != nil {
+			cmd.Printf("\nTransfer statistics:\n")
+			for _, line := range stats.Report.Summary.Transfers.Lines() {
+				cmd.Printf("- %s\n", line)
+			}
+		}
+	}
+
+	if stats.DiskFull {
+		if logger != nil {
+			logger.InfoWithContext(ctx, "Run stopped: output disk is below download.disk_reserve_gb, remaining work is left for the next run")
+		}
+		return errRunDiskFull
+	}
+	if stats.TimeBoxed && stats.StopReason != "" {
+		if logger != nil {
+			logger.InfoWithContext(ctx, fmt.Sprintf("Run stopped by the resource monitor: %s, remaining work is left for the next run", stats.StopReason))
+		}
+		return fmt.Errorf("%w (%s)", errRunStoppedByMonitor, stats.StopReason)
+	}
+	if stats.TimeBoxed {
+		if logger != nil {
+			logger.InfoWithContext(ctx, fmt.Sprintf("Run time-boxed after %v, remaining work is left for the next run", cfg.Limits.MaxRunDuration))
+		}
+		return errRunTimeBoxed
 	}
 
 	return nil
 }
 
+// humanOutput reports whether progress and summaries are printed, i.e. neither --quiet nor
+// --output json was given
+func humanOutput() bool {
+	return !quiet && outputFormat != outputJSON
+}
+
+// printf prints progress and summary lines unless --quiet or --output json was given
+func printf(format string, args ...interface{}) {
+	if humanOutput() {
+		fmt.Printf(format, args...)
+	}
+}
+
+// runExitCode returns the exit code of a run that ended with err
+func runExitCode(err error) int {
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, errRunInterrupted):
+		return exitCodeInterrupted
+	case errors.Is(err, errRunTimeBoxed), errors.Is(err, errRunStoppedByMonitor), errors.Is(err, errRunDiskFull):
+		return exitCodeTimeBoxed
+	default:
+		return exitCodeFor(err)
+	}
+}
+
+// printRunOutput writes the --output json document for a run that ended with stats and err
+func printRunOutput(w io.Writer, stats *DownloadStats, err error) {
+	output := runOutput{Status: "completed", ExitCode: runExitCode(err)}
+	switch {
+	case err == nil:
+	case output.ExitCode == exitCodeInterrupted:
+		output.Status = "interrupted"
+	case output.ExitCode == exitCodeTimeBoxed:
+		output.Status = "stopped"
+	default:
+		output.Status = "failed"
+	}
+	if err != nil {
+		output.Error = err.Error()
+	}
+	if stats != nil {
+		output.RunReport = stats.Report
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if encodeErr := encoder.Encode(output); encodeErr != nil {
+		logging.Error("Failed to write JSON output: %v", encodeErr)
+	}
+}
+
+// notifyRun posts the outcome of a run to the configured chat webhooks; dry runs are not reported
+func notifyRun(ctx context.Context, cfg *config.Config, stats *DownloadStats, runErr error) {
+	var notifiers []notify.Notifier
+	if cfg.Notifications.Slack.WebhookURL != "" {
+		notifiers = append(notifiers, notify.NewSlackNotifier(cfg.Notifications.Slack.WebhookURL))
+	}
+	if cfg.Notifications.Teams.WebhookURL != "" {
+		notifiers = append(notifiers, notify.NewTeamsNotifier(cfg.Notifications.Teams.WebhookURL))
+	}
+	if email := cfg.Notifications.Email; email.SMTPHost != "" {
+		notifier, err := notify.NewEmailNotifier(notify.EmailConfig{
+			Host:        email.SMTPHost,
+			Port:        email.SMTPPort,
+			Username:    email.Username,
+			Password:    email.Password,
+			ImplicitTLS: email.ImplicitTLS,
+			From:        email.From,
+			To:          email.To,
+			Subject:     email.Subject,
+			Attach:      email.Attach,
+		})
+		if err != nil {
+			logging.Warn("Email notifications disabled: %v", err)
+		} else {
+			notifiers = append(notifiers, notifier)
+		}
+	}
+	if len(notifiers) == 0 || dryRun {
+		return
+	}
+
+	run := &notify.Run{Status: notify.StatusCompleted}
+	if stats != nil {
+		run.Report = stats.Report
+	}
+	switch {
+	case runErr != nil:
+		run.Status, run.Reason = notify.StatusAborted, runErr.Error()
+	case stats.Interrupted:
+		run.Status, run.Reason = notify.StatusStopped, "interrupted by a signal"
+	case stats.DiskFull:
+		run.Status, run.Reason = notify.StatusStopped, "output disk is below download.disk_reserve_gb"
+	case stats.TimeBoxed && stats.StopReason != "":
+		run.Status, run.Reason = notify.StatusStopped, "monitor limit "+stats.StopReason
+	case stats.TimeBoxed:
+		run.Status, run.Reason = notify.StatusStopped, "limits.max_run_duration reached"
+	case stats.ErrorCount > 0:
+		run.Status = notify.StatusWithErrors
+	}
+	if cfg.Notifications.OnFailureOnly && !run.Failed() {
+		return
+	}
+	notify.Send(ctx, notifiers, run)
+}
+
+// checkOutputFilesystem runs the preflight checks on the download directory: free space and
+// inodes, and whether the deepest path the run plans to create fits the filesystem's limits
+func checkOutputFilesystem(ctx context.Context, cfg *config.Config, singleUserConfig SingleUserConfig) error {
+	var zoomEmails, boxEmails []string
+	if singleUserConfig.Enabled {
+		zoomEmails = []string{singleUserConfig.ZoomEmail}
+		boxEmails = []string{singleUserConfig.BoxEmail}
+	} else if cfg.ActiveUsers.File != "" {
+		if usersFile, err := users.LoadActiveUsersFile(cfg.ActiveUsers.File); err == nil {
+			for _, entry := range usersFile.Entries {
+				zoomEmails = append(zoomEmails, entry.ZoomEmail)
+				boxEmails = append(boxEmails, entry.BoxEmail)
+			}
+		}
+	}
+
+	deepest, err := deepestPlannedPath(cfg, zoomEmails, boxEmails)
+	if err != nil {
+		return err
+	}
+
+	stats, err := preflight.Check(cfg.Download.OutputDir, preflight.Options{
+		MinFreeBytes:  uint64(cfg.Preflight.MinFreeMB) << 20,
+		MinFreeInodes: uint64(cfg.Preflight.MinFreeInodes),
+		MaxPathLength: cfg.Preflight.MaxPathLength,
+		DeepestPath:   deepest,
+	})
+	if errors.Is(err, preflight.ErrUnsupported) {
+		logging.Warn("Skipping output filesystem checks: %v", err)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("preflight check failed: %w", err)
+	}
+
+	if logger := logging.GetDefaultLogger(); logger != nil {
+		logger.InfoWithContext(ctx, fmt.Sprintf("Output filesystem: %s free, %d free inodes, deepest planned path %d of %d bytes",
+			progress.FormatBytes(int64(stats.FreeBytes)), stats.FreeInodes, len(deepest), stats.MaxPathLength))
+	}
+	return nil
+}
+
+// deepestPlannedPath returns the longest path a run for the given users is expected to create:
+// the directory layout for the longest username and a file name rendered from worst-case fields
+func deepestPlannedPath(cfg *config.Config, zoomEmails, boxEmails []string) (string, error) {
+	longest := func(values []string, fallback string) string {
+		result := fallback
+		for _, value := range values {
+			if len(value) > len(result) {
+				result = value
+			}
+		}
+		return result
+	}
+	usernames := make([]string, 0, len(boxEmails))
+	for _, boxEmail := range boxEmails {
+		usernames = append(usernames, email.ExtractUsername(boxEmail))
+	}
+	hostEmail := longest(zoomEmails, "")
+	username := longest(usernames, "")
+	if username == "" {
+		username = strings.Repeat("u", 32)
+	}
+	if hostEmail == "" {
+		hostEmail = username + "@example.com"
+	}
+
+	layout, err := directory.NewLayout(cfg.Directory.Layout)
+	if err != nil {
+		return "", fmt.Errorf("directory.layout: %w", err)
+	}
+	sanitizer := filename.NewFileSanitizer(filename.FileSanitizerOptions{WindowsSafe: cfg.Filename.WindowsSafe})
+	template, err := filename.NewTemplate(cfg.Filename.Template, sanitizer)
+	if err != nil {
+		return "", fmt.Errorf("filename.template: %w", err)
+	}
+
+	// A date with two-digit month and day, a topic at the sanitizer's length limit and the
+	// longest Zoom recording type, meeting UUID and file ID
+	start := time.Date(2024, 12, 31, 23, 59, 0, 0, time.UTC)
+	dir, err := layout.LocalPath(username, start)
+	if err != nil {
+		return "", err
+	}
+	name, err := template.Render(filename.NewTemplateData(sanitizer, strings.Repeat("x", 200), start, hostEmail,
+		"shared_screen_with_gallery_view", "JSON", strings.Repeat("x", 24), strings.Repeat("x", 36)))
+	if err != nil {
+		return "", err
+	}
+	// A second file of the same type and recording type adds both to the name
+	qualified := zoom.RecordingFile{ID: "b", FileType: "JSON", RecordingType: "shared_screen_with_gallery_view"}
+	name += template.Qualifier([]zoom.RecordingFile{{ID: "a", FileType: "JSON", RecordingType: qualified.RecordingType}, qualified}, qualified)
+
+	outputDir, err := filepath.Abs(cfg.Download.OutputDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", cfg.Download.OutputDir, err)
+	}
+	// Preview files and name collisions add suffixes, e.g. "-preview-99.json"
+	if cfg.Filename.WindowsSafe {
+		// Windows-safe runs shorten names to fit the path budget instead of failing
+		maxLength := cfg.Preflight.MaxPathLength
+		if maxLength <= 0 {
+			maxLength = directory.WindowsMaxPath
+		}
+		dir = directory.SafePath(dir)
+		name, err = directory.FitName(filepath.Join(outputDir, dir), name+"-preview", len("-99.json"), maxLength)
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(outputDir, dir, name+"-99.json"), nil
+	}
+	return filepath.Join(outputDir, dir, name+"-preview-99.json"), nil
+}
+
 // performDownloads executes the download process using the processor package
-func performDownloads(ctx context.Context, cfg *config.Config, singleUserConfig SingleUserConfig) (*DownloadStats, error) {
-	logger := logging.GetDefaultLogger()
+// reporter receives transfer progress (nil = no progress bars)
+func performDownloads(ctx context.Context, cfg *config.Config, singleUserConfig SingleUserConfig, reporter progress.Reporter) (*DownloadStats, error) {
 	stats := &DownloadStats{}
+	startedAt := time.Now()
+
+	// Record failed file operations so they can be replayed with 'zoom-to-box replay'
+	var report *runreport.Report
+	var recorder runreport.FailureRecorder
+	if runReportFile != "" && !dryRun {
+		report = runreport.New()
+		recorder = report
+		defer func() {
+			if err := report.Save(runReportFile); err != nil {
+				logging.Error("Failed to save run report: %v", err)
+				return
+			}
+			if len(report.Failures) > 0 {
+				printf("Run report with %d failed operations written to %s\n", len(report.Failures), runReportFile)
+			}
+		}()
+	}
 
-	// Initialize Zoom API client
-	auth := zoom.NewServerToServerAuth(cfg.Zoom)
-	httpConfig := zoom.HTTPClientConfigFromDownloadConfig(cfg.Download)
-	retryClient := zoom.NewRetryHTTPClient(httpConfig)
-	authRetryClient := zoom.NewAuthenticatedRetryClient(retryClient, auth)
-	zoomClient := zoom.NewZoomClient(authRetryClient, cfg.Zoom.BaseURL)
+	// Stop starting new files once the run time limit is reached
+	var deadline time.Time
+	if cfg.Limits.MaxRunDuration > 0 {
+		deadline = time.Now().Add(cfg.Limits.MaxRunDuration)
+		printf("Run time limit: %v (until %s)\n", cfg.Limits.MaxRunDuration, deadline.Format(time.Kitchen))
+	}
+
+	// Sample resource usage; with monitor.restart_on_limit a limit stops new files like a deadline
+	watchdogCtx, stopWatchdog := context.WithCancel(ctx)
+	defer stopWatchdog()
+	limitStop, limitReason := startWatchdog(watchdogCtx, cfg)
+	defer func() {
+		stats.StopReason = limitReason()
+	}()
+
+	userProcessor, cleanup, err := buildUserProcessor(ctx, cfg, processorOptions{recorder: recorder, deadline: deadline, stop: limitStop, progress: reporter, abortUploadsOnCancel: true})
+	if err != nil {
+		return stats, err
+	}
+	defer cleanup()
+
+	// Handle single user mode vs batch mode
+	if singleUserConfig.Enabled {
+		// Single user mode
+		printf("Single user mode: Processing recordings for %s\n", singleUserConfig.ZoomEmail)
+		if singleUserConfig.BoxEmail != singleUserConfig.ZoomEmail {
+			printf("Box email mapping: %s -> %s\n", singleUserConfig.ZoomEmail, singleUserConfig.BoxEmail)
+		}
+
+		result, err := userProcessor.ProcessUser(ctx, singleUserConfig.ZoomEmail, singleUserConfig.BoxEmail)
+		writeReportFile(processor.SummaryForUser(result), startedAt)
+		if result != nil {
+			stats.Report = processor.NewRunReport(processor.SummaryForUser(result), startedAt, dryRun)
+		}
+
+		// Convert processor result to download stats
+		if result != nil {
+			stats.SuccessCount = result.DownloadedCount
+			stats.UploadCount = result.UploadedCount
+			stats.ErrorCount = result.ErrorCount
+			stats.SkippedCount = result.SkippedCount
+			stats.TimeBoxed = result.TimeBoxed
+			stats.DiskFull = result.DiskFull
+			stats.BytesPlanned = result.BytesPlanned
+		}
+		stats.Interrupted = ctx.Err() != nil
+
+		if err != nil && !continueOnError && !stats.Interrupted {
+			return stats, fmt.Errorf("failed to process user %s: %w", singleUserConfig.ZoomEmail, err)
+		}
+
+		return stats, nil
+	}
+
+	// Batch mode with active users file
+	if cfg.ActiveUsers.File == "" {
+		return stats, fmt.Errorf("active users file not configured and no single user specified")
+	}
+
+	// Load active users file
+	activeUsersFile, err := users.LoadActiveUsersFile(cfg.ActiveUsers.File)
+	if err != nil {
+		return stats, fmt.Errorf("failed to load active users file: %w", err)
+	}
+
+	printf("Processing users from active users file: %s\n", cfg.ActiveUsers.File)
+
+	// Process all incomplete users
+	summary, err := userProcessor.ProcessAllUsers(ctx, activeUsersFile)
+	writeReportFile(summary, startedAt)
+	stats.Interrupted = ctx.Err() != nil
+	if err != nil && !continueOnError && !stats.Interrupted {
+		return stats, fmt.Errorf("failed to process users: %w", err)
+	}
+	if summary == nil {
+		return stats, nil
+	}
+
+	// Convert processor summary to download stats
+	stats.Report = processor.NewRunReport(summary, startedAt, dryRun)
+	stats.SuccessCount = summary.TotalDownloads
+	stats.UploadCount = summary.TotalUploads
+	stats.ErrorCount = summary.TotalErrors
+	stats.SkippedCount = summary.TotalSkipped
+	stats.TimeBoxed = summary.TimeBoxed
+	stats.DiskFull = summary.DiskFull
+	stats.BytesPlanned = summary.TotalBytesPlanned
+
+	// Print summary
+	printf("\nProcessing Summary:\n")
+	printf("- Total users processed: %d/%d\n", summary.ProcessedUsers, summary.TotalUsers)
+	printf("- Failed users: %d\n", summary.FailedUsers)
+	if summary.RetriedUsers > 0 {
+		printf("- Retried after transient errors: %d\n", summary.RetriedUsers)
+	}
+	printf("- Total downloads: %d\n", summary.TotalDownloads)
+	printf("- Total uploads: %d\n", summary.TotalUploads)
+	printf("- Total deleted: %d\n", summary.TotalDeleted)
+	printf("- Duration: %v\n", summary.Duration)
+
+	return stats, nil
+}
+
+// startWatchdog samples resource usage every monitor.interval until ctx is cancelled
+// With monitor.restart_on_limit the returned channel is closed when a limit is exceeded and
+// reason returns the exceeded limit; otherwise the channel is nil and limits are only logged.
+func startWatchdog(ctx context.Context, cfg *config.Config) (stop <-chan struct{}, reason func() string) {
+	var (
+		mu         sync.Mutex
+		stopReason string
+	)
+	reason = func() string {
+		mu.Lock()
+		defer mu.Unlock()
+		return stopReason
+	}
+	if cfg.Monitor.Interval <= 0 {
+		return nil, reason
+	}
+
+	watchdogConfig := watchdog.Config{
+		Interval:      cfg.Monitor.Interval,
+		MaxHeapBytes:  uint64(cfg.Monitor.MaxHeapMB) << 20,
+		MaxGoroutines: cfg.Monitor.MaxGoroutines,
+		MaxOpenFiles:  cfg.Monitor.MaxOpenFiles,
+	}
+	if cfg.Monitor.RestartOnLimit {
+		stopCh := make(chan struct{})
+		stop = stopCh
+		watchdogConfig.OnLimit = func(limit string) {
+			mu.Lock()
+			stopReason = limit
+			mu.Unlock()
+			logging.Warn("Stopping after in-flight transfers so the run can be restarted: %s", limit)
+			close(stopCh)
+		}
+	}
+
+	go watchdog.New(watchdogConfig).Run(ctx)
+	return stop, reason
+}
+
+// writeReportFile writes the JSON end-of-run report if --report-file was given
+func writeReportFile(summary *processor.ProcessorSummary, startedAt time.Time) {
+	if reportFile == "" || summary == nil {
+		return
+	}
+	if err := processor.NewRunReport(summary, startedAt, dryRun).WriteFile(reportFile); err != nil {
+		logging.Error("Failed to write report file: %v", err)
+		return
+	}
+	printf("Run report written to %s\n", reportFile)
+}
+
+// processorOptions holds per-run settings for buildUserProcessor that do not come from the configuration
+type processorOptions struct {
+	recorder runreport.FailureRecorder // Receives failed file operations (nil = not recorded)
+	deadline time.Time                 // Stop starting new files once reached (zero = no limit)
+	stop     <-chan struct{}           // Closed to stop starting new files (nil = never)
+	progress progress.Reporter         // Receives transfer progress (nil = not reported)
+
+	abortUploadsOnCancel bool // Abort the destination's in-progress uploads when ctx is cancelled
+}
+
+// openStatusTracker opens the download status file that records each file's download and upload
+// A dry run only reads an existing file and never creates one.
+func openStatusTracker(cfg *config.Config) (download.StatusTracker, error) {
+	statusFile := cfg.Download.StatusFilePath()
+	if dryRun {
+		if _, err := os.Stat(statusFile); err != nil {
+			return nil, nil
+		}
+	}
+	statusTracker, err := download.NewStatusTracker(statusFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open download status file: %w", err)
+	}
+	return statusTracker, nil
+}
+
+// sharedLinkAccess returns the access level of the shared links created for uploaded recordings
+// ("" = none); only Box uploads are shared
+func sharedLinkAccess(cfg *config.Config) string {
+	if !cfg.Box.Enabled {
+		return ""
+	}
+	return strings.ToLower(cfg.Box.CreateSharedLinks)
+}
+
+// buildUserProcessor creates the Zoom client, download manager, upload destination and
+// user processor from the configuration. The returned cleanup function releases resources
+// held by the processor's dependencies.
+func buildUserProcessor(ctx context.Context, cfg *config.Config, opts processorOptions) (processor.UserProcessor, func(), error) {
+	logger := logging.GetDefaultLogger()
+
+	// Resolve the recordings date range
+	from, to, err := cfg.Download.DateRange(time.Now())
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid date range: %w", err)
+	}
+
+	checksumAlgorithm, err := download.ParseChecksumAlgorithm(cfg.Download.ChecksumAlgorithm)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid download configuration: %w", err)
+	}
+
+	encryption, err := loadEncryptionCipher(ctx, cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	recordingFilter, err := processor.NewRecordingFilter(cfg.Filters.MinDurationMinutes, cfg.Filters.TopicRegex, cfg.Filters.ExcludeTopicRegex, cfg.Filters.MeetingTypes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid filters configuration: %w", err)
+	}
+	recordingFilter.MinFileSize = cfg.Filters.MinFileSize
+
+	// Initialize Zoom API client, paced for the account's rate tier
+	zoomClient := buildZoomClient(cfg)
+	detectZoomRateTier(ctx, zoomClient, cfg)
 
 	// Initialize download manager
 	downloadManager := download.NewDownloadManager(download.DownloadConfig{
-		ChunkSize:     64 * 1024, // 64KB chunks
-		RetryAttempts: cfg.Download.RetryAttempts,
-		RetryDelay:    1 * time.Second,
+		ChunkSize:     cfg.Download.ChunkSize,
+		RetryAttempts: cfg.Retry.ZoomDownload.Retries(),
+		RetryDelay:    cfg.Retry.ZoomDownload.BaseDelay,
 		UserAgent:     "zoom-to-box/1.0",
 		Timeout:       cfg.Download.TimeoutDuration(),
+
+		RetryMultiplier: cfg.Retry.ZoomDownload.Multiplier,
+		RetryMaxDelay:   cfg.Retry.ZoomDownload.MaxDelay,
+
+		ReadBufferSize: cfg.Download.ReadBufferSize,
 	})
 
+	// Initialize the upload destination (Box or Google Drive) if enabled
+	var destination storage.UploadDestination
+	if cfg.Box.Enabled {
+		boxClient, err := buildBoxClient(cfg)
+		if err != nil {
+			return nil, nil, err
+		}
+		destination = storage.NewBoxDestinationWithOptions(box.NewUploadManager(boxClient), storage.BoxDestinationOptions{
+			ConflictPolicy: storage.ConflictPolicy(strings.ToLower(cfg.Upload.ConflictPolicy)),
+			UploadAsUser:   cfg.Box.UploadAsUser,
+
+			CreateZoomFolderIfMissing: cfg.Box.CreateZoomFolderIfMissing,
+			DestinationFolderID:       cfg.Box.DestinationFolderID,
+			DryRun:                    dryRun,
+			SearchExisting:            cfg.Box.SearchExisting,
+		})
+	} else if cfg.GoogleDrive.Enabled {
+		destination, err = storage.NewGoogleDriveDestination(storage.GoogleDriveConfig{
+			CredentialsFile: cfg.GoogleDrive.CredentialsFile,
+			RootFolderName:  cfg.GoogleDrive.RootFolder,
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create Google Drive destination: %w", err)
+		}
+	} else if cfg.S3.Enabled {
+		destination, err = storage.NewS3Destination(ctx, storage.S3Config{
+			Bucket:               cfg.S3.Bucket,
+			Region:               cfg.S3.Region,
+			KeyTemplate:          cfg.S3.KeyTemplate,
+			ServerSideEncryption: cfg.S3.ServerSideEncryption,
+			KMSKeyID:             cfg.S3.KMSKeyID,
+			Endpoint:             cfg.S3.Endpoint,
+			UsePathStyle:         cfg.S3.UsePathStyle,
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create S3 destination: %w", err)
+		}
+	} else if cfg.SFTP.Enabled {
+		destination, err = storage.NewSFTPDestination(storage.SFTPConfig{
+			Host:           cfg.SFTP.Host,
+			Port:           cfg.SFTP.Port,
+			User:           cfg.SFTP.User,
+			PrivateKeyFile: cfg.SFTP.PrivateKeyFile,
+			KnownHostsFile: cfg.SFTP.KnownHostsFile,
+			RootPath:       cfg.SFTP.RootPath,
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create SFTP destination: %w", err)
+		}
+	} else if cfg.WebDAV.Enabled {
+		destination, err = storage.NewWebDAVDestination(storage.WebDAVConfig{
+			URL:      cfg.WebDAV.URL,
+			Username: cfg.WebDAV.Username,
+			Password: cfg.WebDAV.Password,
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create WebDAV destination: %w", err)
+		}
+	}
+
+	if destination != nil {
+		// Initialize CSV trackers for upload tracking
+		globalCSVPath := filepath.Join(cfg.Download.OutputDir, "all-uploads.csv")
+		globalCSVTracker, err := tracking.NewGlobalCSVTracker(globalCSVPath)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create global CSV tracker: %w", err)
+		}
+		destination.SetGlobalCSVTracker(globalCSVTracker)
+
+		if logger != nil {
+			logger.InfoWithContext(ctx, fmt.Sprintf("%s upload integration enabled with CSV tracking", destination.Name()))
+		}
+		printf("%s upload integration enabled\n", destination.Name())
+	}
+
 	// Initialize user manager
 	userManager, err := users.NewActiveUserManager(users.ActiveUserConfig{
 		FilePath:      "", // Empty for single user mode, will use processor directly
@@ -476,136 +1305,360 @@ func performDownloads(ctx context.Context, cfg *config.Config, singleUserConfig
 		WatchFile:     false,
 	})
 	if err != nil {
-		return stats, fmt.Errorf("failed to initialize user manager: %w", err)
+		return nil, nil, fmt.Errorf("failed to initialize user manager: %w", err)
 	}
-	defer userManager.Close()
 
 	// Initialize directory manager
+	directoryLayout, err := directory.NewLayout(cfg.Directory.Layout)
+	if err != nil {
+		return nil, nil, fmt.Errorf("directory.layout: %w", err)
+	}
 	dirConfig := directory.DirectoryConfig{
 		BaseDirectory: cfg.Download.OutputDir,
 		CreateDirs:    true,
+
+		Layout: directoryLayout,
+
+		WindowsSafe:   cfg.Filename.WindowsSafe,
+		MaxPathLength: cfg.Preflight.MaxPathLength,
 	}
 	dirManager := directory.NewDirectoryManager(dirConfig, userManager)
 
 	// Initialize filename sanitizer
-	filenameSanitizer := filename.NewFileSanitizer(filename.FileSanitizerOptions{})
+	filenameSanitizer := filename.NewFileSanitizer(filename.FileSanitizerOptions{WindowsSafe: cfg.Filename.WindowsSafe})
+	filenameTemplate, err := filename.NewTemplate(cfg.Filename.Template, filenameSanitizer)
+	if err != nil {
+		return nil, nil, fmt.Errorf("filename.template: %w", err)
+	}
+	if _, err := processor.MetadataSerializerFor(cfg.Metadata.Format); err != nil {
+		return nil, nil, fmt.Errorf("metadata.format: %w", err)
+	}
 
-	// Initialize Box upload manager if enabled
-	var uploadManager box.UploadManager
+	var metadataTemplate *processor.MetadataTemplate
 	if cfg.Box.Enabled {
-		// Validate Box configuration
-		if cfg.Box.ClientID == "" {
-			return stats, fmt.Errorf("box.client_id is required when Box is enabled")
-		}
-		if cfg.Box.ClientSecret == "" {
-			return stats, fmt.Errorf("box.client_secret is required when Box is enabled")
-		}
-
-		// Create Box client
-		credentials := &box.OAuth2Credentials{
-			ClientID:     cfg.Box.ClientID,
-			ClientSecret: cfg.Box.ClientSecret,
-			EnterpriseID: cfg.Box.EnterpriseID,
-		}
-
-		httpClient := &http.Client{
-			Timeout: 30 * time.Second,
-		}
-
-		auth := box.NewOAuth2Authenticator(credentials, httpClient)
-		boxClient := box.NewBoxClient(auth, httpClient)
-		uploadManager = box.NewUploadManager(boxClient)
-
-		// Initialize CSV trackers for upload tracking
-		globalCSVPath := filepath.Join(cfg.Download.OutputDir, "all-uploads.csv")
-		globalCSVTracker, err := tracking.NewGlobalCSVTracker(globalCSVPath)
+		template := cfg.Box.MetadataTemplate
+		metadataTemplate, err = processor.NewMetadataTemplate(template.Scope, template.TemplateKey, template.Fields)
 		if err != nil {
-			return stats, fmt.Errorf("failed to create global CSV tracker: %w", err)
+			return nil, nil, fmt.Errorf("box.metadata_template: %w", err)
 		}
-		uploadManager.SetGlobalCSVTracker(globalCSVTracker)
+	}
 
-		if logger != nil {
-			logger.InfoWithContext(ctx, "Box upload integration enabled with CSV tracking")
-		}
-		fmt.Printf("Box upload integration enabled\n")
+	hookRunner, err := hooks.New(hooks.Config{
+		Commands: cfg.Hooks.PostUpload,
+		URL:      cfg.Hooks.PostUploadURL,
+		Timeout:  cfg.Hooks.Timeout,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("hooks.post_upload: %w", err)
 	}
 
 	// Create processor
 	processorConfig := processor.ProcessorConfig{
 		BaseDownloadDir:   cfg.Download.OutputDir,
-		BoxEnabled:        cfg.Box.Enabled,
+		BoxEnabled:        destination != nil,
 		DeleteAfterUpload: deleteAfterUpload,
 		ContinueOnError:   continueOnError,
 		MetaOnly:          metaOnly,
 		Limit:             limit,
 		DryRun:            dryRun,
 		Verbose:           verbose,
+		FromDate:          from,
+		ToDate:            to,
+		ChecksumAlgorithm: checksumAlgorithm,
+		Thumbnails:        cfg.Download.Thumbnails,
+		TranscriptFormats: cfg.Download.TranscriptFormats,
+
+		WindowsSafePaths: cfg.Filename.WindowsSafe,
+		MaxPathLength:    cfg.Preflight.MaxPathLength,
+
+		PreviewMinutes:  cfg.Download.PreviewMinutes,
+		Stream:          cfg.Download.Stream,
+		IncludeWebinars: cfg.Zoom.IncludeWebinars,
+		IncludeTrash:    cfg.Zoom.IncludeTrash,
+		RestoreTrash:    cfg.Zoom.RestoreTrash,
+
+		DisablePasscodes: cfg.Zoom.DisablePasscodes,
+
+		AccountRecordings: cfg.Zoom.AccountRecordings,
+		PageSize:          cfg.Zoom.PageSize,
+
+		IncludeParticipants: cfg.Metadata.IncludeParticipants,
+
+		Progress: opts.progress,
+		Hooks:    hookRunner,
+
+		CleanupEmptyFolders: cfg.Box.Enabled && cfg.Box.CleanupEmptyFolders,
+		SharedLinkAccess:    sharedLinkAccess(cfg),
+		MetadataTemplate:    metadataTemplate,
+
+		FailureRecorder: opts.recorder,
+		Deadline:        opts.deadline,
+		Stop:            opts.stop,
+
+		Filter:           recordingFilter,
+		FilenameTemplate: filenameTemplate,
+		DirectoryLayout:  directoryLayout,
+
+		MetadataOrder:  processor.MetadataOrder(strings.ToLower(cfg.Upload.MetadataOrder)),
+		MetadataFormat: cfg.Metadata.Format,
+
+		DeleteRequiresVerification: cfg.Upload.DeleteRequiresVerification,
+		MigrationReport:            cfg.Upload.MigrationReport,
+
+		Encryption:        encryption,
+		EncryptionTempDir: cfg.Encryption.TempDir,
+
+		MaxUserBytes: cfg.Download.MaxUserBytes(),
+
+		ConcurrentDownloads: cfg.Download.ConcurrentLimit,
+
+		UserTimeout: cfg.Processor.UserTimeout,
+		FileTimeout: cfg.Download.PerFileTimeout,
+
+		SizeTolerancePercent: cfg.Download.SizeTolerancePercent,
+
+		TransientRetries:    cfg.Processor.TransientRetries,
+		TransientRetryDelay: cfg.Processor.TransientRetryDelay,
+	}
+	if cfg.Download.DiskReserveGB > 0 {
+		processorConfig.DiskGuard = preflight.NewDiskGuard(cfg.Download.OutputDir, cfg.Download.DiskReserveBytes(), cfg.Download.DiskWait)
 	}
 
-	userProcessor := processor.NewUserProcessor(
+	// Record each file's progress so an interrupted run resumes at the next recording
+	statusTracker, err := openStatusTracker(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	processorConfig.StatusTracker = statusTracker
+
+	// Set again the recording passcodes an interrupted run removed for a download
+	if statusTracker != nil && !dryRun {
+		if err := processor.RestorePendingPasscodes(ctx, zoomClient, statusTracker); err != nil {
+			logging.Warn("%v", err)
+		}
+	}
+
+	// One row per recording file of the run, next to the per-user uploads.csv files
+	if cfg.Tracking.RunSummary && !dryRun {
+		appendRows := strings.EqualFold(cfg.Tracking.RunSummaryMode, "append")
+		runSummary, err := tracking.NewRunSummaryCSVTracker(cfg.Tracking.RunSummaryFilePath(cfg.Download.OutputDir), appendRows)
+		if err != nil {
+			if statusTracker != nil {
+				statusTracker.Close()
+			}
+			return nil, nil, fmt.Errorf("failed to create run summary: %w", err)
+		}
+		processorConfig.RunSummary = runSummary
+	}
+
+	userProcessor := processor.NewUserProcessorWithDestination(
 		zoomClient,
 		downloadManager,
 		dirManager,
 		filenameSanitizer,
-		uploadManager,
+		destination,
 		processorConfig,
 	)
 
-	// Handle single user mode vs batch mode
-	if singleUserConfig.Enabled {
-		// Single user mode
-		fmt.Printf("Single user mode: Processing recordings for %s\n", singleUserConfig.ZoomEmail)
-		if singleUserConfig.BoxEmail != singleUserConfig.ZoomEmail {
-			fmt.Printf("Box email mapping: %s -> %s\n", singleUserConfig.ZoomEmail, singleUserConfig.BoxEmail)
+	// Abort open upload sessions when the run is interrupted so Box does not keep partial uploads
+	done := make(chan struct{})
+	if aborter, ok := destination.(storage.UploadAborter); ok && opts.abortUploadsOnCancel {
+		go func() {
+			select {
+			case <-ctx.Done():
+			case <-done:
+				return
+			}
+			n, err := aborter.AbortUploads(context.Background())
+			if err != nil {
+				logging.Warn("Failed to abort in-progress uploads: %v", err)
+			} else if n > 0 {
+				logging.Info("Aborted %d in-progress uploads", n)
+			}
+		}()
+	}
+
+	return userProcessor, func() {
+		close(done)
+		userManager.Close()
+		if statusTracker != nil {
+			statusTracker.Close()
 		}
+	}, nil
+}
 
-		result, err := userProcessor.ProcessUser(ctx, singleUserConfig.ZoomEmail, singleUserConfig.BoxEmail)
-		if err != nil && !continueOnError {
-			return stats, fmt.Errorf("failed to process user %s: %w", singleUserConfig.ZoomEmail, err)
+// shutdownContext returns a context cancelled by the first SIGINT or SIGTERM so in-flight
+// transfers stop and state is saved; after that the signals are reset so a second one quits immediately
+func shutdownContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		defer signal.Stop(signals)
+		select {
+		case sig := <-signals:
+			fmt.Fprintf(os.Stderr, "\nReceived %v: stopping transfers and saving state (press Ctrl-C again to quit immediately)\n", sig)
+			cancel()
+		case <-ctx.Done():
 		}
+	}()
+	return ctx, cancel
+}
 
-		// Convert processor result to download stats
-		stats.SuccessCount = result.DownloadedCount
-		stats.ErrorCount = result.ErrorCount
-		stats.SkippedCount = result.SkippedCount
+// loadConfig loads the configuration with the selected profile and applies its timestamp settings
+func loadConfig(configPath string) (*config.Config, error) {
+	cfg, err := config.LoadConfigWithProfile(configPath, profile)
+	if err != nil {
+		return nil, err
+	}
+	if err := timefmt.Configure(cfg.Time.Timezone, cfg.Time.Format); err != nil {
+		return nil, fmt.Errorf("invalid time settings: %w", err)
+	}
+	if err := network.Configure(network.Options{
+		ProxyURL:           cfg.Network.ProxyURL,
+		CACertFile:         cfg.Network.CACertFile,
+		InsecureSkipVerify: cfg.Network.InsecureSkipVerify,
+		MinTLSVersion:      cfg.Network.MinTLSVersion,
+	}); err != nil {
+		return nil, fmt.Errorf("invalid network settings: %w", err)
+	}
+	if cfg.Network.InsecureSkipVerify {
+		fmt.Fprintln(os.Stderr, insecureTLSWarning)
+	}
+	if chaosRate != 0 {
+		if err := chaos.Configure(chaos.Options{Rate: chaosRate}); err != nil {
+			return nil, fmt.Errorf("invalid --chaos: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "WARNING: --chaos is enabled: %g of Zoom and Box requests fail with injected 429s, 500s and timeouts\n", chaosRate)
+	}
+	registerConfigSecrets(cfg)
+	return cfg, nil
+}
 
-		return stats, nil
+// insecureTLSWarning is printed on every start while network.insecure_skip_verify is set
+const insecureTLSWarning = "WARNING: network.insecure_skip_verify is enabled: TLS certificates of Zoom, Box and download servers are NOT verified and credentials can be intercepted. Use network.ca_cert_file instead."
+
+// registerConfigSecrets keeps the configured credentials out of log output
+func registerConfigSecrets(cfg *config.Config) {
+	for _, secret := range []string{
+		cfg.Zoom.ClientSecret,
+		cfg.Zoom.ClientSecretNext,
+		cfg.Box.ClientSecret,
+		cfg.Box.ClientSecretNext,
+		cfg.WebDAV.Password,
+		cfg.Webhook.SecretToken,
+		cfg.Control.Token,
+		cfg.Encryption.Key,
+		cfg.Notifications.Slack.WebhookURL,
+		cfg.Notifications.Teams.WebhookURL,
+		cfg.Notifications.Email.Password,
+	} {
+		logging.RegisterSecret(secret)
 	}
+	if proxyURL, err := url.Parse(cfg.Network.ProxyURL); err == nil && proxyURL.User != nil {
+		password, _ := proxyURL.User.Password()
+		logging.RegisterSecret(password)
+	}
+}
 
-	// Batch mode with active users file
-	if cfg.ActiveUsers.File == "" {
-		return stats, fmt.Errorf("active users file not configured and no single user specified")
+// loadEncryptionCipher returns the cipher for recordings encrypted at rest, or nil when
+// encryption is disabled; the key comes from the config, a key file or AWS KMS
+func loadEncryptionCipher(ctx context.Context, cfg *config.Config) (*atrest.Cipher, error) {
+	if !cfg.Encryption.Enabled {
+		return nil, nil
 	}
 
-	// Load active users file
-	activeUsersFile, err := users.LoadActiveUsersFile(cfg.ActiveUsers.File)
+	var key []byte
+	var err error
+	switch {
+	case cfg.Encryption.Key != "":
+		key, err = atrest.ParseKey(cfg.Encryption.Key)
+	case cfg.Encryption.KeyFile != "":
+		var data []byte
+		data, err = os.ReadFile(cfg.Encryption.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read encryption key file: %w", err)
+		}
+		key, err = atrest.ParseKey(string(data))
+	default:
+		key, err = atrest.DecryptKMSKey(ctx, cfg.Encryption.KMSEncryptedKey, cfg.Encryption.KMSRegion)
+	}
 	if err != nil {
-		return stats, fmt.Errorf("failed to load active users file: %w", err)
+		return nil, fmt.Errorf("invalid encryption configuration: %w", err)
 	}
+	return atrest.NewCipher(key)
+}
 
-	fmt.Printf("Processing users from active users file: %s\n", cfg.ActiveUsers.File)
+// startTracing installs the OpenTelemetry exporter when tracing is enabled
+// The returned function flushes the spans still buffered and must run before exiting.
+func startTracing(cfg *config.Config) (func(), error) {
+	shutdown, err := tracing.Setup(context.Background(), cfg.Tracing, version)
+	if err != nil {
+		return nil, err
+	}
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := shutdown(ctx); err != nil {
+			logging.Warn("Failed to export traces: %v", err)
+		}
+	}, nil
+}
 
-	// Process all incomplete users
-	summary, err := userProcessor.ProcessAllUsers(ctx, activeUsersFile)
-	if err != nil && !continueOnError {
-		return stats, fmt.Errorf("failed to process users: %w", err)
+// buildZoomClient creates an authenticated Zoom API client from the configuration
+func buildZoomClient(cfg *config.Config) *zoom.ZoomClient {
+	auth := zoom.NewServerToServerAuth(cfg.Zoom)
+	if cfg.TokenCache.Enabled {
+		auth.SetTokenCache(tokencache.NewFileCache(cfg.TokenCache.File))
 	}
+	httpConfig := zoom.HTTPClientConfigFromDownloadConfig(cfg.Download).WithRetryPolicy(cfg.Retry.ZoomAPI)
+	httpConfig.RateLimits = zoomRateLimits(cfg, configuredRateTier(cfg))
+	retryClient := zoom.NewRetryHTTPClient(httpConfig)
+	authRetryClient := zoom.NewAuthenticatedRetryClient(retryClient, auth)
+	return zoom.NewZoomClient(authRetryClient, cfg.Zoom.BaseURL)
+}
 
-	// Convert processor summary to download stats
-	stats.SuccessCount = summary.TotalDownloads
-	stats.ErrorCount = summary.TotalErrors
-	stats.SkippedCount = summary.TotalSkipped
+// configuredRateTier returns the configured Zoom rate tier, or the default tier for auto
+func configuredRateTier(cfg *config.Config) zoom.RateTier {
+	tier, err := zoom.ParseRateTier(cfg.Zoom.RateTier)
+	if err != nil || tier == zoom.RateTierAuto {
+		return zoom.DefaultRateTier
+	}
+	return tier
+}
 
-	// Print summary
-	fmt.Printf("\nProcessing Summary:\n")
-	fmt.Printf("- Total users processed: %d/%d\n", summary.ProcessedUsers, summary.TotalUsers)
-	fmt.Printf("- Failed users: %d\n", summary.FailedUsers)
-	fmt.Printf("- Total downloads: %d\n", summary.TotalDownloads)
-	fmt.Printf("- Total uploads: %d\n", summary.TotalUploads)
-	fmt.Printf("- Total deleted: %d\n", summary.TotalDeleted)
-	fmt.Printf("- Duration: %v\n", summary.Duration)
+// zoomRateLimits returns the tier's pacing and concurrency with the configured overrides applied
+func zoomRateLimits(cfg *config.Config, tier zoom.RateTier) zoom.RateLimits {
+	limits := tier.Limits()
+	if cfg.Zoom.RequestsPerSecond > 0 {
+		limits.RequestsPerSecond = cfg.Zoom.RequestsPerSecond
+	}
+	if cfg.Zoom.MaxConcurrentRequests > 0 {
+		limits.MaxConcurrentRequests = cfg.Zoom.MaxConcurrentRequests
+	}
+	return limits
+}
 
-	return stats, nil
+// detectZoomRateTier asks Zoom for the account's rate tier when zoom.rate_tier is auto and
+// paces the client for it; the default tier is kept when Zoom does not report one
+func detectZoomRateTier(ctx context.Context, client *zoom.ZoomClient, cfg *config.Config) {
+	if tier, _ := zoom.ParseRateTier(cfg.Zoom.RateTier); tier != zoom.RateTierAuto {
+		limits := zoomRateLimits(cfg, configuredRateTier(cfg))
+		logging.Info("Zoom rate tier %s: %.1f requests/s, %d concurrent", configuredRateTier(cfg), limits.RequestsPerSecond, limits.MaxConcurrentRequests)
+		return
+	}
+
+	tier, err := client.DetectRateTier(ctx)
+	if err != nil {
+		limits := zoomRateLimits(cfg, zoom.DefaultRateTier)
+		logging.Info("Could not detect the Zoom rate tier (%v), assuming %s: %.1f requests/s, %d concurrent",
+			err, zoom.DefaultRateTier, limits.RequestsPerSecond, limits.MaxConcurrentRequests)
+		return
+	}
+
+	limits := zoomRateLimits(cfg, tier)
+	client.SetRateLimits(limits)
+	logging.Info("Detected Zoom rate tier %s: %.1f requests/s, %d concurrent", tier, limits.RequestsPerSecond, limits.MaxConcurrentRequests)
 }
 
 // saveMetadata saves recording metadata to a JSON file
@@ -646,7 +1699,7 @@ func main() {
 	
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		os.Exit(exitCodeFor(err))
 	}
 }
 
@@ -3,10 +3,19 @@ package main
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/curtbushko/zoom-to-box/internal/box"
+	"github.com/curtbushko/zoom-to-box/internal/config"
+	"github.com/curtbushko/zoom-to-box/internal/errclass"
+	"github.com/curtbushko/zoom-to-box/internal/processor"
+	"github.com/curtbushko/zoom-to-box/internal/zoom"
 	"github.com/spf13/cobra"
 )
 
@@ -84,6 +93,18 @@ func TestVersionCommand(t *testing.T) {
 	}
 }
 
+func TestSelfUpdateWithoutSigningKey(t *testing.T) {
+	cmd := createRootCommand()
+	buf := &bytes.Buffer{}
+	cmd.SetOut(buf)
+	cmd.SetErr(buf)
+	cmd.SetArgs([]string{"self-update"})
+
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "no release signing key") {
+		t.Errorf("Expected self-update to refuse without a signing key, got %v", err)
+	}
+}
+
 func TestConfigCommand(t *testing.T) {
 	cmd := createRootCommand()
 	
@@ -739,4 +760,236 @@ func TestSingleUserConfig(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+func TestFilterZoomUserEmails(t *testing.T) {
+	zoomUsers := []zoom.User{
+		{Email: "a@example.com", GroupIDs: []string{"sales"}},
+		{Email: "b@example.com", GroupIDs: []string{"eng", "ops"}},
+		{Email: "c@example.com"},
+		{Email: ""},
+	}
+
+	all := filterZoomUserEmails(zoomUsers, nil)
+	if strings.Join(all, ",") != "a@example.com,b@example.com,c@example.com" {
+		t.Errorf("Expected all users with an email, got %v", all)
+	}
+
+	grouped := filterZoomUserEmails(zoomUsers, []string{"ops", "sales"})
+	if strings.Join(grouped, ",") != "a@example.com,b@example.com" {
+		t.Errorf("Expected users in ops or sales, got %v", grouped)
+	}
+}
+
+func TestFetchCommandFlags(t *testing.T) {
+	tests := []struct {
+		name          string
+		args          []string
+		expectedError string
+	}{
+		{
+			name:          "meeting is required",
+			args:          []string{"fetch", "--file", "file-1", "--stdout"},
+			expectedError: "--meeting is required",
+		},
+		{
+			name:          "stdout or output is required",
+			args:          []string{"fetch", "--meeting", "uuid", "--file", "file-1"},
+			expectedError: "exactly one of --stdout or --output is required",
+		},
+		{
+			name:          "stdout and output are exclusive",
+			args:          []string{"fetch", "--meeting", "uuid", "--file", "file-1", "--stdout", "--output", "meeting.mp4"},
+			expectedError: "exactly one of --stdout or --output is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := createRootCommand()
+			buf := &bytes.Buffer{}
+			cmd.SetOut(buf)
+			cmd.SetErr(buf)
+			cmd.SetArgs(tt.args)
+
+			err := cmd.Execute()
+			if err == nil || !strings.Contains(err.Error(), tt.expectedError) {
+				t.Errorf("Expected error containing %q, got %v", tt.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestDeepestPlannedPath(t *testing.T) {
+	cfg := &config.Config{Download: config.DownloadConfig{OutputDir: "/data/zoom"}}
+
+	deepest, err := deepestPlannedPath(cfg, []string{"a@example.com", "jane.smith@example.com"}, []string{"a@box.example.com", "jane.smith@box.example.com"})
+	if err != nil {
+		t.Fatalf("deepestPlannedPath failed: %v", err)
+	}
+	if !strings.HasPrefix(deepest, filepath.Join("/data/zoom", "jane.smith", "2024", "12", "31")+string(filepath.Separator)) {
+		t.Errorf("Expected the longest username's date folder, got %q", deepest)
+	}
+	if !strings.HasSuffix(deepest, "-preview-99.json") {
+		t.Errorf("Expected room for preview and collision suffixes, got %q", deepest)
+	}
+
+	cfg.Directory.Layout = "{{.Year}}"
+	if _, err := deepestPlannedPath(cfg, nil, nil); err == nil {
+		t.Error("Expected an invalid directory layout to fail")
+	}
+}
+
+func TestExitCodeFor(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{err: errors.New("boom"), want: 1},
+		{err: fmt.Errorf("download operation failed: %w", &zoom.AuthError{Type: "invalid_client"}), want: exitCodeAuthError},
+		{err: &zoom.HTTPError{StatusCode: 429}, want: exitCodeRateLimitError},
+		{err: &box.BoxError{StatusCode: 404, Code: box.ErrorCodeItemNotFound}, want: exitCodeNotFoundError},
+		{err: &box.BoxError{StatusCode: 403, Code: box.ErrorCodeStorageLimitExceeded}, want: exitCodeQuotaError},
+		{err: errclass.Wrap(errclass.Network, errors.New("connection refused")), want: exitCodeNetworkError},
+		{err: fmt.Errorf("download failed: %w", errclass.Wrap(errclass.Passcode, errors.New("passcode page"))), want: exitCodePasscodeError},
+	}
+
+	for _, tt := range tests {
+		if got := exitCodeFor(tt.err); got != tt.want {
+			t.Errorf("exitCodeFor(%v) = %d, want %d", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestPrintRunOutput(t *testing.T) {
+	tests := []struct {
+		name       string
+		stats      *DownloadStats
+		err        error
+		wantStatus string
+		wantCode   int
+		wantReport bool
+	}{
+		{name: "completed", stats: &DownloadStats{Report: &processor.RunReport{Summary: processor.ReportSummary{Downloads: 2}}}, wantStatus: "completed", wantReport: true},
+		{name: "interrupted", stats: &DownloadStats{}, err: errRunInterrupted, wantStatus: "interrupted", wantCode: exitCodeInterrupted},
+		{name: "time-boxed", stats: &DownloadStats{}, err: errRunTimeBoxed, wantStatus: "stopped", wantCode: exitCodeTimeBoxed},
+		{name: "failed before processing", err: &zoom.AuthError{Type: "invalid_client"}, wantStatus: "failed", wantCode: exitCodeAuthError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			printRunOutput(&buf, tt.stats, tt.err)
+
+			var output map[string]interface{}
+			if err := json.Unmarshal(buf.Bytes(), &output); err != nil {
+				t.Fatalf("Expected one JSON document, got %q: %v", buf.String(), err)
+			}
+			if output["status"] != tt.wantStatus || output["exit_code"] != float64(tt.wantCode) {
+				t.Errorf("Expected status %s and exit code %d, got %v and %v", tt.wantStatus, tt.wantCode, output["status"], output["exit_code"])
+			}
+			if _, ok := output["error"]; ok != (tt.err != nil) {
+				t.Errorf("Expected error field only for failed runs, got %v", output["error"])
+			}
+			if _, ok := output["summary"]; ok != tt.wantReport {
+				t.Errorf("Expected summary present = %v, got %v", tt.wantReport, output)
+			}
+		})
+	}
+}
+
+func TestQuietAndOutputFlags(t *testing.T) {
+	cmd := createRootCommand()
+	for _, name := range []string{"quiet", "output"} {
+		if cmd.Flags().Lookup(name) == nil {
+			t.Errorf("Expected root command flag %q to be defined", name)
+		}
+	}
+
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--output", "yaml"})
+	defer func() { outputFormat = outputText }()
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "must be text or json") {
+		t.Errorf("Expected invalid --output error, got %v", err)
+	}
+}
+
+func TestDaemonSchedule(t *testing.T) {
+	cfg := &config.Config{Daemon: config.DaemonConfig{Schedule: "0 2 * * *"}}
+
+	sched, err := daemonSchedule(cfg, "")
+	if err != nil || sched.String() != "0 2 * * *" {
+		t.Errorf("Expected daemon.schedule to be used, got %v, %v", sched, err)
+	}
+	sched, err = daemonSchedule(cfg, "@hourly")
+	if err != nil || sched.String() != "@hourly" {
+		t.Errorf("Expected --schedule to override daemon.schedule, got %v, %v", sched, err)
+	}
+	if _, err := daemonSchedule(&config.Config{}, ""); err == nil || !strings.Contains(err.Error(), "a schedule is required") {
+		t.Errorf("Expected missing schedule error, got %v", err)
+	}
+	if _, err := daemonSchedule(cfg, "0 2 * *"); err == nil || !strings.Contains(err.Error(), "invalid schedule") {
+		t.Errorf("Expected invalid schedule error, got %v", err)
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+	write := func(content string) {
+		if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	validate := func() (string, error) {
+		cmd := createConfigValidateCommand()
+		buf := &bytes.Buffer{}
+		cmd.SetOut(buf)
+		err := runConfigValidate(cmd, configPath, false)
+		return buf.String(), err
+	}
+
+	write(`zoom:
+  account_id: "account"
+  client_id: "client"
+  client_secret: "secret"
+download:
+  output_dir: "` + filepath.Join(tmpDir, "downloads") + `"
+`)
+	output, err := validate()
+	if err != nil {
+		t.Fatalf("Expected a valid configuration, got %v\n%s", err, output)
+	}
+	if !strings.Contains(output, "Configuration is valid") {
+		t.Errorf("Expected the configuration reported valid, got %q", output)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "downloads")); !os.IsNotExist(err) {
+		t.Error("Expected validate not to create the output directory")
+	}
+
+	write(`zoom:
+  account_id: "account"
+  client_id: "client"
+  client_secret: "secret"
+box:
+  enabled: true
+  client_id: "box-client"
+  client_secret: "box-secret"
+  auth_mode: "user"
+  token_file: "` + filepath.Join(tmpDir, "missing-token.json") + `"
+download:
+  output_dir: "` + configPath + `"
+`)
+	output, err = validate()
+	if err == nil || !strings.Contains(err.Error(), "2 problem(s)") {
+		t.Fatalf("Expected 2 problems, got %v\n%s", err, output)
+	}
+	if !strings.Contains(output, "FAIL  download.output_dir") || !strings.Contains(output, "zoom-to-box box login") {
+		t.Errorf("Expected the output directory and token file failures listed, got %q", output)
+	}
+
+	write("zoom:\n  account_id: \"account\"\n")
+	if output, err := validate(); err == nil || !strings.Contains(output, "FAIL") {
+		t.Errorf("Expected a configuration missing credentials to fail, got %v\n%s", err, output)
+	}
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	"github.com/curtbushko/zoom-to-box/internal/config"
+	"github.com/curtbushko/zoom-to-box/internal/logging"
+	"github.com/curtbushko/zoom-to-box/internal/runreport"
+)
+
+// createReplayCommand creates the subcommand that re-runs failed operations from a run report
+func createReplayCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "replay --run-report <file>",
+		Short: "Re-run the failed downloads and uploads recorded in a run report",
+		Long: `Re-execute only the file operations that failed in a previous run, using the
+recording metadata stored in the run report instead of listing recordings again.
+
+Write a run report by passing --run-report to a normal run. After fixing the
+environmental issue (credentials, disk space, network), replay the report:
+
+  zoom-to-box --run-report run-report.json
+  zoom-to-box replay --run-report run-report.json
+
+Failed downloads are downloaded again (and uploaded if a destination is enabled),
+with their recording looked up in Zoom first for current download URLs; the
+stored metadata is used if the lookup fails. Meeting UUIDs that start with "/"
+or contain "//" are double-encoded in that lookup, as Zoom requires.
+Failed uploads are uploaded from the local file the failed run left behind.
+The report is rewritten with only the operations that still fail, so replay
+can be repeated until it is empty.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if runReportFile == "" {
+				return fmt.Errorf("--run-report is required")
+			}
+
+			configPath := "config.yaml"
+			if configFile != "" {
+				configPath = configFile
+			}
+
+			cfg, err := loadConfig(configPath)
+			if err != nil {
+				return fmt.Errorf("failed to load configuration: %w", err)
+			}
+
+			return runReplay(cmd, cfg, runReportFile)
+		},
+	}
+}
+
+// runReplay replays the failures in the run report at reportPath and rewrites it with the remaining failures
+func runReplay(cmd *cobra.Command, cfg *config.Config, reportPath string) error {
+	report, err := runreport.Load(reportPath)
+	if err != nil {
+		return err
+	}
+	if len(report.Failures) == 0 {
+		cmd.Printf("No failed operations in %s\n", reportPath)
+		return nil
+	}
+
+	if err := logging.InitializeLogging(cfg.Logging); err != nil {
+		return fmt.Errorf("failed to initialize logging: %w", err)
+	}
+	defer func() {
+		if logger := logging.GetDefaultLogger(); logger != nil {
+			logger.Close()
+		}
+	}()
+
+	if outputDir != "" {
+		cfg.Download.OutputDir = outputDir
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	userProcessor, cleanup, err := buildUserProcessor(ctx, cfg, processorOptions{})
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	cmd.Printf("Replaying %d failed operations from %s\n", len(report.Failures), reportPath)
+	summary := runreport.Replay(ctx, report, userProcessor)
+
+	for _, failure := range summary.Remaining.Failures {
+		cmd.Printf("- %s %s (%s): %s\n", failure.Operation, failure.RecordingFileID, failure.ZoomEmail, failure.Error)
+	}
+	cmd.Printf("\nReplay Summary:\n")
+	cmd.Printf("- Replayed: %d\n", summary.Attempted)
+	cmd.Printf("- Succeeded: %d\n", summary.Succeeded)
+	cmd.Printf("- Still failing: %d\n", len(summary.Remaining.Failures))
+
+	if dryRun {
+		return nil
+	}
+	if err := summary.Remaining.Save(reportPath); err != nil {
+		return err
+	}
+	if len(summary.Remaining.Failures) > 0 {
+		return fmt.Errorf("%d operations still failing, see %s", len(summary.Remaining.Failures), reportPath)
+	}
+	return nil
+}
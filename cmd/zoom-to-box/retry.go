@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/curtbushko/zoom-to-box/internal/box"
+	"github.com/curtbushko/zoom-to-box/internal/config"
+	"github.com/curtbushko/zoom-to-box/internal/download"
+	"github.com/curtbushko/zoom-to-box/internal/logging"
+)
+
+// createRetryUploadsCommand creates the subcommand that re-attempts failed Box uploads from the status file
+func createRetryUploadsCommand() *cobra.Command {
+	var (
+		statusFile string
+		maxRetries int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "retry-uploads [--status-file <file>]",
+		Short: "Retry the Box uploads that failed according to the download status file",
+		Long: `Scan the download status file for recordings whose Box upload failed
+(box.upload_error is set) and upload only those files again.
+
+Each failure increments box.upload_retries. An upload is skipped when it has
+already failed --max-retries times, or when its backoff has not elapsed yet:
+a file that failed n times waits n² minutes after box.last_upload_attempt.
+Successful retries clear the error and record the Box file ID. Relative file
+paths in the status file are resolved against the download output directory.
+The status file defaults to download.status_file (<output_dir>/.status.json),
+where every run records its failed uploads.
+
+Use --dry-run to list the uploads that would be retried.`,
+		Example: `  zoom-to-box retry-uploads
+  zoom-to-box retry-uploads --status-file downloads/status.json
+  zoom-to-box retry-uploads --status-file downloads/status.json --max-retries 5 --dry-run`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if maxRetries < 1 {
+				return fmt.Errorf("--max-retries must be at least 1")
+			}
+
+			configPath := "config.yaml"
+			if configFile != "" {
+				configPath = configFile
+			}
+
+			cfg, err := loadConfig(configPath)
+			if err != nil {
+				return fmt.Errorf("failed to load configuration: %w", err)
+			}
+			if !cfg.Box.Enabled {
+				return fmt.Errorf("box.enabled must be true to retry Box uploads")
+			}
+			if outputDir != "" {
+				cfg.Download.OutputDir = outputDir
+			}
+
+			if statusFile == "" {
+				statusFile = cfg.Download.StatusFilePath()
+			}
+			if _, err := os.Stat(statusFile); err != nil {
+				return fmt.Errorf("status file %s not found: %w", statusFile, err)
+			}
+
+			return runRetryUploads(cmd, cfg, statusFile, maxRetries)
+		},
+	}
+
+	cmd.Flags().StringVar(&statusFile, "status-file", "", "download status file recording the failed uploads (default: download.status_file)")
+	cmd.Flags().IntVar(&maxRetries, "max-retries", 3, "skip uploads that have already failed this many times")
+
+	return cmd
+}
+
+// runRetryUploads retries the failed uploads recorded in statusFile
+func runRetryUploads(cmd *cobra.Command, cfg *config.Config, statusFile string, maxRetries int) error {
+	if err := logging.InitializeLogging(cfg.Logging); err != nil {
+		return fmt.Errorf("failed to initialize logging: %w", err)
+	}
+	defer func() {
+		if logger := logging.GetDefaultLogger(); logger != nil {
+			logger.Close()
+		}
+	}()
+
+	statusTracker, err := download.NewStatusTracker(statusFile)
+	if err != nil {
+		return fmt.Errorf("failed to open status file: %w", err)
+	}
+	defer statusTracker.Close()
+
+	boxClient, err := buildBoxClient(cfg)
+	if err != nil {
+		return err
+	}
+
+	// Stop on SIGINT/SIGTERM and still save the status file through the deferred Close
+	ctx, stop := shutdownContext()
+	defer stop()
+
+	summary, err := box.NewUploadManager(boxClient).RetryFailedUploads(ctx, statusTracker, box.RetryOptions{
+		MaxRetries: maxRetries,
+		BaseDir:    cfg.Download.OutputDir,
+		DryRun:     dryRun,
+	})
+	if err != nil {
+		return fmt.Errorf("retrying uploads: %w", err)
+	}
+
+	if summary.TotalFiles == 0 {
+		cmd.Printf("No failed uploads in %s\n", statusFile)
+		return nil
+	}
+	if dryRun {
+		cmd.Printf("DRY RUN: %d of %d failed uploads would be retried, %d skipped (max retries or backoff)\n",
+			len(summary.Results), summary.TotalFiles, summary.SkippedCount)
+		return nil
+	}
+
+	cmd.Printf("Retried %d failed uploads: %d uploaded, %d failed, %d skipped (max retries or backoff)\n",
+		summary.TotalFiles, summary.SuccessCount, summary.FailureCount, summary.SkippedCount)
+	for _, uploadErr := range summary.Errors {
+		cmd.Printf("  %v\n", uploadErr)
+	}
+	if summary.FailureCount > 0 {
+		return fmt.Errorf("%d uploads still failing, see %s", summary.FailureCount, statusFile)
+	}
+	return nil
+}
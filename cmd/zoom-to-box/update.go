@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/curtbushko/zoom-to-box/internal/selfupdate"
+)
+
+// createSelfUpdateCommand creates the self-update subcommand
+func createSelfUpdateCommand() *cobra.Command {
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "self-update",
+		Short: "Replace this binary with the latest release",
+		Long: `Download the latest GitHub release binary for this platform and replace the
+running zoom-to-box with it.
+
+The release must carry checksums.txt (sha256sum output) and checksums.txt.sig,
+an Ed25519 signature of it. The signature is checked against the release
+signing key built into this binary and the downloaded binary against its
+checksum; nothing is installed if either check fails. Builds without a signing
+key cannot self-update.
+
+Dev builds and releases that are not older than the latest one are only
+replaced with --force. Use --dry-run to download and verify without installing.`,
+		Example: `  zoom-to-box self-update
+  zoom-to-box self-update --dry-run`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if releasePublicKey == "" {
+				return fmt.Errorf("this build has no release signing key; download the release manually")
+			}
+			publicKey, err := selfupdate.ParsePublicKey(releasePublicKey)
+			if err != nil {
+				return err
+			}
+			executable, err := selfupdate.Executable()
+			if err != nil {
+				return err
+			}
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Minute)
+			defer cancel()
+			client := releaseClient()
+			release, err := client.Latest(ctx)
+			if err != nil {
+				return err
+			}
+			newer, err := selfupdate.Newer(version, release.TagName)
+			if !force && err != nil {
+				return fmt.Errorf("cannot compare version %s with %s: %w; use --force to install it anyway", version, release.TagName, err)
+			}
+			if !force && !newer {
+				cmd.Printf("zoom-to-box %s is up to date (latest release: %s)\n", version, release.TagName)
+				return nil
+			}
+
+			cmd.Printf("Downloading %s %s for %s/%s\n", release.TagName, selfupdate.AssetName(runtime.GOOS, runtime.GOARCH), runtime.GOOS, runtime.GOARCH)
+			path, err := client.Download(ctx, release, publicKey, filepath.Dir(executable))
+			if err != nil {
+				return err
+			}
+			if dryRun {
+				os.Remove(path)
+				cmd.Printf("DRY RUN: %s verified; %s was not replaced\n", release.TagName, executable)
+				return nil
+			}
+			if err := selfupdate.Replace(executable, path); err != nil {
+				os.Remove(path)
+				return err
+			}
+			cmd.Printf("Updated %s from %s to %s\n", executable, version, release.TagName)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&force, "force", false, "install the latest release even if this build is a dev build or not older")
+
+	return cmd
+}
+
+// releaseClient returns a client for the GitHub releases of zoom-to-box
+func releaseClient() *selfupdate.Client {
+	return &selfupdate.Client{Token: os.Getenv("GITHUB_TOKEN")}
+}
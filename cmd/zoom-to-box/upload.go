@@ -0,0 +1,155 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/curtbushko/zoom-to-box/internal/config"
+	"github.com/curtbushko/zoom-to-box/internal/logging"
+	"github.com/curtbushko/zoom-to-box/internal/progress"
+	"github.com/curtbushko/zoom-to-box/internal/timefmt"
+	"github.com/curtbushko/zoom-to-box/internal/users"
+)
+
+// createUploadCommand creates the subcommand that uploads an existing download tree without Zoom
+func createUploadCommand() *cobra.Command {
+	var (
+		root   string
+		layout string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "upload [--path <dir>]",
+		Short: "Upload recordings that are already downloaded, without contacting Zoom",
+		Long: `Walk an existing local download tree and run only the upload and tracking
+phase: every file in a user's directory.layout folder is uploaded into the
+matching folder below the user's zoom folder and recorded in the tracking CSVs.
+
+Use it for recordings downloaded by an earlier run or another tool. The tree
+must follow directory.layout (default: <user>/YYYY/MM/DD); pass --layout for a
+tree written with a different layout. Files outside the layout folders of the
+known users are skipped. Files already in the destination with the same size
+are skipped, so an interrupted upload can simply be run again.
+
+Users come from --zoom-user/--box-user or the active users file.
+--delete-after-upload, --continue-on-error and --dry-run apply as for a normal run.`,
+		Example: `  zoom-to-box upload --path ./downloads
+  zoom-to-box upload --path /mnt/old-archive --layout "{{.User}}/{{.Year}}-{{.Month}}" --delete-after-upload
+  zoom-to-box upload --zoom-user jane@company.com --box-user jane@company.com --dry-run`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			configPath := "config.yaml"
+			if configFile != "" {
+				configPath = configFile
+			}
+
+			if downloadOnly {
+				return fmt.Errorf("--download-only cannot be used with the upload command")
+			}
+
+			cfg, err := loadConfig(configPath)
+			if err != nil {
+				return fmt.Errorf("failed to load configuration: %w", err)
+			}
+			if outputDir != "" {
+				cfg.Download.OutputDir = outputDir
+			}
+			if layout != "" {
+				cfg.Directory.Layout = layout
+			}
+			if root == "" {
+				root = cfg.Download.OutputDir
+			}
+			if info, err := os.Stat(root); err != nil || !info.IsDir() {
+				return fmt.Errorf("--path %s is not a directory", root)
+			}
+
+			var entries []users.UserEntry
+			switch {
+			case zoomUser != "" && boxUser != "":
+				entries = []users.UserEntry{{ZoomEmail: zoomUser, BoxEmail: boxUser}}
+			case zoomUser != "" || boxUser != "":
+				return fmt.Errorf("--zoom-user and --box-user must be used together")
+			default:
+				usersPath := cfg.ActiveUsers.File
+				if activeUsersFile != "" {
+					usersPath = activeUsersFile
+				}
+				if usersPath == "" {
+					return fmt.Errorf("no users to upload; use --zoom-user/--box-user, --active-users-file or active_users.file")
+				}
+				usersFile, err := users.LoadActiveUsersFile(usersPath)
+				if err != nil {
+					return err
+				}
+				entries = usersFile.Entries
+			}
+
+			return runUpload(cmd, cfg, root, entries)
+		},
+	}
+
+	cmd.Flags().StringVar(&root, "path", "", "local download tree to upload (default: download.output_dir)")
+	cmd.Flags().StringVar(&layout, "layout", "", "directory layout of the tree (overrides directory.layout)")
+
+	return cmd
+}
+
+// runUpload uploads the files below root for entries and prints a summary
+func runUpload(cmd *cobra.Command, cfg *config.Config, root string, entries []users.UserEntry) error {
+	if err := logging.InitializeLogging(cfg.Logging); err != nil {
+		return fmt.Errorf("failed to initialize logging: %w", err)
+	}
+	defer func() {
+		if logger := logging.GetDefaultLogger(); logger != nil {
+			logger.Close()
+		}
+	}()
+
+	ctx, stop := shutdownContext()
+	defer stop()
+
+	startedAt := timefmt.Now()
+	userProcessor, cleanup, err := buildUserProcessor(ctx, cfg, processorOptions{abortUploadsOnCancel: true})
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	summary, err := userProcessor.UploadLocal(ctx, root, entries)
+	if summary != nil {
+		writeReportFile(summary, startedAt)
+	}
+	if summary != nil && summary.Interrupted {
+		cmd.Printf("\nINTERRUPTED: run the same command again to resume; files already in the destination are skipped\n")
+		os.Exit(exitCodeInterrupted)
+	}
+	if err != nil {
+		return fmt.Errorf("upload failed: %w", err)
+	}
+
+	if dryRun {
+		cmd.Printf("\nDRY RUN: would upload %d files (%s) for %d users\n",
+			summary.TotalUploads, progress.FormatBytes(summary.TotalBytesPlanned), summary.TotalUsers)
+		return nil
+	}
+	cmd.Printf("\nUpload Summary:\n")
+	cmd.Printf("- Users: %d/%d\n", summary.ProcessedUsers, summary.TotalUsers)
+	cmd.Printf("- Uploaded: %d\n", summary.TotalUploads)
+	cmd.Printf("- Already in destination: %d\n", summary.TotalSkipped)
+	if deleteAfterUpload {
+		cmd.Printf("- Deleted locally: %d\n", summary.TotalDeleted)
+	}
+	cmd.Printf("- Failed: %d\n", summary.TotalErrors)
+	for _, result := range summary.UserResults {
+		for _, uploadErr := range result.Errors {
+			cmd.Printf("  %s: %v\n", result.ZoomEmail, uploadErr)
+		}
+	}
+
+	if summary.TotalErrors > 0 {
+		return fmt.Errorf("%d uploads failed", summary.TotalErrors)
+	}
+	return nil
+}
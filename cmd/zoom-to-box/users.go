@@ -0,0 +1,370 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/curtbushko/zoom-to-box/internal/download"
+	"github.com/curtbushko/zoom-to-box/internal/progress"
+	"github.com/curtbushko/zoom-to-box/internal/runreport"
+	"github.com/curtbushko/zoom-to-box/internal/users"
+	"github.com/curtbushko/zoom-to-box/internal/zoom"
+)
+
+// createUsersCommand creates the subcommand for managing the active users file
+func createUsersCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "users",
+		Short: "Manage the active users file",
+	}
+
+	cmd.AddCommand(createUsersSyncCommand())
+	cmd.AddCommand(createUsersUnmanagedCommand())
+	cmd.AddCommand(createUsersStatusCommand())
+
+	return cmd
+}
+
+// createUsersSyncCommand creates the subcommand that builds the active users file from Zoom
+func createUsersSyncCommand() *cobra.Command {
+	var (
+		status string
+		roleID string
+		groups []string
+		prune  bool
+		output string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Generate or update the active users file from the Zoom users API",
+		Long: `Query the Zoom users API and write the users to the 3-column active users file
+(zoom_email,box_email,upload_complete).
+
+Users already in the file keep their Box email, upload_complete flag and
+destination, so sync can be re-run safely. New users are added with their Zoom email as the
+Box email. Use --prune to remove users that Zoom no longer returns, and
+--dry-run to see the changes without writing the file.
+
+Requires the user:read:admin scope on the Zoom app.`,
+		Example: `  zoom-to-box users sync
+  zoom-to-box users sync --group abc123 --group def456 --prune
+  zoom-to-box users sync --status inactive --output inactive_users.txt --dry-run`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			switch status {
+			case zoom.UserStatusActive, zoom.UserStatusInactive, zoom.UserStatusPending:
+			default:
+				return fmt.Errorf("invalid --status %q: must be active, inactive or pending", status)
+			}
+
+			configPath := "config.yaml"
+			if configFile != "" {
+				configPath = configFile
+			}
+
+			cfg, err := loadConfig(configPath)
+			if err != nil {
+				return fmt.Errorf("failed to load configuration: %w", err)
+			}
+
+			if output == "" {
+				output = cfg.ActiveUsers.File
+				if activeUsersFile != "" {
+					output = activeUsersFile
+				}
+			}
+			if output == "" {
+				return fmt.Errorf("no active users file configured; use --output or active_users.file")
+			}
+
+			zoomUsers, err := buildZoomClient(cfg).GetAllUsers(cmd.Context(), zoom.ListUsersParams{
+				Status: status,
+				RoleID: roleID,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to list Zoom users: %w", err)
+			}
+
+			emails := filterZoomUserEmails(zoomUsers, groups)
+			result, err := users.SyncActiveUsersFile(output, emails, users.SyncOptions{Prune: prune, DryRun: dryRun})
+			if err != nil {
+				return err
+			}
+
+			for _, email := range result.Added {
+				cmd.Printf("+ %s\n", email)
+			}
+			for _, email := range result.Removed {
+				cmd.Printf("- %s\n", email)
+			}
+			if dryRun {
+				cmd.Printf("DRY RUN: %s was not modified\n", output)
+			}
+			cmd.Printf("Synced %d Zoom users to %s: %d added, %d removed, %d kept\n",
+				len(emails), output, len(result.Added), len(result.Removed), result.Kept)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&status, "status", zoom.UserStatusActive, "Zoom user status to include (active, inactive, pending)")
+	cmd.Flags().StringVar(&roleID, "role-id", "", "only include users with this Zoom role ID")
+	cmd.Flags().StringSliceVar(&groups, "group", nil, "only include users in this Zoom group ID (repeatable)")
+	cmd.Flags().BoolVar(&prune, "prune", false, "remove users that are no longer returned by Zoom")
+	cmd.Flags().StringVar(&output, "output", "", "active users file to write (default: active_users.file)")
+
+	return cmd
+}
+
+// createUsersUnmanagedCommand creates the subcommand that reports Zoom users with cloud
+// recordings who are missing from the active users file
+func createUsersUnmanagedCommand() *cobra.Command {
+	var (
+		statuses []string
+		output   string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "unmanaged",
+		Short: "Report Zoom users with recordings who are not in the active users file",
+		Long: `List the Zoom users in the account, check the cloud recordings of every user
+missing from the active users file and write an "unmanaged hosts" CSV report
+(zoom_email,status,recordings,total_size_bytes,oldest_recording,newest_recording)
+with the oldest recordings first.
+
+Nothing is downloaded or changed: the report lets admins add people to the
+migration roster before Zoom's retention policy deletes their recordings.
+Use --from/--to to limit the recordings checked.
+
+Requires the user:read:admin and recording:read:admin scopes on the Zoom app.`,
+		Example: `  zoom-to-box users unmanaged
+  zoom-to-box users unmanaged --status active --status inactive --from 180d
+  zoom-to-box users unmanaged --output /reports/unmanaged-hosts.csv`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			for _, status := range statuses {
+				switch status {
+				case zoom.UserStatusActive, zoom.UserStatusInactive, zoom.UserStatusPending:
+				default:
+					return fmt.Errorf("invalid --status %q: must be active, inactive or pending", status)
+				}
+			}
+
+			configPath := "config.yaml"
+			if configFile != "" {
+				configPath = configFile
+			}
+
+			cfg, err := loadConfig(configPath)
+			if err != nil {
+				return fmt.Errorf("failed to load configuration: %w", err)
+			}
+
+			usersPath := cfg.ActiveUsers.File
+			if activeUsersFile != "" {
+				usersPath = activeUsersFile
+			}
+			if usersPath == "" {
+				return fmt.Errorf("no active users file configured; use --active-users-file or active_users.file")
+			}
+			usersFile, err := users.LoadActiveUsersFile(usersPath)
+			if err != nil {
+				return err
+			}
+
+			if fromDate != "" {
+				cfg.Download.FromDate = fromDate
+			}
+			if toDate != "" {
+				cfg.Download.ToDate = toDate
+			}
+			from, to, err := cfg.Download.DateRange(time.Now())
+			if err != nil {
+				return fmt.Errorf("invalid date range: %w", err)
+			}
+
+			if output == "" {
+				output = filepath.Join(cfg.Download.OutputDir, "unmanaged-hosts.csv")
+			}
+
+			ctx := cmd.Context()
+			zoomClient := buildZoomClient(cfg)
+
+			userStatus := make(map[string]string)
+			var zoomEmails []string
+			for _, status := range statuses {
+				zoomUsers, err := zoomClient.GetAllUsers(ctx, zoom.ListUsersParams{Status: status})
+				if err != nil {
+					return fmt.Errorf("failed to list %s Zoom users: %w", status, err)
+				}
+				for _, email := range filterZoomUserEmails(zoomUsers, nil) {
+					userStatus[strings.ToLower(email)] = status
+					zoomEmails = append(zoomEmails, email)
+				}
+			}
+
+			missing := usersFile.MissingEmails(zoomEmails)
+			var hosts []users.UnmanagedHost
+			for _, email := range missing {
+				recordings, err := zoomClient.GetAllUserRecordings(ctx, email, zoom.ListRecordingsParams{From: from, To: to, PageSize: cfg.Zoom.PageSize})
+				if err != nil {
+					return fmt.Errorf("failed to list recordings for %s: %w", email, err)
+				}
+				if len(recordings) == 0 {
+					continue
+				}
+				host := users.UnmanagedHost{Email: email, Status: userStatus[email]}
+				for _, recording := range recordings {
+					host.AddRecording(recording.StartTime, recording.TotalSize)
+				}
+				hosts = append(hosts, host)
+			}
+
+			if err := os.MkdirAll(filepath.Dir(output), 0755); err != nil {
+				return fmt.Errorf("failed to create report directory: %w", err)
+			}
+			file, err := os.Create(output)
+			if err != nil {
+				return fmt.Errorf("failed to create unmanaged hosts report: %w", err)
+			}
+			if err := users.WriteUnmanagedHostsCSV(file, hosts); err != nil {
+				file.Close()
+				return err
+			}
+			if err := file.Close(); err != nil {
+				return fmt.Errorf("failed to write unmanaged hosts report: %w", err)
+			}
+
+			for _, host := range hosts {
+				cmd.Printf("! %s (%s): %d recordings, %s\n", host.Email, host.Status, host.Recordings, progress.FormatBytes(host.TotalSize))
+			}
+			cmd.Printf("Checked %d Zoom users missing from %s: %d have recordings, report written to %s\n",
+				len(missing), usersPath, len(hosts), output)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&statuses, "status", []string{zoom.UserStatusActive, zoom.UserStatusInactive}, "Zoom user statuses to check (repeatable: active, inactive, pending)")
+	cmd.Flags().StringVar(&output, "output", "", "CSV report to write (default: <output_dir>/unmanaged-hosts.csv)")
+
+	return cmd
+}
+
+// createUsersStatusCommand creates the subcommand that shows each active user's progress
+func createUsersStatusCommand() *cobra.Command {
+	var statusFile string
+
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show per-user completion and failure reasons from earlier runs",
+		Long: `Read the active users file and the download status file and print a table
+with, for every user: upload_complete, the recordings found, the files
+downloaded and uploaded, the last error and the time of the last run.
+
+The status file defaults to download.status_file (<output_dir>/.status.json).
+Pass --run-report to also include the failures recorded by a run with
+--run-report, so users whose downloads failed show why.
+
+Nothing is contacted or changed: the table only reflects earlier runs.`,
+		Example: `  zoom-to-box users status
+  zoom-to-box users status --run-report failures.json
+  zoom-to-box users status --status-file downloads/.status.json --active-users-file active_users.txt`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			configPath := "config.yaml"
+			if configFile != "" {
+				configPath = configFile
+			}
+
+			cfg, err := loadConfig(configPath)
+			if err != nil {
+				return fmt.Errorf("failed to load configuration: %w", err)
+			}
+			if outputDir != "" {
+				cfg.Download.OutputDir = outputDir
+			}
+
+			usersPath := cfg.ActiveUsers.File
+			if activeUsersFile != "" {
+				usersPath = activeUsersFile
+			}
+			if usersPath == "" {
+				return fmt.Errorf("no active users file configured; use --active-users-file or active_users.file")
+			}
+			usersFile, err := users.LoadActiveUsersFile(usersPath)
+			if err != nil {
+				return err
+			}
+
+			if statusFile == "" {
+				statusFile = cfg.Download.StatusFilePath()
+			}
+			var downloads map[string]download.DownloadEntry
+			if _, err := os.Stat(statusFile); err == nil {
+				tracker, err := download.NewStatusTracker(statusFile)
+				if err != nil {
+					return fmt.Errorf("failed to read status file %s: %w", statusFile, err)
+				}
+				downloads = tracker.GetAllDownloads()
+			} else {
+				fmt.Fprintf(cmd.OutOrStdout(), "No status file at %s; no user has been processed yet\n", statusFile)
+			}
+
+			var failures []runreport.Failure
+			if runReportFile != "" {
+				report, err := runreport.Load(runReportFile)
+				if err != nil {
+					return err
+				}
+				failures = report.Failures
+			}
+
+			statuses := usersFile.Statuses(downloads, failures)
+			if err := users.WriteUserStatusTable(cmd.OutOrStdout(), statuses); err != nil {
+				return err
+			}
+
+			complete, failed := 0, 0
+			for _, status := range statuses {
+				if status.UploadComplete {
+					complete++
+				}
+				if status.LastError != "" {
+					failed++
+				}
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "\n%d users: %d complete, %d with errors\n", len(statuses), complete, failed)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&statusFile, "status-file", "", "download status file to read (default: download.status_file)")
+
+	return cmd
+}
+
+// filterZoomUserEmails returns the emails of users in any of groups (all users if groups is empty)
+func filterZoomUserEmails(zoomUsers []zoom.User, groups []string) []string {
+	emails := make([]string, 0, len(zoomUsers))
+	for _, user := range zoomUsers {
+		if user.Email == "" {
+			continue
+		}
+		if len(groups) > 0 {
+			inGroup := false
+			for _, group := range groups {
+				if user.InGroup(group) {
+					inGroup = true
+					break
+				}
+			}
+			if !inGroup {
+				continue
+			}
+		}
+		emails = append(emails, user.Email)
+	}
+	return emails
+}
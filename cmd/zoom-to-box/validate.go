@@ -0,0 +1,227 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/curtbushko/zoom-to-box/internal/config"
+	"github.com/curtbushko/zoom-to-box/internal/processor"
+)
+
+// createConfigValidateCommand creates the subcommand that checks a configuration before a run
+func createConfigValidateCommand() *cobra.Command {
+	var live bool
+
+	cmd := &cobra.Command{
+		Use:   "validate [--live]",
+		Short: "Check the configuration for errors before a run",
+		Long: `Load the configuration (with --profile and environment overrides) and check it
+the way a run would, without downloading anything:
+
+  - required credentials and value ranges
+  - filters and the Box metadata template
+  - credential, key and token files exist
+  - the output, log, report and temporary directories are writable
+
+With --live the Zoom credentials (and Box credentials when box.enabled) are also
+checked by requesting an access token. Every problem is listed, and the command
+exits non-zero if there is any.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			configPath := "config.yaml"
+			if configFile != "" {
+				configPath = configFile
+			}
+			return runConfigValidate(cmd, configPath, live)
+		},
+	}
+
+	cmd.Flags().BoolVar(&live, "live", false, "also check the Zoom and Box credentials against their APIs")
+	return cmd
+}
+
+// runConfigValidate checks the configuration at configPath and lists every problem found
+func runConfigValidate(cmd *cobra.Command, configPath string, live bool) error {
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "Checking %s\n", configPath)
+
+	cfg, err := loadConfig(configPath)
+	if err != nil {
+		fmt.Fprintf(out, "  FAIL  %v\n", err)
+		return fmt.Errorf("configuration %s is invalid", configPath)
+	}
+	fmt.Fprintln(out, "  ok    configuration loaded and validated")
+	if cfg.Network.InsecureSkipVerify {
+		fmt.Fprintln(out, "  WARN  network.insecure_skip_verify is enabled; set network.ca_cert_file to trust a TLS-intercepting proxy instead")
+	}
+
+	problems := 0
+	report := func(check string, err error) {
+		if err != nil {
+			problems++
+			fmt.Fprintf(out, "  FAIL  %s: %v\n", check, err)
+			return
+		}
+		fmt.Fprintf(out, "  ok    %s\n", check)
+	}
+
+	for _, check := range configChecks(cfg) {
+		report(check.name, check.run())
+	}
+
+	if live {
+		ctx, stop := shutdownContext()
+		defer stop()
+
+		_, err := buildZoomClient(cfg).GetOAuthAccessToken(ctx)
+		report("zoom credentials (live)", err)
+		if cfg.Box.Enabled {
+			report("box credentials (live)", checkBoxCredentials(cfg))
+		}
+	}
+
+	if problems > 0 {
+		return fmt.Errorf("configuration %s has %d problem(s)", configPath, problems)
+	}
+	fmt.Fprintln(out, "Configuration is valid")
+	return nil
+}
+
+// configCheck is a single offline check run by 'config validate'
+type configCheck struct {
+	name string
+	run  func() error
+}
+
+// configChecks returns the checks for the settings a run only resolves once it starts
+func configChecks(cfg *config.Config) []configCheck {
+	checks := []configCheck{
+		{"filters", func() error {
+			_, err := processor.NewRecordingFilter(cfg.Filters.MinDurationMinutes, cfg.Filters.TopicRegex, cfg.Filters.ExcludeTopicRegex, cfg.Filters.MeetingTypes)
+			return err
+		}},
+		{"download.output_dir " + cfg.Download.OutputDir + " is writable", func() error {
+			return checkWritableDir(cfg.Download.OutputDir)
+		}},
+	}
+
+	if cfg.Logging.File != "" {
+		checks = append(checks, configCheck{"logging.file " + cfg.Logging.File + " is writable", func() error {
+			return checkWritableDir(filepath.Dir(cfg.Logging.File))
+		}})
+	}
+	if cfg.ActiveUsers.File != "" {
+		checks = append(checks, configCheck{"active_users.file " + cfg.ActiveUsers.File + " is writable", func() error {
+			return checkWritableDir(filepath.Dir(cfg.ActiveUsers.File))
+		}})
+	}
+	if cfg.Daemon.ReportDir != "" {
+		checks = append(checks, configCheck{"daemon.report_dir " + cfg.Daemon.ReportDir + " is writable", func() error {
+			return checkWritableDir(cfg.Daemon.ReportDir)
+		}})
+	}
+	if cfg.TokenCache.Enabled {
+		checks = append(checks, configCheck{"token_cache.file " + cfg.TokenCache.File + " is writable", func() error {
+			return checkWritableDir(filepath.Dir(cfg.TokenCache.File))
+		}})
+	}
+
+	if cfg.Box.Enabled {
+		checks = append(checks, configCheck{"box credentials are set", func() error {
+			if cfg.Box.ClientID == "" || cfg.Box.ClientSecret == "" {
+				return fmt.Errorf("box.client_id and box.client_secret are required when Box is enabled (or set BOX_CLIENT_ID and BOX_CLIENT_SECRET)")
+			}
+			return nil
+		}})
+		if cfg.Box.AuthMode == "user" {
+			checks = append(checks, configCheck{"box.token_file " + cfg.Box.TokenFile + " exists", func() error {
+				if err := checkReadableFile(cfg.Box.TokenFile); err != nil {
+					return fmt.Errorf("%w; run 'zoom-to-box box login' first", err)
+				}
+				return nil
+			}})
+		}
+		checks = append(checks, configCheck{"box.metadata_template", func() error {
+			template := cfg.Box.MetadataTemplate
+			_, err := processor.NewMetadataTemplate(template.Scope, template.TemplateKey, template.Fields)
+			return err
+		}})
+	}
+	if cfg.GoogleDrive.Enabled {
+		checks = append(checks, configCheck{"google_drive.credentials_file " + cfg.GoogleDrive.CredentialsFile + " exists", func() error {
+			return checkReadableFile(cfg.GoogleDrive.CredentialsFile)
+		}})
+	}
+	if cfg.SFTP.Enabled {
+		checks = append(checks, configCheck{"sftp.private_key_file " + cfg.SFTP.PrivateKeyFile + " exists", func() error {
+			return checkReadableFile(cfg.SFTP.PrivateKeyFile)
+		}})
+	}
+
+	if cfg.Encryption.Enabled {
+		if cfg.Encryption.KeyFile != "" {
+			checks = append(checks, configCheck{"encryption.key_file " + cfg.Encryption.KeyFile + " exists", func() error {
+				return checkReadableFile(cfg.Encryption.KeyFile)
+			}})
+		}
+		if cfg.Encryption.TempDir != "" {
+			checks = append(checks, configCheck{"encryption.temp_dir " + cfg.Encryption.TempDir + " is writable", func() error {
+				return checkWritableDir(cfg.Encryption.TempDir)
+			}})
+		}
+	}
+	return checks
+}
+
+// checkWritableDir checks that files can be created in dir
+// A missing directory is checked at its nearest existing parent, where a run would create it.
+func checkWritableDir(dir string) error {
+	for {
+		info, err := os.Stat(dir)
+		if err == nil {
+			if !info.IsDir() {
+				return fmt.Errorf("%s is not a directory", dir)
+			}
+			break
+		}
+		if !os.IsNotExist(err) {
+			return err
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return err
+		}
+		dir = parent
+	}
+
+	probe, err := os.CreateTemp(dir, ".zoom-to-box-validate-*")
+	if err != nil {
+		return fmt.Errorf("cannot create files in %s: %w", dir, err)
+	}
+	probe.Close()
+	return os.Remove(probe.Name())
+}
+
+// checkReadableFile checks that path is an existing, readable file
+func checkReadableFile(path string) error {
+	if path == "" {
+		return fmt.Errorf("no file configured")
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+// checkBoxCredentials requests a Box access token by looking up the authenticated user
+func checkBoxCredentials(cfg *config.Config) error {
+	boxClient, err := buildBoxClient(cfg)
+	if err != nil {
+		return err
+	}
+	_, err = boxClient.GetCurrentUser()
+	return err
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/curtbushko/zoom-to-box/internal/directory"
+	"github.com/curtbushko/zoom-to-box/internal/users"
+	"github.com/curtbushko/zoom-to-box/internal/verify"
+)
+
+// createVerifyCommand creates the subcommand that audits local downloads against Box
+func createVerifyCommand() *cobra.Command {
+	var (
+		source    string
+		checksums bool
+		output    string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Audit the local downloads and tracked uploads against Box",
+		Long: `Check every downloaded file and every upload recorded in the tracking CSVs
+against the user's zoom folder in Box and write a discrepancy report
+(kind,zoom_email,box_email,local_path,box_path,local_size_bytes,box_size_bytes,detail).
+
+Discrepancy kinds:
+  missing_in_box     No file with the name in the expected Box folder
+  size_mismatch      Box has the file with a different size
+  checksum_mismatch  Box has the file with a different SHA1 (with --checksums)
+  orphaned_local     Local file outside the directory.layout folders of the known users
+  lookup_failed      Box could not be queried for the file
+
+Local files are looked up at the folder directory.layout gives them. Tracked
+uploads whose local copy is gone (e.g. --delete-after-upload) are searched for
+anywhere in the user's zoom folder, since the CSVs do not record folders.
+Nothing is uploaded or changed. Users come from --zoom-user/--box-user or the
+active users file. The command fails when discrepancies are found.`,
+		Example: `  zoom-to-box verify
+  zoom-to-box verify --checksums --output /reports/verify.csv
+  zoom-to-box verify --source csv --zoom-user jane@company.com --box-user jane@company.com`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if source != "local" && source != "csv" && source != "all" {
+				return fmt.Errorf("invalid --source %q: must be local, csv or all", source)
+			}
+
+			configPath := "config.yaml"
+			if configFile != "" {
+				configPath = configFile
+			}
+
+			cfg, err := loadConfig(configPath)
+			if err != nil {
+				return fmt.Errorf("failed to load configuration: %w", err)
+			}
+			if !cfg.Box.Enabled {
+				return fmt.Errorf("box.enabled must be true to verify uploads")
+			}
+			if outputDir != "" {
+				cfg.Download.OutputDir = outputDir
+			}
+			if output == "" {
+				output = filepath.Join(cfg.Download.OutputDir, "verify-report.csv")
+			}
+
+			var verifyUsers []verify.User
+			switch {
+			case zoomUser != "" && boxUser != "":
+				verifyUsers = []verify.User{{ZoomEmail: zoomUser, BoxEmail: boxUser}}
+			case zoomUser != "" || boxUser != "":
+				return fmt.Errorf("--zoom-user and --box-user must be used together")
+			default:
+				usersPath := cfg.ActiveUsers.File
+				if activeUsersFile != "" {
+					usersPath = activeUsersFile
+				}
+				if usersPath == "" {
+					return fmt.Errorf("no users to verify; use --zoom-user/--box-user, --active-users-file or active_users.file")
+				}
+				usersFile, err := users.LoadActiveUsersFile(usersPath)
+				if err != nil {
+					return err
+				}
+				for _, entry := range usersFile.Entries {
+					verifyUsers = append(verifyUsers, verify.User{ZoomEmail: entry.ZoomEmail, BoxEmail: entry.BoxEmail})
+				}
+			}
+
+			layout, err := directory.NewLayout(cfg.Directory.Layout)
+			if err != nil {
+				return err
+			}
+			boxClient, err := buildBoxClient(cfg)
+			if err != nil {
+				return err
+			}
+
+			ctx, stop := shutdownContext()
+			defer stop()
+
+			encryption, err := loadEncryptionCipher(ctx, cfg)
+			if err != nil {
+				return err
+			}
+
+			report, err := verify.NewVerifier(boxClient, verify.Options{
+				OutputDir: cfg.Download.OutputDir,
+				Layout:    layout,
+				Users:     verifyUsers,
+				Local:     source != "csv",
+				Tracked:   source != "local",
+				Checksums: checksums,
+				Cipher:    encryption,
+			}).Run(ctx)
+			if err != nil {
+				return fmt.Errorf("verification failed: %w", err)
+			}
+
+			if err := os.MkdirAll(filepath.Dir(output), 0755); err != nil {
+				return fmt.Errorf("failed to create report directory: %w", err)
+			}
+			file, err := os.Create(output)
+			if err != nil {
+				return fmt.Errorf("failed to create verify report: %w", err)
+			}
+			if err := verify.WriteCSV(file, report.Discrepancies); err != nil {
+				file.Close()
+				return err
+			}
+			if err := file.Close(); err != nil {
+				return fmt.Errorf("failed to write verify report: %w", err)
+			}
+
+			counts := make(map[verify.Kind]int)
+			for _, d := range report.Discrepancies {
+				counts[d.Kind]++
+				location := d.LocalPath
+				if location == "" {
+					location = d.BoxPath
+				}
+				cmd.Printf("! %s: %s\n", d.Kind, location)
+			}
+			cmd.Printf("Checked %d local files and %d tracked uploads: %d missing in Box, %d size mismatches, %d checksum mismatches, %d orphaned, %d lookup failures\n",
+				report.LocalFiles, report.TrackedFiles, counts[verify.KindMissingInBox], counts[verify.KindSizeMismatch],
+				counts[verify.KindChecksumMismatch], counts[verify.KindOrphanedLocal], counts[verify.KindLookupFailed])
+			cmd.Printf("Report written to %s\n", output)
+
+			if len(report.Discrepancies) > 0 {
+				return fmt.Errorf("%d discrepancies found, see %s", len(report.Discrepancies), output)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&source, "source", "all", "what to verify: local (download tree), csv (tracking CSVs) or all")
+	cmd.Flags().BoolVar(&checksums, "checksums", false, "compare SHA1 checksums of local files with Box, not just sizes")
+	cmd.Flags().StringVar(&output, "output", "", "CSV report to write (default: <output_dir>/verify-report.csv)")
+
+	return cmd
+}
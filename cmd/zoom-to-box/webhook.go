@@ -0,0 +1,239 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/curtbushko/zoom-to-box/internal/config"
+	"github.com/curtbushko/zoom-to-box/internal/control"
+	"github.com/curtbushko/zoom-to-box/internal/logging"
+	"github.com/curtbushko/zoom-to-box/internal/users"
+	"github.com/curtbushko/zoom-to-box/internal/webhook"
+	"github.com/curtbushko/zoom-to-box/internal/zoom"
+)
+
+// createServeCommand creates the webhook listener subcommand
+func createServeCommand() *cobra.Command {
+	var listenAddress string
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Process recordings as Zoom reports them via webhooks",
+		Long: `Run an HTTP listener for Zoom webhook events. Requests are verified with the
+webhook secret token, and each recording.completed event is queued and downloaded
+(and uploaded, if a destination is enabled) as soon as Zoom finishes processing it.
+
+Use this alongside, or instead of, periodic batch runs.
+
+With control.enabled, the same listener serves an authenticated control API under
+/api/v1/ to enqueue users, query per-user progress, pause and resume processing, and
+stream progress events. The webhook endpoint is optional when the control API is enabled.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			configPath := "config.yaml"
+			if configFile != "" {
+				configPath = configFile
+			}
+
+			cfg, err := loadConfig(configPath)
+			if err != nil {
+				return fmt.Errorf("failed to load configuration: %w", err)
+			}
+			if listenAddress != "" {
+				cfg.Webhook.ListenAddress = listenAddress
+			}
+
+			err = runWebhookServer(cmd, cfg)
+			if errors.Is(err, errRunStoppedByMonitor) {
+				cmd.Printf("%v; restart to continue\n", err)
+				os.Exit(exitCodeTimeBoxed)
+			}
+			return err
+		},
+	}
+
+	cmd.Flags().StringVar(&listenAddress, "listen", "", "address to listen on (overrides webhook.listen_address)")
+
+	return cmd
+}
+
+// runWebhookServer serves Zoom webhook events until interrupted
+// On SIGINT/SIGTERM the listener stops accepting events and recordings already queued
+// are processed before exiting; a second signal exits immediately.
+func runWebhookServer(cmd *cobra.Command, cfg *config.Config) error {
+	if cfg.Webhook.SecretToken == "" && !cfg.Control.Enabled {
+		return fmt.Errorf("webhook.secret_token (or ZOOM_WEBHOOK_SECRET_TOKEN) is required to verify Zoom webhook requests")
+	}
+
+	if err := logging.InitializeLogging(cfg.Logging); err != nil {
+		return fmt.Errorf("failed to initialize logging: %w", err)
+	}
+	defer func() {
+		if logger := logging.GetDefaultLogger(); logger != nil {
+			logger.Close()
+		}
+	}()
+
+	stopTracing, err := startTracing(cfg)
+	if err != nil {
+		return err
+	}
+	defer stopTracing()
+
+	// Apply command-line overrides to config
+	if outputDir != "" {
+		cfg.Download.OutputDir = outputDir
+	}
+	if activeUsersFile != "" {
+		cfg.ActiveUsers.File = activeUsersFile
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	userProcessor, cleanup, err := buildUserProcessor(ctx, cfg, processorOptions{})
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	// With monitor.restart_on_limit, a limit shuts the listener down like a signal
+	limitStop, limitReason := startWatchdog(ctx, cfg)
+
+	// Webhook recordings and users enqueued through the control API share the processor;
+	// the controller runs one at a time and holds new work while paused
+	controller := control.NewController(userProcessor, cfg.Webhook.QueueSize)
+
+	queue := webhook.NewQueue(cfg.Webhook.QueueSize, func(ctx context.Context, job webhook.RecordingJob) error {
+		release, err := controller.Acquire(ctx)
+		if err != nil {
+			return err
+		}
+		defer release()
+
+		boxEmail, ok, err := resolveWebhookUser(cfg.ActiveUsers.File, job.HostEmail)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			logging.Info("Skipping recording %s: host %s is not in the active users file", job.Recording.UUID, job.HostEmail)
+			return nil
+		}
+
+		result, err := userProcessor.ProcessRecordings(ctx, job.HostEmail, boxEmail, []*zoom.Recording{job.Recording})
+		if err != nil {
+			return err
+		}
+		logging.Info("Processed recording %s for %s: %d downloaded, %d uploaded, %d skipped, %d errors",
+			job.Recording.UUID, job.HostEmail, result.DownloadedCount, result.UploadedCount, result.SkippedCount, result.ErrorCount)
+		return nil
+	})
+
+	mux := http.NewServeMux()
+	if cfg.Webhook.SecretToken != "" {
+		mux.Handle(cfg.Webhook.Path, webhook.NewHandler(cfg.Webhook.SecretToken, queue.Enqueue))
+	}
+	if cfg.Control.Enabled {
+		mux.Handle(control.APIPrefix, control.NewHandler(controller, cfg.Control.Token))
+	}
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, "ok (%d queued)\n", queue.Len()+controller.Status().Queued)
+	})
+
+	server := &http.Server{
+		Addr:              cfg.Webhook.ListenAddress,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	// Recordings keep being processed after the signal until the queue is drained
+	var workers sync.WaitGroup
+	workers.Add(2)
+	go func() {
+		defer workers.Done()
+		queue.Run(context.Background())
+	}()
+	go func() {
+		defer workers.Done()
+		controller.Run(context.Background())
+	}()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- server.ListenAndServe()
+	}()
+
+	if cfg.Webhook.SecretToken != "" {
+		cmd.Printf("Listening for Zoom webhook events on %s%s\n", cfg.Webhook.ListenAddress, cfg.Webhook.Path)
+		logging.Info("Webhook listener started on %s%s", cfg.Webhook.ListenAddress, cfg.Webhook.Path)
+	}
+	if cfg.Control.Enabled {
+		cmd.Printf("Control API available on %s%s\n", cfg.Webhook.ListenAddress, control.APIPrefix)
+		logging.Info("Control API started on %s%s", cfg.Webhook.ListenAddress, control.APIPrefix)
+	}
+
+	// Queued work is drained on shutdown, so a paused controller is resumed first
+	drain := func() {
+		controller.Resume()
+		queue.Close()
+		controller.Close()
+		workers.Wait()
+	}
+
+	select {
+	case err := <-serverErr:
+		drain()
+		return fmt.Errorf("webhook listener failed: %w", err)
+	case <-ctx.Done():
+	case <-limitStop:
+	}
+
+	// Restore default signal handling so a second signal exits immediately
+	stop()
+	cmd.Printf("Shutting down: finishing %d queued recording(s) and %d queued user(s)\n", queue.Len(), controller.Status().Queued)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		logging.Warn("Webhook listener did not shut down cleanly: %v", err)
+	}
+
+	drain()
+	if reason := limitReason(); reason != "" {
+		return fmt.Errorf("%w (%s)", errRunStoppedByMonitor, reason)
+	}
+	return nil
+}
+
+// resolveWebhookUser maps a recording host to a Box email using the active users file
+// The file is re-read for every recording so edits take effect without a restart. When the
+// file does not exist every host is processed with their Zoom email as the Box email.
+func resolveWebhookUser(usersFilePath, hostEmail string) (string, bool, error) {
+	if usersFilePath == "" {
+		return hostEmail, true, nil
+	}
+	if _, err := os.Stat(usersFilePath); os.IsNotExist(err) {
+		return hostEmail, true, nil
+	}
+
+	usersFile, err := users.LoadActiveUsersFile(usersFilePath)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to load active users file: %w", err)
+	}
+	for _, entry := range usersFile.Entries {
+		if strings.EqualFold(entry.ZoomEmail, hostEmail) {
+			return entry.BoxEmail, true, nil
+		}
+	}
+	return "", false, nil
+}
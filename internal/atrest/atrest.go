@@ -0,0 +1,341 @@
+// Package atrest encrypts downloaded recordings on disk
+// Files are encrypted in place with AES-256-GCM in 1 MiB chunks, so recordings of any size
+// can be encrypted and decrypted as streams. Encrypted files keep their names; a header
+// marks them so readers can tell them apart from plaintext files and decrypt transparently.
+//
+// File format: an 8-byte magic, the chunk size (uint32), the plaintext size (uint64) and an
+// 8-byte random nonce prefix, followed by the sealed chunks. Each chunk's nonce is the prefix
+// and the chunk index, and the header is authenticated with every chunk, so reordered,
+// truncated or extended files fail to decrypt.
+package atrest
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// KeySize is the AES-256 key length in bytes
+const KeySize = 32
+
+// chunkSize is the plaintext size of every chunk but the last
+const chunkSize = 1 << 20
+
+var magic = []byte("Z2BENC01")
+
+// headerSize is magic + chunk size + plaintext size + nonce prefix
+const headerSize = 8 + 4 + 8 + 8
+
+// ErrNoKey is returned when an encrypted file is opened without a key
+var ErrNoKey = errors.New("file is encrypted at rest but no encryption key is configured")
+
+// Cipher encrypts and decrypts files with one key
+type Cipher struct {
+	aead cipher.AEAD
+}
+
+// NewCipher creates a Cipher from a 32-byte key
+func NewCipher(key []byte) (*Cipher, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("encryption key must be %d bytes, got %d", KeySize, len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCM: %w", err)
+	}
+	return &Cipher{aead: aead}, nil
+}
+
+// ParseKey decodes a 32-byte key written as 64 hex characters or as base64
+func ParseKey(s string) ([]byte, error) {
+	s = strings.TrimSpace(s)
+	if len(s) == hex.EncodedLen(KeySize) {
+		if key, err := hex.DecodeString(s); err == nil {
+			return key, nil
+		}
+	}
+	key, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("encryption key must be %d bytes as hex or base64", KeySize)
+	}
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("encryption key must be %d bytes, got %d", KeySize, len(key))
+	}
+	return key, nil
+}
+
+// header is the decoded file header
+type header struct {
+	raw         []byte // Authenticated with every chunk
+	chunkSize   int
+	size        int64 // Plaintext size
+	noncePrefix []byte
+}
+
+// readHeader reads the header from r; ok is false when r does not start with the magic
+func readHeader(r io.Reader) (h *header, ok bool, err error) {
+	raw := make([]byte, headerSize)
+	n, err := io.ReadFull(r, raw)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	if n < len(magic) || !bytes.Equal(raw[:len(magic)], magic) {
+		return nil, false, nil
+	}
+
+	h = &header{
+		raw:         raw,
+		chunkSize:   int(binary.BigEndian.Uint32(raw[8:12])),
+		size:        int64(binary.BigEndian.Uint64(raw[12:20])),
+		noncePrefix: raw[20:28],
+	}
+	// The header is only authenticated with the chunks, so nothing is sized from it before the
+	// chunk size is known to be the one files are written with
+	if h.chunkSize != chunkSize {
+		return nil, true, fmt.Errorf("invalid encryption header: unsupported chunk size %d", h.chunkSize)
+	}
+	if h.size < 0 {
+		return nil, true, fmt.Errorf("invalid encryption header")
+	}
+	return h, true, nil
+}
+
+// Inspect reports whether filePath is encrypted and the size of its plaintext
+// Plaintext files report their own size. The key is not needed.
+func Inspect(filePath string) (size int64, encrypted bool, err error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return 0, false, err
+	}
+	defer file.Close()
+
+	h, ok, err := readHeader(file)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to read %s: %w", filePath, err)
+	}
+	if ok {
+		return h.size, true, nil
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return 0, false, err
+	}
+	return info.Size(), false, nil
+}
+
+// EncryptFile encrypts filePath in place; files that are already encrypted are left alone
+// The ciphertext is written next to the file and renamed over it, so a failure never leaves
+// a half-encrypted recording behind.
+func (c *Cipher) EncryptFile(filePath string) (err error) {
+	src, err := os.Open(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to open %s for encryption: %w", filePath, err)
+	}
+	defer src.Close()
+
+	if _, ok, err := readHeader(src); err != nil || ok {
+		return err
+	}
+	info, err := src.Stat()
+	if err != nil {
+		return err
+	}
+	if _, err := src.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(filePath), "."+filepath.Base(filePath)+".enc-*")
+	if err != nil {
+		return fmt.Errorf("failed to create encrypted file: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+	if err := tmp.Chmod(info.Mode().Perm()); err != nil {
+		return fmt.Errorf("failed to set encrypted file mode: %w", err)
+	}
+
+	if err := c.encrypt(tmp, src, info.Size()); err != nil {
+		return fmt.Errorf("failed to encrypt %s: %w", filePath, err)
+	}
+	if err := tmp.Sync(); err != nil {
+		return fmt.Errorf("failed to write encrypted file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write encrypted file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), filePath); err != nil {
+		return fmt.Errorf("failed to replace %s with its encrypted copy: %w", filePath, err)
+	}
+	return nil
+}
+
+// encrypt writes the header and the sealed chunks of the size bytes read from r to w
+func (c *Cipher) encrypt(w io.Writer, r io.Reader, size int64) error {
+	raw := make([]byte, headerSize)
+	copy(raw, magic)
+	binary.BigEndian.PutUint32(raw[8:12], chunkSize)
+	binary.BigEndian.PutUint64(raw[12:20], uint64(size))
+	if _, err := rand.Read(raw[20:28]); err != nil {
+		return fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	if _, err := w.Write(raw); err != nil {
+		return err
+	}
+	h := &header{raw: raw, chunkSize: chunkSize, size: size, noncePrefix: raw[20:28]}
+
+	plain := make([]byte, chunkSize)
+	sealed := make([]byte, 0, chunkSize+c.aead.Overhead())
+	for index, remaining := uint32(0), size; remaining > 0; index++ {
+		n := int(min(remaining, chunkSize))
+		if _, err := io.ReadFull(r, plain[:n]); err != nil {
+			return fmt.Errorf("file changed while encrypting: %w", err)
+		}
+		sealed = c.aead.Seal(sealed[:0], h.nonce(index), plain[:n], h.raw)
+		if _, err := w.Write(sealed); err != nil {
+			return err
+		}
+		remaining -= int64(n)
+	}
+	return nil
+}
+
+// nonce returns the nonce of chunk index
+func (h *header) nonce(index uint32) []byte {
+	nonce := make([]byte, 12)
+	copy(nonce, h.noncePrefix)
+	binary.BigEndian.PutUint32(nonce[8:], index)
+	return nonce
+}
+
+// Open opens filePath for reading its plaintext
+// Encrypted files are decrypted as they are read and need c; plaintext files are returned
+// as they are, so callers can read recordings whether or not they are encrypted.
+func Open(c *Cipher, filePath string) (io.ReadCloser, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	h, ok, err := readHeader(file)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to read %s: %w", filePath, err)
+	}
+	if !ok {
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			file.Close()
+			return nil, err
+		}
+		return file, nil
+	}
+	if c == nil {
+		file.Close()
+		return nil, fmt.Errorf("%s: %w", filePath, ErrNoKey)
+	}
+	return &reader{cipher: c, file: file, header: h, remaining: h.size}, nil
+}
+
+// DecryptFile writes the plaintext of src to a new file dst readable only by its owner
+func DecryptFile(c *Cipher, src, dst string) (err error) {
+	in, err := Open(c, src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create decrypted file: %w", err)
+	}
+	defer func() {
+		if closeErr := out.Close(); err == nil && closeErr != nil {
+			err = fmt.Errorf("failed to write decrypted file: %w", closeErr)
+		}
+		if err != nil {
+			os.Remove(dst)
+		}
+	}()
+
+	if _, err := io.Copy(out, in); err != nil {
+		return fmt.Errorf("failed to decrypt %s: %w", src, err)
+	}
+	return nil
+}
+
+// reader decrypts an encrypted file chunk by chunk
+type reader struct {
+	cipher    *Cipher
+	file      *os.File
+	header    *header
+	index     uint32
+	remaining int64 // Plaintext bytes not decrypted yet
+	sealed    []byte
+	plain     []byte // Decrypted bytes not read yet
+}
+
+// Read implements io.Reader
+func (r *reader) Read(p []byte) (int, error) {
+	if len(r.plain) == 0 {
+		if r.remaining == 0 {
+			// Anything after the last chunk means the file was tampered with
+			var extra [1]byte
+			if n, _ := r.file.Read(extra[:]); n > 0 {
+				return 0, fmt.Errorf("encrypted file has trailing data")
+			}
+			return 0, io.EOF
+		}
+		if err := r.next(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, r.plain)
+	r.plain = r.plain[n:]
+	return n, nil
+}
+
+// next reads and decrypts the next chunk
+func (r *reader) next() error {
+	n := int(min(r.remaining, int64(r.header.chunkSize))) + r.cipher.aead.Overhead()
+	if cap(r.sealed) < n {
+		r.sealed = make([]byte, n)
+	}
+	r.sealed = r.sealed[:n]
+	if _, err := io.ReadFull(r.file, r.sealed); err != nil {
+		return fmt.Errorf("encrypted file is truncated: %w", err)
+	}
+
+	plain, err := r.cipher.aead.Open(r.sealed[:0], r.header.nonce(r.index), r.sealed, r.header.raw)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt chunk %d (wrong key or corrupted file): %w", r.index, err)
+	}
+	r.plain = plain
+	r.index++
+	r.remaining -= int64(len(plain))
+	return nil
+}
+
+// Close closes the underlying file
+func (r *reader) Close() error {
+	return r.file.Close()
+}
@@ -0,0 +1,177 @@
+package atrest
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newTestCipher(t *testing.T) *Cipher {
+	t.Helper()
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatal(err)
+	}
+	c, err := NewCipher(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func readAll(t *testing.T, c *Cipher, filePath string) ([]byte, error) {
+	t.Helper()
+	r, err := Open(c, filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+func TestEncryptFile_RoundTrip(t *testing.T) {
+	c := newTestCipher(t)
+
+	for _, size := range []int{0, 1, chunkSize - 1, chunkSize, 2*chunkSize + 17} {
+		filePath := filepath.Join(t.TempDir(), "meeting.mp4")
+		content := make([]byte, size)
+		rand.Read(content)
+		if err := os.WriteFile(filePath, content, 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := c.EncryptFile(filePath); err != nil {
+			t.Fatalf("EncryptFile(%d bytes) failed: %v", size, err)
+		}
+		// Encrypting twice leaves the file alone
+		if err := c.EncryptFile(filePath); err != nil {
+			t.Fatalf("second EncryptFile failed: %v", err)
+		}
+
+		plainSize, encrypted, err := Inspect(filePath)
+		if err != nil || !encrypted || plainSize != int64(size) {
+			t.Errorf("Inspect = %d, %v, %v; want %d, true", plainSize, encrypted, err, size)
+		}
+		if size > 0 {
+			raw, _ := os.ReadFile(filePath)
+			if bytes.Contains(raw, content) {
+				t.Errorf("Encrypted file contains the plaintext")
+			}
+		}
+
+		got, err := readAll(t, c, filePath)
+		if err != nil || !bytes.Equal(got, content) {
+			t.Errorf("Decrypted %d bytes (err %v), want the %d original bytes", len(got), err, size)
+		}
+
+		dst := filepath.Join(t.TempDir(), "meeting.mp4")
+		if err := DecryptFile(c, filePath, dst); err != nil {
+			t.Fatalf("DecryptFile failed: %v", err)
+		}
+		if got, _ := os.ReadFile(dst); !bytes.Equal(got, content) {
+			t.Errorf("DecryptFile wrote different content")
+		}
+	}
+}
+
+func TestOpen_Plaintext(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "meeting.mp4")
+	if err := os.WriteFile(filePath, []byte("plain recording"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := readAll(t, nil, filePath)
+	if err != nil || string(got) != "plain recording" {
+		t.Errorf("Expected plaintext file to be read as is, got %q, %v", got, err)
+	}
+	if size, encrypted, err := Inspect(filePath); err != nil || encrypted || size != 15 {
+		t.Errorf("Inspect = %d, %v, %v", size, encrypted, err)
+	}
+}
+
+func TestOpen_Rejects(t *testing.T) {
+	c := newTestCipher(t)
+	content := make([]byte, chunkSize+100)
+	rand.Read(content)
+
+	encrypt := func(t *testing.T) string {
+		filePath := filepath.Join(t.TempDir(), "meeting.mp4")
+		if err := os.WriteFile(filePath, content, 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := c.EncryptFile(filePath); err != nil {
+			t.Fatal(err)
+		}
+		return filePath
+	}
+
+	t.Run("no key", func(t *testing.T) {
+		if _, err := Open(nil, encrypt(t)); !errors.Is(err, ErrNoKey) {
+			t.Errorf("Expected ErrNoKey, got %v", err)
+		}
+	})
+	t.Run("wrong key", func(t *testing.T) {
+		if _, err := readAll(t, newTestCipher(t), encrypt(t)); err == nil {
+			t.Error("Expected decryption with the wrong key to fail")
+		}
+	})
+	t.Run("modified", func(t *testing.T) {
+		filePath := encrypt(t)
+		raw, _ := os.ReadFile(filePath)
+		raw[headerSize+10] ^= 1
+		os.WriteFile(filePath, raw, 0644)
+		if _, err := readAll(t, c, filePath); err == nil {
+			t.Error("Expected a modified file to fail")
+		}
+	})
+	t.Run("truncated", func(t *testing.T) {
+		filePath := encrypt(t)
+		raw, _ := os.ReadFile(filePath)
+		os.WriteFile(filePath, raw[:len(raw)-50], 0644)
+		if _, err := readAll(t, c, filePath); err == nil {
+			t.Error("Expected a truncated file to fail")
+		}
+	})
+	t.Run("chunk size", func(t *testing.T) {
+		filePath := encrypt(t)
+		raw, _ := os.ReadFile(filePath)
+		binary.BigEndian.PutUint32(raw[8:12], 1<<32-1)
+		os.WriteFile(filePath, raw, 0644)
+		if _, err := Open(c, filePath); err == nil || !strings.Contains(err.Error(), "chunk size") {
+			t.Errorf("Expected an unsupported chunk size to be rejected before decrypting, got %v", err)
+		}
+	})
+	t.Run("trailing data", func(t *testing.T) {
+		filePath := encrypt(t)
+		raw, _ := os.ReadFile(filePath)
+		os.WriteFile(filePath, append(raw, 0), 0644)
+		if _, err := readAll(t, c, filePath); err == nil {
+			t.Error("Expected trailing data to fail")
+		}
+	})
+}
+
+func TestParseKey(t *testing.T) {
+	key := make([]byte, KeySize)
+	rand.Read(key)
+
+	for _, encoded := range []string{hex.EncodeToString(key), base64.StdEncoding.EncodeToString(key)} {
+		got, err := ParseKey(encoded + "\n")
+		if err != nil || !bytes.Equal(got, key) {
+			t.Errorf("ParseKey(%q) = %x, %v", encoded, got, err)
+		}
+	}
+	for _, invalid := range []string{"", "short", base64.StdEncoding.EncodeToString(key[:16])} {
+		if _, err := ParseKey(invalid); err == nil {
+			t.Errorf("Expected ParseKey(%q) to fail", invalid)
+		}
+	}
+}
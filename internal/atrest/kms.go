@@ -0,0 +1,39 @@
+package atrest
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+)
+
+// DecryptKMSKey decrypts a data key encrypted with AWS KMS, e.g. the CiphertextBlob of
+// `aws kms generate-data-key --key-spec AES_256`, given as base64
+// Credentials and the region come from the standard AWS chain unless region is set.
+func DecryptKMSKey(ctx context.Context, encryptedKey, region string) ([]byte, error) {
+	blob, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encryptedKey))
+	if err != nil {
+		return nil, fmt.Errorf("KMS encrypted key must be base64: %w", err)
+	}
+
+	var loadOptions []func(*awsconfig.LoadOptions) error
+	if region != "" {
+		loadOptions = append(loadOptions, awsconfig.WithRegion(region))
+	}
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx, loadOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS configuration: %w", err)
+	}
+
+	out, err := kms.NewFromConfig(awsCfg).Decrypt(ctx, &kms.DecryptInput{CiphertextBlob: blob})
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt encryption key with KMS: %w", err)
+	}
+	if len(out.Plaintext) != KeySize {
+		return nil, fmt.Errorf("KMS data key must be %d bytes, got %d", KeySize, len(out.Plaintext))
+	}
+	return out.Plaintext, nil
+}
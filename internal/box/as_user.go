@@ -0,0 +1,118 @@
+package box
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// AsUserClient wraps a BoxClient so folder listings, folder creation and uploads are made with
+// the As-User header. Everything it creates is owned by the impersonated user instead of the
+// service account. Other operations (file details, versions, deletes) still use the wrapped
+// client's own identity.
+type AsUserClient struct {
+	BoxClient
+
+	userID string
+}
+
+// NewAsUserClient wraps client so it acts as the Box user userID
+func NewAsUserClient(client BoxClient, userID string) *AsUserClient {
+	return &AsUserClient{BoxClient: client, userID: userID}
+}
+
+// UserID returns the ID of the impersonated Box user
+func (c *AsUserClient) UserID() string {
+	return c.userID
+}
+
+// CreateFolder creates a folder as the user
+func (c *AsUserClient) CreateFolder(name string, parentID string) (*Folder, error) {
+	return c.BoxClient.CreateFolderAsUser(name, parentID, c.userID)
+}
+
+// ListFolderItems lists a folder as the user
+func (c *AsUserClient) ListFolderItems(folderID string) (*FolderItems, error) {
+	return c.BoxClient.ListFolderItemsAsUser(folderID, c.userID)
+}
+
+// UploadFile uploads a file as the user
+func (c *AsUserClient) UploadFile(filePath string, parentFolderID string, fileName string) (*File, error) {
+	return c.BoxClient.UploadFileAsUser(filePath, parentFolderID, fileName, c.userID, nil)
+}
+
+// UploadFileWithProgress uploads a file as the user
+func (c *AsUserClient) UploadFileWithProgress(filePath string, parentFolderID string, fileName string, progressCallback ProgressCallback) (*File, error) {
+	return c.BoxClient.UploadFileAsUser(filePath, parentFolderID, fileName, c.userID, progressCallback)
+}
+
+// UploadStream uploads a stream as the user
+func (c *AsUserClient) UploadStream(r io.Reader, size int64, parentFolderID string, fileName string, progressCallback ProgressCallback) (*File, error) {
+	return c.BoxClient.UploadStreamAsUser(r, size, parentFolderID, fileName, c.userID, progressCallback)
+}
+
+// SearchFiles searches for files named name below ancestorFolderID as the user
+func (c *AsUserClient) SearchFiles(name string, ancestorFolderID string) ([]*File, error) {
+	return c.BoxClient.SearchFilesAsUser(name, ancestorFolderID, c.userID)
+}
+
+// FindFolderByName searches parentID for a folder named name as the user
+func (c *AsUserClient) FindFolderByName(parentID string, name string) (*Folder, error) {
+	item, err := c.findItem(parentID, name, ItemTypeFolder)
+	if err != nil {
+		return nil, err
+	}
+	return &Folder{
+		ID:      item.ID,
+		Type:    item.Type,
+		Name:    item.Name,
+		OwnedBy: item.OwnedBy,
+	}, nil
+}
+
+// FindFileByName searches folderID for a file named name as the user
+func (c *AsUserClient) FindFileByName(folderID string, name string) (*File, error) {
+	item, err := c.findItem(folderID, name, ItemTypeFile)
+	if err != nil {
+		return nil, err
+	}
+	return c.BoxClient.GetFile(item.ID)
+}
+
+// FindZoomFolderByOwner finds the "zoom" folder in the user's own root folder
+// The owner is implied by the impersonated user, so ownerEmail is only used in errors.
+func (c *AsUserClient) FindZoomFolderByOwner(ownerEmail string) (*Folder, error) {
+	folder, err := c.FindFolderByName(RootFolderID, "zoom")
+	if err != nil {
+		return nil, fmt.Errorf("zoom folder not found for %s: %w", ownerEmail, err)
+	}
+	return folder, nil
+}
+
+// findItem returns the entry of itemType named name in folderID as listed by the user
+func (c *AsUserClient) findItem(folderID, name, itemType string) (*Item, error) {
+	if strings.TrimSpace(name) == "" {
+		return nil, fmt.Errorf("%s name cannot be empty", itemType)
+	}
+	if folderID == "" {
+		folderID = RootFolderID
+	}
+
+	items, err := c.ListFolderItems(folderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list folder items: %w", err)
+	}
+	for _, item := range items.Entries {
+		if item.Type == itemType && item.Name == name {
+			return &item, nil
+		}
+	}
+
+	return nil, &BoxError{
+		StatusCode: http.StatusNotFound,
+		Code:       ErrorCodeItemNotFound,
+		Message:    fmt.Sprintf("%s '%s' not found in folder %s", itemType, name, folderID),
+		Retryable:  false,
+	}
+}
@@ -5,12 +5,18 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
+
+	"github.com/curtbushko/zoom-to-box/internal/errclass"
+	"github.com/curtbushko/zoom-to-box/internal/logging"
+	"github.com/curtbushko/zoom-to-box/internal/retry"
 )
 
 // Authenticator defines the interface for Box OAuth 2.0 authentication
@@ -59,6 +65,7 @@ type AuthenticatedHTTPClient interface {
 type oauth2Authenticator struct {
 	credentials *OAuth2Credentials
 	httpClient  *http.Client
+	tokenURL    string
 
 	// Callbacks for credential updates
 	onCredentialsUpdated func(*OAuth2Credentials) error
@@ -80,6 +87,7 @@ func NewOAuth2Authenticator(creds *OAuth2Credentials, httpClient *http.Client) A
 	return &oauth2Authenticator{
 		credentials: creds,
 		httpClient:  httpClient,
+		tokenURL:    BoxTokenURL,
 	}
 }
 
@@ -103,7 +111,6 @@ func (a *oauth2Authenticator) GetAccessTokenWithClientCredentials(ctx context.Co
 	data := url.Values{}
 	data.Set("grant_type", "client_credentials")
 	data.Set("client_id", a.credentials.ClientID)
-	data.Set("client_secret", a.credentials.ClientSecret)
 	data.Set("box_subject_type", "enterprise")
 
 	// Use enterprise_id if provided, otherwise default to "0"
@@ -113,51 +120,14 @@ func (a *oauth2Authenticator) GetAccessTokenWithClientCredentials(ctx context.Co
 		data.Set("box_subject_id", "0")
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", BoxTokenURL, strings.NewReader(data.Encode()))
-	if err != nil {
-		return fmt.Errorf("failed to create token request: %w", err)
-	}
-
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.Header.Set("Accept", "application/json")
-	req.Header.Set("User-Agent", "zoom-to-box/1.0")
-
-	// Make the request
-	resp, err := a.httpClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("token request failed: %w", err)
-	}
-	defer resp.Body.Close()
-
-	// Read response body
-	body, err := io.ReadAll(resp.Body)
+	tokenResp, err := a.requestTokenWithClientSecrets(ctx, data, "token request")
 	if err != nil {
-		return fmt.Errorf("failed to read token response: %w", err)
-	}
-
-	// Check for errors
-	if resp.StatusCode != http.StatusOK {
-		var errorResp ErrorResponse
-		if json.Unmarshal(body, &errorResp) == nil {
-			return &BoxError{
-				StatusCode: resp.StatusCode,
-				Message:    errorResp.Message,
-				Code:       errorResp.Code,
-				RequestID:  errorResp.RequestID,
-				Retryable:  resp.StatusCode >= 500 || resp.StatusCode == 429,
-			}
-		}
-		return fmt.Errorf("token request failed with status %d: %s", resp.StatusCode, string(body))
-	}
-
-	// Parse token response
-	var tokenResp TokenResponse
-	if err := json.Unmarshal(body, &tokenResp); err != nil {
-		return fmt.Errorf("failed to parse token response: %w", err)
+		return err
 	}
 
 	// Update credentials
 	a.credentials.AccessToken = tokenResp.AccessToken
+	logging.RegisterSecret(tokenResp.AccessToken)
 	a.credentials.ExpiresIn = tokenResp.ExpiresIn
 	a.credentials.TokenType = tokenResp.TokenType
 	a.credentials.Scope = tokenResp.Scope
@@ -193,35 +163,90 @@ func (a *oauth2Authenticator) RefreshToken(ctx context.Context) error {
 	data.Set("grant_type", "refresh_token")
 	data.Set("refresh_token", a.credentials.RefreshToken)
 	data.Set("client_id", a.credentials.ClientID)
-	data.Set("client_secret", a.credentials.ClientSecret)
-	
-	req, err := http.NewRequestWithContext(ctx, "POST", BoxTokenURL, strings.NewReader(data.Encode()))
+
+	tokenResp, err := a.requestTokenWithClientSecrets(ctx, data, "token refresh")
 	if err != nil {
-		return fmt.Errorf("failed to create token refresh request: %w", err)
+		return err
+	}
+	
+	// Update credentials
+	a.credentials.AccessToken = tokenResp.AccessToken
+	a.credentials.RefreshToken = tokenResp.RefreshToken
+	logging.RegisterSecret(tokenResp.AccessToken)
+	logging.RegisterSecret(tokenResp.RefreshToken)
+	a.credentials.ExpiresIn = tokenResp.ExpiresIn
+	a.credentials.TokenType = tokenResp.TokenType
+	a.credentials.Scope = tokenResp.Scope
+	a.credentials.ExpiresAt = time.Now().Add(time.Duration(tokenResp.ExpiresIn) * time.Second)
+	
+	// Call update callback if set
+	if a.onCredentialsUpdated != nil {
+		if err := a.onCredentialsUpdated(a.credentials); err != nil {
+			return fmt.Errorf("failed to update stored credentials: %w", err)
+		}
 	}
 	
+	return nil
+}
+
+// requestTokenWithClientSecrets posts a token request signed with client_secret. If Box rejects
+// the secret and client_secret_next is set, the request is retried with the next secret, which is
+// then promoted to client_secret so later refreshes and persisted credentials use it.
+func (a *oauth2Authenticator) requestTokenWithClientSecrets(ctx context.Context, data url.Values, operation string) (*TokenResponse, error) {
+	data.Set("client_secret", a.credentials.ClientSecret)
+	tokenResp, err := a.requestToken(ctx, data, operation)
+	if a.credentials.ClientSecretNext == "" {
+		return tokenResp, err
+	}
+	if err == nil {
+		logging.Info("Box %s succeeded using client_secret", operation)
+		return tokenResp, nil
+	}
+	if !isCredentialRejection(err) {
+		return nil, err
+	}
+
+	logging.Warn("Box rejected client_secret for %s (%v), retrying with client_secret_next", operation, err)
+	data.Set("client_secret", a.credentials.ClientSecretNext)
+	tokenResp, err = a.requestToken(ctx, data, operation)
+	if err != nil {
+		return nil, err
+	}
+
+	logging.Info("Box %s succeeded using client_secret_next; it is now the active client secret", operation)
+	a.credentials.ClientSecret, a.credentials.ClientSecretNext = a.credentials.ClientSecretNext, a.credentials.ClientSecret
+	return tokenResp, nil
+}
+
+// requestToken posts a form to the Box token endpoint and parses the token response
+func (a *oauth2Authenticator) requestToken(ctx context.Context, data url.Values, operation string) (*TokenResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", a.tokenURL, strings.NewReader(data.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", operation, err)
+	}
+
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("User-Agent", "zoom-to-box/1.0")
-	
+
 	// Make the request
 	resp, err := a.httpClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("token refresh request failed: %w", err)
+		return nil, fmt.Errorf("%s failed: %w", operation, err)
 	}
 	defer resp.Body.Close()
-	
+
 	// Read response body
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return fmt.Errorf("failed to read token response: %w", err)
+		return nil, fmt.Errorf("failed to read token response: %w", err)
 	}
-	
+
 	// Check for errors
 	if resp.StatusCode != http.StatusOK {
 		var errorResp ErrorResponse
 		if json.Unmarshal(body, &errorResp) == nil {
-			return &BoxError{
+			return nil, &BoxError{
 				StatusCode: resp.StatusCode,
 				Message:    errorResp.Message,
 				Code:       errorResp.Code,
@@ -229,31 +254,24 @@ func (a *oauth2Authenticator) RefreshToken(ctx context.Context) error {
 				Retryable:  resp.StatusCode >= 500 || resp.StatusCode == 429,
 			}
 		}
-		return fmt.Errorf("token refresh failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, fmt.Errorf("%s failed with status %d: %s", operation, resp.StatusCode, string(body))
 	}
-	
+
 	// Parse token response
 	var tokenResp TokenResponse
 	if err := json.Unmarshal(body, &tokenResp); err != nil {
-		return fmt.Errorf("failed to parse token response: %w", err)
+		return nil, fmt.Errorf("failed to parse token response: %w", err)
 	}
-	
-	// Update credentials
-	a.credentials.AccessToken = tokenResp.AccessToken
-	a.credentials.RefreshToken = tokenResp.RefreshToken
-	a.credentials.ExpiresIn = tokenResp.ExpiresIn
-	a.credentials.TokenType = tokenResp.TokenType
-	a.credentials.Scope = tokenResp.Scope
-	a.credentials.ExpiresAt = time.Now().Add(time.Duration(tokenResp.ExpiresIn) * time.Second)
-	
-	// Call update callback if set
-	if a.onCredentialsUpdated != nil {
-		if err := a.onCredentialsUpdated(a.credentials); err != nil {
-			return fmt.Errorf("failed to update stored credentials: %w", err)
-		}
+
+	return &tokenResp, nil
+}
+
+// isCredentialRejection reports whether the token endpoint refused the client credentials
+func isCredentialRejection(err error) bool {
+	if boxErr, ok := err.(*BoxError); ok {
+		return boxErr.StatusCode == http.StatusBadRequest || boxErr.StatusCode == http.StatusUnauthorized
 	}
-	
-	return nil
+	return false
 }
 
 // GetAccessToken returns the current access token
@@ -308,10 +326,17 @@ func (a *oauth2Authenticator) UpdateCredentials(creds *OAuth2Credentials) error
 type authenticatedHTTPClient struct {
 	authenticator Authenticator
 	httpClient    *http.Client
+	retryPolicy   retry.Policy          // Applied to Box API calls; uploads retry at the part level
+	breaker       *retry.CircuitBreaker // Pauses all requests after consecutive failures (nil = never)
 }
 
 // NewAuthenticatedHTTPClient creates a new HTTP client with OAuth authentication
 func NewAuthenticatedHTTPClient(auth Authenticator, httpClient *http.Client) AuthenticatedHTTPClient {
+	return newAuthenticatedHTTPClient(auth, httpClient, retry.Policy{})
+}
+
+// newAuthenticatedHTTPClient creates an authenticated client that retries Box API calls
+func newAuthenticatedHTTPClient(auth Authenticator, httpClient *http.Client, retryPolicy retry.Policy) *authenticatedHTTPClient {
 	if httpClient == nil {
 		httpClient = &http.Client{
 			Timeout: 30 * time.Second,
@@ -321,11 +346,78 @@ func NewAuthenticatedHTTPClient(auth Authenticator, httpClient *http.Client) Aut
 	return &authenticatedHTTPClient{
 		authenticator: auth,
 		httpClient:    httpClient,
+		retryPolicy:   retryPolicy,
 	}
 }
 
 // Do performs an HTTP request with automatic token refresh
+// Box API calls that fail with a network error, 429 or 5xx are retried according to the
+// retry policy when the request body can be replayed. Requests to the upload host are sent
+// once, since chunked upload parts have their own retry policy.
 func (c *authenticatedHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	retries := c.retryPolicy.Retries()
+	if strings.HasPrefix(req.URL.String(), BoxUploadBaseURL) || (req.Body != nil && req.GetBody == nil) {
+		retries = 0
+	}
+
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, fmt.Errorf("failed to reset request body for retry: %w", err)
+			}
+			req.Body = body
+		}
+
+		if err := c.breaker.Wait(req.Context()); err != nil {
+			return nil, err
+		}
+		resp, err := c.doOnce(req)
+		c.breaker.Record(failedRequest(resp, err))
+		if attempt >= retries {
+			return resp, err
+		}
+
+		var delay time.Duration
+		switch {
+		case err != nil:
+			if !isRetryableError(err) {
+				return nil, err
+			}
+			delay = c.retryPolicy.Backoff(attempt)
+		case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+			delay = retryAfter(resp, c.retryPolicy.Backoff(attempt))
+			resp.Body.Close()
+		default:
+			return resp, nil
+		}
+
+		logging.Debug("Retrying Box API %s %s in %v (attempt %d of %d)", req.Method, req.URL.Path, delay, attempt+2, retries+1)
+		if err := retry.Sleep(req.Context(), delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// failedRequest reports whether a request failed in a way that counts toward the circuit
+// breaker: a network error, a rate limit or a server error
+func failedRequest(resp *http.Response, err error) bool {
+	if err != nil {
+		return isRetryableError(err)
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
+
+// retryAfter returns the wait requested by resp's Retry-After header when it exceeds delay
+func retryAfter(resp *http.Response, delay time.Duration) time.Duration {
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && time.Duration(seconds)*time.Second > delay {
+		return time.Duration(seconds) * time.Second
+	}
+	return delay
+}
+
+// doOnce performs a single authenticated request, refreshing the token once on 401
+func (c *authenticatedHTTPClient) doOnce(req *http.Request) (*http.Response, error) {
 	// Ensure we have a valid token
 	if err := c.ensureValidToken(req.Context()); err != nil {
 		return nil, fmt.Errorf("failed to ensure valid token: %w", err)
@@ -475,6 +567,15 @@ func (e *TokenRefreshError) IsRetryable() bool {
 	return e.Retryable
 }
 
+// ErrorClass returns the class of the underlying error, e.g. errclass.Network when the token
+// endpoint could not be reached, and errclass.Auth otherwise
+func (e *TokenRefreshError) ErrorClass() errclass.Class {
+	if class := errclass.Of(e.Err); class != errclass.Unknown {
+		return class
+	}
+	return errclass.Auth
+}
+
 // Helper functions for error handling
 
 // IsAuthError returns true if the error is an authentication error
@@ -496,6 +597,12 @@ func IsRetryableError(err error) bool {
 	return false
 }
 
+// IsNotFoundError returns true if the error, or an error it wraps, is a Box "not found" error
+func IsNotFoundError(err error) bool {
+	var boxErr *BoxError
+	return errors.As(err, &boxErr) && boxErr.StatusCode == http.StatusNotFound
+}
+
 // IsRateLimitError returns true if the error is a rate limit error
 func IsRateLimitError(err error) bool {
 	if boxErr, ok := err.(*BoxError); ok {
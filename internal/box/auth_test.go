@@ -3,11 +3,14 @@ package box
 import (
 	"context"
 	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/curtbushko/zoom-to-box/internal/retry"
 )
 
 func TestNewOAuth2Authenticator(t *testing.T) {
@@ -212,6 +215,61 @@ func TestOAuth2Authenticator_RefreshToken(t *testing.T) {
 	}
 }
 
+func TestOAuth2Authenticator_ClientSecretRotation(t *testing.T) {
+	var secretsSeen []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		secret := r.Form.Get("client_secret")
+		secretsSeen = append(secretsSeen, secret)
+
+		if secret != "new-secret" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"type": "error", "status": 400, "code": "invalid_client", "message": "The client credentials are invalid"}`))
+			return
+		}
+		w.Write([]byte(`{"access_token": "rotated-token", "expires_in": 3600, "token_type": "bearer"}`))
+	}))
+	defer server.Close()
+
+	creds := &OAuth2Credentials{
+		ClientID:         "test-client",
+		ClientSecret:     "old-secret",
+		ClientSecretNext: "new-secret",
+		EnterpriseID:     "12345",
+	}
+	auth := NewOAuth2Authenticator(creds, &http.Client{Timeout: 5 * time.Second}).(*oauth2Authenticator)
+	auth.tokenURL = server.URL + "/oauth2/token"
+
+	var saved *OAuth2Credentials
+	auth.SetCredentialsUpdateCallback(func(c *OAuth2Credentials) error {
+		saved = c
+		return nil
+	})
+
+	if err := auth.RefreshToken(context.Background()); err != nil {
+		t.Fatalf("Expected fallback to client_secret_next, got error: %v", err)
+	}
+	if auth.GetAccessToken() != "rotated-token" {
+		t.Errorf("Expected access token 'rotated-token', got '%s'", auth.GetAccessToken())
+	}
+	if strings.Join(secretsSeen, ",") != "old-secret,new-secret" {
+		t.Errorf("Expected old-secret then new-secret, got %v", secretsSeen)
+	}
+
+	// The accepted secret is promoted so it is persisted and tried first next time
+	if saved == nil || saved.ClientSecret != "new-secret" || saved.ClientSecretNext != "old-secret" {
+		t.Errorf("Expected promoted client secret in saved credentials, got %+v", saved)
+	}
+
+	secretsSeen = nil
+	if err := auth.RefreshToken(context.Background()); err != nil {
+		t.Fatalf("Failed to refresh with promoted secret: %v", err)
+	}
+	if len(secretsSeen) != 1 || secretsSeen[0] != "new-secret" {
+		t.Errorf("Expected a single request with new-secret, got %v", secretsSeen)
+	}
+}
+
 func TestOAuth2Authenticator_GetCredentials(t *testing.T) {
 	originalCreds := &OAuth2Credentials{
 		ClientID:     "test-client",
@@ -401,6 +459,57 @@ func TestAuthenticatedHTTPClient_PostJSON(t *testing.T) {
 	}
 }
 
+func TestAuthenticatedHTTPClient_RetryPolicy(t *testing.T) {
+	var requests int
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if requests < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	auth := &mockAuthenticator{
+		credentials: &OAuth2Credentials{
+			AccessToken: "mock-token",
+			ExpiresAt:   time.Now().Add(time.Hour),
+		},
+	}
+	policy := retry.Policy{MaxAttempts: 3, BaseDelay: time.Millisecond, Multiplier: 2, MaxDelay: 10 * time.Millisecond}
+	client := newAuthenticatedHTTPClient(auth, &http.Client{Timeout: 5 * time.Second}, policy)
+
+	resp, err := client.PostJSON(context.Background(), server.URL, map[string]string{"name": "test"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated || requests != 3 {
+		t.Errorf("Expected success on the third attempt, got status %d after %d requests", resp.StatusCode, requests)
+	}
+	for _, body := range bodies {
+		if body != `{"name":"test"}` {
+			t.Errorf("Expected request body to be replayed on retries, got %q", body)
+		}
+	}
+
+	// Without a policy the 503 is returned as is
+	requests = 0
+	resp, err = NewAuthenticatedHTTPClient(auth, nil).Get(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable || requests != 1 {
+		t.Errorf("Expected a single 503 without retries, got status %d after %d requests", resp.StatusCode, requests)
+	}
+}
+
 func TestIsAuthError(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -0,0 +1,125 @@
+package box
+
+import (
+	"fmt"
+	"sync"
+)
+
+// EmptyFolderCleaner removes folders created during a run that were left empty
+type EmptyFolderCleaner interface {
+	// CleanupEmptyFolders deletes empty tracked folders and returns the IDs it deleted
+	CleanupEmptyFolders() ([]string, error)
+}
+
+// FolderTrackingClient wraps a BoxClient and remembers the folders it creates, so that
+// <year>/<month>/<day> folders left empty by failed uploads can be removed afterwards.
+// Folders that existed before the run, including ones another upload or run created first, are
+// never touched, and neither are the zoom and destination user folders.
+type FolderTrackingClient struct {
+	BoxClient
+
+	mu      sync.Mutex
+	created []string // folder IDs in creation order (parents before children)
+}
+
+// NewFolderTrackingClient wraps client so created folders are tracked for cleanup
+func NewFolderTrackingClient(client BoxClient) *FolderTrackingClient {
+	return &FolderTrackingClient{BoxClient: client}
+}
+
+// CreateFolder creates a folder and tracks its ID when this call created it
+func (c *FolderTrackingClient) CreateFolder(name string, parentID string) (*Folder, error) {
+	folder, err := c.BoxClient.CreateFolder(name, parentID)
+	if err != nil {
+		return nil, err
+	}
+	c.track(folder)
+	return folder, nil
+}
+
+// CreateFolderAsUser creates a folder as userID and tracks its ID when this call created it
+func (c *FolderTrackingClient) CreateFolderAsUser(name string, parentID string, userID string) (*Folder, error) {
+	folder, err := c.BoxClient.CreateFolderAsUser(name, parentID, userID)
+	if err != nil {
+		return nil, err
+	}
+	c.track(folder)
+	return folder, nil
+}
+
+// track remembers a created folder; a folder returned for a name conflict already existed
+func (c *FolderTrackingClient) track(folder *Folder) {
+	if folder.Existed {
+		return
+	}
+	c.mu.Lock()
+	c.created = append(c.created, folder.ID)
+	c.mu.Unlock()
+}
+
+// untrack forgets folderID so CleanupEmptyFolders never deletes it
+func (c *FolderTrackingClient) untrack(folderID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, id := range c.created {
+		if id == folderID {
+			c.created = append(c.created[:i], c.created[i+1:]...)
+			return
+		}
+	}
+}
+
+// untrackFolder keeps a folder created through client, which may wrap a FolderTrackingClient,
+// out of the empty folder cleanup; the zoom and destination user folders must outlive a run
+// whose uploads all failed
+func untrackFolder(client BoxClient, folderID string) {
+	for {
+		switch c := client.(type) {
+		case *FolderTrackingClient:
+			c.untrack(folderID)
+			return
+		case *AsUserClient:
+			client = c.BoxClient
+		default:
+			return
+		}
+	}
+}
+
+// CleanupEmptyFolders deletes tracked folders that contain no items
+// Children are checked before their parents so a day folder's removal can leave its month
+// folder empty in turn. Every tracked folder is forgotten afterwards, whether it was deleted
+// or kept because files were placed in it.
+func (c *FolderTrackingClient) CleanupEmptyFolders() ([]string, error) {
+	c.mu.Lock()
+	created := c.created
+	c.created = nil
+	c.mu.Unlock()
+
+	var deleted []string
+	var firstErr error
+	for i := len(created) - 1; i >= 0; i-- {
+		folderID := created[i]
+
+		items, err := c.BoxClient.ListFolderItems(folderID)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("failed to list folder %s: %w", folderID, err)
+			}
+			continue
+		}
+		if items.TotalCount > 0 || len(items.Entries) > 0 {
+			continue
+		}
+
+		if err := c.BoxClient.DeleteFolder(folderID); err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("failed to delete empty folder %s: %w", folderID, err)
+			}
+			continue
+		}
+		deleted = append(deleted, folderID)
+	}
+
+	return deleted, firstErr
+}
@@ -0,0 +1,151 @@
+package box
+
+import (
+	"testing"
+)
+
+// folderTreeClient is a mock client that keeps parent/child folder listings in sync
+// and records deleted folders
+type folderTreeClient struct {
+	*mockBoxClient
+	deleted []string
+}
+
+func (c *folderTreeClient) CreateFolder(name string, parentID string) (*Folder, error) {
+	folder, err := c.mockBoxClient.CreateFolder(name, parentID)
+	if err != nil {
+		return nil, err
+	}
+	c.folderItems[parentID] = append(c.folderItems[parentID], Item{ID: folder.ID, Type: ItemTypeFolder, Name: name})
+	return folder, nil
+}
+
+func (c *folderTreeClient) DeleteFolder(folderID string) error {
+	c.deleted = append(c.deleted, folderID)
+	for parentID, items := range c.folderItems {
+		for i, item := range items {
+			if item.ID == folderID {
+				c.folderItems[parentID] = append(items[:i], items[i+1:]...)
+				break
+			}
+		}
+	}
+	return nil
+}
+
+func TestFolderTrackingClient_CleanupEmptyFolders(t *testing.T) {
+	inner := &folderTreeClient{mockBoxClient: newMockBoxClient()}
+	client := NewFolderTrackingClient(inner)
+	inner.folderItems["zoom"] = []Item{{ID: "existing-2023", Type: ItemTypeFolder, Name: "2023"}}
+
+	// Two days in the same month: one upload succeeds, one fails
+	uploaded, err := CreateFolderPath(client, "2024/01/15", "zoom")
+	if err != nil {
+		t.Fatalf("CreateFolderPath failed: %v", err)
+	}
+	failed, err := CreateFolderPath(client, "2024/01/16", "zoom")
+	if err != nil {
+		t.Fatalf("CreateFolderPath failed: %v", err)
+	}
+	inner.folderItems[uploaded.ID] = []Item{{ID: "file-1", Type: ItemTypeFile, Name: "meeting.mp4"}}
+
+	deleted, err := client.CleanupEmptyFolders()
+	if err != nil {
+		t.Fatalf("CleanupEmptyFolders failed: %v", err)
+	}
+
+	if len(deleted) != 1 || deleted[0] != failed.ID {
+		t.Errorf("Expected only the empty day folder %s to be deleted, got %v", failed.ID, deleted)
+	}
+
+	// Folders are only cleaned up once
+	deleted, _ = client.CleanupEmptyFolders()
+	if len(deleted) != 0 {
+		t.Errorf("Expected no folders on second cleanup, got %v", deleted)
+	}
+}
+
+func TestFolderTrackingClient_RemovesEmptyParents(t *testing.T) {
+	inner := &folderTreeClient{mockBoxClient: newMockBoxClient()}
+	client := NewFolderTrackingClient(inner)
+
+	if _, err := CreateFolderPath(client, "2024/03/01", "zoom"); err != nil {
+		t.Fatalf("CreateFolderPath failed: %v", err)
+	}
+
+	deleted, err := client.CleanupEmptyFolders()
+	if err != nil {
+		t.Fatalf("CleanupEmptyFolders failed: %v", err)
+	}
+
+	// Day, month and year folders are removed, children first; the zoom folder is untouched
+	if len(deleted) != 3 {
+		t.Fatalf("Expected 3 deleted folders, got %v", deleted)
+	}
+	if inner.folders[deleted[0]].Name != "01" || inner.folders[deleted[2]].Name != "2024" {
+		t.Errorf("Expected day folder first and year folder last, got %v", deleted)
+	}
+}
+
+// conflictingFolderClient answers creates of existing folder names with the existing folder,
+// like Box's 409 conflict handling in CreateFolder
+type conflictingFolderClient struct {
+	*folderTreeClient
+	existing map[string]string // Folder name -> ID of the folder that already exists
+}
+
+func (c *conflictingFolderClient) CreateFolder(name string, parentID string) (*Folder, error) {
+	if id, ok := c.existing[name]; ok {
+		return &Folder{ID: id, Type: ItemTypeFolder, Name: name, Existed: true}, nil
+	}
+	return c.folderTreeClient.CreateFolder(name, parentID)
+}
+
+func TestFolderTrackingClient_SkipsExistingFolders(t *testing.T) {
+	inner := &folderTreeClient{mockBoxClient: newMockBoxClient()}
+	client := NewFolderTrackingClient(&conflictingFolderClient{folderTreeClient: inner, existing: map[string]string{"15": "concurrent-15"}})
+
+	// Another upload created the day folder between the listing and the create
+	if _, err := client.CreateFolder("15", "zoom"); err != nil {
+		t.Fatalf("CreateFolder failed: %v", err)
+	}
+
+	deleted, err := client.CleanupEmptyFolders()
+	if err != nil {
+		t.Fatalf("CleanupEmptyFolders failed: %v", err)
+	}
+	if len(deleted) != 0 {
+		t.Errorf("Expected a folder that already existed to be kept, got %v", deleted)
+	}
+}
+
+func TestFolderTrackingClient_KeepsUserFolders(t *testing.T) {
+	inner := &folderTreeClient{mockBoxClient: newMockBoxClient()}
+	client := NewFolderTrackingClient(inner)
+
+	zoomFolder, err := CreateZoomFolderForUser(NewAsUserClient(client, "user_jane@example.com"), "jane@example.com")
+	if err != nil {
+		t.Fatalf("CreateZoomFolderForUser failed: %v", err)
+	}
+	userFolder, err := CreateDestinationUserFolder(client, "destination", "john@example.com")
+	if err != nil {
+		t.Fatalf("CreateDestinationUserFolder failed: %v", err)
+	}
+	day, err := CreateFolderPath(client, "2024/03/01", userFolder.ID)
+	if err != nil {
+		t.Fatalf("CreateFolderPath failed: %v", err)
+	}
+
+	deleted, err := client.CleanupEmptyFolders()
+	if err != nil {
+		t.Fatalf("CleanupEmptyFolders failed: %v", err)
+	}
+	if len(deleted) != 3 || deleted[0] != day.ID {
+		t.Errorf("Expected only the empty date folders to be deleted, got %v", deleted)
+	}
+	for _, id := range deleted {
+		if id == zoomFolder.ID || id == userFolder.ID {
+			t.Errorf("Expected the user's folder %s to be kept, got %v", id, deleted)
+		}
+	}
+}
@@ -13,20 +13,60 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/curtbushko/zoom-to-box/internal/logging"
+	"github.com/curtbushko/zoom-to-box/internal/retry"
 )
 
 type boxClient struct {
-	httpClient AuthenticatedHTTPClient
+	httpClient        AuthenticatedHTTPClient
+	uploadConcurrency int
+	uploadRetry       retry.Policy
+	uploadPartSize    int64
+
+	destinationFolderID string // Replaces each user's zoom folder with a folder per user in it ("" = none)
+
+	sessions openSessions // Chunked upload sessions in progress, aborted on shutdown
+}
+
+// ClientOptions holds optional tuning for the Box client
+type ClientOptions struct {
+	UploadConcurrency int   // Number of chunked upload parts in flight (0 = DefaultUploadConcurrency)
+	UploadPartSize    int64 // Part size when an upload session does not assign one (0 = DefaultChunkSize)
+
+	APIRetry    retry.Policy // Retries for Box API calls on 429/5xx and network errors (zero value = no retries)
+	UploadRetry retry.Policy // Retries for chunked upload parts (zero value = retry.DefaultBoxUpload)
+
+	CircuitBreaker retry.CircuitBreakerConfig // Pauses all Box requests after consecutive failures (zero value = never)
+
+	DestinationFolderID string // Shared folder whose per-user folders FindZoomFolderByOwner returns instead of zoom folders ("" = none)
 }
 
 func NewBoxClient(auth Authenticator, httpClient *http.Client) BoxClient {
-	authClient := NewAuthenticatedHTTPClient(auth, httpClient)
+	return NewBoxClientWithOptions(auth, httpClient, ClientOptions{})
+}
+
+// NewBoxClientWithOptions creates a Box client with the given tuning options
+func NewBoxClientWithOptions(auth Authenticator, httpClient *http.Client, opts ClientOptions) BoxClient {
+	if opts.UploadConcurrency <= 0 {
+		opts.UploadConcurrency = DefaultUploadConcurrency
+	}
+	authClient := newAuthenticatedHTTPClient(auth, httpClient, opts.APIRetry)
+	authClient.breaker = retry.NewCircuitBreaker(opts.CircuitBreaker, func(failures int, cooldown time.Duration) {
+		logging.Warn("Pausing all Box requests for %v after %d failed requests in a row", cooldown, failures)
+	})
 	return &boxClient{
-		httpClient: authClient,
+		httpClient:        authClient,
+		uploadConcurrency: opts.UploadConcurrency,
+		uploadRetry:       opts.UploadRetry,
+		uploadPartSize:    opts.UploadPartSize,
+
+		destinationFolderID: opts.DestinationFolderID,
 	}
 }
 
@@ -173,9 +213,10 @@ func (c *boxClient) CreateFolder(name string, parentID string) (*Folder, error)
 			// Return the existing folder info
 			conflict := errorResp.ContextInfo.Conflicts[0]
 			return &Folder{
-				ID:   conflict.ID,
-				Type: conflict.Type,
-				Name: conflict.Name,
+				ID:      conflict.ID,
+				Type:    conflict.Type,
+				Name:    conflict.Name,
+				Existed: true,
 			}, nil
 		}
 
@@ -240,9 +281,10 @@ func (c *boxClient) CreateFolderAsUser(name string, parentID string, userID stri
 			// Return the existing folder info
 			conflict := errorResp.ContextInfo.Conflicts[0]
 			return &Folder{
-				ID:   conflict.ID,
-				Type: conflict.Type,
-				Name: conflict.Name,
+				ID:      conflict.ID,
+				Type:    conflict.Type,
+				Name:    conflict.Name,
+				Existed: true,
 			}, nil
 		}
 
@@ -301,96 +343,92 @@ func (c *boxClient) GetFolder(folderID string) (*Folder, error) {
 	return &folder, nil
 }
 
+// ListFolderItems lists every item in a folder, following pagination markers
 func (c *boxClient) ListFolderItems(folderID string) (*FolderItems, error) {
-	if folderID == "" {
-		folderID = RootFolderID
-	}
-
-	url := fmt.Sprintf("%s/folders/%s/items", BoxAPIBaseURL, folderID)
-	resp, err := c.httpClient.Get(context.Background(), url)
-	if err != nil {
-		return nil, fmt.Errorf("failed to list folder items: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode == http.StatusNotFound {
-		return nil, &BoxError{
-			StatusCode: resp.StatusCode,
-			Code:       ErrorCodeItemNotFound,
-			Message:    fmt.Sprintf("folder with ID '%s' not found", folderID),
-			Retryable:  false,
-		}
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("failed to list folder items, status: %d, body: %s", resp.StatusCode, string(body))
-	}
-
-	var items FolderItems
-	if err := json.NewDecoder(resp.Body).Decode(&items); err != nil {
-		return nil, fmt.Errorf("failed to decode folder items response: %w", err)
-	}
-
-	return &items, nil
+	return c.listFolderItems(folderID, "")
 }
 
+// ListFolderItemsAsUser lists every item in a folder as a specific user, following pagination markers
 func (c *boxClient) ListFolderItemsAsUser(folderID string, userID string) (*FolderItems, error) {
-	if folderID == "" {
-		folderID = RootFolderID
-	}
 	if userID == "" {
 		return nil, fmt.Errorf("user ID cannot be empty")
 	}
+	return c.listFolderItems(folderID, userID)
+}
 
-	url := fmt.Sprintf("%s/folders/%s/items", BoxAPIBaseURL, folderID)
-	resp, err := c.httpClient.GetAsUser(context.Background(), url, userID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to list folder items as user: %w", err)
+// listFolderItems lists every item in a folder with FolderItemFields, as userID when it is set
+// Pages of FolderItemsPageSize items are fetched with marker-based pagination until Box
+// returns no next marker, so large folders are listed completely.
+func (c *boxClient) listFolderItems(folderID string, userID string) (*FolderItems, error) {
+	if folderID == "" {
+		folderID = RootFolderID
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode == http.StatusNotFound {
-		return nil, &BoxError{
-			StatusCode: resp.StatusCode,
-			Code:       ErrorCodeItemNotFound,
-			Message:    fmt.Sprintf("folder with ID '%s' not found", folderID),
-			Retryable:  false,
+	all := &FolderItems{Limit: FolderItemsPageSize}
+	marker := ""
+	for {
+		query := url.Values{}
+		query.Set("fields", FolderItemFields)
+		query.Set("limit", strconv.Itoa(FolderItemsPageSize))
+		query.Set("usemarker", "true")
+		if marker != "" {
+			query.Set("marker", marker)
 		}
-	}
+		apiURL := fmt.Sprintf("%s/folders/%s/items?%s", BoxAPIBaseURL, folderID, query.Encode())
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("failed to list folder items as user, status: %d, body: %s", resp.StatusCode, string(body))
-	}
+		var resp *http.Response
+		var err error
+		if userID != "" {
+			resp, err = c.httpClient.GetAsUser(context.Background(), apiURL, userID)
+		} else {
+			resp, err = c.httpClient.Get(context.Background(), apiURL)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list folder items: %w", err)
+		}
 
-	var items FolderItems
-	if err := json.NewDecoder(resp.Body).Decode(&items); err != nil {
-		return nil, fmt.Errorf("failed to decode folder items response: %w", err)
+		if resp.StatusCode == http.StatusNotFound {
+			resp.Body.Close()
+			return nil, &BoxError{
+				StatusCode: resp.StatusCode,
+				Code:       ErrorCodeItemNotFound,
+				Message:    fmt.Sprintf("folder with ID '%s' not found", folderID),
+				Retryable:  false,
+			}
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			return nil, fmt.Errorf("failed to list folder items, status: %d, body: %s", resp.StatusCode, string(body))
+		}
+
+		var page FolderItems
+		err = json.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode folder items response: %w", err)
+		}
+
+		all.Entries = append(all.Entries, page.Entries...)
+		if page.NextMarker == "" || len(page.Entries) == 0 {
+			break
+		}
+		marker = page.NextMarker
+		logging.Debug("Fetching next page of Box folder %s items (%d so far)", folderID, len(all.Entries))
 	}
 
-	return &items, nil
+	all.TotalCount = len(all.Entries)
+	return all, nil
 }
 
 // FindZoomFolder finds the "zoom" folder in the root directory
 // This matches the behavior of the box-upload.sh script
 func (c *boxClient) FindZoomFolder() (string, error) {
-	url := fmt.Sprintf("%s/folders/0/items?fields=id,name,type&limit=1000", BoxAPIBaseURL)
-	resp, err := c.httpClient.Get(context.Background(), url)
+	items, err := c.ListFolderItems(RootFolderID)
 	if err != nil {
 		return "", fmt.Errorf("failed to list root folder items: %w", err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("failed to list root folder items, status: %d, body: %s", resp.StatusCode, string(body))
-	}
-
-	var items FolderItems
-	if err := json.NewDecoder(resp.Body).Decode(&items); err != nil {
-		return "", fmt.Errorf("failed to decode folder items response: %w", err)
-	}
 
 	// Search for the zoom folder
 	for _, item := range items.Entries {
@@ -479,76 +517,46 @@ func (c *boxClient) FindFileByName(folderID string, name string) (*File, error)
 // FindZoomFolderByOwner finds the "zoom" folder owned by a specific user
 // Searches the root directory for zoom folders and matches by owner email
 // Returns the full folder information if found, or a BoxError if not found
-// Supports pagination to handle cases where there are more than 1000 items in root
+// The root folder is listed completely, however many items it holds
 func (c *boxClient) FindZoomFolderByOwner(ownerEmail string) (*Folder, error) {
 	if strings.TrimSpace(ownerEmail) == "" {
 		return nil, fmt.Errorf("owner email cannot be empty")
 	}
 
+	if c.destinationFolderID != "" {
+		// Never creates the folder, so lookups by verify, diff and dry runs change nothing
+		return c.FindFolderByName(c.destinationFolderID, DestinationUserFolderName(ownerEmail))
+	}
+
 	ownerEmailLower := strings.ToLower(ownerEmail)
-	offset := 0
-	limit := 1000
 
 	logging.Info("Searching for zoom folder for owner: %s", ownerEmail)
 
-	// Paginate through all items in the root folder
-	for {
-		// List root folder items with owned_by field
-		apiURL := fmt.Sprintf("%s/folders/0/items?fields=id,name,type,owned_by&limit=%d&offset=%d", BoxAPIBaseURL, limit, offset)
-
-		logging.Debug("Fetching Box root folder items - offset: %d, limit: %d", offset, limit)
-
-		resp, err := c.httpClient.Get(context.Background(), apiURL)
-		if err != nil {
-			return nil, fmt.Errorf("failed to list root folder items: %w", err)
-		}
-
-		if resp.StatusCode != http.StatusOK {
-			body, _ := io.ReadAll(resp.Body)
-			resp.Body.Close()
-			return nil, fmt.Errorf("failed to list root folder items, status: %d, body: %s", resp.StatusCode, string(body))
-		}
-
-		var items FolderItems
-		if err := json.NewDecoder(resp.Body).Decode(&items); err != nil {
-			resp.Body.Close()
-			return nil, fmt.Errorf("failed to decode folder items response: %w", err)
-		}
-		resp.Body.Close()
-
-		logging.Debug("Retrieved %d items from Box root folder (offset: %d, total_count: %d)", len(items.Entries), offset, items.TotalCount)
+	items, err := c.ListFolderItems(RootFolderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list root folder items: %w", err)
+	}
 
-		// Search for zoom folder owned by the specified user (case-insensitive)
-		for _, item := range items.Entries {
-			if item.Type == ItemTypeFolder && item.Name == "zoom" {
-				// Check if owner matches
-				if item.OwnedBy != nil && strings.ToLower(item.OwnedBy.Login) == ownerEmailLower {
-					// Construct folder from item data to avoid unnecessary GetFolder call
-					// which can fail with 404 if parent folder information is unavailable
-					folder := &Folder{
-						ID:      item.ID,
-						Type:    item.Type,
-						Name:    item.Name,
-						OwnedBy: item.OwnedBy,
-					}
+	logging.Debug("Retrieved %d items from Box root folder", len(items.Entries))
 
-					logging.Info("Found zoom folder for %s - folder ID: %s", ownerEmail, folder.ID)
-					return folder, nil
+	// Search for zoom folder owned by the specified user (case-insensitive)
+	for _, item := range items.Entries {
+		if item.Type == ItemTypeFolder && item.Name == "zoom" {
+			// Check if owner matches
+			if item.OwnedBy != nil && strings.ToLower(item.OwnedBy.Login) == ownerEmailLower {
+				// Construct folder from item data to avoid unnecessary GetFolder call
+				// which can fail with 404 if parent folder information is unavailable
+				folder := &Folder{
+					ID:      item.ID,
+					Type:    item.Type,
+					Name:    item.Name,
+					OwnedBy: item.OwnedBy,
 				}
-			}
-		}
 
-		// Check if there are more items to fetch
-		// Stop if: no items returned OR we've fetched all items based on total count
-		if len(items.Entries) == 0 || offset+len(items.Entries) >= items.TotalCount {
-			// No more items to fetch
-			logging.Debug("Reached end of Box root folder items (total pages checked: %d, total items: %d)", (offset/limit)+1, items.TotalCount)
-			break
+				logging.Info("Found zoom folder for %s - folder ID: %s", ownerEmail, folder.ID)
+				return folder, nil
+			}
 		}
-
-		// Move to next page
-		offset += limit
-		logging.Debug("Moving to next page of Box root folder items")
 	}
 
 	// Zoom folder not found for this owner
@@ -586,6 +594,10 @@ func (c *boxClient) UploadFileWithProgress(filePath string, parentFolderID strin
 		return c.UploadLargeFile(filePath, parentFolderID, fileName, progressCallback)
 	}
 
+	if err := c.preflightUpload(fileName, parentFolderID, fileInfo.Size(), ""); err != nil {
+		return nil, err
+	}
+
 	// Use regular upload for smaller files
 	file, err := os.Open(filePath)
 	if err != nil {
@@ -715,6 +727,15 @@ func (c *boxClient) UploadFileAsUser(filePath string, parentFolderID string, fil
 		return nil, fmt.Errorf("failed to get file info: %w", err)
 	}
 
+	// Use chunked upload for files >= 20MB, as the user
+	if fileInfo.Size() >= MinChunkedUploadSize {
+		return c.UploadLargeFileAsUser(filePath, parentFolderID, fileName, userID, progressCallback)
+	}
+
+	if err := c.preflightUpload(fileName, parentFolderID, fileInfo.Size(), userID); err != nil {
+		return nil, err
+	}
+
 	var body bytes.Buffer
 	writer := multipart.NewWriter(&body)
 
@@ -841,6 +862,142 @@ func (c *boxClient) GetFile(fileID string) (*File, error) {
 	return &file, nil
 }
 
+// CreateSharedLink creates a shared link to fileID with the given access level, or updates the
+// access of its existing link, and returns the link
+func (c *boxClient) CreateSharedLink(fileID string, access string) (*SharedLink, error) {
+	if fileID == "" {
+		return nil, fmt.Errorf("file ID cannot be empty")
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"shared_link": map[string]string{"access": access},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal shared link request: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/files/%s?fields=shared_link", BoxAPIBaseURL, fileID)
+	req, err := http.NewRequestWithContext(context.Background(), "PUT", url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create shared link request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "zoom-to-box/1.0")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create shared link: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, &BoxError{
+			StatusCode: resp.StatusCode,
+			Code:       ErrorCodeItemNotFound,
+			Message:    fmt.Sprintf("file with ID '%s' not found", fileID),
+			Retryable:  false,
+		}
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to create shared link, status: %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	var file File
+	if err := json.NewDecoder(resp.Body).Decode(&file); err != nil {
+		return nil, fmt.Errorf("failed to decode shared link response: %w", err)
+	}
+	if file.SharedLink == nil || file.SharedLink.URL == "" {
+		return nil, fmt.Errorf("no shared link in response for file %s", fileID)
+	}
+
+	return file.SharedLink, nil
+}
+
+// ApplyMetadata attaches an instance of the metadata template scope/templateKey with values to
+// fileID. If the file already has an instance of the template, its values are updated.
+func (c *boxClient) ApplyMetadata(fileID string, scope string, templateKey string, values map[string]interface{}) error {
+	if fileID == "" {
+		return fmt.Errorf("file ID cannot be empty")
+	}
+	if templateKey == "" {
+		return fmt.Errorf("metadata template key cannot be empty")
+	}
+	if scope == "" {
+		scope = MetadataScopeEnterprise
+	}
+
+	url := fmt.Sprintf("%s/files/%s/metadata/%s/%s", BoxAPIBaseURL, fileID, scope, templateKey)
+	resp, err := c.httpClient.PostJSON(context.Background(), url, values)
+	if err != nil {
+		return fmt.Errorf("failed to apply metadata: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusCreated:
+		return nil
+	case http.StatusConflict:
+		// The file already has an instance of the template
+		return c.updateMetadata(url, values)
+	case http.StatusNotFound:
+		return &BoxError{
+			StatusCode: resp.StatusCode,
+			Code:       ErrorCodeItemNotFound,
+			Message:    fmt.Sprintf("file '%s' or metadata template '%s/%s' not found", fileID, scope, templateKey),
+			Retryable:  false,
+		}
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	return fmt.Errorf("failed to apply metadata, status: %d, body: %s", resp.StatusCode, string(body))
+}
+
+// updateMetadata sets values on an existing metadata instance with a JSON Patch
+// "add" replaces the value of keys that are already set.
+func (c *boxClient) updateMetadata(url string, values map[string]interface{}) error {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	operations := make([]map[string]interface{}, 0, len(keys))
+	for _, key := range keys {
+		operations = append(operations, map[string]interface{}{
+			"op":    "add",
+			"path":  "/" + key,
+			"value": values[key],
+		})
+	}
+	body, err := json.Marshal(operations)
+	if err != nil {
+		return fmt.Errorf("failed to marshal metadata update: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(context.Background(), "PUT", url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create metadata update request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json-patch+json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "zoom-to-box/1.0")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to update metadata: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to update metadata, status: %d, body: %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
+
 func (c *boxClient) DeleteFile(fileID string) error {
 	if fileID == "" {
 		return fmt.Errorf("file ID cannot be empty")
@@ -875,6 +1032,42 @@ func (c *boxClient) DeleteFile(fileID string) error {
 	return nil
 }
 
+// DeleteFolder deletes an empty folder
+// Box refuses to delete folders that still contain items, so files are never removed by accident.
+func (c *boxClient) DeleteFolder(folderID string) error {
+	if folderID == "" || folderID == RootFolderID {
+		return fmt.Errorf("folder ID cannot be empty or the root folder")
+	}
+
+	url := fmt.Sprintf("%s/folders/%s", BoxAPIBaseURL, folderID)
+	req, err := http.NewRequestWithContext(context.Background(), "DELETE", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create delete request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to delete folder: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return &BoxError{
+			StatusCode: resp.StatusCode,
+			Code:       ErrorCodeItemNotFound,
+			Message:    fmt.Sprintf("folder with ID '%s' not found", folderID),
+			Retryable:  false,
+		}
+	}
+
+	if resp.StatusCode != http.StatusNoContent {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to delete folder, status: %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	return nil
+}
+
 func CreateFolderPath(client BoxClient, folderPath string, parentID string) (*Folder, error) {
 	if folderPath == "" || folderPath == "/" {
 		if parentID == "" {
@@ -1090,6 +1283,12 @@ func ValidateFolderStructure(client BoxClient, folderPath string, parentID strin
 
 // CreateUploadSession creates a new chunked upload session
 func (c *boxClient) CreateUploadSession(fileName string, folderID string, fileSize int64) (*UploadSession, error) {
+	return c.createUploadSession(fileName, folderID, fileSize, "")
+}
+
+// createUploadSession creates a chunked upload session, as userID when it is set
+// Parts, commit and abort requests for the session must then be made as the same user.
+func (c *boxClient) createUploadSession(fileName string, folderID string, fileSize int64, userID string) (*UploadSession, error) {
 	if strings.TrimSpace(fileName) == "" {
 		return nil, fmt.Errorf("file name cannot be empty")
 	}
@@ -1107,7 +1306,13 @@ func (c *boxClient) CreateUploadSession(fileName string, folderID string, fileSi
 	}
 
 	url := fmt.Sprintf("%s/files/upload_sessions", BoxUploadBaseURL)
-	resp, err := c.httpClient.PostJSON(context.Background(), url, request)
+	var resp *http.Response
+	var err error
+	if userID != "" {
+		resp, err = c.httpClient.PostJSONAsUser(context.Background(), url, request, userID)
+	} else {
+		resp, err = c.httpClient.PostJSON(context.Background(), url, request)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to create upload session: %w", err)
 	}
@@ -1137,6 +1342,11 @@ func (c *boxClient) CreateUploadSession(fileName string, folderID string, fileSi
 
 // UploadPart uploads a single part of a chunked upload with retry logic
 func (c *boxClient) UploadPart(sessionID string, part []byte, offset int64, totalSize int64) (*UploadPart, error) {
+	return c.uploadPart(sessionID, part, offset, totalSize, "")
+}
+
+// uploadPart uploads a single part of a chunked upload, as userID when it is set
+func (c *boxClient) uploadPart(sessionID string, part []byte, offset int64, totalSize int64, userID string) (*UploadPart, error) {
 	if sessionID == "" {
 		return nil, fmt.Errorf("session ID cannot be empty")
 	}
@@ -1156,7 +1366,8 @@ func (c *boxClient) UploadPart(sessionID string, part []byte, offset int64, tota
 	contentRange := fmt.Sprintf("bytes %d-%d/%d", offset, rangeEnd, totalSize)
 
 	// Use retry logic for transient failures
-	maxRetries := 3
+	policy := c.uploadRetry.WithDefaults(retry.DefaultBoxUpload)
+	maxRetries := policy.MaxAttempts
 	var lastErr error
 	var uploadPart *UploadPart
 
@@ -1172,15 +1383,16 @@ func (c *boxClient) UploadPart(sessionID string, part []byte, offset int64, tota
 		req.Header.Set("Content-Type", "application/octet-stream")
 		req.Header.Set("Content-Range", contentRange)
 		req.Header.Set("Digest", digest)
+		if userID != "" {
+			req.Header.Set("As-User", userID)
+		}
 
 		resp, err := c.httpClient.Do(req)
 		if err != nil {
 			lastErr = err
 			// Check if error is retryable (network/timeout errors)
 			if isRetryableError(err) && attempt < maxRetries-1 {
-				// Exponential backoff: 500ms, 1s, 2s
-				backoff := 500 * (1 << attempt) * time.Millisecond
-				time.Sleep(backoff)
+				time.Sleep(policy.Backoff(attempt))
 				continue
 			}
 			return nil, fmt.Errorf("failed to upload part after %d attempts: %w", attempt+1, err)
@@ -1194,12 +1406,7 @@ func (c *boxClient) UploadPart(sessionID string, part []byte, offset int64, tota
 
 			// Retry on 5xx server errors and 429 rate limit
 			if (resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests) && attempt < maxRetries-1 {
-				backoff := 500 * (1 << attempt) * time.Millisecond
-				if resp.StatusCode == http.StatusTooManyRequests {
-					// Longer backoff for rate limits
-					backoff = 5 * time.Second
-				}
-				time.Sleep(backoff)
+				time.Sleep(retryAfter(resp, policy.Backoff(attempt)))
 				continue
 			}
 			return nil, lastErr
@@ -1293,6 +1500,11 @@ func validateUploadedParts(parts []UploadPartInfo, totalSize int64) error {
 
 // CommitUploadSession commits a chunked upload session
 func (c *boxClient) CommitUploadSession(sessionID string, parts []UploadPartInfo, attributes map[string]interface{}, digest string) (*File, error) {
+	return c.commitUploadSession(sessionID, parts, attributes, digest, "")
+}
+
+// commitUploadSession commits a chunked upload session, as userID when it is set
+func (c *boxClient) commitUploadSession(sessionID string, parts []UploadPartInfo, attributes map[string]interface{}, digest string, userID string) (*File, error) {
 	if sessionID == "" {
 		return nil, fmt.Errorf("session ID cannot be empty")
 	}
@@ -1324,6 +1536,9 @@ func (c *boxClient) CommitUploadSession(sessionID string, parts []UploadPartInfo
 
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Digest", digest)
+	if userID != "" {
+		req.Header.Set("As-User", userID)
+	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -1362,6 +1577,11 @@ func (c *boxClient) CommitUploadSession(sessionID string, parts []UploadPartInfo
 
 // AbortUploadSession aborts a chunked upload session
 func (c *boxClient) AbortUploadSession(sessionID string) error {
+	return c.abortUploadSession(sessionID, "")
+}
+
+// abortUploadSession aborts a chunked upload session, as userID when it is set
+func (c *boxClient) abortUploadSession(sessionID string, userID string) error {
 	if sessionID == "" {
 		return fmt.Errorf("session ID cannot be empty")
 	}
@@ -1371,6 +1591,9 @@ func (c *boxClient) AbortUploadSession(sessionID string) error {
 	if err != nil {
 		return fmt.Errorf("failed to create abort request: %w", err)
 	}
+	if userID != "" {
+		req.Header.Set("As-User", userID)
+	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -1389,25 +1612,47 @@ func (c *boxClient) AbortUploadSession(sessionID string) error {
 // calculateFileSHA1 computes the SHA-1 hash of an entire file
 // Returns the hash in the format "sha=<base64-encoded-hash>" as required by Box API
 func calculateFileSHA1(filePath string) (string, error) {
+	sha1Hash, err := fileSHA1Sum(filePath)
+	if err != nil {
+		return "", err
+	}
+
+	digest := "sha=" + base64.StdEncoding.EncodeToString(sha1Hash)
+	return digest, nil
+}
+
+// fileSHA1Sum returns the raw SHA-1 sum of an entire file
+func fileSHA1Sum(filePath string) ([]byte, error) {
 	file, err := os.Open(filePath)
 	if err != nil {
-		return "", fmt.Errorf("failed to open file: %w", err)
+		return nil, fmt.Errorf("failed to open file: %w", err)
 	}
 	defer file.Close()
 
 	h := sha1.New()
 	if _, err := io.Copy(h, file); err != nil {
-		return "", fmt.Errorf("failed to calculate SHA-1: %w", err)
+		return nil, fmt.Errorf("failed to calculate SHA-1: %w", err)
 	}
-
-	sha1Hash := h.Sum(nil)
-	digest := "sha=" + base64.StdEncoding.EncodeToString(sha1Hash)
-	return digest, nil
+	return h.Sum(nil), nil
 }
 
 // UploadLargeFile uploads a file using chunked upload API
 // This is a helper function that orchestrates the entire chunked upload process
 func (c *boxClient) UploadLargeFile(filePath string, parentFolderID string, fileName string, progressCallback ProgressCallback) (*File, error) {
+	return c.uploadLargeFile(filePath, parentFolderID, fileName, "", progressCallback)
+}
+
+// UploadLargeFileAsUser uploads a file using chunked upload API as a specific user
+// Every session request carries the As-User header, so the user owns the committed file.
+func (c *boxClient) UploadLargeFileAsUser(filePath string, parentFolderID string, fileName string, userID string, progressCallback ProgressCallback) (*File, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("user ID cannot be empty")
+	}
+	return c.uploadLargeFile(filePath, parentFolderID, fileName, userID, progressCallback)
+}
+
+// uploadLargeFile uploads a file using chunked upload API, as userID when it is set
+func (c *boxClient) uploadLargeFile(filePath string, parentFolderID string, fileName string, userID string, progressCallback ProgressCallback) (*File, error) {
 	if strings.TrimSpace(filePath) == "" {
 		return nil, fmt.Errorf("file path cannot be empty")
 	}
@@ -1432,6 +1677,10 @@ func (c *boxClient) UploadLargeFile(filePath string, parentFolderID string, file
 
 	totalSize := fileInfo.Size()
 
+	if err := c.preflightUpload(fileName, parentFolderID, totalSize, userID); err != nil {
+		return nil, err
+	}
+
 	// Calculate SHA-1 digest of entire file for commit
 	fileSHA1, err := calculateFileSHA1(filePath)
 	if err != nil {
@@ -1439,73 +1688,46 @@ func (c *boxClient) UploadLargeFile(filePath string, parentFolderID string, file
 	}
 
 	// Create upload session
-	session, err := c.CreateUploadSession(fileName, parentFolderID, totalSize)
+	session, err := c.createUploadSession(fileName, parentFolderID, totalSize, userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create upload session: %w", err)
 	}
 
-	// Track uploaded parts for commit
-	var uploadedParts []UploadPartInfo
-	var offset int64 = 0
-	partSize := session.PartSize
-	if partSize == 0 {
-		partSize = DefaultChunkSize
-	}
-
-	// Upload parts
-	buffer := make([]byte, partSize)
-	for offset < totalSize {
-		n, readErr := file.Read(buffer)
-		if n > 0 {
-			// Upload this part - make a copy to avoid buffer reuse issues
-			part := make([]byte, n)
-			copy(part, buffer[:n])
-
-			uploadPart, err := c.UploadPart(session.ID, part, offset, totalSize)
-			if err != nil {
-				// Abort session on error
-				_ = c.AbortUploadSession(session.ID)
-				return nil, fmt.Errorf("failed to upload part at offset %d: %w", offset, err)
-			}
-
-			// Track the uploaded part - always calculate SHA1 for validation
-			h := sha1.New()
-			h.Write(part)
-			sha1Hash := base64.StdEncoding.EncodeToString(h.Sum(nil))
-
-			partInfo := UploadPartInfo{
-				Offset: offset,
-				Size:   int64(n),
-				SHA1:   sha1Hash,
-			}
-
-			// Use Box-returned part info if available, otherwise use our calculated values
-			if uploadPart.Part != nil {
-				partInfo = *uploadPart.Part
-			}
+	return c.uploadToSession(file, session, totalSize, fileSHA1, userID, progressCallback)
+}
 
-			uploadedParts = append(uploadedParts, partInfo)
+// partSize returns the part size for session's parts
+// Box rejects parts of another size than the one it assigned, so the configured size only
+// applies to sessions without one.
+func (c *boxClient) partSize(session *UploadSession) int64 {
+	switch {
+	case session.PartSize > 0:
+		return session.PartSize
+	case c.uploadPartSize > 0:
+		return c.uploadPartSize
+	default:
+		return DefaultChunkSize
+	}
+}
 
-			offset += int64(n)
+// uploadToSession uploads all parts of file to an upload session and commits it
+// userID is the user the session was created as ("" = the authenticated account).
+func (c *boxClient) uploadToSession(file io.ReaderAt, session *UploadSession, totalSize int64, fileSHA1 string, userID string, progressCallback ProgressCallback) (*File, error) {
+	partSize := c.partSize(session)
 
-			// Report progress
-			if progressCallback != nil {
-				progressCallback(offset, totalSize)
-			}
-		}
+	c.sessions.add(session.ID, userID)
+	defer c.sessions.remove(session.ID)
 
-		if readErr == io.EOF {
-			break
-		}
-		if readErr != nil {
-			_ = c.AbortUploadSession(session.ID)
-			return nil, fmt.Errorf("failed to read file: %w", readErr)
-		}
+	// Upload parts concurrently; results are stored by index so commit order is preserved
+	uploadedParts, err := c.uploadPartsConcurrently(file, session.ID, partSize, totalSize, userID, progressCallback)
+	if err != nil {
+		_ = c.abortUploadSession(session.ID, userID)
+		return nil, err
 	}
 
 	// Validate uploaded parts before committing
 	if err := validateUploadedParts(uploadedParts, totalSize); err != nil {
-		_ = c.AbortUploadSession(session.ID)
+		_ = c.abortUploadSession(session.ID, userID)
 		return nil, fmt.Errorf("upload validation failed: %w", err)
 	}
 
@@ -1514,7 +1736,7 @@ func (c *boxClient) UploadLargeFile(filePath string, parentFolderID string, file
 	attributes := map[string]interface{}{}
 
 	// Commit the upload session with file metadata and digest
-	uploadedFile, err := c.CommitUploadSession(session.ID, uploadedParts, attributes, fileSHA1)
+	uploadedFile, err := c.commitUploadSession(session.ID, uploadedParts, attributes, fileSHA1, userID)
 	if err != nil {
 		// Don't abort on commit error - the session might still be processing
 		return nil, fmt.Errorf("failed to commit upload session: %w", err)
@@ -1526,4 +1748,213 @@ func (c *boxClient) UploadLargeFile(filePath string, parentFolderID string, file
 	}
 
 	return uploadedFile, nil
-}
\ No newline at end of file
+}
+
+// UploadFileVersion uploads filePath as a new version of the existing file fileID
+// Box keeps the previous content as an older version of the file.
+func (c *boxClient) UploadFileVersion(fileID string, filePath string, progressCallback ProgressCallback) (*File, error) {
+	if fileID == "" {
+		return nil, fmt.Errorf("file ID cannot be empty")
+	}
+	if strings.TrimSpace(filePath) == "" {
+		return nil, fmt.Errorf("file path cannot be empty")
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	fileInfo, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file info: %w", err)
+	}
+	totalSize := fileInfo.Size()
+
+	// Large versions use a chunked upload session on the existing file
+	if totalSize >= MinChunkedUploadSize {
+		fileSHA1, err := calculateFileSHA1(filePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to calculate file digest: %w", err)
+		}
+
+		url := fmt.Sprintf("%s/files/%s/upload_sessions", BoxUploadBaseURL, fileID)
+		resp, err := c.httpClient.PostJSON(context.Background(), url, map[string]interface{}{
+			"file_size": totalSize,
+			"file_name": filepath.Base(filePath),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create version upload session: %w", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusCreated {
+			body, _ := io.ReadAll(resp.Body)
+			return nil, fmt.Errorf("failed to create version upload session, status: %d, body: %s", resp.StatusCode, string(body))
+		}
+
+		var session UploadSession
+		if err := json.NewDecoder(resp.Body).Decode(&session); err != nil {
+			return nil, fmt.Errorf("failed to decode upload session response: %w", err)
+		}
+		return c.uploadToSession(file, &session, totalSize, fileSHA1, "", progressCallback)
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", filepath.Base(filePath))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create form file: %w", err)
+	}
+	if progressCallback != nil {
+		progressCallback(0, totalSize)
+	}
+	if _, err := io.Copy(part, file); err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close multipart writer: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/files/%s/content", BoxUploadBaseURL, fileID)
+	resp, err := c.httpClient.Post(context.Background(), url, writer.FormDataContentType(), &body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload file version: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, &BoxError{
+			StatusCode: resp.StatusCode,
+			Code:       ErrorCodeItemNotFound,
+			Message:    fmt.Sprintf("file with ID '%s' not found", fileID),
+			Retryable:  false,
+		}
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to upload file version, status: %d, body: %s", resp.StatusCode, string(respBody))
+	}
+
+	var uploadResponse struct {
+		TotalCount int     `json:"total_count"`
+		Entries    []*File `json:"entries"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&uploadResponse); err != nil {
+		return nil, fmt.Errorf("failed to decode upload response: %w", err)
+	}
+	if len(uploadResponse.Entries) == 0 {
+		return nil, fmt.Errorf("no file entries in upload response")
+	}
+
+	if progressCallback != nil {
+		progressCallback(totalSize, totalSize)
+	}
+	return uploadResponse.Entries[0], nil
+}
+
+// uploadPartsConcurrently uploads all parts of a file with at most
+// uploadConcurrency parts in flight. Each worker owns a single part-sized
+// buffer, so memory use is bounded by concurrency * partSize.
+func (c *boxClient) uploadPartsConcurrently(file io.ReaderAt, sessionID string, partSize int64, totalSize int64, userID string, progressCallback ProgressCallback) ([]UploadPartInfo, error) {
+	numParts := int((totalSize + partSize - 1) / partSize)
+	uploadedParts := make([]UploadPartInfo, numParts)
+
+	workers := c.partConcurrency()
+	if workers > numParts {
+		workers = numParts
+	}
+
+	var (
+		mu            sync.Mutex
+		firstErr      error
+		bytesUploaded int64
+		wg            sync.WaitGroup
+	)
+	partIndexes := make(chan int)
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			buffer := make([]byte, partSize)
+			for i := range partIndexes {
+				offset := int64(i) * partSize
+				size := partSize
+				if offset+size > totalSize {
+					size = totalSize - offset
+				}
+
+				partInfo, err := c.uploadPartAt(file, buffer[:size], sessionID, offset, totalSize, userID)
+
+				mu.Lock()
+				if err != nil {
+					if firstErr == nil {
+						firstErr = err
+					}
+					mu.Unlock()
+					continue
+				}
+				uploadedParts[i] = partInfo
+				bytesUploaded += size
+				if progressCallback != nil {
+					progressCallback(bytesUploaded, totalSize)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for i := 0; i < numParts; i++ {
+		mu.Lock()
+		failed := firstErr != nil
+		mu.Unlock()
+		if failed {
+			break
+		}
+		partIndexes <- i
+	}
+	close(partIndexes)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return uploadedParts, nil
+}
+
+// uploadPartAt reads len(buffer) bytes at offset and uploads them as a single part
+func (c *boxClient) uploadPartAt(file io.ReaderAt, buffer []byte, sessionID string, offset int64, totalSize int64, userID string) (UploadPartInfo, error) {
+	n, err := file.ReadAt(buffer, offset)
+	if err != nil && !(err == io.EOF && n == len(buffer)) {
+		return UploadPartInfo{}, fmt.Errorf("failed to read file at offset %d: %w", offset, err)
+	}
+	part := buffer[:n]
+
+	uploadPart, err := c.uploadPart(sessionID, part, offset, totalSize, userID)
+	if err != nil {
+		return UploadPartInfo{}, fmt.Errorf("failed to upload part at offset %d: %w", offset, err)
+	}
+
+	// Use Box-returned part info if available, otherwise use our calculated values
+	if uploadPart.Part != nil {
+		return *uploadPart.Part, nil
+	}
+
+	h := sha1.New()
+	h.Write(part)
+	return UploadPartInfo{
+		Offset: offset,
+		Size:   int64(n),
+		SHA1:   base64.StdEncoding.EncodeToString(h.Sum(nil)),
+	}, nil
+}
+
+// partConcurrency returns the number of parts to upload in parallel
+func (c *boxClient) partConcurrency() int {
+	if c.uploadConcurrency < 1 {
+		return 1
+	}
+	return c.uploadConcurrency
+}
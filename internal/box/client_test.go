@@ -7,8 +7,10 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -28,6 +30,18 @@ func newMockAuthenticatedHTTPClient() *mockAuthenticatedHTTPClient {
 	}
 }
 
+// folderItemsURL returns the URL the client requests for the page of folderID's items at marker
+func folderItemsURL(folderID, marker string) string {
+	query := url.Values{}
+	query.Set("fields", FolderItemFields)
+	query.Set("limit", strconv.Itoa(FolderItemsPageSize))
+	query.Set("usemarker", "true")
+	if marker != "" {
+		query.Set("marker", marker)
+	}
+	return fmt.Sprintf("%s/folders/%s/items?%s", BoxAPIBaseURL, folderID, query.Encode())
+}
+
 func (m *mockAuthenticatedHTTPClient) Do(req *http.Request) (*http.Response, error) {
 	m.requests = append(m.requests, req)
 	key := fmt.Sprintf("%s %s", req.Method, req.URL.String())
@@ -98,7 +112,7 @@ func TestBoxClient_FindZoomFolder(t *testing.T) {
 		{
 			name: "zoom folder found in root",
 			setupMock: func(m *mockAuthenticatedHTTPClient) {
-				m.setResponse("GET", BoxAPIBaseURL+"/folders/0/items?fields=id,name,type&limit=1000",
+				m.setResponse("GET", folderItemsURL("0", ""),
 					http.StatusOK,
 					`{
 						"total_count": 2,
@@ -114,7 +128,7 @@ func TestBoxClient_FindZoomFolder(t *testing.T) {
 		{
 			name: "zoom folder not found",
 			setupMock: func(m *mockAuthenticatedHTTPClient) {
-				m.setResponse("GET", BoxAPIBaseURL+"/folders/0/items?fields=id,name,type&limit=1000",
+				m.setResponse("GET", folderItemsURL("0", ""),
 					http.StatusOK,
 					`{
 						"total_count": 1,
@@ -130,7 +144,7 @@ func TestBoxClient_FindZoomFolder(t *testing.T) {
 		{
 			name: "API error",
 			setupMock: func(m *mockAuthenticatedHTTPClient) {
-				m.setResponse("GET", BoxAPIBaseURL+"/folders/0/items?fields=id,name,type&limit=1000",
+				m.setResponse("GET", folderItemsURL("0", ""),
 					http.StatusUnauthorized,
 					`{"message": "unauthorized"}`)
 			},
@@ -185,7 +199,7 @@ func TestBoxClient_FindFileByName(t *testing.T) {
 			folderID: "123",
 			fileName: "meeting-recording.mp4",
 			setupMock: func(m *mockAuthenticatedHTTPClient) {
-				m.setResponse("GET", BoxAPIBaseURL+"/folders/123/items",
+				m.setResponse("GET", folderItemsURL("123", ""),
 					http.StatusOK,
 					`{
 						"total_count": 3,
@@ -217,7 +231,7 @@ func TestBoxClient_FindFileByName(t *testing.T) {
 			folderID: "200",
 			fileName: "meeting-2024-01-15_10:30.mp4",
 			setupMock: func(m *mockAuthenticatedHTTPClient) {
-				m.setResponse("GET", BoxAPIBaseURL+"/folders/200/items",
+				m.setResponse("GET", folderItemsURL("200", ""),
 					http.StatusOK,
 					`{
 						"total_count": 2,
@@ -248,7 +262,7 @@ func TestBoxClient_FindFileByName(t *testing.T) {
 			folderID: "123",
 			fileName: "nonexistent.mp4",
 			setupMock: func(m *mockAuthenticatedHTTPClient) {
-				m.setResponse("GET", BoxAPIBaseURL+"/folders/123/items",
+				m.setResponse("GET", folderItemsURL("123", ""),
 					http.StatusOK,
 					`{
 						"total_count": 2,
@@ -275,7 +289,7 @@ func TestBoxClient_FindFileByName(t *testing.T) {
 			folderID: "999",
 			fileName: "test.mp4",
 			setupMock: func(m *mockAuthenticatedHTTPClient) {
-				m.setResponse("GET", BoxAPIBaseURL+"/folders/999/items",
+				m.setResponse("GET", folderItemsURL("999", ""),
 					http.StatusNotFound,
 					`{"message": "Not Found"}`)
 			},
@@ -287,7 +301,7 @@ func TestBoxClient_FindFileByName(t *testing.T) {
 			folderID: "123",
 			fileName: "test.mp4",
 			setupMock: func(m *mockAuthenticatedHTTPClient) {
-				m.setResponse("GET", BoxAPIBaseURL+"/folders/123/items",
+				m.setResponse("GET", folderItemsURL("123", ""),
 					http.StatusInternalServerError,
 					`{"message": "Internal Server Error"}`)
 			},
@@ -299,7 +313,7 @@ func TestBoxClient_FindFileByName(t *testing.T) {
 			folderID: "",
 			fileName: "readme.txt",
 			setupMock: func(m *mockAuthenticatedHTTPClient) {
-				m.setResponse("GET", BoxAPIBaseURL+"/folders/0/items",
+				m.setResponse("GET", folderItemsURL("0", ""),
 					http.StatusOK,
 					`{
 						"total_count": 1,
@@ -329,7 +343,7 @@ func TestBoxClient_FindFileByName(t *testing.T) {
 			folderID: "123",
 			fileName: "meeting.mp4",
 			setupMock: func(m *mockAuthenticatedHTTPClient) {
-				m.setResponse("GET", BoxAPIBaseURL+"/folders/123/items",
+				m.setResponse("GET", folderItemsURL("123", ""),
 					http.StatusOK,
 					`{
 						"total_count": 2,
@@ -397,7 +411,7 @@ func TestBoxClient_FindZoomFolderByOwner(t *testing.T) {
 			name:       "positive - zoom folder found for owner",
 			ownerEmail: "john.doe@company.com",
 			setupMock: func(m *mockAuthenticatedHTTPClient) {
-				m.setResponse("GET", BoxAPIBaseURL+"/folders/0/items?fields=id,name,type,owned_by&limit=1000",
+				m.setResponse("GET", folderItemsURL("0", ""),
 					http.StatusOK,
 					`{
 						"total_count": 3,
@@ -431,7 +445,7 @@ func TestBoxClient_FindZoomFolderByOwner(t *testing.T) {
 			name:       "positive - case insensitive email matching",
 			ownerEmail: "John.Doe@Company.COM",
 			setupMock: func(m *mockAuthenticatedHTTPClient) {
-				m.setResponse("GET", BoxAPIBaseURL+"/folders/0/items?fields=id,name,type,owned_by&limit=1000",
+				m.setResponse("GET", folderItemsURL("0", ""),
 					http.StatusOK,
 					`{
 						"total_count": 1,
@@ -463,7 +477,7 @@ func TestBoxClient_FindZoomFolderByOwner(t *testing.T) {
 			name:       "negative - no zoom folder for owner",
 			ownerEmail: "missing@company.com",
 			setupMock: func(m *mockAuthenticatedHTTPClient) {
-				m.setResponse("GET", BoxAPIBaseURL+"/folders/0/items?fields=id,name,type,owned_by&limit=1000",
+				m.setResponse("GET", folderItemsURL("0", ""),
 					http.StatusOK,
 					`{
 						"total_count": 2,
@@ -488,7 +502,7 @@ func TestBoxClient_FindZoomFolderByOwner(t *testing.T) {
 			name:       "negative - no zoom folders exist",
 			ownerEmail: "john.doe@company.com",
 			setupMock: func(m *mockAuthenticatedHTTPClient) {
-				m.setResponse("GET", BoxAPIBaseURL+"/folders/0/items?fields=id,name,type,owned_by&limit=1000",
+				m.setResponse("GET", folderItemsURL("0", ""),
 					http.StatusOK,
 					`{
 						"total_count": 1,
@@ -504,7 +518,7 @@ func TestBoxClient_FindZoomFolderByOwner(t *testing.T) {
 			name:       "negative - API error listing root items",
 			ownerEmail: "john.doe@company.com",
 			setupMock: func(m *mockAuthenticatedHTTPClient) {
-				m.setResponse("GET", BoxAPIBaseURL+"/folders/0/items?fields=id,name,type,owned_by&limit=1000",
+				m.setResponse("GET", folderItemsURL("0", ""),
 					http.StatusInternalServerError,
 					`{"message": "Internal Server Error"}`)
 			},
@@ -515,7 +529,7 @@ func TestBoxClient_FindZoomFolderByOwner(t *testing.T) {
 			name:       "negative - zoom folder with no owned_by field",
 			ownerEmail: "john.doe@company.com",
 			setupMock: func(m *mockAuthenticatedHTTPClient) {
-				m.setResponse("GET", BoxAPIBaseURL+"/folders/0/items?fields=id,name,type,owned_by&limit=1000",
+				m.setResponse("GET", folderItemsURL("0", ""),
 					http.StatusOK,
 					`{
 						"total_count": 1,
@@ -532,7 +546,7 @@ func TestBoxClient_FindZoomFolderByOwner(t *testing.T) {
 			ownerEmail: "john.doe@company.com",
 			setupMock: func(m *mockAuthenticatedHTTPClient) {
 				// First page - 1000 items, no match
-				m.setResponse("GET", BoxAPIBaseURL+"/folders/0/items?fields=id,name,type,owned_by&limit=1000&offset=0",
+				m.setResponse("GET", folderItemsURL("0", ""),
 					http.StatusOK,
 					`{
 						"total_count": 1500,
@@ -541,10 +555,11 @@ func TestBoxClient_FindZoomFolderByOwner(t *testing.T) {
 							{"id": "222", "type": "folder", "name": "zoom", "owned_by": {"id": "1001", "login": "jane.smith@company.com"}}
 						],
 						"offset": 0,
-						"limit": 1000
+						"limit": 1000,
+						"next_marker": "page-2"
 					}`)
 				// Second page - zoom folder found
-				m.setResponse("GET", BoxAPIBaseURL+"/folders/0/items?fields=id,name,type,owned_by&limit=1000&offset=1000",
+				m.setResponse("GET", folderItemsURL("0", "page-2"),
 					http.StatusOK,
 					`{
 						"total_count": 1500,
@@ -643,7 +658,7 @@ func TestBoxClient_FindZoomFolderByOwner_NoGetFolderCall(t *testing.T) {
 		"limit": 1000
 	}`
 
-	mockHTTPClient.setResponse("GET", "https://api.box.com/2.0/folders/0/items?fields=id,name,type,owned_by&limit=1000&offset=0", http.StatusOK, listResponse)
+	mockHTTPClient.setResponse("GET", folderItemsURL("0", ""), http.StatusOK, listResponse)
 
 	// Call FindZoomFolderByOwner
 	folder, err := client.FindZoomFolderByOwner("test@example.com")
@@ -930,8 +945,7 @@ func TestBoxClient_ListFolderItems(t *testing.T) {
 			}
 			
 			if tt.statusCode > 0 {
-				url := fmt.Sprintf("%s/folders/%s/items", BoxAPIBaseURL, folderID)
-				mockClient.setResponse("GET", url, tt.statusCode, tt.responseBody)
+				mockClient.setResponse("GET", folderItemsURL(folderID, ""), tt.statusCode, tt.responseBody)
 			}
 			
 			client := &boxClient{httpClient: mockClient}
@@ -1379,7 +1393,7 @@ func TestBoxClient_FindFolderByName(t *testing.T) {
 			parentID:   "123",
 			folderName: "zoom",
 			setupMock: func(m *mockAuthenticatedHTTPClient) {
-				m.setResponse("GET", BoxAPIBaseURL+"/folders/123/items",
+				m.setResponse("GET", folderItemsURL("123", ""),
 					http.StatusOK,
 					`{
 						"total_count": 3,
@@ -1411,7 +1425,7 @@ func TestBoxClient_FindFolderByName(t *testing.T) {
 			parentID:   "100",
 			folderName: "2024-01-15",
 			setupMock: func(m *mockAuthenticatedHTTPClient) {
-				m.setResponse("GET", BoxAPIBaseURL+"/folders/100/items",
+				m.setResponse("GET", folderItemsURL("100", ""),
 					http.StatusOK,
 					`{
 						"total_count": 2,
@@ -1440,7 +1454,7 @@ func TestBoxClient_FindFolderByName(t *testing.T) {
 			parentID:   "123",
 			folderName: "nonexistent",
 			setupMock: func(m *mockAuthenticatedHTTPClient) {
-				m.setResponse("GET", BoxAPIBaseURL+"/folders/123/items",
+				m.setResponse("GET", folderItemsURL("123", ""),
 					http.StatusOK,
 					`{
 						"total_count": 2,
@@ -1467,7 +1481,7 @@ func TestBoxClient_FindFolderByName(t *testing.T) {
 			parentID:   "999",
 			folderName: "zoom",
 			setupMock: func(m *mockAuthenticatedHTTPClient) {
-				m.setResponse("GET", BoxAPIBaseURL+"/folders/999/items",
+				m.setResponse("GET", folderItemsURL("999", ""),
 					http.StatusNotFound,
 					`{"message": "Not Found"}`)
 			},
@@ -1479,7 +1493,7 @@ func TestBoxClient_FindFolderByName(t *testing.T) {
 			parentID:   "123",
 			folderName: "zoom",
 			setupMock: func(m *mockAuthenticatedHTTPClient) {
-				m.setResponse("GET", BoxAPIBaseURL+"/folders/123/items",
+				m.setResponse("GET", folderItemsURL("123", ""),
 					http.StatusInternalServerError,
 					`{"message": "Internal Server Error"}`)
 			},
@@ -1491,7 +1505,7 @@ func TestBoxClient_FindFolderByName(t *testing.T) {
 			parentID:   "",
 			folderName: "zoom",
 			setupMock: func(m *mockAuthenticatedHTTPClient) {
-				m.setResponse("GET", BoxAPIBaseURL+"/folders/0/items",
+				m.setResponse("GET", folderItemsURL("0", ""),
 					http.StatusOK,
 					`{
 						"total_count": 1,
@@ -1519,7 +1533,7 @@ func TestBoxClient_FindFolderByName(t *testing.T) {
 			parentID:   "123",
 			folderName: "zoom",
 			setupMock: func(m *mockAuthenticatedHTTPClient) {
-				m.setResponse("GET", BoxAPIBaseURL+"/folders/123/items",
+				m.setResponse("GET", folderItemsURL("123", ""),
 					http.StatusOK,
 					`{
 						"total_count": 2,
@@ -1568,4 +1582,59 @@ func TestBoxClient_FindFolderByName(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+func TestBoxClient_CreateSharedLink(t *testing.T) {
+	mockClient := newMockAuthenticatedHTTPClient()
+	mockClient.setResponse("PUT", BoxAPIBaseURL+"/files/456?fields=shared_link", http.StatusOK, `{
+		"id": "456",
+		"type": "file",
+		"shared_link": {"url": "https://app.box.com/s/abc123", "access": "company"}
+	}`)
+	client := &boxClient{httpClient: mockClient}
+
+	link, err := client.CreateSharedLink("456", SharedLinkAccessCompany)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if link.URL != "https://app.box.com/s/abc123" || link.Access != "company" {
+		t.Errorf("Unexpected shared link %+v", link)
+	}
+
+	body, _ := io.ReadAll(mockClient.requests[0].Body)
+	if string(body) != `{"shared_link":{"access":"company"}}` {
+		t.Errorf("Unexpected request body %s", body)
+	}
+
+	if _, err := client.CreateSharedLink("missing", SharedLinkAccessCompany); err == nil {
+		t.Error("Expected error for a missing file")
+	}
+}
+
+func TestBoxClient_ApplyMetadata(t *testing.T) {
+	url := BoxAPIBaseURL + "/files/456/metadata/enterprise/zoomRecording"
+	values := map[string]interface{}{"meetingUuid": "abc==", "duration": 30}
+
+	mockClient := newMockAuthenticatedHTTPClient()
+	mockClient.setResponse("POST", url, http.StatusCreated, `{"$template": "zoomRecording"}`)
+	client := &boxClient{httpClient: mockClient}
+	if err := client.ApplyMetadata("456", "", "zoomRecording", values); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// An existing instance is updated with a JSON Patch
+	mockClient = newMockAuthenticatedHTTPClient()
+	mockClient.setResponse("POST", url, http.StatusConflict, `{"code": "tuple_already_exists"}`)
+	mockClient.setResponse("PUT", url, http.StatusOK, `{"$template": "zoomRecording"}`)
+	client = &boxClient{httpClient: mockClient}
+	if err := client.ApplyMetadata("456", MetadataScopeEnterprise, "zoomRecording", values); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(mockClient.requests) != 2 || mockClient.requests[1].Header.Get("Content-Type") != "application/json-patch+json" {
+		t.Fatalf("Expected a JSON Patch update after the conflict, got %d requests", len(mockClient.requests))
+	}
+	body, _ := io.ReadAll(mockClient.requests[1].Body)
+	expected := `[{"op":"add","path":"/duration","value":30},{"op":"add","path":"/meetingUuid","value":"abc=="}]`
+	if string(body) != expected {
+		t.Errorf("Expected patch %s, got %s", expected, body)
+	}
+}
@@ -14,6 +14,9 @@ type BoxConfig struct {
 	ClientSecret string `yaml:"client_secret" json:"client_secret"`
 	EnterpriseID string `yaml:"enterprise_id" json:"enterprise_id"`
 	FolderID     string `yaml:"folder_id" json:"folder_id"`
+
+	ClientSecretNext  string `yaml:"client_secret_next" json:"client_secret_next"`
+	UploadConcurrency int    `yaml:"upload_concurrency" json:"upload_concurrency"`
 }
 
 type Config interface {
@@ -35,9 +38,10 @@ func NewBoxClientFromConfig(config Config) (BoxClient, error) {
 	}
 
 	credentials := &OAuth2Credentials{
-		ClientID:     boxConfig.ClientID,
-		ClientSecret: boxConfig.ClientSecret,
-		EnterpriseID: boxConfig.EnterpriseID,
+		ClientID:         boxConfig.ClientID,
+		ClientSecret:     boxConfig.ClientSecret,
+		ClientSecretNext: boxConfig.ClientSecretNext,
+		EnterpriseID:     boxConfig.EnterpriseID,
 	}
 
 	httpClient := &http.Client{
@@ -45,7 +49,9 @@ func NewBoxClientFromConfig(config Config) (BoxClient, error) {
 	}
 
 	auth := NewOAuth2Authenticator(credentials, httpClient)
-	client := NewBoxClient(auth, httpClient)
+	client := NewBoxClientWithOptions(auth, httpClient, ClientOptions{
+		UploadConcurrency: boxConfig.UploadConcurrency,
+	})
 
 	return client, nil
 }
@@ -106,9 +112,10 @@ func CreateBoxClientWithCredentialsCallback(config Config, saveCredentials func(
 	}
 
 	credentials := &OAuth2Credentials{
-		ClientID:     boxConfig.ClientID,
-		ClientSecret: boxConfig.ClientSecret,
-		EnterpriseID: boxConfig.EnterpriseID,
+		ClientID:         boxConfig.ClientID,
+		ClientSecret:     boxConfig.ClientSecret,
+		ClientSecretNext: boxConfig.ClientSecretNext,
+		EnterpriseID:     boxConfig.EnterpriseID,
 	}
 
 	httpClient := &http.Client{
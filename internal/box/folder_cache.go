@@ -0,0 +1,102 @@
+package box
+
+import (
+	"path"
+	"strings"
+	"sync"
+)
+
+// FolderCache remembers the folders found or created in Box during a run, so uploads after
+// the first of each day skip listing the root, zoom and date folders again
+// Only successful lookups are cached. A nil *FolderCache caches nothing.
+type FolderCache struct {
+	mu      sync.Mutex
+	folders map[string]*Folder // Folder by parent ID and path, e.g. "123/2024/01", and zoom folder by "owner:<email>"
+}
+
+// FolderCacher is implemented by upload managers that keep a run-scoped FolderCache
+type FolderCacher interface {
+	FolderCache() *FolderCache
+}
+
+// NewFolderCache creates an empty folder cache
+func NewFolderCache() *FolderCache {
+	return &FolderCache{folders: make(map[string]*Folder)}
+}
+
+// FindZoomFolderByOwner returns client.FindZoomFolderByOwner(ownerEmail), looking each owner
+// up only once
+func (c *FolderCache) FindZoomFolderByOwner(client BoxClient, ownerEmail string) (*Folder, error) {
+	if c == nil {
+		return client.FindZoomFolderByOwner(ownerEmail)
+	}
+	key := "owner:" + strings.ToLower(ownerEmail)
+	if folder, ok := c.get(key); ok {
+		return folder, nil
+	}
+
+	folder, err := client.FindZoomFolderByOwner(ownerEmail)
+	if err != nil {
+		return nil, err
+	}
+	c.put(key, folder)
+	return folder, nil
+}
+
+// CreateFolderPath returns CreateFolderPath(client, folderPath, parentID), listing or creating
+// each folder on the path only once
+func (c *FolderCache) CreateFolderPath(client BoxClient, folderPath string, parentID string) (*Folder, error) {
+	if c == nil {
+		return CreateFolderPath(client, folderPath, parentID)
+	}
+	if parentID == "" {
+		parentID = RootFolderID
+	}
+
+	// Resolve the longest cached prefix, then each remaining folder on its own
+	parts := strings.FieldsFunc(folderPath, func(r rune) bool { return r == '/' })
+	if len(parts) == 0 {
+		return CreateFolderPath(client, folderPath, parentID)
+	}
+	var folder *Folder
+	currentID := parentID
+	for i, part := range parts {
+		key := parentID + "/" + path.Join(parts[:i+1]...)
+		cached, ok := c.get(key)
+		if !ok {
+			created, err := CreateFolderPath(client, part, currentID)
+			if err != nil {
+				return nil, err
+			}
+			c.put(key, created)
+			cached = created
+		}
+		folder, currentID = cached, cached.ID
+	}
+	return folder, nil
+}
+
+// Reset forgets every folder, e.g. after empty folders were deleted
+func (c *FolderCache) Reset() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.folders = make(map[string]*Folder)
+}
+
+// get returns the folder cached under key
+func (c *FolderCache) get(key string) (*Folder, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	folder, ok := c.folders[key]
+	return folder, ok
+}
+
+// put caches folder under key
+func (c *FolderCache) put(key string, folder *Folder) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.folders[key] = folder
+}
@@ -3,7 +3,10 @@ package box
 
 import (
 	"fmt"
+	"io"
 	"time"
+
+	"github.com/curtbushko/zoom-to-box/internal/errclass"
 )
 
 // BoxClient defines the interface for Box API operations
@@ -19,20 +22,29 @@ type BoxClient interface {
 	// Folder operations
 	CreateFolder(name string, parentID string) (*Folder, error)
 	CreateFolderAsUser(name string, parentID string, userID string) (*Folder, error)
+	AddFolderCollaboratorAsUser(folderID string, collaboratorID string, role string, userID string) error
 	GetFolder(folderID string) (*Folder, error)
 	ListFolderItems(folderID string) (*FolderItems, error)
 	ListFolderItemsAsUser(folderID string, userID string) (*FolderItems, error)
 	FindZoomFolder() (string, error)
 	FindFolderByName(parentID string, name string) (*Folder, error)
 	FindZoomFolderByOwner(ownerEmail string) (*Folder, error)
+	DeleteFolder(folderID string) error
 
 	// File operations
 	UploadFile(filePath string, parentFolderID string, fileName string) (*File, error)
 	UploadFileWithProgress(filePath string, parentFolderID string, fileName string, progressCallback ProgressCallback) (*File, error)
 	UploadFileAsUser(filePath string, parentFolderID string, fileName string, userID string, progressCallback ProgressCallback) (*File, error)
+	UploadStream(r io.Reader, size int64, parentFolderID string, fileName string, progressCallback ProgressCallback) (*File, error)
+	UploadStreamAsUser(r io.Reader, size int64, parentFolderID string, fileName string, userID string, progressCallback ProgressCallback) (*File, error)
 	GetFile(fileID string) (*File, error)
 	DeleteFile(fileID string) error
 	FindFileByName(folderID string, name string) (*File, error)
+	SearchFiles(name string, ancestorFolderID string) ([]*File, error)
+	SearchFilesAsUser(name string, ancestorFolderID string, userID string) ([]*File, error)
+	UploadFileVersion(fileID string, filePath string, progressCallback ProgressCallback) (*File, error)
+	CreateSharedLink(fileID string, access string) (*SharedLink, error)
+	ApplyMetadata(fileID string, scope string, templateKey string, values map[string]interface{}) error
 
 	// Chunked upload operations (for files >= 20MB)
 	CreateUploadSession(fileName string, folderID string, fileSize int64) (*UploadSession, error)
@@ -46,15 +58,16 @@ type ProgressCallback func(bytesUploaded int64, totalBytes int64)
 
 // OAuth2Credentials represents Box OAuth 2.0 credentials
 type OAuth2Credentials struct {
-	ClientID     string    `json:"client_id"`
-	ClientSecret string    `json:"client_secret"`
-	EnterpriseID string    `json:"enterprise_id,omitempty"`
-	AccessToken  string    `json:"access_token"`
-	RefreshToken string    `json:"refresh_token"`
-	TokenType    string    `json:"token_type"`
-	ExpiresIn    int       `json:"expires_in"`
-	Scope        string    `json:"scope"`
-	ExpiresAt    time.Time `json:"expires_at"`
+	ClientID         string    `json:"client_id"`
+	ClientSecret     string    `json:"client_secret"`
+	ClientSecretNext string    `json:"client_secret_next,omitempty"` // Fallback secret during rotation
+	EnterpriseID     string    `json:"enterprise_id,omitempty"`
+	AccessToken      string    `json:"access_token"`
+	RefreshToken     string    `json:"refresh_token"`
+	TokenType        string    `json:"token_type"`
+	ExpiresIn        int       `json:"expires_in"`
+	Scope            string    `json:"scope"`
+	ExpiresAt        time.Time `json:"expires_at"`
 }
 
 // IsExpired returns true if the access token is expired or will expire soon
@@ -89,6 +102,8 @@ type Folder struct {
 	CanDelete         bool      `json:"can_delete"`
 	CanShare          bool      `json:"can_share"`
 	CanSetShareAccess bool      `json:"can_set_share_access"`
+
+	Existed bool `json:"-"` // Set by CreateFolder when the folder already existed (409 conflict)
 }
 
 // File represents a Box file
@@ -125,6 +140,15 @@ type File struct {
 	CanSetShareAccess  bool      `json:"can_set_share_access"`
 	SHA1               string    `json:"sha1"`
 	FileVersion        *FileVersion `json:"file_version,omitempty"`
+	SharedLink         *SharedLink  `json:"shared_link,omitempty"`
+}
+
+// SharedLink represents the shared link of a Box file
+type SharedLink struct {
+	URL             string `json:"url"`
+	DownloadURL     string `json:"download_url,omitempty"`
+	Access          string `json:"access"`                     // SharedLinkAccessOpen, SharedLinkAccessCompany or SharedLinkAccessCollaborators
+	EffectiveAccess string `json:"effective_access,omitempty"` // Access after enterprise policies are applied
 }
 
 // FileVersion represents a Box file version
@@ -183,6 +207,7 @@ type FolderItems struct {
 	Entries    []Item `json:"entries"`
 	Offset     int    `json:"offset"`
 	Limit      int    `json:"limit"`
+	NextMarker string `json:"next_marker,omitempty"` // Marker of the next page with marker-based pagination ("" = last page)
 }
 
 // CreateFolderRequest represents the request to create a folder
@@ -304,6 +329,21 @@ func (e *BoxError) IsRetryable() bool {
 	return e.Retryable
 }
 
+// ErrorClass classifies the error by its Box error code, then by its HTTP status
+func (e *BoxError) ErrorClass() errclass.Class {
+	switch e.Code {
+	case ErrorCodeUnauthorized, ErrorCodeInvalidGrant, ErrorCodeInsufficientScope:
+		return errclass.Auth
+	case ErrorCodeRateLimitExceeded:
+		return errclass.RateLimit
+	case ErrorCodeItemNotFound:
+		return errclass.NotFound
+	case ErrorCodeStorageLimitExceeded, ErrorCodeFileSizeLimitExceeded:
+		return errclass.Quota
+	}
+	return errclass.FromStatus(e.StatusCode)
+}
+
 // Common Box API constants
 const (
 	// API endpoints
@@ -315,13 +355,33 @@ const (
 	// Folder IDs
 	RootFolderID = "0"
 
+	// Folder listings
+	FolderItemFields    = "id,type,name,size,etag,sequence_id,owned_by" // Fields requested for every listed item
+	FolderItemsPageSize = 1000                                          // Items per page (Box maximum)
+
 	// Item types
 	ItemTypeFile   = "file"
 	ItemTypeFolder = "folder"
 
 	// Upload limits
-	MinChunkedUploadSize = 20 * 1024 * 1024 // 20MB minimum for chunked uploads
-	DefaultChunkSize     = 8 * 1024 * 1024  // 8MB default chunk size
+	MinChunkedUploadSize     = 20 * 1024 * 1024 // 20MB minimum for chunked uploads
+	DefaultChunkSize         = 8 * 1024 * 1024  // 8MB default chunk size
+	DefaultUploadConcurrency = 4                // Default number of parts uploaded in parallel
+
+	// Shared link access levels
+	SharedLinkAccessOpen          = "open"          // Anyone with the link
+	SharedLinkAccessCompany       = "company"       // People in the enterprise
+	SharedLinkAccessCollaborators = "collaborators" // Collaborators on the file only
+
+	// Collaboration roles
+	CollaborationRoleEditor = "editor" // Can upload, download, edit and delete items
+
+	// Name of each user's top-level folder that recordings are uploaded into
+	ZoomFolderName = "zoom"
+
+	// Metadata template scopes
+	MetadataScopeEnterprise = "enterprise" // Templates defined by the enterprise's admins
+	MetadataScopeGlobal     = "global"     // Box's own templates, e.g. properties
 
 	// OAuth scopes
 	ScopeBaseExplorer = "base_explorer"
@@ -330,11 +390,16 @@ const (
 	ScopeBasePreview  = "base_preview"
 
 	// Error codes
-	ErrorCodeItemNotFound      = "not_found"
-	ErrorCodeItemNameTaken     = "item_name_taken"
-	ErrorCodeItemNameInvalid   = "item_name_invalid"
-	ErrorCodeInsufficientScope = "insufficient_scope"
-	ErrorCodeInvalidGrant      = "invalid_grant"
-	ErrorCodeUnauthorized      = "unauthorized"
-	ErrorCodeRateLimitExceeded = "rate_limit_exceeded"
+	ErrorCodeItemNotFound          = "not_found"
+	ErrorCodeItemNameTaken         = "item_name_taken"
+	ErrorCodeItemNameInvalid       = "item_name_invalid"
+	ErrorCodeInsufficientScope     = "insufficient_scope"
+	ErrorCodeInvalidGrant          = "invalid_grant"
+	ErrorCodeUnauthorized          = "unauthorized"
+	ErrorCodeRateLimitExceeded     = "rate_limit_exceeded"
+	ErrorCodeStorageLimitExceeded  = "storage_limit_exceeded"
+	ErrorCodeFileSizeLimitExceeded = "file_size_limit_exceeded"
+
+	// Returned when inviting someone who already collaborates on the item
+	ErrorCodeUserAlreadyCollaborator = "user_already_collaborator"
 )
\ No newline at end of file
@@ -0,0 +1,91 @@
+package box
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// preflightRequest is the body of the upload preflight check
+type preflightRequest struct {
+	Name   string        `json:"name"`
+	Parent *FolderParent `json:"parent"`
+	Size   int64         `json:"size"`
+}
+
+// preflightUpload asks Box whether fileName of size bytes can be uploaded into parentFolderID
+// before any content is sent, so a name conflict or a full account fails in one small request
+// instead of after streaming the whole file.
+// Name conflicts and storage or file size limits are returned as *BoxError. Any other preflight
+// failure is ignored and left for the upload itself to report. userID uploads As-User ("" = none).
+func (c *boxClient) preflightUpload(fileName, parentFolderID string, size int64, userID string) error {
+	body, err := json.Marshal(preflightRequest{
+		Name:   fileName,
+		Parent: &FolderParent{ID: parentFolderID},
+		Size:   size,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal preflight request: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/files/content", BoxAPIBaseURL)
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodOptions, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create preflight request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "zoom-to-box/1.0")
+	if userID != "" {
+		req.Header.Set("As-User", userID)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusConflict && resp.StatusCode != http.StatusForbidden {
+		return nil
+	}
+
+	var errResp ErrorResponse
+	_ = json.NewDecoder(resp.Body).Decode(&errResp)
+
+	if resp.StatusCode == http.StatusConflict {
+		message := fmt.Sprintf("file '%s' already exists in folder", fileName)
+		if len(errResp.ContextInfo.Conflicts) > 0 && errResp.ContextInfo.Conflicts[0].ID != "" {
+			message += fmt.Sprintf(" (file ID %s)", errResp.ContextInfo.Conflicts[0].ID)
+		}
+		return &BoxError{
+			StatusCode: resp.StatusCode,
+			Code:       ErrorCodeItemNameTaken,
+			Message:    message,
+			RequestID:  errResp.RequestID,
+			Retryable:  false,
+		}
+	}
+
+	switch errResp.Code {
+	case ErrorCodeStorageLimitExceeded:
+		return &BoxError{
+			StatusCode: resp.StatusCode,
+			Code:       errResp.Code,
+			Message:    fmt.Sprintf("not enough Box storage to upload '%s' (%d bytes)", fileName, size),
+			RequestID:  errResp.RequestID,
+			Retryable:  false,
+		}
+	case ErrorCodeFileSizeLimitExceeded:
+		return &BoxError{
+			StatusCode: resp.StatusCode,
+			Code:       errResp.Code,
+			Message:    fmt.Sprintf("'%s' (%d bytes) exceeds the Box file size limit", fileName, size),
+			RequestID:  errResp.RequestID,
+			Retryable:  false,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package box
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBoxClient_UploadPreflight(t *testing.T) {
+	testFile := filepath.Join(t.TempDir(), "meeting.mp4")
+	if err := os.WriteFile(testFile, []byte("recording"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		statusCode    int
+		responseBody  string
+		expectedCode  string
+		errorContains string
+	}{
+		{
+			name:          "name conflict",
+			statusCode:    http.StatusConflict,
+			responseBody:  `{"type": "error", "status": 409, "code": "item_name_in_use", "context_info": {"conflicts": [{"id": "789", "type": "file", "name": "meeting.mp4"}]}}`,
+			expectedCode:  ErrorCodeItemNameTaken,
+			errorContains: "file 'meeting.mp4' already exists in folder (file ID 789)",
+		},
+		{
+			name:          "storage limit",
+			statusCode:    http.StatusForbidden,
+			responseBody:  `{"type": "error", "status": 403, "code": "storage_limit_exceeded"}`,
+			expectedCode:  ErrorCodeStorageLimitExceeded,
+			errorContains: "not enough Box storage",
+		},
+		{
+			name:          "file size limit",
+			statusCode:    http.StatusForbidden,
+			responseBody:  `{"type": "error", "status": 403, "code": "file_size_limit_exceeded"}`,
+			expectedCode:  ErrorCodeFileSizeLimitExceeded,
+			errorContains: "exceeds the Box file size limit",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := newMockAuthenticatedHTTPClient()
+			mockClient.setResponse("OPTIONS", BoxAPIBaseURL+"/files/content", tt.statusCode, tt.responseBody)
+			client := &boxClient{httpClient: mockClient}
+
+			_, err := client.UploadFile(testFile, "123", "")
+			var boxErr *BoxError
+			if !errors.As(err, &boxErr) || boxErr.Code != tt.expectedCode {
+				t.Fatalf("Expected a %s error, got %v", tt.expectedCode, err)
+			}
+			if !strings.Contains(err.Error(), tt.errorContains) {
+				t.Errorf("Expected error containing %q, got %q", tt.errorContains, err.Error())
+			}
+			for _, req := range mockClient.requests {
+				if req.Method == http.MethodPost {
+					t.Errorf("Expected no upload after a failed preflight, got %s %s", req.Method, req.URL)
+				}
+			}
+		})
+	}
+}
+
+func TestBoxClient_UploadPreflightPasses(t *testing.T) {
+	testFile := filepath.Join(t.TempDir(), "meeting.mp4")
+	if err := os.WriteFile(testFile, []byte("recording"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	mockClient := newMockAuthenticatedHTTPClient()
+	mockClient.setResponse("OPTIONS", BoxAPIBaseURL+"/files/content", http.StatusOK, `{"upload_url": "https://upload.box.com/api/2.0/files/content"}`)
+	mockClient.setResponse("POST", BoxUploadBaseURL+"/files/content", http.StatusCreated, `{"total_count": 1, "entries": [{"id": "456", "type": "file", "name": "meeting.mp4"}]}`)
+	client := &boxClient{httpClient: mockClient}
+
+	file, err := client.UploadFileAsUser(testFile, "123", "", "42", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if file.ID != "456" {
+		t.Errorf("Expected file 456, got %s", file.ID)
+	}
+	if len(mockClient.requests) != 2 || mockClient.requests[0].Method != http.MethodOptions {
+		t.Fatalf("Expected a preflight before the upload, got %d requests", len(mockClient.requests))
+	}
+	if mockClient.requests[0].Header.Get("As-User") != "42" {
+		t.Errorf("Expected the preflight to run As-User, got %q", mockClient.requests[0].Header.Get("As-User"))
+	}
+}
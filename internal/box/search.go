@@ -0,0 +1,122 @@
+package box
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// searchPageSize is the number of search results requested per page (Box maximum: 200)
+const searchPageSize = 100
+
+// SearchFiles finds the files named name anywhere below ancestorFolderID with the Box search API
+// Search results can lag a few minutes behind uploads, so a file that is not found may still exist.
+func (c *boxClient) SearchFiles(name string, ancestorFolderID string) ([]*File, error) {
+	return c.searchFiles(name, ancestorFolderID, "")
+}
+
+// SearchFilesAsUser finds the files named name below ancestorFolderID as a specific user
+func (c *boxClient) SearchFilesAsUser(name string, ancestorFolderID string, userID string) ([]*File, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("user ID cannot be empty")
+	}
+	return c.searchFiles(name, ancestorFolderID, userID)
+}
+
+// searchFiles searches file names below ancestorFolderID, as userID when it is set, and returns
+// the files whose name is exactly name
+func (c *boxClient) searchFiles(name string, ancestorFolderID string, userID string) ([]*File, error) {
+	if strings.TrimSpace(name) == "" {
+		return nil, fmt.Errorf("file name cannot be empty")
+	}
+	if ancestorFolderID == "" {
+		ancestorFolderID = RootFolderID
+	}
+
+	var matches []*File
+	for offset := 0; ; offset += searchPageSize {
+		query := url.Values{}
+		query.Set("query", `"`+name+`"`) // Quoted for an exact phrase match
+		query.Set("type", ItemTypeFile)
+		query.Set("content_types", "name")
+		query.Set("ancestor_folder_ids", ancestorFolderID)
+		query.Set("fields", "id,type,name,size,path_collection")
+		query.Set("limit", strconv.Itoa(searchPageSize))
+		query.Set("offset", strconv.Itoa(offset))
+		apiURL := fmt.Sprintf("%s/search?%s", BoxAPIBaseURL, query.Encode())
+
+		var resp *http.Response
+		var err error
+		if userID != "" {
+			resp, err = c.httpClient.GetAsUser(context.Background(), apiURL, userID)
+		} else {
+			resp, err = c.httpClient.Get(context.Background(), apiURL)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to search files: %w", err)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			return nil, fmt.Errorf("failed to search files, status: %d, body: %s", resp.StatusCode, string(body))
+		}
+
+		var page struct {
+			TotalCount int     `json:"total_count"`
+			Entries    []*File `json:"entries"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode search response: %w", err)
+		}
+
+		for _, file := range page.Entries {
+			if file.Type == ItemTypeFile && file.Name == name {
+				matches = append(matches, file)
+			}
+		}
+		if len(page.Entries) == 0 || offset+len(page.Entries) >= page.TotalCount {
+			return matches, nil
+		}
+	}
+}
+
+// FileInFolderPath reports whether file, as returned with its path_collection, is directly in
+// folderPath below the folder rootFolderID
+func FileInFolderPath(file *File, rootFolderID string, folderPath string) bool {
+	if file.PathCollection == nil {
+		return false
+	}
+
+	var parts []string
+	for _, part := range strings.Split(strings.Trim(folderPath, "/"), "/") {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+
+	ancestors := file.PathCollection.Entries
+	for i, ancestor := range ancestors {
+		if ancestor.ID != rootFolderID {
+			continue
+		}
+		below := ancestors[i+1:]
+		if len(below) != len(parts) {
+			return false
+		}
+		for j, folder := range below {
+			if folder.Name != parts[j] {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
@@ -0,0 +1,75 @@
+package box
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// UploadSessionAborter is implemented by clients that track their open chunked upload sessions,
+// so an interrupted run can abort them instead of leaving them on Box until they expire
+type UploadSessionAborter interface {
+	// AbortOpenUploadSessions aborts every chunked upload session still in progress and returns how many were aborted
+	AbortOpenUploadSessions() (int, error)
+}
+
+// openSessions is the set of chunked upload sessions a client has created but not finished
+type openSessions struct {
+	mu  sync.Mutex
+	ids map[string]string // As-User ID the session was created as, by session ID ("" = the client's account)
+}
+
+// add records a session created as userID as open
+func (s *openSessions) add(id, userID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ids == nil {
+		s.ids = make(map[string]string)
+	}
+	s.ids[id] = userID
+}
+
+// remove records a session as finished
+func (s *openSessions) remove(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.ids, id)
+}
+
+// list returns the open sessions as a map of session ID to the user ID they were created as
+func (s *openSessions) list() map[string]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids := make(map[string]string, len(s.ids))
+	for id, userID := range s.ids {
+		ids[id] = userID
+	}
+	return ids
+}
+
+// AbortOpenUploadSessions aborts the chunked upload sessions still in progress
+// Parts still being uploaded to an aborted session fail, which ends that upload.
+func (c *boxClient) AbortOpenUploadSessions() (int, error) {
+	var errs []error
+	aborted := 0
+	for id, userID := range c.sessions.list() {
+		if err := c.abortUploadSession(id, userID); err != nil {
+			errs = append(errs, fmt.Errorf("session %s: %w", id, err))
+			continue
+		}
+		c.sessions.remove(id)
+		aborted++
+	}
+	return aborted, errors.Join(errs...)
+}
+
+// AbortOpenUploadSessions aborts the wrapped client's open upload sessions, if it tracks them
+func (c *FolderTrackingClient) AbortOpenUploadSessions() (int, error) {
+	if aborter, ok := c.BoxClient.(UploadSessionAborter); ok {
+		return aborter.AbortOpenUploadSessions()
+	}
+	return 0, nil
+}
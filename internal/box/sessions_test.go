@@ -0,0 +1,45 @@
+package box
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestBoxClient_AbortOpenUploadSessions(t *testing.T) {
+	httpClient := newMockAuthenticatedHTTPClient()
+	client := &boxClient{httpClient: httpClient}
+
+	abortURL := BoxUploadBaseURL + "/files/upload_sessions/session-1"
+	httpClient.responses["DELETE "+abortURL] = []*http.Response{
+		{StatusCode: http.StatusNoContent, Body: io.NopCloser(strings.NewReader(""))},
+	}
+
+	client.sessions.add("session-1", "user-1")
+	client.sessions.add("session-2", "")
+	client.sessions.remove("session-2")
+
+	aborted, err := client.AbortOpenUploadSessions()
+	if err != nil {
+		t.Fatalf("AbortOpenUploadSessions failed: %v", err)
+	}
+	if aborted != 1 {
+		t.Errorf("Expected 1 aborted session, got %d", aborted)
+	}
+	if len(httpClient.requests) != 1 || httpClient.requests[0].URL.String() != abortURL {
+		t.Errorf("Expected a single abort request for session-1, got %v", httpClient.requests)
+	}
+	if len(httpClient.requests) == 1 && httpClient.requests[0].Header.Get("As-User") != "user-1" {
+		t.Errorf("Expected the session to be aborted as the user it was created as, got As-User %q",
+			httpClient.requests[0].Header.Get("As-User"))
+	}
+	if open := client.sessions.list(); len(open) != 0 {
+		t.Errorf("Expected no open sessions after aborting, got %v", open)
+	}
+
+	// Wrapped clients forward to the Box client
+	if aborted, err := NewFolderTrackingClient(client).AbortOpenUploadSessions(); err != nil || aborted != 0 {
+		t.Errorf("Expected nothing left to abort through the wrapper, got %d, %v", aborted, err)
+	}
+}
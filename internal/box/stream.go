@@ -0,0 +1,103 @@
+package box
+
+import (
+	"crypto/sha1"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// UploadStream uploads size bytes read from r with a chunked upload session, without a local file
+// Only one part is held in memory at a time. The file must be at least MinChunkedUploadSize.
+func (c *boxClient) UploadStream(r io.Reader, size int64, parentFolderID string, fileName string, progressCallback ProgressCallback) (*File, error) {
+	return c.uploadStream(r, size, parentFolderID, fileName, "", progressCallback)
+}
+
+// UploadStreamAsUser uploads a stream with a chunked upload session as a specific user
+func (c *boxClient) UploadStreamAsUser(r io.Reader, size int64, parentFolderID string, fileName string, userID string, progressCallback ProgressCallback) (*File, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("user ID cannot be empty")
+	}
+	return c.uploadStream(r, size, parentFolderID, fileName, userID, progressCallback)
+}
+
+// uploadStream uploads r part by part, as userID when it is set
+// Parts are uploaded in order since the stream cannot be read at an offset; the SHA-1 of the
+// whole file is computed on the way and sent with the commit, so Box rejects a corrupt stream.
+func (c *boxClient) uploadStream(r io.Reader, size int64, parentFolderID string, fileName string, userID string, progressCallback ProgressCallback) (*File, error) {
+	if strings.TrimSpace(fileName) == "" {
+		return nil, fmt.Errorf("file name cannot be empty")
+	}
+	if parentFolderID == "" {
+		parentFolderID = RootFolderID
+	}
+	if size < MinChunkedUploadSize {
+		return nil, fmt.Errorf("stream size %d is less than minimum chunked upload size %d", size, MinChunkedUploadSize)
+	}
+
+	if err := c.preflightUpload(fileName, parentFolderID, size, userID); err != nil {
+		return nil, err
+	}
+
+	session, err := c.createUploadSession(fileName, parentFolderID, size, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create upload session: %w", err)
+	}
+	c.sessions.add(session.ID, userID)
+	defer c.sessions.remove(session.ID)
+
+	partSize := c.partSize(session)
+
+	fileHash := sha1.New()
+	buffer := make([]byte, partSize)
+	var parts []UploadPartInfo
+	for offset := int64(0); offset < size; {
+		n := partSize
+		if offset+n > size {
+			n = size - offset
+		}
+		part := buffer[:n]
+		if _, err := io.ReadFull(r, part); err != nil {
+			_ = c.abortUploadSession(session.ID, userID)
+			return nil, fmt.Errorf("failed to read stream at offset %d: %w", offset, err)
+		}
+		fileHash.Write(part)
+
+		uploadPart, err := c.uploadPart(session.ID, part, offset, size, userID)
+		if err != nil {
+			_ = c.abortUploadSession(session.ID, userID)
+			return nil, fmt.Errorf("failed to upload part at offset %d: %w", offset, err)
+		}
+		if uploadPart.Part != nil {
+			parts = append(parts, *uploadPart.Part)
+		} else {
+			partHash := sha1.Sum(part)
+			parts = append(parts, UploadPartInfo{Offset: offset, Size: n, SHA1: base64.StdEncoding.EncodeToString(partHash[:])})
+		}
+
+		offset += n
+		if progressCallback != nil {
+			progressCallback(offset, size)
+		}
+	}
+
+	// A stream longer than announced means the source changed; its tail would be lost
+	if extra, _ := r.Read(make([]byte, 1)); extra > 0 {
+		_ = c.abortUploadSession(session.ID, userID)
+		return nil, fmt.Errorf("stream is longer than its size of %d bytes", size)
+	}
+
+	if err := validateUploadedParts(parts, size); err != nil {
+		_ = c.abortUploadSession(session.ID, userID)
+		return nil, fmt.Errorf("upload validation failed: %w", err)
+	}
+
+	digest := "sha=" + base64.StdEncoding.EncodeToString(fileHash.Sum(nil))
+	file, err := c.commitUploadSession(session.ID, parts, map[string]interface{}{}, digest, userID)
+	if err != nil {
+		// Don't abort on commit error - the session might still be processing
+		return nil, fmt.Errorf("failed to commit upload session: %w", err)
+	}
+	return file, nil
+}
@@ -0,0 +1,48 @@
+package box
+
+import (
+	"github.com/curtbushko/zoom-to-box/internal/logging"
+	"github.com/curtbushko/zoom-to-box/internal/tokencache"
+)
+
+// UseTokenCache makes a client credentials authenticator reuse the access token cached by an
+// earlier run until it expires, and cache the tokens it obtains
+// Authenticators with a refresh token (box login) already persist their credentials and are left unchanged.
+func UseTokenCache(auth Authenticator, cache *tokencache.FileCache) {
+	a, ok := auth.(*oauth2Authenticator)
+	if !ok || a.credentials == nil || a.credentials.RefreshToken != "" || cache == nil {
+		return
+	}
+
+	name := "box:" + a.credentials.EnterpriseID + ":" + a.credentials.ClientID
+	cached, err := cache.Load(name, a.credentials.ClientSecret)
+	if err != nil {
+		logging.Warn("Ignoring Box token cache: %v", err)
+	}
+	if cached != nil {
+		a.credentials.AccessToken = cached.AccessToken
+		a.credentials.TokenType = cached.TokenType
+		a.credentials.Scope = cached.Scope
+		a.credentials.ExpiresAt = cached.ExpiresAt
+		logging.RegisterSecret(cached.AccessToken)
+	}
+
+	previous := a.onCredentialsUpdated
+	a.onCredentialsUpdated = func(creds *OAuth2Credentials) error {
+		if creds.RefreshToken == "" {
+			err := cache.Save(name, creds.ClientSecret, tokencache.Token{
+				AccessToken: creds.AccessToken,
+				TokenType:   creds.TokenType,
+				Scope:       creds.Scope,
+				ExpiresAt:   creds.ExpiresAt,
+			})
+			if err != nil {
+				logging.Warn("Failed to save Box token to cache: %v", err)
+			}
+		}
+		if previous != nil {
+			return previous(creds)
+		}
+		return nil
+	}
+}
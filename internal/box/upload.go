@@ -3,11 +3,16 @@ package box
 
 import (
 	"context"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/curtbushko/zoom-to-box/internal/atrest"
 	"github.com/curtbushko/zoom-to-box/internal/download"
 	"github.com/curtbushko/zoom-to-box/internal/logging"
 	"github.com/curtbushko/zoom-to-box/internal/tracking"
@@ -27,9 +32,11 @@ type UploadManager interface {
 
 	// Bulk operations
 	UploadPendingFiles(ctx context.Context, statusTracker download.StatusTracker) (*UploadSummary, error)
+	RetryFailedUploads(ctx context.Context, statusTracker download.StatusTracker, options RetryOptions) (*UploadSummary, error)
 
 	// Validation
 	ValidateUploadedFile(ctx context.Context, fileID string, expectedSize int64) (bool, error)
+	VerifyUploadedFileChecksum(ctx context.Context, fileID, localPath string) error
 
 	// Configuration
 	SetBaseFolderID(folderID string)
@@ -47,6 +54,9 @@ type UploadManager interface {
 	UploadFileWithEmailMappingWithTime(ctx context.Context, localPath, zoomEmail, boxEmail, downloadID string, progressCallback UploadProgressCallback, processingTime time.Duration, trackingZoomEmail string, fileSize int64) (*UploadResult, error)
 }
 
+// ErrChecksumMismatch is returned when an uploaded Box file's SHA1 differs from the local file
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
 // UploadProgressCallback is called during file upload to report progress
 type UploadProgressCallback func(uploaded int64, total int64, phase UploadPhase)
 
@@ -91,6 +101,7 @@ type boxUploadManager struct {
 	maxRetries        int
 	globalCSVTracker  tracking.CSVTracker
 	userCSVTracker    tracking.CSVTracker
+	folders           *FolderCache // Folders found or created during the run
 }
 
 // NewUploadManager creates a new Box upload manager
@@ -103,9 +114,15 @@ func NewUploadManager(client BoxClient) UploadManager {
 		client:       client,
 		baseFolderID: RootFolderID, // Will be set to user's zoom folder before uploads
 		maxRetries:   3,
+		folders:      NewFolderCache(),
 	}
 }
 
+// FolderCache returns the cache of folders found or created by uploads of this manager
+func (um *boxUploadManager) FolderCache() *FolderCache {
+	return um.folders
+}
+
 // SetBaseFolderID sets the base folder ID for uploads
 func (um *boxUploadManager) SetBaseFolderID(folderID string) {
 	if folderID == "" {
@@ -469,9 +486,183 @@ func (um *boxUploadManager) UploadPendingFiles(ctx context.Context, statusTracke
 	return summary, nil
 }
 
+// RetryOptions controls which failed uploads RetryFailedUploads re-attempts
+type RetryOptions struct {
+	MaxRetries int    // Uploads that already failed this many times are skipped (default: 3)
+	BaseDir    string // Directory relative file paths in the status file are resolved against
+	DryRun     bool   // Report what would be retried without uploading
+}
+
+// RetryFailedUploads re-attempts only the uploads the status tracker recorded with an upload error
+// Entries that exhausted MaxRetries, or whose backoff (retries² minutes since the last attempt)
+// has not elapsed, are skipped and left for a later run.
+func (um *boxUploadManager) RetryFailedUploads(ctx context.Context, statusTracker download.StatusTracker, options RetryOptions) (*UploadSummary, error) {
+	startTime := time.Now()
+	if options.MaxRetries <= 0 {
+		options.MaxRetries = um.maxRetries
+	}
+
+	summary := &UploadSummary{
+		Results: make([]*UploadResult, 0),
+		Errors:  make([]error, 0),
+	}
+
+	failedUploads := statusTracker.GetFailedBoxUploads()
+	summary.TotalFiles = len(failedUploads)
+
+	// Retry in a stable order so repeated runs are predictable
+	downloadIDs := make([]string, 0, len(failedUploads))
+	for downloadID := range failedUploads {
+		downloadIDs = append(downloadIDs, downloadID)
+	}
+	sort.Strings(downloadIDs)
+
+	logging.Info("Found %d failed Box uploads in the status file", summary.TotalFiles)
+
+	for _, downloadID := range downloadIDs {
+		if err := ctx.Err(); err != nil {
+			return summary, err
+		}
+
+		entry := failedUploads[downloadID]
+		if entry.Box.UploadRetries >= options.MaxRetries {
+			summary.SkippedCount++
+			logging.Info("Skipping upload for %s (max retries exceeded: %d)", downloadID, entry.Box.UploadRetries)
+			continue
+		}
+		if !download.ShouldRetryBoxUpload(entry, options.MaxRetries) {
+			summary.SkippedCount++
+			logging.Info("Skipping upload for %s (backing off after %d failed attempts, last at %s)",
+				downloadID, entry.Box.UploadRetries, entry.Box.LastUploadAttempt.Format(time.RFC3339))
+			continue
+		}
+
+		localPath := entry.FilePath
+		if !filepath.IsAbs(localPath) && options.BaseDir != "" {
+			localPath = filepath.Join(options.BaseDir, localPath)
+		}
+		boxEmail := download.GetBoxEmailForEntry(entry)
+		attempt := entry.Box.UploadRetries + 1
+
+		if options.DryRun {
+			logging.Info("DRY RUN: would retry upload of %s for %s (attempt %d)", localPath, boxEmail, attempt)
+			summary.Results = append(summary.Results, &UploadResult{FileName: filepath.Base(localPath)})
+			continue
+		}
+
+		result, err := um.retryUpload(ctx, statusTracker, downloadID, localPath, entry.VideoOwner, boxEmail)
+		if err != nil {
+			summary.FailureCount++
+			summary.Errors = append(summary.Errors, fmt.Errorf("%s: %w", downloadID, err))
+			statusTracker.MarkBoxUploadFailed(downloadID, err.Error())
+
+			logging.LogUserAction("box_upload_retry_failed", entry.VideoOwner, map[string]interface{}{
+				"download_id": downloadID,
+				"file_path":   localPath,
+				"attempt":     attempt,
+				"error":       err.Error(),
+			})
+		} else {
+			summary.SuccessCount++
+			statusTracker.MarkBoxUploadCompleted(downloadID, result.FileID)
+		}
+
+		summary.Results = append(summary.Results, result)
+	}
+
+	summary.Duration = time.Since(startTime)
+
+	logging.Info("Box upload retry completed: %d success, %d failed, %d skipped in %v",
+		summary.SuccessCount, summary.FailureCount, summary.SkippedCount, summary.Duration)
+
+	return summary, nil
+}
+
+// retryUpload uploads one file into its owner's zoom folder
+func (um *boxUploadManager) retryUpload(ctx context.Context, statusTracker download.StatusTracker, downloadID, localPath, zoomEmail, boxEmail string) (*UploadResult, error) {
+	result := &UploadResult{FileName: filepath.Base(localPath)}
+
+	if _, err := os.Stat(localPath); err != nil {
+		result.Error = fmt.Errorf("local file is no longer available: %w", err)
+		return result, result.Error
+	}
+
+	// The Box folder comes from the local path, so encrypted recordings are not decrypted
+	// here; replaying a run report uploads them through a decrypted copy
+	if _, encrypted, err := atrest.Inspect(localPath); err == nil && encrypted {
+		result.Error = fmt.Errorf("local file is encrypted at rest; retry it with zoom-to-box replay --run-report")
+		return result, result.Error
+	}
+
+	zoomFolder, err := um.folders.FindZoomFolderByOwner(um.client, boxEmail)
+	if err != nil {
+		result.Error = fmt.Errorf("failed to find zoom folder for user %s: %w", boxEmail, err)
+		return result, result.Error
+	}
+	um.SetBaseFolderID(zoomFolder.ID)
+
+	statusTracker.MarkBoxUploadStarted(downloadID, zoomFolder.ID)
+
+	if zoomEmail == "" {
+		zoomEmail = boxEmail
+	}
+	return um.UploadFileWithEmailMapping(ctx, localPath, zoomEmail, boxEmail, downloadID, nil)
+}
+
 // createFolderStructure creates the necessary folder structure for the upload with proper permissions
 func (um *boxUploadManager) createFolderStructure(ctx context.Context, folderPath string) (*Folder, error) {
-	return CreateFolderPath(um.client, folderPath, um.baseFolderID)
+	return um.folders.CreateFolderPath(um.client, folderPath, um.baseFolderID)
+}
+
+// FolderUploader is implemented by upload managers that can upload into an already resolved folder,
+// for callers that choose the folder themselves instead of deriving it from the local path
+type FolderUploader interface {
+	UploadFileToFolder(ctx context.Context, localPath, folderID, fileName string, progressCallback UploadProgressCallback) (*UploadResult, error)
+}
+
+// UploadFileToFolder uploads a file into folderID as fileName
+func (um *boxUploadManager) UploadFileToFolder(ctx context.Context, localPath, folderID, fileName string, progressCallback UploadProgressCallback) (*UploadResult, error) {
+	startTime := time.Now()
+
+	result := &UploadResult{
+		FileName:   fileName,
+		FolderID:   folderID,
+		UploadDate: startTime,
+	}
+
+	// Report progress - uploading file
+	if progressCallback != nil {
+		progressCallback(0, 0, PhaseUploadingFile)
+	}
+
+	var uploadProgressCallback ProgressCallback
+	if progressCallback != nil {
+		uploadProgressCallback = func(uploaded, total int64) {
+			progressCallback(uploaded, total, PhaseUploadingFile)
+		}
+	}
+
+	file, err := um.client.UploadFileWithProgress(localPath, folderID, fileName, uploadProgressCallback)
+	if err != nil {
+		err = fmt.Errorf("failed to upload file: %w", err)
+		result.Error = err
+		if progressCallback != nil {
+			progressCallback(0, 0, PhaseFailed)
+		}
+		return result, err
+	}
+
+	result.FileID = file.ID
+	result.FileSize = file.Size
+	result.Success = true
+	result.Duration = time.Since(startTime)
+
+	// Report progress - completed
+	if progressCallback != nil {
+		progressCallback(result.FileSize, result.FileSize, PhaseCompleted)
+	}
+
+	return result, nil
 }
 
 // Helper functions
@@ -598,16 +789,48 @@ func (um *boxUploadManager) ValidateUploadedFile(ctx context.Context, fileID str
 	return true, nil
 }
 
+// VerifyUploadedFileChecksum compares the SHA1 Box reports for an uploaded file with the local file
+// Returns an error wrapping ErrChecksumMismatch when the contents differ.
+func (um *boxUploadManager) VerifyUploadedFileChecksum(ctx context.Context, fileID, localPath string) error {
+	if fileID == "" {
+		return fmt.Errorf("file ID cannot be empty")
+	}
+
+	localSum, err := fileSHA1Sum(localPath)
+	if err != nil {
+		return fmt.Errorf("failed to checksum local file %s: %w", localPath, err)
+	}
+
+	file, err := um.client.GetFile(fileID)
+	if err != nil {
+		return fmt.Errorf("failed to get Box file %s: %w", fileID, err)
+	}
+	if file.SHA1 == "" {
+		return fmt.Errorf("Box returned no SHA1 for file %s", fileID)
+	}
+
+	localSHA1 := hex.EncodeToString(localSum)
+	if !strings.EqualFold(file.SHA1, localSHA1) {
+		return fmt.Errorf("%w: Box file %s has SHA1 %s, local file %s has %s", ErrChecksumMismatch, fileID, file.SHA1, filepath.Base(localPath), localSHA1)
+	}
+
+	logging.Debug("Verified SHA1 of Box file %s: %s", fileID, localSHA1)
+	return nil
+}
+
 // trackUpload records an upload to both global and user CSV trackers if they are configured
 func (um *boxUploadManager) trackUpload(zoomUser, fileName string, fileSize int64, uploadDate time.Time, processingTime time.Duration) {
-	entry := tracking.UploadEntry{
+	um.TrackUpload(tracking.UploadEntry{
 		ZoomUser:       zoomUser,
 		FileName:       fileName,
 		RecordingSize:  fileSize,
 		UploadDate:     uploadDate,
 		ProcessingTime: processingTime,
-	}
+	})
+}
 
+// TrackUpload records entry in both global and user CSV trackers if they are configured
+func (um *boxUploadManager) TrackUpload(entry tracking.UploadEntry) {
 	// Track in global CSV if configured
 	if um.globalCSVTracker != nil {
 		if err := um.globalCSVTracker.TrackUpload(entry); err != nil {
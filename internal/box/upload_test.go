@@ -6,12 +6,14 @@ import (
 	"crypto/sha1"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -27,6 +29,8 @@ type mockBoxClient struct {
 	folderItems map[string][]Item
 	uploadError error
 	folderError error
+
+	zoomFolderID string // Returned by FindZoomFolderByOwner when set
 }
 
 func newMockBoxClient() *mockBoxClient {
@@ -105,6 +109,26 @@ func (m *mockBoxClient) CreateFolderAsUser(name string, parentID string, userID
 	return folder, nil
 }
 
+func (m *mockBoxClient) AddFolderCollaboratorAsUser(folderID string, collaboratorID string, role string, userID string) error {
+	return m.folderError
+}
+
+func (m *mockBoxClient) UploadStream(r io.Reader, size int64, parentFolderID string, fileName string, progressCallback ProgressCallback) (*File, error) {
+	return nil, nil
+}
+
+func (m *mockBoxClient) UploadStreamAsUser(r io.Reader, size int64, parentFolderID string, fileName string, userID string, progressCallback ProgressCallback) (*File, error) {
+	return nil, nil
+}
+
+func (m *mockBoxClient) SearchFiles(name string, ancestorFolderID string) ([]*File, error) {
+	return nil, nil
+}
+
+func (m *mockBoxClient) SearchFilesAsUser(name string, ancestorFolderID string, userID string) ([]*File, error) {
+	return nil, nil
+}
+
 func (m *mockBoxClient) ListFolderItems(folderID string) (*FolderItems, error) {
 	if items, exists := m.folderItems[folderID]; exists {
 		return &FolderItems{
@@ -172,6 +196,23 @@ func (m *mockBoxClient) UploadFileAsUser(filePath string, parentFolderID string,
 	return file, nil
 }
 
+func (m *mockBoxClient) CreateSharedLink(fileID string, access string) (*SharedLink, error) {
+	return &SharedLink{URL: "https://app.box.com/s/" + fileID, Access: access}, nil
+}
+
+func (m *mockBoxClient) ApplyMetadata(fileID string, scope string, templateKey string, values map[string]interface{}) error {
+	return nil
+}
+
+func (m *mockBoxClient) UploadFileVersion(fileID string, filePath string, progressCallback ProgressCallback) (*File, error) {
+	if m.uploadError != nil {
+		return nil, m.uploadError
+	}
+	file := &File{ID: fileID, Name: filepath.Base(filePath), Type: ItemTypeFile, Size: 1000}
+	m.files[fileID] = file
+	return file, nil
+}
+
 func (m *mockBoxClient) GetFile(fileID string) (*File, error) {
 	if file, exists := m.files[fileID]; exists {
 		return file, nil
@@ -184,6 +225,11 @@ func (m *mockBoxClient) DeleteFile(fileID string) error {
 	return nil
 }
 
+func (m *mockBoxClient) DeleteFolder(folderID string) error {
+	delete(m.folders, folderID)
+	return nil
+}
+
 // FindFolderByName - Feature 4.4 implementation for mock
 func (m *mockBoxClient) FindFolderByName(parentID string, name string) (*Folder, error) {
 	// Simple implementation for tests - return nil as not used in upload tests
@@ -198,6 +244,9 @@ func (m *mockBoxClient) FindFileByName(folderID string, name string) (*File, err
 
 // FindZoomFolderByOwner - Feature 4.4 implementation for mock
 func (m *mockBoxClient) FindZoomFolderByOwner(ownerEmail string) (*Folder, error) {
+	if m.zoomFolderID != "" {
+		return &Folder{ID: m.zoomFolderID, Name: "zoom", Type: ItemTypeFolder}, nil
+	}
 	// Simple implementation for tests - return nil as not used in upload tests
 	return nil, &BoxError{StatusCode: 404, Code: ErrorCodeItemNotFound, Message: "not implemented in mock"}
 }
@@ -647,6 +696,39 @@ func TestValidateUploadedFile(t *testing.T) {
 	}
 }
 
+func TestVerifyUploadedFileChecksum(t *testing.T) {
+	client := newMockBoxClient()
+	manager := NewUploadManager(client)
+	ctx := context.Background()
+
+	localPath := filepath.Join(t.TempDir(), "test.mp4")
+	if err := os.WriteFile(localPath, []byte("hello world"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	client.files["match"] = &File{ID: "match", Name: "test.mp4", SHA1: "2AAE6C35C94FCFB415DBE95F408B9CE91EE846ED"}
+	client.files["mismatch"] = &File{ID: "mismatch", Name: "test.mp4", SHA1: "da39a3ee5e6b4b0d3255bfef95601890afd80709"}
+	client.files["no-sha1"] = &File{ID: "no-sha1", Name: "test.mp4"}
+
+	if err := manager.VerifyUploadedFileChecksum(ctx, "match", localPath); err != nil {
+		t.Errorf("Expected matching SHA1 to verify, got %v", err)
+	}
+
+	err := manager.VerifyUploadedFileChecksum(ctx, "mismatch", localPath)
+	if !errors.Is(err, ErrChecksumMismatch) {
+		t.Errorf("Expected ErrChecksumMismatch, got %v", err)
+	}
+
+	err = manager.VerifyUploadedFileChecksum(ctx, "no-sha1", localPath)
+	if err == nil || errors.Is(err, ErrChecksumMismatch) {
+		t.Errorf("Expected a missing SHA1 error, got %v", err)
+	}
+
+	if err := manager.VerifyUploadedFileChecksum(ctx, "non-existent", localPath); err == nil {
+		t.Error("Expected an error for a non-existent Box file")
+	}
+}
+
 func TestUploadPendingFiles(t *testing.T) {
 	// Create temporary test files
 	tempDir := t.TempDir()
@@ -700,6 +782,83 @@ func TestUploadPendingFiles(t *testing.T) {
 	}
 }
 
+func TestRetryFailedUploads(t *testing.T) {
+	tempDir := t.TempDir()
+	for _, name := range []string{"ready.mp4", "exhausted.mp4", "backoff.mp4"} {
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte("test content"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	client := newMockBoxClient()
+	client.zoomFolderID = "zoom-folder"
+	manager := NewUploadManager(client)
+	statusTracker := newMockStatusTracker()
+
+	failed := func(path string, retries int, lastAttempt time.Duration) download.DownloadEntry {
+		return download.DownloadEntry{
+			Status:     download.StatusCompleted,
+			FilePath:   path,
+			VideoOwner: "user@example.com",
+			Box: &download.BoxUploadInfo{
+				UploadRetries:     retries,
+				UploadError:       "connection reset",
+				LastUploadAttempt: time.Now().Add(-lastAttempt),
+			},
+		}
+	}
+	statusTracker.entries["ready"] = failed("ready.mp4", 1, time.Hour)
+	statusTracker.entries["exhausted"] = failed("exhausted.mp4", 5, time.Hour)
+	statusTracker.entries["backoff"] = failed("backoff.mp4", 2, time.Minute)
+	statusTracker.entries["missing"] = failed("missing.mp4", 0, 0)
+	statusTracker.entries["uploaded"] = download.DownloadEntry{
+		Status:   download.StatusCompleted,
+		FilePath: "ready.mp4",
+		Box:      &download.BoxUploadInfo{Uploaded: true, FileID: "existing"},
+	}
+
+	summary, err := manager.RetryFailedUploads(context.Background(), statusTracker, RetryOptions{MaxRetries: 5, BaseDir: tempDir})
+	if err != nil {
+		t.Fatalf("RetryFailedUploads failed: %v", err)
+	}
+
+	if summary.TotalFiles != 4 || summary.SuccessCount != 1 || summary.FailureCount != 1 || summary.SkippedCount != 2 {
+		t.Errorf("Unexpected summary: total=%d success=%d failed=%d skipped=%d",
+			summary.TotalFiles, summary.SuccessCount, summary.FailureCount, summary.SkippedCount)
+	}
+
+	if ready := statusTracker.entries["ready"].Box; !ready.Uploaded || ready.UploadError != "" {
+		t.Errorf("Expected the retried upload to be marked completed, got %+v", ready)
+	}
+	if missing := statusTracker.entries["missing"].Box; missing.Uploaded || missing.UploadRetries != 1 {
+		t.Errorf("Expected the missing file to be marked failed again, got %+v", missing)
+	}
+	if exhausted := statusTracker.entries["exhausted"].Box; exhausted.UploadRetries != 5 {
+		t.Errorf("Expected the exhausted upload to be left alone, got %+v", exhausted)
+	}
+}
+
+func TestRetryFailedUploads_DryRun(t *testing.T) {
+	client := newMockBoxClient()
+	client.zoomFolderID = "zoom-folder"
+	manager := NewUploadManager(client)
+	statusTracker := newMockStatusTracker()
+	statusTracker.entries["download-1"] = download.DownloadEntry{
+		Status:     download.StatusCompleted,
+		FilePath:   "/recordings/user/2024/01/15/meeting.mp4",
+		VideoOwner: "user@example.com",
+		Box:        &download.BoxUploadInfo{UploadRetries: 1, UploadError: "timeout"},
+	}
+
+	summary, err := manager.RetryFailedUploads(context.Background(), statusTracker, RetryOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("RetryFailedUploads failed: %v", err)
+	}
+	if len(summary.Results) != 1 || summary.SuccessCount != 0 || len(client.files) != 0 {
+		t.Errorf("Expected a dry run to report the upload without performing it, got %+v", summary)
+	}
+}
+
 func TestExtractUsernameFromEmail(t *testing.T) {
 	tests := []struct {
 		email    string
@@ -1378,4 +1537,116 @@ func (m *mockBoxClient) setupFolderStructure() {
 			{ID: "month_folder", Type: ItemTypeFolder, Name: "01"},
 		},
 	}
-}
\ No newline at end of file
+}
+// concurrentPartsHTTPClient serves upload session requests and records how many
+// part uploads were in flight at once
+type concurrentPartsHTTPClient struct {
+	*mockAuthenticatedHTTPClient
+	mu          sync.Mutex
+	inFlight    int
+	maxInFlight int
+	commitParts []UploadPartInfo
+}
+
+func (c *concurrentPartsHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	switch {
+	case req.Method == "PUT":
+		c.mu.Lock()
+		c.inFlight++
+		if c.inFlight > c.maxInFlight {
+			c.maxInFlight = c.inFlight
+		}
+		c.mu.Unlock()
+
+		time.Sleep(20 * time.Millisecond)
+
+		var offset, rangeEnd, total int64
+		fmt.Sscanf(req.Header.Get("Content-Range"), "bytes %d-%d/%d", &offset, &rangeEnd, &total)
+
+		c.mu.Lock()
+		c.inFlight--
+		c.mu.Unlock()
+
+		responseBody := fmt.Sprintf(`{"part":{"part_id":"%d","offset":%d,"size":%d,"sha1":"%s"}}`,
+			offset, offset, rangeEnd-offset+1, req.Header.Get("Digest")[4:])
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(responseBody)),
+			Header:     make(http.Header),
+		}, nil
+	case strings.HasSuffix(req.URL.Path, "/commit"):
+		var commitReq CommitUploadSessionRequest
+		json.NewDecoder(req.Body).Decode(&commitReq)
+		c.mu.Lock()
+		c.commitParts = commitReq.Parts
+		c.mu.Unlock()
+		return &http.Response{
+			StatusCode: http.StatusCreated,
+			Body:       io.NopCloser(strings.NewReader(`{"total_count":1,"entries":[{"id":"uploaded-file","name":"large-test.mp4"}]}`)),
+			Header:     make(http.Header),
+		}, nil
+	case strings.HasSuffix(req.URL.Path, "/upload_sessions"):
+		return &http.Response{
+			StatusCode: http.StatusCreated,
+			Body:       io.NopCloser(strings.NewReader(`{"id":"test-session","part_size":8388608,"total_parts":5}`)),
+			Header:     make(http.Header),
+		}, nil
+	}
+	return nil, fmt.Errorf("unexpected request: %s %s", req.Method, req.URL.Path)
+}
+
+func (c *concurrentPartsHTTPClient) PostJSON(ctx context.Context, url string, payload interface{}) (*http.Response, error) {
+	body, _ := json.Marshal(payload)
+	req, _ := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+	return c.Do(req)
+}
+
+func TestUploadLargeFile_ConcurrentParts(t *testing.T) {
+	tempDir := t.TempDir()
+	testFile := filepath.Join(tempDir, "large-test.mp4")
+
+	// 5 parts: four full 8MB parts and a short final part
+	fileSize := int64(4*DefaultChunkSize + 1024)
+	testData := make([]byte, fileSize)
+	for i := range testData {
+		testData[i] = byte(i % 251)
+	}
+	if err := os.WriteFile(testFile, testData, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	httpClient := &concurrentPartsHTTPClient{mockAuthenticatedHTTPClient: newMockAuthenticatedHTTPClient()}
+	client := &boxClient{httpClient: httpClient, uploadConcurrency: 3}
+
+	var lastProgress int64
+	_, err := client.UploadLargeFile(testFile, "test-folder", "large-test.mp4", func(uploaded, total int64) {
+		lastProgress = uploaded
+	})
+	if err != nil {
+		t.Fatalf("UploadLargeFile failed: %v", err)
+	}
+
+	if httpClient.maxInFlight < 2 {
+		t.Errorf("Expected parts to upload concurrently, max in flight was %d", httpClient.maxInFlight)
+	}
+	if httpClient.maxInFlight > 3 {
+		t.Errorf("Expected at most 3 parts in flight, got %d", httpClient.maxInFlight)
+	}
+	if lastProgress != fileSize {
+		t.Errorf("Expected final progress %d, got %d", fileSize, lastProgress)
+	}
+
+	if len(httpClient.commitParts) != 5 {
+		t.Fatalf("Expected 5 parts in commit, got %d", len(httpClient.commitParts))
+	}
+	var expectedOffset int64
+	for i, part := range httpClient.commitParts {
+		if part.Offset != expectedOffset {
+			t.Errorf("Commit part %d out of order: offset %d, expected %d", i, part.Offset, expectedOffset)
+		}
+		expectedOffset += part.Size
+	}
+	if expectedOffset != fileSize {
+		t.Errorf("Expected committed parts to cover %d bytes, got %d", fileSize, expectedOffset)
+	}
+}
@@ -0,0 +1,310 @@
+package box
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	"github.com/curtbushko/zoom-to-box/internal/logging"
+)
+
+// DefaultRedirectURL is the local callback used by the authorization-code login
+// It must be registered as a redirect URI on the Box app.
+const DefaultRedirectURL = "http://localhost:8085/callback"
+
+// UserToken is the token pair persisted after a user logs in with the authorization-code flow
+// Box rotates refresh tokens on every refresh, so the file is rewritten whenever a token is refreshed.
+type UserToken struct {
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token"`
+	TokenType    string    `json:"token_type,omitempty"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+// LoadUserToken reads a token file written by SaveUserToken
+func LoadUserToken(path string) (*UserToken, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Box token file %s (run 'zoom-to-box box login' first): %w", path, err)
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0077 != 0 {
+		logging.Warn("Box token file %s is readable by other users (mode %v); it should be 0600", path, info.Mode().Perm())
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Box token file %s: %w", path, err)
+	}
+
+	var token UserToken
+	if err := json.Unmarshal(data, &token); err != nil {
+		return nil, fmt.Errorf("failed to parse Box token file %s: %w", path, err)
+	}
+	if token.RefreshToken == "" {
+		return nil, fmt.Errorf("Box token file %s has no refresh token (run 'zoom-to-box box login' again)", path)
+	}
+	return &token, nil
+}
+
+// SaveUserToken writes the token file readable only by the current user, replacing it atomically
+func SaveUserToken(path string, token *UserToken) error {
+	if token == nil {
+		return fmt.Errorf("token cannot be nil")
+	}
+
+	data, err := json.MarshalIndent(token, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal Box token: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create Box token directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".box-token-*")
+	if err != nil {
+		return fmt.Errorf("failed to write Box token file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+
+	if err := tmp.Chmod(0600); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to restrict Box token file permissions: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write Box token file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write Box token file: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to replace Box token file %s: %w", path, err)
+	}
+	return nil
+}
+
+// userTokenFromCredentials extracts the persisted token pair from credentials
+func userTokenFromCredentials(creds *OAuth2Credentials) *UserToken {
+	return &UserToken{
+		AccessToken:  creds.AccessToken,
+		RefreshToken: creds.RefreshToken,
+		TokenType:    creds.TokenType,
+		ExpiresAt:    creds.ExpiresAt,
+	}
+}
+
+// NewUserAuthenticator creates an Authenticator for a user who logged in with 'zoom-to-box box login'
+// Access tokens are refreshed automatically and every rotated refresh token is saved to tokenFile.
+func NewUserAuthenticator(clientID, clientSecret, tokenFile string, httpClient *http.Client) (Authenticator, error) {
+	token, err := LoadUserToken(tokenFile)
+	if err != nil {
+		return nil, err
+	}
+
+	auth := NewOAuth2Authenticator(&OAuth2Credentials{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		AccessToken:  token.AccessToken,
+		RefreshToken: token.RefreshToken,
+		TokenType:    token.TokenType,
+		ExpiresAt:    token.ExpiresAt,
+	}, httpClient)
+
+	auth.(*oauth2Authenticator).SetCredentialsUpdateCallback(func(creds *OAuth2Credentials) error {
+		return SaveUserToken(tokenFile, userTokenFromCredentials(creds))
+	})
+	return auth, nil
+}
+
+// AuthCodeFlow performs the OAuth 2.0 authorization-code flow for an individual Box user
+type AuthCodeFlow struct {
+	clientID     string
+	clientSecret string
+	redirectURL  string
+	httpClient   *http.Client
+	authURL      string
+	tokenURL     string
+}
+
+// NewAuthCodeFlow creates an authorization-code flow that receives the redirect on redirectURL
+// (default: http://localhost:8085/callback)
+func NewAuthCodeFlow(clientID, clientSecret, redirectURL string, httpClient *http.Client) *AuthCodeFlow {
+	if redirectURL == "" {
+		redirectURL = DefaultRedirectURL
+	}
+	if httpClient == nil {
+		httpClient = &http.Client{
+			Timeout: 30 * time.Second,
+		}
+	}
+	return &AuthCodeFlow{
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		redirectURL:  redirectURL,
+		httpClient:   httpClient,
+		authURL:      BoxAuthURL,
+		tokenURL:     BoxTokenURL,
+	}
+}
+
+// AuthorizationURL returns the Box consent page URL for the given anti-forgery state
+func (f *AuthCodeFlow) AuthorizationURL(state string) string {
+	params := url.Values{}
+	params.Set("response_type", "code")
+	params.Set("client_id", f.clientID)
+	params.Set("redirect_uri", f.redirectURL)
+	params.Set("state", state)
+	return f.authURL + "?" + params.Encode()
+}
+
+// Exchange trades an authorization code for an access and refresh token
+func (f *AuthCodeFlow) Exchange(ctx context.Context, code string) (*UserToken, error) {
+	auth := &oauth2Authenticator{
+		credentials: &OAuth2Credentials{ClientID: f.clientID, ClientSecret: f.clientSecret},
+		httpClient:  f.httpClient,
+		tokenURL:    f.tokenURL,
+	}
+
+	data := url.Values{}
+	data.Set("grant_type", "authorization_code")
+	data.Set("code", code)
+	data.Set("client_id", f.clientID)
+	data.Set("client_secret", f.clientSecret)
+	data.Set("redirect_uri", f.redirectURL)
+
+	tokenResp, err := auth.requestToken(ctx, data, "authorization code exchange")
+	if err != nil {
+		return nil, err
+	}
+	if tokenResp.RefreshToken == "" {
+		return nil, fmt.Errorf("Box did not return a refresh token")
+	}
+
+	return &UserToken{
+		AccessToken:  tokenResp.AccessToken,
+		RefreshToken: tokenResp.RefreshToken,
+		TokenType:    tokenResp.TokenType,
+		ExpiresAt:    time.Now().Add(time.Duration(tokenResp.ExpiresIn) * time.Second),
+	}, nil
+}
+
+// Login runs the interactive flow: it listens on the redirect URL, passes the consent page URL to
+// openBrowser, waits for Box to redirect back with a code and exchanges it for tokens
+func (f *AuthCodeFlow) Login(ctx context.Context, openBrowser func(authURL string) error) (*UserToken, error) {
+	redirect, err := url.Parse(f.redirectURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redirect URL %s: %w", f.redirectURL, err)
+	}
+	if redirect.Scheme != "http" || !isLoopbackHost(redirect.Hostname()) {
+		return nil, fmt.Errorf("redirect URL %s must be an http://localhost address", f.redirectURL)
+	}
+
+	state, err := randomState()
+	if err != nil {
+		return nil, err
+	}
+
+	listener, err := net.Listen("tcp", redirect.Host)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen for the Box redirect on %s: %w", redirect.Host, err)
+	}
+
+	type callbackResult struct {
+		code string
+		err  error
+	}
+	results := make(chan callbackResult, 1)
+
+	callbackPath := redirect.Path
+	if callbackPath == "" {
+		callbackPath = "/"
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc(callbackPath, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		var result callbackResult
+		switch {
+		case query.Get("state") != state:
+			result.err = errors.New("authorization response has an invalid state")
+		case query.Get("error") != "":
+			result.err = fmt.Errorf("authorization denied: %s %s", query.Get("error"), query.Get("error_description"))
+		case query.Get("code") == "":
+			result.err = errors.New("authorization response has no code")
+		default:
+			result.code = query.Get("code")
+		}
+
+		if result.err != nil {
+			http.Error(w, "Box login failed: "+result.err.Error(), http.StatusBadRequest)
+		} else {
+			fmt.Fprintln(w, "Box login complete. You can close this window and return to zoom-to-box.")
+		}
+		select {
+		case results <- result:
+		default:
+		}
+	})
+
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go server.Serve(listener)
+	defer server.Close()
+
+	if err := openBrowser(f.AuthorizationURL(state)); err != nil {
+		return nil, fmt.Errorf("failed to open the Box consent page: %w", err)
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case result := <-results:
+		if result.err != nil {
+			return nil, result.err
+		}
+		return f.Exchange(ctx, result.code)
+	}
+}
+
+// OpenBrowser opens url in the user's default browser
+func OpenBrowser(url string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", url)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
+	}
+	return cmd.Start()
+}
+
+// isLoopbackHost reports whether host refers to the local machine
+func isLoopbackHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// randomState returns an unguessable value that ties the redirect to this login attempt
+func randomState() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate login state: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
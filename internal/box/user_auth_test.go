@@ -0,0 +1,197 @@
+package box
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTokenServer returns a Box token endpoint that accepts authorization codes and refresh tokens
+func newTokenServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("Failed to parse token request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.Form.Get("grant_type") {
+		case "authorization_code":
+			if r.Form.Get("code") != "good-code" || r.Form.Get("client_secret") != "secret" {
+				w.WriteHeader(http.StatusBadRequest)
+				json.NewEncoder(w).Encode(ErrorResponse{Code: "invalid_grant", Message: "bad code"})
+				return
+			}
+			json.NewEncoder(w).Encode(TokenResponse{AccessToken: "access-1", RefreshToken: "refresh-1", ExpiresIn: 3600, TokenType: "bearer"})
+		case "refresh_token":
+			if r.Form.Get("refresh_token") != "refresh-1" {
+				w.WriteHeader(http.StatusBadRequest)
+				json.NewEncoder(w).Encode(ErrorResponse{Code: "invalid_grant", Message: "bad refresh token"})
+				return
+			}
+			json.NewEncoder(w).Encode(TokenResponse{AccessToken: "access-2", RefreshToken: "refresh-2", ExpiresIn: 3600, TokenType: "bearer"})
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+}
+
+// freeRedirectURL returns a localhost callback URL on an unused port
+func freeRedirectURL(t *testing.T) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to find a free port: %v", err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+	return "http://" + addr + "/callback"
+}
+
+func TestAuthCodeFlow_AuthorizationURL(t *testing.T) {
+	flow := NewAuthCodeFlow("client", "secret", "", nil)
+	authURL, err := url.Parse(flow.AuthorizationURL("state-123"))
+	if err != nil {
+		t.Fatalf("Invalid authorization URL: %v", err)
+	}
+
+	query := authURL.Query()
+	if query.Get("response_type") != "code" || query.Get("client_id") != "client" ||
+		query.Get("state") != "state-123" || query.Get("redirect_uri") != DefaultRedirectURL {
+		t.Errorf("Unexpected authorization URL query: %v", query)
+	}
+}
+
+func TestAuthCodeFlow_Login(t *testing.T) {
+	tokenServer := newTokenServer(t)
+	defer tokenServer.Close()
+
+	tests := []struct {
+		name    string
+		code    string
+		state   func(actual string) string
+		wantErr string
+	}{
+		{name: "successful login", code: "good-code", state: func(s string) string { return s }},
+		{name: "forged state", code: "good-code", state: func(string) string { return "forged" }, wantErr: "invalid state"},
+		{name: "rejected code", code: "bad-code", state: func(s string) string { return s }, wantErr: "bad code"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flow := NewAuthCodeFlow("client", "secret", freeRedirectURL(t), nil)
+			flow.tokenURL = tokenServer.URL
+
+			// The "browser" approves the consent page and follows the redirect back
+			browser := func(authURL string) error {
+				parsed, err := url.Parse(authURL)
+				if err != nil {
+					return err
+				}
+				query := parsed.Query()
+				callback := query.Get("redirect_uri") + "?code=" + tt.code + "&state=" + tt.state(query.Get("state"))
+				go func() {
+					if resp, err := http.Get(callback); err == nil {
+						resp.Body.Close()
+					}
+				}()
+				return nil
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			token, err := flow.Login(ctx, browser)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Login failed: %v", err)
+			}
+			if token.AccessToken != "access-1" || token.RefreshToken != "refresh-1" || token.ExpiresAt.Before(time.Now()) {
+				t.Errorf("Unexpected token: %+v", token)
+			}
+		})
+	}
+}
+
+func TestAuthCodeFlow_RejectsNonLocalRedirect(t *testing.T) {
+	flow := NewAuthCodeFlow("client", "secret", "https://example.com/callback", nil)
+	_, err := flow.Login(context.Background(), func(string) error { return nil })
+	if err == nil || !strings.Contains(err.Error(), "localhost") {
+		t.Errorf("Expected a localhost redirect error, got %v", err)
+	}
+}
+
+func TestUserToken_SaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens", "box-token.json")
+	token := &UserToken{AccessToken: "access", RefreshToken: "refresh", ExpiresAt: time.Now().Add(time.Hour).Round(time.Second)}
+
+	if err := SaveUserToken(path, token); err != nil {
+		t.Fatalf("SaveUserToken failed: %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("Expected token file mode 0600, got %v", info.Mode().Perm())
+	}
+
+	loaded, err := LoadUserToken(path)
+	if err != nil {
+		t.Fatalf("LoadUserToken failed: %v", err)
+	}
+	if loaded.RefreshToken != "refresh" || !loaded.ExpiresAt.Equal(token.ExpiresAt) {
+		t.Errorf("Unexpected loaded token: %+v", loaded)
+	}
+
+	if _, err := LoadUserToken(filepath.Join(t.TempDir(), "missing.json")); err == nil || !strings.Contains(err.Error(), "box login") {
+		t.Errorf("Expected a missing token file to suggest logging in, got %v", err)
+	}
+}
+
+func TestNewUserAuthenticator_PersistsRefreshedTokens(t *testing.T) {
+	tokenServer := newTokenServer(t)
+	defer tokenServer.Close()
+
+	path := filepath.Join(t.TempDir(), "box-token.json")
+	if err := SaveUserToken(path, &UserToken{AccessToken: "access-1", RefreshToken: "refresh-1", ExpiresAt: time.Now().Add(-time.Minute)}); err != nil {
+		t.Fatal(err)
+	}
+
+	auth, err := NewUserAuthenticator("client", "secret", path, nil)
+	if err != nil {
+		t.Fatalf("NewUserAuthenticator failed: %v", err)
+	}
+	auth.(*oauth2Authenticator).tokenURL = tokenServer.URL
+
+	if auth.IsAuthenticated() {
+		t.Error("Expected an expired access token not to be authenticated")
+	}
+	if err := auth.RefreshToken(context.Background()); err != nil {
+		t.Fatalf("RefreshToken failed: %v", err)
+	}
+	if auth.GetAccessToken() != "access-2" {
+		t.Errorf("Expected the refreshed access token, got %q", auth.GetAccessToken())
+	}
+
+	saved, err := LoadUserToken(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.RefreshToken != "refresh-2" {
+		t.Errorf("Expected the rotated refresh token to be saved, got %+v", saved)
+	}
+}
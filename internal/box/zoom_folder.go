@@ -0,0 +1,99 @@
+package box
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/curtbushko/zoom-to-box/internal/email"
+	"github.com/curtbushko/zoom-to-box/internal/logging"
+)
+
+// AddFolderCollaboratorAsUser invites the Box user collaboratorID to folderID with role,
+// acting as the folder's owner userID. Inviting an existing collaborator is not an error.
+func (c *boxClient) AddFolderCollaboratorAsUser(folderID string, collaboratorID string, role string, userID string) error {
+	if folderID == "" {
+		return fmt.Errorf("folder ID cannot be empty")
+	}
+	if collaboratorID == "" {
+		return fmt.Errorf("collaborator ID cannot be empty")
+	}
+	if role == "" {
+		role = CollaborationRoleEditor
+	}
+
+	request := map[string]interface{}{
+		"item":          map[string]string{"type": ItemTypeFolder, "id": folderID},
+		"accessible_by": map[string]string{"type": "user", "id": collaboratorID},
+		"role":          role,
+	}
+
+	url := fmt.Sprintf("%s/collaborations?notify=false", BoxAPIBaseURL)
+	resp, err := c.httpClient.PostJSONAsUser(context.Background(), url, request, userID)
+	if err != nil {
+		return fmt.Errorf("failed to add collaborator: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusCreated {
+		return nil
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	var errorResp ErrorResponse
+	if json.Unmarshal(body, &errorResp) == nil && errorResp.Code == ErrorCodeUserAlreadyCollaborator {
+		return nil
+	}
+	return fmt.Errorf("failed to add collaborator, status: %d, body: %s", resp.StatusCode, string(body))
+}
+
+// CreateZoomFolderForUser creates the "zoom" folder in ownerEmail's root folder as that user,
+// so the user owns it, and returns the existing folder if one is already there. The client's
+// own account is invited as an editor so later lookups with FindZoomFolderByOwner find it.
+func CreateZoomFolderForUser(client BoxClient, ownerEmail string) (*Folder, error) {
+	owner, err := client.GetUserByEmail(ownerEmail)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up Box user %s: %w", ownerEmail, err)
+	}
+
+	folder, err := client.CreateFolderAsUser(ZoomFolderName, RootFolderID, owner.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create zoom folder for %s: %w", ownerEmail, err)
+	}
+	untrackFolder(client, folder.ID)
+
+	account, err := client.GetCurrentUser()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current Box user: %w", err)
+	}
+	if account.ID != owner.ID {
+		if err := client.AddFolderCollaboratorAsUser(folder.ID, account.ID, CollaborationRoleEditor, owner.ID); err != nil {
+			return nil, fmt.Errorf("failed to share zoom folder %s with the service account: %w", folder.ID, err)
+		}
+	}
+
+	logging.Info("Created zoom folder for %s - folder ID: %s", ownerEmail, folder.ID)
+	return folder, nil
+}
+
+// DestinationUserFolderName is the name of ownerEmail's folder in a shared destination folder,
+// the same user name the local download directories use
+func DestinationUserFolderName(ownerEmail string) string {
+	return email.ExtractUsername(ownerEmail)
+}
+
+// CreateDestinationUserFolder creates ownerEmail's folder in the shared destination folder
+// destinationFolderID and returns the existing folder if one is already there
+func CreateDestinationUserFolder(client BoxClient, destinationFolderID string, ownerEmail string) (*Folder, error) {
+	name := DestinationUserFolderName(ownerEmail)
+	folder, err := client.CreateFolder(name, destinationFolderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create folder %s for %s in destination folder %s: %w", name, ownerEmail, destinationFolderID, err)
+	}
+	untrackFolder(client, folder.ID)
+
+	logging.Info("Created folder %s for %s in destination folder %s - folder ID: %s", name, ownerEmail, destinationFolderID, folder.ID)
+	return folder, nil
+}
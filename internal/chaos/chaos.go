@@ -0,0 +1,191 @@
+// Package chaos injects HTTP failures into requests to Zoom and Box for resilience testing
+// A configured fraction of the requests to the selected hosts fails with a 429 (with a
+// Retry-After of one second), a 500, a timeout before the response, or a timeout partway through
+// the response body, so retries and download resumes can be exercised against realistic failure
+// patterns without a misbehaving server. It is a developer tool behind the hidden --chaos flag.
+package chaos
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/curtbushko/zoom-to-box/internal/logging"
+)
+
+// DefaultHosts are the Zoom and Box hosts whose requests fail, with their subdomains
+var DefaultHosts = []string{"zoom.us", "box.com", "boxcloud.com"}
+
+// Options configures the injected failures
+type Options struct {
+	Rate  float64  // Fraction of the requests that fail, 0-1
+	Hosts []string // Hosts whose requests can fail, with their subdomains (nil = DefaultHosts)
+	Seed  int64    // Seed of the failure sequence (0 = random)
+}
+
+// Fault is a kind of injected failure
+type Fault string
+
+// Injected failures, picked with equal probability
+const (
+	FaultRateLimit   Fault = "429"
+	FaultServerError Fault = "500"
+	FaultTimeout     Fault = "timeout"
+	FaultBodyTimeout Fault = "body-timeout"
+)
+
+var faults = []Fault{FaultRateLimit, FaultServerError, FaultTimeout, FaultBodyTimeout}
+
+// TimeoutError is an injected timeout; like a real one it is a net.Error with Timeout true
+type TimeoutError struct {
+	Host string
+}
+
+func (e *TimeoutError) Error() string   { return fmt.Sprintf("chaos: injected timeout for %s", e.Host) }
+func (e *TimeoutError) Timeout() bool   { return true }
+func (e *TimeoutError) Temporary() bool { return true }
+
+// transport fails a fraction of the requests to the selected hosts
+type transport struct {
+	base  http.RoundTripper
+	rate  float64
+	hosts []string
+
+	mu     sync.Mutex
+	random *rand.Rand
+}
+
+// Configure wraps http.DefaultTransport, and so every HTTP client created afterwards, with
+// failures as configured by opts. Clients that tune a clone of the default *http.Transport
+// (download.read_buffer_size, box.upload_buffer_size) use the default connection buffers instead.
+func Configure(opts Options) error {
+	if opts.Rate < 0 || opts.Rate > 1 {
+		return fmt.Errorf("chaos rate must be between 0 and 1, got %g", opts.Rate)
+	}
+	http.DefaultTransport = NewTransport(http.DefaultTransport, opts)
+	return nil
+}
+
+// NewTransport wraps base (nil = http.DefaultTransport) so requests fail as configured by opts
+func NewTransport(base http.RoundTripper, opts Options) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	hosts := opts.Hosts
+	if hosts == nil {
+		hosts = DefaultHosts
+	}
+	seed := opts.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return &transport{base: base, rate: opts.Rate, hosts: hosts, random: rand.New(rand.NewSource(seed))}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := req.URL.Hostname()
+	fault, cut := t.pick(host)
+	if fault == "" {
+		return t.base.RoundTrip(req)
+	}
+
+	if logger := logging.GetDefaultLogger(); logger != nil {
+		logger.DebugWithContext(req.Context(), "chaos: injecting %s for %s %s%s", fault, req.Method, host, req.URL.Path)
+	}
+
+	switch fault {
+	case FaultRateLimit, FaultServerError:
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return faultResponse(req, fault), nil
+	case FaultTimeout:
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, &TimeoutError{Host: host}
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	limit := int64(0)
+	if resp.ContentLength > 0 {
+		limit = int64(cut * float64(resp.ContentLength))
+	}
+	resp.Body = &cutBody{ReadCloser: resp.Body, remaining: limit, host: host}
+	return resp, nil
+}
+
+// pick returns the failure for a request to host ("" = none) and, for body timeouts, the
+// fraction of the body read before it
+func (t *transport) pick(host string) (Fault, float64) {
+	if !t.matches(host) {
+		return "", 0
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.random.Float64() >= t.rate {
+		return "", 0
+	}
+	return faults[t.random.Intn(len(faults))], t.random.Float64()
+}
+
+// matches reports whether requests to host can fail
+func (t *transport) matches(host string) bool {
+	host = strings.ToLower(host)
+	for _, h := range t.hosts {
+		if host == h || strings.HasSuffix(host, "."+h) {
+			return true
+		}
+	}
+	return false
+}
+
+// faultResponse returns the error response of a status fault
+func faultResponse(req *http.Request, fault Fault) *http.Response {
+	status := http.StatusInternalServerError
+	header := http.Header{"Content-Type": []string{"application/json"}}
+	if fault == FaultRateLimit {
+		status = http.StatusTooManyRequests
+		header.Set("Retry-After", "1")
+	}
+	body := fmt.Sprintf(`{"code": %d, "message": "chaos: injected %s"}`, status, http.StatusText(status))
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// cutBody times out after its first remaining bytes
+type cutBody struct {
+	io.ReadCloser
+	remaining int64
+	host      string
+}
+
+// Read implements io.Reader
+func (b *cutBody) Read(p []byte) (int, error) {
+	if b.remaining <= 0 {
+		return 0, &TimeoutError{Host: b.host}
+	}
+	if int64(len(p)) > b.remaining {
+		p = p[:b.remaining]
+	}
+	n, err := b.ReadCloser.Read(p)
+	b.remaining -= int64(n)
+	return n, err
+}
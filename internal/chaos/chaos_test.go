@@ -0,0 +1,92 @@
+package chaos
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestTransportInjectsEveryFault(t *testing.T) {
+	body := strings.Repeat("x", 1000)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, body)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: NewTransport(nil, Options{Rate: 1, Hosts: []string{"127.0.0.1"}, Seed: 1})}
+
+	seen := map[Fault]bool{}
+	for i := 0; i < 100; i++ {
+		resp, err := client.Get(server.URL)
+		var netErr net.Error
+		if err != nil {
+			if !errors.As(err, &netErr) || !netErr.Timeout() {
+				t.Fatalf("Expected a timeout error, got %v", err)
+			}
+			seen[FaultTimeout] = true
+			continue
+		}
+		data, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		switch {
+		case resp.StatusCode == http.StatusTooManyRequests:
+			if resp.Header.Get("Retry-After") != "1" {
+				t.Errorf("Expected Retry-After on the 429, got %q", resp.Header.Get("Retry-After"))
+			}
+			seen[FaultRateLimit] = true
+		case resp.StatusCode == http.StatusInternalServerError:
+			seen[FaultServerError] = true
+		case err != nil:
+			if !errors.As(err, &netErr) || !netErr.Timeout() || len(data) >= len(body) {
+				t.Fatalf("Expected a timeout partway through the body, got %v after %d bytes", err, len(data))
+			}
+			seen[FaultBodyTimeout] = true
+		default:
+			t.Fatalf("Expected every request to fail at rate 1, got %d with %d bytes", resp.StatusCode, len(data))
+		}
+	}
+
+	for _, fault := range faults {
+		if !seen[fault] {
+			t.Errorf("Expected %s to be injected", fault)
+		}
+	}
+}
+
+func TestTransportOnlyFailsSelectedHosts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "ok")
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: NewTransport(nil, Options{Rate: 1})}
+	for i := 0; i < 10; i++ {
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatalf("Expected requests to other hosts to succeed, got %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected requests to other hosts to succeed, got %d", resp.StatusCode)
+		}
+	}
+
+	tr := NewTransport(nil, Options{}).(*transport)
+	for host, want := range map[string]bool{"api.zoom.us": true, "zoom.us": true, "upload.box.com": true, "dl.boxcloud.com": true, "notzoom.us": false, "example.com": false} {
+		if got := tr.matches(host); got != want {
+			t.Errorf("matches(%q) = %v, want %v", host, got, want)
+		}
+	}
+}
+
+func TestConfigureRejectsInvalidRate(t *testing.T) {
+	for _, rate := range []float64{-0.1, 1.5} {
+		if err := Configure(Options{Rate: rate}); err == nil {
+			t.Errorf("Expected rate %g to be rejected", rate)
+		}
+	}
+}
@@ -2,12 +2,24 @@
 package config
 
 import (
+	"context"
 	"fmt"
+	"net/url"
 	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"text/template"
 	"time"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/curtbushko/zoom-to-box/internal/retry"
+	"github.com/curtbushko/zoom-to-box/internal/schedule"
+	"github.com/curtbushko/zoom-to-box/internal/timefmt"
+	"github.com/curtbushko/zoom-to-box/internal/transcript"
 )
 
 // ZoomConfig holds Zoom API authentication and connection settings
@@ -16,14 +28,118 @@ type ZoomConfig struct {
 	ClientID     string `yaml:"client_id" json:"client_id"`
 	ClientSecret string `yaml:"client_secret" json:"client_secret"`
 	BaseURL      string `yaml:"base_url" json:"base_url"`
+
+	Region   string `yaml:"region" json:"region"`       // Zoom cloud: us (default), eu or gov; sets the default base_url and oauth_url
+	OAuthURL string `yaml:"oauth_url" json:"oauth_url"` // Server-to-Server OAuth token endpoint (default: from region)
+
+	ClientSecretNext string `yaml:"client_secret_next" json:"client_secret_next"` // Tried when client_secret is rejected (secret rotation)
+
+	IncludeWebinars bool `yaml:"include_webinars" json:"include_webinars"` // Also migrate recordings of webinars each user hosted
+
+	AccountRecordings bool `yaml:"account_recordings" json:"account_recordings"` // List the whole account's recordings once per run instead of each user's (needs recording:read:admin, user:read:admin)
+	PageSize          int  `yaml:"page_size" json:"page_size"`                   // Recordings per page of the list recordings API, 1-300 (default: 300)
+
+	IncludeTrash bool `yaml:"include_trash" json:"include_trash"` // Also migrate meeting recordings users moved to the Zoom trash
+	RestoreTrash bool `yaml:"restore_trash" json:"restore_trash"` // Restore trash recordings before downloading them (needs recording:write:admin)
+
+	DisablePasscodes bool `yaml:"disable_passcodes" json:"disable_passcodes"` // Remove the passcode of recordings whose download is refused for it, restoring it afterwards (needs recording:write:admin)
+
+	RateTier              string  `yaml:"rate_tier" json:"rate_tier"`                             // auto (default), free, pro or business; sets API pacing and concurrency
+	RequestsPerSecond     float64 `yaml:"requests_per_second" json:"requests_per_second"`         // Overrides the tier's API request rate (0 = from tier)
+	MaxConcurrentRequests int     `yaml:"max_concurrent_requests" json:"max_concurrent_requests"` // Overrides the tier's API requests in flight (0 = from tier)
+}
+
+// ZoomRegion holds the API and OAuth token endpoints of a Zoom cloud
+type ZoomRegion struct {
+	BaseURL  string
+	OAuthURL string
+}
+
+// ZoomRegions are the zoom.region presets
+var ZoomRegions = map[string]ZoomRegion{
+	"us":  {BaseURL: "https://api.zoom.us/v2", OAuthURL: "https://zoom.us/oauth/token"},
+	"eu":  {BaseURL: "https://eu01api-www4local.zoom.us/v2", OAuthURL: "https://zoom.us/oauth/token"}, // EU data residency
+	"gov": {BaseURL: "https://api.zoomgov.com/v2", OAuthURL: "https://zoomgov.com/oauth/token"},       // Zoom for Government
 }
 
+// zoomGovDomain is the domain of every Zoom for Government endpoint
+const zoomGovDomain = "zoomgov.com"
+
 // BoxConfig holds Box API authentication and settings
 type BoxConfig struct {
 	Enabled      bool   `yaml:"enabled" json:"enabled"`
 	ClientID     string `yaml:"client_id" json:"client_id"`
 	ClientSecret string `yaml:"client_secret" json:"client_secret"`
 	EnterpriseID string `yaml:"enterprise_id" json:"enterprise_id"`
+
+	ClientSecretNext  string `yaml:"client_secret_next" json:"client_secret_next"` // Tried when client_secret is rejected (secret rotation)
+	UploadConcurrency int    `yaml:"upload_concurrency" json:"upload_concurrency"` // Chunked upload parts in flight per file
+
+	UploadPartSize   int64 `yaml:"upload_part_size" json:"upload_part_size"`     // Chunked upload part size in bytes when the upload session does not assign one (0 = 8 MiB)
+	UploadBufferSize int   `yaml:"upload_buffer_size" json:"upload_buffer_size"` // Write buffer of Box connections in bytes (0 = Go's 4 KiB)
+
+	CleanupEmptyFolders bool `yaml:"cleanup_empty_folders" json:"cleanup_empty_folders"` // Remove empty date folders left by failed uploads
+	UploadAsUser        bool `yaml:"upload_as_user" json:"upload_as_user"`               // Create folders and upload with the As-User header so the user owns them
+
+	CreateZoomFolderIfMissing bool `yaml:"create_zoom_folder_if_missing" json:"create_zoom_folder_if_missing"` // Create a user's missing zoom folder instead of failing the user
+	SearchExisting            bool `yaml:"search_existing" json:"search_existing"`                             // Check for existing files with the Box search API instead of listing folders
+
+	DestinationFolderID string `yaml:"destination_folder_id" json:"destination_folder_id"` // Shared folder holding a folder per user, used instead of each user's zoom folder ("" = none)
+
+	CreateSharedLinks string                    `yaml:"create_shared_links" json:"create_shared_links"` // Shared link access for uploaded MP4s: open, company, collaborators ("" = none)
+	MetadataTemplate  BoxMetadataTemplateConfig `yaml:"metadata_template" json:"metadata_template"`     // Metadata template attached to uploaded recordings
+
+	AuthMode    string `yaml:"auth_mode" json:"auth_mode"`       // "client_credentials" (enterprise app) or "user" (box login)
+	TokenFile   string `yaml:"token_file" json:"token_file"`     // Refresh token saved by 'box login' (auth_mode: user)
+	RedirectURL string `yaml:"redirect_url" json:"redirect_url"` // Local callback registered on the Box app for 'box login'
+}
+
+// BoxMetadataTemplateConfig maps the keys of a Box metadata template to recording fields
+type BoxMetadataTemplateConfig struct {
+	Scope       string            `yaml:"scope" json:"scope"`               // "enterprise" (default) or "global"
+	TemplateKey string            `yaml:"template_key" json:"template_key"` // e.g. "zoomRecording" ("" = no template)
+	Fields      map[string]string `yaml:"fields" json:"fields"`             // Template key → recording field, e.g. meetingUuid: uuid
+}
+
+// GoogleDriveConfig holds Google Drive upload settings
+// Uploads impersonate each user through a service account with domain-wide delegation
+type GoogleDriveConfig struct {
+	Enabled         bool   `yaml:"enabled" json:"enabled"`
+	CredentialsFile string `yaml:"credentials_file" json:"credentials_file"`
+	RootFolder      string `yaml:"root_folder" json:"root_folder"`
+}
+
+// S3Config holds Amazon S3 upload settings
+// Credentials come from the standard AWS chain (environment, shared config, SSO, instance roles)
+type S3Config struct {
+	Enabled              bool   `yaml:"enabled" json:"enabled"`
+	Bucket               string `yaml:"bucket" json:"bucket"`
+	Region               string `yaml:"region" json:"region"`             // Overrides the AWS chain region
+	KeyTemplate          string `yaml:"key_template" json:"key_template"` // e.g. {user}/{year}/{month}/{day}/{filename}
+	ServerSideEncryption string `yaml:"server_side_encryption" json:"server_side_encryption"`
+	KMSKeyID             string `yaml:"kms_key_id" json:"kms_key_id"`
+	Endpoint             string `yaml:"endpoint" json:"endpoint"` // For S3-compatible services
+	UsePathStyle         bool   `yaml:"use_path_style" json:"use_path_style"`
+}
+
+// SFTPConfig holds SFTP upload settings for on-prem archival
+// The server's host key must be in the known hosts file; only key-based authentication is used
+type SFTPConfig struct {
+	Enabled        bool   `yaml:"enabled" json:"enabled"`
+	Host           string `yaml:"host" json:"host"`
+	Port           int    `yaml:"port" json:"port"`
+	User           string `yaml:"user" json:"user"`
+	PrivateKeyFile string `yaml:"private_key_file" json:"private_key_file"`
+	KnownHostsFile string `yaml:"known_hosts_file" json:"known_hosts_file"` // Default: ~/.ssh/known_hosts
+	RootPath       string `yaml:"root_path" json:"root_path"`               // Remote directory holding <user>/<year>/<month>/<day>
+}
+
+// WebDAVConfig holds WebDAV upload settings for on-prem archival
+type WebDAVConfig struct {
+	Enabled  bool   `yaml:"enabled" json:"enabled"`
+	URL      string `yaml:"url" json:"url"` // Collection holding <user>/<year>/<month>/<day>
+	Username string `yaml:"username" json:"username"`
+	Password string `yaml:"password" json:"password"`
 }
 
 // DownloadConfig holds download-related settings
@@ -31,6 +147,62 @@ type DownloadConfig struct {
 	OutputDir      string `yaml:"output_dir" json:"output_dir"`
 	RetryAttempts  int    `yaml:"retry_attempts" json:"retry_attempts"`
 	TimeoutSeconds int    `yaml:"timeout_seconds" json:"timeout_seconds"`
+	FromDate       string `yaml:"from_date" json:"from_date"` // YYYY-MM-DD or relative (e.g. 90d)
+	ToDate         string `yaml:"to_date" json:"to_date"`     // YYYY-MM-DD or relative (e.g. 7d)
+
+	ChecksumAlgorithm string `yaml:"checksum_algorithm" json:"checksum_algorithm"` // sha256 (default) or blake3
+	Thumbnails        bool   `yaml:"thumbnails" json:"thumbnails"`                 // Also archive recording thumbnails where Zoom exposes them
+
+	TranscriptFormats []string `yaml:"transcript_formats" json:"transcript_formats"` // Convert downloaded WebVTT transcripts to these sidecars: srt, txt (empty = none)
+
+	PreviewMinutes int `yaml:"preview_minutes" json:"preview_minutes"` // Download only about the first N minutes of each MP4 for a preview archive (0 = full files)
+
+	Stream bool `yaml:"stream" json:"stream"` // Experimental: pipe MP4s of 20 MB and up from Zoom into a Box upload session without a local copy
+
+	ProgressFile bool `yaml:"progress_file" json:"progress_file"` // Keep <output_dir>/progress.json updated for dashboards and scripts
+
+	DiskReserveGB float64       `yaml:"disk_reserve_gb" json:"disk_reserve_gb"` // Keep this much free on the output disk; downloads that do not fit stop the run (0 = not checked)
+	DiskWait      time.Duration `yaml:"disk_wait" json:"disk_wait"`             // Pause this long for space to be freed before stopping, e.g. "30m" (0 = stop immediately)
+	MaxUserGB     float64       `yaml:"max_user_gb" json:"max_user_gb"`         // Cap on each user's local recordings; the rest of the user is left for the next run (0 = no cap)
+
+	ConcurrentLimit int `yaml:"concurrent_limit" json:"concurrent_limit"` // Recording files of a user downloaded at the same time (1-10)
+
+	PerFileTimeout time.Duration `yaml:"per_file_timeout" json:"per_file_timeout"` // Fail a file whose download, retries included, takes longer, e.g. "2h" (0 = no limit)
+
+	SizeTolerancePercent float64 `yaml:"size_tolerance_percent" json:"size_tolerance_percent"` // Reject downloads whose size differs from Zoom's reported size by more than this (default: 1)
+
+	StatusFile string `yaml:"status_file" json:"status_file"` // Per-file download and upload status, used to resume runs ("" = <output_dir>/.status.json)
+
+	ChunkSize      int `yaml:"chunk_size" json:"chunk_size"`             // Bytes written to disk per read of a download (default: 64 KiB)
+	ReadBufferSize int `yaml:"read_buffer_size" json:"read_buffer_size"` // Read buffer of download connections in bytes (0 = Go's 4 KiB)
+}
+
+// Limits of the transfer size settings
+// Box accepts chunked upload parts of 8 MiB up to 128 MiB.
+const (
+	MinDownloadChunkSize = 4 * 1024
+	MaxDownloadChunkSize = 16 * 1024 * 1024
+	MaxBufferSize        = 16 * 1024 * 1024
+	MinUploadPartSize    = 8 * 1024 * 1024
+	MaxUploadPartSize    = 128 * 1024 * 1024
+)
+
+// DiskReserveBytes returns DiskReserveGB in bytes
+func (d DownloadConfig) DiskReserveBytes() uint64 {
+	return uint64(d.DiskReserveGB * (1 << 30))
+}
+
+// StatusFilePath returns the download status file, in the output directory unless configured
+func (d DownloadConfig) StatusFilePath() string {
+	if d.StatusFile != "" {
+		return d.StatusFile
+	}
+	return filepath.Join(d.OutputDir, ".status.json")
+}
+
+// MaxUserBytes returns MaxUserGB in bytes
+func (d DownloadConfig) MaxUserBytes() int64 {
+	return int64(d.MaxUserGB * (1 << 30))
 }
 
 // TimeoutDuration returns the timeout as a time.Duration
@@ -38,12 +210,106 @@ func (d DownloadConfig) TimeoutDuration() time.Duration {
 	return time.Duration(d.TimeoutSeconds) * time.Second
 }
 
+// DateRange resolves FromDate and ToDate relative to now
+// A nil value means the bound was not configured
+func (d DownloadConfig) DateRange(now time.Time) (*time.Time, *time.Time, error) {
+	from, err := ParseDateValue(d.FromDate, now)
+	if err != nil {
+		return nil, nil, fmt.Errorf("download.from_date: %w", err)
+	}
+	to, err := ParseDateValue(d.ToDate, now)
+	if err != nil {
+		return nil, nil, fmt.Errorf("download.to_date: %w", err)
+	}
+	if from != nil && to != nil && from.After(*to) {
+		return nil, nil, fmt.Errorf("download.from_date (%s) must not be after download.to_date (%s)",
+			from.Format("2006-01-02"), to.Format("2006-01-02"))
+	}
+	return from, to, nil
+}
+
+// ParseDateValue parses an absolute date (YYYY-MM-DD) or a relative value
+// counted back from now: Nd (days), Nw (weeks), Nm (months) or Ny (years).
+// An empty value returns nil.
+func ParseDateValue(value string, now time.Time) (*time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return &t, nil
+	}
+
+	unit := strings.ToLower(value[len(value)-1:])
+	amount, err := strconv.Atoi(value[:len(value)-1])
+	if err != nil || amount < 0 {
+		return nil, fmt.Errorf("invalid date %q: expected YYYY-MM-DD or a relative value like 90d", value)
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	var t time.Time
+	switch unit {
+	case "d":
+		t = today.AddDate(0, 0, -amount)
+	case "w":
+		t = today.AddDate(0, 0, -7*amount)
+	case "m":
+		t = today.AddDate(0, -amount, 0)
+	case "y":
+		t = today.AddDate(-amount, 0, 0)
+	default:
+		return nil, fmt.Errorf("invalid date %q: relative unit must be one of d, w, m, y", value)
+	}
+
+	return &t, nil
+}
+
 // LoggingConfig holds logging settings
 type LoggingConfig struct {
 	Level      string `yaml:"level" json:"level"`
 	File       string `yaml:"file" json:"file"`
 	Console    bool   `yaml:"console" json:"console"`
 	JSONFormat bool   `yaml:"json_format" json:"json_format"`
+
+	Rotation   LogRotationConfig   `yaml:"rotation" json:"rotation"`       // Rotation of the log file (zero value = one growing file)
+	JSONSchema LogJSONSchemaConfig `yaml:"json_schema" json:"json_schema"` // Key names and extra fields of JSON log lines
+}
+
+// LogRotationConfig controls when the log file is rotated and how many rotated files are kept
+// Rotated files are renamed to <name>-<YYYYMMDD-HHMMSS.mmm><ext> next to the log file.
+type LogRotationConfig struct {
+	MaxSizeMB  int           `yaml:"max_size_mb" json:"max_size_mb"` // Rotate before the file grows past this size (0 = no size limit)
+	Interval   time.Duration `yaml:"interval" json:"interval"`       // Rotate when a new interval starts, aligned to UTC: "24h" rotates at midnight (0 = never)
+	MaxBackups int           `yaml:"max_backups" json:"max_backups"` // Rotated files kept (0 = all)
+	MaxAge     time.Duration `yaml:"max_age" json:"max_age"`         // Remove rotated files older than this, e.g. "720h" (0 = never)
+	Compress   bool          `yaml:"compress" json:"compress"`       // Gzip rotated files
+}
+
+// Enabled reports whether any rotation setting is configured
+func (r LogRotationConfig) Enabled() bool {
+	return r.MaxSizeMB > 0 || r.Interval > 0 || r.MaxBackups > 0 || r.MaxAge > 0 || r.Compress
+}
+
+// LogJSONSchemaConfig renames the standard keys of JSON log lines and adds fixed fields, e.g. to
+// match the Elastic Common Schema when logs are shipped to ELK
+type LogJSONSchemaConfig struct {
+	TimestampKey string            `yaml:"timestamp_key" json:"timestamp_key"`   // Default: timestamp (ECS: @timestamp)
+	LevelKey     string            `yaml:"level_key" json:"level_key"`           // Default: level (ECS: log.level)
+	MessageKey   string            `yaml:"message_key" json:"message_key"`       // Default: message
+	RequestIDKey string            `yaml:"request_id_key" json:"request_id_key"` // Default: request_id (ECS: trace.id)
+	StaticFields map[string]string `yaml:"static_fields" json:"static_fields"`   // Added to every line, e.g. service.name: zoom-to-box
+}
+
+// Keys returns the keys of the standard fields, with the defaults for those not configured
+func (s LogJSONSchemaConfig) Keys() (timestamp, level, message, requestID string) {
+	pick := func(key, fallback string) string {
+		if key == "" {
+			return fallback
+		}
+		return key
+	}
+	return pick(s.TimestampKey, "timestamp"), pick(s.LevelKey, "level"), pick(s.MessageKey, "message"), pick(s.RequestIDKey, "request_id")
 }
 
 // ActiveUsersConfig holds active users list settings
@@ -52,17 +318,240 @@ type ActiveUsersConfig struct {
 	CheckEnabled bool   `yaml:"check_enabled" json:"check_enabled"`
 }
 
+// WebhookConfig holds settings for the `serve` webhook listener
+type WebhookConfig struct {
+	ListenAddress string `yaml:"listen_address" json:"listen_address"`
+	Path          string `yaml:"path" json:"path"`
+	SecretToken   string `yaml:"secret_token" json:"secret_token"` // Zoom app's webhook secret token, used to verify signatures
+	QueueSize     int    `yaml:"queue_size" json:"queue_size"`     // Recordings that can wait for processing
+}
+
+// MonitorConfig controls resource usage sampling for long runs
+type MonitorConfig struct {
+	Interval       time.Duration `yaml:"interval" json:"interval"`                 // Time between resource usage samples, e.g. "5m" (0 = disabled)
+	MaxHeapMB      int           `yaml:"max_heap_mb" json:"max_heap_mb"`           // Heap limit in MB (0 = no limit)
+	MaxGoroutines  int           `yaml:"max_goroutines" json:"max_goroutines"`     // Goroutine limit (0 = no limit)
+	MaxOpenFiles   int           `yaml:"max_open_files" json:"max_open_files"`     // Open file descriptor limit (0 = no limit)
+	RestartOnLimit bool          `yaml:"restart_on_limit" json:"restart_on_limit"` // Checkpoint and exit with status 75 when a limit is exceeded
+}
+
+// TimeConfig controls how timestamps are shown in logs, CSVs, reports and date folders
+type TimeConfig struct {
+	Timezone string `yaml:"timezone" json:"timezone"` // "UTC", "Local" or an IANA name such as "America/Toronto"
+	Format   string `yaml:"format" json:"format"`     // rfc3339, rfc3339nano, datetime, datetime-tz or a Go time layout
+}
+
+// FilenameConfig controls how downloaded recording files are named
+type FilenameConfig struct {
+	Template    string `yaml:"template" json:"template"`         // Go text/template, e.g. "{{.Date}}_{{.Topic}}_{{.HostEmail}}_{{.RecordingType}}"
+	WindowsSafe bool   `yaml:"windows_safe" json:"windows_safe"` // Mangle reserved Windows names (CON, NUL, ...), keep local paths within preflight.max_path_length (default: 259) and use \\?\ long paths
+}
+
+// DirectoryConfig controls the folders recordings are stored in, locally and in the upload destination
+type DirectoryConfig struct {
+	Layout string `yaml:"layout" json:"layout"` // Go text/template, e.g. "{{.Year}}/{{.Month}}/{{.User}}"
+}
+
+// PreflightConfig holds the checks run on the output filesystem before downloads start
+type PreflightConfig struct {
+	MinFreeMB     int64 `yaml:"min_free_mb" json:"min_free_mb"`         // Required free space in MB (0 = not checked)
+	MinFreeInodes int64 `yaml:"min_free_inodes" json:"min_free_inodes"` // Required free inodes (0 = not checked)
+	MaxPathLength int   `yaml:"max_path_length" json:"max_path_length"` // Path length limit when lower than the OS limit, e.g. 260 for Windows shares (0 = OS limit)
+}
+
+// TokenCacheConfig controls caching of Zoom and Box access tokens between runs
+type TokenCacheConfig struct {
+	Enabled bool   `yaml:"enabled" json:"enabled"`
+	File    string `yaml:"file" json:"file"` // Encrypted token cache; keep it private (mode 0600)
+}
+
+// TracingConfig controls OpenTelemetry tracing of runs
+type TracingConfig struct {
+	Enabled     bool              `yaml:"enabled" json:"enabled"`
+	Endpoint    string            `yaml:"endpoint" json:"endpoint"`         // OTLP/HTTP endpoint URL, e.g. "http://localhost:4318" ("" = OTEL_EXPORTER_OTLP_ENDPOINT)
+	Headers     map[string]string `yaml:"headers" json:"headers"`           // Extra headers sent to the endpoint, e.g. an API key
+	ServiceName string            `yaml:"service_name" json:"service_name"` // Service name on the spans (default: zoom-to-box)
+	SampleRatio float64           `yaml:"sample_ratio" json:"sample_ratio"` // Fraction of runs traced (default: 1)
+}
+
+// EncryptionConfig controls encryption of downloaded MP4s on disk
+// The key comes from exactly one of Key, KeyFile or KMSEncryptedKey.
+type EncryptionConfig struct {
+	Enabled         bool   `yaml:"enabled" json:"enabled"`
+	Key             string `yaml:"key" json:"key"`                             // 32-byte AES-256 key as hex or base64
+	KeyFile         string `yaml:"key_file" json:"key_file"`                   // File holding the key
+	KMSEncryptedKey string `yaml:"kms_encrypted_key" json:"kms_encrypted_key"` // Data key encrypted with AWS KMS, base64
+	KMSRegion       string `yaml:"kms_region" json:"kms_region"`               // Overrides the AWS chain region for KMS
+	TempDir         string `yaml:"temp_dir" json:"temp_dir"`                   // Where decrypted copies are staged during uploads ("" = OS temp dir)
+}
+
+// NetworkConfig controls how the Zoom, Box and download HTTP clients reach the internet
+type NetworkConfig struct {
+	ProxyURL   string `yaml:"proxy_url" json:"proxy_url"`       // http, https, socks5 or socks5h proxy for all requests ("" = HTTP_PROXY/HTTPS_PROXY)
+	CACertFile string `yaml:"ca_cert_file" json:"ca_cert_file"` // PEM bundle trusted in addition to the system CAs, e.g. a corporate proxy's CA
+
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify" json:"insecure_skip_verify"` // Accept any TLS certificate; for debugging TLS-intercepting middleboxes only
+	MinTLSVersion      string `yaml:"min_tls_version" json:"min_tls_version"`           // Oldest TLS version accepted: 1.0, 1.1, 1.2 (default) or 1.3
+}
+
+// NotificationsConfig holds the chat webhooks that receive a summary when a batch run ends
+type NotificationsConfig struct {
+	Slack         NotificationWebhookConfig `yaml:"slack" json:"slack"`
+	Teams         NotificationWebhookConfig `yaml:"teams" json:"teams"`
+	OnFailureOnly bool                      `yaml:"on_failure_only" json:"on_failure_only"` // Only notify when a run aborts, stops early or has failed users
+
+	Email EmailNotificationConfig `yaml:"email" json:"email"`
+}
+
+// EmailNotificationConfig holds the SMTP settings for emailed run summaries
+type EmailNotificationConfig struct {
+	SMTPHost    string   `yaml:"smtp_host" json:"smtp_host"`       // SMTP server ("" = email disabled)
+	SMTPPort    int      `yaml:"smtp_port" json:"smtp_port"`       // Default: 587
+	Username    string   `yaml:"username" json:"username"`         // SMTP login ("" = no authentication)
+	Password    string   `yaml:"password" json:"password"`         // SMTP password
+	ImplicitTLS bool     `yaml:"implicit_tls" json:"implicit_tls"` // TLS from the first byte (usually port 465); otherwise STARTTLS when offered
+	From        string   `yaml:"from" json:"from"`
+	To          []string `yaml:"to" json:"to"`
+	Subject     string   `yaml:"subject" json:"subject"` // Go template with .Status, .Reason, .Date and .FailedUsers
+	Attach      []string `yaml:"attach" json:"attach"`   // Run report formats to attach: json, csv
+}
+
+// NotificationWebhookConfig holds an incoming webhook
+type NotificationWebhookConfig struct {
+	WebhookURL string `yaml:"webhook_url" json:"webhook_url"` // Incoming webhook URL ("" = disabled)
+}
+
+// HooksConfig holds the commands and URL fired after each upload and after each user
+type HooksConfig struct {
+	PostUpload    []string      `yaml:"post_upload" json:"post_upload"`         // Command templates, e.g. "./notify.sh {{.User}} {{.File}}"; run without a shell
+	PostUploadURL string        `yaml:"post_upload_url" json:"post_upload_url"` // Receives each event as a JSON POST ("" = none)
+	Timeout       time.Duration `yaml:"timeout" json:"timeout"`                 // Limit for each command and post (default: 30s)
+}
+
+// DaemonConfig holds settings for the scheduled batch runs of `daemon`
+type DaemonConfig struct {
+	Schedule  string `yaml:"schedule" json:"schedule"`     // Cron expression in time.timezone, e.g. "0 2 * * *" for 02:00 daily
+	ReportDir string `yaml:"report_dir" json:"report_dir"` // Directory for the per-run JSON reports (default: <output_dir>/reports)
+}
+
+// ControlConfig holds settings for the control API served by `serve`
+type ControlConfig struct {
+	Enabled bool   `yaml:"enabled" json:"enabled"`
+	Token   string `yaml:"token" json:"token"` // Bearer token clients must send to use the API
+}
+
+// RetryConfig holds retry policies for each class of remote operation
+// Durations are written as Go durations, e.g. "500ms" or "30s".
+type RetryConfig struct {
+	ZoomAPI      retry.Policy `yaml:"zoom_api" json:"zoom_api"`           // Zoom REST API calls
+	ZoomDownload retry.Policy `yaml:"zoom_download" json:"zoom_download"` // Recording file downloads
+	BoxAPI       retry.Policy `yaml:"box_api" json:"box_api"`             // Box API calls (folders, users, lookups)
+	BoxUpload    retry.Policy `yaml:"box_upload" json:"box_upload"`       // Box chunked upload parts
+
+	BoxCircuitBreaker retry.CircuitBreakerConfig `yaml:"box_circuit_breaker" json:"box_circuit_breaker"` // Pause all Box traffic after consecutive failed requests
+}
+
+// FiltersConfig selects which recordings are archived
+type FiltersConfig struct {
+	MinDurationMinutes int    `yaml:"min_duration_minutes" json:"min_duration_minutes"` // Skip recordings shorter than this (0 = no minimum)
+	MinFileSize        int64  `yaml:"min_file_size" json:"min_file_size"`               // Skip recording files Zoom reports as smaller than this many bytes (0 = no minimum)
+	TopicRegex         string `yaml:"topic_regex" json:"topic_regex"`                   // Only archive recordings whose topic matches
+	ExcludeTopicRegex  string `yaml:"exclude_topic_regex" json:"exclude_topic_regex"`   // Skip recordings whose topic matches
+	MeetingTypes       []int  `yaml:"meeting_types" json:"meeting_types"`               // Only archive these Zoom meeting types (empty = all)
+}
+
+// UploadConfig controls how recordings are uploaded to the destination
+type UploadConfig struct {
+	MetadataOrder  string `yaml:"metadata_order" json:"metadata_order"`   // Upload the metadata JSON "after" (default) or "before" the recording
+	ConflictPolicy string `yaml:"conflict_policy" json:"conflict_policy"` // Existing file with a different size: "version" (default), "replace" or "report"
+
+	DeleteRequiresVerification bool `yaml:"delete_requires_verification" json:"delete_requires_verification"` // --delete-after-upload only deletes files whose size and SHA1 Box confirmed
+
+	MigrationReport bool `yaml:"migration_report" json:"migration_report"` // Upload a MIGRATION-REPORT.md summary to each user's zoom folder for users to check their migration
+}
+
+// MetadataConfig controls the metadata sidecar saved next to each recording
+type MetadataConfig struct {
+	Format              string `yaml:"format" json:"format"`                             // Sidecar format: "json" (default), "yaml" or "none"
+	IncludeParticipants bool   `yaml:"include_participants" json:"include_participants"` // Add the host's name and the meeting participants (extra Zoom API calls per recording)
+}
+
+// TrackingConfig holds the CSV reports written besides the per-user uploads.csv files
+type TrackingConfig struct {
+	RunSummary     bool   `yaml:"run_summary" json:"run_summary"`           // Write one row per recording file of the run to a consolidated CSV
+	RunSummaryFile string `yaml:"run_summary_file" json:"run_summary_file"` // Run summary CSV ("" = <output_dir>/run-summary.csv)
+	RunSummaryMode string `yaml:"run_summary_mode" json:"run_summary_mode"` // "overwrite" (default) starts the file over each run; "append" keeps earlier runs
+}
+
+// RunSummaryFilePath returns the run summary CSV, in outputDir unless configured
+func (t TrackingConfig) RunSummaryFilePath(outputDir string) string {
+	if t.RunSummaryFile != "" {
+		return t.RunSummaryFile
+	}
+	return filepath.Join(outputDir, "run-summary.csv")
+}
+
+// ProcessorConfig holds settings for processing each user
+type ProcessorConfig struct {
+	UserTimeout time.Duration `yaml:"user_timeout" json:"user_timeout"` // Fail a user that takes longer and move on to the next, e.g. "4h" (0 = no limit)
+
+	TransientRetries    int           `yaml:"transient_retries" json:"transient_retries"`         // End-of-run passes that retry users who failed on rate limits, 5xx or network errors (0 = none)
+	TransientRetryDelay time.Duration `yaml:"transient_retry_delay" json:"transient_retry_delay"` // Wait before each retry pass (default: 1m)
+}
+
+// LimitsConfig holds limits that bound a single batch run
+type LimitsConfig struct {
+	MaxRunDuration time.Duration `yaml:"max_run_duration" json:"max_run_duration"` // Stop starting new files after this long, e.g. "6h" (0 = no limit)
+}
+
 // Config represents the complete application configuration
 type Config struct {
 	Zoom        ZoomConfig        `yaml:"zoom" json:"zoom"`
 	Box         BoxConfig         `yaml:"box" json:"box"`
+	GoogleDrive GoogleDriveConfig `yaml:"google_drive" json:"google_drive"`
+	S3          S3Config          `yaml:"s3" json:"s3"`
+	SFTP        SFTPConfig        `yaml:"sftp" json:"sftp"`
+	WebDAV      WebDAVConfig      `yaml:"webdav" json:"webdav"`
 	Download    DownloadConfig    `yaml:"download" json:"download"`
 	Logging     LoggingConfig     `yaml:"logging" json:"logging"`
 	ActiveUsers ActiveUsersConfig `yaml:"active_users" json:"active_users"`
+	Webhook     WebhookConfig     `yaml:"webhook" json:"webhook"`
+	Retry       RetryConfig       `yaml:"retry" json:"retry"`
+	Limits      LimitsConfig      `yaml:"limits" json:"limits"`
+	Processor   ProcessorConfig   `yaml:"processor" json:"processor"`
+	Filters     FiltersConfig     `yaml:"filters" json:"filters"`
+	Upload      UploadConfig      `yaml:"upload" json:"upload"`
+	Tracking    TrackingConfig    `yaml:"tracking" json:"tracking"`
+	Metadata    MetadataConfig    `yaml:"metadata" json:"metadata"`
+	Control     ControlConfig     `yaml:"control" json:"control"`
+	Monitor     MonitorConfig     `yaml:"monitor" json:"monitor"`
+	Time        TimeConfig        `yaml:"time" json:"time"`
+	Filename    FilenameConfig    `yaml:"filename" json:"filename"`
+	Directory   DirectoryConfig   `yaml:"directory" json:"directory"`
+	Preflight   PreflightConfig   `yaml:"preflight" json:"preflight"`
+	TokenCache  TokenCacheConfig  `yaml:"token_cache" json:"token_cache"`
+	Tracing     TracingConfig     `yaml:"tracing" json:"tracing"`
+	Daemon      DaemonConfig      `yaml:"daemon" json:"daemon"`
+	Encryption  EncryptionConfig  `yaml:"encryption" json:"encryption"`
+	Network     NetworkConfig     `yaml:"network" json:"network"`
+
+	Notifications NotificationsConfig `yaml:"notifications" json:"notifications"`
+	Hooks         HooksConfig         `yaml:"hooks" json:"hooks"`
+
+	// Profiles holds named overlays of the settings above, e.g. one per Zoom account.
+	// A selected profile's sections replace the matching top-level settings key by key.
+	Profiles map[string]yaml.Node `yaml:"profiles" json:"-"`
+	Profile  string               `yaml:"-" json:"profile,omitempty"` // Name of the applied profile ("" = none)
 }
 
 // LoadConfig loads configuration from a YAML file with defaults and environment variable overrides
 func LoadConfig(configPath string) (*Config, error) {
+	return LoadConfigWithProfile(configPath, "")
+}
+
+// LoadConfigWithProfile loads configuration like LoadConfig, then applies the named profile
+// from the file's profiles section before defaults and environment overrides ("" = no profile)
+func LoadConfigWithProfile(configPath, profile string) (*Config, error) {
 	config := &Config{}
 
 	// Load from YAML file
@@ -70,12 +559,27 @@ func LoadConfig(configPath string) (*Config, error) {
 		return nil, fmt.Errorf("failed to load config from file: %w", err)
 	}
 
+	// Overlay the selected profile
+	if err := config.applyProfile(profile); err != nil {
+		return nil, err
+	}
+
 	// Apply defaults
 	config.setDefaults()
 
 	// Override with environment variables
 	config.loadFromEnvironment()
 
+	// Fill the Zoom endpoints from zoom.region, which may itself come from the environment
+	config.applyZoomRegion()
+
+	// Replace vault: and aws-sm: references with the secrets they name
+	ctx, cancel := context.WithTimeout(context.Background(), secretResolveTimeout)
+	defer cancel()
+	if err := config.resolveSecrets(ctx); err != nil {
+		return nil, err
+	}
+
 	// Validate configuration
 	if err := config.Validate(); err != nil {
 		return nil, fmt.Errorf("configuration validation failed: %w", err)
@@ -98,15 +602,103 @@ func (c *Config) loadFromFile(configPath string) error {
 	return nil
 }
 
+// applyProfile overlays the named profile onto the settings loaded from the file
+// Settings the profile does not mention keep their top-level values, so shared settings
+// only need to be written once.
+func (c *Config) applyProfile(profile string) error {
+	if profile == "" {
+		return nil
+	}
+
+	node, ok := c.Profiles[profile]
+	if !ok {
+		names := c.ProfileNames()
+		if len(names) == 0 {
+			return fmt.Errorf("profile %q not found: the config file has no profiles section", profile)
+		}
+		return fmt.Errorf("profile %q not found (available: %s)", profile, strings.Join(names, ", "))
+	}
+
+	var overlay Config
+	if err := node.Decode(&overlay); err != nil {
+		return fmt.Errorf("failed to parse profile %q: %w", profile, err)
+	}
+	if len(overlay.Profiles) > 0 {
+		return fmt.Errorf("profile %q cannot contain nested profiles", profile)
+	}
+
+	// Decode again onto the loaded settings so the profile's keys replace them one by one
+	if err := node.Decode(c); err != nil {
+		return fmt.Errorf("failed to apply profile %q: %w", profile, err)
+	}
+	c.Profile = profile
+	return nil
+}
+
+// ProfileNames returns the names of the profiles in the config file, sorted
+func (c *Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 // setDefaults applies default values for missing configuration
 func (c *Config) setDefaults() {
-	// Zoom defaults
-	if c.Zoom.BaseURL == "" {
-		c.Zoom.BaseURL = "https://api.zoom.us/v2"
+	// Zoom defaults; the endpoints are set by applyZoomRegion
+	if c.Zoom.RateTier == "" {
+		c.Zoom.RateTier = "auto"
+	}
+	if c.Zoom.PageSize == 0 {
+		c.Zoom.PageSize = 300
 	}
 
 	// Box defaults
 	// Box.Enabled defaults to false (zero value)
+	if c.Box.UploadConcurrency == 0 {
+		c.Box.UploadConcurrency = 4
+	}
+	if c.Box.AuthMode == "" {
+		c.Box.AuthMode = "client_credentials"
+	}
+	if c.Box.TokenFile == "" {
+		c.Box.TokenFile = "box-token.json"
+	}
+	if c.TokenCache.File == "" {
+		c.TokenCache.File = "token-cache.json"
+	}
+	if c.Network.MinTLSVersion == "" {
+		c.Network.MinTLSVersion = "1.2"
+	}
+	if c.Tracing.ServiceName == "" {
+		c.Tracing.ServiceName = "zoom-to-box"
+	}
+	if c.Tracing.SampleRatio == 0 {
+		c.Tracing.SampleRatio = 1
+	}
+	if c.Box.RedirectURL == "" {
+		c.Box.RedirectURL = "http://localhost:8085/callback"
+	}
+
+	// Google Drive defaults
+	if c.GoogleDrive.RootFolder == "" {
+		c.GoogleDrive.RootFolder = "zoom"
+	}
+
+	// S3 defaults
+	if c.S3.KeyTemplate == "" {
+		c.S3.KeyTemplate = "{user}/{year}/{month}/{day}/{filename}"
+	}
+
+	// SFTP defaults
+	if c.SFTP.Port == 0 {
+		c.SFTP.Port = 22
+	}
+	if c.SFTP.RootPath == "" {
+		c.SFTP.RootPath = "zoom"
+	}
 
 	// Download defaults
 	if c.Download.OutputDir == "" {
@@ -118,6 +710,37 @@ func (c *Config) setDefaults() {
 	if c.Download.TimeoutSeconds == 0 {
 		c.Download.TimeoutSeconds = 300
 	}
+	if c.Download.ConcurrentLimit == 0 {
+		c.Download.ConcurrentLimit = 3
+	}
+	if c.Download.ChecksumAlgorithm == "" {
+		c.Download.ChecksumAlgorithm = "sha256"
+	}
+	if c.Download.ChunkSize == 0 {
+		c.Download.ChunkSize = 64 * 1024
+	}
+	if c.Download.SizeTolerancePercent == 0 {
+		c.Download.SizeTolerancePercent = 1
+	}
+
+	// Retry defaults
+	// download.retry_attempts still sets the Zoom attempt counts unless a policy overrides them
+	if c.Retry.ZoomAPI.MaxAttempts == 0 {
+		c.Retry.ZoomAPI.MaxAttempts = c.Download.RetryAttempts + 1
+	}
+	if c.Retry.ZoomDownload.MaxAttempts == 0 {
+		c.Retry.ZoomDownload.MaxAttempts = c.Download.RetryAttempts + 1
+	}
+	c.Retry.ZoomAPI = c.Retry.ZoomAPI.WithDefaults(retry.DefaultZoomAPI)
+	c.Retry.ZoomDownload = c.Retry.ZoomDownload.WithDefaults(retry.DefaultZoomDownload)
+	c.Retry.BoxAPI = c.Retry.BoxAPI.WithDefaults(retry.DefaultBoxAPI)
+	c.Retry.BoxUpload = c.Retry.BoxUpload.WithDefaults(retry.DefaultBoxUpload)
+	c.Retry.BoxCircuitBreaker = c.Retry.BoxCircuitBreaker.WithDefaults(retry.DefaultBoxCircuitBreaker)
+
+	// Processor defaults
+	if c.Processor.TransientRetryDelay == 0 {
+		c.Processor.TransientRetryDelay = time.Minute
+	}
 
 	// Logging defaults
 	if c.Logging.Level == "" {
@@ -130,6 +753,14 @@ func (c *Config) setDefaults() {
 	// Note: This will always set to true, override in YAML if false is desired
 	c.Logging.Console = true
 
+	// Time defaults
+	if c.Time.Timezone == "" {
+		c.Time.Timezone = "UTC"
+	}
+	if c.Time.Format == "" {
+		c.Time.Format = "rfc3339"
+	}
+
 	// Active users defaults
 	if c.ActiveUsers.File == "" {
 		c.ActiveUsers.File = "./active_users.txt"
@@ -137,6 +768,35 @@ func (c *Config) setDefaults() {
 	// CheckEnabled defaults to true (if not explicitly configured)
 	// Note: This will always set to true, override in YAML if false is desired
 	c.ActiveUsers.CheckEnabled = true
+
+	// Webhook defaults
+	if c.Webhook.ListenAddress == "" {
+		c.Webhook.ListenAddress = ":8080"
+	}
+	if c.Webhook.Path == "" {
+		c.Webhook.Path = "/zoom/webhook"
+	}
+	if c.Webhook.QueueSize == 0 {
+		c.Webhook.QueueSize = 100
+	}
+
+	// Upload defaults
+	if c.Upload.MetadataOrder == "" {
+		c.Upload.MetadataOrder = "after"
+	}
+	if c.Upload.ConflictPolicy == "" {
+		c.Upload.ConflictPolicy = "version"
+	}
+
+	// Metadata defaults
+	if c.Metadata.Format == "" {
+		c.Metadata.Format = "json"
+	}
+
+	// Tracking defaults
+	if c.Tracking.RunSummaryMode == "" {
+		c.Tracking.RunSummaryMode = "overwrite"
+	}
 }
 
 // loadFromEnvironment overrides configuration with environment variables
@@ -150,9 +810,21 @@ func (c *Config) loadFromEnvironment() {
 	if val := os.Getenv("ZOOM_CLIENT_SECRET"); val != "" {
 		c.Zoom.ClientSecret = val
 	}
+	if val := os.Getenv("ZOOM_CLIENT_SECRET_NEXT"); val != "" {
+		c.Zoom.ClientSecretNext = val
+	}
 	if val := os.Getenv("ZOOM_BASE_URL"); val != "" {
 		c.Zoom.BaseURL = val
 	}
+	if val := os.Getenv("ZOOM_REGION"); val != "" {
+		c.Zoom.Region = val
+	}
+	if val := os.Getenv("ZOOM_OAUTH_URL"); val != "" {
+		c.Zoom.OAuthURL = val
+	}
+	if val := os.Getenv("ZOOM_RATE_TIER"); val != "" {
+		c.Zoom.RateTier = val
+	}
 
 	if val := os.Getenv("BOX_CLIENT_ID"); val != "" {
 		c.Box.ClientID = val
@@ -160,13 +832,185 @@ func (c *Config) loadFromEnvironment() {
 	if val := os.Getenv("BOX_CLIENT_SECRET"); val != "" {
 		c.Box.ClientSecret = val
 	}
+	if val := os.Getenv("BOX_CLIENT_SECRET_NEXT"); val != "" {
+		c.Box.ClientSecretNext = val
+	}
 	if val := os.Getenv("BOX_ENTERPRISE_ID"); val != "" {
 		c.Box.EnterpriseID = val
 	}
+	if val := os.Getenv("BOX_AUTH_MODE"); val != "" {
+		c.Box.AuthMode = val
+	}
+	if val := os.Getenv("BOX_TOKEN_FILE"); val != "" {
+		c.Box.TokenFile = val
+	}
+
+	if val := os.Getenv("GOOGLE_DRIVE_CREDENTIALS_FILE"); val != "" {
+		c.GoogleDrive.CredentialsFile = val
+	}
+
+	if val := os.Getenv("S3_BUCKET"); val != "" {
+		c.S3.Bucket = val
+	}
+
+	if val := os.Getenv("SFTP_PRIVATE_KEY_FILE"); val != "" {
+		c.SFTP.PrivateKeyFile = val
+	}
+
+	if val := os.Getenv("WEBDAV_USERNAME"); val != "" {
+		c.WebDAV.Username = val
+	}
+
+	if val := os.Getenv("WEBDAV_PASSWORD"); val != "" {
+		c.WebDAV.Password = val
+	}
 
 	if val := os.Getenv("DOWNLOAD_OUTPUT_DIR"); val != "" {
 		c.Download.OutputDir = val
 	}
+
+	if val := os.Getenv("DOWNLOAD_DISK_RESERVE_GB"); val != "" {
+		if gb, err := strconv.ParseFloat(val, 64); err == nil {
+			c.Download.DiskReserveGB = gb
+		}
+	}
+
+	if val := os.Getenv("ZOOM_WEBHOOK_SECRET_TOKEN"); val != "" {
+		c.Webhook.SecretToken = val
+	}
+
+	if val := os.Getenv("CONTROL_API_TOKEN"); val != "" {
+		c.Control.Token = val
+	}
+
+	if val := os.Getenv("LIMITS_MAX_RUN_DURATION"); val != "" {
+		if d, err := time.ParseDuration(val); err == nil {
+			c.Limits.MaxRunDuration = d
+		}
+	}
+
+	if val := os.Getenv("MONITOR_INTERVAL"); val != "" {
+		if d, err := time.ParseDuration(val); err == nil {
+			c.Monitor.Interval = d
+		}
+	}
+
+	if val := os.Getenv("NETWORK_PROXY_URL"); val != "" {
+		c.Network.ProxyURL = val
+	}
+	if val := os.Getenv("NETWORK_CA_CERT_FILE"); val != "" {
+		c.Network.CACertFile = val
+	}
+	if val := os.Getenv("NETWORK_INSECURE_SKIP_VERIFY"); val != "" {
+		if skip, err := strconv.ParseBool(val); err == nil {
+			c.Network.InsecureSkipVerify = skip
+		}
+	}
+
+	if val := os.Getenv("TIME_TIMEZONE"); val != "" {
+		c.Time.Timezone = val
+	}
+	if val := os.Getenv("TIME_FORMAT"); val != "" {
+		c.Time.Format = val
+	}
+
+	if val := os.Getenv("FILENAME_TEMPLATE"); val != "" {
+		c.Filename.Template = val
+	}
+	if val := os.Getenv("DIRECTORY_LAYOUT"); val != "" {
+		c.Directory.Layout = val
+	}
+
+	if val := os.Getenv("PREFLIGHT_MIN_FREE_MB"); val != "" {
+		if n, err := strconv.ParseInt(val, 10, 64); err == nil {
+			c.Preflight.MinFreeMB = n
+		}
+	}
+	if val := os.Getenv("PREFLIGHT_MIN_FREE_INODES"); val != "" {
+		if n, err := strconv.ParseInt(val, 10, 64); err == nil {
+			c.Preflight.MinFreeInodes = n
+		}
+	}
+
+	if val := os.Getenv("TOKEN_CACHE_FILE"); val != "" {
+		c.TokenCache.File = val
+	}
+	if val := os.Getenv("TRACING_ENDPOINT"); val != "" {
+		c.Tracing.Endpoint = val
+	}
+	if val := os.Getenv("DAEMON_SCHEDULE"); val != "" {
+		c.Daemon.Schedule = val
+	}
+	if val := os.Getenv("ENCRYPTION_KEY"); val != "" {
+		c.Encryption.Key = val
+	}
+	if val := os.Getenv("SLACK_WEBHOOK_URL"); val != "" {
+		c.Notifications.Slack.WebhookURL = val
+	}
+	if val := os.Getenv("TEAMS_WEBHOOK_URL"); val != "" {
+		c.Notifications.Teams.WebhookURL = val
+	}
+	if val := os.Getenv("SMTP_PASSWORD"); val != "" {
+		c.Notifications.Email.Password = val
+	}
+
+	if val := os.Getenv("UPLOAD_METADATA_ORDER"); val != "" {
+		c.Upload.MetadataOrder = val
+	}
+	if val := os.Getenv("UPLOAD_CONFLICT_POLICY"); val != "" {
+		c.Upload.ConflictPolicy = val
+	}
+}
+
+// applyZoomRegion sets the Zoom API and OAuth endpoints not configured explicitly from zoom.region
+// An unknown region is left for Validate to report.
+func (c *Config) applyZoomRegion() {
+	if c.Zoom.Region == "" {
+		c.Zoom.Region = "us"
+	}
+	region, ok := ZoomRegions[strings.ToLower(c.Zoom.Region)]
+	if !ok {
+		return
+	}
+	if c.Zoom.BaseURL == "" {
+		c.Zoom.BaseURL = region.BaseURL
+	}
+	if c.Zoom.OAuthURL == "" {
+		c.Zoom.OAuthURL = region.OAuthURL
+	}
+}
+
+// validateZoomEndpoints checks that the Zoom API and OAuth endpoints are URLs of the same Zoom cloud
+func (c *Config) validateZoomEndpoints() error {
+	if c.Zoom.Region != "" {
+		if _, ok := ZoomRegions[strings.ToLower(c.Zoom.Region)]; !ok {
+			return fmt.Errorf("zoom.region must be one of: us, eu, gov")
+		}
+	}
+
+	var govHosts []bool
+	for _, endpoint := range []struct{ name, url string }{
+		{"base_url", c.Zoom.BaseURL},
+		{"oauth_url", c.Zoom.OAuthURL},
+	} {
+		if endpoint.url == "" {
+			continue
+		}
+		u, err := url.Parse(endpoint.url)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("zoom.%s must be an http or https URL", endpoint.name)
+		}
+		host := strings.ToLower(u.Hostname())
+		govHosts = append(govHosts, host == zoomGovDomain || strings.HasSuffix(host, "."+zoomGovDomain))
+	}
+	// Zoom for Government accounts only exist there, and its tokens are not accepted elsewhere
+	if len(govHosts) == 2 && govHosts[0] != govHosts[1] {
+		return fmt.Errorf("zoom.base_url and zoom.oauth_url must both be Zoom for Government (%s) endpoints or neither", zoomGovDomain)
+	}
+	if strings.EqualFold(c.Zoom.Region, "gov") && len(govHosts) > 0 && !govHosts[0] {
+		return fmt.Errorf("zoom.region gov requires %s endpoints; remove zoom.base_url and zoom.oauth_url to use the defaults", zoomGovDomain)
+	}
+	return nil
 }
 
 // Validate performs validation on the loaded configuration
@@ -181,6 +1025,26 @@ func (c *Config) Validate() error {
 	if c.Zoom.ClientSecret == "" {
 		return fmt.Errorf("zoom.client_secret is required")
 	}
+	if err := c.validateZoomEndpoints(); err != nil {
+		return err
+	}
+	switch strings.ToLower(c.Zoom.RateTier) {
+	case "", "auto", "free", "pro", "business":
+	default:
+		return fmt.Errorf("zoom.rate_tier must be one of: auto, free, pro, business")
+	}
+	if c.Zoom.PageSize < 0 || c.Zoom.PageSize > 300 {
+		return fmt.Errorf("zoom.page_size must be between 1 and 300")
+	}
+	if c.Zoom.RequestsPerSecond < 0 {
+		return fmt.Errorf("zoom.requests_per_second must be >= 0")
+	}
+	if c.Zoom.MaxConcurrentRequests < 0 {
+		return fmt.Errorf("zoom.max_concurrent_requests must be >= 0")
+	}
+	if c.Zoom.RestoreTrash && !c.Zoom.IncludeTrash {
+		return fmt.Errorf("zoom.restore_trash requires zoom.include_trash")
+	}
 
 	// Validate download configuration
 	if c.Download.RetryAttempts < 0 {
@@ -189,6 +1053,371 @@ func (c *Config) Validate() error {
 	if c.Download.TimeoutSeconds <= 0 {
 		return fmt.Errorf("download.timeout_seconds must be greater than 0")
 	}
+	if _, _, err := c.Download.DateRange(time.Now()); err != nil {
+		return err
+	}
+	validChecksumAlgorithms := map[string]bool{
+		"":       true,
+		"sha256": true,
+		"blake3": true,
+	}
+	if !validChecksumAlgorithms[strings.ToLower(c.Download.ChecksumAlgorithm)] {
+		return fmt.Errorf("download.checksum_algorithm must be one of: sha256, blake3")
+	}
+	for _, format := range c.Download.TranscriptFormats {
+		if !transcript.ValidFormat(format) {
+			return fmt.Errorf("download.transcript_formats must contain only: srt, txt")
+		}
+	}
+	if c.Download.PreviewMinutes < 0 {
+		return fmt.Errorf("download.preview_minutes must be >= 0")
+	}
+	if c.Download.DiskReserveGB < 0 {
+		return fmt.Errorf("download.disk_reserve_gb must be >= 0")
+	}
+	if c.Download.DiskWait < 0 {
+		return fmt.Errorf("download.disk_wait must be >= 0")
+	}
+	if c.Download.MaxUserGB < 0 {
+		return fmt.Errorf("download.max_user_gb must be >= 0")
+	}
+	if c.Download.ConcurrentLimit < 0 || c.Download.ConcurrentLimit > 10 {
+		return fmt.Errorf("download.concurrent_limit must be between 1 and 10")
+	}
+	if c.Download.ChunkSize != 0 && (c.Download.ChunkSize < MinDownloadChunkSize || c.Download.ChunkSize > MaxDownloadChunkSize) {
+		return fmt.Errorf("download.chunk_size must be between %d and %d bytes", MinDownloadChunkSize, MaxDownloadChunkSize)
+	}
+	if c.Download.ReadBufferSize < 0 || c.Download.ReadBufferSize > MaxBufferSize {
+		return fmt.Errorf("download.read_buffer_size must be between 0 and %d bytes", MaxBufferSize)
+	}
+
+	// Validate Box configuration
+	if c.Box.UploadConcurrency < 0 {
+		return fmt.Errorf("box.upload_concurrency must be >= 0")
+	}
+	if c.Box.UploadConcurrency > 16 {
+		return fmt.Errorf("box.upload_concurrency must be at most 16")
+	}
+	if c.Box.UploadPartSize != 0 && (c.Box.UploadPartSize < MinUploadPartSize || c.Box.UploadPartSize > MaxUploadPartSize) {
+		return fmt.Errorf("box.upload_part_size must be between %d and %d bytes", MinUploadPartSize, MaxUploadPartSize)
+	}
+	if c.Box.UploadBufferSize < 0 || c.Box.UploadBufferSize > MaxBufferSize {
+		return fmt.Errorf("box.upload_buffer_size must be between 0 and %d bytes", MaxBufferSize)
+	}
+	if c.Box.AuthMode != "" && c.Box.AuthMode != "client_credentials" && c.Box.AuthMode != "user" {
+		return fmt.Errorf("box.auth_mode must be one of: client_credentials, user")
+	}
+	if c.Box.UploadAsUser && c.Box.AuthMode == "user" {
+		return fmt.Errorf("box.upload_as_user requires box.auth_mode client_credentials")
+	}
+	if c.Box.CreateZoomFolderIfMissing && c.Box.AuthMode == "user" {
+		return fmt.Errorf("box.create_zoom_folder_if_missing requires box.auth_mode client_credentials")
+	}
+	if c.Box.DestinationFolderID != "" {
+		if strings.Trim(c.Box.DestinationFolderID, "0123456789") != "" {
+			return fmt.Errorf("box.destination_folder_id must be a numeric Box folder ID")
+		}
+		if c.Box.UploadAsUser {
+			return fmt.Errorf("box.destination_folder_id cannot be combined with box.upload_as_user")
+		}
+	}
+	switch strings.ToLower(c.Box.CreateSharedLinks) {
+	case "", "open", "company", "collaborators":
+	default:
+		return fmt.Errorf("box.create_shared_links must be one of: open, company, collaborators")
+	}
+	if template := c.Box.MetadataTemplate; template.TemplateKey != "" || len(template.Fields) > 0 {
+		if template.TemplateKey == "" || len(template.Fields) == 0 {
+			return fmt.Errorf("box.metadata_template requires template_key and at least one field")
+		}
+		if template.Scope != "" && template.Scope != "enterprise" && template.Scope != "global" {
+			return fmt.Errorf("box.metadata_template.scope must be one of: enterprise, global")
+		}
+	}
+
+	// Validate upload destinations
+	enabledDestinations := 0
+	for _, enabled := range []bool{c.Box.Enabled, c.GoogleDrive.Enabled, c.S3.Enabled, c.SFTP.Enabled, c.WebDAV.Enabled} {
+		if enabled {
+			enabledDestinations++
+		}
+	}
+	if enabledDestinations > 1 {
+		return fmt.Errorf("only one upload destination can be enabled: box, google_drive, s3, sftp or webdav")
+	}
+
+	// Validate Google Drive configuration
+	if c.GoogleDrive.Enabled {
+		if c.GoogleDrive.CredentialsFile == "" {
+			return fmt.Errorf("google_drive.credentials_file is required when Google Drive is enabled")
+		}
+	}
+
+	// Validate S3 configuration
+	if c.S3.Enabled {
+		if c.S3.Bucket == "" {
+			return fmt.Errorf("s3.bucket is required when S3 is enabled")
+		}
+		if c.S3.KeyTemplate != "" && !strings.Contains(c.S3.KeyTemplate, "{filename}") {
+			return fmt.Errorf("s3.key_template must contain {filename}")
+		}
+		switch c.S3.ServerSideEncryption {
+		case "", "AES256", "aws:kms":
+		default:
+			return fmt.Errorf("s3.server_side_encryption must be one of: AES256, aws:kms")
+		}
+		if c.S3.KMSKeyID != "" && c.S3.ServerSideEncryption != "aws:kms" {
+			return fmt.Errorf("s3.kms_key_id requires s3.server_side_encryption: aws:kms")
+		}
+	}
+
+	// Validate SFTP configuration
+	if c.SFTP.Enabled {
+		if c.SFTP.Host == "" || c.SFTP.User == "" {
+			return fmt.Errorf("sftp.host and sftp.user are required when SFTP is enabled")
+		}
+		if c.SFTP.PrivateKeyFile == "" {
+			return fmt.Errorf("sftp.private_key_file is required when SFTP is enabled")
+		}
+		if c.SFTP.Port < 0 || c.SFTP.Port > 65535 {
+			return fmt.Errorf("sftp.port must be between 1 and 65535")
+		}
+	}
+
+	// Validate WebDAV configuration
+	if c.WebDAV.Enabled {
+		if !strings.HasPrefix(c.WebDAV.URL, "http://") && !strings.HasPrefix(c.WebDAV.URL, "https://") {
+			return fmt.Errorf("webdav.url must be an http:// or https:// URL when WebDAV is enabled")
+		}
+	}
+
+	// Validate retry policies
+	retryPolicies := []struct {
+		name   string
+		policy retry.Policy
+	}{
+		{"zoom_api", c.Retry.ZoomAPI},
+		{"zoom_download", c.Retry.ZoomDownload},
+		{"box_api", c.Retry.BoxAPI},
+		{"box_upload", c.Retry.BoxUpload},
+	}
+	for _, rp := range retryPolicies {
+		if err := rp.policy.Validate(); err != nil {
+			return fmt.Errorf("retry.%s.%w", rp.name, err)
+		}
+	}
+	if err := c.Retry.BoxCircuitBreaker.Validate(); err != nil {
+		return fmt.Errorf("retry.box_circuit_breaker.%w", err)
+	}
+
+	// Validate webhook configuration
+	if c.Webhook.Path != "" && !strings.HasPrefix(c.Webhook.Path, "/") {
+		return fmt.Errorf("webhook.path must start with /")
+	}
+	if c.Webhook.QueueSize < 0 {
+		return fmt.Errorf("webhook.queue_size must be >= 0")
+	}
+
+	// Validate control API configuration
+	if c.Control.Enabled && c.Control.Token == "" {
+		return fmt.Errorf("control.token is required when control.enabled is true")
+	}
+
+	// Validate recording filters
+	if c.Filters.MinDurationMinutes < 0 {
+		return fmt.Errorf("filters.min_duration_minutes must be >= 0")
+	}
+	if c.Filters.MinFileSize < 0 {
+		return fmt.Errorf("filters.min_file_size must be >= 0")
+	}
+	if _, err := regexp.Compile(c.Filters.TopicRegex); err != nil {
+		return fmt.Errorf("filters.topic_regex is invalid: %w", err)
+	}
+	if _, err := regexp.Compile(c.Filters.ExcludeTopicRegex); err != nil {
+		return fmt.Errorf("filters.exclude_topic_regex is invalid: %w", err)
+	}
+	for _, meetingType := range c.Filters.MeetingTypes {
+		if meetingType <= 0 {
+			return fmt.Errorf("filters.meeting_types must contain positive Zoom meeting types")
+		}
+	}
+
+	// Validate upload configuration
+	validMetadataOrders := map[string]bool{
+		"":       true,
+		"after":  true,
+		"before": true,
+	}
+	if !validMetadataOrders[strings.ToLower(c.Upload.MetadataOrder)] {
+		return fmt.Errorf("upload.metadata_order must be one of: after, before")
+	}
+	validConflictPolicies := map[string]bool{
+		"":        true,
+		"version": true,
+		"replace": true,
+		"report":  true,
+	}
+	if !validConflictPolicies[strings.ToLower(c.Upload.ConflictPolicy)] {
+		return fmt.Errorf("upload.conflict_policy must be one of: version, replace, report")
+	}
+
+	// Validate tracking configuration
+	switch strings.ToLower(c.Tracking.RunSummaryMode) {
+	case "", "overwrite", "append":
+	default:
+		return fmt.Errorf("tracking.run_summary_mode must be one of: overwrite, append")
+	}
+
+	// Validate limits
+	if c.Processor.UserTimeout < 0 {
+		return fmt.Errorf("processor.user_timeout must be >= 0")
+	}
+	if c.Processor.TransientRetries < 0 {
+		return fmt.Errorf("processor.transient_retries must be >= 0")
+	}
+	if c.Processor.TransientRetryDelay < 0 {
+		return fmt.Errorf("processor.transient_retry_delay must be >= 0")
+	}
+	if c.Download.PerFileTimeout < 0 {
+		return fmt.Errorf("download.per_file_timeout must be >= 0")
+	}
+	if c.Download.SizeTolerancePercent < 0 || c.Download.SizeTolerancePercent > 100 {
+		return fmt.Errorf("download.size_tolerance_percent must be between 0 and 100")
+	}
+	if c.Limits.MaxRunDuration < 0 {
+		return fmt.Errorf("limits.max_run_duration must be >= 0")
+	}
+
+	// Validate resource monitoring
+	if c.Monitor.Interval < 0 {
+		return fmt.Errorf("monitor.interval must be >= 0")
+	}
+	if c.Monitor.MaxHeapMB < 0 || c.Monitor.MaxGoroutines < 0 || c.Monitor.MaxOpenFiles < 0 {
+		return fmt.Errorf("monitor limits must be >= 0")
+	}
+	if c.Monitor.RestartOnLimit && c.Monitor.Interval == 0 {
+		return fmt.Errorf("monitor.restart_on_limit requires monitor.interval")
+	}
+
+	// Validate timestamp display
+	if _, err := timefmt.LoadLocation(c.Time.Timezone); err != nil {
+		return fmt.Errorf("time.timezone is invalid: %w", err)
+	}
+	if _, err := timefmt.ParseLayout(c.Time.Format); err != nil {
+		return fmt.Errorf("time.format is invalid: %w", err)
+	}
+
+	// Validate filename template syntax; unknown fields are reported when the processor is built
+	if c.Filename.Template != "" {
+		if _, err := template.New("filename").Parse(c.Filename.Template); err != nil {
+			return fmt.Errorf("filename.template is invalid: %w", err)
+		}
+	}
+	if c.Directory.Layout != "" {
+		if _, err := template.New("layout").Parse(c.Directory.Layout); err != nil {
+			return fmt.Errorf("directory.layout is invalid: %w", err)
+		}
+		if !strings.Contains(c.Directory.Layout, ".User") {
+			return fmt.Errorf("directory.layout must contain {{.User}}")
+		}
+	}
+
+	// Validate output filesystem preflight checks
+	if c.Preflight.MinFreeMB < 0 || c.Preflight.MinFreeInodes < 0 || c.Preflight.MaxPathLength < 0 {
+		return fmt.Errorf("preflight limits must be >= 0")
+	}
+
+	// Validate tracing configuration
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		return fmt.Errorf("tracing.sample_ratio must be between 0 and 1")
+	}
+	if c.Tracing.Endpoint != "" {
+		if u, err := url.Parse(c.Tracing.Endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("tracing.endpoint must be an http or https URL")
+		}
+	}
+
+	// Validate network configuration
+	if c.Network.ProxyURL != "" {
+		u, err := url.Parse(c.Network.ProxyURL)
+		if err != nil || u.Host == "" {
+			return fmt.Errorf("network.proxy_url must be a URL such as http://proxy.example.com:3128")
+		}
+		switch u.Scheme {
+		case "http", "https", "socks5", "socks5h":
+		default:
+			return fmt.Errorf("network.proxy_url scheme must be http, https, socks5 or socks5h")
+		}
+	}
+	switch c.Network.MinTLSVersion {
+	case "", "1.0", "1.1", "1.2", "1.3":
+	default:
+		return fmt.Errorf("network.min_tls_version must be 1.0, 1.1, 1.2 or 1.3")
+	}
+
+	// Validate daemon schedule
+	if c.Daemon.Schedule != "" {
+		if _, err := schedule.Parse(c.Daemon.Schedule); err != nil {
+			return fmt.Errorf("daemon.schedule is invalid: %w", err)
+		}
+	}
+
+	// Validate encryption at rest
+	if c.Encryption.Enabled {
+		sources := 0
+		for _, source := range []string{c.Encryption.Key, c.Encryption.KeyFile, c.Encryption.KMSEncryptedKey} {
+			if source != "" {
+				sources++
+			}
+		}
+		if sources != 1 {
+			return fmt.Errorf("encryption.enabled requires exactly one of encryption.key, encryption.key_file or encryption.kms_encrypted_key")
+		}
+	}
+
+	// Validate notification webhooks
+	for _, webhook := range []struct{ name, url string }{
+		{"slack", c.Notifications.Slack.WebhookURL},
+		{"teams", c.Notifications.Teams.WebhookURL},
+	} {
+		if webhook.url == "" {
+			continue
+		}
+		if u, err := url.Parse(webhook.url); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("notifications.%s.webhook_url must be an http or https URL", webhook.name)
+		}
+	}
+	if email := c.Notifications.Email; email.SMTPHost != "" {
+		if email.From == "" || len(email.To) == 0 {
+			return fmt.Errorf("notifications.email requires from and at least one to address")
+		}
+		if email.SMTPPort < 0 || email.SMTPPort > 65535 {
+			return fmt.Errorf("notifications.email.smtp_port must be between 1 and 65535")
+		}
+		if _, err := template.New("subject").Parse(email.Subject); err != nil {
+			return fmt.Errorf("notifications.email.subject is invalid: %w", err)
+		}
+		for _, format := range email.Attach {
+			if format := strings.ToLower(format); format != "json" && format != "csv" {
+				return fmt.Errorf("notifications.email.attach must contain only json or csv")
+			}
+		}
+	}
+
+	// Validate hooks
+	if c.Hooks.PostUploadURL != "" {
+		if u, err := url.Parse(c.Hooks.PostUploadURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("hooks.post_upload_url must be an http or https URL")
+		}
+	}
+	for _, command := range c.Hooks.PostUpload {
+		if strings.TrimSpace(command) == "" {
+			return fmt.Errorf("hooks.post_upload must not contain empty commands")
+		}
+	}
+	if c.Hooks.Timeout < 0 {
+		return fmt.Errorf("hooks.timeout must be >= 0")
+	}
 
 	// Validate logging configuration
 	validLogLevels := map[string]bool{
@@ -200,6 +1429,17 @@ func (c *Config) Validate() error {
 	if !validLogLevels[strings.ToLower(c.Logging.Level)] {
 		return fmt.Errorf("logging.level must be one of: debug, info, warn, error")
 	}
+	if rotation := c.Logging.Rotation; rotation.MaxSizeMB < 0 || rotation.Interval < 0 || rotation.MaxBackups < 0 || rotation.MaxAge < 0 {
+		return fmt.Errorf("logging.rotation values must not be negative")
+	}
+	timestampKey, levelKey, messageKey, requestIDKey := c.Logging.JSONSchema.Keys()
+	keys := map[string]bool{}
+	for _, key := range []string{timestampKey, levelKey, messageKey, requestIDKey} {
+		if keys[key] {
+			return fmt.Errorf("logging.json_schema key %q is used twice", key)
+		}
+		keys[key] = true
+	}
 
 	return nil
 }
@@ -207,4 +1447,13 @@ func (c *Config) Validate() error {
 // GetBoxConfig returns the Box configuration
 func (c *Config) GetBoxConfig() BoxConfig {
 	return c.Box
+}
+
+// DisableUploads turns off every upload destination, for runs that only download
+func (c *Config) DisableUploads() {
+	c.Box.Enabled = false
+	c.GoogleDrive.Enabled = false
+	c.S3.Enabled = false
+	c.SFTP.Enabled = false
+	c.WebDAV.Enabled = false
 }
\ No newline at end of file
@@ -3,8 +3,11 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/curtbushko/zoom-to-box/internal/retry"
 )
 
 func TestLoadConfig(t *testing.T) {
@@ -223,190 +226,1459 @@ func TestConfigValidation(t *testing.T) {
 			shouldError: true,
 			errorMsg:    "download.retry_attempts must be >= 0",
 		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			err := tt.config.Validate()
-
-			if tt.shouldError {
-				if err == nil {
-					t.Errorf("Expected error, but got none")
-					return
-				}
-				if tt.errorMsg != "" && err.Error() != tt.errorMsg {
-					t.Errorf("Expected error message %q, got %q", tt.errorMsg, err.Error())
-				}
-			} else {
-				if err != nil {
-					t.Errorf("Unexpected error: %v", err)
-				}
-			}
-		})
-	}
-}
-
-func TestConfigDefaults(t *testing.T) {
-	tests := []struct {
-		name           string
-		configYAML     string
-		expectedConfig Config
-	}{
 		{
-			name: "apply defaults for missing sections",
-			configYAML: `
-zoom:
-  account_id: "test_account"
-  client_id: "test_client"
-  client_secret: "test_secret"
-`,
-			expectedConfig: Config{
+			name: "box upload concurrency too high",
+			config: &Config{
 				Zoom: ZoomConfig{
 					AccountID:    "test_account",
 					ClientID:     "test_client",
 					ClientSecret: "test_secret",
-					BaseURL:      "https://api.zoom.us/v2",
 				},
 				Box: BoxConfig{
-					Enabled: false,
+					UploadConcurrency: 64,
 				},
 				Download: DownloadConfig{
-					OutputDir:       "./downloads",
-					RetryAttempts:   3,
-					TimeoutSeconds:  300,
+					RetryAttempts:  3,
+					TimeoutSeconds: 300,
 				},
 				Logging: LoggingConfig{
-					Level:      "info",
-					File:       "./zoom-downloader.log",
-					Console:    true,
-					JSONFormat: false,
+					Level: "info",
 				},
-				ActiveUsers: ActiveUsersConfig{
-					File:         "./active_users.txt",
-					CheckEnabled: true,
+			},
+			shouldError: true,
+			errorMsg:    "box.upload_concurrency must be at most 16",
+		},
+		{
+			name: "unsupported box auth mode",
+			config: &Config{
+				Zoom: ZoomConfig{
+					AccountID:    "test_account",
+					ClientID:     "test_client",
+					ClientSecret: "test_secret",
+				},
+				Box: BoxConfig{
+					AuthMode: "jwt",
+				},
+				Download: DownloadConfig{
+					RetryAttempts:  3,
+					TimeoutSeconds: 300,
+				},
+				Logging: LoggingConfig{
+					Level: "info",
 				},
 			},
+			shouldError: true,
+			errorMsg:    "box.auth_mode must be one of: client_credentials, user",
 		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			// Create temporary config file
-			tmpDir := t.TempDir()
-			configPath := filepath.Join(tmpDir, "config.yaml")
-			err := os.WriteFile(configPath, []byte(tt.configYAML), 0644)
-			if err != nil {
-				t.Fatalf("Failed to create temp config file: %v", err)
-			}
-
-			// Load configuration
-			config, err := LoadConfig(configPath)
-			if err != nil {
-				t.Fatalf("Unexpected error: %v", err)
-			}
-
-			// Check defaults were applied
-			if config.Download.OutputDir != tt.expectedConfig.Download.OutputDir {
-				t.Errorf("Expected default OutputDir %s, got %s", tt.expectedConfig.Download.OutputDir, config.Download.OutputDir)
-			}
-			if config.Logging.Level != tt.expectedConfig.Logging.Level {
-				t.Errorf("Expected default Logging Level %s, got %s", tt.expectedConfig.Logging.Level, config.Logging.Level)
-			}
-		})
-	}
-}
-
-func TestLoadConfigFileNotFound(t *testing.T) {
-	_, err := LoadConfig("nonexistent_config.yaml")
-	if err == nil {
-		t.Error("Expected error for nonexistent config file, but got none")
-	}
-}
-
-func TestLoadConfigFromEnvironment(t *testing.T) {
-	// Set environment variables
-	os.Setenv("ZOOM_ACCOUNT_ID", "env_account")
-	os.Setenv("ZOOM_CLIENT_ID", "env_client")
-	os.Setenv("ZOOM_CLIENT_SECRET", "env_secret")
-	defer func() {
-		os.Unsetenv("ZOOM_ACCOUNT_ID")
-		os.Unsetenv("ZOOM_CLIENT_ID")
-		os.Unsetenv("ZOOM_CLIENT_SECRET")
-	}()
-
-	config := &Config{}
-	config.loadFromEnvironment()
-
-	if config.Zoom.AccountID != "env_account" {
-		t.Errorf("Expected AccountID from env %s, got %s", "env_account", config.Zoom.AccountID)
-	}
-	if config.Zoom.ClientID != "env_client" {
-		t.Errorf("Expected ClientID from env %s, got %s", "env_client", config.Zoom.ClientID)
-	}
-	if config.Zoom.ClientSecret != "env_secret" {
-		t.Errorf("Expected ClientSecret from env %s, got %s", "env_secret", config.Zoom.ClientSecret)
-	}
-}
-
-func TestTimeoutDuration(t *testing.T) {
-	config := &Config{
-		Download: DownloadConfig{
-			TimeoutSeconds: 300,
+		{
+			name: "invalid box shared link access",
+			config: &Config{
+				Zoom: ZoomConfig{
+					AccountID:    "test_account",
+					ClientID:     "test_client",
+					ClientSecret: "test_secret",
+				},
+				Box: BoxConfig{
+					CreateSharedLinks: "everyone",
+				},
+				Download: DownloadConfig{
+					RetryAttempts:  3,
+					TimeoutSeconds: 300,
+				},
+				Logging: LoggingConfig{
+					Level: "info",
+				},
+			},
+			shouldError: true,
+			errorMsg:    "box.create_shared_links must be one of: open, company, collaborators",
 		},
-	}
-
-	expectedDuration := 300 * time.Second
-	if config.Download.TimeoutDuration() != expectedDuration {
-		t.Errorf("Expected timeout duration %v, got %v", expectedDuration, config.Download.TimeoutDuration())
-	}
-}
-
-func TestLogLevelValidation(t *testing.T) {
-	validLevels := []string{"debug", "info", "warn", "error"}
-	
-	for _, level := range validLevels {
-		t.Run("valid_level_"+level, func(t *testing.T) {
-			config := &Config{
+		{
+			name: "box metadata template without fields",
+			config: &Config{
 				Zoom: ZoomConfig{
 					AccountID:    "test_account",
 					ClientID:     "test_client",
 					ClientSecret: "test_secret",
 				},
+				Box: BoxConfig{
+					MetadataTemplate: BoxMetadataTemplateConfig{TemplateKey: "zoomRecording"},
+				},
 				Download: DownloadConfig{
-					RetryAttempts:   3,
-					TimeoutSeconds:  300,
+					RetryAttempts:  3,
+					TimeoutSeconds: 300,
 				},
 				Logging: LoggingConfig{
-					Level: level,
+					Level: "info",
 				},
-			}
-			
-			err := config.Validate()
-			if err != nil {
-				t.Errorf("Valid log level %s should not cause error: %v", level, err)
-			}
-		})
-	}
-	
-	t.Run("invalid_log_level", func(t *testing.T) {
-		config := &Config{
-			Zoom: ZoomConfig{
-				AccountID:    "test_account",
-				ClientID:     "test_client",
-				ClientSecret: "test_secret",
 			},
-			Download: DownloadConfig{
-				RetryAttempts:  3,
-				TimeoutSeconds: 300,
+			shouldError: true,
+			errorMsg:    "box.metadata_template requires template_key and at least one field",
+		},
+		{
+			name: "unsupported zoom rate tier",
+			config: &Config{
+				Zoom: ZoomConfig{
+					AccountID:    "test_account",
+					ClientID:     "test_client",
+					ClientSecret: "test_secret",
+					RateTier:     "platinum",
+				},
+				Download: DownloadConfig{
+					RetryAttempts:  3,
+					TimeoutSeconds: 300,
+				},
+				Logging: LoggingConfig{
+					Level: "info",
+				},
 			},
-			Logging: LoggingConfig{
-				Level: "invalid",
+			shouldError: true,
+			errorMsg:    "zoom.rate_tier must be one of: auto, free, pro, business",
+		},
+		{
+			name: "zoom page size above the API maximum",
+			config: &Config{
+				Zoom: ZoomConfig{
+					AccountID:    "test_account",
+					ClientID:     "test_client",
+					ClientSecret: "test_secret",
+					PageSize:     500,
+				},
+				Download: DownloadConfig{
+					RetryAttempts:  3,
+					TimeoutSeconds: 300,
+				},
+				Logging: LoggingConfig{
+					Level: "info",
+				},
 			},
-		}
-		
-		err := config.Validate()
-		if err == nil {
-			t.Error("Invalid log level should cause error")
-		}
-	})
-}
\ No newline at end of file
+			shouldError: true,
+			errorMsg:    "zoom.page_size must be between 1 and 300",
+		},
+		{
+			name: "unsupported checksum algorithm",
+			config: &Config{
+				Zoom: ZoomConfig{
+					AccountID:    "test_account",
+					ClientID:     "test_client",
+					ClientSecret: "test_secret",
+				},
+				Download: DownloadConfig{
+					RetryAttempts:     3,
+					TimeoutSeconds:    300,
+					ChecksumAlgorithm: "md5",
+				},
+				Logging: LoggingConfig{
+					Level: "info",
+				},
+			},
+			shouldError: true,
+			errorMsg:    "download.checksum_algorithm must be one of: sha256, blake3",
+		},
+		{
+			name: "negative preview minutes",
+			config: &Config{
+				Zoom: ZoomConfig{
+					AccountID:    "test_account",
+					ClientID:     "test_client",
+					ClientSecret: "test_secret",
+				},
+				Download: DownloadConfig{
+					RetryAttempts:  3,
+					TimeoutSeconds: 300,
+					PreviewMinutes: -1,
+				},
+				Logging: LoggingConfig{
+					Level: "info",
+				},
+			},
+			shouldError: true,
+			errorMsg:    "download.preview_minutes must be >= 0",
+		},
+		{
+			name: "invalid slack webhook url",
+			config: &Config{
+				Zoom: ZoomConfig{
+					AccountID:    "test_account",
+					ClientID:     "test_client",
+					ClientSecret: "test_secret",
+				},
+				Download: DownloadConfig{
+					RetryAttempts:  3,
+					TimeoutSeconds: 300,
+				},
+				Logging: LoggingConfig{
+					Level: "info",
+				},
+				Notifications: NotificationsConfig{
+					Slack: NotificationWebhookConfig{WebhookURL: "hooks.slack.com/services/T000"},
+				},
+			},
+			shouldError: true,
+			errorMsg:    "notifications.slack.webhook_url must be an http or https URL",
+		},
+		{
+			name: "invalid hook url",
+			config: &Config{
+				Zoom: ZoomConfig{
+					AccountID:    "test_account",
+					ClientID:     "test_client",
+					ClientSecret: "test_secret",
+				},
+				Download: DownloadConfig{
+					RetryAttempts:  3,
+					TimeoutSeconds: 300,
+				},
+				Logging: LoggingConfig{
+					Level: "info",
+				},
+				Hooks: HooksConfig{PostUploadURL: "ftp://example.com/hook"},
+			},
+			shouldError: true,
+			errorMsg:    "hooks.post_upload_url must be an http or https URL",
+		},
+		{
+			name: "duplicate json schema key",
+			config: &Config{
+				Zoom: ZoomConfig{
+					AccountID:    "test_account",
+					ClientID:     "test_client",
+					ClientSecret: "test_secret",
+				},
+				Download: DownloadConfig{
+					RetryAttempts:  3,
+					TimeoutSeconds: 300,
+				},
+				Logging: LoggingConfig{
+					Level:      "info",
+					JSONSchema: LogJSONSchemaConfig{LevelKey: "message"},
+				},
+			},
+			shouldError: true,
+			errorMsg:    `logging.json_schema key "message" is used twice`,
+		},
+		{
+			name: "destination folder with upload as user",
+			config: &Config{
+				Zoom: ZoomConfig{
+					AccountID:    "test_account",
+					ClientID:     "test_client",
+					ClientSecret: "test_secret",
+				},
+				Download: DownloadConfig{
+					RetryAttempts:  3,
+					TimeoutSeconds: 300,
+				},
+				Logging: LoggingConfig{
+					Level: "info",
+				},
+				Box: BoxConfig{DestinationFolderID: "123456789", UploadAsUser: true},
+			},
+			shouldError: true,
+			errorMsg:    "box.destination_folder_id cannot be combined with box.upload_as_user",
+		},
+		{
+			name: "email notifications without recipients",
+			config: &Config{
+				Zoom: ZoomConfig{
+					AccountID:    "test_account",
+					ClientID:     "test_client",
+					ClientSecret: "test_secret",
+				},
+				Download: DownloadConfig{
+					RetryAttempts:  3,
+					TimeoutSeconds: 300,
+				},
+				Logging: LoggingConfig{
+					Level: "info",
+				},
+				Notifications: NotificationsConfig{
+					Email: EmailNotificationConfig{SMTPHost: "smtp.example.com", From: "zoom-to-box@example.com"},
+				},
+			},
+			shouldError: true,
+			errorMsg:    "notifications.email requires from and at least one to address",
+		},
+		{
+			name: "negative disk reserve",
+			config: &Config{
+				Zoom: ZoomConfig{
+					AccountID:    "test_account",
+					ClientID:     "test_client",
+					ClientSecret: "test_secret",
+				},
+				Download: DownloadConfig{
+					RetryAttempts:  3,
+					TimeoutSeconds: 300,
+					DiskReserveGB:  -1,
+				},
+				Logging: LoggingConfig{
+					Level: "info",
+				},
+			},
+			shouldError: true,
+			errorMsg:    "download.disk_reserve_gb must be >= 0",
+		},
+		{
+			name: "concurrent limit out of range",
+			config: &Config{
+				Zoom: ZoomConfig{
+					AccountID:    "test_account",
+					ClientID:     "test_client",
+					ClientSecret: "test_secret",
+				},
+				Download: DownloadConfig{
+					RetryAttempts:   3,
+					TimeoutSeconds:  300,
+					ConcurrentLimit: 11,
+				},
+				Logging: LoggingConfig{
+					Level: "info",
+				},
+			},
+			shouldError: true,
+			errorMsg:    "download.concurrent_limit must be between 1 and 10",
+		},
+		{
+			name: "download chunk size too small",
+			config: &Config{
+				Zoom: ZoomConfig{
+					AccountID:    "test_account",
+					ClientID:     "test_client",
+					ClientSecret: "test_secret",
+				},
+				Download: DownloadConfig{
+					RetryAttempts:  3,
+					TimeoutSeconds: 300,
+					ChunkSize:      512,
+				},
+				Logging: LoggingConfig{
+					Level: "info",
+				},
+			},
+			shouldError: true,
+			errorMsg:    "download.chunk_size must be between 4096 and 16777216 bytes",
+		},
+		{
+			name: "box upload part size below Box minimum",
+			config: &Config{
+				Zoom: ZoomConfig{
+					AccountID:    "test_account",
+					ClientID:     "test_client",
+					ClientSecret: "test_secret",
+				},
+				Box: BoxConfig{
+					UploadPartSize: 1024 * 1024,
+				},
+				Download: DownloadConfig{
+					RetryAttempts:  3,
+					TimeoutSeconds: 300,
+				},
+				Logging: LoggingConfig{
+					Level: "info",
+				},
+			},
+			shouldError: true,
+			errorMsg:    "box.upload_part_size must be between 8388608 and 134217728 bytes",
+		},
+		{
+			name: "unsupported proxy scheme",
+			config: &Config{
+				Zoom: ZoomConfig{
+					AccountID:    "test_account",
+					ClientID:     "test_client",
+					ClientSecret: "test_secret",
+				},
+				Download: DownloadConfig{
+					RetryAttempts:  3,
+					TimeoutSeconds: 300,
+				},
+				Logging: LoggingConfig{
+					Level: "info",
+				},
+				Network: NetworkConfig{
+					ProxyURL: "ftp://proxy.example.com:21",
+				},
+			},
+			shouldError: true,
+			errorMsg:    "network.proxy_url scheme must be http, https, socks5 or socks5h",
+		},
+		{
+			name: "unknown minimum TLS version",
+			config: &Config{
+				Zoom: ZoomConfig{
+					AccountID:    "test_account",
+					ClientID:     "test_client",
+					ClientSecret: "test_secret",
+				},
+				Download: DownloadConfig{
+					RetryAttempts:  3,
+					TimeoutSeconds: 300,
+				},
+				Logging: LoggingConfig{
+					Level: "info",
+				},
+				Network: NetworkConfig{
+					MinTLSVersion: "1.4",
+				},
+			},
+			shouldError: true,
+			errorMsg:    "network.min_tls_version must be 1.0, 1.1, 1.2 or 1.3",
+		},
+		{
+			name: "box and google drive both enabled",
+			config: &Config{
+				Zoom: ZoomConfig{
+					AccountID:    "test_account",
+					ClientID:     "test_client",
+					ClientSecret: "test_secret",
+				},
+				Box: BoxConfig{
+					Enabled: true,
+				},
+				GoogleDrive: GoogleDriveConfig{
+					Enabled:         true,
+					CredentialsFile: "service-account.json",
+				},
+				Download: DownloadConfig{
+					RetryAttempts:  3,
+					TimeoutSeconds: 300,
+				},
+				Logging: LoggingConfig{
+					Level: "info",
+				},
+			},
+			shouldError: true,
+			errorMsg:    "only one upload destination can be enabled: box, google_drive, s3, sftp or webdav",
+		},
+		{
+			name: "google drive without credentials",
+			config: &Config{
+				Zoom: ZoomConfig{
+					AccountID:    "test_account",
+					ClientID:     "test_client",
+					ClientSecret: "test_secret",
+				},
+				GoogleDrive: GoogleDriveConfig{
+					Enabled: true,
+				},
+				Download: DownloadConfig{
+					RetryAttempts:  3,
+					TimeoutSeconds: 300,
+				},
+				Logging: LoggingConfig{
+					Level: "info",
+				},
+			},
+			shouldError: true,
+			errorMsg:    "google_drive.credentials_file is required when Google Drive is enabled",
+		},
+		{
+			name: "s3 without bucket",
+			config: &Config{
+				Zoom: ZoomConfig{
+					AccountID:    "test_account",
+					ClientID:     "test_client",
+					ClientSecret: "test_secret",
+				},
+				S3: S3Config{
+					Enabled: true,
+				},
+				Download: DownloadConfig{
+					RetryAttempts:  3,
+					TimeoutSeconds: 300,
+				},
+				Logging: LoggingConfig{
+					Level: "info",
+				},
+			},
+			shouldError: true,
+			errorMsg:    "s3.bucket is required when S3 is enabled",
+		},
+		{
+			name: "s3 kms key without kms encryption",
+			config: &Config{
+				Zoom: ZoomConfig{
+					AccountID:    "test_account",
+					ClientID:     "test_client",
+					ClientSecret: "test_secret",
+				},
+				S3: S3Config{
+					Enabled:              true,
+					Bucket:               "recordings",
+					ServerSideEncryption: "AES256",
+					KMSKeyID:             "alias/recordings",
+				},
+				Download: DownloadConfig{
+					RetryAttempts:  3,
+					TimeoutSeconds: 300,
+				},
+				Logging: LoggingConfig{
+					Level: "info",
+				},
+			},
+			shouldError: true,
+			errorMsg:    "s3.kms_key_id requires s3.server_side_encryption: aws:kms",
+		},
+		{
+			name: "sftp without private key",
+			config: &Config{
+				Zoom: ZoomConfig{
+					AccountID:    "test_account",
+					ClientID:     "test_client",
+					ClientSecret: "test_secret",
+				},
+				SFTP: SFTPConfig{
+					Enabled: true,
+					Host:    "nas.example.com",
+					User:    "archiver",
+				},
+				Download: DownloadConfig{
+					RetryAttempts:  3,
+					TimeoutSeconds: 300,
+				},
+				Logging: LoggingConfig{
+					Level: "info",
+				},
+			},
+			shouldError: true,
+			errorMsg:    "sftp.private_key_file is required when SFTP is enabled",
+		},
+		{
+			name: "webdav without url",
+			config: &Config{
+				Zoom: ZoomConfig{
+					AccountID:    "test_account",
+					ClientID:     "test_client",
+					ClientSecret: "test_secret",
+				},
+				WebDAV: WebDAVConfig{
+					Enabled: true,
+				},
+				Download: DownloadConfig{
+					RetryAttempts:  3,
+					TimeoutSeconds: 300,
+				},
+				Logging: LoggingConfig{
+					Level: "info",
+				},
+			},
+			shouldError: true,
+			errorMsg:    "webdav.url must be an http:// or https:// URL when WebDAV is enabled",
+		},
+		{
+			name: "relative webhook path",
+			config: &Config{
+				Zoom: ZoomConfig{
+					AccountID:    "test_account",
+					ClientID:     "test_client",
+					ClientSecret: "test_secret",
+				},
+				Download: DownloadConfig{
+					RetryAttempts:  3,
+					TimeoutSeconds: 300,
+				},
+				Logging: LoggingConfig{
+					Level: "info",
+				},
+				Webhook: WebhookConfig{
+					Path: "zoom/webhook",
+				},
+			},
+			shouldError: true,
+			errorMsg:    "webhook.path must start with /",
+		},
+		{
+			name: "retry multiplier below one",
+			config: &Config{
+				Zoom: ZoomConfig{
+					AccountID:    "test_account",
+					ClientID:     "test_client",
+					ClientSecret: "test_secret",
+				},
+				Download: DownloadConfig{
+					RetryAttempts:  3,
+					TimeoutSeconds: 300,
+				},
+				Logging: LoggingConfig{
+					Level: "info",
+				},
+				Retry: RetryConfig{
+					BoxAPI: retry.Policy{Multiplier: 0.5},
+				},
+			},
+			shouldError: true,
+			errorMsg:    "retry.box_api.multiplier must be >= 1",
+		},
+		{
+			name: "negative max run duration",
+			config: &Config{
+				Zoom: ZoomConfig{
+					AccountID:    "test_account",
+					ClientID:     "test_client",
+					ClientSecret: "test_secret",
+				},
+				Download: DownloadConfig{
+					RetryAttempts:  3,
+					TimeoutSeconds: 300,
+				},
+				Logging: LoggingConfig{
+					Level: "info",
+				},
+				Limits: LimitsConfig{
+					MaxRunDuration: -time.Hour,
+				},
+			},
+			shouldError: true,
+			errorMsg:    "limits.max_run_duration must be >= 0",
+		},
+		{
+			name: "monitor restart without interval",
+			config: &Config{
+				Zoom: ZoomConfig{
+					AccountID:    "test_account",
+					ClientID:     "test_client",
+					ClientSecret: "test_secret",
+				},
+				Download: DownloadConfig{
+					RetryAttempts:  3,
+					TimeoutSeconds: 300,
+				},
+				Logging: LoggingConfig{
+					Level: "info",
+				},
+				Monitor: MonitorConfig{
+					MaxHeapMB:      512,
+					RestartOnLimit: true,
+				},
+			},
+			shouldError: true,
+			errorMsg:    "monitor.restart_on_limit requires monitor.interval",
+		},
+		{
+			name: "time format without a year",
+			config: &Config{
+				Zoom: ZoomConfig{
+					AccountID:    "test_account",
+					ClientID:     "test_client",
+					ClientSecret: "test_secret",
+				},
+				Download: DownloadConfig{
+					RetryAttempts:  3,
+					TimeoutSeconds: 300,
+				},
+				Logging: LoggingConfig{
+					Level: "info",
+				},
+				Time: TimeConfig{
+					Format: "15:04",
+				},
+			},
+			shouldError: true,
+			errorMsg:    `time.format is invalid: timestamp format "15:04" is neither a known name (rfc3339, rfc3339nano, datetime, datetime-tz) nor a Go layout with a 2006 year`,
+		},
+		{
+			name: "filename template with bad syntax",
+			config: &Config{
+				Zoom: ZoomConfig{
+					AccountID:    "test_account",
+					ClientID:     "test_client",
+					ClientSecret: "test_secret",
+				},
+				Download: DownloadConfig{
+					RetryAttempts:  3,
+					TimeoutSeconds: 300,
+				},
+				Logging: LoggingConfig{
+					Level: "info",
+				},
+				Filename: FilenameConfig{
+					Template: "{{.Topic",
+				},
+			},
+			shouldError: true,
+			errorMsg:    "filename.template is invalid: template: filename:1: unclosed action",
+		},
+		{
+			name: "directory layout without user",
+			config: &Config{
+				Zoom: ZoomConfig{
+					AccountID:    "test_account",
+					ClientID:     "test_client",
+					ClientSecret: "test_secret",
+				},
+				Download: DownloadConfig{
+					RetryAttempts:  3,
+					TimeoutSeconds: 300,
+				},
+				Logging: LoggingConfig{
+					Level: "info",
+				},
+				Directory: DirectoryConfig{
+					Layout: "{{.Year}}/{{.Month}}",
+				},
+			},
+			shouldError: true,
+			errorMsg:    "directory.layout must contain {{.User}}",
+		},
+		{
+			name: "negative preflight limit",
+			config: &Config{
+				Zoom: ZoomConfig{
+					AccountID:    "test_account",
+					ClientID:     "test_client",
+					ClientSecret: "test_secret",
+				},
+				Download: DownloadConfig{
+					RetryAttempts:  3,
+					TimeoutSeconds: 300,
+				},
+				Logging: LoggingConfig{
+					Level: "info",
+				},
+				Preflight: PreflightConfig{
+					MinFreeInodes: -1,
+				},
+			},
+			shouldError: true,
+			errorMsg:    "preflight limits must be >= 0",
+		},
+		{
+			name: "control API without token",
+			config: &Config{
+				Zoom: ZoomConfig{
+					AccountID:    "test_account",
+					ClientID:     "test_client",
+					ClientSecret: "test_secret",
+				},
+				Download: DownloadConfig{
+					RetryAttempts:  3,
+					TimeoutSeconds: 300,
+				},
+				Logging: LoggingConfig{
+					Level: "info",
+				},
+				Control: ControlConfig{
+					Enabled: true,
+				},
+			},
+			shouldError: true,
+			errorMsg:    "control.token is required when control.enabled is true",
+		},
+		{
+			name: "tracing sample ratio out of range",
+			config: &Config{
+				Zoom: ZoomConfig{
+					AccountID:    "test_account",
+					ClientID:     "test_client",
+					ClientSecret: "test_secret",
+				},
+				Download: DownloadConfig{
+					RetryAttempts:  3,
+					TimeoutSeconds: 300,
+				},
+				Logging: LoggingConfig{
+					Level: "info",
+				},
+				Tracing: TracingConfig{
+					Enabled:     true,
+					SampleRatio: 1.5,
+				},
+			},
+			shouldError: true,
+			errorMsg:    "tracing.sample_ratio must be between 0 and 1",
+		},
+		{
+			name: "invalid daemon schedule",
+			config: &Config{
+				Zoom: ZoomConfig{
+					AccountID:    "test_account",
+					ClientID:     "test_client",
+					ClientSecret: "test_secret",
+				},
+				Download: DownloadConfig{
+					RetryAttempts:  3,
+					TimeoutSeconds: 300,
+				},
+				Logging: LoggingConfig{
+					Level: "info",
+				},
+				Daemon: DaemonConfig{
+					Schedule: "0 25 * * *",
+				},
+			},
+			shouldError: true,
+			errorMsg:    "daemon.schedule is invalid: hour 25 out of range 0-23",
+		},
+		{
+			name: "encryption without a key",
+			config: &Config{
+				Zoom: ZoomConfig{
+					AccountID:    "test_account",
+					ClientID:     "test_client",
+					ClientSecret: "test_secret",
+				},
+				Download: DownloadConfig{
+					RetryAttempts:  3,
+					TimeoutSeconds: 300,
+				},
+				Logging: LoggingConfig{
+					Level: "info",
+				},
+				Encryption: EncryptionConfig{
+					Enabled: true,
+				},
+			},
+			shouldError: true,
+			errorMsg:    "encryption.enabled requires exactly one of encryption.key, encryption.key_file or encryption.kms_encrypted_key",
+		},
+		{
+			name: "invalid upload metadata order",
+			config: &Config{
+				Zoom: ZoomConfig{
+					AccountID:    "test_account",
+					ClientID:     "test_client",
+					ClientSecret: "test_secret",
+				},
+				Download: DownloadConfig{
+					RetryAttempts:  3,
+					TimeoutSeconds: 300,
+				},
+				Logging: LoggingConfig{
+					Level: "info",
+				},
+				Upload: UploadConfig{
+					MetadataOrder: "first",
+				},
+			},
+			shouldError: true,
+			errorMsg:    "upload.metadata_order must be one of: after, before",
+		},
+		{
+			name: "invalid upload conflict policy",
+			config: &Config{
+				Zoom: ZoomConfig{
+					AccountID:    "test_account",
+					ClientID:     "test_client",
+					ClientSecret: "test_secret",
+				},
+				Download: DownloadConfig{
+					RetryAttempts:  3,
+					TimeoutSeconds: 300,
+				},
+				Logging: LoggingConfig{
+					Level: "info",
+				},
+				Upload: UploadConfig{
+					ConflictPolicy: "overwrite",
+				},
+			},
+			shouldError: true,
+			errorMsg:    "upload.conflict_policy must be one of: version, replace, report",
+		},
+		{
+			name: "invalid run summary mode",
+			config: &Config{
+				Zoom: ZoomConfig{
+					AccountID:    "test_account",
+					ClientID:     "test_client",
+					ClientSecret: "test_secret",
+				},
+				Download: DownloadConfig{
+					RetryAttempts:  3,
+					TimeoutSeconds: 300,
+				},
+				Logging: LoggingConfig{
+					Level: "info",
+				},
+				Tracking: TrackingConfig{
+					RunSummaryMode: "truncate",
+				},
+			},
+			shouldError: true,
+			errorMsg:    "tracking.run_summary_mode must be one of: overwrite, append",
+		},
+		{
+			name: "invalid topic filter regex",
+			config: &Config{
+				Zoom: ZoomConfig{
+					AccountID:    "test_account",
+					ClientID:     "test_client",
+					ClientSecret: "test_secret",
+				},
+				Download: DownloadConfig{
+					RetryAttempts:  3,
+					TimeoutSeconds: 300,
+				},
+				Logging: LoggingConfig{
+					Level: "info",
+				},
+				Filters: FiltersConfig{
+					TopicRegex: "(unclosed",
+				},
+			},
+			shouldError: true,
+			errorMsg:    "filters.topic_regex is invalid: error parsing regexp: missing closing ): `(unclosed`",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+
+			if tt.shouldError {
+				if err == nil {
+					t.Errorf("Expected error, but got none")
+					return
+				}
+				if tt.errorMsg != "" && err.Error() != tt.errorMsg {
+					t.Errorf("Expected error message %q, got %q", tt.errorMsg, err.Error())
+				}
+			} else {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestConfigDefaults(t *testing.T) {
+	tests := []struct {
+		name           string
+		configYAML     string
+		expectedConfig Config
+	}{
+		{
+			name: "apply defaults for missing sections",
+			configYAML: `
+zoom:
+  account_id: "test_account"
+  client_id: "test_client"
+  client_secret: "test_secret"
+`,
+			expectedConfig: Config{
+				Zoom: ZoomConfig{
+					AccountID:    "test_account",
+					ClientID:     "test_client",
+					ClientSecret: "test_secret",
+					BaseURL:      "https://api.zoom.us/v2",
+				},
+				Box: BoxConfig{
+					Enabled:           false,
+					UploadConcurrency: 4,
+				},
+				Download: DownloadConfig{
+					OutputDir:       "./downloads",
+					RetryAttempts:   3,
+					TimeoutSeconds:  300,
+				},
+				Logging: LoggingConfig{
+					Level:      "info",
+					File:       "./zoom-downloader.log",
+					Console:    true,
+					JSONFormat: false,
+				},
+				ActiveUsers: ActiveUsersConfig{
+					File:         "./active_users.txt",
+					CheckEnabled: true,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Create temporary config file
+			tmpDir := t.TempDir()
+			configPath := filepath.Join(tmpDir, "config.yaml")
+			err := os.WriteFile(configPath, []byte(tt.configYAML), 0644)
+			if err != nil {
+				t.Fatalf("Failed to create temp config file: %v", err)
+			}
+
+			// Load configuration
+			config, err := LoadConfig(configPath)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			// Check defaults were applied
+			if config.Download.OutputDir != tt.expectedConfig.Download.OutputDir {
+				t.Errorf("Expected default OutputDir %s, got %s", tt.expectedConfig.Download.OutputDir, config.Download.OutputDir)
+			}
+			if config.Logging.Level != tt.expectedConfig.Logging.Level {
+				t.Errorf("Expected default Logging Level %s, got %s", tt.expectedConfig.Logging.Level, config.Logging.Level)
+			}
+			if config.Box.UploadConcurrency != tt.expectedConfig.Box.UploadConcurrency {
+				t.Errorf("Expected default Box UploadConcurrency %d, got %d", tt.expectedConfig.Box.UploadConcurrency, config.Box.UploadConcurrency)
+			}
+		})
+	}
+}
+
+func TestLoadConfigRetryPolicies(t *testing.T) {
+	configYAML := `
+zoom:
+  account_id: "test_account"
+  client_id: "test_client"
+  client_secret: "test_secret"
+
+download:
+  retry_attempts: 5
+
+retry:
+  zoom_download:
+    base_delay: "2s"
+    multiplier: 2
+    max_delay: "1m"
+  box_upload:
+    max_attempts: 6
+    base_delay: "250ms"
+
+limits:
+  max_run_duration: "6h"
+`
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configPath, []byte(configYAML), 0644); err != nil {
+		t.Fatalf("Failed to create temp config file: %v", err)
+	}
+
+	config, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// download.retry_attempts still drives the Zoom attempt counts
+	if config.Retry.ZoomAPI.MaxAttempts != 6 || config.Retry.ZoomDownload.MaxAttempts != 6 {
+		t.Errorf("Expected Zoom policies to allow 6 attempts, got %d and %d", config.Retry.ZoomAPI.MaxAttempts, config.Retry.ZoomDownload.MaxAttempts)
+	}
+	if config.Retry.ZoomDownload.BaseDelay != 2*time.Second || config.Retry.ZoomDownload.MaxDelay != time.Minute || config.Retry.ZoomDownload.Multiplier != 2 {
+		t.Errorf("Unexpected zoom_download policy: %+v", config.Retry.ZoomDownload)
+	}
+	if config.Retry.BoxUpload.MaxAttempts != 6 || config.Retry.BoxUpload.BaseDelay != 250*time.Millisecond || config.Retry.BoxUpload.Multiplier != 2 {
+		t.Errorf("Unexpected box_upload policy: %+v", config.Retry.BoxUpload)
+	}
+	if config.Retry.BoxAPI.MaxAttempts != 3 || config.Retry.BoxAPI.BaseDelay != 500*time.Millisecond {
+		t.Errorf("Expected default box_api policy, got %+v", config.Retry.BoxAPI)
+	}
+	if config.Limits.MaxRunDuration != 6*time.Hour {
+		t.Errorf("Expected max_run_duration of 6h, got %v", config.Limits.MaxRunDuration)
+	}
+}
+
+func TestLoadConfigFilters(t *testing.T) {
+	configYAML := `
+zoom:
+  account_id: "test_account"
+  client_id: "test_client"
+  client_secret: "test_secret"
+
+filters:
+  min_duration_minutes: 5
+  exclude_topic_regex: "(?i)standup"
+  meeting_types: [2, 8]
+`
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configPath, []byte(configYAML), 0644); err != nil {
+		t.Fatalf("Failed to create temp config file: %v", err)
+	}
+
+	config, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if config.Filters.MinDurationMinutes != 5 || config.Filters.ExcludeTopicRegex != "(?i)standup" || config.Filters.TopicRegex != "" {
+		t.Errorf("Unexpected filters: %+v", config.Filters)
+	}
+	if len(config.Filters.MeetingTypes) != 2 || config.Filters.MeetingTypes[1] != 8 {
+		t.Errorf("Expected meeting types [2 8], got %v", config.Filters.MeetingTypes)
+	}
+}
+
+func TestLoadConfigZoomRegion(t *testing.T) {
+	tests := []struct {
+		name         string
+		zoomYAML     string
+		wantBaseURL  string
+		wantOAuthURL string
+		wantErr      string
+	}{
+		{
+			name:         "default region",
+			wantBaseURL:  "https://api.zoom.us/v2",
+			wantOAuthURL: "https://zoom.us/oauth/token",
+		},
+		{
+			name:         "government",
+			zoomYAML:     `  region: "gov"`,
+			wantBaseURL:  "https://api.zoomgov.com/v2",
+			wantOAuthURL: "https://zoomgov.com/oauth/token",
+		},
+		{
+			name:         "explicit base url keeps the region's token endpoint",
+			zoomYAML:     "  region: \"eu\"\n  base_url: \"https://zoom.example.com/v2\"",
+			wantBaseURL:  "https://zoom.example.com/v2",
+			wantOAuthURL: "https://zoom.us/oauth/token",
+		},
+		{
+			name:     "unknown region",
+			zoomYAML: `  region: "apac"`,
+			wantErr:  "zoom.region must be one of",
+		},
+		{
+			name:     "government API with commercial tokens",
+			zoomYAML: `  base_url: "https://api.zoomgov.com/v2"`,
+			wantErr:  "must both be Zoom for Government",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configYAML := "zoom:\n  account_id: \"test_account\"\n  client_id: \"test_client\"\n  client_secret: \"test_secret\"\n" + tt.zoomYAML + "\n"
+			configPath := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(configPath, []byte(configYAML), 0644); err != nil {
+				t.Fatalf("Failed to create temp config file: %v", err)
+			}
+
+			config, err := LoadConfig(configPath)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if config.Zoom.BaseURL != tt.wantBaseURL || config.Zoom.OAuthURL != tt.wantOAuthURL {
+				t.Errorf("Got endpoints %s and %s, want %s and %s", config.Zoom.BaseURL, config.Zoom.OAuthURL, tt.wantBaseURL, tt.wantOAuthURL)
+			}
+		})
+	}
+}
+
+func TestLoadConfigUploadMetadataOrder(t *testing.T) {
+	configYAML := `
+zoom:
+  account_id: "test_account"
+  client_id: "test_client"
+  client_secret: "test_secret"
+`
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configPath, []byte(configYAML), 0644); err != nil {
+		t.Fatalf("Failed to create temp config file: %v", err)
+	}
+
+	config, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if config.Upload.MetadataOrder != "after" {
+		t.Errorf("Expected default metadata order 'after', got %q", config.Upload.MetadataOrder)
+	}
+
+	t.Setenv("UPLOAD_METADATA_ORDER", "before")
+	config, err = LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if config.Upload.MetadataOrder != "before" {
+		t.Errorf("Expected metadata order 'before' from environment, got %q", config.Upload.MetadataOrder)
+	}
+}
+
+func TestLoadConfigWithProfile(t *testing.T) {
+	configYAML := `
+zoom:
+  account_id: "shared_account"
+  client_id: "shared_client"
+  client_secret: "shared_secret"
+download:
+  output_dir: "./downloads"
+  retry_attempts: 5
+logging:
+  level: "debug"
+profiles:
+  prod:
+    zoom:
+      account_id: "prod_account"
+      client_id: "prod_client"
+      client_secret: "prod_secret"
+    download:
+      output_dir: "/data/prod"
+  edu:
+    zoom:
+      account_id: "edu_account"
+    box:
+      enabled: true
+      client_id: "edu_box_client"
+      client_secret: "edu_box_secret"
+      enterprise_id: "edu_enterprise"
+`
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configPath, []byte(configYAML), 0644); err != nil {
+		t.Fatalf("Failed to create temp config file: %v", err)
+	}
+
+	config, err := LoadConfigWithProfile(configPath, "prod")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if config.Profile != "prod" || config.Zoom.AccountID != "prod_account" || config.Zoom.ClientSecret != "prod_secret" {
+		t.Errorf("Expected the prod Zoom credentials, got %+v", config.Zoom)
+	}
+	if config.Download.OutputDir != "/data/prod" || config.Download.RetryAttempts != 5 {
+		t.Errorf("Expected the prod output dir with the shared retry attempts, got %+v", config.Download)
+	}
+	if config.Logging.Level != "debug" || config.Box.Enabled {
+		t.Errorf("Expected unmentioned sections to keep their top-level values, got logging %+v, box enabled %v", config.Logging, config.Box.Enabled)
+	}
+
+	config, err = LoadConfigWithProfile(configPath, "edu")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if config.Zoom.AccountID != "edu_account" || config.Zoom.ClientID != "shared_client" || !config.Box.Enabled {
+		t.Errorf("Expected the edu overlay on the shared settings, got zoom %+v, box enabled %v", config.Zoom, config.Box.Enabled)
+	}
+
+	config, err = LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if config.Profile != "" || config.Zoom.AccountID != "shared_account" {
+		t.Errorf("Expected the top-level settings without a profile, got %+v", config.Zoom)
+	}
+
+	_, err = LoadConfigWithProfile(configPath, "staging")
+	if err == nil || !strings.Contains(err.Error(), "available: edu, prod") {
+		t.Errorf("Expected an unknown profile error listing the profiles, got %v", err)
+	}
+}
+
+func TestLoadConfigFileNotFound(t *testing.T) {
+	_, err := LoadConfig("nonexistent_config.yaml")
+	if err == nil {
+		t.Error("Expected error for nonexistent config file, but got none")
+	}
+}
+
+func TestLoadConfigFromEnvironment(t *testing.T) {
+	// Set environment variables
+	os.Setenv("ZOOM_ACCOUNT_ID", "env_account")
+	os.Setenv("ZOOM_CLIENT_ID", "env_client")
+	os.Setenv("ZOOM_CLIENT_SECRET", "env_secret")
+	defer func() {
+		os.Unsetenv("ZOOM_ACCOUNT_ID")
+		os.Unsetenv("ZOOM_CLIENT_ID")
+		os.Unsetenv("ZOOM_CLIENT_SECRET")
+	}()
+
+	config := &Config{}
+	config.loadFromEnvironment()
+
+	if config.Zoom.AccountID != "env_account" {
+		t.Errorf("Expected AccountID from env %s, got %s", "env_account", config.Zoom.AccountID)
+	}
+	if config.Zoom.ClientID != "env_client" {
+		t.Errorf("Expected ClientID from env %s, got %s", "env_client", config.Zoom.ClientID)
+	}
+	if config.Zoom.ClientSecret != "env_secret" {
+		t.Errorf("Expected ClientSecret from env %s, got %s", "env_secret", config.Zoom.ClientSecret)
+	}
+}
+
+func TestLoadConfigFromEnvironment_NextClientSecrets(t *testing.T) {
+	os.Setenv("ZOOM_CLIENT_SECRET_NEXT", "zoom_next")
+	os.Setenv("BOX_CLIENT_SECRET_NEXT", "box_next")
+	defer func() {
+		os.Unsetenv("ZOOM_CLIENT_SECRET_NEXT")
+		os.Unsetenv("BOX_CLIENT_SECRET_NEXT")
+	}()
+
+	config := &Config{}
+	config.loadFromEnvironment()
+
+	if config.Zoom.ClientSecretNext != "zoom_next" {
+		t.Errorf("Expected Zoom ClientSecretNext from env %s, got %s", "zoom_next", config.Zoom.ClientSecretNext)
+	}
+	if config.Box.ClientSecretNext != "box_next" {
+		t.Errorf("Expected Box ClientSecretNext from env %s, got %s", "box_next", config.Box.ClientSecretNext)
+	}
+}
+
+func TestTimeoutDuration(t *testing.T) {
+	config := &Config{
+		Download: DownloadConfig{
+			TimeoutSeconds: 300,
+		},
+	}
+
+	expectedDuration := 300 * time.Second
+	if config.Download.TimeoutDuration() != expectedDuration {
+		t.Errorf("Expected timeout duration %v, got %v", expectedDuration, config.Download.TimeoutDuration())
+	}
+}
+
+func TestDisableUploads(t *testing.T) {
+	config := &Config{
+		Box:         BoxConfig{Enabled: true},
+		GoogleDrive: GoogleDriveConfig{Enabled: true},
+		S3:          S3Config{Enabled: true},
+		SFTP:        SFTPConfig{Enabled: true},
+		WebDAV:      WebDAVConfig{Enabled: true},
+	}
+
+	config.DisableUploads()
+	if config.Box.Enabled || config.GoogleDrive.Enabled || config.S3.Enabled || config.SFTP.Enabled || config.WebDAV.Enabled {
+		t.Errorf("Expected every upload destination to be disabled, got %+v", config)
+	}
+}
+
+func TestLogLevelValidation(t *testing.T) {
+	validLevels := []string{"debug", "info", "warn", "error"}
+	
+	for _, level := range validLevels {
+		t.Run("valid_level_"+level, func(t *testing.T) {
+			config := &Config{
+				Zoom: ZoomConfig{
+					AccountID:    "test_account",
+					ClientID:     "test_client",
+					ClientSecret: "test_secret",
+				},
+				Download: DownloadConfig{
+					RetryAttempts:   3,
+					TimeoutSeconds:  300,
+				},
+				Logging: LoggingConfig{
+					Level: level,
+				},
+			}
+			
+			err := config.Validate()
+			if err != nil {
+				t.Errorf("Valid log level %s should not cause error: %v", level, err)
+			}
+		})
+	}
+	
+	t.Run("invalid_log_level", func(t *testing.T) {
+		config := &Config{
+			Zoom: ZoomConfig{
+				AccountID:    "test_account",
+				ClientID:     "test_client",
+				ClientSecret: "test_secret",
+			},
+			Download: DownloadConfig{
+				RetryAttempts:  3,
+				TimeoutSeconds: 300,
+			},
+			Logging: LoggingConfig{
+				Level: "invalid",
+			},
+		}
+		
+		err := config.Validate()
+		if err == nil {
+			t.Error("Invalid log level should cause error")
+		}
+	})
+}
+
+func TestParseDateValue(t *testing.T) {
+	now := time.Date(2024, 6, 15, 13, 45, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		value       string
+		expected    *time.Time
+		shouldError bool
+	}{
+		{name: "empty value", value: "", expected: nil},
+		{name: "absolute date", value: "2024-01-02", expected: timePtr(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC))},
+		{name: "relative days", value: "90d", expected: timePtr(time.Date(2024, 3, 17, 0, 0, 0, 0, time.UTC))},
+		{name: "relative weeks", value: "2w", expected: timePtr(time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC))},
+		{name: "relative months", value: "6m", expected: timePtr(time.Date(2023, 12, 15, 0, 0, 0, 0, time.UTC))},
+		{name: "relative years", value: "1Y", expected: timePtr(time.Date(2023, 6, 15, 0, 0, 0, 0, time.UTC))},
+		{name: "zero days is today", value: "0d", expected: timePtr(time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC))},
+		{name: "unknown unit", value: "10h", shouldError: true},
+		{name: "negative amount", value: "-5d", shouldError: true},
+		{name: "garbage", value: "yesterday", shouldError: true},
+		{name: "invalid absolute date", value: "2024-13-40", shouldError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDateValue(tt.value, now)
+			if tt.shouldError {
+				if err == nil {
+					t.Errorf("Expected error for %q, got %v", tt.value, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if tt.expected == nil {
+				if got != nil {
+					t.Errorf("Expected nil, got %v", got)
+				}
+				return
+			}
+			if got == nil || !got.Equal(*tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestDownloadConfigDateRange(t *testing.T) {
+	now := time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)
+
+	t.Run("from after to is rejected", func(t *testing.T) {
+		cfg := DownloadConfig{FromDate: "2024-06-01", ToDate: "2024-05-01"}
+		if _, _, err := cfg.DateRange(now); err == nil {
+			t.Error("Expected error when from_date is after to_date")
+		}
+	})
+
+	t.Run("mixed absolute and relative", func(t *testing.T) {
+		cfg := DownloadConfig{FromDate: "2024-01-01", ToDate: "7d"}
+		from, to, err := cfg.DateRange(now)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if from == nil || from.Format("2006-01-02") != "2024-01-01" {
+			t.Errorf("Expected from 2024-01-01, got %v", from)
+		}
+		if to == nil || to.Format("2006-01-02") != "2024-06-08" {
+			t.Errorf("Expected to 2024-06-08, got %v", to)
+		}
+	})
+
+	t.Run("validate reports invalid date", func(t *testing.T) {
+		config := &Config{
+			Zoom: ZoomConfig{
+				AccountID:    "test_account",
+				ClientID:     "test_client",
+				ClientSecret: "test_secret",
+			},
+			Download: DownloadConfig{
+				RetryAttempts:  3,
+				TimeoutSeconds: 300,
+				FromDate:       "not-a-date",
+			},
+			Logging: LoggingConfig{
+				Level: "info",
+			},
+		}
+		if err := config.Validate(); err == nil {
+			t.Error("Expected validation error for invalid from_date")
+		}
+	})
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
@@ -0,0 +1,293 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+)
+
+// secretResolveTimeout bounds fetching all secret references of a config at load time
+const secretResolveTimeout = 30 * time.Second
+
+// SecretResolver fetches a secret referenced from the config, e.g. "secret/zoom#client_secret"
+// for the value "vault:secret/zoom#client_secret". The part after '#' names a field of the secret.
+type SecretResolver interface {
+	ResolveSecret(ctx context.Context, ref string) (string, error)
+}
+
+// SecretResolverFunc adapts a function to SecretResolver
+type SecretResolverFunc func(ctx context.Context, ref string) (string, error)
+
+// ResolveSecret calls f
+func (f SecretResolverFunc) ResolveSecret(ctx context.Context, ref string) (string, error) {
+	return f(ctx, ref)
+}
+
+var (
+	secretResolversMu sync.RWMutex
+	secretResolvers   = map[string]SecretResolver{
+		"vault":  &VaultResolver{},
+		"aws-sm": &AWSSecretsManagerResolver{},
+	}
+)
+
+// RegisterSecretResolver makes config values starting with "<scheme>:" resolve through resolver
+// It replaces any resolver registered for the scheme, including the built-in vault and aws-sm ones.
+func RegisterSecretResolver(scheme string, resolver SecretResolver) {
+	secretResolversMu.Lock()
+	defer secretResolversMu.Unlock()
+	secretResolvers[scheme] = resolver
+}
+
+// secretResolverFor returns the resolver for a "<scheme>:<ref>" value, or false for plain values
+func secretResolverFor(value string) (SecretResolver, string, bool) {
+	scheme, ref, ok := strings.Cut(value, ":")
+	if !ok || ref == "" {
+		return nil, "", false
+	}
+	secretResolversMu.RLock()
+	defer secretResolversMu.RUnlock()
+	resolver, ok := secretResolvers[scheme]
+	return resolver, ref, ok
+}
+
+// secretFields returns the credential settings that may hold secret references, by config key
+func (c *Config) secretFields() map[string]*string {
+	return map[string]*string{
+		"zoom.account_id":                 &c.Zoom.AccountID,
+		"zoom.client_id":                  &c.Zoom.ClientID,
+		"zoom.client_secret":              &c.Zoom.ClientSecret,
+		"zoom.client_secret_next":         &c.Zoom.ClientSecretNext,
+		"box.client_id":                   &c.Box.ClientID,
+		"box.client_secret":               &c.Box.ClientSecret,
+		"box.client_secret_next":          &c.Box.ClientSecretNext,
+		"box.enterprise_id":               &c.Box.EnterpriseID,
+		"webdav.username":                 &c.WebDAV.Username,
+		"webdav.password":                 &c.WebDAV.Password,
+		"webhook.secret_token":            &c.Webhook.SecretToken,
+		"control.token":                   &c.Control.Token,
+		"encryption.key":                  &c.Encryption.Key,
+		"encryption.kms_encrypted_key":    &c.Encryption.KMSEncryptedKey,
+		"notifications.slack.webhook_url": &c.Notifications.Slack.WebhookURL,
+		"notifications.teams.webhook_url": &c.Notifications.Teams.WebhookURL,
+		"notifications.email.username":    &c.Notifications.Email.Username,
+		"notifications.email.password":    &c.Notifications.Email.Password,
+		"network.proxy_url":               &c.Network.ProxyURL,
+	}
+}
+
+// resolveSecrets replaces secret references in the credential settings with the secrets they name
+// A reference used by several settings is fetched once.
+func (c *Config) resolveSecrets(ctx context.Context) error {
+	fields := c.secretFields()
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	resolved := make(map[string]string)
+	for _, key := range keys {
+		field := fields[key]
+		resolver, ref, ok := secretResolverFor(*field)
+		if !ok {
+			continue
+		}
+		if value, ok := resolved[*field]; ok {
+			*field = value
+			continue
+		}
+
+		value, err := resolver.ResolveSecret(ctx, ref)
+		if err != nil {
+			return fmt.Errorf("failed to resolve %s from %s: %w", key, *field, err)
+		}
+		resolved[*field] = value
+		*field = value
+	}
+	return nil
+}
+
+// splitSecretRef splits "path#field" into the secret's path and field ("" = whole secret)
+func splitSecretRef(ref string) (string, string) {
+	if i := strings.LastIndex(ref, "#"); i >= 0 {
+		return ref[:i], ref[i+1:]
+	}
+	return ref, ""
+}
+
+// secretField returns the named field of a secret's key/value data
+// Without a field name the secret must hold exactly one value.
+func secretField(data map[string]interface{}, field string) (string, error) {
+	if field == "" {
+		if len(data) != 1 {
+			return "", fmt.Errorf("secret has %d fields; name one with #field", len(data))
+		}
+		for only := range data {
+			field = only
+		}
+	}
+	value, ok := data[field]
+	if !ok {
+		return "", fmt.Errorf("secret has no field %q", field)
+	}
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+	return fmt.Sprint(value), nil
+}
+
+// VaultResolver reads secrets from HashiCorp Vault's KV secrets engine (version 1 or 2)
+// References are "<path>#<field>", e.g. "secret/zoom#client_secret"; the "data/" segment of
+// KV version 2 paths may be left out, as with 'vault kv get'.
+type VaultResolver struct {
+	Address    string       // Vault server ("" = VAULT_ADDR)
+	Token      string       // Vault token ("" = VAULT_TOKEN, then ~/.vault-token)
+	Namespace  string       // Vault Enterprise namespace ("" = VAULT_NAMESPACE)
+	HTTPClient *http.Client // Default: 10s timeout
+}
+
+// ResolveSecret reads the secret at ref from Vault
+func (v *VaultResolver) ResolveSecret(ctx context.Context, ref string) (string, error) {
+	address := v.Address
+	if address == "" {
+		address = os.Getenv("VAULT_ADDR")
+	}
+	if address == "" {
+		return "", fmt.Errorf("vault address is not set (set VAULT_ADDR)")
+	}
+	token, err := v.token()
+	if err != nil {
+		return "", err
+	}
+
+	path, field := splitSecretRef(strings.Trim(ref, "/"))
+	data, status, err := v.read(ctx, address, token, path)
+	if err == nil && status == http.StatusNotFound {
+		// KV version 2 serves secrets under <mount>/data/<path>
+		if mount, rest, ok := strings.Cut(path, "/"); ok && !strings.HasPrefix(rest, "data/") {
+			data, status, err = v.read(ctx, address, token, mount+"/data/"+rest)
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+	if status == http.StatusNotFound {
+		return "", fmt.Errorf("vault secret %s not found", path)
+	}
+
+	// KV version 2 nests the secret's values with its metadata
+	if inner, ok := data["data"].(map[string]interface{}); ok {
+		if _, versioned := data["metadata"]; versioned {
+			data = inner
+		}
+	}
+	return secretField(data, field)
+}
+
+// token returns the configured Vault token
+func (v *VaultResolver) token() (string, error) {
+	if v.Token != "" {
+		return v.Token, nil
+	}
+	if token := os.Getenv("VAULT_TOKEN"); token != "" {
+		return token, nil
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		if data, err := os.ReadFile(filepath.Join(home, ".vault-token")); err == nil {
+			return strings.TrimSpace(string(data)), nil
+		}
+	}
+	return "", fmt.Errorf("vault token is not set (set VAULT_TOKEN or run 'vault login')")
+}
+
+// read fetches a Vault path, returning the response's data and status
+func (v *VaultResolver) read(ctx context.Context, address, token, path string) (map[string]interface{}, int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(address, "/")+"/v1/"+path, nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to create vault request: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", token)
+	namespace := v.Namespace
+	if namespace == "" {
+		namespace = os.Getenv("VAULT_NAMESPACE")
+	}
+	if namespace != "" {
+		req.Header.Set("X-Vault-Namespace", namespace)
+	}
+
+	client := v.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("vault request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, resp.StatusCode, nil
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if resp.StatusCode != http.StatusOK {
+		return nil, resp.StatusCode, fmt.Errorf("vault returned %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var secret struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &secret); err != nil {
+		return nil, resp.StatusCode, fmt.Errorf("failed to parse vault response: %w", err)
+	}
+	return secret.Data, resp.StatusCode, nil
+}
+
+// AWSSecretsManagerResolver reads secrets from AWS Secrets Manager
+// References are "<secret id or ARN>#<json key>", e.g. "zoom-creds#client_secret"; without a
+// key the whole secret string is used. Credentials and the region come from the standard AWS chain.
+type AWSSecretsManagerResolver struct {
+	Region string // Overrides the AWS chain region
+}
+
+// ResolveSecret reads the secret at ref from AWS Secrets Manager
+func (r *AWSSecretsManagerResolver) ResolveSecret(ctx context.Context, ref string) (string, error) {
+	secretID, key := splitSecretRef(ref)
+
+	var loadOptions []func(*awsconfig.LoadOptions) error
+	if r.Region != "" {
+		loadOptions = append(loadOptions, awsconfig.WithRegion(r.Region))
+	}
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx, loadOptions...)
+	if err != nil {
+		return "", fmt.Errorf("failed to load AWS configuration: %w", err)
+	}
+
+	out, err := secretsmanager.NewFromConfig(awsCfg).GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{SecretId: &secretID})
+	if err != nil {
+		return "", fmt.Errorf("failed to read secret %s: %w", secretID, err)
+	}
+	if out.SecretString == nil {
+		return "", fmt.Errorf("secret %s has no string value", secretID)
+	}
+	if key == "" {
+		return *out.SecretString, nil
+	}
+
+	var data map[string]interface{}
+	if err := json.Unmarshal([]byte(*out.SecretString), &data); err != nil {
+		return "", fmt.Errorf("secret %s is not a JSON object, so #%s cannot be read: %w", secretID, key, err)
+	}
+	return secretField(data, key)
+}
@@ -0,0 +1,125 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestVaultResolver(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		if r.Header.Get("X-Vault-Token") != "test-token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/kv1/zoom":
+			fmt.Fprint(w, `{"data": {"client_secret": "kv1-secret"}}`)
+		case "/v1/secret/data/zoom":
+			fmt.Fprint(w, `{"data": {"data": {"client_secret": "kv2-secret", "client_id": "kv2-id"}, "metadata": {"version": 3}}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	resolver := &VaultResolver{Address: server.URL, Token: "test-token"}
+	tests := []struct {
+		ref     string
+		want    string
+		wantErr string
+	}{
+		{ref: "kv1/zoom#client_secret", want: "kv1-secret"},
+		{ref: "kv1/zoom", want: "kv1-secret"},
+		{ref: "secret/zoom#client_secret", want: "kv2-secret"},
+		{ref: "secret/data/zoom#client_id", want: "kv2-id"},
+		{ref: "secret/zoom", wantErr: "name one with #field"},
+		{ref: "secret/zoom#missing", wantErr: `no field "missing"`},
+		{ref: "secret/other#client_secret", wantErr: "not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			got, err := resolver.ResolveSecret(context.Background(), tt.ref)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ResolveSecret failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+
+	if _, err := (&VaultResolver{Address: server.URL, Token: "wrong"}).ResolveSecret(context.Background(), "kv1/zoom"); err == nil || !strings.Contains(err.Error(), "403") {
+		t.Errorf("Expected a rejected token to fail, got %v", err)
+	}
+}
+
+func TestLoadConfigResolvesSecrets(t *testing.T) {
+	var lookups []string
+	RegisterSecretResolver("test-sm", SecretResolverFunc(func(ctx context.Context, ref string) (string, error) {
+		lookups = append(lookups, ref)
+		if ref == "broken" {
+			return "", fmt.Errorf("access denied")
+		}
+		return "resolved-" + ref, nil
+	}))
+	t.Cleanup(func() {
+		secretResolversMu.Lock()
+		delete(secretResolvers, "test-sm")
+		secretResolversMu.Unlock()
+	})
+
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	write := func(content string) {
+		if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write(`zoom:
+  account_id: "account"
+  client_id: "test-sm:zoom#id"
+  client_secret: "test-sm:zoom#secret"
+box:
+  client_secret: "test-sm:zoom#secret"
+notifications:
+  slack:
+    webhook_url: "https://hooks.slack.com/services/T000/B000/XXXX"
+`)
+	config, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if config.Zoom.ClientID != "resolved-zoom#id" || config.Zoom.ClientSecret != "resolved-zoom#secret" || config.Box.ClientSecret != "resolved-zoom#secret" {
+		t.Errorf("Expected secret references resolved, got %+v and %+v", config.Zoom, config.Box)
+	}
+	if config.Notifications.Slack.WebhookURL != "https://hooks.slack.com/services/T000/B000/XXXX" {
+		t.Errorf("Expected plain values kept, got %q", config.Notifications.Slack.WebhookURL)
+	}
+	if len(lookups) != 2 {
+		t.Errorf("Expected each reference looked up once, got %v", lookups)
+	}
+
+	write(`zoom:
+  account_id: "account"
+  client_id: "client"
+  client_secret: "test-sm:broken"
+`)
+	if _, err := LoadConfig(configPath); err == nil || !strings.Contains(err.Error(), "zoom.client_secret") || !strings.Contains(err.Error(), "access denied") {
+		t.Errorf("Expected the failed lookup reported with its setting, got %v", err)
+	}
+}
@@ -0,0 +1,154 @@
+package control
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// APIPrefix is the path prefix of every control API endpoint
+const APIPrefix = "/api/v1/"
+
+// maxBodySize bounds control API request bodies
+const maxBodySize = 64 << 10
+
+// enqueueRequest is the body of POST /api/v1/users
+type enqueueRequest struct {
+	ZoomEmail string `json:"zoom_email"`
+	BoxEmail  string `json:"box_email"` // Defaults to the Zoom email
+}
+
+// NewHandler creates the control API handler; every request must carry "Authorization: Bearer <token>"
+//
+// Endpoints:
+//
+//	GET  /api/v1/status          controller state
+//	GET  /api/v1/users           progress of every enqueued user
+//	POST /api/v1/users           enqueue a user: {"zoom_email": "...", "box_email": "..."}
+//	GET  /api/v1/users/{email}   progress of one user
+//	POST /api/v1/pause           stop starting new users and recordings
+//	POST /api/v1/resume          continue processing
+//	GET  /api/v1/events          server-sent event stream of progress events
+func NewHandler(controller *Controller, token string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+APIPrefix+"status", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, controller.Status())
+	})
+	mux.HandleFunc("GET "+APIPrefix+"users", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, controller.Users())
+	})
+	mux.HandleFunc("POST "+APIPrefix+"users", func(w http.ResponseWriter, r *http.Request) {
+		handleEnqueue(w, r, controller)
+	})
+	mux.HandleFunc("GET "+APIPrefix+"users/{email}", func(w http.ResponseWriter, r *http.Request) {
+		status, ok := controller.User(r.PathValue("email"))
+		if !ok {
+			writeError(w, http.StatusNotFound, "user not found")
+			return
+		}
+		writeJSON(w, http.StatusOK, status)
+	})
+	mux.HandleFunc("POST "+APIPrefix+"pause", func(w http.ResponseWriter, r *http.Request) {
+		controller.Pause()
+		writeJSON(w, http.StatusOK, controller.Status())
+	})
+	mux.HandleFunc("POST "+APIPrefix+"resume", func(w http.ResponseWriter, r *http.Request) {
+		controller.Resume()
+		writeJSON(w, http.StatusOK, controller.Status())
+	})
+	mux.HandleFunc("GET "+APIPrefix+"events", func(w http.ResponseWriter, r *http.Request) {
+		streamEvents(w, r, controller)
+	})
+
+	return requireToken(token, mux)
+}
+
+// requireToken rejects requests without the expected bearer token
+func requireToken(token string, next http.Handler) http.Handler {
+	expected := []byte("Bearer " + token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if token == "" || subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), expected) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="zoom-to-box"`)
+			writeError(w, http.StatusUnauthorized, "invalid or missing bearer token")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// handleEnqueue adds a user to the controller's queue
+func handleEnqueue(w http.ResponseWriter, r *http.Request, controller *Controller) {
+	var req enqueueRequest
+	decoder := json.NewDecoder(io.LimitReader(r.Body, maxBodySize))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
+		return
+	}
+	if !strings.Contains(req.ZoomEmail, "@") {
+		writeError(w, http.StatusBadRequest, "zoom_email must be an email address")
+		return
+	}
+	if req.BoxEmail != "" && !strings.Contains(req.BoxEmail, "@") {
+		writeError(w, http.StatusBadRequest, "box_email must be an email address")
+		return
+	}
+
+	status, err := controller.EnqueueUser(req.ZoomEmail, req.BoxEmail)
+	if err != nil {
+		code := http.StatusInternalServerError
+		if errors.Is(err, ErrQueueFull) || errors.Is(err, ErrQueueClosed) {
+			code = http.StatusServiceUnavailable
+		}
+		writeError(w, code, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusAccepted, status)
+}
+
+// streamEvents writes controller events as server-sent events until the client disconnects
+func streamEvents(w http.ResponseWriter, r *http.Request, controller *Controller) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "streaming is not supported")
+		return
+	}
+
+	events, cancel := controller.Subscribe()
+	defer cancel()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event := <-events:
+			data, err := json.Marshal(event)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+			flusher.Flush()
+		}
+	}
+}
+
+// writeJSON writes v as a JSON response
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes a JSON error response
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
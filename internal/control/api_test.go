@@ -0,0 +1,165 @@
+package control
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testToken = "control-token"
+
+func apiRequest(method, path, body string) *http.Request {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	return req
+}
+
+func TestHandler_RequiresToken(t *testing.T) {
+	h := NewHandler(NewController(&fakeRunner{}, 10), testToken)
+
+	tests := []struct {
+		name   string
+		header string
+	}{
+		{name: "missing", header: ""},
+		{name: "wrong token", header: "Bearer wrong"},
+		{name: "wrong scheme", header: "Basic " + testToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, APIPrefix+"status", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if rec.Code != http.StatusUnauthorized {
+				t.Errorf("Expected 401, got %d", rec.Code)
+			}
+		})
+	}
+
+	// An empty configured token never authenticates
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, APIPrefix+"status", nil)
+	req.Header.Set("Authorization", "Bearer ")
+	NewHandler(NewController(&fakeRunner{}, 10), "").ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 with an empty token, got %d", rec.Code)
+	}
+}
+
+func TestHandler_EnqueueAndQueryUsers(t *testing.T) {
+	c := NewController(&fakeRunner{}, 10)
+	h := NewHandler(c, testToken)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, apiRequest(http.MethodPost, APIPrefix+"users", `{"zoom_email": "jane@example.com", "box_email": "jane@box.example.com"}`))
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("Expected 202, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, apiRequest(http.MethodPost, APIPrefix+"users", `{"zoom_email": "not-an-email"}`))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an invalid email, got %d", rec.Code)
+	}
+
+	c.Close()
+	c.Run(context.Background())
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, apiRequest(http.MethodGet, APIPrefix+"users/jane@example.com", ""))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", rec.Code)
+	}
+	var status UserStatus
+	if err := json.NewDecoder(rec.Body).Decode(&status); err != nil {
+		t.Fatalf("Failed to decode user status: %v", err)
+	}
+	if status.State != StateCompleted || status.BoxEmail != "jane@box.example.com" {
+		t.Errorf("Unexpected user status: %+v", status)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, apiRequest(http.MethodGet, APIPrefix+"users", ""))
+	var statuses []UserStatus
+	if err := json.NewDecoder(rec.Body).Decode(&statuses); err != nil || len(statuses) != 1 {
+		t.Errorf("Expected one user in the list, got %v (%v)", statuses, err)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, apiRequest(http.MethodGet, APIPrefix+"users/unknown@example.com", ""))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown user, got %d", rec.Code)
+	}
+}
+
+func TestHandler_PauseResume(t *testing.T) {
+	c := NewController(&fakeRunner{}, 10)
+	h := NewHandler(c, testToken)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, apiRequest(http.MethodPost, APIPrefix+"pause", ""))
+	if rec.Code != http.StatusOK || !c.Status().Paused {
+		t.Errorf("Expected pause to succeed, got %d (paused=%v)", rec.Code, c.Status().Paused)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, apiRequest(http.MethodPost, APIPrefix+"resume", ""))
+	if rec.Code != http.StatusOK || c.Status().Paused {
+		t.Errorf("Expected resume to succeed, got %d (paused=%v)", rec.Code, c.Status().Paused)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, apiRequest(http.MethodGet, APIPrefix+"pause", ""))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected 405 for GET pause, got %d", rec.Code)
+	}
+}
+
+func TestHandler_StreamEvents(t *testing.T) {
+	c := NewController(&fakeRunner{}, 10)
+	server := httptest.NewServer(NewHandler(c, testToken))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+APIPrefix+"events", nil)
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to open event stream: %v", err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Expected an event stream, got %q", ct)
+	}
+
+	if _, err := c.EnqueueUser("jane@example.com", ""); err != nil {
+		t.Fatalf("EnqueueUser failed: %v", err)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data: ") {
+			continue
+		}
+		var event Event
+		if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &event); err != nil {
+			t.Fatalf("Failed to decode event: %v", err)
+		}
+		if event.Type != EventUserQueued || event.User == nil || event.User.ZoomEmail != "jane@example.com" {
+			t.Errorf("Unexpected event: %+v", event)
+		}
+		return
+	}
+	t.Fatalf("Event stream ended without an event: %v", scanner.Err())
+}
@@ -0,0 +1,357 @@
+// Package control lets an orchestration service drive a running zoom-to-box daemon
+// Users can be enqueued for processing, their progress queried, processing paused and
+// resumed, and progress events streamed over an authenticated HTTP API.
+package control
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/curtbushko/zoom-to-box/internal/logging"
+	"github.com/curtbushko/zoom-to-box/internal/processor"
+)
+
+// DefaultQueueSize is the number of users that can wait for processing
+const DefaultQueueSize = 100
+
+// Controller errors
+var (
+	ErrQueueFull   = errors.New("user queue is full")
+	ErrQueueClosed = errors.New("user queue is closed")
+)
+
+// User states reported by the API
+const (
+	StateQueued    = "queued"
+	StateRunning   = "running"
+	StateCompleted = "completed"
+	StateFailed    = "failed"
+)
+
+// UserRunner processes all recordings of a single user
+type UserRunner interface {
+	ProcessUser(ctx context.Context, zoomEmail, boxEmail string) (*processor.ProcessorResult, error)
+}
+
+// UserStatus is the progress of a user enqueued through the API
+type UserStatus struct {
+	ZoomEmail       string    `json:"zoom_email"`
+	BoxEmail        string    `json:"box_email"`
+	State           string    `json:"state"`
+	QueuedAt        time.Time `json:"queued_at"`
+	StartedAt       time.Time `json:"started_at,omitzero"`
+	FinishedAt      time.Time `json:"finished_at,omitzero"`
+	Downloaded      int       `json:"downloaded"`
+	Uploaded        int       `json:"uploaded"`
+	Skipped         int       `json:"skipped"`
+	Errors          int       `json:"errors"`
+	BytesDownloaded int64     `json:"bytes_downloaded"`
+	Error           string    `json:"error,omitempty"`
+}
+
+// Status describes the controller as a whole
+type Status struct {
+	Paused bool `json:"paused"`
+	Queued int  `json:"queued"` // Users waiting to start, including one held back by a pause
+	Active bool `json:"active"` // A user or webhook recording is being processed
+}
+
+// userJob is a user waiting in the queue
+type userJob struct {
+	zoomEmail string
+	boxEmail  string
+}
+
+// Controller queues users for processing and serializes all processing in the daemon
+// Webhook recordings and API-enqueued users share one processor, so both acquire the
+// controller's processing slot through Acquire; pausing stops new work from starting
+// while in-flight work finishes.
+type Controller struct {
+	runner UserRunner
+	jobs   chan userJob
+	events *broker
+
+	slot chan struct{} // Held while a user or recording is processed
+
+	mu      sync.Mutex
+	users   map[string]*UserStatus // Keyed by lower-cased Zoom email
+	paused  bool
+	resumed chan struct{} // Closed when processing resumes
+	active  bool
+	closed  bool
+	nowFunc func() time.Time
+}
+
+// NewController creates a controller holding up to queueSize users (default: 100)
+func NewController(runner UserRunner, queueSize int) *Controller {
+	if queueSize <= 0 {
+		queueSize = DefaultQueueSize
+	}
+	return &Controller{
+		runner:  runner,
+		jobs:    make(chan userJob, queueSize),
+		events:  newBroker(),
+		slot:    make(chan struct{}, 1),
+		users:   make(map[string]*UserStatus),
+		nowFunc: time.Now,
+	}
+}
+
+// EnqueueUser adds a user to the queue without blocking
+// A user that is already queued or running is not queued again; its current status is returned.
+func (c *Controller) EnqueueUser(zoomEmail, boxEmail string) (UserStatus, error) {
+	zoomEmail = strings.TrimSpace(zoomEmail)
+	boxEmail = strings.TrimSpace(boxEmail)
+	if boxEmail == "" {
+		boxEmail = zoomEmail
+	}
+	key := strings.ToLower(zoomEmail)
+
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return UserStatus{}, ErrQueueClosed
+	}
+	if existing, ok := c.users[key]; ok && (existing.State == StateQueued || existing.State == StateRunning) {
+		status := *existing
+		c.mu.Unlock()
+		return status, nil
+	}
+
+	select {
+	case c.jobs <- userJob{zoomEmail: zoomEmail, boxEmail: boxEmail}:
+	default:
+		c.mu.Unlock()
+		return UserStatus{}, ErrQueueFull
+	}
+
+	status := &UserStatus{
+		ZoomEmail: zoomEmail,
+		BoxEmail:  boxEmail,
+		State:     StateQueued,
+		QueuedAt:  c.nowFunc(),
+	}
+	c.users[key] = status
+	snapshot := *status
+	c.mu.Unlock()
+
+	c.publish(EventUserQueued, snapshot, "")
+	return snapshot, nil
+}
+
+// User returns the status of a user enqueued through the API
+func (c *Controller) User(zoomEmail string) (UserStatus, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	status, ok := c.users[strings.ToLower(strings.TrimSpace(zoomEmail))]
+	if !ok {
+		return UserStatus{}, false
+	}
+	return *status, true
+}
+
+// Users returns the status of every user enqueued through the API, oldest first
+func (c *Controller) Users() []UserStatus {
+	c.mu.Lock()
+	statuses := make([]UserStatus, 0, len(c.users))
+	for _, status := range c.users {
+		statuses = append(statuses, *status)
+	}
+	c.mu.Unlock()
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].QueuedAt.Before(statuses[j].QueuedAt)
+	})
+	return statuses
+}
+
+// Status returns the controller's current state
+func (c *Controller) Status() Status {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	queued := 0
+	for _, status := range c.users {
+		if status.State == StateQueued {
+			queued++
+		}
+	}
+	return Status{Paused: c.paused, Queued: queued, Active: c.active}
+}
+
+// Pause stops new users and recordings from starting; in-flight work finishes
+func (c *Controller) Pause() {
+	c.mu.Lock()
+	if c.paused {
+		c.mu.Unlock()
+		return
+	}
+	c.paused = true
+	c.resumed = make(chan struct{})
+	c.mu.Unlock()
+
+	logging.Info("Processing paused through the control API")
+	c.events.publish(Event{Type: EventPaused, Time: c.nowFunc()})
+}
+
+// Resume lets paused processing continue
+func (c *Controller) Resume() {
+	c.mu.Lock()
+	if !c.paused {
+		c.mu.Unlock()
+		return
+	}
+	c.paused = false
+	close(c.resumed)
+	c.mu.Unlock()
+
+	logging.Info("Processing resumed through the control API")
+	c.events.publish(Event{Type: EventResumed, Time: c.nowFunc()})
+}
+
+// Acquire waits until processing is not paused and the processing slot is free
+// The returned release function must be called once the work is done.
+func (c *Controller) Acquire(ctx context.Context) (func(), error) {
+	for {
+		c.mu.Lock()
+		paused, resumed := c.paused, c.resumed
+		c.mu.Unlock()
+
+		if paused {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-resumed:
+				continue
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case c.slot <- struct{}{}:
+		}
+
+		// Pause may have been requested while waiting for the slot
+		c.mu.Lock()
+		if c.paused {
+			c.mu.Unlock()
+			<-c.slot
+			continue
+		}
+		c.active = true
+		c.mu.Unlock()
+
+		return func() {
+			c.mu.Lock()
+			c.active = false
+			c.mu.Unlock()
+			<-c.slot
+		}, nil
+	}
+}
+
+// Subscribe returns a channel of events and a function that cancels the subscription
+func (c *Controller) Subscribe() (<-chan Event, func()) {
+	return c.events.subscribe()
+}
+
+// Close stops accepting users; Run returns once the queued users are processed
+func (c *Controller) Close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.closed {
+		c.closed = true
+		close(c.jobs)
+	}
+}
+
+// Run processes queued users until the queue is closed and drained, or ctx is cancelled
+func (c *Controller) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case job, ok := <-c.jobs:
+			if !ok {
+				return
+			}
+			if err := c.processUser(ctx, job); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// processUser runs a queued user and records the outcome
+// Only a cancelled context is returned as an error; processing failures are recorded on the user.
+func (c *Controller) processUser(ctx context.Context, job userJob) error {
+	release, err := c.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	started := c.update(job.zoomEmail, func(status *UserStatus) {
+		status.State = StateRunning
+		status.StartedAt = c.nowFunc()
+	})
+	c.publish(EventUserStarted, started, "")
+
+	result, err := c.runner.ProcessUser(ctx, job.zoomEmail, job.boxEmail)
+
+	finished := c.update(job.zoomEmail, func(status *UserStatus) {
+		status.FinishedAt = c.nowFunc()
+		if result != nil {
+			status.Downloaded = result.DownloadedCount
+			status.Uploaded = result.UploadedCount
+			status.Skipped = result.SkippedCount
+			status.Errors = result.ErrorCount
+			status.BytesDownloaded = result.BytesDownloaded
+		}
+		if err != nil {
+			status.State = StateFailed
+			status.Error = err.Error()
+		} else {
+			status.State = StateCompleted
+		}
+	})
+
+	if err != nil {
+		logging.Error("Failed to process user %s enqueued through the control API: %v", job.zoomEmail, err)
+		c.publish(EventUserFailed, finished, err.Error())
+	} else {
+		c.publish(EventUserCompleted, finished, "")
+	}
+	return nil
+}
+
+// update applies fn to a user's status and returns a copy of the result
+func (c *Controller) update(zoomEmail string, fn func(status *UserStatus)) UserStatus {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := strings.ToLower(zoomEmail)
+	status, ok := c.users[key]
+	if !ok {
+		status = &UserStatus{ZoomEmail: zoomEmail}
+		c.users[key] = status
+	}
+	fn(status)
+	return *status
+}
+
+// publish sends a user event to all subscribers
+func (c *Controller) publish(eventType string, status UserStatus, message string) {
+	c.events.publish(Event{
+		Type:    eventType,
+		Time:    c.nowFunc(),
+		User:    &status,
+		Message: message,
+	})
+}
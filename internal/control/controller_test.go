@@ -0,0 +1,159 @@
+package control
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/curtbushko/zoom-to-box/internal/processor"
+)
+
+// fakeRunner records processed users and can block until released
+type fakeRunner struct {
+	mu        sync.Mutex
+	processed []string
+	err       error
+	block     chan struct{}
+}
+
+func (f *fakeRunner) ProcessUser(ctx context.Context, zoomEmail, boxEmail string) (*processor.ProcessorResult, error) {
+	if f.block != nil {
+		<-f.block
+	}
+	f.mu.Lock()
+	f.processed = append(f.processed, zoomEmail+"->"+boxEmail)
+	f.mu.Unlock()
+	return &processor.ProcessorResult{ZoomEmail: zoomEmail, DownloadedCount: 2, UploadedCount: 1, BytesDownloaded: 2048}, f.err
+}
+
+func waitForState(t *testing.T, c *Controller, zoomEmail, state string) UserStatus {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if status, ok := c.User(zoomEmail); ok && status.State == state {
+			return status
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	status, _ := c.User(zoomEmail)
+	t.Fatalf("Timed out waiting for %s to be %s, got %+v", zoomEmail, state, status)
+	return UserStatus{}
+}
+
+func TestController_ProcessesQueuedUsers(t *testing.T) {
+	runner := &fakeRunner{}
+	c := NewController(runner, 10)
+
+	status, err := c.EnqueueUser("Jane@example.com", "")
+	if err != nil {
+		t.Fatalf("EnqueueUser failed: %v", err)
+	}
+	if status.State != StateQueued || status.BoxEmail != "Jane@example.com" {
+		t.Errorf("Unexpected queued status: %+v", status)
+	}
+
+	// Enqueuing a waiting user again returns the existing status
+	if _, err := c.EnqueueUser("jane@example.com", "other@example.com"); err != nil {
+		t.Fatalf("EnqueueUser failed: %v", err)
+	}
+	if got := c.Status().Queued; got != 1 {
+		t.Errorf("Expected 1 queued user, got %d", got)
+	}
+
+	c.Close()
+	c.Run(context.Background())
+
+	status = waitForState(t, c, "jane@example.com", StateCompleted)
+	if status.Downloaded != 2 || status.Uploaded != 1 || status.BytesDownloaded != 2048 || status.FinishedAt.IsZero() {
+		t.Errorf("Unexpected completed status: %+v", status)
+	}
+	if len(runner.processed) != 1 || runner.processed[0] != "Jane@example.com->Jane@example.com" {
+		t.Errorf("Expected one processed user, got %v", runner.processed)
+	}
+
+	if _, err := c.EnqueueUser("john@example.com", ""); !errors.Is(err, ErrQueueClosed) {
+		t.Errorf("Expected ErrQueueClosed after Close, got %v", err)
+	}
+}
+
+func TestController_FailedUser(t *testing.T) {
+	c := NewController(&fakeRunner{err: errors.New("zoom unavailable")}, 10)
+	if _, err := c.EnqueueUser("jane@example.com", "jane@box.example.com"); err != nil {
+		t.Fatalf("EnqueueUser failed: %v", err)
+	}
+	c.Close()
+	c.Run(context.Background())
+
+	status := waitForState(t, c, "jane@example.com", StateFailed)
+	if status.Error != "zoom unavailable" {
+		t.Errorf("Expected the error to be recorded, got %+v", status)
+	}
+}
+
+func TestController_QueueFull(t *testing.T) {
+	c := NewController(&fakeRunner{}, 1)
+	if _, err := c.EnqueueUser("a@example.com", ""); err != nil {
+		t.Fatalf("EnqueueUser failed: %v", err)
+	}
+	if _, err := c.EnqueueUser("b@example.com", ""); !errors.Is(err, ErrQueueFull) {
+		t.Errorf("Expected ErrQueueFull, got %v", err)
+	}
+}
+
+func TestController_PauseResume(t *testing.T) {
+	c := NewController(&fakeRunner{}, 10)
+	events, cancel := c.Subscribe()
+	defer cancel()
+
+	c.Pause()
+	if !c.Status().Paused {
+		t.Fatal("Expected the controller to be paused")
+	}
+
+	acquired := make(chan func(), 1)
+	go func() {
+		release, err := c.Acquire(context.Background())
+		if err == nil {
+			acquired <- release
+		}
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("Expected Acquire to wait while paused")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	c.Resume()
+	select {
+	case release := <-acquired:
+		if !c.Status().Active {
+			t.Error("Expected the controller to be active while the slot is held")
+		}
+		release()
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected Acquire to return after resume")
+	}
+
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	c.Pause()
+	cancelCtx()
+	if _, err := c.Acquire(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected a cancelled Acquire to fail, got %v", err)
+	}
+
+	var types []string
+	for len(types) < 3 {
+		select {
+		case event := <-events:
+			types = append(types, event.Type)
+		case <-time.After(time.Second):
+			t.Fatalf("Timed out waiting for events, got %v", types)
+		}
+	}
+	if types[0] != EventPaused || types[1] != EventResumed || types[2] != EventPaused {
+		t.Errorf("Unexpected events: %v", types)
+	}
+}
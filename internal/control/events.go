@@ -0,0 +1,70 @@
+package control
+
+import (
+	"sync"
+	"time"
+)
+
+// Event types streamed to subscribers
+const (
+	EventUserQueued    = "user.queued"
+	EventUserStarted   = "user.started"
+	EventUserCompleted = "user.completed"
+	EventUserFailed    = "user.failed"
+	EventPaused        = "processing.paused"
+	EventResumed       = "processing.resumed"
+)
+
+// subscriberBuffer is the number of events a slow subscriber can fall behind before events are dropped
+const subscriberBuffer = 64
+
+// Event is a change in the controller's state
+type Event struct {
+	Type    string      `json:"type"`
+	Time    time.Time   `json:"time"`
+	User    *UserStatus `json:"user,omitempty"`
+	Message string      `json:"message,omitempty"`
+}
+
+// broker fans events out to subscribers without blocking the publisher
+type broker struct {
+	mu          sync.Mutex
+	subscribers map[chan Event]struct{}
+}
+
+// newBroker creates a broker with no subscribers
+func newBroker() *broker {
+	return &broker{subscribers: make(map[chan Event]struct{})}
+}
+
+// subscribe registers a new subscriber
+func (b *broker) subscribe() (<-chan Event, func()) {
+	ch := make(chan Event, subscriberBuffer)
+
+	b.mu.Lock()
+	b.subscribers[ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subscribers, ch)
+			b.mu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+// publish delivers an event to every subscriber, dropping it for subscribers that are full
+func (b *broker) publish(event Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package directory
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/curtbushko/zoom-to-box/internal/filename"
+	"github.com/curtbushko/zoom-to-box/internal/timefmt"
+)
+
+// DefaultLayout reproduces the built-in <user>/<year>/<month>/<day> layout
+const DefaultLayout = "{{.User}}/{{.Year}}/{{.Month}}/{{.Day}}"
+
+// LayoutData holds the fields available to directory layout templates
+type LayoutData struct {
+	User  string // Username part of the Box email, e.g. "jane.smith"
+	Year  string // e.g. "2024"
+	Month string // e.g. "01"
+	Day   string // e.g. "15"
+	Date  string // e.g. "2024-01-15"
+}
+
+// Layout renders the folder a recording is stored in from a text/template
+// The same folders are used below the local download directory and in the upload destination.
+// Destinations already keep each user under their own root folder, so a leading {{.User}}
+// folder is only used locally.
+type Layout struct {
+	tmpl *template.Template
+}
+
+// NewLayout parses a directory layout template; an empty text uses DefaultLayout
+// Every layout must contain {{.User}} so users' recordings never share a local folder.
+func NewLayout(text string) (*Layout, error) {
+	if text == "" {
+		text = DefaultLayout
+	}
+	tmpl, err := template.New("layout").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid directory layout: %w", err)
+	}
+
+	l := &Layout{tmpl: tmpl}
+	segments, err := l.segments("layout-user", time.Now())
+	if err != nil {
+		return nil, err
+	}
+	hasUser := false
+	for _, segment := range segments {
+		if strings.Contains(segment, "layout-user") {
+			hasUser = true
+		}
+	}
+	if !hasUser {
+		return nil, fmt.Errorf("invalid directory layout: %q must contain {{.User}}", text)
+	}
+	return l, nil
+}
+
+// LocalPath returns the folder for a user's recording relative to the download directory
+func (l *Layout) LocalPath(username string, meetingTime time.Time) (string, error) {
+	segments, err := l.segments(username, meetingTime)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(segments...), nil
+}
+
+// FolderPath returns the folder for a user's recording below their root folder in the
+// upload destination, e.g. "2024/01/15" ("" = the root folder itself)
+func (l *Layout) FolderPath(username string, meetingTime time.Time) (string, error) {
+	segments, err := l.segments(username, meetingTime)
+	if err != nil {
+		return "", err
+	}
+	if len(segments) > 0 && segments[0] == filename.SanitizeSegment(username) {
+		segments = segments[1:]
+	}
+	return path.Join(segments...), nil
+}
+
+// Owner maps a folder relative to the download directory back to the user it belongs to
+// It returns which of usernames the folder was rendered for and the folder's path in the upload
+// destination; ok is false when relDir is not a layout folder of any of them. Layouts that
+// combine {{.User}} with date fields in one folder name are not matched.
+func (l *Layout) Owner(relDir string, usernames []string) (username, folderPath string, ok bool) {
+	parts := strings.FieldsFunc(relDir, func(r rune) bool { return r == '/' || r == '\\' })
+
+	// Any date works: only the folder holding the username is compared
+	reference := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	sentinel, err := l.segments("layout-user", reference)
+	if err != nil || len(sentinel) != len(parts) {
+		return "", "", false
+	}
+	userIndex := -1
+	for i, segment := range sentinel {
+		if strings.Contains(segment, "layout-user") {
+			userIndex = i
+			break
+		}
+	}
+	if userIndex < 0 {
+		return "", "", false
+	}
+
+	for _, candidate := range usernames {
+		segments, err := l.segments(candidate, reference)
+		if err != nil || len(segments) != len(parts) || segments[userIndex] != parts[userIndex] {
+			continue
+		}
+		if userIndex == 0 && parts[0] == filename.SanitizeSegment(candidate) {
+			return candidate, path.Join(parts[1:]...), true
+		}
+		return candidate, path.Join(parts...), true
+	}
+	return "", "", false
+}
+
+// segments renders the layout and sanitizes each folder name; empty, "." and ".." folders are dropped
+func (l *Layout) segments(username string, meetingTime time.Time) ([]string, error) {
+	t := timefmt.In(meetingTime)
+	data := LayoutData{
+		User:  username,
+		Year:  t.Format("2006"),
+		Month: t.Format("01"),
+		Day:   t.Format("02"),
+		Date:  t.Format("2006-01-02"),
+	}
+
+	var buf bytes.Buffer
+	if err := l.tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("invalid directory layout: %w", err)
+	}
+
+	var segments []string
+	for _, segment := range strings.FieldsFunc(buf.String(), func(r rune) bool { return r == '/' || r == '\\' }) {
+		if segment = filename.SanitizeSegment(segment); segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	return segments, nil
+}
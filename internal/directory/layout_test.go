@@ -0,0 +1,122 @@
+package directory
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/curtbushko/zoom-to-box/internal/users"
+)
+
+func TestLayout_Paths(t *testing.T) {
+	meetingTime := time.Date(2024, 1, 15, 9, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		layout     string
+		wantLocal  string
+		wantFolder string
+	}{
+		{
+			name:       "default layout",
+			layout:     "",
+			wantLocal:  filepath.Join("jane.smith", "2024", "01", "15"),
+			wantFolder: "2024/01/15",
+		},
+		{
+			name:       "user below month",
+			layout:     "{{.Year}}/{{.Month}}/{{.User}}",
+			wantLocal:  filepath.Join("2024", "01", "jane.smith"),
+			wantFolder: "2024/01/jane.smith",
+		},
+		{
+			name:       "flat per user",
+			layout:     "{{.User}}",
+			wantLocal:  "jane.smith",
+			wantFolder: "",
+		},
+		{
+			name:       "unsafe and relative segments are cleaned",
+			layout:     "{{.User}}/../{{.Date}}: archive//",
+			wantLocal:  filepath.Join("jane.smith", "2024-01-15-archive"),
+			wantFolder: "2024-01-15-archive",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			layout, err := NewLayout(tt.layout)
+			if err != nil {
+				t.Fatalf("NewLayout failed: %v", err)
+			}
+			local, err := layout.LocalPath("jane.smith", meetingTime)
+			if err != nil || local != tt.wantLocal {
+				t.Errorf("Expected local path %q, got %q (err=%v)", tt.wantLocal, local, err)
+			}
+			folder, err := layout.FolderPath("jane.smith", meetingTime)
+			if err != nil || folder != tt.wantFolder {
+				t.Errorf("Expected folder path %q, got %q (err=%v)", tt.wantFolder, folder, err)
+			}
+		})
+	}
+}
+
+func TestNewLayout_RejectsInvalidLayouts(t *testing.T) {
+	for _, text := range []string{"{{.User", "{{.Topic}}/{{.User}}", "{{.Year}}/{{.Month}}"} {
+		if _, err := NewLayout(text); err == nil {
+			t.Errorf("Expected NewLayout(%q) to fail", text)
+		}
+	}
+}
+
+func TestGenerateDirectory_UsesLayout(t *testing.T) {
+	layout, err := NewLayout("{{.Year}}/{{.User}}")
+	if err != nil {
+		t.Fatalf("NewLayout failed: %v", err)
+	}
+	activeUserManager, err := users.NewActiveUserManager(users.ActiveUserConfig{FilePath: ""})
+	if err != nil {
+		t.Fatalf("Failed to create active user manager: %v", err)
+	}
+	defer activeUserManager.Close()
+	dm := NewDirectoryManager(DirectoryConfig{BaseDirectory: t.TempDir(), Layout: layout}, activeUserManager)
+
+	result, err := dm.GenerateDirectory("jane.smith@example.com", time.Date(2024, 1, 15, 9, 30, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("GenerateDirectory failed: %v", err)
+	}
+	if want := filepath.Join("2024", "jane.smith"); result.RelativePath != want {
+		t.Errorf("Expected relative path %q, got %q", want, result.RelativePath)
+	}
+}
+
+func TestLayout_Owner(t *testing.T) {
+	usernames := []string{"john.doe", "jane.smith"}
+	tests := []struct {
+		name       string
+		layout     string
+		relDir     string
+		wantUser   string
+		wantFolder string
+		wantOK     bool
+	}{
+		{"default layout", "", filepath.Join("jane.smith", "2024", "01", "15"), "jane.smith", "2024/01/15", true},
+		{"user below month", "{{.Year}}/{{.Month}}/{{.User}}", filepath.Join("2024", "01", "jane.smith"), "jane.smith", "2024/01/jane.smith", true},
+		{"flat per user", "{{.User}}", "john.doe", "john.doe", "", true},
+		{"unknown user", "", filepath.Join("someone", "2024", "01", "15"), "", "", false},
+		{"not a layout folder", "", filepath.Join("jane.smith", "2024"), "", "", false},
+		{"download directory itself", "", "", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			layout, err := NewLayout(tt.layout)
+			if err != nil {
+				t.Fatalf("NewLayout failed: %v", err)
+			}
+			user, folder, ok := layout.Owner(tt.relDir, usernames)
+			if ok != tt.wantOK || user != tt.wantUser || folder != tt.wantFolder {
+				t.Errorf("Owner(%q) = %q, %q, %v; want %q, %q, %v", tt.relDir, user, folder, ok, tt.wantUser, tt.wantFolder, tt.wantOK)
+			}
+		})
+	}
+}
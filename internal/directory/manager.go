@@ -5,10 +5,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/curtbushko/zoom-to-box/internal/email"
 	"github.com/curtbushko/zoom-to-box/internal/filename"
+	"github.com/curtbushko/zoom-to-box/internal/timefmt"
 	"github.com/curtbushko/zoom-to-box/internal/users"
 	"github.com/curtbushko/zoom-to-box/internal/zoom"
 )
@@ -23,6 +25,11 @@ type DirectoryManager interface {
 type DirectoryConfig struct {
 	BaseDirectory string // Base directory path for all downloads
 	CreateDirs    bool   // Whether to create directories if they don't exist
+
+	Layout *Layout // Folder layout below the base directory (nil = DefaultLayout)
+
+	WindowsSafe   bool // Mangle reserved Windows names in folders and fit file paths into MaxPathLength
+	MaxPathLength int  // Longest file path in WindowsSafe mode (0 = WindowsMaxPath)
 }
 
 // DirectoryResult represents the result of directory generation
@@ -34,12 +41,20 @@ type DirectoryResult struct {
 	Day           string // Day component (DD)
 	BasePath      string // Base directory path
 	RelativePath  string // Relative path from base directory
+
+	MaxPathLength int // File names are shortened to keep file paths within this length (0 = no limit)
 }
 
 // GenerateFilePath creates a complete file path with sanitized filename for a recording
 func (dr *DirectoryResult) GenerateFilePath(recording zoom.Recording, fileType string, sanitizer filename.FileSanitizer) string {
 	filename := sanitizer.GenerateFilename(recording, fileType)
-	return filepath.Join(dr.FullPath, filename)
+	if dr.MaxPathLength > 0 {
+		ext := filepath.Ext(filename)
+		if fitted, err := FitName(dr.FullPath, strings.TrimSuffix(filename, ext), len(ext), dr.MaxPathLength); err == nil {
+			filename = fitted + ext
+		}
+	}
+	return LongPath(filepath.Join(dr.FullPath, filename))
 }
 
 // GenerateRelativeFilePath creates a relative file path from the base directory
@@ -116,20 +131,35 @@ func (dm *directoryManagerImpl) GenerateDirectory(userEmail string, meetingDate
 	userDir := email.ExtractUsername(boxEmail)
 	
 	// Convert meeting date to UTC for consistent directory structure
-	utcDate := meetingDate.UTC()
+	utcDate := timefmt.In(meetingDate)
 	
 	// Generate date components
 	year := utcDate.Format("2006")
 	month := utcDate.Format("01")
 	day := utcDate.Format("02")
 	
-	// Build directory path: <base>/<user>/<year>/<month>/<day>
+	// Build directory path: <base>/<layout>, <base>/<user>/<year>/<month>/<day> by default
 	relativePath := filepath.Join(userDir, year, month, day)
+	if dm.config.Layout != nil {
+		layoutPath, err := dm.config.Layout.LocalPath(userDir, meetingDate)
+		if err != nil {
+			return nil, err
+		}
+		relativePath = layoutPath
+	}
+	var maxPathLength int
+	if dm.config.WindowsSafe {
+		relativePath = SafePath(relativePath)
+		maxPathLength = dm.config.MaxPathLength
+		if maxPathLength <= 0 {
+			maxPathLength = WindowsMaxPath
+		}
+	}
 	fullPath := filepath.Join(dm.config.BaseDirectory, relativePath)
 	
 	// Create directory if requested
 	if dm.config.CreateDirs {
-		if err := os.MkdirAll(fullPath, 0755); err != nil {
+		if err := os.MkdirAll(LongPath(fullPath), 0755); err != nil {
 			return nil, fmt.Errorf("failed to create directory %s: %w", fullPath, err)
 		}
 		
@@ -146,6 +176,7 @@ func (dm *directoryManagerImpl) GenerateDirectory(userEmail string, meetingDate
 		Day:           day,
 		BasePath:      dm.config.BaseDirectory,
 		RelativePath:  relativePath,
+		MaxPathLength: maxPathLength,
 	}, nil
 }
 
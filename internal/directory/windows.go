@@ -0,0 +1,95 @@
+package directory
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/curtbushko/zoom-to-box/internal/filename"
+)
+
+// WindowsMaxPath is the longest path most Windows programs accept: MAX_PATH without the
+// terminating NUL
+const WindowsMaxPath = 259
+
+// longPathThreshold is the path length from which LongPath adds the \\?\ prefix; directories
+// are limited to MAX_PATH minus room for an 8.3 file name
+const longPathThreshold = 248
+
+// minNameLength is the shortest name FitName shortens a file name to
+const minNameLength = 8
+
+// LongPath returns path with the Windows \\?\ long path prefix when it is long enough to
+// need one, so Win32 calls accept it beyond MAX_PATH; other platforms get path unchanged
+func LongPath(path string) string {
+	if runtime.GOOS != "windows" {
+		return path
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+	return windowsLongPath(abs)
+}
+
+// windowsLongPath prefixes an absolute Windows path of longPathThreshold or more characters:
+// drive paths become \\?\C:\... and UNC paths \\?\UNC\server\share\...
+func windowsLongPath(path string) string {
+	if len(path) < longPathThreshold || strings.HasPrefix(path, `\\?\`) {
+		return path
+	}
+	// The prefix turns off path normalization, so only backslashes are accepted
+	path = strings.ReplaceAll(path, "/", `\`)
+	switch {
+	case strings.HasPrefix(path, `\\`):
+		return `\\?\UNC\` + path[2:]
+	case len(path) >= 3 && path[1] == ':' && path[2] == '\\':
+		return `\\?\` + path
+	default:
+		return path
+	}
+}
+
+// SafePath returns a relative path with each segment made usable on Windows, e.g. a user
+// folder named "con" becomes "con_"
+func SafePath(relative string) string {
+	segments := strings.Split(filepath.ToSlash(relative), "/")
+	for i, segment := range segments {
+		segments[i] = filename.WindowsSafeName(segment)
+	}
+	return filepath.FromSlash(strings.Join(segments, "/"))
+}
+
+// FitName shortens name so a file named name plus reserve more characters (extension,
+// collision suffix, ...) fits in dir within maxLength characters of absolute path
+// It returns name unchanged when it fits or maxLength is 0, and an error when dir leaves
+// room for fewer than minNameLength characters.
+func FitName(dir, name string, reserve, maxLength int) (string, error) {
+	if maxLength <= 0 {
+		return name, nil
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", dir, err)
+	}
+	available := maxLength - len(abs) - len(string(filepath.Separator)) - reserve
+	if len(name) <= available {
+		return name, nil
+	}
+	if available < minNameLength {
+		return "", fmt.Errorf("path of %s exceeds the %d character limit: %d characters left for file names", dir, maxLength, available)
+	}
+
+	// Cut at a rune boundary, then drop separators the cut left at the end
+	end := available
+	for end > 0 && !utf8.RuneStart(name[end]) {
+		end--
+	}
+	fitted := strings.TrimRight(name[:end], "-. ")
+	if fitted == "" {
+		fitted = name[:end]
+	}
+	return filename.WindowsSafeName(fitted), nil
+}
@@ -0,0 +1,65 @@
+package directory
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWindowsLongPath(t *testing.T) {
+	long := strings.Repeat("a", 250)
+	tests := []struct {
+		name string
+		path string
+		want string
+	}{
+		{"short path", `C:\zoom\jane`, `C:\zoom\jane`},
+		{"long drive path", `C:\` + long, `\\?\C:\` + long},
+		{"long UNC path", `\\server\share\` + long, `\\?\UNC\server\share\` + long},
+		{"forward slashes", `C:/zoom/` + long, `\\?\C:\zoom\` + long},
+		{"already prefixed", `\\?\C:\` + long, `\\?\C:\` + long},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := windowsLongPath(tt.path); got != tt.want {
+				t.Errorf("windowsLongPath() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSafePath(t *testing.T) {
+	got := SafePath(filepath.Join("con", "2024", "01", "15"))
+	if want := filepath.Join("con_", "2024", "01", "15"); got != want {
+		t.Errorf("SafePath() = %q, want %q", got, want)
+	}
+}
+
+func TestFitName(t *testing.T) {
+	dir := t.TempDir()
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	name := strings.Repeat("weekly-", 40)
+
+	// 28 characters left after the directory, separator and reserve; the cut's trailing dash is dropped
+	maxLength := len(abs) + 1 + 4 + 28
+	fitted, err := FitName(dir, name, 4, maxLength)
+	if err != nil {
+		t.Fatalf("FitName failed: %v", err)
+	}
+	if fitted != "weekly-weekly-weekly-weekly" {
+		t.Errorf("FitName() = %q", fitted)
+	}
+
+	if got, err := FitName(dir, "short", 4, maxLength); err != nil || got != "short" {
+		t.Errorf("FitName() = %q, %v; want the name unchanged", got, err)
+	}
+	if got, err := FitName(dir, name, 4, 0); err != nil || got != name {
+		t.Errorf("FitName() without a limit = %q, %v; want the name unchanged", got, err)
+	}
+	if _, err := FitName(dir, name, 4, len(abs)+8); err == nil {
+		t.Error("Expected an error when the directory leaves no room for the name")
+	}
+}
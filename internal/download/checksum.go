@@ -0,0 +1,92 @@
+package download
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"strings"
+
+	"lukechampine.com/blake3"
+)
+
+// ChecksumAlgorithm identifies the hash function used for file checksums
+type ChecksumAlgorithm string
+
+const (
+	ChecksumSHA256 ChecksumAlgorithm = "sha256"
+	ChecksumBLAKE3 ChecksumAlgorithm = "blake3"
+
+	// DefaultChecksumAlgorithm is used when no algorithm is configured
+	DefaultChecksumAlgorithm = ChecksumSHA256
+)
+
+// ParseChecksumAlgorithm validates an algorithm name; empty selects the default
+func ParseChecksumAlgorithm(name string) (ChecksumAlgorithm, error) {
+	switch ChecksumAlgorithm(strings.ToLower(strings.TrimSpace(name))) {
+	case "":
+		return DefaultChecksumAlgorithm, nil
+	case ChecksumSHA256:
+		return ChecksumSHA256, nil
+	case ChecksumBLAKE3:
+		return ChecksumBLAKE3, nil
+	default:
+		return "", fmt.Errorf("unsupported checksum algorithm %q: must be one of sha256, blake3", name)
+	}
+}
+
+// newHash returns a fresh hash.Hash for the algorithm
+func (a ChecksumAlgorithm) newHash() (hash.Hash, error) {
+	switch a {
+	case ChecksumSHA256:
+		return sha256.New(), nil
+	case ChecksumBLAKE3:
+		return blake3.New(32, nil), nil
+	default:
+		return nil, fmt.Errorf("unsupported checksum algorithm %q", string(a))
+	}
+}
+
+// CalculateFileChecksumWith calculates a file checksum using the given algorithm.
+// The result is prefixed with the algorithm name, e.g. "blake3:<hex>".
+func CalculateFileChecksumWith(filePath string, algorithm ChecksumAlgorithm) (string, error) {
+	h, err := algorithm.newHash()
+	if err != nil {
+		return "", err
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to open file for checksum: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := io.Copy(h, file); err != nil {
+		return "", fmt.Errorf("failed to calculate checksum: %w", err)
+	}
+
+	return fmt.Sprintf("%s:%x", algorithm, h.Sum(nil)), nil
+}
+
+// CalculateChecksumWith calculates the checksum of everything read from r, prefixed like
+// CalculateFileChecksumWith, e.g. for files that are decrypted as they are read
+func CalculateChecksumWith(r io.Reader, algorithm ChecksumAlgorithm) (string, error) {
+	h, err := algorithm.newHash()
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(h, r); err != nil {
+		return "", fmt.Errorf("failed to calculate checksum: %w", err)
+	}
+	return fmt.Sprintf("%s:%x", algorithm, h.Sum(nil)), nil
+}
+
+// SplitChecksum separates a prefixed checksum into its algorithm and digest.
+// Checksums without a prefix are treated as SHA-256 for compatibility.
+func SplitChecksum(checksum string) (ChecksumAlgorithm, string) {
+	if algo, digest, found := strings.Cut(checksum, ":"); found {
+		return ChecksumAlgorithm(strings.ToLower(algo)), digest
+	}
+	return ChecksumSHA256, checksum
+}
@@ -0,0 +1,99 @@
+// Package download provides checksum algorithm tests
+package download
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseChecksumAlgorithm(t *testing.T) {
+	tests := []struct {
+		input       string
+		expected    ChecksumAlgorithm
+		shouldError bool
+	}{
+		{input: "", expected: ChecksumSHA256},
+		{input: "sha256", expected: ChecksumSHA256},
+		{input: "BLAKE3", expected: ChecksumBLAKE3},
+		{input: " blake3 ", expected: ChecksumBLAKE3},
+		{input: "md5", shouldError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			algo, err := ParseChecksumAlgorithm(tt.input)
+			if tt.shouldError {
+				if err == nil {
+					t.Errorf("Expected error for %q", tt.input)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if algo != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, algo)
+			}
+		})
+	}
+}
+
+func TestCalculateFileChecksumWith(t *testing.T) {
+	testFile := filepath.Join(t.TempDir(), "test.mp4")
+	if err := os.WriteFile(testFile, []byte("recording content"), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	sha, err := CalculateFileChecksumWith(testFile, ChecksumSHA256)
+	if err != nil {
+		t.Fatalf("SHA-256 checksum failed: %v", err)
+	}
+	if !strings.HasPrefix(sha, "sha256:") || len(sha) != len("sha256:")+64 {
+		t.Errorf("Unexpected SHA-256 checksum format: %s", sha)
+	}
+
+	b3, err := CalculateFileChecksumWith(testFile, ChecksumBLAKE3)
+	if err != nil {
+		t.Fatalf("BLAKE3 checksum failed: %v", err)
+	}
+	if !strings.HasPrefix(b3, "blake3:") || len(b3) != len("blake3:")+64 {
+		t.Errorf("Unexpected BLAKE3 checksum format: %s", b3)
+	}
+
+	if _, err := CalculateFileChecksumWith(testFile, ChecksumAlgorithm("crc32")); err == nil {
+		t.Error("Expected error for unsupported algorithm")
+	}
+}
+
+func TestVerifyFileChecksum_UsesRecordedAlgorithm(t *testing.T) {
+	testFile := filepath.Join(t.TempDir(), "test.mp4")
+	if err := os.WriteFile(testFile, []byte("recording content"), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	b3, err := CalculateFileChecksumWith(testFile, ChecksumBLAKE3)
+	if err != nil {
+		t.Fatalf("BLAKE3 checksum failed: %v", err)
+	}
+
+	valid, err := VerifyFileChecksum(testFile, b3)
+	if err != nil {
+		t.Fatalf("VerifyFileChecksum failed: %v", err)
+	}
+	if !valid {
+		t.Error("Expected BLAKE3 checksum to verify")
+	}
+
+	// Legacy checksums without a prefix are SHA-256
+	sha, _ := CalculateFileChecksumWith(testFile, ChecksumSHA256)
+	_, digest := SplitChecksum(sha)
+	valid, err = VerifyFileChecksum(testFile, digest)
+	if err != nil {
+		t.Fatalf("VerifyFileChecksum failed: %v", err)
+	}
+	if !valid {
+		t.Error("Expected unprefixed SHA-256 digest to verify")
+	}
+}
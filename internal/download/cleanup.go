@@ -0,0 +1,103 @@
+package download
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// CleanupOptions controls which local recordings CleanupUploaded removes
+type CleanupOptions struct {
+	BaseDir           string    // Relative status file paths are resolved against it; files outside it are never removed
+	Before            time.Time // Only files uploaded before this time are removed
+	SidecarExtensions []string  // Metadata files removed with each MP4, e.g. ".json"
+	DryRun            bool      // Report the files without removing them
+}
+
+// CleanupFile is a local file CleanupUploaded removed, or would remove in a dry run
+type CleanupFile struct {
+	Path       string
+	Size       int64
+	UploadDate time.Time
+}
+
+// CleanupSummary is the outcome of CleanupUploaded
+type CleanupSummary struct {
+	Files      []CleanupFile // Removed files, sorted by path
+	Bytes      int64         // Disk space reclaimed
+	Unverified int           // Uploaded MP4s kept because the destination did not verify them
+	Errors     []error
+}
+
+// CleanupUploaded removes the local MP4 recordings, with their metadata sidecars, whose upload the
+// status file records as verified by the destination (size and checksum) before opts.Before.
+// Files still downloading, with a failed or unverified upload, or outside opts.BaseDir are kept.
+// The status file is not changed: its upload index keeps later runs from downloading them again.
+func CleanupUploaded(tracker StatusTracker, opts CleanupOptions) *CleanupSummary {
+	summary := &CleanupSummary{}
+	for _, entry := range tracker.GetAllDownloads() {
+		if entry.Box == nil || !entry.Box.Uploaded || !strings.EqualFold(filepath.Ext(entry.FilePath), ".mp4") {
+			continue
+		}
+		if !entry.Box.Verified {
+			summary.Unverified++
+			continue
+		}
+		if entry.Box.UploadDate.IsZero() || !entry.Box.UploadDate.Before(opts.Before) {
+			continue
+		}
+
+		path, err := cleanupPath(opts.BaseDir, entry.FilePath)
+		if err != nil {
+			summary.Errors = append(summary.Errors, err)
+			continue
+		}
+		// A partial download means the file was fetched again after the upload
+		if IsPartial(path) {
+			continue
+		}
+
+		stem := strings.TrimSuffix(path, filepath.Ext(path))
+		paths := []string{path}
+		for _, ext := range opts.SidecarExtensions {
+			paths = append(paths, stem+ext)
+		}
+		for _, p := range paths {
+			info, err := os.Stat(p)
+			if err != nil || !info.Mode().IsRegular() {
+				continue
+			}
+			if !opts.DryRun {
+				if err := os.Remove(p); err != nil {
+					summary.Errors = append(summary.Errors, fmt.Errorf("failed to remove %s: %w", p, err))
+					continue
+				}
+			}
+			summary.Files = append(summary.Files, CleanupFile{Path: p, Size: info.Size(), UploadDate: entry.Box.UploadDate})
+			summary.Bytes += info.Size()
+		}
+	}
+
+	sort.Slice(summary.Files, func(i, j int) bool { return summary.Files[i].Path < summary.Files[j].Path })
+	return summary
+}
+
+// cleanupPath resolves a status file path and makes sure it lies below baseDir
+func cleanupPath(baseDir, filePath string) (string, error) {
+	base, err := filepath.Abs(baseDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", baseDir, err)
+	}
+	path := filePath
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(base, path)
+	}
+	rel, err := filepath.Rel(base, path)
+	if err != nil || !filepath.IsLocal(rel) {
+		return "", fmt.Errorf("refusing to remove %s: not below %s", filePath, baseDir)
+	}
+	return path, nil
+}
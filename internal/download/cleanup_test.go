@@ -0,0 +1,69 @@
+package download
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCleanupUploaded(t *testing.T) {
+	baseDir := t.TempDir()
+	tracker, err := NewStatusTracker(filepath.Join(baseDir, ".status.json"))
+	if err != nil {
+		t.Fatalf("NewStatusTracker failed: %v", err)
+	}
+
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	old, recent := now.AddDate(0, 0, -40), now.AddDate(0, 0, -5)
+	write := func(rel string) {
+		path := filepath.Join(baseDir, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("recording"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	entries := map[string]DownloadEntry{
+		"verified":   {FilePath: "jane/2024/04/01/standup-0930.mp4", Box: &BoxUploadInfo{Uploaded: true, Verified: true, UploadDate: old}},
+		"recent":     {FilePath: "jane/2024/05/27/review-1000.mp4", Box: &BoxUploadInfo{Uploaded: true, Verified: true, UploadDate: recent}},
+		"unverified": {FilePath: "jane/2024/04/02/planning-1100.mp4", Box: &BoxUploadInfo{Uploaded: true, UploadDate: old}},
+		"failed":     {FilePath: "jane/2024/04/03/retro-1200.mp4", Box: &BoxUploadInfo{UploadError: "timeout", UploadDate: old}},
+		"outside":    {FilePath: "../elsewhere/demo-1300.mp4", Box: &BoxUploadInfo{Uploaded: true, Verified: true, UploadDate: old}},
+	}
+	for id, entry := range entries {
+		if entry.FilePath != "../elsewhere/demo-1300.mp4" {
+			write(entry.FilePath)
+		}
+		if err := tracker.UpdateDownloadStatus(id, entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("jane/2024/04/01/standup-0930.json")
+
+	opts := CleanupOptions{BaseDir: baseDir, Before: now.AddDate(0, 0, -30), SidecarExtensions: []string{".json"}, DryRun: true}
+	summary := CleanupUploaded(tracker, opts)
+	if len(summary.Files) != 2 || summary.Unverified != 1 || len(summary.Errors) != 1 {
+		t.Fatalf("Unexpected dry run summary: %+v", summary)
+	}
+	if _, err := os.Stat(filepath.Join(baseDir, "jane/2024/04/01/standup-0930.mp4")); err != nil {
+		t.Errorf("Dry run removed the recording: %v", err)
+	}
+
+	opts.DryRun = false
+	summary = CleanupUploaded(tracker, opts)
+	if len(summary.Files) != 2 || summary.Bytes != 18 {
+		t.Fatalf("Unexpected summary: %+v", summary)
+	}
+	for _, rel := range []string{"jane/2024/04/01/standup-0930.mp4", "jane/2024/04/01/standup-0930.json"} {
+		if _, err := os.Stat(filepath.Join(baseDir, rel)); !os.IsNotExist(err) {
+			t.Errorf("Expected %s removed, got %v", rel, err)
+		}
+	}
+	for _, rel := range []string{"jane/2024/05/27/review-1000.mp4", "jane/2024/04/02/planning-1100.mp4", "jane/2024/04/03/retro-1200.mp4"} {
+		if _, err := os.Stat(filepath.Join(baseDir, rel)); err != nil {
+			t.Errorf("Expected %s kept: %v", rel, err)
+		}
+	}
+}
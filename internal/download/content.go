@@ -0,0 +1,114 @@
+package download
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/curtbushko/zoom-to-box/internal/errclass"
+)
+
+// ContentTypeError is a download answered with a web page or other content that is not the
+// requested file. Zoom serves its passcode prompt this way for passcode-protected recordings.
+type ContentTypeError struct {
+	ContentType string
+}
+
+func (e *ContentTypeError) Error() string {
+	if e.passcodePage() {
+		return fmt.Sprintf("download returned a %s page instead of the file; the recording is likely passcode protected", e.ContentType)
+	}
+	return fmt.Sprintf("download returned %s content instead of the file", e.ContentType)
+}
+
+// ErrorClass classifies web pages as passcode prompts
+func (e *ContentTypeError) ErrorClass() errclass.Class {
+	if e.passcodePage() {
+		return errclass.Passcode
+	}
+	return errclass.Unknown
+}
+
+// passcodePage reports whether the content is a web page
+func (e *ContentTypeError) passcodePage() bool {
+	return e.ContentType == "text/html" || e.ContentType == "application/xhtml+xml"
+}
+
+// checkContentType rejects responses that cannot be the requested file: web pages always, and
+// anything other than audio, video or binary content for media downloads. Responses without a
+// content type are accepted.
+func checkContentType(resp *http.Response, mediaOnly bool) error {
+	header := resp.Header.Get("Content-Type")
+	if header == "" {
+		return nil
+	}
+	contentType, _, err := mime.ParseMediaType(header)
+	if err != nil {
+		contentType = strings.ToLower(strings.TrimSpace(strings.Split(header, ";")[0]))
+	}
+
+	typeErr := &ContentTypeError{ContentType: contentType}
+	if typeErr.passcodePage() {
+		return typeErr
+	}
+	if !mediaOnly {
+		return nil
+	}
+	switch {
+	case strings.HasPrefix(contentType, "video/"), strings.HasPrefix(contentType, "audio/"),
+		contentType == "application/octet-stream", contentType == "binary/octet-stream", contentType == "application/mp4":
+		return nil
+	}
+	return typeErr
+}
+
+// MediaSniffLength is how much of a media download's body is checked, and reported when it is
+// not media
+const MediaSniffLength = 512
+
+// MediaSignatureError is a media download whose body does not start like an MP4 or M4A file,
+// e.g. a JSON error or an HTML login page sent as binary content
+type MediaSignatureError struct {
+	Head []byte // Up to the first 512 bytes of the body
+}
+
+func (e *MediaSignatureError) Error() string {
+	return fmt.Sprintf("downloaded content is not an MP4 or M4A file, it starts with %q", e.Head)
+}
+
+// ErrorClass classifies web pages as passcode prompts
+func (e *MediaSignatureError) ErrorClass() errclass.Class {
+	head := strings.ToLower(strings.TrimSpace(string(e.Head)))
+	if strings.HasPrefix(head, "<!doctype html") || strings.HasPrefix(head, "<html") {
+		return errclass.Passcode
+	}
+	return errclass.Unknown
+}
+
+// isoMediaBoxes are the top-level boxes an MP4 or M4A file can start with
+var isoMediaBoxes = map[string]bool{
+	"ftyp": true, "styp": true, "moov": true, "mdat": true, "free": true, "skip": true, "wide": true, "pdin": true,
+}
+
+// CheckMediaSignature rejects a body that does not start with an ISO base media (MP4, M4A) box;
+// the box type follows the 4-byte box size. head should hold the first MediaSniffLength bytes
+// of the body, or the whole body when it is shorter.
+func CheckMediaSignature(head []byte) error {
+	if len(head) >= 8 && isoMediaBoxes[string(head[4:8])] {
+		return nil
+	}
+	if len(head) > MediaSniffLength {
+		head = head[:MediaSniffLength]
+	}
+	return &MediaSignatureError{Head: append([]byte(nil), head...)}
+}
+
+// permanentContentError reports whether err is content that is not the file, which another
+// attempt would only download again
+func permanentContentError(err error) bool {
+	var typeErr *ContentTypeError
+	var signatureErr *MediaSignatureError
+	return errors.As(err, &typeErr) || errors.As(err, &signatureErr)
+}
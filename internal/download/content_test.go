@@ -0,0 +1,82 @@
+package download
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/curtbushko/zoom-to-box/internal/errclass"
+)
+
+// mp4Head is the start of an MP4 file: the size and type of its ftyp box, then the brand
+const mp4Head = "\x00\x00\x00\x20ftypisom\x00\x00\x02\x00"
+
+const passcodePage = "<html><body>Enter the passcode</body></html>"
+
+func TestDownloadContentType(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		mediaOnly   bool
+		wantClass   errclass.Class
+		wantErr     bool
+	}{
+		{name: "passcode page", contentType: "text/html; charset=utf-8", body: passcodePage, wantErr: true, wantClass: errclass.Passcode},
+		{name: "passcode page for a media file", contentType: "text/html", body: passcodePage, mediaOnly: true, wantErr: true, wantClass: errclass.Passcode},
+		{name: "json error for a media file", contentType: "application/json", body: `{"code": 124}`, mediaOnly: true, wantErr: true, wantClass: errclass.Unknown},
+		{name: "video", contentType: "video/mp4", body: mp4Head + "video data", mediaOnly: true},
+		{name: "audio", contentType: "audio/mp4", body: "\x00\x00\x00\x1cftypM4A \x00\x00\x02\x00", mediaOnly: true},
+		{name: "binary", contentType: "application/octet-stream", body: mp4Head, mediaOnly: true},
+		{name: "web page sent as binary", contentType: "application/octet-stream", body: passcodePage, mediaOnly: true, wantErr: true, wantClass: errclass.Passcode},
+		{name: "json error sent as video", contentType: "video/mp4", body: `{"code": 3001, "message": "File does not exist"}`, mediaOnly: true, wantErr: true, wantClass: errclass.Unknown},
+		{name: "transcript", contentType: "text/vtt", body: "WEBVTT\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				w.Header().Set("Content-Type", tt.contentType)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			manager := NewDownloadManager(DownloadConfig{RetryAttempts: 2, RetryDelay: time.Millisecond})
+			destination := filepath.Join(t.TempDir(), "recording.mp4")
+			_, err := manager.Download(context.Background(), DownloadRequest{URL: server.URL, Destination: destination, MediaOnly: tt.mediaOnly}, nil)
+
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("Download failed: %v", err)
+				}
+				if data, _ := os.ReadFile(destination); string(data) != tt.body {
+					t.Errorf("Expected the whole body to be saved, got %q", data)
+				}
+				return
+			}
+			if !permanentContentError(err) {
+				t.Fatalf("Expected the content to be rejected, got %v", err)
+			}
+			var signatureErr *MediaSignatureError
+			if errors.As(err, &signatureErr) && string(signatureErr.Head) != tt.body {
+				t.Errorf("Expected the start of the body in the error, got %q", signatureErr.Head)
+			}
+			if class := errclass.Of(err); class != tt.wantClass {
+				t.Errorf("Expected class %s, got %s", tt.wantClass, class)
+			}
+			if requests != 1 {
+				t.Errorf("Expected the page not to be requested again, got %d requests", requests)
+			}
+			if _, err := os.Stat(destination); !os.IsNotExist(err) {
+				t.Errorf("Expected nothing to be written for the page")
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@
 package download
 
 import (
+	"bufio"
 	"context"
 	"fmt"
 	"io"
@@ -9,6 +10,11 @@ import (
 	"os"
 	"path/filepath"
 	"time"
+
+	"github.com/curtbushko/zoom-to-box/internal/retry"
+	"github.com/curtbushko/zoom-to-box/internal/tracing"
+	"github.com/curtbushko/zoom-to-box/internal/zoom"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // DownloadManager defines the interface for download operations
@@ -23,6 +29,11 @@ type DownloadConfig struct {
 	RetryDelay    time.Duration // Delay between retry attempts
 	UserAgent     string        // User agent string for HTTP requests
 	Timeout       time.Duration // HTTP request timeout
+
+	RetryMultiplier float64       // Growth factor applied to RetryDelay per retry (0 or 1 = fixed delay)
+	RetryMaxDelay   time.Duration // Upper bound for a single retry delay (0 = no bound)
+
+	ReadBufferSize int // Read buffer of each connection in bytes (0 = the transport's default)
 }
 
 // DownloadRequest represents a single download request
@@ -33,6 +44,12 @@ type DownloadRequest struct {
 	FileSize    int64                  // Expected file size in bytes (for progress tracking)
 	Headers     map[string]string      // Additional HTTP headers
 	Metadata    map[string]interface{} // Additional metadata for tracking
+
+	MaxBytes int64 // Download only this many leading bytes, e.g. for previews (0 = whole file)
+
+	IfRange string // ETag or Last-Modified date a partial destination was downloaded from ("" = the one in its resume file)
+
+	MediaOnly bool // Reject responses that are not audio, video or binary content, or do not start like an MP4/M4A file, e.g. Zoom's passcode page
 }
 
 // ProgressUpdate represents download progress information
@@ -60,6 +77,7 @@ type DownloadResult struct {
 	Error           error                  // Error if download failed
 	Metadata        map[string]interface{} // Final metadata
 	Timestamp       time.Time              // When download completed
+	ETag            string                 // ETag or Last-Modified date of the downloaded content ("" = not sent)
 }
 
 // DownloadStatus represents current status of an active download
@@ -130,9 +148,20 @@ func NewDownloadManager(config DownloadConfig) DownloadManager {
 		config.Timeout = 30 * time.Second
 	}
 
+	// Larger connection buffers mean fewer reads on fast links
+	var base http.RoundTripper
+	if config.ReadBufferSize > 0 {
+		if transport, ok := http.DefaultTransport.(*http.Transport); ok {
+			tuned := transport.Clone()
+			tuned.ReadBufferSize = config.ReadBufferSize
+			base = tuned
+		}
+	}
+
 	// Create HTTP client
 	httpClient := &http.Client{
-		Timeout: config.Timeout,
+		Timeout:   config.Timeout,
+		Transport: tracing.NewTransport(base),
 		CheckRedirect: func(req *http.Request, via []*http.Request) error {
 			// Limit redirects to prevent infinite loops
 			if len(via) >= 10 {
@@ -157,6 +186,22 @@ func NewDownloadManager(config DownloadConfig) DownloadManager {
 
 // Download performs a download with resume support and retry logic
 func (dm *downloadManagerImpl) Download(ctx context.Context, req DownloadRequest, progressCallback ProgressCallback) (*DownloadResult, error) {
+	ctx, span := tracing.Start(ctx, "download_file",
+		attribute.String("file.name", filepath.Base(req.Destination)),
+		attribute.Int64("file.size", req.FileSize))
+	result, err := dm.download(ctx, req, progressCallback)
+	if result != nil {
+		span.SetAttributes(
+			attribute.Int64("download.bytes", result.BytesDownloaded),
+			attribute.Bool("download.resumed", result.Resumed),
+			attribute.Int("download.retries", result.RetryCount))
+	}
+	tracing.End(span, err)
+	return result, err
+}
+
+// download runs the download attempts, retrying with backoff
+func (dm *downloadManagerImpl) download(ctx context.Context, req DownloadRequest, progressCallback ProgressCallback) (*DownloadResult, error) {
 	// Generate ID if not provided
 	if req.ID == "" {
 		req.ID = fmt.Sprintf("download_%d", time.Now().UnixNano())
@@ -175,8 +220,8 @@ func (dm *downloadManagerImpl) Download(ctx context.Context, req DownloadRequest
 			return result, nil
 		}
 
-		// Check if we should retry
-		if attempt >= dm.config.RetryAttempts {
+		// Check if we should retry; a page served instead of the file comes back on every attempt
+		if attempt >= dm.config.RetryAttempts || permanentContentError(err) {
 			// Final attempt failed
 			return &DownloadResult{
 				DownloadID:      req.ID,
@@ -189,20 +234,29 @@ func (dm *downloadManagerImpl) Download(ctx context.Context, req DownloadRequest
 				Error:           err,
 				Metadata:        req.Metadata,
 				Timestamp:       time.Now(),
+				ETag:            readResumeValidator(req.Destination),
 			}, err
 		}
 
 		// Wait before retry
-		select {
-		case <-time.After(dm.config.RetryDelay):
-		case <-ctx.Done():
-			return nil, ctx.Err()
+		if err := retry.Sleep(ctx, dm.retryPolicy().Delay(attempt)); err != nil {
+			return nil, err
 		}
 	}
 
 	return nil, fmt.Errorf("download failed after %d attempts", dm.config.RetryAttempts)
 }
 
+// retryPolicy returns the backoff policy between download attempts
+func (dm *downloadManagerImpl) retryPolicy() retry.Policy {
+	return retry.Policy{
+		MaxAttempts: dm.config.RetryAttempts + 1,
+		BaseDelay:   dm.config.RetryDelay,
+		Multiplier:  dm.config.RetryMultiplier,
+		MaxDelay:    dm.config.RetryMaxDelay,
+	}
+}
+
 // performDownload performs a single download attempt with resume support
 func (dm *downloadManagerImpl) performDownload(ctx context.Context, req DownloadRequest, startTime time.Time, progressCallback ProgressCallback) (*DownloadResult, error) {
 
@@ -217,6 +271,23 @@ func (dm *downloadManagerImpl) performDownload(ctx context.Context, req Download
 		}
 	}
 
+	// A partial download that already has all the requested bytes is complete
+	if req.MaxBytes > 0 && currentSize >= req.MaxBytes {
+		clearResumeValidator(req.Destination)
+		return &DownloadResult{
+			DownloadID:      req.ID,
+			BytesDownloaded: currentSize,
+			Resumed:         true,
+			Success:         true,
+			Metadata:        req.Metadata,
+			Timestamp:       time.Now(),
+		}, nil
+	}
+	totalSize := req.FileSize
+	if req.MaxBytes > 0 && (totalSize <= 0 || req.MaxBytes < totalSize) {
+		totalSize = req.MaxBytes
+	}
+
 	// Create directory if it doesn't exist
 	if err := os.MkdirAll(filepath.Dir(req.Destination), 0755); err != nil {
 		return nil, fmt.Errorf("failed to create destination directory: %w", err)
@@ -236,17 +307,29 @@ func (dm *downloadManagerImpl) performDownload(ctx context.Context, req Download
 		httpReq.Header.Set(key, value)
 	}
 
-	// Add Range header for resume if needed
-	if currentSize > 0 {
+	// Add Range header for resume and partial downloads if needed
+	if req.MaxBytes > 0 {
+		httpReq.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", currentSize, req.MaxBytes-1))
+	} else if currentSize > 0 {
 		httpReq.Header.Set("Range", fmt.Sprintf("bytes=%d-", currentSize))
 	}
 
+	// Only resume from the file version the partial content came from; a changed file is sent
+	// whole and the download starts over
+	validator := req.IfRange
+	if validator == "" {
+		validator = readResumeValidator(req.Destination)
+	}
+	if currentSize > 0 && validator != "" {
+		httpReq.Header.Set("If-Range", validator)
+	}
+
 	// Send progress update: downloading
 	if progressCallback != nil {
 		progressCallback(ProgressUpdate{
 			DownloadID:      req.ID,
 			BytesDownloaded: currentSize,
-			TotalBytes:      req.FileSize,
+			TotalBytes:      totalSize,
 			Speed:           0,
 			ETA:             0,
 			State:           DownloadStateDownloading,
@@ -264,15 +347,51 @@ func (dm *downloadManagerImpl) performDownload(ctx context.Context, req Download
 
 	// Check status code
 	if resp.StatusCode != 200 && resp.StatusCode != 206 {
-		return nil, fmt.Errorf("HTTP error: %d %s", resp.StatusCode, resp.Status)
+		return nil, &zoom.HTTPError{StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+
+	// Nothing is written for a web page or other content that is not the file
+	if err := checkContentType(resp, req.MediaOnly); err != nil {
+		return nil, err
 	}
 
 	// Validate partial content response
 	if currentSize > 0 && resp.StatusCode != 206 {
-		// Server doesn't support range requests, start over
+		// Server doesn't support range requests or the file changed, start over
 		currentSize = 0
 		resumed = false
 	}
+	if currentSize > 0 {
+		if start, ok := contentRangeStart(resp.Header.Get("Content-Range")); ok && start != currentSize {
+			// The bytes sent would not line up with the partial file; the next attempt starts over
+			os.Remove(req.Destination)
+			clearResumeValidator(req.Destination)
+			return nil, fmt.Errorf("server resumed at byte %d instead of %d", start, currentSize)
+		}
+	}
+
+	// Media downloaded from the start must begin like an MP4/M4A file, not a JSON error or a web page
+	body := io.Reader(resp.Body)
+	if req.MediaOnly && currentSize == 0 {
+		sniffer := bufio.NewReaderSize(resp.Body, MediaSniffLength)
+		head, err := sniffer.Peek(MediaSniffLength)
+		if err != nil && err != io.EOF {
+			return nil, fmt.Errorf("failed to read response body: %w", err)
+		}
+		if err := CheckMediaSignature(head); err != nil {
+			return nil, err
+		}
+		body = sniffer
+	}
+
+	// Mark the destination as partial until every byte is written; without a validator the
+	// content cannot be checked on resume, so an interrupted download is removed instead
+	etag := responseValidator(resp)
+	if etag == "" {
+		clearResumeValidator(req.Destination)
+	} else if err := writeResumeValidator(req.Destination, etag); err != nil {
+		return nil, err
+	}
 
 	// Open/create destination file
 	var file *os.File
@@ -293,6 +412,11 @@ func (dm *downloadManagerImpl) performDownload(ctx context.Context, req Download
 	}
 	defer file.Close()
 
+	// Servers that ignore the range send the whole file; stop reading after the requested bytes
+	if req.MaxBytes > 0 {
+		body = io.LimitReader(body, req.MaxBytes-currentSize)
+	}
+
 	// Download with progress tracking
 	downloadStartTime := time.Now()
 	lastProgressTime := downloadStartTime
@@ -309,7 +433,7 @@ func (dm *downloadManagerImpl) performDownload(ctx context.Context, req Download
 		}
 
 		// Read chunk
-		n, err := resp.Body.Read(buffer)
+		n, err := body.Read(buffer)
 		if err != nil && err != io.EOF {
 			return nil, fmt.Errorf("failed to read response body: %w", err)
 		}
@@ -336,15 +460,15 @@ func (dm *downloadManagerImpl) performDownload(ctx context.Context, req Download
 
 				// Calculate ETA
 				var eta time.Duration
-				if speed > 0 && req.FileSize > totalDownloaded {
-					eta = time.Duration(float64(req.FileSize-totalDownloaded)/speed) * time.Second
+				if speed > 0 && totalSize > totalDownloaded {
+					eta = time.Duration(float64(totalSize-totalDownloaded)/speed) * time.Second
 				}
 
 				// Send progress update
 				progressCallback(ProgressUpdate{
 					DownloadID:      req.ID,
 					BytesDownloaded: totalDownloaded,
-					TotalBytes:      req.FileSize,
+					TotalBytes:      totalSize,
 					Speed:           speed,
 					ETA:             eta,
 					State:           DownloadStateDownloading,
@@ -366,6 +490,7 @@ func (dm *downloadManagerImpl) performDownload(ctx context.Context, req Download
 	if err := file.Sync(); err != nil {
 		return nil, fmt.Errorf("failed to sync file: %w", err)
 	}
+	clearResumeValidator(req.Destination)
 
 	// Calculate final statistics
 	duration := time.Since(downloadStartTime)
@@ -376,7 +501,7 @@ func (dm *downloadManagerImpl) performDownload(ctx context.Context, req Download
 		progressCallback(ProgressUpdate{
 			DownloadID:      req.ID,
 			BytesDownloaded: totalDownloaded,
-			TotalBytes:      req.FileSize,
+			TotalBytes:      totalSize,
 			Speed:           0,
 			ETA:             0,
 			State:           DownloadStateCompleted,
@@ -396,5 +521,6 @@ func (dm *downloadManagerImpl) performDownload(ctx context.Context, req Download
 		Error:           nil,
 		Metadata:        req.Metadata,
 		Timestamp:       time.Now(),
+		ETag:            etag,
 	}, nil
 }
\ No newline at end of file
@@ -254,6 +254,71 @@ func TestRangeHeaderSupport(t *testing.T) {
 	}
 }
 
+// TestPartialDownload tests that MaxBytes limits a download to the leading bytes of the file
+func TestPartialDownload(t *testing.T) {
+	fileContent := strings.Repeat("0123456789", 100) // 1000 bytes
+
+	tests := []struct {
+		name        string
+		honorRange  bool
+		existing    string
+		wantRange   string
+		wantContent string
+	}{
+		{name: "server honors range", honorRange: true, wantRange: "bytes=0-299", wantContent: fileContent[:300]},
+		{name: "server ignores range", honorRange: false, wantRange: "bytes=0-299", wantContent: fileContent[:300]},
+		{name: "resume partial preview", honorRange: true, existing: fileContent[:100], wantRange: "bytes=100-299", wantContent: fileContent[:300]},
+		{name: "preview already complete", honorRange: true, existing: fileContent[:300], wantContent: fileContent[:300]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotRange string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotRange = r.Header.Get("Range")
+				if tt.honorRange {
+					http.ServeContent(w, r, "file.mp4", time.Time{}, strings.NewReader(fileContent))
+					return
+				}
+				w.Write([]byte(fileContent))
+			}))
+			defer server.Close()
+
+			destination := filepath.Join(t.TempDir(), "preview.mp4")
+			if tt.existing != "" {
+				if err := os.WriteFile(destination, []byte(tt.existing), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			manager := NewDownloadManager(DownloadConfig{ChunkSize: 64, RetryAttempts: 0})
+			result, err := manager.Download(context.Background(), DownloadRequest{
+				URL:         server.URL + "/file.mp4",
+				Destination: destination,
+				FileSize:    int64(len(fileContent)),
+				MaxBytes:    300,
+			}, nil)
+			if err != nil {
+				t.Fatalf("Download failed: %v", err)
+			}
+
+			if gotRange != tt.wantRange {
+				t.Errorf("Expected Range %q, got %q", tt.wantRange, gotRange)
+			}
+			if result.BytesDownloaded != 300 {
+				t.Errorf("Expected 300 bytes downloaded, got %d", result.BytesDownloaded)
+			}
+			content, err := os.ReadFile(destination)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != tt.wantContent {
+				t.Errorf("Expected the first 300 bytes, got %d bytes", len(content))
+			}
+		})
+	}
+}
+
 // TestSerialDownloads tests that multiple downloads work correctly (serially)
 func TestSerialDownloads(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
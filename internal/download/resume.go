@@ -0,0 +1,63 @@
+package download
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ResumeFilePath returns the file that marks destination as a partial download
+// It is hidden next to the destination and holds the ETag (or Last-Modified date) of the content
+// downloaded so far, so a resumed download only appends bytes of the same file version.
+func ResumeFilePath(destination string) string {
+	return filepath.Join(filepath.Dir(destination), "."+filepath.Base(destination)+".resume")
+}
+
+// IsPartial reports whether destination holds an interrupted download that Download resumes
+func IsPartial(destination string) bool {
+	_, err := os.Stat(ResumeFilePath(destination))
+	return err == nil
+}
+
+// readResumeValidator returns the validator saved for a partial download ("" = none)
+func readResumeValidator(destination string) string {
+	data, err := os.ReadFile(ResumeFilePath(destination))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// writeResumeValidator marks destination as partial, remembering the validator of its content
+func writeResumeValidator(destination, validator string) error {
+	if err := os.WriteFile(ResumeFilePath(destination), []byte(validator), 0644); err != nil {
+		return fmt.Errorf("failed to write resume file: %w", err)
+	}
+	return nil
+}
+
+// clearResumeValidator marks destination as complete
+func clearResumeValidator(destination string) {
+	_ = os.Remove(ResumeFilePath(destination))
+}
+
+// responseValidator returns the strong ETag of resp, or its Last-Modified date without one
+// Weak ETags cannot be used with If-Range.
+func responseValidator(resp *http.Response) string {
+	if etag := resp.Header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+		return etag
+	}
+	return resp.Header.Get("Last-Modified")
+}
+
+// contentRangeStart returns the first byte of a "bytes start-end/total" Content-Range header
+func contentRangeStart(header string) (int64, bool) {
+	var start, end int64
+	var total string
+	if _, err := fmt.Sscanf(header, "bytes %d-%d/%s", &start, &end, &total); err != nil {
+		return 0, false
+	}
+	return start, true
+}
@@ -0,0 +1,137 @@
+package download
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestResumeWithIfRange(t *testing.T) {
+	fileContent := strings.Repeat("0123456789", 100) // 1000 bytes
+
+	tests := []struct {
+		name        string
+		serverETag  string
+		wantRange   string
+		wantResumed bool
+	}{
+		{name: "same file version resumes", serverETag: `"v1"`, wantRange: "bytes=400-", wantResumed: true},
+		{name: "changed file starts over", serverETag: `"v2"`, wantRange: "bytes=400-", wantResumed: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotRange, gotIfRange string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotRange = r.Header.Get("Range")
+				gotIfRange = r.Header.Get("If-Range")
+				w.Header().Set("ETag", tt.serverETag)
+				http.ServeContent(w, r, "file.mp4", time.Time{}, strings.NewReader(fileContent))
+			}))
+			defer server.Close()
+
+			destination := filepath.Join(t.TempDir(), "meeting.mp4")
+			if err := os.WriteFile(destination, []byte(fileContent[:400]), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if err := writeResumeValidator(destination, `"v1"`); err != nil {
+				t.Fatal(err)
+			}
+
+			manager := NewDownloadManager(DownloadConfig{ChunkSize: 64, RetryAttempts: 0})
+			result, err := manager.Download(context.Background(), DownloadRequest{
+				URL:         server.URL + "/file.mp4",
+				Destination: destination,
+				FileSize:    int64(len(fileContent)),
+			}, nil)
+			if err != nil {
+				t.Fatalf("Download failed: %v", err)
+			}
+
+			if gotRange != tt.wantRange || gotIfRange != `"v1"` {
+				t.Errorf("Expected Range %q with If-Range \"v1\", got %q and %q", tt.wantRange, gotRange, gotIfRange)
+			}
+			if result.Resumed != tt.wantResumed {
+				t.Errorf("Expected resumed %v, got %v", tt.wantResumed, result.Resumed)
+			}
+			if result.ETag != tt.serverETag {
+				t.Errorf("Expected ETag %s, got %s", tt.serverETag, result.ETag)
+			}
+			content, err := os.ReadFile(destination)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != fileContent {
+				t.Errorf("Expected the whole file, got %d bytes", len(content))
+			}
+			if IsPartial(destination) {
+				t.Error("Expected the resume file removed once the download completed")
+			}
+		})
+	}
+}
+
+func TestInterruptedDownloadIsPartial(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Content-Length", "1000")
+		w.Write([]byte(strings.Repeat("x", 400)))
+		// Close the connection before the promised length is sent
+		if hijacker, ok := w.(http.Hijacker); ok {
+			w.(http.Flusher).Flush()
+			conn, _, _ := hijacker.Hijack()
+			conn.Close()
+		}
+	}))
+	defer server.Close()
+
+	destination := filepath.Join(t.TempDir(), "meeting.mp4")
+	manager := NewDownloadManager(DownloadConfig{ChunkSize: 64, RetryAttempts: 0})
+	if _, err := manager.Download(context.Background(), DownloadRequest{
+		URL:         server.URL + "/file.mp4",
+		Destination: destination,
+		FileSize:    1000,
+	}, nil); err == nil {
+		t.Fatal("Expected the cut-off download to fail")
+	}
+
+	if !IsPartial(destination) {
+		t.Fatal("Expected the partial download to be marked for resuming")
+	}
+	if validator := readResumeValidator(destination); validator != `"v1"` {
+		t.Errorf("Expected the ETag saved for resuming, got %q", validator)
+	}
+}
+
+func TestInterruptedDownloadWithoutValidatorIsNotPartial(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "1000")
+		w.Write([]byte(strings.Repeat("x", 400)))
+		// Close the connection before the promised length is sent
+		if hijacker, ok := w.(http.Hijacker); ok {
+			w.(http.Flusher).Flush()
+			conn, _, _ := hijacker.Hijack()
+			conn.Close()
+		}
+	}))
+	defer server.Close()
+
+	destination := filepath.Join(t.TempDir(), "meeting.mp4")
+	manager := NewDownloadManager(DownloadConfig{ChunkSize: 64, RetryAttempts: 0})
+	if _, err := manager.Download(context.Background(), DownloadRequest{
+		URL:         server.URL + "/file.mp4",
+		Destination: destination,
+		FileSize:    1000,
+	}, nil); err == nil {
+		t.Fatal("Expected the cut-off download to fail")
+	}
+
+	if IsPartial(destination) {
+		t.Error("Expected a download without ETag or Last-Modified not to be marked for resuming")
+	}
+}
@@ -3,10 +3,8 @@ package download
 
 import (
 	"context"
-	"crypto/sha256"
 	"encoding/json"
 	"fmt"
-	"io"
 	"os"
 	"path/filepath"
 	"time"
@@ -29,6 +27,8 @@ type BoxUploadInfo struct {
 	FileID            string    `json:"file_id,omitempty"`
 	FolderID          string    `json:"folder_id,omitempty"`
 	UploadDate        time.Time `json:"upload_date,omitempty"`
+	Verified          bool      `json:"verified,omitempty"` // The destination confirmed the stored file's size and checksum
+	Target            string    `json:"target,omitempty"`   // Destination and folder the file was uploaded to
 	UploadRetries     int       `json:"upload_retries"`
 	UploadError       string    `json:"upload_error,omitempty"`
 	LastUploadAttempt time.Time `json:"last_upload_attempt,omitempty"`
@@ -41,6 +41,7 @@ type DownloadEntry struct {
 	FileSize           int64                  `json:"file_size"`
 	DownloadedSize     int64                  `json:"downloaded_size"`
 	Checksum           string                 `json:"checksum,omitempty"`
+	ChecksumAlgorithm  ChecksumAlgorithm      `json:"checksum_algorithm,omitempty"`
 	LastAttempt        time.Time              `json:"last_attempt"`
 	MetadataDownloaded bool                   `json:"metadata_downloaded"`
 	RetryCount         int                    `json:"retry_count"`
@@ -51,6 +52,7 @@ type DownloadEntry struct {
 	VideoOwner         string                 `json:"video_owner,omitempty"`         // Zoom email of the video owner
 	BoxUser            string                 `json:"box_user,omitempty"`            // Box email for folder structure and permissions
 	Box                *BoxUploadInfo         `json:"box,omitempty"`
+	ETag               string                 `json:"etag,omitempty"` // Version of the downloaded content, checked when resuming
 }
 
 // StatusFile represents the structure of the status file
@@ -58,6 +60,18 @@ type StatusFile struct {
 	Version     string                    `json:"version"`
 	LastUpdated time.Time                 `json:"last_updated"`
 	Downloads   map[string]DownloadEntry  `json:"downloads"`
+	Passcodes   map[string]string         `json:"pending_passcodes,omitempty"` // Recording passcodes removed for a download, by meeting UUID
+}
+
+// PasscodeKeeper is implemented by status trackers that keep the passcode of a recording while
+// it is removed for a download, so a run that stops before setting it again can restore it later
+type PasscodeKeeper interface {
+	// SavePasscode records the passcode of a meeting's recordings before it is removed
+	SavePasscode(meetingUUID, passcode string) error
+	// ForgetPasscode removes the saved passcode once it is set again
+	ForgetPasscode(meetingUUID string) error
+	// PendingPasscodes returns the saved passcodes still to be set again, by meeting UUID
+	PendingPasscodes() map[string]string
 }
 
 // StatusTracker defines the interface for download status tracking
@@ -249,6 +263,33 @@ func (st *statusTrackerImpl) LoadFromFile() error {
 	return nil
 }
 
+// SavePasscode records a meeting's recording passcode and saves the status file
+func (st *statusTrackerImpl) SavePasscode(meetingUUID, passcode string) error {
+	if st.data.Passcodes == nil {
+		st.data.Passcodes = make(map[string]string)
+	}
+	st.data.Passcodes[meetingUUID] = passcode
+	return st.saveToFileUnsafe()
+}
+
+// ForgetPasscode removes a meeting's saved recording passcode and saves the status file
+func (st *statusTrackerImpl) ForgetPasscode(meetingUUID string) error {
+	if _, ok := st.data.Passcodes[meetingUUID]; !ok {
+		return nil
+	}
+	delete(st.data.Passcodes, meetingUUID)
+	return st.saveToFileUnsafe()
+}
+
+// PendingPasscodes returns a copy of the saved recording passcodes
+func (st *statusTrackerImpl) PendingPasscodes() map[string]string {
+	passcodes := make(map[string]string, len(st.data.Passcodes))
+	for meetingUUID, passcode := range st.data.Passcodes {
+		passcodes[meetingUUID] = passcode
+	}
+	return passcodes
+}
+
 // Close closes the status tracker
 func (st *statusTrackerImpl) Close() error {
 	// Final save before closing
@@ -257,28 +298,19 @@ func (st *statusTrackerImpl) Close() error {
 
 // CalculateFileChecksum calculates SHA256 checksum of a file
 func CalculateFileChecksum(filePath string) (string, error) {
-	file, err := os.Open(filePath)
-	if err != nil {
-		return "", fmt.Errorf("failed to open file: %w", err)
-	}
-	defer file.Close()
-	
-	hash := sha256.New()
-	if _, err := io.Copy(hash, file); err != nil {
-		return "", fmt.Errorf("failed to calculate hash: %w", err)
-	}
-	
-	return fmt.Sprintf("sha256:%x", hash.Sum(nil)), nil
+	return CalculateFileChecksumWith(filePath, ChecksumSHA256)
 }
 
 // VerifyFileChecksum verifies that a file matches the expected checksum
+// The algorithm is taken from the checksum prefix (sha256 if absent)
 func VerifyFileChecksum(filePath, expectedChecksum string) (bool, error) {
-	actualChecksum, err := CalculateFileChecksum(filePath)
+	algorithm, digest := SplitChecksum(expectedChecksum)
+	actualChecksum, err := CalculateFileChecksumWith(filePath, algorithm)
 	if err != nil {
 		return false, err
 	}
 	
-	return actualChecksum == expectedChecksum, nil
+	return actualChecksum == fmt.Sprintf("%s:%s", algorithm, digest), nil
 }
 
 // UpdateDownloadProgress is a convenience method to update download progress
@@ -420,9 +452,15 @@ func UpdateEntryFromProgress(entry DownloadEntry, progress ProgressUpdate) Downl
 
 // UpdateFromDownloadResult updates a download entry from a DownloadResult
 func UpdateEntryFromResult(entry DownloadEntry, result DownloadResult) DownloadEntry {
-	entry.DownloadedSize = result.BytesDownloaded
+	// A failed download keeps the size its progress reached, which is where it resumes from
+	if result.Success || result.BytesDownloaded > 0 {
+		entry.DownloadedSize = result.BytesDownloaded
+	}
 	entry.RetryCount = result.RetryCount
 	entry.CompletedTime = result.Timestamp
+	if result.ETag != "" {
+		entry.ETag = result.ETag
+	}
 	
 	if result.Success {
 		entry.Status = StatusCompleted
@@ -503,7 +541,8 @@ func NeedsChecksumVerification(entry DownloadEntry) bool {
 // StatusTrackerWithManager provides integration between StatusTracker and DownloadManager
 type StatusTrackerWithManager struct {
 	StatusTracker
-	manager DownloadManager
+	manager           DownloadManager
+	checksumAlgorithm ChecksumAlgorithm
 }
 
 // NewStatusTrackerWithManager creates a status tracker integrated with a download manager
@@ -514,11 +553,17 @@ func NewStatusTrackerWithManager(statusFile string, manager DownloadManager) (*S
 	}
 	
 	return &StatusTrackerWithManager{
-		StatusTracker: tracker,
-		manager:      manager,
+		StatusTracker:     tracker,
+		manager:           manager,
+		checksumAlgorithm: DefaultChecksumAlgorithm,
 	}, nil
 }
 
+// SetChecksumAlgorithm sets the algorithm used to checksum completed downloads
+func (stm *StatusTrackerWithManager) SetChecksumAlgorithm(algorithm ChecksumAlgorithm) {
+	stm.checksumAlgorithm = algorithm
+}
+
 // StartDownloadWithTracking starts a download and tracks its status
 func (stm *StatusTrackerWithManager) StartDownloadWithTracking(ctx context.Context, req DownloadRequest, progressCallback ProgressCallback) (*DownloadResult, error) {
 	// Create initial entry
@@ -532,6 +577,11 @@ func (stm *StatusTrackerWithManager) StartDownloadWithTracking(ctx context.Conte
 		entry = existing
 		entry.Status = StatusDownloading
 		entry.StartTime = time.Now().UTC()
+
+		// Resume only the file version the downloaded bytes came from
+		if GetResumeOffset(existing) > 0 && req.IfRange == "" {
+			req.IfRange = existing.ETag
+		}
 	}
 	
 	// Update status to downloading
@@ -556,6 +606,12 @@ func (stm *StatusTrackerWithManager) StartDownloadWithTracking(ctx context.Conte
 	// Update final status
 	if result != nil {
 		entry = UpdateEntryFromResult(entry, *result)
+		if result.Success && stm.checksumAlgorithm != "" {
+			if checksum, checksumErr := CalculateFileChecksumWith(req.Destination, stm.checksumAlgorithm); checksumErr == nil {
+				entry.Checksum = checksum
+				entry.ChecksumAlgorithm = stm.checksumAlgorithm
+			}
+		}
 	} else if err != nil {
 		entry.Status = StatusFailed
 		entry.Error = err.Error()
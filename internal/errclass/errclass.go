@@ -0,0 +1,162 @@
+// Package errclass sorts errors from the Zoom, Box and download packages into a few classes
+// that callers can branch on, e.g. to pick a process exit code
+package errclass
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"syscall"
+)
+
+// Class is the category of a failure
+type Class string
+
+const (
+	Unknown   Class = "unknown"
+	Auth      Class = "auth"       // Credentials were rejected or lack a scope
+	RateLimit Class = "rate_limit" // The API is throttling requests
+	NotFound  Class = "not_found"  // A user, folder, file or recording does not exist
+	Quota     Class = "quota"      // Storage quota, file size limit or local disk is exhausted
+	Network   Class = "network"    // The service could not be reached
+	Passcode  Class = "passcode"   // A recording download needs the recording's passcode
+)
+
+// Classified is implemented by errors that know their class
+type Classified interface {
+	error
+	ErrorClass() Class
+}
+
+// Retryable is implemented by errors that know whether retrying the failed request can succeed,
+// e.g. errors for 5xx responses
+type Retryable interface {
+	error
+	IsRetryable() bool
+}
+
+// IsTransient reports whether err is likely to go away on its own: rate limiting, network
+// failures and errors that report themselves as retryable
+func IsTransient(err error) bool {
+	switch Of(err) {
+	case RateLimit, Network:
+		return true
+	case Auth, NotFound, Quota, Passcode:
+		return false
+	}
+
+	var retryable Retryable
+	return errors.As(err, &retryable) && retryable.IsRetryable()
+}
+
+// Of returns the class of err: the class of the outermost Classified error it wraps, or a class
+// inferred from network and disk errors. Errors that cannot be classified are Unknown.
+func Of(err error) Class {
+	if err == nil {
+		return Unknown
+	}
+
+	var classified Classified
+	if errors.As(err, &classified) {
+		if class := classified.ErrorClass(); class != Unknown {
+			return class
+		}
+	}
+
+	if errors.Is(err, context.Canceled) {
+		return Unknown
+	}
+	if errors.Is(err, syscall.ENOSPC) || errors.Is(err, syscall.EDQUOT) {
+		return Quota
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return Network
+	}
+	if errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) {
+		return Network
+	}
+	return Unknown
+}
+
+// FromStatus returns the class of an HTTP error status
+func FromStatus(statusCode int) Class {
+	switch statusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return Auth
+	case http.StatusNotFound:
+		return NotFound
+	case http.StatusTooManyRequests:
+		return RateLimit
+	case http.StatusInsufficientStorage, http.StatusRequestEntityTooLarge:
+		return Quota
+	default:
+		return Unknown
+	}
+}
+
+// AuthError is a failure to authenticate or authorize
+type AuthError struct{ Err error }
+
+func (e *AuthError) Error() string     { return e.Err.Error() }
+func (e *AuthError) Unwrap() error     { return e.Err }
+func (e *AuthError) ErrorClass() Class { return Auth }
+
+// RateLimitError is a request rejected by rate limiting
+type RateLimitError struct{ Err error }
+
+func (e *RateLimitError) Error() string     { return e.Err.Error() }
+func (e *RateLimitError) Unwrap() error     { return e.Err }
+func (e *RateLimitError) ErrorClass() Class { return RateLimit }
+
+// NotFoundError is a request for something that does not exist
+type NotFoundError struct{ Err error }
+
+func (e *NotFoundError) Error() string     { return e.Err.Error() }
+func (e *NotFoundError) Unwrap() error     { return e.Err }
+func (e *NotFoundError) ErrorClass() Class { return NotFound }
+
+// QuotaError is a failure caused by an exhausted storage quota or size limit
+type QuotaError struct{ Err error }
+
+func (e *QuotaError) Error() string     { return e.Err.Error() }
+func (e *QuotaError) Unwrap() error     { return e.Err }
+func (e *QuotaError) ErrorClass() Class { return Quota }
+
+// NetworkError is a failure to reach a service
+type NetworkError struct{ Err error }
+
+func (e *NetworkError) Error() string     { return e.Err.Error() }
+func (e *NetworkError) Unwrap() error     { return e.Err }
+func (e *NetworkError) ErrorClass() Class { return Network }
+
+// PasscodeError is a download refused because the recording is passcode protected
+type PasscodeError struct{ Err error }
+
+func (e *PasscodeError) Error() string     { return e.Err.Error() }
+func (e *PasscodeError) Unwrap() error     { return e.Err }
+func (e *PasscodeError) ErrorClass() Class { return Passcode }
+
+// Wrap wraps err in the typed error for class; Unknown errors are returned unchanged
+func Wrap(class Class, err error) error {
+	if err == nil {
+		return nil
+	}
+	switch class {
+	case Auth:
+		return &AuthError{Err: err}
+	case RateLimit:
+		return &RateLimitError{Err: err}
+	case NotFound:
+		return &NotFoundError{Err: err}
+	case Quota:
+		return &QuotaError{Err: err}
+	case Network:
+		return &NetworkError{Err: err}
+	case Passcode:
+		return &PasscodeError{Err: err}
+	default:
+		return err
+	}
+}
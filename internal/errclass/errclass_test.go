@@ -0,0 +1,93 @@
+package errclass_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/curtbushko/zoom-to-box/internal/box"
+	"github.com/curtbushko/zoom-to-box/internal/errclass"
+	"github.com/curtbushko/zoom-to-box/internal/zoom"
+)
+
+func TestOf(t *testing.T) {
+	dialErr := &url.Error{Op: "Get", URL: "https://api.zoom.us", Err: &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}}
+
+	tests := []struct {
+		name string
+		err  error
+		want errclass.Class
+	}{
+		{name: "nil", err: nil, want: errclass.Unknown},
+		{name: "plain error", err: errors.New("boom"), want: errclass.Unknown},
+		{name: "zoom auth error", err: &zoom.AuthError{Type: "invalid_client", Reason: "bad secret"}, want: errclass.Auth},
+		{name: "zoom auth error from an unreachable endpoint", err: &zoom.AuthError{Type: "request_failed", Err: dialErr}, want: errclass.Network},
+		{name: "zoom API 404", err: &zoom.ZoomAPIError{Code: 1001, Message: "User does not exist", Status: 404}, want: errclass.NotFound},
+		{name: "zoom HTTP 429", err: &zoom.HTTPError{StatusCode: 429}, want: errclass.RateLimit},
+		{name: "zoom HTTP 500", err: &zoom.HTTPError{StatusCode: 500}, want: errclass.Unknown},
+		{name: "box storage limit", err: &box.BoxError{StatusCode: 403, Code: box.ErrorCodeStorageLimitExceeded}, want: errclass.Quota},
+		{name: "box forbidden", err: &box.BoxError{StatusCode: 403, Code: "access_denied_insufficient_permissions"}, want: errclass.Auth},
+		{name: "box rate limit", err: &box.BoxError{StatusCode: 429, Code: box.ErrorCodeRateLimitExceeded}, want: errclass.RateLimit},
+		{name: "box token refresh", err: &box.TokenRefreshError{Err: errors.New("invalid_grant")}, want: errclass.Auth},
+		{name: "wrapped box not found", err: fmt.Errorf("cannot access zoom folder: %w", &box.BoxError{StatusCode: 404, Code: box.ErrorCodeItemNotFound}), want: errclass.NotFound},
+		{name: "typed wrapper", err: fmt.Errorf("download failed: %w", errclass.Wrap(errclass.Auth, errors.New("HTTP error: 401"))), want: errclass.Auth},
+		{name: "connection refused", err: fmt.Errorf("HTTP request failed: %w", dialErr), want: errclass.Network},
+		{name: "disk full", err: fmt.Errorf("failed to write to file: %w", &os.PathError{Op: "write", Path: "a.mp4", Err: syscall.ENOSPC}), want: errclass.Quota},
+		{name: "cancelled", err: fmt.Errorf("HTTP request failed: %w", &url.Error{Op: "Get", URL: "https://zoom.us", Err: context.Canceled}), want: errclass.Unknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errclass.Of(tt.err); got != tt.want {
+				t.Errorf("Of(%v) = %s, want %s", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWrap(t *testing.T) {
+	cause := errors.New("HTTP error: 404 Not Found")
+
+	wrapped := errclass.Wrap(errclass.NotFound, cause)
+	var notFound *errclass.NotFoundError
+	if !errors.As(wrapped, &notFound) || !errors.Is(wrapped, cause) {
+		t.Errorf("Expected a NotFoundError wrapping the cause, got %T", wrapped)
+	}
+	if wrapped.Error() != cause.Error() {
+		t.Errorf("Expected the cause's message, got %q", wrapped.Error())
+	}
+
+	if errclass.Wrap(errclass.Unknown, cause) != cause {
+		t.Error("Expected unknown errors to be returned unchanged")
+	}
+	if errclass.Wrap(errclass.Auth, nil) != nil {
+		t.Error("Expected nil to stay nil")
+	}
+}
+
+func TestIsTransient(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{err: errors.New("boom"), want: false},
+		{err: fmt.Errorf("failed to get recordings: %w", &zoom.HTTPError{StatusCode: 503}), want: true},
+		{err: &zoom.ZoomAPIError{Code: 429, Status: 429}, want: true},
+		{err: &zoom.HTTPError{StatusCode: 404}, want: false},
+		{err: &box.BoxError{StatusCode: 500, Code: "internal_server_error", Retryable: true}, want: true},
+		{err: &box.BoxError{StatusCode: 401, Code: box.ErrorCodeUnauthorized, Retryable: true}, want: false},
+		{err: errclass.Wrap(errclass.Network, errors.New("connection reset")), want: true},
+		{err: errclass.Wrap(errclass.Passcode, errors.New("passcode page")), want: false},
+	}
+
+	for _, tt := range tests {
+		if got := errclass.IsTransient(tt.err); got != tt.want {
+			t.Errorf("IsTransient(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+// Package filelock takes advisory locks on files shared between zoom-to-box processes, such
+// as the active users file, so concurrent runs update them one at a time
+// The lock is held on a separate lock file: files replaced by rename get a new inode, which
+// would leave a lock on the old one useless.
+package filelock
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ErrLocked is returned by TryAcquire when another process holds the lock
+var ErrLocked = errors.New("lock is held by another process")
+
+// Lock is a held advisory lock
+type Lock struct {
+	file *os.File
+}
+
+// Acquire takes an exclusive lock on path, creating the file if needed, and waits while
+// another process holds it
+// The operating system releases the lock when the process exits, so a crashed run never
+// leaves it behind.
+func Acquire(path string) (*Lock, error) {
+	return acquire(path, lockFile)
+}
+
+// TryAcquire takes an exclusive lock on path like Acquire, but returns an error matching
+// ErrLocked instead of waiting while another process holds it
+func TryAcquire(path string) (*Lock, error) {
+	return acquire(path, tryLockFile)
+}
+
+// acquire opens path, creating it and its directory if needed, and locks it with lock
+func acquire(path string, lock func(*os.File) error) (*Lock, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create lock directory: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file %s: %w", path, err)
+	}
+	if err := lock(file); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+	}
+	return &Lock{file: file}, nil
+}
+
+// File returns the locked file, e.g. to record the owner in it
+// On Windows the locked region can only be read and written through this file.
+func (l *Lock) File() *os.File {
+	return l.file
+}
+
+// Release releases the lock; the lock file is left in place for the next process
+func (l *Lock) Release() error {
+	unlockErr := unlockFile(l.file)
+	closeErr := l.file.Close()
+	if unlockErr != nil {
+		return fmt.Errorf("failed to unlock %s: %w", l.file.Name(), unlockErr)
+	}
+	return closeErr
+}
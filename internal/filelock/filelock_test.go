@@ -0,0 +1,65 @@
+package filelock
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAcquire_WaitsForRelease(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "locks", "active_users.txt.lock")
+
+	lock, err := Acquire(path)
+	if err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+
+	acquired := make(chan *Lock)
+	go func() {
+		second, err := Acquire(path)
+		if err != nil {
+			t.Errorf("Second Acquire failed: %v", err)
+		}
+		acquired <- second
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("Expected the second Acquire to wait while the lock is held")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if err := lock.Release(); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+	select {
+	case second := <-acquired:
+		if second != nil {
+			second.Release()
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the second Acquire to succeed after release")
+	}
+}
+
+func TestTryAcquire(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run.lock")
+
+	lock, err := TryAcquire(path)
+	if err != nil {
+		t.Fatalf("TryAcquire failed: %v", err)
+	}
+	if _, err := TryAcquire(path); !errors.Is(err, ErrLocked) {
+		t.Fatalf("Expected ErrLocked while the lock is held, got %v", err)
+	}
+
+	if err := lock.Release(); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+	lock, err = TryAcquire(path)
+	if err != nil {
+		t.Fatalf("TryAcquire after release failed: %v", err)
+	}
+	lock.Release()
+}
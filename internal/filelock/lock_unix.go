@@ -0,0 +1,36 @@
+//go:build !windows
+
+package filelock
+
+import (
+	"os"
+	"syscall"
+)
+
+// lockFile waits for an exclusive flock on file
+func lockFile(file *os.File) error {
+	for {
+		err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX)
+		if err != syscall.EINTR {
+			return err
+		}
+	}
+}
+
+// tryLockFile takes an exclusive flock on file, or returns ErrLocked while another process holds it
+func tryLockFile(file *os.File) error {
+	for {
+		err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+		if err == syscall.EWOULDBLOCK {
+			return ErrLocked
+		}
+		if err != syscall.EINTR {
+			return err
+		}
+	}
+}
+
+// unlockFile releases the flock on file
+func unlockFile(file *os.File) error {
+	return syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+}
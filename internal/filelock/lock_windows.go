@@ -0,0 +1,30 @@
+package filelock
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// lockFile waits for an exclusive LockFileEx lock on the first byte of file
+func lockFile(file *os.File) error {
+	overlapped := new(windows.Overlapped)
+	return windows.LockFileEx(windows.Handle(file.Fd()), windows.LOCKFILE_EXCLUSIVE_LOCK, 0, 1, 0, overlapped)
+}
+
+// tryLockFile takes an exclusive LockFileEx lock on the first byte of file, or returns ErrLocked
+// while another process holds it
+func tryLockFile(file *os.File) error {
+	overlapped := new(windows.Overlapped)
+	err := windows.LockFileEx(windows.Handle(file.Fd()), windows.LOCKFILE_EXCLUSIVE_LOCK|windows.LOCKFILE_FAIL_IMMEDIATELY, 0, 1, 0, overlapped)
+	if err == windows.ERROR_LOCK_VIOLATION {
+		return ErrLocked
+	}
+	return err
+}
+
+// unlockFile releases the lock on file
+func unlockFile(file *os.File) error {
+	overlapped := new(windows.Overlapped)
+	return windows.UnlockFileEx(windows.Handle(file.Fd()), 0, 1, 0, overlapped)
+}
@@ -35,12 +35,16 @@ type FileSanitizerOptions struct {
 	
 	// DefaultTopic is used when the topic is empty or only contains invalid characters (default: "untitled")
 	DefaultTopic string
+
+	// WindowsSafe mangles names Windows reserves for devices, such as "con" or "lpt1"
+	WindowsSafe bool
 }
 
 // fileSanitizer is the concrete implementation of FileSanitizer
 type fileSanitizer struct {
 	maxTopicLength int
 	defaultTopic   string
+	windows        bool
 	
 	// Compiled regex for performance
 	invalidCharsRegex    *regexp.Regexp
@@ -63,6 +67,7 @@ func NewFileSanitizer(options FileSanitizerOptions) FileSanitizer {
 	return &fileSanitizer{
 		maxTopicLength:       maxLength,
 		defaultTopic:        defaultTopic,
+		windows:             options.WindowsSafe,
 		invalidCharsRegex:   regexp.MustCompile(`[<>:"/\\|?*]`),
 		multipleSpacesRegex: regexp.MustCompile(`\s+`),
 		nonAlphaNumRegex:    regexp.MustCompile(`[^a-zA-Z0-9\s]`),
@@ -133,9 +138,18 @@ func (fs *fileSanitizer) SanitizeTopic(topic string) string {
 		dashed = strings.TrimRight(dashed, "-")
 	}
 	
+	if fs.windows {
+		dashed = WindowsSafeName(dashed)
+	}
+	
 	return dashed
 }
 
+// windowsSafe reports whether names are made safe for Windows
+func (fs *fileSanitizer) windowsSafe() bool {
+	return fs.windows
+}
+
 // normalizeUnicode removes diacritics and converts unicode to ASCII equivalents
 func (fs *fileSanitizer) normalizeUnicode(s string) string {
 	// Create a transformer that removes diacritics
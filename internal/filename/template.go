@@ -0,0 +1,190 @@
+package filename
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/curtbushko/zoom-to-box/internal/zoom"
+)
+
+// DefaultTemplate reproduces the built-in <topic>-<HHMM> naming
+const DefaultTemplate = "{{.Topic}}-{{.Time}}"
+
+// TemplateData holds the fields available to filename templates
+// String fields are sanitized before the template runs, so each segment is filesystem safe.
+type TemplateData struct {
+	Topic         string    // Sanitized meeting topic, e.g. "weekly-standup"
+	Date          string    // Meeting date, e.g. "2024-01-15"
+	Time          string    // Meeting start time, e.g. "0930"
+	Year          string    // e.g. "2024"
+	Month         string    // e.g. "01"
+	Day           string    // e.g. "15"
+	HostEmail     string    // Recording host, e.g. "jane.smith@example.com"
+	RecordingType string    // Zoom recording type, e.g. "shared_screen_with_speaker_view"
+	FileType      string    // Zoom file type, lowercase, e.g. "mp4"
+	MeetingID     string    // Zoom meeting UUID
+	FileID        string    // Zoom recording file ID
+	Start         time.Time // Meeting start time for custom formats, e.g. {{.Start.Format "150405"}}
+}
+
+// Template renders recording filenames (without extension) from a text/template
+// Names that collide within a directory get a -2, -3, ... suffix.
+type Template struct {
+	tmpl      *template.Template
+	sanitizer FileSanitizer
+	typed     bool // The template includes .RecordingType
+
+	mu     sync.Mutex
+	owners map[string]string // Claimed "<dir>/<name>" -> owner key
+	claims map[string]string // Owner key + "|" + dir -> claimed name
+}
+
+// unsafeChars matches characters that are not allowed in file names on common filesystems
+var unsafeChars = regexp.MustCompile(`[<>:"/\\|?*\x00-\x1f]+`)
+
+// NewTemplate parses a filename template; an empty text uses DefaultTemplate
+func NewTemplate(text string, sanitizer FileSanitizer) (*Template, error) {
+	if text == "" {
+		text = DefaultTemplate
+	}
+	tmpl, err := template.New("filename").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filename template: %w", err)
+	}
+
+	t := &Template{
+		tmpl:      tmpl,
+		sanitizer: sanitizer,
+		typed:     strings.Contains(text, ".RecordingType"),
+		owners:    make(map[string]string),
+		claims:    make(map[string]string),
+	}
+
+	// Catch unknown fields now rather than on the first recording
+	if _, err := t.Render(TemplateData{Topic: "topic", Start: time.Now()}); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// NewTemplateData builds the template fields for a recording file
+func NewTemplateData(sanitizer FileSanitizer, topic string, start time.Time, hostEmail, recordingType, fileType, meetingID, fileID string) TemplateData {
+	return TemplateData{
+		Topic:         sanitizer.SanitizeTopic(topic),
+		Date:          start.Format("2006-01-02"),
+		Time:          sanitizer.FormatTime(start),
+		Year:          start.Format("2006"),
+		Month:         start.Format("01"),
+		Day:           start.Format("02"),
+		HostEmail:     SanitizeSegment(hostEmail),
+		RecordingType: SanitizeSegment(recordingType),
+		FileType:      SanitizeSegment(strings.ToLower(fileType)),
+		MeetingID:     SanitizeSegment(meetingID),
+		FileID:        SanitizeSegment(fileID),
+		Start:         start,
+	}
+}
+
+// Render executes the template; the result is made safe as a single path segment
+func (t *Template) Render(data TemplateData) (string, error) {
+	var buf bytes.Buffer
+	if err := t.tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("invalid filename template: %w", err)
+	}
+
+	name := SanitizeSegment(buf.String())
+	if name == "" {
+		name = t.sanitizer.SanitizeTopic("")
+	}
+	if safe, ok := t.sanitizer.(windowsSafe); ok && safe.windowsSafe() {
+		name = WindowsSafeName(name)
+	}
+	return name, nil
+}
+
+// Qualifier returns the suffix that tells file apart from the recording's other files of the same
+// file type, which render to the same name: none when it is the only one, its recording type
+// unless the template already includes it, e.g. "-gallery_view", and a -2, -3, ... sequence in
+// recording start order for files that share the recording type too. Unlike Unique's suffixes,
+// it depends only on the recording, so every run and the Box existence checks agree on the name.
+func (t *Template) Qualifier(files []zoom.RecordingFile, file zoom.RecordingFile) string {
+	var sameType, sameKind []zoom.RecordingFile
+	for _, other := range files {
+		if !strings.EqualFold(other.FileType, file.FileType) {
+			continue
+		}
+		sameType = append(sameType, other)
+		if other.RecordingType == file.RecordingType {
+			sameKind = append(sameKind, other)
+		}
+	}
+	if len(sameType) <= 1 {
+		return ""
+	}
+
+	var parts []string
+	if !t.typed {
+		if recordingType := SanitizeSegment(file.RecordingType); recordingType != "" {
+			parts = append(parts, recordingType)
+		}
+	}
+	if len(sameKind) > 1 {
+		sort.SliceStable(sameKind, func(i, j int) bool {
+			if !sameKind[i].RecordingStart.Equal(sameKind[j].RecordingStart) {
+				return sameKind[i].RecordingStart.Before(sameKind[j].RecordingStart)
+			}
+			return sameKind[i].ID < sameKind[j].ID
+		})
+		for i, other := range sameKind {
+			if other.ID == file.ID && i > 0 {
+				parts = append(parts, fmt.Sprint(i+1))
+			}
+		}
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "-" + strings.Join(parts, "-")
+}
+
+// Unique returns name+ext, suffixed with -2, -3, ... when another owner already claimed it in dir
+// owner identifies the recording file (e.g. meeting UUID and file ID), so asking again for the
+// same file returns the same name. Names are only unique within this Template's lifetime.
+func (t *Template) Unique(dir, name, ext, owner string) string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	claimKey := owner + "|" + dir
+	if claimed, ok := t.claims[claimKey]; ok {
+		return claimed
+	}
+
+	candidate := name + ext
+	for i := 2; ; i++ {
+		if existing, taken := t.owners[dir+"/"+candidate]; !taken || existing == owner {
+			break
+		}
+		candidate = fmt.Sprintf("%s-%d%s", name, i, ext)
+	}
+
+	t.owners[dir+"/"+candidate] = owner
+	t.claims[claimKey] = candidate
+	return candidate
+}
+
+// SanitizeSegment replaces characters that are unsafe in file or folder names and whitespace with dashes
+func SanitizeSegment(value string) string {
+	value = unsafeChars.ReplaceAllString(value, "-")
+	value = strings.Join(strings.Fields(value), "-")
+	for strings.Contains(value, "--") {
+		value = strings.ReplaceAll(value, "--", "-")
+	}
+	// Leading dots would hide the file; trailing dots and dashes are dropped by some filesystems
+	return strings.Trim(value, "-. ")
+}
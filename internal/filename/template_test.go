@@ -0,0 +1,112 @@
+package filename
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/curtbushko/zoom-to-box/internal/zoom"
+)
+
+func TestTemplate_DefaultMatchesBuiltInNaming(t *testing.T) {
+	sanitizer := NewFileSanitizer(FileSanitizerOptions{})
+	tmpl, err := NewTemplate("", sanitizer)
+	if err != nil {
+		t.Fatalf("NewTemplate failed: %v", err)
+	}
+
+	start := time.Date(2024, 1, 15, 9, 30, 0, 0, time.UTC)
+	data := NewTemplateData(sanitizer, "Weekly Team Meeting", start, "jane@example.com", "shared_screen", "MP4", "uuid", "file1")
+	name, err := tmpl.Render(data)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if want := sanitizer.SanitizeTopic("Weekly Team Meeting") + "-" + sanitizer.FormatTime(start); name != want {
+		t.Errorf("Expected %q, got %q", want, name)
+	}
+}
+
+func TestTemplate_SanitizesEachSegment(t *testing.T) {
+	sanitizer := NewFileSanitizer(FileSanitizerOptions{})
+	tmpl, err := NewTemplate("{{.Date}}_{{.Topic}}_{{.HostEmail}}_{{.RecordingType}}", sanitizer)
+	if err != nil {
+		t.Fatalf("NewTemplate failed: %v", err)
+	}
+
+	start := time.Date(2024, 1, 15, 9, 30, 0, 0, time.UTC)
+	data := NewTemplateData(sanitizer, "Q4 Planning: Budget", start, "jane/smith@example.com", "audio only", "M4A", "uuid", "file1")
+	name, err := tmpl.Render(data)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if want := "2024-01-15_q4-planning-budget_jane-smith@example.com_audio-only"; name != want {
+		t.Errorf("Expected %q, got %q", want, name)
+	}
+	if strings.ContainsAny(name, `/\:`) {
+		t.Errorf("Expected a single safe path segment, got %q", name)
+	}
+}
+
+func TestNewTemplate_RejectsInvalidTemplates(t *testing.T) {
+	sanitizer := NewFileSanitizer(FileSanitizerOptions{})
+	for _, text := range []string{"{{.Topic", "{{.Unknown}}"} {
+		if _, err := NewTemplate(text, sanitizer); err == nil {
+			t.Errorf("Expected NewTemplate(%q) to fail", text)
+		}
+	}
+}
+
+func TestTemplate_UniqueSuffixesCollisions(t *testing.T) {
+	tmpl, err := NewTemplate("", NewFileSanitizer(FileSanitizerOptions{}))
+	if err != nil {
+		t.Fatalf("NewTemplate failed: %v", err)
+	}
+
+	first := tmpl.Unique("/out/2024/01/15", "standup-0930", ".mp4", "uuid-a/file1")
+	second := tmpl.Unique("/out/2024/01/15", "standup-0930", ".mp4", "uuid-b/file1")
+	third := tmpl.Unique("/out/2024/01/15", "standup-0930", ".mp4", "uuid-c/file1")
+	other := tmpl.Unique("/out/2024/01/16", "standup-0930", ".mp4", "uuid-b/file1")
+
+	if first != "standup-0930.mp4" || second != "standup-0930-2.mp4" || third != "standup-0930-3.mp4" {
+		t.Errorf("Expected suffixed names, got %q, %q, %q", first, second, third)
+	}
+	if other != "standup-0930.mp4" {
+		t.Errorf("Expected no suffix in another folder, got %q", other)
+	}
+	if again := tmpl.Unique("/out/2024/01/15", "standup-0930", ".mp4", "uuid-b/file1"); again != second {
+		t.Errorf("Expected the same file to keep its name %q, got %q", second, again)
+	}
+}
+
+func TestTemplate_Qualifier(t *testing.T) {
+	sanitizer := NewFileSanitizer(FileSanitizerOptions{})
+	start := time.Date(2024, 1, 15, 9, 30, 0, 0, time.UTC)
+	files := []zoom.RecordingFile{
+		{ID: "gallery", FileType: "MP4", RecordingType: "gallery_view", RecordingStart: start},
+		{ID: "speaker", FileType: "MP4", RecordingType: "speaker_view", RecordingStart: start},
+		{ID: "screen-b", FileType: "MP4", RecordingType: "shared_screen", RecordingStart: start.Add(time.Hour)},
+		{ID: "screen-a", FileType: "MP4", RecordingType: "shared_screen", RecordingStart: start},
+		{ID: "audio", FileType: "M4A", RecordingType: "audio_only", RecordingStart: start},
+	}
+
+	tmpl, err := NewTemplate("", sanitizer)
+	if err != nil {
+		t.Fatalf("NewTemplate failed: %v", err)
+	}
+	for i, want := range []string{"-gallery_view", "-speaker_view", "-shared_screen-2", "-shared_screen", ""} {
+		if got := tmpl.Qualifier(files, files[i]); got != want {
+			t.Errorf("Qualifier(%s) = %q, want %q", files[i].ID, got, want)
+		}
+	}
+
+	// A template with the recording type only needs the sequence
+	typed, err := NewTemplate("{{.Topic}}-{{.RecordingType}}", sanitizer)
+	if err != nil {
+		t.Fatalf("NewTemplate failed: %v", err)
+	}
+	for i, want := range []string{"", "", "-2", "", ""} {
+		if got := typed.Qualifier(files, files[i]); got != want {
+			t.Errorf("typed Qualifier(%s) = %q, want %q", files[i].ID, got, want)
+		}
+	}
+}
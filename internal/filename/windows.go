@@ -0,0 +1,41 @@
+package filename
+
+import (
+	"strings"
+)
+
+// windowsReservedNames are device names Windows refuses as file or folder names, with or
+// without an extension
+var windowsReservedNames = map[string]bool{
+	"con": true, "prn": true, "aux": true, "nul": true,
+	"com1": true, "com2": true, "com3": true, "com4": true, "com5": true, "com6": true, "com7": true, "com8": true, "com9": true,
+	"lpt1": true, "lpt2": true, "lpt3": true, "lpt4": true, "lpt5": true, "lpt6": true, "lpt7": true, "lpt8": true, "lpt9": true,
+}
+
+// IsWindowsReserved reports whether Windows refuses name as a file or folder name, e.g. "CON"
+// or "nul.mp4"
+func IsWindowsReserved(name string) bool {
+	stem, _, _ := strings.Cut(name, ".")
+	return windowsReservedNames[strings.ToLower(strings.TrimRight(stem, " "))]
+}
+
+// WindowsSafeName returns name usable on Windows: trailing dots and spaces, which Windows
+// drops, are removed and reserved device names get a "_" after the stem, e.g. "con.mp4"
+// becomes "con_.mp4"
+func WindowsSafeName(name string) string {
+	name = strings.TrimRight(name, ". ")
+	if !IsWindowsReserved(name) {
+		return name
+	}
+	stem, rest, hasExt := strings.Cut(name, ".")
+	stem = strings.TrimRight(stem, " ") + "_"
+	if hasExt {
+		return stem + "." + rest
+	}
+	return stem
+}
+
+// windowsSafe is implemented by sanitizers created with FileSanitizerOptions.WindowsSafe
+type windowsSafe interface {
+	windowsSafe() bool
+}
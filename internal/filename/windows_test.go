@@ -0,0 +1,52 @@
+package filename
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWindowsSafeName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"con", "con_"},
+		{"CON", "CON_"},
+		{"nul.mp4", "nul_.mp4"},
+		{"lpt1.tar.gz", "lpt1_.tar.gz"},
+		{"com9 ", "com9_"},
+		{"console", "console"},
+		{"con-0930", "con-0930"},
+		{"meeting. . ", "meeting"},
+		{"com10", "com10"},
+	}
+	for _, tt := range tests {
+		if got := WindowsSafeName(tt.name); got != tt.want {
+			t.Errorf("WindowsSafeName(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestWindowsSafeSanitizer(t *testing.T) {
+	sanitizer := NewFileSanitizer(FileSanitizerOptions{WindowsSafe: true})
+	if got := sanitizer.SanitizeTopic("Aux"); got != "aux_" {
+		t.Errorf("SanitizeTopic(\"Aux\") = %q, want \"aux_\"", got)
+	}
+
+	template, err := NewTemplate("{{.Topic}}", sanitizer)
+	if err != nil {
+		t.Fatalf("NewTemplate failed: %v", err)
+	}
+	name, err := template.Render(TemplateData{Topic: "prn", Start: time.Now()})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if name != "prn_" {
+		t.Errorf("Render = %q, want \"prn_\"", name)
+	}
+
+	// Without the option, names are left alone
+	if got := NewFileSanitizer(FileSanitizerOptions{}).SanitizeTopic("Aux"); got != "aux" {
+		t.Errorf("SanitizeTopic(\"Aux\") = %q, want \"aux\"", got)
+	}
+}
@@ -0,0 +1,170 @@
+// Package hooks runs user-configured commands and webhooks after uploads, so downstream
+// systems (search indexing, ticketing) can react without changes to zoom-to-box
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/curtbushko/zoom-to-box/internal/logging"
+)
+
+// DefaultTimeout bounds each hook command and webhook post
+const DefaultTimeout = 30 * time.Second
+
+// Event types
+const (
+	EventUpload = "upload" // A recording file was uploaded
+	EventUser   = "user"   // A user's recordings were processed
+)
+
+// Event describes what a hook is fired for; its fields are available to command templates,
+// e.g. "./notify.sh {{.User}} {{.File}}", and are posted as JSON to the hook URL
+type Event struct {
+	Event   string `json:"event"`    // EventUpload or EventUser
+	User    string `json:"user"`     // Zoom email
+	BoxUser string `json:"box_user"` // Destination user email
+
+	// Upload events
+	File        string `json:"file,omitempty"`         // File name
+	Path        string `json:"path,omitempty"`         // Local path ("" when streamed)
+	Folder      string `json:"folder,omitempty"`       // Folder in the destination, e.g. "2024/01/15"
+	FileID      string `json:"file_id,omitempty"`      // ID of the file in the destination
+	Size        int64  `json:"size,omitempty"`         // Bytes uploaded
+	Topic       string `json:"topic,omitempty"`        // Meeting topic
+	MeetingUUID string `json:"meeting_uuid,omitempty"` // Zoom meeting UUID
+	SharedLink  string `json:"shared_link,omitempty"`  // Shared link created for the file ("" = none)
+
+	// User events
+	Downloaded int `json:"downloaded,omitempty"`
+	Uploaded   int `json:"uploaded,omitempty"`
+	Errors     int `json:"errors,omitempty"`
+}
+
+// Config lists the hooks to fire
+type Config struct {
+	Commands []string      // Command templates; each whitespace-separated argument is rendered on its own
+	URL      string        // Receives each event as a JSON POST ("" = none)
+	Timeout  time.Duration // Limit for each command and post (0 = DefaultTimeout)
+}
+
+// Runner fires the configured hooks
+// A nil *Runner fires nothing.
+type Runner struct {
+	commands [][]*template.Template
+	url      string
+	timeout  time.Duration
+	client   *http.Client
+}
+
+// New parses the command templates of config; it returns nil when no hooks are configured
+// Commands are split into arguments before rendering and run without a shell, so values
+// such as meeting topics cannot inject extra arguments or commands.
+func New(config Config) (*Runner, error) {
+	if len(config.Commands) == 0 && config.URL == "" {
+		return nil, nil
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultTimeout
+	}
+
+	r := &Runner{url: config.URL, timeout: config.Timeout, client: &http.Client{Timeout: config.Timeout}}
+	for i, command := range config.Commands {
+		fields := strings.Fields(command)
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("hook command %d is empty", i+1)
+		}
+		args := make([]*template.Template, len(fields))
+		for j, field := range fields {
+			tmpl, err := template.New("hook").Option("missingkey=error").Parse(field)
+			if err != nil {
+				return nil, fmt.Errorf("invalid hook command %q: %w", command, err)
+			}
+			args[j] = tmpl
+		}
+		r.commands = append(r.commands, args)
+	}
+	return r, nil
+}
+
+// Fire runs every hook for event
+// Failures are logged only: a hook problem never fails the upload or user it reports on.
+func (r *Runner) Fire(ctx context.Context, event Event) {
+	if r == nil {
+		return
+	}
+	for _, command := range r.commands {
+		if err := r.run(ctx, command, event); err != nil {
+			logging.Warn("Hook command failed for %s event of %s: %v", event.Event, event.User, err)
+		}
+	}
+	if r.url != "" {
+		if err := r.post(ctx, event); err != nil {
+			logging.Warn("Hook URL failed for %s event of %s: %v", event.Event, event.User, err)
+		}
+	}
+}
+
+// run renders command for event and runs it; the event is also passed as JSON on stdin
+func (r *Runner) run(ctx context.Context, command []*template.Template, event Event) error {
+	args := make([]string, len(command))
+	for i, tmpl := range command {
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, event); err != nil {
+			return fmt.Errorf("failed to render hook command: %w", err)
+		}
+		args[i] = buf.String()
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode hook event: %w", err)
+	}
+
+	runCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), r.timeout)
+	defer cancel()
+	cmd := exec.CommandContext(runCtx, args[0], args[1:]...)
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.Env = append(os.Environ(), "ZOOM_TO_BOX_EVENT="+event.Event)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s: %w: %s", args[0], err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// post sends event as JSON to the hook URL
+func (r *Runner) post(ctx context.Context, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode hook event: %w", err)
+	}
+
+	postCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), r.timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(postCtx, http.MethodPost, r.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create hook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post hook event: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("hook URL returned %s: %s", resp.Status, strings.TrimSpace(string(detail)))
+	}
+	return nil
+}
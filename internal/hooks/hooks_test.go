@@ -0,0 +1,64 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestRunner_Fire(t *testing.T) {
+	if _, err := exec.LookPath("tee"); err != nil {
+		t.Skip("tee is not available")
+	}
+	dir := t.TempDir()
+
+	var posted Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&posted); err != nil {
+			t.Errorf("Invalid hook payload: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	// The event arrives on stdin; tee saves it to a file named from the template
+	runner, err := New(Config{
+		Commands: []string{"tee " + filepath.Join(dir, "{{.Event}}-{{.FileID}}.json")},
+		URL:      server.URL,
+	})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	event := Event{Event: EventUpload, User: "jane@example.com", File: "standup; rm -rf.mp4", FileID: "42", Size: 9}
+	runner.Fire(context.Background(), event)
+
+	data, err := os.ReadFile(filepath.Join(dir, "upload-42.json"))
+	if err != nil {
+		t.Fatalf("Expected the hook command to run: %v", err)
+	}
+	var received Event
+	if err := json.Unmarshal(data, &received); err != nil || received != event {
+		t.Errorf("Expected the event on stdin, got %s (%v)", data, err)
+	}
+	if posted != event {
+		t.Errorf("Expected the event posted to the hook URL, got %+v", posted)
+	}
+}
+
+func TestNew(t *testing.T) {
+	if runner, err := New(Config{}); runner != nil || err != nil {
+		t.Errorf("Expected no runner without hooks, got %v, %v", runner, err)
+	}
+	if _, err := New(Config{Commands: []string{"./notify.sh {{.User"}}); err == nil {
+		t.Error("Expected an error for an invalid template")
+	}
+
+	// A nil runner fires nothing
+	var runner *Runner
+	runner.Fire(context.Background(), Event{Event: EventUser})
+}
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/curtbushko/zoom-to-box/internal/config"
+	"github.com/curtbushko/zoom-to-box/internal/timefmt"
 )
 
 // LogLevel represents the severity level of a log entry
@@ -108,7 +109,11 @@ type loggerImpl struct {
 	level      LogLevel
 	jsonFormat bool
 	writers    []io.Writer
-	fileHandle *os.File
+	fileHandle io.Closer
+	jsonSchema config.LogJSONSchemaConfig
+
+	console      int      // Index of the console in writers (-1 = no console)
+	consoleLevel LogLevel // Lowest level printed to the console
 }
 
 // LogEntry represents a structured log entry
@@ -131,15 +136,25 @@ func NewLogger(config config.LoggingConfig) (Logger, error) {
 		level:      level,
 		jsonFormat: config.JSONFormat,
 		writers:    []io.Writer{},
+		jsonSchema: config.JSONSchema,
+		console:    -1,
 	}
 	
 	// Add console writer if enabled
 	if config.Console {
-		logger.writers = append(logger.writers, os.Stdout)
+		logger.console, logger.consoleLevel = len(logger.writers), consoleLevel
+		logger.writers = append(logger.writers, consoleOutput)
 	}
 	
 	// Add file writer if configured
-	if config.File != "" {
+	if config.File != "" && config.Rotation.Enabled() {
+		file, err := OpenRotatingFile(config.File, config.Rotation)
+		if err != nil {
+			return nil, err
+		}
+		logger.fileHandle = file
+		logger.writers = append(logger.writers, file)
+	} else if config.File != "" {
 		file, err := os.OpenFile(config.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
 		if err != nil {
 			return nil, fmt.Errorf("failed to open log file %s: %w", config.File, err)
@@ -174,7 +189,7 @@ func (l *loggerImpl) log(level LogLevel, ctx context.Context, format string, arg
 	}
 
 	entry := LogEntry{
-		Timestamp: time.Now().UTC(),
+		Timestamp: timefmt.Now(),
 		Level:     strings.ToUpper(level.String()),
 		Message:   fmt.Sprintf(format, args...),
 	}
@@ -186,18 +201,23 @@ func (l *loggerImpl) log(level LogLevel, ctx context.Context, format string, arg
 		}
 	}
 
-	l.writeEntry(entry)
+	l.writeEntry(level, entry)
 }
 
 // writeEntry writes a log entry to all configured writers
-func (l *loggerImpl) writeEntry(entry LogEntry) {
+func (l *loggerImpl) writeEntry(level LogLevel, entry LogEntry) {
 	var output string
 	
 	if l.jsonFormat {
-		data, _ := json.Marshal(entry)
+		entryMap := l.jsonEntry(entry)
+		if entry.RequestID != "" {
+			_, _, _, requestIDKey := l.jsonSchema.Keys()
+			entryMap[requestIDKey] = entry.RequestID
+		}
+		data, _ := json.Marshal(entryMap)
 		output = string(data) + "\n"
 	} else {
-		timestamp := entry.Timestamp.Format("2006-01-02T15:04:05Z")
+		timestamp := timefmt.Format(entry.Timestamp)
 		if entry.RequestID != "" {
 			output = fmt.Sprintf("%s [%s] [%s] %s\n", timestamp, entry.Level, entry.RequestID, entry.Message)
 		} else {
@@ -205,7 +225,30 @@ func (l *loggerImpl) writeEntry(entry LogEntry) {
 		}
 	}
 	
-	for _, writer := range l.writers {
+	l.write(level, output)
+}
+
+// jsonEntry returns the standard fields of entry under the configured JSON schema keys, with
+// the static fields
+func (l *loggerImpl) jsonEntry(entry LogEntry) map[string]interface{} {
+	timestampKey, levelKey, messageKey, _ := l.jsonSchema.Keys()
+	entryMap := make(map[string]interface{}, len(l.jsonSchema.StaticFields)+3)
+	for key, value := range l.jsonSchema.StaticFields {
+		entryMap[key] = value
+	}
+	entryMap[timestampKey] = entry.Timestamp
+	entryMap[levelKey] = entry.Level
+	entryMap[messageKey] = entry.Message
+	return entryMap
+}
+
+// write writes output to all configured writers, skipping the console for levels below its level
+func (l *loggerImpl) write(level LogLevel, output string) {
+	output = Redact(output)
+	for i, writer := range l.writers {
+		if i == l.console && level < l.consoleLevel {
+			continue
+		}
 		writer.Write([]byte(output))
 	}
 }
@@ -217,7 +260,7 @@ func (l *loggerImpl) writeStructuredEntry(level LogLevel, message string, fields
 	}
 
 	entry := LogEntry{
-		Timestamp: time.Now().UTC(),
+		Timestamp: timefmt.Now(),
 		Level:     strings.ToUpper(level.String()),
 		Message:   message,
 		Fields:    fields,
@@ -227,12 +270,7 @@ func (l *loggerImpl) writeStructuredEntry(level LogLevel, message string, fields
 	
 	if l.jsonFormat {
 		// Flatten the fields into the entry for JSON format
-		entryMap := map[string]interface{}{
-			"timestamp": entry.Timestamp,
-			"level":     entry.Level,
-			"message":   entry.Message,
-		}
-		
+		entryMap := l.jsonEntry(entry)
 		for key, value := range fields {
 			entryMap[key] = value
 		}
@@ -241,7 +279,7 @@ func (l *loggerImpl) writeStructuredEntry(level LogLevel, message string, fields
 		output = string(data) + "\n"
 	} else {
 		// For text format, include key fields in the message
-		timestamp := entry.Timestamp.Format("2006-01-02T15:04:05Z")
+		timestamp := timefmt.Format(entry.Timestamp)
 		fieldStr := ""
 		if len(fields) > 0 {
 			var pairs []string
@@ -253,9 +291,7 @@ func (l *loggerImpl) writeStructuredEntry(level LogLevel, message string, fields
 		output = fmt.Sprintf("%s [%s] %s%s\n", timestamp, entry.Level, message, fieldStr)
 	}
 	
-	for _, writer := range l.writers {
-		writer.Write([]byte(output))
-	}
+	l.write(level, output)
 }
 
 // Debug logs a debug message
@@ -339,7 +375,7 @@ func (l *loggerImpl) LogPerformance(metrics PerformanceMetrics) {
 func (l *loggerImpl) LogAPIRequest(request APIRequest) {
 	// Set timestamp if not provided
 	if request.Timestamp.IsZero() {
-		request.Timestamp = time.Now().UTC()
+		request.Timestamp = timefmt.Now()
 	}
 	
 	fields := map[string]interface{}{
@@ -379,7 +415,7 @@ func (l *loggerImpl) LogAPIRequest(request APIRequest) {
 func (l *loggerImpl) LogAPIResponse(response APIResponse) {
 	// Set timestamp if not provided
 	if response.Timestamp.IsZero() {
-		response.Timestamp = time.Now().UTC()
+		response.Timestamp = timefmt.Now()
 	}
 	
 	fields := map[string]interface{}{
@@ -425,6 +461,7 @@ func (l *loggerImpl) SetLevel(level LogLevel) {
 // SetOutput sets the output writer (mainly for testing)
 func (l *loggerImpl) SetOutput(w io.Writer) {
 	l.writers = []io.Writer{w}
+	l.console = -1
 }
 
 // Close closes the logger and any open file handles
@@ -438,6 +475,24 @@ func (l *loggerImpl) Close() error {
 // Global logger instance for package-level convenience functions
 var defaultLogger Logger
 
+// consoleOutput receives console log output from loggers created after it is set
+var consoleOutput io.Writer = os.Stdout
+
+// SetConsoleOutput replaces stdout as the console writer for loggers created afterwards,
+// e.g. so log lines are printed above interactive progress bars
+func SetConsoleOutput(w io.Writer) {
+	consoleOutput = w
+}
+
+// consoleLevel is the lowest level loggers created after it is set print to the console
+var consoleLevel = DebugLevel
+
+// SetConsoleLevel limits the console output of loggers created afterwards to level and above,
+// e.g. ErrorLevel for --quiet; the log file still receives every line of the configured level
+func SetConsoleLevel(level LogLevel) {
+	consoleLevel = level
+}
+
 // SetDefaultLogger sets the global default logger
 func SetDefaultLogger(logger Logger) {
 	defaultLogger = logger
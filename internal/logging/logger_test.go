@@ -266,6 +266,50 @@ func TestJSONLogging(t *testing.T) {
 	}
 }
 
+func TestJSONSchema(t *testing.T) {
+	var buffer bytes.Buffer
+	logger, err := NewLogger(config.LoggingConfig{
+		Level:      "info",
+		Console:    true,
+		JSONFormat: true,
+		JSONSchema: config.LogJSONSchemaConfig{
+			TimestampKey: "@timestamp",
+			LevelKey:     "log.level",
+			RequestIDKey: "trace.id",
+			StaticFields: map[string]string{"service.name": "zoom-to-box"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	logger.SetOutput(&buffer)
+
+	logger.InfoWithContext(WithRequestID(context.Background(), "req-1"), "Test message")
+	logger.LogUserAction("download", "user@example.com", nil)
+
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 log lines, got %d: %s", len(lines), buffer.String())
+	}
+	for _, line := range lines {
+		var logEntry map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &logEntry); err != nil {
+			t.Fatalf("Failed to parse JSON log: %v", err)
+		}
+		for _, field := range []string{"@timestamp", "log.level", "message", "service.name"} {
+			if _, exists := logEntry[field]; !exists {
+				t.Errorf("Missing field %s in %s", field, line)
+			}
+		}
+		if _, exists := logEntry["timestamp"]; exists {
+			t.Errorf("Default timestamp key should be replaced in %s", line)
+		}
+	}
+	if !strings.Contains(lines[0], `"trace.id":"req-1"`) {
+		t.Errorf("Expected request ID under trace.id, got %s", lines[0])
+	}
+}
+
 func TestFileLogging(t *testing.T) {
 	tempDir := t.TempDir()
 	logFile := filepath.Join(tempDir, "test.log")
@@ -680,4 +724,60 @@ func TestPackageLevelContextualLogging(t *testing.T) {
 	if !strings.Contains(output, "Test contextual message") {
 		t.Error("Expected to find test message in output")
 	}
-}
\ No newline at end of file
+}
+func TestSetConsoleOutput(t *testing.T) {
+	var buffer bytes.Buffer
+	SetConsoleOutput(&buffer)
+	defer SetConsoleOutput(os.Stdout)
+
+	logger, err := NewLogger(config.LoggingConfig{Level: "info", Console: true})
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	logger.Info("routed to the console writer")
+	if !strings.Contains(buffer.String(), "routed to the console writer") {
+		t.Errorf("Expected console output in the replacement writer, got %q", buffer.String())
+	}
+}
+
+func TestRedaction(t *testing.T) {
+	RegisterSecret("s3cr3t-client-value")
+	RegisterSecret("short")
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"registered secret", "secret is s3cr3t-client-value", "secret is [REDACTED]"},
+		{"short values are not registered", "short answer", "short answer"},
+		{"bearer token", "Authorization: Bearer eyJhbGciOi.abc-123", "Authorization: Bearer [REDACTED]"},
+		{"json token field", `{"access_token":"abc123","expires_in":3600}`, `{"access_token":"[REDACTED]","expires_in":3600}`},
+		{"query parameter", "POST /oauth/token?refresh_token=xyz789&grant_type=refresh", "POST /oauth/token?refresh_token=[REDACTED]&grant_type=refresh"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Redact(tt.input); got != tt.expected {
+				t.Errorf("Redact(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+
+	t.Run("log output is redacted", func(t *testing.T) {
+		var buffer bytes.Buffer
+		logger, err := NewLogger(config.LoggingConfig{Level: "info", Console: true, JSONFormat: true})
+		if err != nil {
+			t.Fatalf("Failed to create logger: %v", err)
+		}
+		logger.SetOutput(&buffer)
+
+		logger.Info("token request failed: Bearer abc.def.ghi (secret s3cr3t-client-value)")
+
+		output := buffer.String()
+		if strings.Contains(output, "abc.def.ghi") || strings.Contains(output, "s3cr3t-client-value") {
+			t.Errorf("Expected secrets to be redacted, got: %s", output)
+		}
+	})
+}
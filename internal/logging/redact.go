@@ -0,0 +1,65 @@
+package logging
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// redactedText replaces secrets in log output
+const redactedText = "[REDACTED]"
+
+// minSecretLength keeps short values (e.g. "true", port numbers) from being redacted everywhere
+const minSecretLength = 8
+
+// maxSecrets bounds the registered secrets; the oldest are forgotten first as tokens rotate
+const maxSecrets = 64
+
+// tokenPatterns match credentials that appear in log output without being registered,
+// e.g. Authorization headers and token fields echoed in API error bodies
+var tokenPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)(bearer\s+)[A-Za-z0-9\-._~+/]+=*`),
+	regexp.MustCompile(`(?i)("(?:access_token|refresh_token|client_secret|password|token)"\s*:\s*")[^"]*`),
+	regexp.MustCompile(`(?i)((?:access_token|refresh_token|client_secret|password)=)[^&\s"]+`),
+}
+
+var (
+	secretsMu sync.RWMutex
+	secrets   []string
+)
+
+// RegisterSecret makes Redact, and so every log line, replace value wherever it appears
+// Values shorter than 8 characters are ignored.
+func RegisterSecret(value string) {
+	if len(value) < minSecretLength {
+		return
+	}
+
+	secretsMu.Lock()
+	defer secretsMu.Unlock()
+	for _, existing := range secrets {
+		if existing == value {
+			return
+		}
+	}
+	secrets = append(secrets, value)
+	if len(secrets) > maxSecrets {
+		secrets = secrets[len(secrets)-maxSecrets:]
+	}
+}
+
+// Redact replaces registered secrets and anything that looks like a token in s
+func Redact(s string) string {
+	secretsMu.RLock()
+	for _, secret := range secrets {
+		if strings.Contains(s, secret) {
+			s = strings.ReplaceAll(s, secret, redactedText)
+		}
+	}
+	secretsMu.RUnlock()
+
+	for _, pattern := range tokenPatterns {
+		s = pattern.ReplaceAllString(s, "${1}"+redactedText)
+	}
+	return s
+}
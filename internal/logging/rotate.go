@@ -0,0 +1,232 @@
+package logging
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/curtbushko/zoom-to-box/internal/config"
+)
+
+// rotatedTimeFormat is the timestamp in rotated file names, e.g. zoom-to-box-20240115-103000.000.log
+const rotatedTimeFormat = "20060102-150405.000"
+
+// RotatingFile is a log file that is renamed and replaced by a new file when it reaches its size
+// limit or a new rotation interval starts. Rotated files are compressed and pruned in the
+// background; Close waits for that to finish.
+type RotatingFile struct {
+	path     string
+	rotation config.LogRotationConfig
+
+	mu        sync.Mutex
+	file      *os.File
+	size      int64
+	lastWrite time.Time
+
+	background sync.WaitGroup
+	housekeep  sync.Mutex // Serializes compression and pruning of rotated files
+}
+
+// OpenRotatingFile opens path for appending, rotating it first when it was last written in an
+// earlier rotation interval
+func OpenRotatingFile(path string, rotation config.LogRotationConfig) (*RotatingFile, error) {
+	r := &RotatingFile{path: path, rotation: rotation}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	if r.size > 0 && r.intervalElapsed(time.Now()) {
+		if err := r.rotate(time.Now()); err != nil {
+			r.file.Close()
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// Write appends p to the log file, rotating it first when p would exceed the size limit or the
+// rotation interval has passed
+func (r *RotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return 0, os.ErrClosed
+	}
+	now := time.Now()
+	maxSize := int64(r.rotation.MaxSizeMB) * 1024 * 1024
+	if r.size > 0 && (r.intervalElapsed(now) || (maxSize > 0 && r.size+int64(len(p)) > maxSize)) {
+		if err := r.rotate(now); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	r.lastWrite = now
+	return n, err
+}
+
+// Close closes the log file and waits for rotated files to be compressed and pruned
+func (r *RotatingFile) Close() error {
+	r.mu.Lock()
+	var err error
+	if r.file != nil {
+		err = r.file.Close()
+		r.file = nil
+	}
+	r.mu.Unlock()
+
+	r.background.Wait()
+	return err
+}
+
+// open opens the log file and records its size and last write time
+func (r *RotatingFile) open() error {
+	file, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file %s: %w", r.path, err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to stat log file %s: %w", r.path, err)
+	}
+	r.file, r.size, r.lastWrite = file, info.Size(), info.ModTime()
+	return nil
+}
+
+// intervalElapsed reports whether now falls in a later rotation interval than the last write;
+// intervals are aligned to UTC, so "24h" rotates at midnight UTC
+func (r *RotatingFile) intervalElapsed(now time.Time) bool {
+	interval := r.rotation.Interval
+	return interval > 0 && !now.Truncate(interval).Equal(r.lastWrite.Truncate(interval))
+}
+
+// rotate renames the log file to its timestamped name and opens a new one
+func (r *RotatingFile) rotate(now time.Time) error {
+	if err := r.file.Close(); err != nil {
+		return fmt.Errorf("failed to close log file %s: %w", r.path, err)
+	}
+	r.file = nil
+
+	rotated := r.rotatedName(now)
+	for exists(rotated) || exists(rotated+".gz") {
+		now = now.Add(time.Millisecond)
+		rotated = r.rotatedName(now)
+	}
+	if err := os.Rename(r.path, rotated); err != nil {
+		// Keep logging to the current file rather than losing lines
+		if openErr := r.open(); openErr != nil {
+			return openErr
+		}
+		return fmt.Errorf("failed to rotate log file %s: %w", r.path, err)
+	}
+	if err := r.open(); err != nil {
+		return err
+	}
+
+	r.background.Add(1)
+	go func() {
+		defer r.background.Done()
+		r.housekeep.Lock()
+		defer r.housekeep.Unlock()
+		if r.rotation.Compress {
+			if err := compressFile(rotated); err != nil {
+				fmt.Fprintf(os.Stderr, "failed to compress rotated log file: %v\n", err)
+			}
+		}
+		r.prune(time.Now())
+	}()
+	return nil
+}
+
+// rotatedName returns the name the log file is rotated to at t
+func (r *RotatingFile) rotatedName(t time.Time) string {
+	ext := filepath.Ext(r.path)
+	return strings.TrimSuffix(r.path, ext) + "-" + t.UTC().Format(rotatedTimeFormat) + ext
+}
+
+// prune removes rotated files beyond MaxBackups and older than MaxAge
+func (r *RotatingFile) prune(now time.Time) {
+	if r.rotation.MaxBackups <= 0 && r.rotation.MaxAge <= 0 {
+		return
+	}
+
+	type backup struct {
+		path    string
+		rotated time.Time
+	}
+	ext := filepath.Ext(r.path)
+	prefix := filepath.Base(strings.TrimSuffix(r.path, ext)) + "-"
+	dir := filepath.Dir(r.path)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	var backups []backup
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), ".gz")
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ext) {
+			continue
+		}
+		stamp := strings.TrimSuffix(strings.TrimPrefix(name, prefix), ext)
+		rotated, err := time.Parse(rotatedTimeFormat, stamp)
+		if err != nil {
+			continue
+		}
+		backups = append(backups, backup{path: filepath.Join(dir, entry.Name()), rotated: rotated})
+	}
+
+	sort.Slice(backups, func(i, j int) bool { return backups[i].rotated.After(backups[j].rotated) })
+	for i, b := range backups {
+		tooMany := r.rotation.MaxBackups > 0 && i >= r.rotation.MaxBackups
+		tooOld := r.rotation.MaxAge > 0 && now.Sub(b.rotated) > r.rotation.MaxAge
+		if tooMany || tooOld {
+			os.Remove(b.path)
+		}
+	}
+}
+
+// compressFile gzips path to path.gz and removes path
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	tmp := path + ".gz.tmp"
+	dst, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(dst)
+	_, err = io.Copy(gz, src)
+	if closeErr := gz.Close(); err == nil {
+		err = closeErr
+	}
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp, path+".gz")
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to compress %s: %w", path, err)
+	}
+	src.Close()
+	return os.Remove(path)
+}
+
+// exists reports whether path exists
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,139 @@
+package logging
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/curtbushko/zoom-to-box/internal/config"
+)
+
+func TestRotatingFile(t *testing.T) {
+	line := strings.Repeat("x", 1023) + "\n"
+
+	t.Run("rotates on size and prunes backups", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "app.log")
+		file, err := OpenRotatingFile(path, config.LogRotationConfig{MaxSizeMB: 1, MaxBackups: 2})
+		if err != nil {
+			t.Fatalf("OpenRotatingFile failed: %v", err)
+		}
+		// 1024 lines fill exactly 1 MB, so every 1024 lines start a new file
+		for i := 0; i < 4*1024+1; i++ {
+			if _, err := file.Write([]byte(line)); err != nil {
+				t.Fatalf("Write failed: %v", err)
+			}
+		}
+		if err := file.Close(); err != nil {
+			t.Fatalf("Close failed: %v", err)
+		}
+
+		backups, _ := filepath.Glob(filepath.Join(dir, "app-*.log"))
+		if len(backups) != 2 {
+			t.Errorf("Expected 2 backups, got %v", backups)
+		}
+		for _, backup := range backups {
+			if info, err := os.Stat(backup); err != nil || info.Size() != 1024*1024 {
+				t.Errorf("Expected %s to hold 1 MB, got %v (%v)", backup, info.Size(), err)
+			}
+		}
+		if info, err := os.Stat(path); err != nil || info.Size() != int64(len(line)) {
+			t.Errorf("Expected current log to hold one line, got %v", err)
+		}
+	})
+
+	t.Run("compresses rotated files", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "app.log")
+		file, err := OpenRotatingFile(path, config.LogRotationConfig{MaxSizeMB: 1, Compress: true})
+		if err != nil {
+			t.Fatalf("OpenRotatingFile failed: %v", err)
+		}
+		for i := 0; i < 1024+1; i++ {
+			file.Write([]byte(line))
+		}
+		file.Close()
+
+		compressed, _ := filepath.Glob(filepath.Join(dir, "app-*.log.gz"))
+		if len(compressed) != 1 {
+			t.Fatalf("Expected 1 compressed backup, got %v", compressed)
+		}
+		if plain, _ := filepath.Glob(filepath.Join(dir, "app-*.log")); len(plain) != 0 {
+			t.Errorf("Expected uncompressed backups to be removed, got %v", plain)
+		}
+		f, err := os.Open(compressed[0])
+		if err != nil {
+			t.Fatalf("Open failed: %v", err)
+		}
+		defer f.Close()
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			t.Fatalf("gzip.NewReader failed: %v", err)
+		}
+		data, err := io.ReadAll(gz)
+		if err != nil || len(data) != 1024*1024 {
+			t.Errorf("Expected 1 MB of log lines, got %d bytes (%v)", len(data), err)
+		}
+	})
+
+	t.Run("rotates a file from an earlier interval on open", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "app.log")
+		if err := os.WriteFile(path, []byte(line), 0644); err != nil {
+			t.Fatal(err)
+		}
+		yesterday := time.Now().Add(-24 * time.Hour)
+		if err := os.Chtimes(path, yesterday, yesterday); err != nil {
+			t.Fatal(err)
+		}
+
+		file, err := OpenRotatingFile(path, config.LogRotationConfig{Interval: 24 * time.Hour})
+		if err != nil {
+			t.Fatalf("OpenRotatingFile failed: %v", err)
+		}
+		file.Write([]byte("today\n"))
+		file.Close()
+
+		if backups, _ := filepath.Glob(filepath.Join(dir, "app-*.log")); len(backups) != 1 {
+			t.Errorf("Expected 1 backup, got %v", backups)
+		}
+		if data, _ := os.ReadFile(path); string(data) != "today\n" {
+			t.Errorf("Expected only today's line in the log, got %q", data)
+		}
+	})
+
+	t.Run("removes backups older than max age", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "app.log")
+		old := filepath.Join(dir, "app-"+time.Now().Add(-48*time.Hour).UTC().Format(rotatedTimeFormat)+".log.gz")
+		unrelated := filepath.Join(dir, "app-notes.log")
+		for _, p := range []string{old, unrelated} {
+			if err := os.WriteFile(p, []byte("old"), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		file, err := OpenRotatingFile(path, config.LogRotationConfig{MaxSizeMB: 1, MaxAge: 24 * time.Hour})
+		if err != nil {
+			t.Fatalf("OpenRotatingFile failed: %v", err)
+		}
+		for i := 0; i < 1024+1; i++ {
+			file.Write([]byte(line))
+		}
+		file.Close()
+
+		if _, err := os.Stat(old); !os.IsNotExist(err) {
+			t.Errorf("Expected %s to be removed", old)
+		}
+		if _, err := os.Stat(unrelated); err != nil {
+			t.Errorf("Expected files that are not backups to be kept: %v", err)
+		}
+		if backups, _ := filepath.Glob(filepath.Join(dir, "app-2*.log")); len(backups) != 1 {
+			t.Errorf("Expected the new backup to be kept, got %v", backups)
+		}
+	})
+}
@@ -0,0 +1,129 @@
+// Package network configures how HTTP clients reach Zoom, Box and the other services
+// Every client in the tool builds on http.DefaultTransport (through tracing.NewTransport or a
+// zero http.Client), so Configure installs the proxy and TLS settings there once at startup.
+package network
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// baseTransport is Go's default transport, which each configuration starts from
+var baseTransport = http.DefaultTransport.(*http.Transport).Clone()
+
+// Options holds the network settings of the config file
+type Options struct {
+	ProxyURL   string // Proxy for every request ("" = HTTP_PROXY, HTTPS_PROXY and NO_PROXY)
+	CACertFile string // PEM bundle trusted in addition to the system CAs ("" = system CAs only)
+
+	InsecureSkipVerify bool   // Accept any server certificate
+	MinTLSVersion      string // Oldest TLS version accepted: 1.0, 1.1, 1.2 or 1.3 ("" = 1.2)
+}
+
+// tlsVersions maps configured TLS versions to their crypto/tls constants
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// Configure makes http.DefaultTransport, and so every HTTP client created afterwards, use opts
+func Configure(opts Options) error {
+	transport, err := NewTransport(opts)
+	if err != nil {
+		return err
+	}
+	http.DefaultTransport = transport
+	return nil
+}
+
+// NewTransport returns a transport that connects as configured by opts
+func NewTransport(opts Options) (*http.Transport, error) {
+	transport := baseTransport.Clone()
+
+	proxy, err := proxyFunc(opts.ProxyURL)
+	if err != nil {
+		return nil, err
+	}
+	transport.Proxy = proxy
+
+	tlsConfig, err := newTLSConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+	transport.TLSClientConfig = tlsConfig
+	return transport, nil
+}
+
+// newTLSConfig returns the TLS settings of opts
+func newTLSConfig(opts Options) (*tls.Config, error) {
+	minVersion := "1.2"
+	if opts.MinTLSVersion != "" {
+		minVersion = opts.MinTLSVersion
+	}
+	version, ok := tlsVersions[minVersion]
+	if !ok {
+		return nil, fmt.Errorf("unsupported minimum TLS version %q", opts.MinTLSVersion)
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:         version,
+		InsecureSkipVerify: opts.InsecureSkipVerify, // Opt-in; the CLI warns about it on every start
+	}
+	if opts.CACertFile != "" {
+		pool, err := loadCACerts(opts.CACertFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.RootCAs = pool
+	}
+	return tlsConfig, nil
+}
+
+// proxyFunc returns the proxy selection for proxyURL
+// An explicit proxy still skips the hosts listed in NO_PROXY.
+func proxyFunc(proxyURL string) (func(*http.Request) (*url.URL, error), error) {
+	if proxyURL == "" {
+		return http.ProxyFromEnvironment, nil
+	}
+	if _, err := url.Parse(proxyURL); err != nil {
+		return nil, fmt.Errorf("invalid proxy URL: %w", err)
+	}
+
+	noProxy := os.Getenv("NO_PROXY")
+	if noProxy == "" {
+		noProxy = os.Getenv("no_proxy")
+	}
+	selectProxy := (&httpproxy.Config{
+		HTTPProxy:  proxyURL,
+		HTTPSProxy: proxyURL,
+		NoProxy:    noProxy,
+	}).ProxyFunc()
+	return func(req *http.Request) (*url.URL, error) {
+		return selectProxy(req.URL)
+	}, nil
+}
+
+// loadCACerts returns the system CAs together with the certificates in caCertFile
+func loadCACerts(caCertFile string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(caCertFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA certificate file: %w", err)
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no PEM certificates found in %s", caCertFile)
+	}
+	return pool, nil
+}
@@ -0,0 +1,118 @@
+package network
+
+import (
+	"crypto/tls"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNewTransportProxy(t *testing.T) {
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = append(proxied, r.URL.String())
+		fmt.Fprint(w, "via proxy")
+	}))
+	defer proxy.Close()
+
+	t.Setenv("NO_PROXY", "skip.example.com")
+	transport, err := NewTransport(Options{ProxyURL: proxy.URL})
+	if err != nil {
+		t.Fatalf("NewTransport failed: %v", err)
+	}
+	client := &http.Client{Transport: transport}
+
+	resp, err := client.Get("http://api.zoom.example.com/v2/users")
+	if err != nil {
+		t.Fatalf("Request through proxy failed: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != "via proxy" || len(proxied) != 1 || proxied[0] != "http://api.zoom.example.com/v2/users" {
+		t.Errorf("Expected the request sent to the proxy, got %q and %v", body, proxied)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "http://skip.example.com/", nil)
+	if proxyURL, err := transport.Proxy(req); err != nil || proxyURL != nil {
+		t.Errorf("Expected NO_PROXY hosts to connect directly, got %v, %v", proxyURL, err)
+	}
+}
+
+func TestNewTransportCACertFile(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "ok")
+	}))
+	defer server.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caFile, certPEM, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	untrusted, err := NewTransport(Options{})
+	if err != nil {
+		t.Fatalf("NewTransport failed: %v", err)
+	}
+	if _, err := (&http.Client{Transport: untrusted}).Get(server.URL); err == nil {
+		t.Error("Expected the test server's certificate to be rejected without the CA file")
+	}
+
+	trusted, err := NewTransport(Options{CACertFile: caFile})
+	if err != nil {
+		t.Fatalf("NewTransport failed: %v", err)
+	}
+	resp, err := (&http.Client{Transport: trusted}).Get(server.URL)
+	if err != nil {
+		t.Fatalf("Expected the CA file to be trusted, got %v", err)
+	}
+	resp.Body.Close()
+
+	notPEM := filepath.Join(t.TempDir(), "ca.txt")
+	if err := os.WriteFile(notPEM, []byte("not a certificate"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewTransport(Options{CACertFile: notPEM}); err == nil || !strings.Contains(err.Error(), "no PEM certificates") {
+		t.Errorf("Expected a file without certificates to fail, got %v", err)
+	}
+}
+
+func TestNewTransportTLSSettings(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "ok")
+	}))
+	server.TLS = &tls.Config{MaxVersion: tls.VersionTLS12}
+	server.StartTLS()
+	defer server.Close()
+
+	insecure, err := NewTransport(Options{InsecureSkipVerify: true})
+	if err != nil {
+		t.Fatalf("NewTransport failed: %v", err)
+	}
+	resp, err := (&http.Client{Transport: insecure}).Get(server.URL)
+	if err != nil {
+		t.Fatalf("Expected an unverified certificate to be accepted, got %v", err)
+	}
+	resp.Body.Close()
+
+	tls13, err := NewTransport(Options{InsecureSkipVerify: true, MinTLSVersion: "1.3"})
+	if err != nil {
+		t.Fatalf("NewTransport failed: %v", err)
+	}
+	if tls13.TLSClientConfig.MinVersion != tls.VersionTLS13 {
+		t.Errorf("Expected TLS 1.3 as the minimum, got %x", tls13.TLSClientConfig.MinVersion)
+	}
+	if _, err := (&http.Client{Transport: tls13}).Get(server.URL); err == nil {
+		t.Error("Expected a TLS 1.2 server to be rejected with min_tls_version 1.3")
+	}
+
+	if _, err := NewTransport(Options{MinTLSVersion: "1.4"}); err == nil {
+		t.Error("Expected an unknown TLS version to fail")
+	}
+}
@@ -0,0 +1,274 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/curtbushko/zoom-to-box/internal/timefmt"
+)
+
+// DefaultEmailSubject is the subject template used when EmailConfig.Subject is empty
+const DefaultEmailSubject = "zoom-to-box run {{.Status}}{{if .Reason}}: {{.Reason}}{{end}}"
+
+// Report formats that can be attached to emails
+const (
+	AttachJSON = "json" // The run report as written by --report-file
+	AttachCSV  = "csv"  // One row per recording file
+)
+
+// EmailConfig holds the SMTP settings for emailed run summaries
+type EmailConfig struct {
+	Host     string
+	Port     int // Default: 587
+	Username string
+	Password string
+
+	ImplicitTLS bool // Use TLS from the first byte (usually port 465); otherwise STARTTLS is used when offered
+
+	From    string
+	To      []string
+	Subject string   // text/template with .Status, .Reason, .Date and .FailedUsers (default: DefaultEmailSubject)
+	Attach  []string // Report formats to attach: AttachJSON, AttachCSV
+}
+
+// subjectData is what the subject template can reference
+type subjectData struct {
+	Status      Status
+	Reason      string
+	Date        string // YYYY-MM-DD in time.timezone
+	FailedUsers int
+}
+
+// emailNotifier sends run summaries by SMTP
+type emailNotifier struct {
+	config  EmailConfig
+	subject *template.Template
+}
+
+// NewEmailNotifier creates a notifier emailing run summaries through an SMTP server
+func NewEmailNotifier(config EmailConfig) (Notifier, error) {
+	if config.Port == 0 {
+		config.Port = 587
+	}
+	if config.Subject == "" {
+		config.Subject = DefaultEmailSubject
+	}
+	subject, err := template.New("subject").Parse(config.Subject)
+	if err != nil {
+		return nil, fmt.Errorf("invalid email subject template: %w", err)
+	}
+	return &emailNotifier{config: config, subject: subject}, nil
+}
+
+// Name implements Notifier
+func (n *emailNotifier) Name() string {
+	return "email"
+}
+
+// Notify implements Notifier
+func (n *emailNotifier) Notify(ctx context.Context, run *Run) error {
+	message, err := n.message(run, timefmt.Now())
+	if err != nil {
+		return err
+	}
+	return n.send(ctx, message)
+}
+
+// message renders the MIME message for run: a plain text summary plus the report attachments
+func (n *emailNotifier) message(run *Run, now time.Time) ([]byte, error) {
+	data := subjectData{Status: run.Status, Reason: run.Reason, Date: now.Format("2006-01-02")}
+	if run.Report != nil {
+		data.FailedUsers = run.Report.Summary.FailedUsers
+	}
+	var subject strings.Builder
+	if err := n.subject.Execute(&subject, data); err != nil {
+		return nil, fmt.Errorf("failed to render email subject: %w", err)
+	}
+
+	body := Title(run) + "\n"
+	if lines := Lines(run); len(lines) > 0 {
+		body += "\n" + strings.Join(lines, "\n") + "\n"
+	}
+
+	attachments, err := n.attachments(run)
+	if err != nil {
+		return nil, err
+	}
+
+	boundary, err := randomBoundary()
+	if err != nil {
+		return nil, err
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", n.config.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(n.config.To, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", strings.TrimSpace(subject.String())))
+	fmt.Fprintf(&msg, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&msg, "Content-Type: multipart/mixed; boundary=%q\r\n\r\n", boundary)
+
+	fmt.Fprintf(&msg, "--%s\r\n", boundary)
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	msg.WriteString("Content-Transfer-Encoding: base64\r\n\r\n")
+	writeBase64(&msg, []byte(body))
+
+	for _, attachment := range attachments {
+		fmt.Fprintf(&msg, "--%s\r\n", boundary)
+		fmt.Fprintf(&msg, "Content-Type: %s\r\n", attachment.contentType)
+		fmt.Fprintf(&msg, "Content-Disposition: attachment; filename=%q\r\n", attachment.name)
+		msg.WriteString("Content-Transfer-Encoding: base64\r\n\r\n")
+		writeBase64(&msg, attachment.content)
+	}
+	fmt.Fprintf(&msg, "--%s--\r\n", boundary)
+
+	return msg.Bytes(), nil
+}
+
+// attachment is a file attached to the email
+type attachment struct {
+	name        string
+	contentType string
+	content     []byte
+}
+
+// attachments renders the configured report formats; runs without a report have none
+func (n *emailNotifier) attachments(run *Run) ([]attachment, error) {
+	if run.Report == nil {
+		return nil, nil
+	}
+
+	var attachments []attachment
+	for _, format := range n.config.Attach {
+		switch strings.ToLower(format) {
+		case AttachJSON:
+			content, err := json.MarshalIndent(run.Report, "", "  ")
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode run report: %w", err)
+			}
+			attachments = append(attachments, attachment{name: "run-report.json", contentType: "application/json", content: content})
+		case AttachCSV:
+			content, err := reportCSV(run)
+			if err != nil {
+				return nil, err
+			}
+			attachments = append(attachments, attachment{name: "run-report.csv", contentType: "text/csv; charset=utf-8", content: content})
+		default:
+			return nil, fmt.Errorf("unknown email attachment format %q", format)
+		}
+	}
+	return attachments, nil
+}
+
+// reportCSV renders one row per recording file of the run report
+func reportCSV(run *Run) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{"zoom_email", "box_email", "recording_uuid", "topic", "file_name", "local_path", "outcome", "bytes_downloaded", "error"})
+	for _, user := range run.Report.Users {
+		for _, file := range user.Files {
+			w.Write([]string{
+				user.ZoomEmail, user.BoxEmail, file.RecordingUUID, file.Topic, file.FileName, file.LocalPath,
+				file.Outcome, strconv.FormatInt(file.BytesDownloaded, 10), file.Error,
+			})
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, fmt.Errorf("failed to write run report CSV: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// send delivers message through the SMTP server
+func (n *emailNotifier) send(ctx context.Context, message []byte) error {
+	addr := net.JoinHostPort(n.config.Host, strconv.Itoa(n.config.Port))
+	tlsConfig := &tls.Config{ServerName: n.config.Host}
+
+	var conn net.Conn
+	var err error
+	if n.config.ImplicitTLS {
+		conn, err = (&tls.Dialer{Config: tlsConfig}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to connect to SMTP server %s: %w", addr, err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, n.config.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to start SMTP session with %s: %w", addr, err)
+	}
+	defer client.Close()
+
+	if !n.config.ImplicitTLS {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(tlsConfig); err != nil {
+				return fmt.Errorf("SMTP STARTTLS failed: %w", err)
+			}
+		}
+	}
+	if n.config.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", n.config.Username, n.config.Password, n.config.Host)); err != nil {
+			return fmt.Errorf("SMTP authentication failed: %w", err)
+		}
+	}
+
+	if err := client.Mail(n.config.From); err != nil {
+		return fmt.Errorf("SMTP server rejected sender %s: %w", n.config.From, err)
+	}
+	for _, to := range n.config.To {
+		if err := client.Rcpt(to); err != nil {
+			return fmt.Errorf("SMTP server rejected recipient %s: %w", to, err)
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("SMTP DATA failed: %w", err)
+	}
+	if _, err := w.Write(message); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("SMTP server rejected the email: %w", err)
+	}
+	return client.Quit()
+}
+
+// writeBase64 writes content base64-encoded in 76-character lines, as MIME requires
+func writeBase64(buf *bytes.Buffer, content []byte) {
+	encoded := base64.StdEncoding.EncodeToString(content)
+	for len(encoded) > 76 {
+		buf.WriteString(encoded[:76] + "\r\n")
+		encoded = encoded[76:]
+	}
+	buf.WriteString(encoded + "\r\n")
+}
+
+// randomBoundary returns a MIME multipart boundary
+func randomBoundary() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate MIME boundary: %w", err)
+	}
+	return "zoom-to-box-" + hex.EncodeToString(b), nil
+}
@@ -0,0 +1,180 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/curtbushko/zoom-to-box/internal/processor"
+)
+
+func TestEmailNotifier_Message(t *testing.T) {
+	run := testRun()
+	run.Report.Users[1].Files = []processor.FileOutcome{
+		{RecordingUUID: "uuid-1", Topic: "Standup", FileName: "a.mp4", Outcome: processor.OutcomeFailed, Error: "download failed"},
+	}
+	notifier, err := NewEmailNotifier(EmailConfig{
+		Host:    "smtp.example.com",
+		From:    "zoom-to-box@example.com",
+		To:      []string{"ops@example.com", "it@example.com"},
+		Subject: "[zoom-to-box] {{.Date}} {{.Status}} ({{.FailedUsers}} failed)",
+		Attach:  []string{AttachJSON, AttachCSV},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	raw, err := notifier.(*emailNotifier).message(run, time.Date(2024, 3, 1, 2, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg, err := mail.ReadMessage(strings.NewReader(string(raw)))
+	if err != nil {
+		t.Fatalf("Invalid message: %v", err)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if subject != "[zoom-to-box] 2024-03-01 completed with errors (1 failed)" {
+		t.Errorf("Unexpected subject %q", subject)
+	}
+	if msg.Header.Get("To") != "ops@example.com, it@example.com" {
+		t.Errorf("Unexpected recipients %q", msg.Header.Get("To"))
+	}
+
+	_, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := make(map[string]string)
+	reader := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, err := io.ReadAll(base64.NewDecoder(base64.StdEncoding, part))
+		if err != nil {
+			t.Fatal(err)
+		}
+		name := part.FileName()
+		if name == "" {
+			name = "body"
+		}
+		parts[name] = string(content)
+	}
+
+	if !strings.Contains(parts["body"], "jane@example.com: 4 errors") {
+		t.Errorf("Expected the failed user in the body, got %q", parts["body"])
+	}
+	var report processor.RunReport
+	if err := json.Unmarshal([]byte(parts["run-report.json"]), &report); err != nil || report.Summary.FailedUsers != 1 {
+		t.Errorf("Expected the JSON run report attached, got %v (%v)", parts["run-report.json"], err)
+	}
+	rows, err := csv.NewReader(strings.NewReader(parts["run-report.csv"])).ReadAll()
+	if err != nil || len(rows) != 2 || rows[1][0] != "jane@example.com" || rows[1][8] != "download failed" {
+		t.Errorf("Expected a CSV row per file, got %v (%v)", rows, err)
+	}
+}
+
+func TestNewEmailNotifier_InvalidSubject(t *testing.T) {
+	if _, err := NewEmailNotifier(EmailConfig{Subject: "{{.Status"}); err == nil {
+		t.Error("Expected an error for an invalid subject template")
+	}
+}
+
+// fakeSMTPServer accepts one session and records the envelope and message
+func fakeSMTPServer(t *testing.T) (port int, received chan string) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	received = make(chan string, 1)
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+
+		var session strings.Builder
+		reply("220 localhost ESMTP")
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			command := strings.ToUpper(strings.TrimSpace(line))
+			switch {
+			case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+				reply("250 localhost")
+			case strings.HasPrefix(command, "MAIL"), strings.HasPrefix(command, "RCPT"):
+				session.WriteString(strings.TrimSpace(line) + "\n")
+				reply("250 OK")
+			case command == "DATA":
+				reply("354 go ahead")
+				for {
+					data, err := r.ReadString('\n')
+					if err != nil || data == ".\r\n" {
+						break
+					}
+					session.WriteString(data)
+				}
+				reply("250 queued")
+			case command == "QUIT":
+				reply("221 bye")
+				received <- session.String()
+				return
+			default:
+				reply("502 not implemented")
+			}
+		}
+	}()
+
+	return listener.Addr().(*net.TCPAddr).Port, received
+}
+
+func TestEmailNotifier_Send(t *testing.T) {
+	port, received := fakeSMTPServer(t)
+	notifier, err := NewEmailNotifier(EmailConfig{
+		Host: "127.0.0.1",
+		Port: port,
+		From: "zoom-to-box@example.com",
+		To:   []string{"ops@example.com"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := notifier.Notify(ctx, &Run{Status: StatusAborted, Reason: "no users"}); err != nil {
+		t.Fatalf("Notify failed: %v", err)
+	}
+
+	session := <-received
+	for _, want := range []string{"MAIL FROM:<zoom-to-box@example.com>", "RCPT TO:<ops@example.com>", "Subject: zoom-to-box run aborted: no users"} {
+		if !strings.Contains(session, want) {
+			t.Errorf("Expected the session to contain %q, got:\n%s", want, session)
+		}
+	}
+}
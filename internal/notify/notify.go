@@ -0,0 +1,204 @@
+// Package notify sends batch run summaries to chat incoming webhooks (Slack, Microsoft Teams)
+// and by email
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/curtbushko/zoom-to-box/internal/logging"
+	"github.com/curtbushko/zoom-to-box/internal/processor"
+	"github.com/curtbushko/zoom-to-box/internal/progress"
+)
+
+// Message limits keep notifications readable for runs with many failures
+const (
+	maxFailedUsers     = 10
+	maxErrorsPerUser   = 3
+	defaultPostTimeout = 15 * time.Second
+)
+
+// Status is how a run ended
+type Status string
+
+const (
+	StatusCompleted  Status = "completed"             // Every user was processed without errors
+	StatusWithErrors Status = "completed with errors" // The run finished but some users or files failed
+	StatusStopped    Status = "stopped early"         // Time-boxed, a monitor limit, a full disk or a signal; the next run resumes
+	StatusAborted    Status = "aborted"               // The run failed before it could finish
+)
+
+// Run describes a batch run that finished or aborted
+type Run struct {
+	Status Status
+	Reason string               // Why the run stopped early or aborted ("" = none)
+	Report *processor.RunReport // Per-user results (nil when the run aborted before processing users)
+}
+
+// Failed reports whether the run deserves attention
+func (r *Run) Failed() bool {
+	return r.Status != StatusCompleted
+}
+
+// Notifier posts run summaries to one chat service
+type Notifier interface {
+	// Name identifies the service in logs
+	Name() string
+
+	// Notify posts the summary of run
+	Notify(ctx context.Context, run *Run) error
+}
+
+// Send posts run to every notifier
+// Failures are logged only: a notification problem never fails the run it reports on.
+func Send(ctx context.Context, notifiers []Notifier, run *Run) {
+	for _, notifier := range notifiers {
+		postCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), defaultPostTimeout)
+		if err := notifier.Notify(postCtx, run); err != nil {
+			logging.Warn("Failed to send %s notification: %v", notifier.Name(), err)
+		}
+		cancel()
+	}
+}
+
+// Title returns the one-line headline of run
+func Title(run *Run) string {
+	title := "zoom-to-box run " + string(run.Status)
+	if run.Reason != "" {
+		title += ": " + run.Reason
+	}
+	return title
+}
+
+// Lines returns the body of the summary of run: totals, then the failed users and their errors
+func Lines(run *Run) []string {
+	report := run.Report
+	if report == nil {
+		return nil
+	}
+
+	s := report.Summary
+	lines := []string{
+		fmt.Sprintf("Users: %d/%d processed, %d failed", s.ProcessedUsers, s.TotalUsers, s.FailedUsers),
+		fmt.Sprintf("Files: %d downloaded (%s), %d uploaded, %d skipped, %d errors",
+			s.Downloads, progress.FormatBytes(s.BytesDownloaded), s.Uploads, s.Skipped, s.Errors),
+		fmt.Sprintf("Duration: %v", time.Duration(report.DurationSeconds*float64(time.Second)).Round(time.Second)),
+	}
+
+	var failed []processor.UserReport
+	for _, user := range report.Users {
+		if user.Errors > 0 {
+			failed = append(failed, user)
+		}
+	}
+	if len(failed) == 0 {
+		return lines
+	}
+
+	lines = append(lines, "Failed users:")
+	for i, user := range failed {
+		if i == maxFailedUsers {
+			lines = append(lines, fmt.Sprintf("…and %d more users", len(failed)-maxFailedUsers))
+			break
+		}
+		lines = append(lines, fmt.Sprintf("• %s: %d errors", user.ZoomEmail, user.Errors))
+		for j, message := range user.ErrorMessages {
+			if j == maxErrorsPerUser {
+				lines = append(lines, fmt.Sprintf("    …and %d more", len(user.ErrorMessages)-maxErrorsPerUser))
+				break
+			}
+			lines = append(lines, "    "+message)
+		}
+	}
+	return lines
+}
+
+// webhookNotifier posts a JSON payload to an incoming webhook URL
+type webhookNotifier struct {
+	name    string
+	url     string
+	client  *http.Client
+	payload func(run *Run) any
+}
+
+// NewSlackNotifier creates a notifier posting to a Slack incoming webhook
+func NewSlackNotifier(webhookURL string) Notifier {
+	return &webhookNotifier{name: "Slack", url: webhookURL, client: newHTTPClient(), payload: slackPayload}
+}
+
+// NewTeamsNotifier creates a notifier posting to a Microsoft Teams incoming webhook
+func NewTeamsNotifier(webhookURL string) Notifier {
+	return &webhookNotifier{name: "Teams", url: webhookURL, client: newHTTPClient(), payload: teamsPayload}
+}
+
+// newHTTPClient returns the client for webhook posts
+// Requests are not traced: webhook URL paths are secrets and spans record paths.
+func newHTTPClient() *http.Client {
+	return &http.Client{Timeout: defaultPostTimeout}
+}
+
+// Name implements Notifier
+func (n *webhookNotifier) Name() string {
+	return n.name
+}
+
+// Notify implements Notifier
+func (n *webhookNotifier) Notify(ctx context.Context, run *Run) error {
+	body, err := json.Marshal(n.payload(run))
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create notification request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post notification: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("webhook returned %s: %s", resp.Status, strings.TrimSpace(string(detail)))
+	}
+	return nil
+}
+
+// slackPayload renders run as a Slack message with the details in a code block
+func slackPayload(run *Run) any {
+	text := "*" + Title(run) + "*"
+	if lines := Lines(run); len(lines) > 0 {
+		text += "\n```\n" + strings.Join(lines, "\n") + "\n```"
+	}
+	return map[string]string{"text": text}
+}
+
+// teamsPayload renders run as a Teams MessageCard
+func teamsPayload(run *Run) any {
+	color := "2EB886"
+	if run.Failed() {
+		color = "D93F0B"
+	}
+	card := map[string]any{
+		"@type":      "MessageCard",
+		"@context":   "https://schema.org/extensions",
+		"summary":    Title(run),
+		"title":      Title(run),
+		"themeColor": color,
+	}
+	if lines := Lines(run); len(lines) > 0 {
+		// Teams renders markdown; two trailing spaces keep the line breaks
+		card["text"] = strings.Join(lines, "  \n")
+	}
+	return card
+}
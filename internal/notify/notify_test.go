@@ -0,0 +1,132 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/curtbushko/zoom-to-box/internal/processor"
+)
+
+func testRun() *Run {
+	return &Run{
+		Status: StatusWithErrors,
+		Report: &processor.RunReport{
+			DurationSeconds: 90,
+			Summary: processor.ReportSummary{
+				TotalUsers:     2,
+				ProcessedUsers: 1,
+				FailedUsers:    1,
+				Downloads:      3,
+				Uploads:        3,
+				Errors:         4,
+			},
+			Users: []processor.UserReport{
+				{ZoomEmail: "ok@example.com"},
+				{
+					ZoomEmail: "jane@example.com",
+					Errors:    4,
+					ErrorMessages: []string{
+						"download failed for a.mp4", "download failed for b.mp4",
+						"download failed for c.mp4", "download failed for d.mp4",
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestLines(t *testing.T) {
+	text := strings.Join(Lines(testRun()), "\n")
+
+	for _, want := range []string{
+		"Users: 1/2 processed, 1 failed",
+		"3 uploaded",
+		"Duration: 1m30s",
+		"• jane@example.com: 4 errors",
+		"download failed for c.mp4",
+		"…and 1 more",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected summary to contain %q, got:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "ok@example.com") || strings.Contains(text, "d.mp4") {
+		t.Errorf("Expected only failed users and the first errors, got:\n%s", text)
+	}
+}
+
+func TestTitle(t *testing.T) {
+	run := &Run{Status: StatusAborted, Reason: "failed to load active users file"}
+	if got := Title(run); got != "zoom-to-box run aborted: failed to load active users file" {
+		t.Errorf("Unexpected title %q", got)
+	}
+}
+
+func TestWebhookNotifiers(t *testing.T) {
+	tests := []struct {
+		name     string
+		notifier func(url string) Notifier
+		check    func(t *testing.T, payload map[string]any)
+	}{
+		{
+			name:     "slack",
+			notifier: NewSlackNotifier,
+			check: func(t *testing.T, payload map[string]any) {
+				text, _ := payload["text"].(string)
+				if !strings.HasPrefix(text, "*zoom-to-box run completed with errors*") || !strings.Contains(text, "jane@example.com") {
+					t.Errorf("Unexpected Slack text %q", text)
+				}
+			},
+		},
+		{
+			name:     "teams",
+			notifier: NewTeamsNotifier,
+			check: func(t *testing.T, payload map[string]any) {
+				if payload["@type"] != "MessageCard" || payload["title"] != "zoom-to-box run completed with errors" {
+					t.Errorf("Unexpected Teams card %v", payload)
+				}
+				if text, _ := payload["text"].(string); !strings.Contains(text, "jane@example.com") {
+					t.Errorf("Expected the failed user in the card text, got %q", text)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var payload map[string]any
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+					t.Errorf("Unexpected request %s %s", r.Method, r.Header.Get("Content-Type"))
+				}
+				if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+					t.Errorf("Invalid payload: %v", err)
+				}
+			}))
+			defer server.Close()
+
+			if err := tt.notifier(server.URL).Notify(context.Background(), testRun()); err != nil {
+				t.Fatalf("Notify failed: %v", err)
+			}
+			tt.check(t, payload)
+		})
+	}
+}
+
+func TestWebhookNotifier_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, "no_service")
+	}))
+	defer server.Close()
+
+	err := NewSlackNotifier(server.URL).Notify(context.Background(), testRun())
+	if err == nil || !strings.Contains(err.Error(), "no_service") {
+		t.Errorf("Expected the webhook's error, got %v", err)
+	}
+}
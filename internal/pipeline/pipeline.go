@@ -0,0 +1,126 @@
+// Package pipeline runs work items through an ordered list of named stages.
+// Each stage reads and updates the item, so stages can be unit tested on their own
+// and new stages (scan, transcode, encrypt) can be inserted between existing ones.
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/curtbushko/zoom-to-box/internal/tracing"
+)
+
+// ErrStop is returned by a stage when the item needs no further stages, e.g. because it was skipped
+// Run treats it as success and does not run the remaining stages.
+var ErrStop = errors.New("pipeline stopped")
+
+// StageFunc processes an item for a single stage
+type StageFunc[T any] func(ctx context.Context, item T) error
+
+// Stage is a named step of a pipeline
+type Stage[T any] struct {
+	Name string
+	Run  StageFunc[T]
+}
+
+// NewStage creates a named stage
+func NewStage[T any](name string, run StageFunc[T]) Stage[T] {
+	return Stage[T]{Name: name, Run: run}
+}
+
+// StageError reports the stage an item failed in
+type StageError struct {
+	Stage string
+	Err   error
+}
+
+func (e *StageError) Error() string {
+	return fmt.Sprintf("%s stage: %v", e.Stage, e.Err)
+}
+
+func (e *StageError) Unwrap() error {
+	return e.Err
+}
+
+// Observer is notified after every stage run, for timing and instrumentation
+type Observer interface {
+	ObserveStage(stage string, elapsed time.Duration, err error)
+}
+
+// Pipeline runs items through its stages in order
+type Pipeline[T any] struct {
+	stages []Stage[T]
+}
+
+// New creates a pipeline from stages, run in the given order
+func New[T any](stages ...Stage[T]) *Pipeline[T] {
+	return &Pipeline[T]{stages: stages}
+}
+
+// Stages returns the stage names in run order
+func (p *Pipeline[T]) Stages() []string {
+	names := make([]string, len(p.stages))
+	for i, stage := range p.stages {
+		names[i] = stage.Name
+	}
+	return names
+}
+
+// InsertAfter returns a copy of the pipeline with stage added after the named stage
+func (p *Pipeline[T]) InsertAfter(after string, stage Stage[T]) (*Pipeline[T], error) {
+	i, err := p.index(after)
+	if err != nil {
+		return nil, err
+	}
+	stages := make([]Stage[T], 0, len(p.stages)+1)
+	stages = append(stages, p.stages[:i+1]...)
+	stages = append(stages, stage)
+	stages = append(stages, p.stages[i+1:]...)
+	return &Pipeline[T]{stages: stages}, nil
+}
+
+// From returns the pipeline starting at the named stage, for items that already passed the earlier stages
+func (p *Pipeline[T]) From(name string) (*Pipeline[T], error) {
+	i, err := p.index(name)
+	if err != nil {
+		return nil, err
+	}
+	return &Pipeline[T]{stages: p.stages[i:]}, nil
+}
+
+func (p *Pipeline[T]) index(name string) (int, error) {
+	for i, stage := range p.stages {
+		if stage.Name == name {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("pipeline has no %q stage", name)
+}
+
+// Run passes item through the stages in order and stops at the first stage that fails or returns ErrStop
+// observer may be nil. A failure is returned as a *StageError naming the stage.
+func (p *Pipeline[T]) Run(ctx context.Context, item T, observer Observer) error {
+	for _, stage := range p.stages {
+		start := time.Now()
+		stageCtx, span := tracing.Start(ctx, "stage."+stage.Name)
+		err := stage.Run(stageCtx, item)
+		if errors.Is(err, ErrStop) {
+			tracing.End(span, nil)
+		} else {
+			tracing.End(span, err)
+		}
+		if observer != nil {
+			observer.ObserveStage(stage.Name, time.Since(start), err)
+		}
+
+		if errors.Is(err, ErrStop) {
+			return nil
+		}
+		if err != nil {
+			return &StageError{Stage: stage.Name, Err: err}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type item struct {
+	visited []string
+}
+
+func record(name string, err error) Stage[*item] {
+	return NewStage(name, func(ctx context.Context, it *item) error {
+		it.visited = append(it.visited, name)
+		return err
+	})
+}
+
+func TestPipeline_Run(t *testing.T) {
+	failure := errors.New("boom")
+
+	tests := []struct {
+		name        string
+		pipeline    *Pipeline[*item]
+		wantVisited []string
+		wantStage   string // Stage reported in the StageError ("" = no error)
+	}{
+		{
+			name:        "all stages run in order",
+			pipeline:    New(record("list", nil), record("download", nil), record("upload", nil)),
+			wantVisited: []string{"list", "download", "upload"},
+		},
+		{
+			name:        "ErrStop ends the item without an error",
+			pipeline:    New(record("list", ErrStop), record("download", nil)),
+			wantVisited: []string{"list"},
+		},
+		{
+			name:        "a failure stops the pipeline and names the stage",
+			pipeline:    New(record("list", nil), record("download", failure), record("upload", nil)),
+			wantVisited: []string{"list", "download"},
+			wantStage:   "download",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			it := &item{}
+			timings := NewTimings()
+			err := tt.pipeline.Run(context.Background(), it, timings)
+
+			if !reflect.DeepEqual(it.visited, tt.wantVisited) {
+				t.Errorf("Expected stages %v to run, got %v", tt.wantVisited, it.visited)
+			}
+
+			var stageErr *StageError
+			switch {
+			case tt.wantStage == "" && err != nil:
+				t.Errorf("Expected no error, got %v", err)
+			case tt.wantStage != "" && (!errors.As(err, &stageErr) || stageErr.Stage != tt.wantStage || !errors.Is(err, failure)):
+				t.Errorf("Expected a %s stage error wrapping %v, got %v", tt.wantStage, failure, err)
+			}
+
+			stats := timings.Snapshot()
+			if len(stats) != len(tt.wantVisited) {
+				t.Errorf("Expected timings for %d stages, got %v", len(tt.wantVisited), stats)
+			}
+			if tt.wantStage != "" && stats[tt.wantStage].Failures != 1 {
+				t.Errorf("Expected one failure recorded for %s, got %+v", tt.wantStage, stats[tt.wantStage])
+			}
+		})
+	}
+}
+
+func TestPipeline_InsertAfterAndFrom(t *testing.T) {
+	base := New(record("list", nil), record("download", nil), record("upload", nil))
+
+	extended, err := base.InsertAfter("download", record("scan", nil))
+	if err != nil {
+		t.Fatalf("InsertAfter failed: %v", err)
+	}
+	if got, want := extended.Stages(), []string{"list", "download", "scan", "upload"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected stages %v, got %v", want, got)
+	}
+	if got, want := base.Stages(), []string{"list", "download", "upload"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected the original pipeline to be unchanged, got %v", got)
+	}
+
+	tail, err := extended.From("scan")
+	if err != nil {
+		t.Fatalf("From failed: %v", err)
+	}
+	it := &item{}
+	if err := tail.Run(context.Background(), it, nil); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if want := []string{"scan", "upload"}; !reflect.DeepEqual(it.visited, want) {
+		t.Errorf("Expected stages %v to run, got %v", want, it.visited)
+	}
+
+	if _, err := base.InsertAfter("encrypt", record("scan", nil)); err == nil {
+		t.Error("Expected an error inserting after an unknown stage")
+	}
+	if _, err := base.From("encrypt"); err == nil {
+		t.Error("Expected an error starting from an unknown stage")
+	}
+}
@@ -0,0 +1,52 @@
+package pipeline
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// StageStats summarizes the runs of a single stage
+type StageStats struct {
+	Runs     int           // Items the stage ran for
+	Failures int           // Runs that returned an error other than ErrStop
+	Duration time.Duration // Total time spent in the stage
+}
+
+// Timings is an Observer that accumulates per-stage statistics
+// It is safe for concurrent use.
+type Timings struct {
+	mu     sync.Mutex
+	stages map[string]StageStats
+}
+
+// NewTimings creates an empty set of stage timings
+func NewTimings() *Timings {
+	return &Timings{stages: make(map[string]StageStats)}
+}
+
+// ObserveStage records a stage run
+func (t *Timings) ObserveStage(stage string, elapsed time.Duration, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	stats := t.stages[stage]
+	stats.Runs++
+	stats.Duration += elapsed
+	if err != nil && !errors.Is(err, ErrStop) {
+		stats.Failures++
+	}
+	t.stages[stage] = stats
+}
+
+// Snapshot returns a copy of the statistics keyed by stage name
+func (t *Timings) Snapshot() map[string]StageStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	snapshot := make(map[string]StageStats, len(t.stages))
+	for stage, stats := range t.stages {
+		snapshot[stage] = stats
+	}
+	return snapshot
+}
@@ -0,0 +1,122 @@
+package preflight
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/curtbushko/zoom-to-box/internal/logging"
+	"github.com/curtbushko/zoom-to-box/internal/progress"
+)
+
+// ErrInsufficientSpace is returned by DiskGuard.Reserve when a download would leave less than
+// the reserve free
+var ErrInsufficientSpace = errors.New("not enough free disk space")
+
+// DefaultPollInterval is how often DiskGuard rechecks free space while waiting
+const DefaultPollInterval = 30 * time.Second
+
+// DiskGuard checks free space on the output filesystem before each download, so a run
+// stops cleanly between files instead of failing mid-write when the disk fills up
+// Downloads in progress keep their size reserved until Release, so concurrent downloads that
+// each fit on their own cannot together fill the disk.
+type DiskGuard struct {
+	dir          string
+	reserveBytes uint64
+	wait         time.Duration
+	pollInterval time.Duration
+
+	stats func(dir string) (*Stats, error)
+
+	mu       sync.Mutex
+	inFlight uint64 // Bytes reserved by downloads that have not been released
+}
+
+// NewDiskGuard creates a guard keeping reserveBytes free on the filesystem holding dir
+// A download that does not fit waits up to wait for space to be freed, e.g. by uploads
+// deleting local copies in another process (0 = fail immediately).
+func NewDiskGuard(dir string, reserveBytes uint64, wait time.Duration) *DiskGuard {
+	return &DiskGuard{
+		dir:          dir,
+		reserveBytes: reserveBytes,
+		wait:         wait,
+		pollInterval: DefaultPollInterval,
+		stats:        FilesystemStats,
+	}
+}
+
+// Reserve returns once size more bytes can be written while keeping the reserve free, next to
+// the bytes reserved by downloads in progress, and reserves them until Release
+// It returns ErrInsufficientSpace when the space is not there, after waiting if configured.
+// Platforms without filesystem statistics are not checked.
+func (g *DiskGuard) Reserve(ctx context.Context, size int64) error {
+	if size < 0 {
+		size = 0
+	}
+	needed := g.reserveBytes + uint64(size)
+
+	var deadline time.Time
+	waiting := false
+	for {
+		dir, err := nearestExistingDir(g.dir)
+		if err != nil {
+			return err
+		}
+		stats, err := g.stats(dir)
+		if errors.Is(err, ErrUnsupported) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		g.mu.Lock()
+		inFlight := g.inFlight
+		fits := stats.FreeBytes >= needed+inFlight
+		if fits {
+			g.inFlight += uint64(size)
+		}
+		g.mu.Unlock()
+		if fits {
+			if waiting {
+				logging.Info("Free space on %s recovered to %s, resuming downloads", g.dir, progress.FormatBytes(int64(stats.FreeBytes)))
+			}
+			return nil
+		}
+
+		if !waiting {
+			deadline = time.Now().Add(g.wait)
+		}
+		if g.wait <= 0 || !time.Now().Before(deadline) {
+			return fmt.Errorf("%w on %s: %s free with %s reserved by downloads in progress, the next download needs %s "+
+				"and download.disk_reserve_gb keeps %s free; free up space (e.g. --delete-after-upload) and run again to resume",
+				ErrInsufficientSpace, g.dir, progress.FormatBytes(int64(stats.FreeBytes)), progress.FormatBytes(int64(inFlight)),
+				progress.FormatBytes(size), progress.FormatBytes(int64(g.reserveBytes)))
+		}
+		if !waiting {
+			logging.Warn("Pausing downloads: %s free on %s, below the %s needed; waiting up to %v for space",
+				progress.FormatBytes(int64(stats.FreeBytes)), g.dir, progress.FormatBytes(int64(needed+inFlight)), g.wait)
+			waiting = true
+		}
+
+		timer := time.NewTimer(min(g.pollInterval, time.Until(deadline)))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// Release returns the size of an earlier Reserve once its download has ended, written or not;
+// written bytes are counted by the filesystem's free space from then on
+func (g *DiskGuard) Release(size int64) {
+	if size <= 0 {
+		return
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.inFlight -= min(uint64(size), g.inFlight)
+}
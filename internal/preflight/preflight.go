@@ -0,0 +1,118 @@
+// Package preflight checks the output filesystem before a run starts
+// A migration that runs out of disk space or inodes, or hits the path length limit, halfway
+// through leaves a partial run to clean up; these checks fail fast instead.
+package preflight
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/curtbushko/zoom-to-box/internal/progress"
+)
+
+// ErrUnsupported is returned by FilesystemStats on platforms where the statistics are not available
+var ErrUnsupported = errors.New("filesystem statistics are not supported on this platform")
+
+// Stats describes the filesystem holding a directory
+type Stats struct {
+	FreeBytes     uint64 // Bytes available to unprivileged users
+	FreeInodes    uint64 // Inodes (file slots) available
+	TotalInodes   uint64 // Total inodes (0 = the filesystem does not report inodes)
+	MaxPathLength int    // Longest path the OS accepts
+	MaxNameLength int    // Longest single file or folder name the filesystem accepts
+}
+
+// Options holds the minimums the output filesystem must meet
+type Options struct {
+	MinFreeBytes  uint64 // Required free space (0 = not checked)
+	MinFreeInodes uint64 // Required free inodes (0 = not checked)
+	MaxPathLength int    // Path length limit used instead of the OS limit when lower (0 = OS limit)
+
+	// DeepestPath is the longest path the run is expected to create, e.g. the output directory
+	// joined with the layout for the longest username and the longest file name ("" = not checked)
+	DeepestPath string
+}
+
+// Check verifies the filesystem holding dir against opts
+// dir does not need to exist yet; its nearest existing parent is checked.
+func Check(dir string, opts Options) (*Stats, error) {
+	existing, err := nearestExistingDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := FilesystemStats(existing)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.MinFreeBytes > 0 && stats.FreeBytes < opts.MinFreeBytes {
+		return stats, fmt.Errorf("output filesystem for %s has %s free, below the required %s; free up space or point download.output_dir at a larger filesystem",
+			dir, progress.FormatBytes(int64(stats.FreeBytes)), progress.FormatBytes(int64(opts.MinFreeBytes)))
+	}
+
+	// Filesystems without fixed inode tables (e.g. btrfs) report 0 total inodes
+	if opts.MinFreeInodes > 0 && stats.TotalInodes > 0 && stats.FreeInodes < opts.MinFreeInodes {
+		return stats, fmt.Errorf("output filesystem for %s has %d free inodes, below the required %d; every recording needs several files "+
+			"(video, metadata JSON, thumbnail), so remove old downloads, enable --delete-after-upload, or use a filesystem with more inodes (e.g. mkfs.ext4 -i 8192)",
+			dir, stats.FreeInodes, opts.MinFreeInodes)
+	}
+
+	if opts.DeepestPath != "" {
+		if err := checkPathLength(opts.DeepestPath, stats, opts.MaxPathLength); err != nil {
+			return stats, err
+		}
+	}
+
+	return stats, nil
+}
+
+// checkPathLength verifies that path and each of its names fit the filesystem's limits
+func checkPathLength(path string, stats *Stats, maxPathLength int) error {
+	limit := stats.MaxPathLength
+	if maxPathLength > 0 && (limit == 0 || maxPathLength < limit) {
+		limit = maxPathLength
+	}
+	if limit > 0 && len(path) > limit {
+		return fmt.Errorf("deepest planned path is %d bytes, above the limit of %d: %s; shorten download.output_dir, directory.layout or filename.template",
+			len(path), limit, path)
+	}
+
+	if stats.MaxNameLength > 0 {
+		for _, name := range strings.Split(filepath.ToSlash(path), "/") {
+			if len(name) > stats.MaxNameLength {
+				return fmt.Errorf("planned name %q is %d bytes, above the filesystem limit of %d; shorten directory.layout or filename.template",
+					name, len(name), stats.MaxNameLength)
+			}
+		}
+	}
+	return nil
+}
+
+// nearestExistingDir returns dir, or its closest parent that exists
+func nearestExistingDir(dir string) (string, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", dir, err)
+	}
+	for {
+		info, err := os.Stat(abs)
+		if err == nil {
+			if !info.IsDir() {
+				return "", fmt.Errorf("%s is not a directory", abs)
+			}
+			return abs, nil
+		}
+		if !os.IsNotExist(err) {
+			return "", fmt.Errorf("failed to check %s: %w", abs, err)
+		}
+		parent := filepath.Dir(abs)
+		if parent == abs {
+			return "", fmt.Errorf("no existing parent directory for %s", dir)
+		}
+		abs = parent
+	}
+}
@@ -0,0 +1,122 @@
+package preflight
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheck(t *testing.T) {
+	dir := t.TempDir()
+	stats, err := FilesystemStats(dir)
+	if errors.Is(err, ErrUnsupported) {
+		t.Skip(err)
+	}
+	if err != nil {
+		t.Fatalf("FilesystemStats failed: %v", err)
+	}
+
+	t.Run("passes with no minimums", func(t *testing.T) {
+		if _, err := Check(filepath.Join(dir, "not", "created", "yet"), Options{}); err != nil {
+			t.Errorf("Expected the check to pass, got %v", err)
+		}
+	})
+
+	t.Run("fails when free space is too low", func(t *testing.T) {
+		_, err := Check(dir, Options{MinFreeBytes: stats.FreeBytes + 1<<40})
+		if err == nil || !strings.Contains(err.Error(), "free") {
+			t.Errorf("Expected a free space error, got %v", err)
+		}
+	})
+
+	t.Run("fails when free inodes are too low", func(t *testing.T) {
+		if stats.TotalInodes == 0 {
+			t.Skip("filesystem does not report inodes")
+		}
+		_, err := Check(dir, Options{MinFreeInodes: stats.FreeInodes + 1})
+		if err == nil || !strings.Contains(err.Error(), "inodes") {
+			t.Errorf("Expected a free inodes error, got %v", err)
+		}
+	})
+
+	t.Run("fails when the deepest path is too long", func(t *testing.T) {
+		deepest := filepath.Join(dir, "jane.smith", "2024", "01", "15", "weekly-standup-0930.mp4")
+		_, err := Check(dir, Options{MaxPathLength: len(deepest) - 1, DeepestPath: deepest})
+		if err == nil || !strings.Contains(err.Error(), "deepest planned path") {
+			t.Errorf("Expected a path length error, got %v", err)
+		}
+		if _, err := Check(dir, Options{MaxPathLength: len(deepest), DeepestPath: deepest}); err != nil {
+			t.Errorf("Expected a path at the limit to pass, got %v", err)
+		}
+	})
+}
+
+func TestCheckPathLength_NameLimit(t *testing.T) {
+	stats := &Stats{MaxPathLength: 4096, MaxNameLength: 255}
+	if err := checkPathLength("/out/"+strings.Repeat("x", 256)+".mp4", stats, 0); err == nil {
+		t.Error("Expected a name longer than the filesystem limit to fail")
+	}
+	if err := checkPathLength("/out/"+strings.Repeat("x", 251)+".mp4", stats, 0); err != nil {
+		t.Errorf("Expected a name at the limit to pass, got %v", err)
+	}
+}
+
+func TestDiskGuard_Reserve(t *testing.T) {
+	const gb = 1 << 30
+	free := uint64(10 * gb)
+	newGuard := func(wait time.Duration) *DiskGuard {
+		guard := NewDiskGuard(t.TempDir(), 2*gb, wait)
+		guard.pollInterval = time.Millisecond
+		guard.stats = func(string) (*Stats, error) {
+			return &Stats{FreeBytes: free}, nil
+		}
+		return guard
+	}
+
+	if err := newGuard(0).Reserve(context.Background(), 8*gb); err != nil {
+		t.Errorf("Expected a download leaving exactly the reserve to fit, got %v", err)
+	}
+	if err := newGuard(0).Reserve(context.Background(), 8*gb+1); !errors.Is(err, ErrInsufficientSpace) {
+		t.Errorf("Expected ErrInsufficientSpace, got %v", err)
+	}
+	if err := newGuard(20*time.Millisecond).Reserve(context.Background(), 9*gb); !errors.Is(err, ErrInsufficientSpace) {
+		t.Errorf("Expected ErrInsufficientSpace after waiting, got %v", err)
+	}
+
+	// Space freed while waiting lets the download go ahead
+	guard := newGuard(time.Minute)
+	calls := 0
+	guard.stats = func(string) (*Stats, error) {
+		calls++
+		if calls < 3 {
+			return &Stats{FreeBytes: 3 * gb}, nil
+		}
+		return &Stats{FreeBytes: free}, nil
+	}
+	if err := guard.Reserve(context.Background(), 4*gb); err != nil || calls != 3 {
+		t.Errorf("Expected the download to go ahead once space was freed, got %v after %d checks", err, calls)
+	}
+
+	// Downloads in progress keep their size reserved until released
+	guard = newGuard(0)
+	if err := guard.Reserve(context.Background(), 5*gb); err != nil {
+		t.Fatalf("Expected the first download to fit, got %v", err)
+	}
+	if err := guard.Reserve(context.Background(), 5*gb); !errors.Is(err, ErrInsufficientSpace) {
+		t.Errorf("Expected two downloads that only fit one at a time to be refused together, got %v", err)
+	}
+	guard.Release(5 * gb)
+	if err := guard.Reserve(context.Background(), 5*gb); err != nil {
+		t.Errorf("Expected the download to fit once the first was released, got %v", err)
+	}
+
+	// Unsupported platforms are not checked
+	guard = newGuard(0)
+	guard.stats = func(string) (*Stats, error) { return nil, ErrUnsupported }
+	if err := guard.Reserve(context.Background(), 100*gb); err != nil {
+		t.Errorf("Expected unsupported platforms to pass, got %v", err)
+	}
+}
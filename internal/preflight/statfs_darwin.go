@@ -0,0 +1,27 @@
+package preflight
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// PATH_MAX and NAME_MAX from <sys/syslimits.h>
+const (
+	darwinPathMax = 1024
+	darwinNameMax = 255
+)
+
+// FilesystemStats reads the statistics of the filesystem holding dir
+func FilesystemStats(dir string) (*Stats, error) {
+	var fs syscall.Statfs_t
+	if err := syscall.Statfs(dir, &fs); err != nil {
+		return nil, fmt.Errorf("failed to read filesystem statistics for %s: %w", dir, err)
+	}
+	return &Stats{
+		FreeBytes:     fs.Bavail * uint64(fs.Bsize),
+		FreeInodes:    fs.Ffree,
+		TotalInodes:   fs.Files,
+		MaxPathLength: darwinPathMax,
+		MaxNameLength: darwinNameMax,
+	}, nil
+}
@@ -0,0 +1,24 @@
+package preflight
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// linuxPathMax is PATH_MAX from <linux/limits.h>
+const linuxPathMax = 4096
+
+// FilesystemStats reads the statistics of the filesystem holding dir
+func FilesystemStats(dir string) (*Stats, error) {
+	var fs syscall.Statfs_t
+	if err := syscall.Statfs(dir, &fs); err != nil {
+		return nil, fmt.Errorf("failed to read filesystem statistics for %s: %w", dir, err)
+	}
+	return &Stats{
+		FreeBytes:     fs.Bavail * uint64(fs.Bsize),
+		FreeInodes:    fs.Ffree,
+		TotalInodes:   fs.Files,
+		MaxPathLength: linuxPathMax,
+		MaxNameLength: int(fs.Namelen),
+	}, nil
+}
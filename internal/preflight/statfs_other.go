@@ -0,0 +1,8 @@
+//go:build !linux && !darwin
+
+package preflight
+
+// FilesystemStats is not available on this platform; Check reports ErrUnsupported
+func FilesystemStats(dir string) (*Stats, error) {
+	return nil, ErrUnsupported
+}
@@ -0,0 +1,109 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/curtbushko/zoom-to-box/internal/logging"
+	"github.com/curtbushko/zoom-to-box/internal/zoom"
+)
+
+// AccountRecordingLister is implemented by Zoom clients that can list the recordings of the
+// whole account and the account's users
+type AccountRecordingLister interface {
+	GetAllAccountRecordings(ctx context.Context, accountID string, params zoom.ListRecordingsParams) ([]*zoom.Recording, error)
+	GetAllUsers(ctx context.Context, params zoom.ListUsersParams) ([]zoom.User, error)
+}
+
+// accountRecordings holds the recordings of the whole account by host
+type accountRecordings struct {
+	byHost  map[string][]*zoom.Recording // Recordings by host user ID
+	hostIDs map[string]string            // Host user ID by lowercased email and by ID
+}
+
+// listAccountRecordings lists the recordings of every user in the account once, for
+// AccountRecordings, so ProcessAllUsers needs a handful of calls instead of some per user.
+// Host IDs are mapped back to emails with the active and inactive users. If the client cannot
+// list the account, or listing fails, users are listed one by one as usual.
+func (p *userProcessorImpl) listAccountRecordings(ctx context.Context) {
+	logger := logging.GetDefaultLogger()
+
+	lister, ok := p.zoomClient.(AccountRecordingLister)
+	if !ok {
+		if logger != nil {
+			logger.WarnWithContext(ctx, "Zoom client does not support account recordings, listing users one by one")
+		}
+		return
+	}
+
+	index, err := newAccountRecordings(ctx, lister, p.recordingParams())
+	if err != nil {
+		if logger != nil {
+			logger.WarnWithContext(ctx, fmt.Sprintf("Failed to list account recordings, listing users one by one: %v", err))
+		}
+		return
+	}
+	if logger != nil {
+		logger.InfoWithContext(ctx, fmt.Sprintf("Listed account recordings of %d hosts", len(index.byHost)))
+	}
+
+	p.accountMu.Lock()
+	p.accountRecordings = index
+	p.accountMu.Unlock()
+}
+
+// clearAccountRecordings drops the account recordings listed for a run
+func (p *userProcessorImpl) clearAccountRecordings() {
+	p.accountMu.Lock()
+	p.accountRecordings = nil
+	p.accountMu.Unlock()
+}
+
+// newAccountRecordings lists the account's recordings and users and indexes the recordings by host
+func newAccountRecordings(ctx context.Context, lister AccountRecordingLister, params zoom.ListRecordingsParams) (*accountRecordings, error) {
+	index := &accountRecordings{
+		byHost:  make(map[string][]*zoom.Recording),
+		hostIDs: make(map[string]string),
+	}
+	// Deactivated users keep their recordings, so their emails are needed too
+	hostEmails := make(map[string]string)
+	for _, status := range []string{zoom.UserStatusActive, zoom.UserStatusInactive} {
+		zoomUsers, err := lister.GetAllUsers(ctx, zoom.ListUsersParams{Status: status})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s users: %w", status, err)
+		}
+		for _, user := range zoomUsers {
+			index.hostIDs[strings.ToLower(user.Email)] = user.ID
+			index.hostIDs[strings.ToLower(user.ID)] = user.ID
+			hostEmails[user.ID] = user.Email
+		}
+	}
+
+	recordings, err := lister.GetAllAccountRecordings(ctx, zoom.AccountMe, params)
+	if err != nil {
+		return nil, err
+	}
+	for _, recording := range recordings {
+		if recording.HostEmail == "" {
+			recording.HostEmail = hostEmails[recording.HostID]
+		}
+		index.byHost[recording.HostID] = append(index.byHost[recording.HostID], recording)
+	}
+	return index, nil
+}
+
+// userRecordings returns the recordings of a user, by email or user ID, from the account
+// recordings when they were listed for the run and include the user
+func (p *userProcessorImpl) userRecordings(ctx context.Context, zoomEmail string, params zoom.ListRecordingsParams) ([]*zoom.Recording, error) {
+	p.accountMu.Lock()
+	index := p.accountRecordings
+	p.accountMu.Unlock()
+
+	if index != nil {
+		if hostID, ok := index.hostIDs[strings.ToLower(zoomEmail)]; ok {
+			return index.byHost[hostID], nil
+		}
+	}
+	return p.zoomClient.GetAllUserRecordings(ctx, zoomEmail, params)
+}
@@ -0,0 +1,127 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/curtbushko/zoom-to-box/internal/filename"
+	"github.com/curtbushko/zoom-to-box/internal/users"
+	"github.com/curtbushko/zoom-to-box/internal/zoom"
+)
+
+// mockAccountZoomClient adds account recordings to mockZoomClient
+type mockAccountZoomClient struct {
+	*mockZoomClient
+	accountRecordings []*zoom.Recording
+	accountError      error
+	users             map[string][]zoom.User // Users by status
+	accountCalls      int
+}
+
+func (m *mockAccountZoomClient) GetAllAccountRecordings(ctx context.Context, accountID string, params zoom.ListRecordingsParams) ([]*zoom.Recording, error) {
+	m.accountCalls++
+	return m.accountRecordings, m.accountError
+}
+
+func (m *mockAccountZoomClient) GetAllUsers(ctx context.Context, params zoom.ListUsersParams) ([]zoom.User, error) {
+	return m.users[params.Status], nil
+}
+
+func TestUserProcessor_AccountRecordings(t *testing.T) {
+	recording := func(uuid, hostID string) *zoom.Recording {
+		return &zoom.Recording{
+			UUID:      uuid,
+			HostID:    hostID,
+			Topic:     "Standup " + uuid,
+			StartTime: time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
+			RecordingFiles: []zoom.RecordingFile{
+				{ID: uuid + "-file", FileType: "MP4", DownloadURL: "https://zoom.us/download/" + uuid + ".mp4", FileSize: 1024},
+			},
+		}
+	}
+
+	tests := []struct {
+		name          string
+		accountError  error
+		wantUserCalls map[string]int
+	}{
+		{
+			name: "known users come from the account listing",
+			// carol is not in the users list, so she is listed on her own
+			wantUserCalls: map[string]int{"carol@example.com": 1},
+		},
+		{
+			name:          "failed account listing falls back to per-user calls",
+			accountError:  errors.New("missing scope recording:read:admin"),
+			wantUserCalls: map[string]int{"alice@example.com": 1, "bob@example.com": 1, "carol@example.com": 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			activeUsersPath := filepath.Join(tmpDir, "active_users.txt")
+			content := "alice@example.com,alice@example.com,false\nbob@example.com,bob@example.com,false\ncarol@example.com,carol@example.com,false\n"
+			if err := os.WriteFile(activeUsersPath, []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			usersFile, err := users.LoadActiveUsersFile(activeUsersPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			zoomClient := &mockAccountZoomClient{
+				mockZoomClient:    newMockZoomClient(),
+				accountRecordings: []*zoom.Recording{recording("a1", "u1"), recording("a2", "u1"), recording("x1", "u9")},
+				accountError:      tt.accountError,
+				users: map[string][]zoom.User{
+					zoom.UserStatusActive:   {{ID: "u1", Email: "Alice@example.com"}},
+					zoom.UserStatusInactive: {{ID: "u2", Email: "bob@example.com"}},
+				},
+			}
+			zoomClient.recordingsCalls = make(map[string]int)
+			zoomClient.recordings["alice@example.com"] = []*zoom.Recording{recording("a1", "u1"), recording("a2", "u1")}
+			zoomClient.recordings["carol@example.com"] = []*zoom.Recording{recording("c1", "u3")}
+
+			processor := NewUserProcessor(
+				zoomClient,
+				newMockDownloadManager(),
+				nil,
+				filename.NewFileSanitizer(filename.FileSanitizerOptions{}),
+				nil,
+				ProcessorConfig{BaseDownloadDir: filepath.Join(tmpDir, "downloads"), ContinueOnError: true, AccountRecordings: true},
+			)
+
+			summary, err := processor.ProcessAllUsers(context.Background(), usersFile)
+			if err != nil {
+				t.Fatalf("ProcessAllUsers failed: %v", err)
+			}
+			if zoomClient.accountCalls != 1 {
+				t.Errorf("Expected the account to be listed once, got %d", zoomClient.accountCalls)
+			}
+			if summary.TotalDownloads != 3 || summary.TotalErrors != 0 {
+				t.Errorf("Expected 3 downloads without errors, got %d downloads and %d errors", summary.TotalDownloads, summary.TotalErrors)
+			}
+			if len(zoomClient.recordingsCalls) != len(tt.wantUserCalls) {
+				t.Errorf("Expected per-user calls %v, got %v", tt.wantUserCalls, zoomClient.recordingsCalls)
+			}
+			for user, want := range tt.wantUserCalls {
+				if got := zoomClient.recordingsCalls[user]; got != want {
+					t.Errorf("Expected %d per-user calls for %s, got %d", want, user, got)
+				}
+			}
+
+			// The listing only serves the run it was made for
+			if _, err := processor.ProcessUser(context.Background(), "alice@example.com", "alice@example.com"); err != nil {
+				t.Fatalf("ProcessUser failed: %v", err)
+			}
+			if zoomClient.recordingsCalls["alice@example.com"] != tt.wantUserCalls["alice@example.com"]+1 {
+				t.Errorf("Expected ProcessUser after the run to list alice on her own")
+			}
+		})
+	}
+}
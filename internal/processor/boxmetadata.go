@@ -0,0 +1,107 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/curtbushko/zoom-to-box/internal/logging"
+	"github.com/curtbushko/zoom-to-box/internal/storage"
+	"github.com/curtbushko/zoom-to-box/internal/zoom"
+)
+
+// metadataFields are the recording fields a metadata template key can be mapped to
+var metadataFields = map[string]func(recording *zoom.Recording, file *zoom.RecordingFile) interface{}{
+	"uuid":            func(r *zoom.Recording, f *zoom.RecordingFile) interface{} { return r.UUID },
+	"meeting_id":      func(r *zoom.Recording, f *zoom.RecordingFile) interface{} { return fmt.Sprintf("%d", r.ID) },
+	"topic":           func(r *zoom.Recording, f *zoom.RecordingFile) interface{} { return r.Topic },
+	"host_id":         func(r *zoom.Recording, f *zoom.RecordingFile) interface{} { return r.HostID },
+	"host_email":      func(r *zoom.Recording, f *zoom.RecordingFile) interface{} { return r.HostEmail },
+	"account_id":      func(r *zoom.Recording, f *zoom.RecordingFile) interface{} { return r.AccountID },
+	"start_time":      func(r *zoom.Recording, f *zoom.RecordingFile) interface{} { return metadataTime(r.StartTime) },
+	"duration":        func(r *zoom.Recording, f *zoom.RecordingFile) interface{} { return r.Duration },
+	"recording_start": func(r *zoom.Recording, f *zoom.RecordingFile) interface{} { return metadataTime(f.RecordingStart) },
+	"recording_end":   func(r *zoom.Recording, f *zoom.RecordingFile) interface{} { return metadataTime(f.RecordingEnd) },
+	"file_type":       func(r *zoom.Recording, f *zoom.RecordingFile) interface{} { return f.FileType },
+	"file_size":       func(r *zoom.Recording, f *zoom.RecordingFile) interface{} { return f.FileSize },
+	"recording_type":  func(r *zoom.Recording, f *zoom.RecordingFile) interface{} { return f.RecordingType },
+}
+
+// MetadataFieldNames returns the recording fields a metadata template key can be mapped to
+func MetadataFieldNames() []string {
+	names := make([]string, 0, len(metadataFields))
+	for name := range metadataFields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// MetadataTemplate attaches an instance of a destination metadata template to uploaded recordings
+type MetadataTemplate struct {
+	Scope       string            // Template scope, e.g. "enterprise"
+	TemplateKey string            // Template key, e.g. "zoomRecording"
+	Fields      map[string]string // Template key → recording field (see MetadataFieldNames)
+}
+
+// NewMetadataTemplate checks that every template key maps to a known recording field
+// It returns nil when no template is configured.
+func NewMetadataTemplate(scope, templateKey string, fields map[string]string) (*MetadataTemplate, error) {
+	if templateKey == "" {
+		return nil, nil
+	}
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("metadata template %s has no fields", templateKey)
+	}
+	for key, field := range fields {
+		if _, ok := metadataFields[strings.ToLower(field)]; !ok {
+			return nil, fmt.Errorf("metadata template key %s: unknown recording field %q (expected one of: %s)",
+				key, field, strings.Join(MetadataFieldNames(), ", "))
+		}
+	}
+	return &MetadataTemplate{Scope: scope, TemplateKey: templateKey, Fields: fields}, nil
+}
+
+// Values returns the template instance values for a recording file
+func (t *MetadataTemplate) Values(recording *zoom.Recording, file *zoom.RecordingFile) map[string]interface{} {
+	values := make(map[string]interface{}, len(t.Fields))
+	for key, field := range t.Fields {
+		values[key] = metadataFields[strings.ToLower(field)](recording, file)
+	}
+	return values
+}
+
+// metadataTime formats t for metadata date fields, which require RFC 3339 timestamps
+func metadataTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// applyMetadataTemplate attaches the configured metadata template to an uploaded recording
+// A failure is logged only: the recording itself is in the destination.
+func (p *userProcessorImpl) applyMetadataTemplate(ctx context.Context, upload *uploadResult, job *fileJob) {
+	template := p.config.MetadataTemplate
+	if template == nil || upload == nil || upload.FileID == "" {
+		return
+	}
+	applier, ok := p.destination.(storage.MetadataApplier)
+	if !ok {
+		return
+	}
+
+	logger := logging.GetDefaultLogger()
+	values := template.Values(job.recording, &job.recordingFile)
+	if err := applier.ApplyMetadata(ctx, upload.FileID, template.Scope, template.TemplateKey, values); err != nil {
+		if logger != nil {
+			logger.WarnWithContext(ctx, fmt.Sprintf("Failed to apply metadata template %s to %s: %v", template.TemplateKey, job.filename, err))
+		}
+		return
+	}
+	if logger != nil {
+		logger.DebugWithContext(ctx, fmt.Sprintf("Applied metadata template %s to %s", template.TemplateKey, job.filename))
+	}
+}
@@ -0,0 +1,106 @@
+package processor
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/curtbushko/zoom-to-box/internal/directory"
+	"github.com/curtbushko/zoom-to-box/internal/filename"
+	"github.com/curtbushko/zoom-to-box/internal/users"
+	"github.com/curtbushko/zoom-to-box/internal/zoom"
+)
+
+func TestNewMetadataTemplate(t *testing.T) {
+	if template, err := NewMetadataTemplate("", "", nil); template != nil || err != nil {
+		t.Errorf("Expected no template without a template key, got %v (%v)", template, err)
+	}
+	if _, err := NewMetadataTemplate("enterprise", "zoomRecording", nil); err == nil {
+		t.Error("Expected an error for a template without fields")
+	}
+	_, err := NewMetadataTemplate("enterprise", "zoomRecording", map[string]string{"host": "host_name"})
+	if err == nil || !strings.Contains(err.Error(), `unknown recording field "host_name"`) {
+		t.Errorf("Expected an unknown field error, got %v", err)
+	}
+}
+
+func TestMetadataTemplate_Values(t *testing.T) {
+	template, err := NewMetadataTemplate("enterprise", "zoomRecording", map[string]string{
+		"meetingUuid": "uuid",
+		"host":        "host_email",
+		"startTime":   "start_time",
+		"duration":    "duration",
+		"fileType":    "FILE_TYPE",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Date(2024, 1, 15, 10, 30, 0, 0, time.FixedZone("EST", -5*3600))
+	recording := &zoom.Recording{UUID: "abc==", HostEmail: "jane@example.com", StartTime: start, Duration: 45}
+	values := template.Values(recording, &zoom.RecordingFile{FileType: "MP4"})
+
+	expected := map[string]interface{}{
+		"meetingUuid": "abc==",
+		"host":        "jane@example.com",
+		"startTime":   "2024-01-15T15:30:00Z",
+		"duration":    45,
+		"fileType":    "MP4",
+	}
+	for key, want := range expected {
+		if values[key] != want {
+			t.Errorf("Expected %s = %v, got %v", key, want, values[key])
+		}
+	}
+}
+
+func TestUserProcessor_MetadataTemplate(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	zoomClient := newMockZoomClient()
+	boxClient := newMockBoxClient()
+	uploadManager := newMockUploadManager(boxClient)
+	zoomClient.recordings["jane.smith@example.com"] = []*zoom.Recording{
+		{
+			UUID:      "test-uuid-789",
+			Topic:     "Tagged Meeting",
+			StartTime: time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
+			RecordingFiles: []zoom.RecordingFile{
+				{ID: "file-789", FileType: "MP4", DownloadURL: "https://zoom.us/download/tagged.mp4", FileSize: 2048000},
+			},
+		},
+	}
+
+	template, err := NewMetadataTemplate("enterprise", "zoomRecording", map[string]string{"meetingUuid": "uuid"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	userManager, _ := users.NewActiveUserManager(users.ActiveUserConfig{})
+	processor := NewUserProcessor(
+		zoomClient,
+		newMockDownloadManager(),
+		directory.NewDirectoryManager(directory.DirectoryConfig{BaseDirectory: tmpDir, CreateDirs: true}, userManager),
+		filename.NewFileSanitizer(filename.FileSanitizerOptions{}),
+		uploadManager,
+		ProcessorConfig{BaseDownloadDir: tmpDir, BoxEnabled: true, MetadataTemplate: template},
+	)
+
+	if _, err := processor.ProcessUser(context.Background(), "jane.smith@example.com", "jane.smith@example.com"); err != nil {
+		t.Fatalf("ProcessUser failed: %v", err)
+	}
+
+	var applied int
+	for fileID, values := range boxClient.metadata {
+		if !strings.HasSuffix(fileID, ".mp4") {
+			t.Errorf("Expected metadata on the recording only, got %s", fileID)
+		}
+		if values["meetingUuid"] != "test-uuid-789" {
+			t.Errorf("Unexpected metadata values %v", values)
+		}
+		applied++
+	}
+	if applied != 1 {
+		t.Errorf("Expected the template applied to 1 file, got %d", applied)
+	}
+}
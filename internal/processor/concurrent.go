@@ -0,0 +1,69 @@
+package processor
+
+import (
+	"context"
+	"time"
+
+	"github.com/curtbushko/zoom-to-box/internal/pipeline"
+	"github.com/curtbushko/zoom-to-box/internal/zoom"
+)
+
+// StageWait holds a file downloaded alongside others until the file before it is done, so uploads
+// and their CSV tracking stay in recording order. It only runs with concurrent downloads.
+const StageWait = "wait"
+
+// pendingFile is a recording file started by processRecordings whose result is not counted yet
+type pendingFile struct {
+	recording     *zoom.Recording
+	recordingFile zoom.RecordingFile
+	filterReason  string // Set for recordings skipped by the filter, which start no file
+	fileFiltered  bool   // filterReason applies to recordingFile only, not the whole recording
+
+	result   *recordingFileResult
+	done     chan struct{} // Closed once the file went through the pipeline
+	duration time.Duration
+}
+
+// downloadConcurrency returns how many recording files of a user are downloaded at the same time
+// A dry run downloads nothing, so it plans files one at a time.
+func (p *userProcessorImpl) downloadConcurrency() int {
+	if p.config.DryRun || p.config.ConcurrentDownloads < 1 {
+		return 1
+	}
+	return p.config.ConcurrentDownloads
+}
+
+// startFile runs a recording file through the file pipeline in the background
+// The file uploads only once previousDone is closed (nil = no file before it).
+func (p *userProcessorImpl) startFile(ctx context.Context, zoomEmail, boxEmail string, recording *zoom.Recording, recordingFile zoom.RecordingFile, previousDone <-chan struct{}, observer pipeline.Observer) *pendingFile {
+	job := &fileJob{
+		zoomEmail:     zoomEmail,
+		boxEmail:      boxEmail,
+		recording:     recording,
+		recordingFile: recordingFile,
+		result:        &recordingFileResult{},
+		previousDone:  previousDone,
+	}
+	file := &pendingFile{
+		recording:     recording,
+		recordingFile: recordingFile,
+		result:        job.result,
+		done:          make(chan struct{}),
+	}
+
+	go func() {
+		defer close(file.done)
+		startTime := time.Now()
+		p.runFileJob(ctx, p.filePipeline, job, observer)
+		file.duration = time.Since(startTime)
+	}()
+	return file
+}
+
+// waitStage waits until the file started before this one is done
+func (p *userProcessorImpl) waitStage(ctx context.Context, job *fileJob) error {
+	if job.previousDone != nil {
+		<-job.previousDone
+	}
+	return nil
+}
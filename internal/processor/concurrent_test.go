@@ -0,0 +1,115 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/curtbushko/zoom-to-box/internal/directory"
+	"github.com/curtbushko/zoom-to-box/internal/download"
+	"github.com/curtbushko/zoom-to-box/internal/filename"
+	"github.com/curtbushko/zoom-to-box/internal/users"
+	"github.com/curtbushko/zoom-to-box/internal/zoom"
+)
+
+// slowDownloadManager finishes earlier files last and records how many downloads overlap
+type slowDownloadManager struct {
+	mu       sync.Mutex
+	active   int
+	maxInUse int
+}
+
+func (m *slowDownloadManager) Download(ctx context.Context, req download.DownloadRequest, progressCallback download.ProgressCallback) (*download.DownloadResult, error) {
+	m.mu.Lock()
+	m.active++
+	m.maxInUse = max(m.maxInUse, m.active)
+	m.mu.Unlock()
+	defer func() {
+		m.mu.Lock()
+		m.active--
+		m.mu.Unlock()
+	}()
+
+	// Files are named after their index; file-0 takes longest
+	var index int
+	fmt.Sscanf(req.ID[strings.LastIndex(req.ID, "-")+1:], "%d", &index)
+	time.Sleep(time.Duration(5-index) * 20 * time.Millisecond)
+
+	if err := os.MkdirAll(filepath.Dir(req.Destination), 0755); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(req.Destination, []byte("test content"), 0644); err != nil {
+		return nil, err
+	}
+	return &download.DownloadResult{Success: true, BytesDownloaded: req.FileSize}, nil
+}
+
+func TestUserProcessor_ConcurrentDownloads(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	zoomClient := newMockZoomClient()
+	uploadManager := newMockUploadManager(newMockBoxClient())
+	downloadManager := &slowDownloadManager{}
+
+	var recordings []*zoom.Recording
+	for i := 0; i < 5; i++ {
+		recordings = append(recordings, &zoom.Recording{
+			UUID:      fmt.Sprintf("uuid-%d", i),
+			Topic:     fmt.Sprintf("Meeting %d", i),
+			StartTime: time.Date(2024, 1, 15, 10, i, 0, 0, time.UTC),
+			RecordingFiles: []zoom.RecordingFile{
+				{ID: fmt.Sprintf("file-%d", i), FileType: "MP4", DownloadURL: "https://zoom.us/download/file.mp4", FileSize: 1024},
+			},
+			DownloadAccessToken: "test-token",
+		})
+	}
+	zoomClient.recordings["jane.smith@example.com"] = recordings
+
+	userManager, _ := users.NewActiveUserManager(users.ActiveUserConfig{})
+	processor := NewUserProcessor(
+		zoomClient,
+		downloadManager,
+		directory.NewDirectoryManager(directory.DirectoryConfig{BaseDirectory: tmpDir, CreateDirs: true}, userManager),
+		filename.NewFileSanitizer(filename.FileSanitizerOptions{}),
+		uploadManager,
+		ProcessorConfig{BaseDownloadDir: tmpDir, BoxEnabled: true, ContinueOnError: true, ConcurrentDownloads: 3},
+	)
+
+	result, err := processor.ProcessUser(context.Background(), "jane.smith@example.com", "jane.smith@example.com")
+	if err != nil {
+		t.Fatalf("ProcessUser failed: %v", err)
+	}
+	if result.DownloadedCount != 5 || result.ErrorCount != 0 {
+		t.Fatalf("Expected 5 downloads without errors, got %d downloads and %v", result.DownloadedCount, result.Errors)
+	}
+	if downloadManager.maxInUse < 2 || downloadManager.maxInUse > 3 {
+		t.Errorf("Expected 2-3 downloads at the same time, got %d", downloadManager.maxInUse)
+	}
+
+	for i, file := range result.Files {
+		if file.FileID != fmt.Sprintf("file-%d", i) {
+			t.Errorf("Expected file-%d reported at position %d, got %s", i, i, file.FileID)
+		}
+	}
+
+	var tracked []string
+	for _, entry := range uploadManager.tracked {
+		if filepath.Ext(entry.FileName) == ".mp4" {
+			tracked = append(tracked, entry.FileName)
+		}
+	}
+	if len(tracked) != 5 {
+		t.Fatalf("Expected 5 tracked recordings, got %v", tracked)
+	}
+	for i, name := range tracked {
+		if !strings.Contains(name, fmt.Sprintf("meeting-%d", i)) {
+			t.Errorf("Expected uploads tracked in recording order, got %v", tracked)
+			break
+		}
+	}
+}
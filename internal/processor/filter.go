@@ -0,0 +1,78 @@
+package processor
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/curtbushko/zoom-to-box/internal/zoom"
+)
+
+// RecordingFilter selects which recordings are archived
+// The zero value processes every recording.
+type RecordingFilter struct {
+	MinDurationMinutes int            // Skip recordings shorter than this (0 = no minimum)
+	Topic              *regexp.Regexp // Only process recordings whose topic matches (nil = all topics)
+	ExcludeTopic       *regexp.Regexp // Skip recordings whose topic matches (nil = none)
+	MeetingTypes       []int          // Only process these Zoom meeting types (empty = all types)
+	MinFileSize        int64          // Skip recording files Zoom reports as smaller than this many bytes (0 = no minimum)
+}
+
+// NewRecordingFilter compiles a filter from its configuration values
+func NewRecordingFilter(minDurationMinutes int, topicRegex, excludeTopicRegex string, meetingTypes []int) (RecordingFilter, error) {
+	filter := RecordingFilter{
+		MinDurationMinutes: minDurationMinutes,
+		MeetingTypes:       meetingTypes,
+	}
+
+	var err error
+	if topicRegex != "" {
+		if filter.Topic, err = regexp.Compile(topicRegex); err != nil {
+			return RecordingFilter{}, fmt.Errorf("invalid topic regex: %w", err)
+		}
+	}
+	if excludeTopicRegex != "" {
+		if filter.ExcludeTopic, err = regexp.Compile(excludeTopicRegex); err != nil {
+			return RecordingFilter{}, fmt.Errorf("invalid exclude topic regex: %w", err)
+		}
+	}
+
+	return filter, nil
+}
+
+// SkipReason returns why the recording is filtered out, or "" if it should be processed
+func (f RecordingFilter) SkipReason(recording *zoom.Recording) string {
+	if f.MinDurationMinutes > 0 && recording.Duration < f.MinDurationMinutes {
+		return fmt.Sprintf("duration %d min is below the %d min minimum", recording.Duration, f.MinDurationMinutes)
+	}
+
+	if len(f.MeetingTypes) > 0 {
+		allowed := false
+		for _, meetingType := range f.MeetingTypes {
+			if recording.Type == meetingType {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return fmt.Sprintf("meeting type %d is not included", recording.Type)
+		}
+	}
+
+	if f.Topic != nil && !f.Topic.MatchString(recording.Topic) {
+		return "topic does not match the topic filter"
+	}
+	if f.ExcludeTopic != nil && f.ExcludeTopic.MatchString(recording.Topic) {
+		return "topic matches the exclude filter"
+	}
+
+	return ""
+}
+
+// SkipFileReason returns why the recording file is filtered out, or "" if it should be processed
+// Zoom occasionally lists zero-byte or stub files, which a minimum file size keeps out of the archive.
+func (f RecordingFilter) SkipFileReason(recordingFile zoom.RecordingFile) string {
+	if f.MinFileSize > 0 && recordingFile.FileSize < f.MinFileSize {
+		return fmt.Sprintf("file size %d bytes is below the %d byte minimum", recordingFile.FileSize, f.MinFileSize)
+	}
+	return ""
+}
@@ -0,0 +1,55 @@
+package processor
+
+import (
+	"testing"
+
+	"github.com/curtbushko/zoom-to-box/internal/zoom"
+)
+
+func TestRecordingFilter_SkipReason(t *testing.T) {
+	filter, err := NewRecordingFilter(5, `(?i)customer|all-hands`, `(?i)standup`, []int{2, 8})
+	if err != nil {
+		t.Fatalf("NewRecordingFilter failed: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		recording zoom.Recording
+		skipped   bool
+	}{
+		{"matching recording", zoom.Recording{Topic: "Customer Review", Duration: 45, Type: 2}, false},
+		{"too short", zoom.Recording{Topic: "Customer Review", Duration: 2, Type: 2}, true},
+		{"excluded meeting type", zoom.Recording{Topic: "Customer Review", Duration: 45, Type: 1}, true},
+		{"topic not included", zoom.Recording{Topic: "Lunch", Duration: 45, Type: 8}, true},
+		{"topic excluded", zoom.Recording{Topic: "All-Hands Standup", Duration: 45, Type: 8}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason := filter.SkipReason(&tt.recording)
+			if (reason != "") != tt.skipped {
+				t.Errorf("Expected skipped=%v, got reason %q", tt.skipped, reason)
+			}
+		})
+	}
+
+	if reason := (RecordingFilter{}).SkipReason(&zoom.Recording{Topic: "Anything"}); reason != "" {
+		t.Errorf("Expected the zero filter to keep every recording, got %q", reason)
+	}
+	if _, err := NewRecordingFilter(0, "(", "", nil); err == nil {
+		t.Error("Expected an error for an invalid topic regex")
+	}
+}
+
+func TestRecordingFilter_SkipFileReason(t *testing.T) {
+	filter := RecordingFilter{MinFileSize: 1024}
+	if reason := filter.SkipFileReason(zoom.RecordingFile{FileSize: 0}); reason == "" {
+		t.Error("Expected a zero-byte file to be skipped")
+	}
+	if reason := filter.SkipFileReason(zoom.RecordingFile{FileSize: 1024}); reason != "" {
+		t.Errorf("Expected a file at the minimum to be kept, got %q", reason)
+	}
+	if reason := (RecordingFilter{}).SkipFileReason(zoom.RecordingFile{}); reason != "" {
+		t.Errorf("Expected the zero filter to keep every file, got %q", reason)
+	}
+}
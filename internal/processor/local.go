@@ -0,0 +1,258 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/curtbushko/zoom-to-box/internal/atrest"
+	"github.com/curtbushko/zoom-to-box/internal/download"
+	"github.com/curtbushko/zoom-to-box/internal/email"
+	"github.com/curtbushko/zoom-to-box/internal/logging"
+	"github.com/curtbushko/zoom-to-box/internal/timefmt"
+	"github.com/curtbushko/zoom-to-box/internal/users"
+)
+
+// localFile is a file found in a user's layout folder below the upload root
+type localFile struct {
+	path       string
+	folderPath string // Folder in the destination, below the user's zoom folder
+	size       int64  // Plaintext size
+}
+
+// UploadLocal uploads the files already in root's layout folders without listing or
+// downloading recordings from Zoom, e.g. a tree downloaded by an older tool
+// Each file is uploaded into the folder the directory layout gives it and tracked like a
+// processed recording. Files outside the layout folders of the given users are skipped.
+func (p *userProcessorImpl) UploadLocal(ctx context.Context, root string, entries []users.UserEntry) (*ProcessorSummary, error) {
+	startTime := time.Now()
+	logger := logging.GetDefaultLogger()
+
+	if p.destination == nil {
+		return nil, fmt.Errorf("no upload destination is enabled")
+	}
+
+	p.routeUsers(ctx, entries)
+
+	byUsername := make(map[string]users.UserEntry)
+	var usernames []string
+	for _, entry := range entries {
+		username := email.ExtractUsername(entry.BoxEmail)
+		if _, ok := byUsername[username]; ok {
+			continue
+		}
+		byUsername[username] = entry
+		usernames = append(usernames, username)
+	}
+
+	files, unmatched, err := p.findLocalFiles(root, usernames)
+	if err != nil {
+		return nil, err
+	}
+	if unmatched > 0 && logger != nil {
+		logger.WarnWithContext(ctx, fmt.Sprintf("Skipping %d files outside the directory layout folders of the given users", unmatched))
+	}
+
+	summary := &ProcessorSummary{
+		TotalUsers:  len(usernames),
+		UserResults: make([]*ProcessorResult, 0, len(usernames)),
+	}
+	for _, username := range usernames {
+		if ctx.Err() != nil {
+			summary.Interrupted = true
+			break
+		}
+		if p.deadlineReached() {
+			summary.TimeBoxed = true
+			break
+		}
+
+		entry := byUsername[username]
+		if logger != nil {
+			logger.InfoWithContext(ctx, fmt.Sprintf("Uploading %d local files for user: %s → %s", len(files[username]), entry.ZoomEmail, entry.BoxEmail))
+		}
+		result := p.uploadLocalFiles(ctx, entry, files[username])
+		summary.UserResults = append(summary.UserResults, result)
+
+		summary.TotalUploads += result.UploadedCount
+		summary.TotalSkipped += result.SkippedCount
+		summary.TotalErrors += result.ErrorCount
+		summary.TotalDeleted += result.DeletedCount
+		summary.TotalBytesPlanned += result.BytesPlanned
+		if result.ErrorCount > 0 {
+			summary.FailedUsers++
+		} else {
+			summary.ProcessedUsers++
+		}
+
+		if result.Interrupted {
+			summary.Interrupted = true
+			break
+		}
+		if result.TimeBoxed {
+			summary.TimeBoxed = true
+			break
+		}
+		if result.ErrorCount > 0 && !p.config.ContinueOnError {
+			break
+		}
+	}
+
+	summary.Duration = time.Since(startTime)
+	return summary, ctx.Err()
+}
+
+// findLocalFiles walks root and groups the files in layout folders by username
+// unmatched counts the files that do not belong to any of usernames.
+func (p *userProcessorImpl) findLocalFiles(root string, usernames []string) (files map[string][]localFile, unmatched int, err error) {
+	files = make(map[string][]localFile)
+	err = filepath.WalkDir(root, func(filePath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !d.Type().IsRegular() || isLocalBookkeeping(d.Name()) || download.IsPartial(filePath) {
+			return nil
+		}
+		relDir, err := filepath.Rel(root, filepath.Dir(filePath))
+		if err != nil {
+			return err
+		}
+		username, folderPath, ok := p.directoryLayout.Owner(relDir, usernames)
+		if !ok {
+			unmatched++
+			return nil
+		}
+		size, _, err := atrest.Inspect(filePath)
+		if err != nil {
+			return err
+		}
+		files[username] = append(files[username], localFile{path: filePath, folderPath: folderPath, size: size})
+		return nil
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to scan %s: %w", root, err)
+	}
+
+	// Upload recordings in a stable order, oldest folders first
+	for _, userFiles := range files {
+		sort.Slice(userFiles, func(i, j int) bool { return userFiles[i].path < userFiles[j].path })
+	}
+	return files, unmatched, nil
+}
+
+// isLocalBookkeeping reports whether a file in the download tree is the tool's own state
+// rather than something to upload
+func isLocalBookkeeping(name string) bool {
+	return name == "uploads.csv" || name == MigrationReportFile || strings.HasPrefix(name, ".") || strings.HasSuffix(name, ".tmp")
+}
+
+// uploadLocalFiles uploads and tracks one user's local files
+func (p *userProcessorImpl) uploadLocalFiles(ctx context.Context, entry users.UserEntry, files []localFile) *ProcessorResult {
+	startTime := time.Now()
+	logger := logging.GetDefaultLogger()
+	result := &ProcessorResult{
+		ZoomEmail: entry.ZoomEmail,
+		BoxEmail:  entry.BoxEmail,
+		Errors:    make([]error, 0),
+	}
+
+	for _, file := range files {
+		if ctx.Err() != nil {
+			result.Interrupted = true
+			break
+		}
+		if p.deadlineReached() {
+			result.TimeBoxed = true
+			break
+		}
+
+		fileStartTime := time.Now()
+		outcome := FileOutcome{FileName: filepath.Base(file.path), LocalPath: file.path}
+
+		if p.config.DryRun {
+			if logger != nil {
+				logger.InfoWithContext(ctx, fmt.Sprintf("DRY RUN: Would upload %s to %s", file.path, file.folderPath))
+			}
+			result.UploadedCount++
+			result.BytesPlanned += file.size
+			outcome.Outcome = OutcomeUploaded
+			outcome.BytesPlanned = file.size
+			result.Files = append(result.Files, outcome)
+			continue
+		}
+
+		deleted, err := p.uploadLocalFile(ctx, entry, file, &outcome)
+		outcome.DurationSeconds = time.Since(fileStartTime).Seconds()
+		if err != nil {
+			if ctx.Err() != nil {
+				result.Interrupted = true
+				break
+			}
+			outcome.Outcome = OutcomeFailed
+			outcome.Error = err.Error()
+			result.Files = append(result.Files, outcome)
+			result.ErrorCount++
+			result.Errors = append(result.Errors, err)
+			if !p.config.ContinueOnError {
+				break
+			}
+			continue
+		}
+		if outcome.Outcome == OutcomeUploaded {
+			result.UploadedCount++
+		} else {
+			result.SkippedCount++
+		}
+		if deleted {
+			result.DeletedCount++
+		}
+		result.Files = append(result.Files, outcome)
+	}
+
+	result.Duration = time.Since(startTime)
+	return result
+}
+
+// uploadLocalFile uploads and tracks a single local file, deleting it afterwards if configured
+func (p *userProcessorImpl) uploadLocalFile(ctx context.Context, entry users.UserEntry, file localFile, outcome *FileOutcome) (deleted bool, err error) {
+	logger := logging.GetDefaultLogger()
+	name := outcome.FileName
+	startTime := time.Now()
+
+	// Files encrypted at rest are uploaded from a decrypted temporary copy
+	job := &fileJob{filePath: file.path}
+	uploadPath, err := p.plaintextPath(job)
+	if err != nil {
+		return false, fmt.Errorf("failed to decrypt %s for upload: %w", name, err)
+	}
+	if job.plainPath != "" {
+		defer os.RemoveAll(filepath.Dir(job.plainPath))
+	}
+
+	upload, err := p.uploadToFolder(ctx, uploadPath, entry.ZoomEmail, entry.BoxEmail, file.folderPath, nil)
+	if err != nil {
+		return false, err
+	}
+	p.destination.TrackUploadWithTime(entry.ZoomEmail, name, file.size, timefmt.Now(), time.Since(startTime))
+	outcome.Outcome = OutcomeUploaded
+	if upload.Skipped {
+		outcome.Outcome = OutcomeSkipped
+	}
+
+	if p.config.DeleteAfterUpload && p.deletionVerified(ctx, upload, uploadPath) {
+		if err := os.Remove(file.path); err != nil {
+			if logger != nil {
+				logger.ErrorWithContext(ctx, fmt.Sprintf("Failed to delete file after upload: %s - %v", file.path, err))
+			}
+			return false, nil
+		}
+		outcome.Deleted = true
+		return true, nil
+	}
+	return false, nil
+}
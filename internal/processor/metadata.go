@@ -0,0 +1,103 @@
+package processor
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Built-in metadata sidecar formats
+const (
+	MetadataFormatJSON = "json" // Pretty-printed JSON (default)
+	MetadataFormatYAML = "yaml"
+	MetadataFormatNone = "none" // No sidecar is saved or uploaded
+)
+
+// MetadataSerializer writes and reads the metadata sidecar saved next to each recording
+type MetadataSerializer interface {
+	// Extension returns the sidecar's file extension, e.g. ".json"
+	Extension() string
+	// Marshal encodes recording metadata
+	Marshal(metadata map[string]interface{}) ([]byte, error)
+	// Unmarshal decodes a sidecar written by Marshal
+	Unmarshal(data []byte) (map[string]interface{}, error)
+}
+
+var (
+	metadataSerializersMu sync.RWMutex
+	metadataSerializers   = map[string]MetadataSerializer{
+		MetadataFormatJSON: jsonMetadata{},
+		MetadataFormatYAML: yamlMetadata{},
+	}
+)
+
+// RegisterMetadataSerializer makes serializer available as metadata.format: format
+// Registering a built-in format replaces it.
+func RegisterMetadataSerializer(format string, serializer MetadataSerializer) {
+	metadataSerializersMu.Lock()
+	defer metadataSerializersMu.Unlock()
+	metadataSerializers[strings.ToLower(format)] = serializer
+}
+
+// MetadataSerializerFor returns the serializer of a metadata format ("" = json)
+// The "none" format returns a nil serializer: no sidecar is written.
+func MetadataSerializerFor(format string) (MetadataSerializer, error) {
+	format = strings.ToLower(format)
+	if format == "" {
+		format = MetadataFormatJSON
+	}
+	if format == MetadataFormatNone {
+		return nil, nil
+	}
+
+	metadataSerializersMu.RLock()
+	defer metadataSerializersMu.RUnlock()
+	serializer, ok := metadataSerializers[format]
+	if !ok {
+		formats := []string{MetadataFormatNone}
+		for name := range metadataSerializers {
+			formats = append(formats, name)
+		}
+		sort.Strings(formats)
+		return nil, fmt.Errorf("unknown metadata format %q (supported: %s)", format, strings.Join(formats, ", "))
+	}
+	return serializer, nil
+}
+
+// jsonMetadata writes metadata sidecars as indented JSON
+type jsonMetadata struct{}
+
+func (jsonMetadata) Extension() string { return ".json" }
+
+func (jsonMetadata) Marshal(metadata map[string]interface{}) ([]byte, error) {
+	return json.MarshalIndent(metadata, "", "  ")
+}
+
+func (jsonMetadata) Unmarshal(data []byte) (map[string]interface{}, error) {
+	var metadata map[string]interface{}
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return nil, err
+	}
+	return metadata, nil
+}
+
+// yamlMetadata writes metadata sidecars as YAML
+type yamlMetadata struct{}
+
+func (yamlMetadata) Extension() string { return ".yaml" }
+
+func (yamlMetadata) Marshal(metadata map[string]interface{}) ([]byte, error) {
+	return yaml.Marshal(metadata)
+}
+
+func (yamlMetadata) Unmarshal(data []byte) (map[string]interface{}, error) {
+	var metadata map[string]interface{}
+	if err := yaml.Unmarshal(data, &metadata); err != nil {
+		return nil, err
+	}
+	return metadata, nil
+}
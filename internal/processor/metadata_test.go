@@ -0,0 +1,76 @@
+package processor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/curtbushko/zoom-to-box/internal/zoom"
+)
+
+func TestMetadataSerializerFor(t *testing.T) {
+	tests := []struct {
+		format    string
+		extension string // "" = no sidecar
+		wantErr   bool
+	}{
+		{format: "", extension: ".json"},
+		{format: "json", extension: ".json"},
+		{format: "YAML", extension: ".yaml"},
+		{format: "none"},
+		{format: "xml", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		serializer, err := MetadataSerializerFor(tt.format)
+		if tt.wantErr {
+			if err == nil || !strings.Contains(err.Error(), "json, none, yaml") {
+				t.Errorf("%q: expected an error listing the formats, got %v", tt.format, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%q: MetadataSerializerFor failed: %v", tt.format, err)
+		}
+		if tt.extension == "" {
+			if serializer != nil {
+				t.Errorf("%q: expected no serializer, got %T", tt.format, serializer)
+			}
+			continue
+		}
+		if serializer == nil || serializer.Extension() != tt.extension {
+			t.Errorf("%q: expected a %s serializer, got %v", tt.format, tt.extension, serializer)
+		}
+	}
+}
+
+func TestSaveRecordingMetadata_YAML(t *testing.T) {
+	metadataPath := filepath.Join(t.TempDir(), "meeting.yaml")
+	recording := &zoom.Recording{UUID: "test-uuid", Topic: "YAML Meeting", StartTime: time.Date(2024, 3, 1, 14, 0, 0, 0, time.UTC)}
+	recordingFile := &zoom.RecordingFile{ID: "file-1", FileType: "MP4"}
+
+	serializer := yamlMetadata{}
+	if err := saveRecordingMetadata(context.Background(), serializer, recording, recordingFile, "", "", nil, metadataPath); err != nil {
+		t.Fatalf("saveRecordingMetadata failed: %v", err)
+	}
+	if err := addSharedLinkToMetadata(serializer, metadataPath, "https://app.box.com/s/abc", "company"); err != nil {
+		t.Fatalf("addSharedLinkToMetadata failed: %v", err)
+	}
+
+	data, err := os.ReadFile(metadataPath)
+	if err != nil {
+		t.Fatalf("Failed to read metadata: %v", err)
+	}
+	metadata, err := serializer.Unmarshal(data)
+	if err != nil {
+		t.Fatalf("Expected YAML metadata, got %v:\n%s", err, data)
+	}
+	meeting, _ := metadata["meeting"].(map[string]interface{})
+	sharedLink, _ := metadata["shared_link"].(map[string]interface{})
+	if meeting["topic"] != "YAML Meeting" || sharedLink["url"] != "https://app.box.com/s/abc" {
+		t.Errorf("Expected the meeting and shared link in the YAML metadata, got %v", metadata)
+	}
+}
@@ -0,0 +1,177 @@
+package processor
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/curtbushko/zoom-to-box/internal/email"
+	"github.com/curtbushko/zoom-to-box/internal/logging"
+	"github.com/curtbushko/zoom-to-box/internal/progress"
+	"github.com/curtbushko/zoom-to-box/internal/storage"
+	"github.com/curtbushko/zoom-to-box/internal/timefmt"
+	"github.com/curtbushko/zoom-to-box/internal/tracking"
+	"github.com/curtbushko/zoom-to-box/internal/zoom"
+)
+
+// MigrationReportFile is the report uploaded to each user's zoom folder with MigrationReport
+const MigrationReportFile = "MIGRATION-REPORT.md"
+
+// migrationReport is a summary of a user's migration that the user can read to check it
+type migrationReport struct {
+	ZoomEmail   string
+	GeneratedAt time.Time
+	From, To    *time.Time // Recording dates searched (nil = Zoom's default range)
+
+	Recordings     int       // Recordings found in Zoom by the run
+	Oldest, Newest time.Time // Start times of the oldest and newest recording found
+
+	Uploads []tracking.UploadEntry // Every file in the user's uploads.csv
+	Gaps    []FileOutcome          // Files of the run that failed or were filtered out
+}
+
+// newMigrationReport summarizes the run's recordings and result with the uploads of all runs
+func newMigrationReport(zoomEmail string, params zoom.ListRecordingsParams, recordings []*zoom.Recording, result *ProcessorResult, uploads []tracking.UploadEntry) migrationReport {
+	report := migrationReport{
+		ZoomEmail:   zoomEmail,
+		GeneratedAt: timefmt.Now(),
+		From:        params.From,
+		To:          params.To,
+		Recordings:  len(recordings),
+		Uploads:     uploads,
+	}
+	for _, recording := range recordings {
+		if report.Oldest.IsZero() || recording.StartTime.Before(report.Oldest) {
+			report.Oldest = recording.StartTime
+		}
+		if recording.StartTime.After(report.Newest) {
+			report.Newest = recording.StartTime
+		}
+	}
+	for _, file := range result.Files {
+		if file.Outcome == OutcomeFailed || file.Outcome == OutcomeFiltered {
+			report.Gaps = append(report.Gaps, file)
+		}
+	}
+	return report
+}
+
+// Markdown renders the report
+func (r migrationReport) Markdown() []byte {
+	var b bytes.Buffer
+	var total int64
+	for _, upload := range r.Uploads {
+		total += upload.RecordingSize
+	}
+
+	fmt.Fprintf(&b, "# Zoom recording migration report\n\n")
+	fmt.Fprintf(&b, "Zoom account: %s  \nGenerated: %s\n\n", r.ZoomEmail, timefmt.Format(r.GeneratedAt))
+
+	fmt.Fprintf(&b, "## Summary\n\n")
+	fmt.Fprintf(&b, "- Dates searched: %s to %s\n", reportDate(r.From, "the start of the Zoom account"), reportDate(r.To, "today"))
+	if r.Recordings > 0 {
+		fmt.Fprintf(&b, "- Recordings found in Zoom: %d, from %s to %s\n", r.Recordings,
+			timefmt.In(r.Oldest).Format("2006-01-02"), timefmt.In(r.Newest).Format("2006-01-02"))
+	} else {
+		fmt.Fprintf(&b, "- Recordings found in Zoom: none\n")
+	}
+	fmt.Fprintf(&b, "- Files in this folder: %d (%s)\n", len(r.Uploads), progress.FormatBytes(total))
+	fmt.Fprintf(&b, "- Known gaps: %d\n\n", len(r.Gaps))
+
+	fmt.Fprintf(&b, "## Known gaps\n\n")
+	if len(r.Gaps) == 0 {
+		fmt.Fprintf(&b, "None. Every recording file found in Zoom is in this folder.\n\n")
+	}
+	for _, gap := range r.Gaps {
+		name := gap.FileName
+		if name == "" {
+			name = gap.Topic
+		}
+		if gap.Outcome == OutcomeFiltered {
+			fmt.Fprintf(&b, "- %s (%s): not migrated, %s\n", markdownText(name), gap.FileType, gap.Reason)
+		} else {
+			fmt.Fprintf(&b, "- %s (%s): could not be migrated yet, it is tried again on the next run\n", markdownText(name), gap.FileType)
+		}
+	}
+	if len(r.Gaps) > 0 {
+		b.WriteString("\n")
+	}
+
+	fmt.Fprintf(&b, "## Migrated files\n\n")
+	if len(r.Uploads) == 0 {
+		fmt.Fprintf(&b, "None yet.\n")
+		return b.Bytes()
+	}
+	fmt.Fprintf(&b, "| File | Size | Uploaded |\n|---|---|---|\n")
+	for _, upload := range r.Uploads {
+		uploaded := ""
+		if !upload.UploadDate.IsZero() {
+			uploaded = timefmt.Format(upload.UploadDate)
+		}
+		fmt.Fprintf(&b, "| %s | %s | %s |\n", markdownText(upload.FileName), progress.FormatBytes(upload.RecordingSize), uploaded)
+	}
+	return b.Bytes()
+}
+
+// reportDate formats an optional date of the report, or returns unset
+func reportDate(date *time.Time, unset string) string {
+	if date == nil {
+		return unset
+	}
+	return timefmt.In(*date).Format("2006-01-02")
+}
+
+// markdownText escapes the characters of a file name or topic that Markdown would interpret
+func markdownText(text string) string {
+	return strings.NewReplacer(`\`, `\\`, "|", `\|`, "*", `\*`, "_", `\_`, "`", "\\`", "[", `\[`, "]", `\]`, "<", "&lt;").Replace(text)
+}
+
+// uploadMigrationReport writes the user's migration report next to uploads.csv and uploads it
+// to the root of their zoom folder, replacing the report of an earlier run
+func (p *userProcessorImpl) uploadMigrationReport(ctx context.Context, zoomEmail, boxEmail string, recordings []*zoom.Recording, result *ProcessorResult) error {
+	logger := logging.GetDefaultLogger()
+
+	username := email.ExtractUsername(boxEmail)
+	if username == "" {
+		return fmt.Errorf("invalid box email format: %s", boxEmail)
+	}
+	userDir := filepath.Join(p.config.BaseDownloadDir, username)
+
+	var uploads []tracking.UploadEntry
+	if _, err := os.Stat(filepath.Join(userDir, "uploads.csv")); err == nil {
+		entries, err := tracking.ReadUploads(filepath.Join(userDir, "uploads.csv"))
+		if err != nil {
+			return err
+		}
+		uploads = entries
+	}
+
+	report := newMigrationReport(zoomEmail, p.recordingParams(), recordings, result, uploads)
+	if err := os.MkdirAll(userDir, 0755); err != nil {
+		return fmt.Errorf("failed to create user directory: %w", err)
+	}
+	reportPath := filepath.Join(userDir, MigrationReportFile)
+	if err := os.WriteFile(reportPath, report.Markdown(), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", MigrationReportFile, err)
+	}
+
+	file, err := p.destination.UploadFile(ctx, storage.UploadRequest{
+		LocalPath: reportPath,
+		ZoomEmail: zoomEmail,
+		UserEmail: boxEmail,
+		FileName:  MigrationReportFile,
+		Overwrite: true,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upload %s: %w", MigrationReportFile, err)
+	}
+
+	if logger != nil {
+		logger.InfoWithContext(ctx, fmt.Sprintf("Uploaded %s to %s for user %s (file ID: %s)", MigrationReportFile, p.destination.Name(), zoomEmail, file.FileID))
+	}
+	return nil
+}
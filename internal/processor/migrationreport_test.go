@@ -0,0 +1,60 @@
+package processor
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/curtbushko/zoom-to-box/internal/tracking"
+	"github.com/curtbushko/zoom-to-box/internal/zoom"
+)
+
+func TestMigrationReport_Markdown(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	recordings := []*zoom.Recording{
+		{UUID: "uuid-2", StartTime: time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC)},
+		{UUID: "uuid-1", StartTime: time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)},
+	}
+	result := &ProcessorResult{
+		Files: []FileOutcome{
+			{FileType: "MP4", FileName: "standup.mp4", Outcome: OutcomeUploaded},
+			{FileType: "MP4", FileName: "retro.mp4", Outcome: OutcomeFailed, Error: "quota exceeded"},
+			{FileType: "CHAT", FileName: "retro.txt", Outcome: OutcomeFiltered, Reason: "file type CHAT is not included"},
+		},
+	}
+	uploads := []tracking.UploadEntry{
+		{ZoomUser: "jane", FileName: "team|standup.mp4", RecordingSize: 2048, UploadDate: time.Date(2024, 3, 6, 9, 0, 0, 0, time.UTC)},
+	}
+
+	report := newMigrationReport("jane@example.com", zoom.ListRecordingsParams{From: &from}, recordings, result, uploads)
+	markdown := string(report.Markdown())
+
+	for _, want := range []string{
+		"Zoom account: jane@example.com",
+		"Dates searched: 2024-01-01 to today",
+		"Recordings found in Zoom: 2, from 2024-01-15 to 2024-03-05",
+		"Files in this folder: 1",
+		"Known gaps: 2",
+		"retro.mp4 (MP4): could not be migrated yet",
+		"retro.txt (CHAT): not migrated, file type CHAT is not included",
+		`| team\|standup.mp4 |`,
+	} {
+		if !strings.Contains(markdown, want) {
+			t.Errorf("Expected the report to contain %q, got:\n%s", want, markdown)
+		}
+	}
+	if strings.Contains(markdown, "quota exceeded") {
+		t.Errorf("Expected no raw error text in the report, got:\n%s", markdown)
+	}
+}
+
+func TestMigrationReport_NoGaps(t *testing.T) {
+	report := newMigrationReport("jane@example.com", zoom.ListRecordingsParams{}, nil, &ProcessorResult{}, nil)
+	markdown := string(report.Markdown())
+
+	for _, want := range []string{"Recordings found in Zoom: none", "Known gaps: 0", "None yet."} {
+		if !strings.Contains(markdown, want) {
+			t.Errorf("Expected the report to contain %q, got:\n%s", want, markdown)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/curtbushko/zoom-to-box/internal/logging"
+	"github.com/curtbushko/zoom-to-box/internal/zoom"
+)
+
+// meetingDetails holds who hosted and attended a recorded meeting, for its metadata sidecar
+type meetingDetails struct {
+	Host         *zoom.User         // nil when the host could not be looked up
+	Participants []zoom.Participant // nil when the participants could not be looked up
+}
+
+// lookupMeetingDetails returns the host and participants of recording with IncludeParticipants set
+// Lookups that fail are logged and left out of the metadata. Results are kept per meeting and
+// host, so the files of a recording and the recordings of a host are looked up once.
+func (p *userProcessorImpl) lookupMeetingDetails(ctx context.Context, recording *zoom.Recording) *meetingDetails {
+	if !p.config.IncludeParticipants {
+		return nil
+	}
+	logger := logging.GetDefaultLogger()
+
+	client, ok := p.zoomClient.(zoom.ParticipantClient)
+	if !ok {
+		if logger != nil {
+			logger.WarnWithContext(ctx, "Zoom client does not support participant lookups, saving metadata without them")
+		}
+		return nil
+	}
+
+	p.detailsMu.Lock()
+	defer p.detailsMu.Unlock()
+	if p.participants == nil {
+		p.participants = make(map[string][]zoom.Participant)
+		p.hosts = make(map[string]*zoom.User)
+	}
+
+	details := &meetingDetails{}
+
+	participants, ok := p.participants[recording.UUID]
+	if !ok {
+		var err error
+		participants, err = client.ListPastMeetingParticipants(ctx, recording.UUID)
+		if err != nil {
+			participants = nil
+			if logger != nil {
+				logger.WarnWithContext(ctx, fmt.Sprintf("Failed to get participants of %s: %v", recording.Topic, err))
+			}
+		}
+		p.participants[recording.UUID] = participants
+	}
+	details.Participants = participants
+
+	if recording.HostID != "" {
+		host, ok := p.hosts[recording.HostID]
+		if !ok {
+			var err error
+			host, err = client.GetUser(ctx, recording.HostID)
+			if err != nil {
+				host = nil
+				if logger != nil {
+					logger.WarnWithContext(ctx, fmt.Sprintf("Failed to get host %s of %s: %v", recording.HostID, recording.Topic, err))
+				}
+			}
+			p.hosts[recording.HostID] = host
+		}
+		details.Host = host
+	}
+
+	return details
+}
+
+// addMeetingDetails adds the host and participants in details to a recording's metadata
+func addMeetingDetails(metadata map[string]interface{}, details *meetingDetails) {
+	if details == nil {
+		return
+	}
+
+	if details.Host != nil {
+		displayName := details.Host.DisplayName
+		if displayName == "" {
+			displayName = strings.TrimSpace(details.Host.FirstName + " " + details.Host.LastName)
+		}
+		metadata["host"] = map[string]interface{}{
+			"id":           details.Host.ID,
+			"email":        details.Host.Email,
+			"display_name": displayName,
+		}
+	}
+
+	if details.Participants != nil {
+		participants := make([]map[string]interface{}, 0, len(details.Participants))
+		for _, participant := range details.Participants {
+			participants = append(participants, map[string]interface{}{
+				"name":       participant.Name,
+				"email":      participant.UserEmail,
+				"join_time":  participant.JoinTime,
+				"leave_time": participant.LeaveTime,
+				"duration":   participant.Duration,
+			})
+		}
+		metadata["participants"] = participants
+	}
+}
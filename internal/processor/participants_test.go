@@ -0,0 +1,81 @@
+package processor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/curtbushko/zoom-to-box/internal/filename"
+	"github.com/curtbushko/zoom-to-box/internal/zoom"
+)
+
+// mockParticipantZoomClient is a mockZoomClient that also looks up meeting participants and hosts
+type mockParticipantZoomClient struct {
+	*mockZoomClient
+	participants map[string][]zoom.Participant
+	lookups      int
+}
+
+func (m *mockParticipantZoomClient) ListPastMeetingParticipants(ctx context.Context, meetingUUID string) ([]zoom.Participant, error) {
+	m.lookups++
+	participants, ok := m.participants[meetingUUID]
+	if !ok {
+		return nil, errors.New("meeting does not exist")
+	}
+	return participants, nil
+}
+
+func (m *mockParticipantZoomClient) GetUser(ctx context.Context, userID string) (*zoom.User, error) {
+	m.lookups++
+	return &zoom.User{ID: userID, Email: "host@example.com", FirstName: "Host", LastName: "Person"}, nil
+}
+
+func TestSaveRecordingMetadata_IncludesParticipants(t *testing.T) {
+	zoomClient := &mockParticipantZoomClient{
+		mockZoomClient: newMockZoomClient(),
+		participants: map[string][]zoom.Participant{
+			"uuid-1": {{Name: "Jane Smith", UserEmail: "jane@example.com", Duration: 1800}, {Name: "Guest Caller", Duration: 60}},
+		},
+	}
+	p := NewUserProcessor(zoomClient, newMockDownloadManager(), nil, filename.NewFileSanitizer(filename.FileSanitizerOptions{}), nil,
+		ProcessorConfig{BaseDownloadDir: t.TempDir(), IncludeParticipants: true}).(*userProcessorImpl)
+
+	recording := &zoom.Recording{UUID: "uuid-1", HostID: "host-id", Topic: "Team Sync"}
+	details := p.lookupMeetingDetails(context.Background(), recording)
+	if p.lookupMeetingDetails(context.Background(), recording); zoomClient.lookups != 2 {
+		t.Errorf("Expected one participant and one host lookup per meeting, got %d lookups", zoomClient.lookups)
+	}
+
+	metadataPath := filepath.Join(t.TempDir(), "team-sync.json")
+	recordingFile := &zoom.RecordingFile{ID: "file-1", FileType: "MP4"}
+	if err := saveRecordingMetadata(context.Background(), jsonMetadata{}, recording, recordingFile, "", "", details, metadataPath); err != nil {
+		t.Fatalf("saveRecordingMetadata failed: %v", err)
+	}
+
+	data, err := os.ReadFile(metadataPath)
+	if err != nil {
+		t.Fatalf("Failed to read metadata: %v", err)
+	}
+	var metadata struct {
+		Host         map[string]string        `json:"host"`
+		Participants []map[string]interface{} `json:"participants"`
+	}
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		t.Fatalf("Failed to parse metadata: %v", err)
+	}
+	if metadata.Host["display_name"] != "Host Person" || metadata.Host["email"] != "host@example.com" {
+		t.Errorf("Expected the host in the metadata, got %v", metadata.Host)
+	}
+	if len(metadata.Participants) != 2 || metadata.Participants[0]["email"] != "jane@example.com" || metadata.Participants[1]["name"] != "Guest Caller" {
+		t.Errorf("Expected both participants in the metadata, got %v", metadata.Participants)
+	}
+
+	// A failed participant lookup leaves the participants out
+	details = p.lookupMeetingDetails(context.Background(), &zoom.Recording{UUID: "uuid-unknown", HostID: "host-id"})
+	if details.Participants != nil || details.Host == nil {
+		t.Errorf("Expected only the host for a meeting without participants, got %+v", details)
+	}
+}
@@ -0,0 +1,133 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/curtbushko/zoom-to-box/internal/download"
+	"github.com/curtbushko/zoom-to-box/internal/logging"
+	"github.com/curtbushko/zoom-to-box/internal/zoom"
+)
+
+// RecordingPasscodeSetter is implemented by Zoom clients that can read and change the passcode
+// of a meeting's recordings
+type RecordingPasscodeSetter interface {
+	GetRecordingSettings(ctx context.Context, meetingUUID string) (*zoom.RecordingSettings, error)
+	SetRecordingPassword(ctx context.Context, meetingUUID, password string) error
+}
+
+// downloadWithoutPasscode retries a download Zoom refused with its passcode page, for
+// DisablePasscodes: the passcode of the recording's meeting is saved in the status tracker, removed
+// for the retry and set again afterwards; a run that stops before then leaves it to
+// RestorePendingPasscodes. Without a status tracker to save it in, or when the passcode cannot be
+// removed, the refused download's error is returned. Passcode downloads run one at a time, so
+// concurrent files of a meeting never see each other's removed passcode as the one to restore.
+func (p *userProcessorImpl) downloadWithoutPasscode(ctx context.Context, recording *zoom.Recording, refused error, retry func() (*download.DownloadResult, error)) (*download.DownloadResult, error) {
+	logger := logging.GetDefaultLogger()
+
+	setter, ok := p.zoomClient.(RecordingPasscodeSetter)
+	if !ok {
+		if logger != nil {
+			logger.WarnWithContext(ctx, "Zoom client does not support recording settings, cannot remove the passcode")
+		}
+		return nil, refused
+	}
+	keeper, ok := p.config.StatusTracker.(download.PasscodeKeeper)
+	if !ok {
+		return nil, fmt.Errorf("%w (the passcode is only removed with a status file to restore it from)", refused)
+	}
+
+	p.passcodeMu.Lock()
+	defer p.passcodeMu.Unlock()
+
+	settings, err := setter.GetRecordingSettings(ctx, recording.UUID)
+	if err != nil {
+		return nil, fmt.Errorf("%w (reading the recording passcode failed: %v)", refused, err)
+	}
+	if settings.Password == "" {
+		// Another file of the meeting may have been downloaded while the passcode was removed
+		return retry()
+	}
+
+	// The passcode is on disk before it is removed, so a crash cannot leave the recording open
+	p.statusMu.Lock()
+	err = keeper.SavePasscode(recording.UUID, settings.Password)
+	p.statusMu.Unlock()
+	if err != nil {
+		return nil, fmt.Errorf("%w (saving the recording passcode failed: %v)", refused, err)
+	}
+	if err := setter.SetRecordingPassword(ctx, recording.UUID, ""); err != nil {
+		p.forgetPasscode(ctx, keeper, recording.UUID)
+		return nil, fmt.Errorf("%w (removing the recording passcode failed: %v)", refused, err)
+	}
+	if logger != nil {
+		logger.InfoWithContext(ctx, fmt.Sprintf("Removed the recording passcode of %s (%s) for the download", recording.Topic, recording.UUID))
+	}
+
+	// The passcode is set again even when the run is cancelled mid-download
+	defer func() {
+		if err := setter.SetRecordingPassword(context.WithoutCancel(ctx), recording.UUID, settings.Password); err != nil {
+			if logger != nil {
+				logger.ErrorWithContext(ctx, fmt.Sprintf("Failed to restore the recording passcode of %s (%s), set it again in Zoom: %v",
+					recording.Topic, recording.UUID, err))
+			}
+			return
+		}
+		p.forgetPasscode(ctx, keeper, recording.UUID)
+		if logger != nil {
+			logger.InfoWithContext(ctx, fmt.Sprintf("Restored the recording passcode of %s (%s)", recording.Topic, recording.UUID))
+		}
+	}()
+
+	return retry()
+}
+
+// forgetPasscode removes a meeting's passcode from the status tracker once it is set again
+func (p *userProcessorImpl) forgetPasscode(ctx context.Context, keeper download.PasscodeKeeper, meetingUUID string) {
+	p.statusMu.Lock()
+	err := keeper.ForgetPasscode(meetingUUID)
+	p.statusMu.Unlock()
+	if err != nil {
+		if logger := logging.GetDefaultLogger(); logger != nil {
+			logger.WarnWithContext(ctx, fmt.Sprintf("Failed to clear the saved recording passcode of %s: %v", meetingUUID, err))
+		}
+	}
+}
+
+// RestorePendingPasscodes sets the recording passcodes saved in tracker again, for runs that
+// stopped while a passcode was removed for a download. Passcodes that cannot be set stay saved
+// for the next run; the error lists their meetings.
+func RestorePendingPasscodes(ctx context.Context, zoomClient ZoomClientInterface, tracker download.StatusTracker) error {
+	keeper, ok := tracker.(download.PasscodeKeeper)
+	if !ok {
+		return nil
+	}
+	pending := keeper.PendingPasscodes()
+	if len(pending) == 0 {
+		return nil
+	}
+	setter, ok := zoomClient.(RecordingPasscodeSetter)
+	if !ok {
+		return fmt.Errorf("%d recording passcode(s) removed by an earlier run cannot be restored: Zoom client does not support recording settings", len(pending))
+	}
+
+	var failed []string
+	for meetingUUID, passcode := range pending {
+		if err := setter.SetRecordingPassword(ctx, meetingUUID, passcode); err != nil {
+			logging.Error("Failed to restore the recording passcode of %s: %v", meetingUUID, err)
+			failed = append(failed, meetingUUID)
+			continue
+		}
+		if err := keeper.ForgetPasscode(meetingUUID); err != nil {
+			return fmt.Errorf("failed to clear the saved recording passcode of %s: %w", meetingUUID, err)
+		}
+		logging.Info("Restored the recording passcode of %s, removed by an earlier run", meetingUUID)
+	}
+	if len(failed) > 0 {
+		sort.Strings(failed)
+		return fmt.Errorf("failed to restore the recording passcodes of %s, set them again in Zoom", strings.Join(failed, ", "))
+	}
+	return nil
+}
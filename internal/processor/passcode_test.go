@@ -0,0 +1,170 @@
+package processor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/curtbushko/zoom-to-box/internal/download"
+	"github.com/curtbushko/zoom-to-box/internal/errclass"
+	"github.com/curtbushko/zoom-to-box/internal/filename"
+	"github.com/curtbushko/zoom-to-box/internal/zoom"
+)
+
+// mockPasscodeZoomClient is a mockZoomClient with passcode-protected recordings
+type mockPasscodeZoomClient struct {
+	*mockZoomClient
+	password  string
+	passwords []string // Passwords set, in order
+
+	tracker download.StatusTracker // Status tracker checked for the saved passcode when it is removed (nil = not checked)
+	unsaved bool                   // The passcode was removed before it was saved
+}
+
+func (m *mockPasscodeZoomClient) GetRecordingSettings(ctx context.Context, meetingUUID string) (*zoom.RecordingSettings, error) {
+	return &zoom.RecordingSettings{Password: m.password}, nil
+}
+
+func (m *mockPasscodeZoomClient) SetRecordingPassword(ctx context.Context, meetingUUID, password string) error {
+	if keeper, ok := m.tracker.(download.PasscodeKeeper); ok && password == "" && keeper.PendingPasscodes()[meetingUUID] != m.password {
+		m.unsaved = true
+	}
+	m.password = password
+	m.passwords = append(m.passwords, password)
+	return nil
+}
+
+// passcodeDownloadManager serves the passcode page while the recording has a passcode
+type passcodeDownloadManager struct {
+	zoomClient *mockPasscodeZoomClient
+}
+
+func (m *passcodeDownloadManager) Download(ctx context.Context, req download.DownloadRequest, progressCallback download.ProgressCallback) (*download.DownloadResult, error) {
+	if m.zoomClient.password != "" {
+		return nil, &download.ContentTypeError{ContentType: "text/html"}
+	}
+	if err := os.MkdirAll(filepath.Dir(req.Destination), 0755); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(req.Destination, []byte("video"), 0644); err != nil {
+		return nil, err
+	}
+	return &download.DownloadResult{Success: true, BytesDownloaded: req.FileSize}, nil
+}
+
+func TestUserProcessor_PasscodeProtectedDownload(t *testing.T) {
+	recordings := []*zoom.Recording{{
+		UUID:      "protected",
+		Topic:     "Board Meeting",
+		StartTime: time.Date(2024, 3, 1, 14, 0, 0, 0, time.UTC),
+		RecordingFiles: []zoom.RecordingFile{
+			{ID: "file-1", FileType: "MP4", DownloadURL: "https://zoom.us/download/protected.mp4", FileSize: 5},
+		},
+	}}
+
+	for _, disablePasscodes := range []bool{false, true} {
+		tmpDir := t.TempDir()
+		statusTracker, err := download.NewStatusTracker(filepath.Join(tmpDir, ".status.json"))
+		if err != nil {
+			t.Fatalf("NewStatusTracker failed: %v", err)
+		}
+		zoomClient := &mockPasscodeZoomClient{mockZoomClient: newMockZoomClient(), password: "s3cret", tracker: statusTracker}
+		processor := NewUserProcessor(
+			zoomClient,
+			&passcodeDownloadManager{zoomClient: zoomClient},
+			nil,
+			filename.NewFileSanitizer(filename.FileSanitizerOptions{}),
+			nil,
+			ProcessorConfig{BaseDownloadDir: tmpDir, ContinueOnError: true, DisablePasscodes: disablePasscodes, StatusTracker: statusTracker},
+		)
+
+		result, err := processor.ProcessRecordings(context.Background(), "jane@example.com", "jane@example.com", recordings)
+		if err != nil {
+			t.Fatalf("ProcessRecordings failed: %v", err)
+		}
+
+		if !disablePasscodes {
+			if result.ErrorCount != 1 || errclass.Of(result.Errors[0]) != errclass.Passcode {
+				t.Errorf("Expected a passcode error, got %v", result.Errors)
+			}
+			if len(zoomClient.passwords) != 0 {
+				t.Errorf("Expected the passcode to be left alone, got %v", zoomClient.passwords)
+			}
+			continue
+		}
+		if result.DownloadedCount != 1 || result.ErrorCount != 0 {
+			t.Errorf("Expected the recording downloaded without its passcode, got %+v", result)
+		}
+		if len(zoomClient.passwords) != 2 || zoomClient.passwords[0] != "" || zoomClient.password != "s3cret" {
+			t.Errorf("Expected the passcode removed and restored, got %v", zoomClient.passwords)
+		}
+		if zoomClient.unsaved {
+			t.Error("Expected the passcode saved in the status file before it was removed")
+		}
+		if pending := statusTracker.(download.PasscodeKeeper).PendingPasscodes(); len(pending) != 0 {
+			t.Errorf("Expected no pending passcodes once restored, got %v", pending)
+		}
+	}
+}
+
+func TestUserProcessor_PasscodeNotRemovedWithoutStatusFile(t *testing.T) {
+	recordings := []*zoom.Recording{{
+		UUID:      "protected",
+		Topic:     "Board Meeting",
+		StartTime: time.Date(2024, 3, 1, 14, 0, 0, 0, time.UTC),
+		RecordingFiles: []zoom.RecordingFile{
+			{ID: "file-1", FileType: "MP4", DownloadURL: "https://zoom.us/download/protected.mp4", FileSize: 5},
+		},
+	}}
+
+	zoomClient := &mockPasscodeZoomClient{mockZoomClient: newMockZoomClient(), password: "s3cret"}
+	processor := NewUserProcessor(
+		zoomClient,
+		&passcodeDownloadManager{zoomClient: zoomClient},
+		nil,
+		filename.NewFileSanitizer(filename.FileSanitizerOptions{}),
+		nil,
+		ProcessorConfig{BaseDownloadDir: t.TempDir(), ContinueOnError: true, DisablePasscodes: true},
+	)
+
+	result, err := processor.ProcessRecordings(context.Background(), "jane@example.com", "jane@example.com", recordings)
+	if err != nil {
+		t.Fatalf("ProcessRecordings failed: %v", err)
+	}
+	if result.ErrorCount != 1 || errclass.Of(result.Errors[0]) != errclass.Passcode {
+		t.Errorf("Expected a passcode error, got %v", result.Errors)
+	}
+	if len(zoomClient.passwords) != 0 {
+		t.Errorf("Expected the passcode left alone without a status file, got %v", zoomClient.passwords)
+	}
+}
+
+func TestRestorePendingPasscodes(t *testing.T) {
+	statusFile := filepath.Join(t.TempDir(), ".status.json")
+	statusTracker, err := download.NewStatusTracker(statusFile)
+	if err != nil {
+		t.Fatalf("NewStatusTracker failed: %v", err)
+	}
+	// A run stopped while the passcode was removed
+	if err := statusTracker.(download.PasscodeKeeper).SavePasscode("protected", "s3cret"); err != nil {
+		t.Fatalf("SavePasscode failed: %v", err)
+	}
+
+	reopened, err := download.NewStatusTracker(statusFile)
+	if err != nil {
+		t.Fatalf("NewStatusTracker failed: %v", err)
+	}
+	zoomClient := &mockPasscodeZoomClient{mockZoomClient: newMockZoomClient()}
+	if err := RestorePendingPasscodes(context.Background(), zoomClient, reopened); err != nil {
+		t.Fatalf("RestorePendingPasscodes failed: %v", err)
+	}
+
+	if zoomClient.password != "s3cret" {
+		t.Errorf("Expected the saved passcode set again, got %q", zoomClient.password)
+	}
+	if pending := reopened.(download.PasscodeKeeper).PendingPasscodes(); len(pending) != 0 {
+		t.Errorf("Expected no pending passcodes once restored, got %v", pending)
+	}
+}
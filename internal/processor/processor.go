@@ -3,22 +3,36 @@ package processor
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"net/url"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/curtbushko/zoom-to-box/internal/atrest"
 	"github.com/curtbushko/zoom-to-box/internal/box"
 	"github.com/curtbushko/zoom-to-box/internal/directory"
 	"github.com/curtbushko/zoom-to-box/internal/download"
 	"github.com/curtbushko/zoom-to-box/internal/email"
 	"github.com/curtbushko/zoom-to-box/internal/filename"
+	"github.com/curtbushko/zoom-to-box/internal/hooks"
 	"github.com/curtbushko/zoom-to-box/internal/logging"
+	"github.com/curtbushko/zoom-to-box/internal/pipeline"
+	"github.com/curtbushko/zoom-to-box/internal/preflight"
+	"github.com/curtbushko/zoom-to-box/internal/progress"
+	"github.com/curtbushko/zoom-to-box/internal/runreport"
+	"github.com/curtbushko/zoom-to-box/internal/storage"
+	"github.com/curtbushko/zoom-to-box/internal/timefmt"
+	"github.com/curtbushko/zoom-to-box/internal/tracing"
 	"github.com/curtbushko/zoom-to-box/internal/tracking"
 	"github.com/curtbushko/zoom-to-box/internal/users"
 	"github.com/curtbushko/zoom-to-box/internal/zoom"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // UserProcessor defines the interface for processing users
@@ -26,8 +40,17 @@ type UserProcessor interface {
 	// ProcessUser downloads and uploads recordings for a single user
 	ProcessUser(ctx context.Context, zoomEmail, boxEmail string) (*ProcessorResult, error)
 
+	// ProcessRecordings downloads and uploads already-fetched recordings for a single user
+	ProcessRecordings(ctx context.Context, zoomEmail, boxEmail string, recordings []*zoom.Recording) (*ProcessorResult, error)
+
 	// ProcessAllUsers processes all incomplete users from the active users file
 	ProcessAllUsers(ctx context.Context, usersFile *users.ActiveUsersFile) (*ProcessorSummary, error)
+
+	// ReplayFailure re-executes a single failed file operation from a run report
+	ReplayFailure(ctx context.Context, failure runreport.Failure) error
+
+	// UploadLocal uploads the files already downloaded below root for the given users
+	UploadLocal(ctx context.Context, root string, entries []users.UserEntry) (*ProcessorSummary, error)
 }
 
 // ProcessorConfig holds configuration for the user processor
@@ -40,8 +63,86 @@ type ProcessorConfig struct {
 	Limit             int
 	DryRun            bool
 	Verbose           bool
+	FromDate          *time.Time                 // Start of the recordings date range (nil = 2020-06-30)
+	ToDate            *time.Time                 // End of the recordings date range (nil = today)
+	ChecksumAlgorithm download.ChecksumAlgorithm // Algorithm for downloaded file checksums ("" = none)
+	Thumbnails        bool                       // Download recording thumbnails next to the MP4
+	TranscriptFormats []string                   // Convert WebVTT transcripts and captions to these sidecar formats: srt, txt (empty = none)
+
+	CleanupEmptyFolders bool // Remove empty destination folders created for failed uploads
+
+	DeleteRequiresVerification bool // With DeleteAfterUpload, delete local files only once the destination confirmed size and checksum
+
+	MigrationReport bool // Upload a MIGRATION-REPORT.md summary to each user's zoom folder next to uploads.csv
+
+	FailureRecorder runreport.FailureRecorder // Receives failed downloads and uploads for later replay (nil = not recorded)
+
+	Deadline time.Time       // Stop starting new files once reached; in-flight transfers finish (zero = no limit)
+	Stop     <-chan struct{} // Closed to stop starting new files, like a reached deadline (nil = never)
+
+	Filter RecordingFilter // Recordings that do not pass the filter are skipped
+
+	FilenameTemplate *filename.Template // Names downloaded files (nil = filename.DefaultTemplate)
+	DirectoryLayout  *directory.Layout  // Folders recordings are stored in, locally and in the destination (nil = directory.DefaultLayout)
+
+	MetadataOrder  MetadataOrder // When the metadata JSON is uploaded relative to the recording ("" = after)
+	MetadataFormat string        // Format of the metadata sidecar saved next to each MP4: json, yaml, none or a registered format ("" = json)
+
+	PreviewMinutes int // Download only about the first N minutes of each MP4, saved as <name>-preview.mp4 (0 = full files)
+
+	WindowsSafePaths bool // Mangle names Windows reserves in local folders, fit local paths into MaxPathLength and use \\?\ long paths
+	MaxPathLength    int  // Longest local file path with WindowsSafePaths (0 = directory.WindowsMaxPath)
+
+	Stream bool // Experimental: pipe large MP4s from Zoom into the destination without a local copy (requires a storage.StreamUploader destination)
+
+	IncludeWebinars bool // Also migrate recordings of webinars the user hosted (requires a WebinarRecordingLister client)
+
+	AccountRecordings bool // ProcessAllUsers lists the whole account's recordings once instead of each user's (requires an AccountRecordingLister client)
+	PageSize          int  // Recordings per Zoom list recordings page (0 = 300)
+
+	IncludeTrash bool // Also migrate meeting recordings in the Zoom trash (requires a TrashRecordingLister client)
+	RestoreTrash bool // With IncludeTrash, restore trash recordings before downloading them (requires a TrashRecoverer client)
+
+	DisablePasscodes bool // Retry downloads refused for a passcode with the recording's passcode removed, then restore it (requires a RecordingPasscodeSetter client)
+
+	IncludeParticipants bool // Add the host and meeting participants to the metadata JSON (requires a zoom.ParticipantClient client)
+
+	Progress progress.Reporter // Receives per-user and per-file transfer progress (nil = not reported)
+
+	Hooks *hooks.Runner // Fired after each upload and after each user (nil = no hooks)
+
+	Encryption        *atrest.Cipher // Encrypts downloaded MP4s on disk; uploads decrypt a temporary copy (nil = not encrypted)
+	EncryptionTempDir string         // Where decrypted copies are staged during uploads ("" = OS temp dir)
+
+	DiskGuard    *preflight.DiskGuard // Checks free space before each download (nil = not checked)
+	MaxUserBytes int64                // Cap on each user's local recordings (0 = no cap)
+
+	ConcurrentDownloads int // Recording files of a user downloaded at the same time; uploads stay in recording order (0 or 1 = one at a time)
+
+	StatusTracker download.StatusTracker  // Records each file's download and upload, so an interrupted run resumes at the next recording (nil = not recorded)
+	RunSummary    tracking.SummaryTracker // Records one row per recording file of the run (nil = not recorded)
+
+	UserTimeout time.Duration // Longest a user may take; the user then fails and the batch moves on (0 = no limit)
+	FileTimeout time.Duration // Longest a single file download may take, retries included; the file then fails (0 = no limit)
+
+	SizeTolerancePercent float64 // Reject downloads whose size differs from Zoom's reported size by more than this percent (0 = exact)
+
+	TransientRetries    int           // End-of-run passes that process users who failed only on transient errors again (0 = none)
+	TransientRetryDelay time.Duration // Wait before each retry pass
+
+	SharedLinkAccess string            // Create a shared link with this access level for each uploaded MP4 recording ("" = none)
+	MetadataTemplate *MetadataTemplate // Metadata template attached to each uploaded recording file (nil = none)
 }
 
+// MetadataOrder controls whether a recording's metadata JSON is uploaded before or after the recording
+type MetadataOrder string
+
+// Metadata upload orderings
+const (
+	MetadataAfterRecording  MetadataOrder = "after"  // Upload the recording first; a failed metadata upload is logged only
+	MetadataBeforeRecording MetadataOrder = "before" // Upload the metadata first; the recording is only uploaded and tracked once it is in place
+)
+
 // ProcessorResult represents the result of processing a single user
 type ProcessorResult struct {
 	ZoomEmail       string
@@ -53,20 +154,69 @@ type ProcessorResult struct {
 	DeletedCount    int
 	Errors          []error
 	Duration        time.Duration
+	TimeBoxed       bool // Processing stopped early because the run deadline was reached
+	Interrupted     bool // Processing stopped early because the context was cancelled, e.g. by SIGINT
+	DiskFull        bool // Processing stopped early because the output disk is below its reserve
+	TimedOut        bool // Processing stopped early because the user timeout was reached
+
+	BytesDownloaded int64         // Bytes downloaded from Zoom for this user
+	BytesPlanned    int64         // Bytes a dry run would download from Zoom for this user
+	Files           []FileOutcome // Outcome of each recording file considered for this user
+
+	StageTimings map[string]pipeline.StageStats // Runs, failures and time spent in each file pipeline stage
+}
+
+// File outcomes reported in FileOutcome.Outcome
+const (
+	OutcomeDownloaded = "downloaded" // Downloaded; no upload destination, or a dry run
+	OutcomeUploaded   = "uploaded"   // Downloaded and uploaded
+	OutcomeSkipped    = "skipped"    // Already present locally or in the destination, or meta-only
+	OutcomeFiltered   = "filtered"   // Excluded by the recording filters
+	OutcomeFailed     = "failed"
+)
+
+// FileOutcome records what happened to a single recording file
+type FileOutcome struct {
+	RecordingUUID   string  `json:"recording_uuid"`
+	Topic           string  `json:"topic"`
+	FileID          string  `json:"file_id"`
+	FileType        string  `json:"file_type"`
+	FileName        string  `json:"file_name,omitempty"`
+	LocalPath       string  `json:"local_path,omitempty"`
+	Outcome         string  `json:"outcome"`
+	Reason          string  `json:"reason,omitempty"` // Why the file was filtered
+	Deleted         bool    `json:"deleted,omitempty"`
+	BytesDownloaded int64   `json:"bytes_downloaded"`
+	BytesPlanned    int64   `json:"bytes_planned,omitempty"` // Bytes a dry run would download
+	DurationSeconds float64 `json:"duration_seconds"`
+	Error           string  `json:"error,omitempty"`
+
+	BytesUploaded   int64   `json:"bytes_uploaded,omitempty"`
+	DownloadSeconds float64 `json:"download_seconds,omitempty"` // Time spent downloading from Zoom
+	UploadSeconds   float64 `json:"upload_seconds,omitempty"`   // Time spent uploading to the destination
+	DownloadMBps    float64 `json:"download_mbps,omitempty"`
+	UploadMBps      float64 `json:"upload_mbps,omitempty"`
 }
 
 // ProcessorSummary represents the summary of processing multiple users
 type ProcessorSummary struct {
-	TotalUsers       int
-	ProcessedUsers   int
-	FailedUsers      int
-	TotalDownloads   int
-	TotalUploads     int
-	TotalSkipped     int
-	TotalErrors      int
-	TotalDeleted     int
-	Duration         time.Duration
-	UserResults      []*ProcessorResult
+	TotalUsers     int
+	ProcessedUsers int
+	FailedUsers    int
+	TotalDownloads int
+	TotalUploads   int
+	TotalSkipped   int
+	TotalErrors    int
+	TotalDeleted   int
+	Duration       time.Duration
+	UserResults    []*ProcessorResult
+	TimeBoxed      bool // The run deadline was reached; remaining users and files are left for the next run
+	Interrupted    bool // The run was cancelled; the interrupted user and the rest are left for the next run
+	DiskFull       bool // Free disk space fell below the reserve; the current user and the rest are left for the next run
+	RetriedUsers   int  // Users processed again by a retry pass after transient failures
+
+	TotalBytesDownloaded int64
+	TotalBytesPlanned    int64 // Bytes a dry run would download from Zoom
 }
 
 // ZoomClientInterface defines the methods we need from ZoomClient
@@ -75,17 +225,54 @@ type ZoomClientInterface interface {
 	GetOAuthAccessToken(ctx context.Context) (string, error)
 }
 
+// WebinarRecordingLister is implemented by Zoom clients that can enumerate webinar recordings
+type WebinarRecordingLister interface {
+	GetAllUserWebinarRecordings(ctx context.Context, userID string, params zoom.ListRecordingsParams) ([]*zoom.Recording, error)
+}
+
+// RecordingFileStreamer is implemented by Zoom clients that can write a recording file to a stream
+type RecordingFileStreamer interface {
+	DownloadRecordingFile(ctx context.Context, downloadURL string, writer io.Writer) error
+}
+
+// MeetingRecordingGetter is implemented by Zoom clients that can look up a single meeting's recordings
+type MeetingRecordingGetter interface {
+	GetMeetingRecordings(ctx context.Context, meetingID string) (*zoom.Recording, error)
+}
+
 // userProcessorImpl implements the UserProcessor interface
 type userProcessorImpl struct {
 	zoomClient        ZoomClientInterface
 	downloadManager   download.DownloadManager
 	dirManager        directory.DirectoryManager
 	filenameSanitizer filename.FileSanitizer
-	boxUploadManager  box.UploadManager
+	destination       storage.UploadDestination
 	config            ProcessorConfig
+
+	plannedFolders map[string]bool // Destination folders a dry run has already reported, keyed by user and path
+
+	filePipeline *pipeline.Pipeline[*fileJob] // Stages each recording file runs through
+
+	filenameTemplate   *filename.Template // Names downloaded files
+	directoryLayout    *directory.Layout  // Folders recordings are stored in
+	metadataSerializer MetadataSerializer // Writes metadata sidecars (nil = none are saved)
+
+	usageMu   sync.Mutex
+	userUsage map[string]int64 // Local bytes per username, for MaxUserBytes
+
+	statusMu sync.Mutex // Serializes access to config.StatusTracker from concurrent downloads
+
+	detailsMu    sync.Mutex
+	participants map[string][]zoom.Participant // Participants by meeting UUID, for IncludeParticipants
+	hosts        map[string]*zoom.User         // Hosts by user ID, for IncludeParticipants
+
+	accountMu         sync.Mutex
+	accountRecordings *accountRecordings // Recordings listed for the run, for AccountRecordings (nil = list per user)
+
+	passcodeMu sync.Mutex // Serializes downloads with a removed recording passcode, for DisablePasscodes
 }
 
-// NewUserProcessor creates a new user processor
+// NewUserProcessor creates a new user processor that uploads to Box
 func NewUserProcessor(
 	zoomClient ZoomClientInterface,
 	downloadManager download.DownloadManager,
@@ -94,18 +281,102 @@ func NewUserProcessor(
 	boxUploadManager box.UploadManager,
 	config ProcessorConfig,
 ) UserProcessor {
-	return &userProcessorImpl{
+	var destination storage.UploadDestination
+	if boxUploadManager != nil {
+		destination = storage.NewBoxDestination(boxUploadManager)
+	}
+	return NewUserProcessorWithDestination(zoomClient, downloadManager, dirManager, filenameSanitizer, destination, config)
+}
+
+// NewUserProcessorWithDestination creates a new user processor that uploads to any destination
+func NewUserProcessorWithDestination(
+	zoomClient ZoomClientInterface,
+	downloadManager download.DownloadManager,
+	dirManager directory.DirectoryManager,
+	filenameSanitizer filename.FileSanitizer,
+	destination storage.UploadDestination,
+	config ProcessorConfig,
+) UserProcessor {
+	p := &userProcessorImpl{
 		zoomClient:        zoomClient,
 		downloadManager:   downloadManager,
 		dirManager:        dirManager,
 		filenameSanitizer: filenameSanitizer,
-		boxUploadManager:  boxUploadManager,
+		destination:       destination,
 		config:            config,
 	}
+	p.filePipeline = p.newFilePipeline()
+	if p.downloadConcurrency() > 1 {
+		// The download stage always exists, so inserting after it cannot fail
+		p.filePipeline, _ = p.filePipeline.InsertAfter(StageDownload, pipeline.NewStage(StageWait, p.waitStage))
+	}
+	p.filenameTemplate = config.FilenameTemplate
+	if p.filenameTemplate == nil {
+		// The default template always parses
+		p.filenameTemplate, _ = filename.NewTemplate(filename.DefaultTemplate, filenameSanitizer)
+	}
+	p.directoryLayout = config.DirectoryLayout
+	if p.directoryLayout == nil {
+		// The default layout always parses
+		p.directoryLayout, _ = directory.NewLayout(directory.DefaultLayout)
+	}
+	serializer, err := MetadataSerializerFor(config.MetadataFormat)
+	if err != nil {
+		// Callers check the format first; an unknown one keeps the default sidecar
+		serializer = jsonMetadata{}
+	}
+	p.metadataSerializer = serializer
+	return p
 }
 
 // ProcessUser downloads and uploads recordings for a single user
 func (p *userProcessorImpl) ProcessUser(ctx context.Context, zoomEmail, boxEmail string) (*ProcessorResult, error) {
+	return traceUser(ctx, "process_user", zoomEmail, boxEmail, func(ctx context.Context) (*ProcessorResult, error) {
+		ctx, cancel := p.userContext(ctx)
+		defer cancel()
+		return p.processUser(ctx, zoomEmail, boxEmail)
+	})
+}
+
+// traceUser runs process in a span for the user and records the result counts on it
+func traceUser(ctx context.Context, name, zoomEmail, boxEmail string, process func(context.Context) (*ProcessorResult, error)) (*ProcessorResult, error) {
+	ctx, span := tracing.Start(ctx, name,
+		attribute.String("zoom.email", zoomEmail),
+		attribute.String("box.email", boxEmail))
+	result, err := process(ctx)
+	if result != nil {
+		span.SetAttributes(
+			attribute.Int("files.downloaded", result.DownloadedCount),
+			attribute.Int("files.uploaded", result.UploadedCount),
+			attribute.Int("files.skipped", result.SkippedCount),
+			attribute.Int("files.failed", result.ErrorCount),
+			attribute.Int64("bytes.downloaded", result.BytesDownloaded))
+	}
+	tracing.End(span, err)
+	return result, err
+}
+
+// recordingParams returns the parameters users' recordings are listed with
+func (p *userProcessorImpl) recordingParams() zoom.ListRecordingsParams {
+	params := zoom.ListRecordingsParams{
+		From:     getFromDate(),
+		To:       getToDate(),
+		PageSize: 300,
+	}
+	if p.config.PageSize > 0 {
+		params.PageSize = p.config.PageSize
+	}
+	if p.config.FromDate != nil {
+		params.From = p.config.FromDate
+	}
+	if p.config.ToDate != nil {
+		params.To = p.config.ToDate
+	}
+	return params
+}
+
+// processUser lists the user's recordings and processes them
+func (p *userProcessorImpl) processUser(ctx context.Context, zoomEmail, boxEmail string) (*ProcessorResult, error) {
 	startTime := time.Now()
 
 	result := &ProcessorResult{
@@ -120,13 +391,8 @@ func (p *userProcessorImpl) ProcessUser(ctx context.Context, zoomEmail, boxEmail
 	}
 
 	// Get recordings for this user FIRST before any setup
-	params := zoom.ListRecordingsParams{
-		From:     getFromDate(),
-		To:       getToDate(),
-		PageSize: 300,
-	}
-
-	recordings, err := p.zoomClient.GetAllUserRecordings(ctx, zoomEmail, params)
+	params := p.recordingParams()
+	recordings, err := p.userRecordings(ctx, zoomEmail, params)
 	if err != nil {
 		err = fmt.Errorf("failed to get recordings for user %s: %w", zoomEmail, err)
 		result.Errors = append(result.Errors, err)
@@ -157,6 +423,13 @@ func (p *userProcessorImpl) ProcessUser(ctx context.Context, zoomEmail, boxEmail
 			len(recordings), zoomEmail, fromStr, toStr, params.PageSize))
 	}
 
+	if p.config.IncludeWebinars {
+		recordings = p.appendWebinarRecordings(ctx, zoomEmail, params, recordings)
+	}
+	if p.config.IncludeTrash {
+		recordings = p.appendTrashRecordings(ctx, zoomEmail, params, recordings)
+	}
+
 	// If user has no recordings, skip them (mark as complete, don't create any directories/files)
 	if len(recordings) == 0 {
 		if logger != nil {
@@ -166,13 +439,102 @@ func (p *userProcessorImpl) ProcessUser(ctx context.Context, zoomEmail, boxEmail
 		return result, nil
 	}
 
-	// If Box is enabled, verify access to the zoom folder BEFORE downloading anything
-	if p.config.BoxEnabled && p.boxUploadManager != nil {
-		boxClient := p.boxUploadManager.GetBoxClient()
-		_, err := boxClient.FindZoomFolderByOwner(boxEmail)
+	return p.processRecordings(ctx, result, startTime, recordings)
+}
+
+// appendWebinarRecordings adds the user's webinar recordings that are not already in recordings
+// A failure to list webinars is logged and the meeting recordings are processed on their own.
+func (p *userProcessorImpl) appendWebinarRecordings(ctx context.Context, zoomEmail string, params zoom.ListRecordingsParams, recordings []*zoom.Recording) []*zoom.Recording {
+	logger := logging.GetDefaultLogger()
+
+	lister, ok := p.zoomClient.(WebinarRecordingLister)
+	if !ok {
+		if logger != nil {
+			logger.WarnWithContext(ctx, "Zoom client does not support webinar recordings, skipping webinars")
+		}
+		return recordings
+	}
+
+	webinarRecordings, err := lister.GetAllUserWebinarRecordings(ctx, zoomEmail, params)
+	if err != nil {
+		if logger != nil {
+			logger.WarnWithContext(ctx, fmt.Sprintf("Failed to get webinar recordings for user %s: %v", zoomEmail, err))
+		}
+		return recordings
+	}
+
+	known := make(map[string]bool, len(recordings))
+	for _, recording := range recordings {
+		known[recording.UUID] = true
+	}
+
+	added := 0
+	for _, recording := range webinarRecordings {
+		if known[recording.UUID] {
+			continue
+		}
+		known[recording.UUID] = true
+		recordings = append(recordings, recording)
+		added++
+	}
+
+	if logger != nil {
+		logger.InfoWithContext(ctx, fmt.Sprintf("Zoom API returned %d webinar recordings for user %s (%d not already listed)",
+			len(webinarRecordings), zoomEmail, added))
+	}
+	return recordings
+}
+
+// ProcessRecordings downloads and uploads the given recordings for a single user
+// It is used when recordings are already known, e.g. from a recording.completed webhook event,
+// and applies the same destination checks, limits and cleanup as ProcessUser.
+func (p *userProcessorImpl) ProcessRecordings(ctx context.Context, zoomEmail, boxEmail string, recordings []*zoom.Recording) (*ProcessorResult, error) {
+	return traceUser(ctx, "process_recordings", zoomEmail, boxEmail, func(ctx context.Context) (*ProcessorResult, error) {
+		ctx, cancel := p.userContext(ctx)
+		defer cancel()
+		return p.processGivenRecordings(ctx, zoomEmail, boxEmail, recordings)
+	})
+}
+
+// processGivenRecordings processes recordings that are already known for a user
+func (p *userProcessorImpl) processGivenRecordings(ctx context.Context, zoomEmail, boxEmail string, recordings []*zoom.Recording) (*ProcessorResult, error) {
+	startTime := time.Now()
+
+	result := &ProcessorResult{
+		ZoomEmail: zoomEmail,
+		BoxEmail:  boxEmail,
+		Errors:    make([]error, 0),
+	}
+
+	if len(recordings) == 0 {
+		result.Duration = time.Since(startTime)
+		return result, nil
+	}
+
+	logger := logging.GetDefaultLogger()
+	if logger != nil {
+		logger.InfoWithContext(ctx, fmt.Sprintf("Processing %d recording(s) for user: %s (Box email: %s)", len(recordings), zoomEmail, boxEmail))
+	}
+
+	return p.processRecordings(ctx, result, startTime, recordings)
+}
+
+// processRecordings verifies destination access and processes each recording file for a user
+func (p *userProcessorImpl) processRecordings(ctx context.Context, result *ProcessorResult, startTime time.Time, recordings []*zoom.Recording) (*ProcessorResult, error) {
+	logger := logging.GetDefaultLogger()
+	zoomEmail, boxEmail := result.ZoomEmail, result.BoxEmail
+
+	// If uploads are enabled, verify access to the user's folder BEFORE downloading anything
+	// Users whose files were all uploaded by earlier runs need no destination lookups at all
+	if p.config.BoxEnabled && p.destination != nil && p.allUploadedEarlier(boxEmail, recordings) {
+		if p.config.Verbose && logger != nil {
+			logger.InfoWithContext(ctx, fmt.Sprintf("All recordings of %s were uploaded in earlier runs, skipping %s checks", zoomEmail, p.destination.Name()))
+		}
+	} else if p.config.BoxEnabled && p.destination != nil {
+		err := p.destination.CheckUserAccess(ctx, boxEmail)
 		if err != nil {
 			// Cannot access zoom folder - mark this user as failed so they remain in active_users with upload_complete=false
-			boxErr := fmt.Errorf("cannot access zoom folder for user %s (Box email: %s): %w", zoomEmail, boxEmail, err)
+			boxErr := fmt.Errorf("cannot access zoom folder for user %s (%s email: %s): %w", zoomEmail, p.destination.Name(), boxEmail, err)
 			result.Errors = append(result.Errors, boxErr)
 			result.ErrorCount++
 			result.Duration = time.Since(startTime)
@@ -188,8 +550,9 @@ func (p *userProcessorImpl) ProcessUser(ctx context.Context, zoomEmail, boxEmail
 		}
 
 		// User has recordings AND we can access their Box zoom folder - initialize CSV tracker
+		// (a dry run uploads nothing, so it has nothing to track)
 		username := email.ExtractUsername(boxEmail)
-		if username != "" {
+		if username != "" && !p.config.DryRun {
 			userDir := filepath.Join(p.config.BaseDownloadDir, username)
 			userCSVTracker, err := tracking.NewUserCSVTracker(userDir, zoomEmail)
 			if err != nil {
@@ -197,7 +560,7 @@ func (p *userProcessorImpl) ProcessUser(ctx context.Context, zoomEmail, boxEmail
 					logger.WarnWithContext(ctx, fmt.Sprintf("Failed to create user CSV tracker for %s: %v", zoomEmail, err))
 				}
 			} else {
-				p.boxUploadManager.SetUserCSVTracker(userCSVTracker)
+				p.destination.SetUserCSVTracker(userCSVTracker)
 				if logger != nil {
 					logger.InfoWithContext(ctx, fmt.Sprintf("Initialized user CSV tracker for %s at %s/uploads.csv", zoomEmail, userDir))
 				}
@@ -205,8 +568,25 @@ func (p *userProcessorImpl) ProcessUser(ctx context.Context, zoomEmail, boxEmail
 		}
 	}
 
-	// Process each recording
+	if p.config.Progress != nil {
+		files, totalBytes := p.transferEstimate(recordings)
+		p.config.Progress.StartUser(zoomEmail, files, totalBytes)
+		defer p.config.Progress.EndUser()
+	}
+
+	// Process each recording, timing every pipeline stage
+	timings := pipeline.NewTimings()
+	defer func() {
+		result.StageTimings = timings.Snapshot()
+	}()
+	// Up to downloadConcurrency files are in flight at a time. Their results are counted in recording
+	// order, and each file uploads only once the file before it is done, so uploads.csv stays ordered.
+	concurrency := p.downloadConcurrency()
+	var pending []*pendingFile
+	var previousDone <-chan struct{}
+	var fileErr error
 	processedCount := 0
+recordingsLoop:
 	for _, recording := range recordings {
 		// Check limit
 		if p.config.Limit > 0 && processedCount >= p.config.Limit {
@@ -216,6 +596,15 @@ func (p *userProcessorImpl) ProcessUser(ctx context.Context, zoomEmail, boxEmail
 			break
 		}
 
+		// Skip recordings excluded by the duration, topic and meeting type filters
+		if reason := p.config.Filter.SkipReason(recording); reason != "" {
+			if p.config.Verbose && logger != nil {
+				logger.InfoWithContext(ctx, fmt.Sprintf("Skipped (filtered, %s): %s", reason, recording.Topic))
+			}
+			pending = append(pending, &pendingFile{recording: recording, filterReason: reason})
+			continue
+		}
+
 		// Process recording files
 		for _, recordingFile := range recording.RecordingFiles {
 			// Check limit again
@@ -228,474 +617,683 @@ func (p *userProcessorImpl) ProcessUser(ctx context.Context, zoomEmail, boxEmail
 				continue
 			}
 
-			// Skip non-MP4 files unless we want all
-			if recordingFile.FileType != "MP4" && !p.config.MetaOnly {
+			// Skip non-MP4 files unless we want all, or they are transcripts to convert
+			if !p.includesFileType(recordingFile) {
 				continue
 			}
 
-			// Process this recording file
-			fileResult := p.processRecordingFile(ctx, zoomEmail, boxEmail, recording, recordingFile)
-
-			// Update counters
-			if fileResult.Downloaded {
-				result.DownloadedCount++
+			// Skip zero-byte and stub files below the minimum file size
+			if reason := p.config.Filter.SkipFileReason(recordingFile); reason != "" {
+				if logger != nil {
+					logger.InfoWithContext(ctx, fmt.Sprintf("Skipped (filtered, %s): %s %s", reason, recording.Topic, recordingFile.FileType))
+				}
+				pending = append(pending, &pendingFile{recording: recording, recordingFile: recordingFile, filterReason: reason, fileFiltered: true})
+				continue
 			}
-			if fileResult.Uploaded {
-				result.UploadedCount++
+
+			// Count the oldest files until there is room for another one
+			for len(pending) >= concurrency {
+				stop, err := p.addFileResult(ctx, result, pending[0])
+				pending = pending[1:]
+				if err != nil {
+					fileErr = err
+					break recordingsLoop
+				}
+				if stop {
+					break recordingsLoop
+				}
 			}
-			if fileResult.Skipped {
-				result.SkippedCount++
+
+			// Stop starting new files once the user timeout is reached; the user counts as failed
+			if userTimedOut(ctx) {
+				p.addUserTimeout(ctx, result)
+				break recordingsLoop
 			}
-			if fileResult.Deleted {
-				result.DeletedCount++
+
+			// Stop starting new files once the run is cancelled
+			if ctx.Err() != nil {
+				result.Interrupted = true
+				if logger != nil {
+					logger.WarnWithContext(ctx, fmt.Sprintf("Interrupted, stopping before remaining recordings for user %s", zoomEmail))
+				}
+				break recordingsLoop
 			}
-			if fileResult.Error != nil {
-				result.ErrorCount++
-				result.Errors = append(result.Errors, fileResult.Error)
 
-				// Stop processing this user if not continuing on error
-				if !p.config.ContinueOnError {
-					result.Duration = time.Since(startTime)
-					return result, fileResult.Error
+			// Stop starting new files once the run deadline is reached
+			if p.deadlineReached() {
+				result.TimeBoxed = true
+				if logger != nil {
+					logger.InfoWithContext(ctx, fmt.Sprintf("Run deadline reached, stopping before remaining recordings for user %s", zoomEmail))
 				}
+				break recordingsLoop
 			}
 
+			// Process this recording file
+			file := p.startFile(ctx, zoomEmail, boxEmail, recording, recordingFile, previousDone, timings)
+			pending = append(pending, file)
+			previousDone = file.done
 			processedCount++
 		}
 	}
 
+	// Files already started run to completion and are counted even when the user stops early
+	for _, file := range pending {
+		if _, err := p.addFileResult(ctx, result, file); err != nil && fileErr == nil {
+			fileErr = err
+		}
+	}
+	if fileErr != nil {
+		result.Duration = time.Since(startTime)
+		return result, fileErr
+	}
+
 	result.Duration = time.Since(startTime)
 
 	if logger != nil {
 		logger.InfoWithContext(ctx, fmt.Sprintf("Completed processing user %s: %d downloaded, %d uploaded, %d skipped, %d deleted, %d errors in %v",
 			zoomEmail, result.DownloadedCount, result.UploadedCount, result.SkippedCount, result.DeletedCount, result.ErrorCount, result.Duration))
+		if p.config.Verbose {
+			stages := timings.Snapshot()
+			for _, stage := range p.filePipeline.Stages() {
+				if stats, ok := stages[stage]; ok {
+					logger.InfoWithContext(ctx, fmt.Sprintf("Stage %s for user %s: %d files, %d failed, %v", stage, zoomEmail, stats.Runs, stats.Failures, stats.Duration.Round(time.Millisecond)))
+				}
+			}
+		}
 	}
 
-	// Upload the user's uploads.csv to their Box zoom folder if Box is enabled and uploads occurred
-	if p.config.BoxEnabled && p.boxUploadManager != nil && result.UploadedCount > 0 {
-		if err := p.uploadUserCSVToBox(ctx, zoomEmail, boxEmail); err != nil {
+	// Upload the user's uploads.csv to their zoom folder if uploads are enabled and uploads occurred
+	if p.config.BoxEnabled && p.destination != nil && result.UploadedCount > 0 && !result.Interrupted && !result.TimedOut {
+		if err := p.uploadUserCSV(ctx, zoomEmail, boxEmail); err != nil {
 			if logger != nil {
-				logger.WarnWithContext(ctx, fmt.Sprintf("Failed to upload uploads.csv to Box for user %s: %v", zoomEmail, err))
+				logger.WarnWithContext(ctx, fmt.Sprintf("Failed to upload uploads.csv to %s for user %s: %v", p.destination.Name(), zoomEmail, err))
 			}
 			// Don't fail the entire user processing if CSV upload fails
 		}
 	}
 
-	return result, nil
-}
+	// Upload the user's migration report when files were uploaded or failed, so it shows what changed
+	if p.config.MigrationReport && p.config.BoxEnabled && p.destination != nil && !p.config.DryRun &&
+		(result.UploadedCount > 0 || result.ErrorCount > 0) && !result.Interrupted && !result.TimedOut {
+		if err := p.uploadMigrationReport(ctx, zoomEmail, boxEmail, recordings, result); err != nil && logger != nil {
+			logger.WarnWithContext(ctx, fmt.Sprintf("Failed to upload %s to %s for user %s: %v", MigrationReportFile, p.destination.Name(), zoomEmail, err))
+		}
+	}
 
-// recordingFileResult represents the result of processing a single recording file
-type recordingFileResult struct {
-	Downloaded bool
-	Uploaded   bool
-	Skipped    bool
-	Deleted    bool
-	Error      error
+	// Remove date folders that were created for this user but never received a file
+	if p.config.CleanupEmptyFolders && p.config.BoxEnabled && !p.config.DryRun && !result.TimedOut {
+		p.cleanupEmptyFolders(ctx, zoomEmail)
+	}
+
+	if !p.config.DryRun {
+		p.config.Hooks.Fire(ctx, hooks.Event{
+			Event:      hooks.EventUser,
+			User:       zoomEmail,
+			BoxUser:    boxEmail,
+			Downloaded: result.DownloadedCount,
+			Uploaded:   result.UploadedCount,
+			Errors:     result.ErrorCount,
+		})
+	}
+
+	return result, nil
 }
 
-// processRecordingFile processes a single recording file (download, upload, delete)
-func (p *userProcessorImpl) processRecordingFile(ctx context.Context, zoomEmail, boxEmail string, recording *zoom.Recording, recordingFile zoom.RecordingFile) *recordingFileResult {
-	result := &recordingFileResult{}
+// addFileResult waits for a started file and counts its result for the user
+// It returns stop when no further files should be started, and the file's error when the user
+// stops on it because ContinueOnError is off.
+func (p *userProcessorImpl) addFileResult(ctx context.Context, result *ProcessorResult, file *pendingFile) (stop bool, err error) {
 	logger := logging.GetDefaultLogger()
+	recording, recordingFile := file.recording, file.recordingFile
 
-	// Extract username from Box email for directory structure
-	username := email.ExtractUsername(boxEmail)
-	if username == "" {
-		result.Error = fmt.Errorf("invalid box email format: %s", boxEmail)
-		if logger != nil {
-			logger.ErrorWithContext(ctx, result.Error.Error())
+	if file.filterReason != "" {
+		result.SkippedCount++
+		recordingFiles := recording.RecordingFiles
+		if file.fileFiltered {
+			recordingFiles = []zoom.RecordingFile{recordingFile}
 		}
-		return result
+		for _, recordingFile := range recordingFiles {
+			result.Files = append(result.Files, FileOutcome{
+				RecordingUUID: recording.UUID,
+				Topic:         recording.Topic,
+				FileID:        recordingFile.ID,
+				FileType:      recordingFile.FileType,
+				Outcome:       OutcomeFiltered,
+				Reason:        file.filterReason,
+			})
+			p.trackSummary(ctx, result.ZoomEmail, recording, recordingFile, &recordingFileResult{}, OutcomeFiltered)
+		}
+		return false, nil
+	}
+
+	<-file.done
+	fileResult := file.result
+	outcome := fileResult.outcome(recording, recordingFile, file.duration)
+	result.Files = append(result.Files, outcome)
+	p.trackSummary(ctx, result.ZoomEmail, recording, recordingFile, fileResult, outcome.Outcome)
+	result.BytesDownloaded += fileResult.BytesDownloaded
+	result.BytesPlanned += fileResult.BytesPlanned
+	if p.config.Verbose && logger != nil && (outcome.DownloadMBps > 0 || outcome.UploadMBps > 0) {
+		logger.InfoWithContext(ctx, fmt.Sprintf("Transfer speed for %s: download %.1f MB/s, upload %.1f MB/s", outcome.FileName, outcome.DownloadMBps, outcome.UploadMBps))
+	}
+	if p.config.Progress != nil {
+		p.config.Progress.FileDone(recordingFile.FileSize)
+	}
+
+	// A transfer cut short by the user timeout fails the user
+	if fileResult.Error != nil && userTimedOut(ctx) {
+		p.addUserTimeout(ctx, result)
+		return true, nil
 	}
 
-	// Create directory path
-	meetingTime := recording.StartTime
-	dirPath := filepath.Join(p.config.BaseDownloadDir, username,
-		fmt.Sprintf("%04d", meetingTime.Year()),
-		fmt.Sprintf("%02d", int(meetingTime.Month())),
-		fmt.Sprintf("%02d", meetingTime.Day()))
+	// A transfer cut short by cancellation is not a failure; the file is redone next run
+	if fileResult.Error != nil && ctx.Err() != nil {
+		result.Interrupted = true
+		return true, nil
+	}
+
+	// Nothing more fits on disk; stop before the next file instead of failing mid-write
+	if errors.Is(fileResult.Error, preflight.ErrInsufficientSpace) {
+		result.DiskFull = true
+		result.ErrorCount++
+		result.Errors = append(result.Errors, fileResult.Error)
+		return true, nil
+	}
 
-	// Create directory if it doesn't exist
-	if err := os.MkdirAll(dirPath, 0755); err != nil {
-		result.Error = fmt.Errorf("failed to create directory %s: %w", dirPath, err)
+	// The user's remaining files would exceed the cap too
+	if errors.Is(fileResult.Error, ErrUserQuotaExceeded) {
+		result.ErrorCount++
+		result.Errors = append(result.Errors, fileResult.Error)
 		if logger != nil {
-			logger.ErrorWithContext(ctx, result.Error.Error())
+			logger.WarnWithContext(ctx, fmt.Sprintf("Local storage cap reached, skipping remaining recordings for user %s", result.ZoomEmail))
 		}
-		return result
+		return true, nil
 	}
 
-	// Generate filename
-	meetingFileName := p.filenameSanitizer.SanitizeTopic(recording.Topic)
-	timeStr := p.filenameSanitizer.FormatTime(meetingTime)
-	filename := fmt.Sprintf("%s-%s.%s", meetingFileName, timeStr, strings.ToLower(recordingFile.FileType))
-	filePath := filepath.Join(dirPath, filename)
+	// Update counters
+	if fileResult.Downloaded {
+		result.DownloadedCount++
+	}
+	if fileResult.Uploaded {
+		result.UploadedCount++
+	}
+	if fileResult.Skipped {
+		result.SkippedCount++
+	}
+	if fileResult.Deleted {
+		result.DeletedCount++
+	}
+	if fileResult.Error != nil {
+		result.ErrorCount++
+		result.Errors = append(result.Errors, fileResult.Error)
 
-	// Check if file already exists locally
-	if _, err := os.Stat(filePath); err == nil {
-		if p.config.Verbose && logger != nil {
-			logger.InfoWithContext(ctx, fmt.Sprintf("Skipped (already exists locally): %s", filename))
+		// Stop processing this user if not continuing on error
+		if !p.config.ContinueOnError {
+			return true, fileResult.Error
 		}
-		result.Skipped = true
-		return result
 	}
+	return false, nil
+}
 
-	// Check if file already exists in Box BEFORE downloading from Zoom
-	if p.config.BoxEnabled && p.boxUploadManager != nil {
-		boxClient := p.boxUploadManager.GetBoxClient()
-
-		// Find the user's zoom folder
-		zoomFolder, err := boxClient.FindZoomFolderByOwner(boxEmail)
-		if err == nil && zoomFolder != nil {
-			// Create folder path for this recording
-			folderPath := fmt.Sprintf("%04d/%02d/%02d",
-				meetingTime.Year(),
-				int(meetingTime.Month()),
-				meetingTime.Day())
+// addUserTimeout records that the user timeout stopped the user, once
+func (p *userProcessorImpl) addUserTimeout(ctx context.Context, result *ProcessorResult) {
+	if result.TimedOut {
+		return
+	}
+	result.TimedOut = true
+	result.ErrorCount++
+	err := fmt.Errorf("%w after %v, remaining recordings for %s are left for the next run", ErrUserTimeout, p.config.UserTimeout, result.ZoomEmail)
+	result.Errors = append(result.Errors, err)
+	if logger := logging.GetDefaultLogger(); logger != nil {
+		logger.WarnWithContext(ctx, err.Error())
+	}
+}
 
-			// Get the folder (don't create it - just check if file exists)
-			if logger != nil {
-				logger.InfoWithContext(ctx, fmt.Sprintf("Processing: %s (checking if exists in Box)", filename))
-			}
-			folder, err := box.CreateFolderPath(boxClient, folderPath, zoomFolder.ID)
-			if err == nil && folder != nil {
-				// Check if file exists in this folder
-				existingFile, err := boxClient.FindFileByName(folder.ID, filename)
-				if err == nil && existingFile != nil {
-					// File already exists in Box - skip download entirely
-					if logger != nil {
-						logger.InfoWithContext(ctx, fmt.Sprintf("Skipped (already exists in Box): %s", filename))
-					}
-					result.Skipped = true
-					return result
-				}
+// transferEstimate counts the files processRecordings may transfer for recordings and their total size
+// Files skipped later, e.g. because they already exist, still count, so the estimate is an upper bound.
+func (p *userProcessorImpl) transferEstimate(recordings []*zoom.Recording) (files int, totalBytes int64) {
+	for _, recording := range recordings {
+		if p.config.Filter.SkipReason(recording) != "" {
+			continue
+		}
+		for _, recordingFile := range recording.RecordingFiles {
+			if recordingFile.DownloadURL == "" || !p.includesFileType(recordingFile) {
+				continue
 			}
+			if p.config.Filter.SkipFileReason(recordingFile) != "" {
+				continue
+			}
+			if p.config.Limit > 0 && files >= p.config.Limit {
+				return files, totalBytes
+			}
+			files++
+			totalBytes += recordingFile.FileSize
 		}
 	}
+	return files, totalBytes
+}
 
-	// Skip if meta-only mode and this is not a metadata file
-	if p.config.MetaOnly && recordingFile.FileType == "MP4" {
-		if p.config.Verbose && logger != nil {
-			logger.InfoWithContext(ctx, fmt.Sprintf("Skipped (meta-only mode): %s", filename))
+// deadlineReached reports whether the configured run deadline has passed or a stop was requested
+func (p *userProcessorImpl) deadlineReached() bool {
+	if p.config.Stop != nil {
+		select {
+		case <-p.config.Stop:
+			return true
+		default:
 		}
-		result.Skipped = true
-		return result
 	}
+	return !p.config.Deadline.IsZero() && !time.Now().Before(p.config.Deadline)
+}
 
-	// Skip download if dry run
-	if p.config.DryRun {
-		if logger != nil {
-			logger.InfoWithContext(ctx, fmt.Sprintf("Would download: %s", filename))
+// routeUsers sends the uploads of users with a destination column in the active users file to
+// that folder; destinations that cannot route users upload to the user's root folder instead
+func (p *userProcessorImpl) routeUsers(ctx context.Context, entries []users.UserEntry) {
+	router, ok := p.destination.(storage.UserRouter)
+	for _, entry := range entries {
+		switch {
+		case ok:
+			router.RouteUser(entry.BoxEmail, entry.Destination)
+		case entry.Destination != "" && p.destination != nil:
+			if logger := logging.GetDefaultLogger(); logger != nil {
+				logger.WarnWithContext(ctx, fmt.Sprintf("%s cannot upload to custom folders, ignoring destination %s of user %s", p.destination.Name(), entry.Destination, entry.ZoomEmail))
+			}
 		}
-		result.Downloaded = true
-		return result
 	}
+}
 
-	// Start timing the total process (download + upload)
-	processingStartTime := time.Now()
-
-	// Prepare download URL and headers with access token if available
-	downloadURL := recordingFile.DownloadURL
-	headers := make(map[string]string)
+// cleanupEmptyFolders removes empty folders created during the run if the destination supports it
+func (p *userProcessorImpl) cleanupEmptyFolders(ctx context.Context, zoomEmail string) {
+	cleaner, ok := p.destination.(storage.EmptyFolderCleaner)
+	if !ok {
+		return
+	}
 
-	// Add download access token as Authorization Bearer header (not query parameter)
-	// This prevents file size limitations that occur when using query parameter tokens
-	// Use download_access_token if available, otherwise fall back to OAuth token
-	if recording.DownloadAccessToken != "" {
-		headers["Authorization"] = fmt.Sprintf("Bearer %s", recording.DownloadAccessToken)
-	} else {
-		// Fall back to OAuth access token if download_access_token is not available
-		// This happens when "View the recording content" permission is not enabled
-		oauthToken, err := p.zoomClient.GetOAuthAccessToken(ctx)
-		if err != nil {
-			result.Error = fmt.Errorf("failed to get access token for download: %w", err)
-			return result
-		}
-		headers["Authorization"] = oauthToken
+	logger := logging.GetDefaultLogger()
+	removed, err := cleaner.CleanupEmptyFolders(ctx)
+	if err != nil && logger != nil {
+		logger.WarnWithContext(ctx, fmt.Sprintf("Empty folder cleanup in %s for user %s was incomplete: %v", p.destination.Name(), zoomEmail, err))
 	}
+	if removed > 0 && logger != nil {
+		logger.InfoWithContext(ctx, fmt.Sprintf("Removed %d empty folder(s) from %s for user %s", removed, p.destination.Name(), zoomEmail))
+	}
+}
 
-	// Download the file
-	downloadReq := download.DownloadRequest{
-		ID:          fmt.Sprintf("%s-%s", recording.UUID, recordingFile.ID),
-		URL:         downloadURL,
-		Destination: filePath,
-		FileSize:    recordingFile.FileSize,
-		Headers:     headers,
-		Metadata: map[string]interface{}{
-			"user_email":    zoomEmail,
-			"meeting_id":    recording.UUID,
-			"meeting_topic": recording.Topic,
-			"file_type":     recordingFile.FileType,
-			"filename":      filename,
-		},
+// recordingFileResult represents the result of processing a single recording file
+type recordingFileResult struct {
+	Downloaded bool
+	Uploaded   bool
+	Skipped    bool
+	Deleted    bool
+	Error      error
+
+	FileName        string
+	LocalPath       string
+	BytesDownloaded int64
+	BytesPlanned    int64 // Bytes a dry run would download
+	BytesUploaded   int64
+
+	DownloadDuration time.Duration // Time spent downloading the file from Zoom
+	UploadDuration   time.Duration // Time spent uploading the file to the destination
+}
+
+// trackSummary records a recording file in the run summary with the given outcome
+func (p *userProcessorImpl) trackSummary(ctx context.Context, zoomEmail string, recording *zoom.Recording, recordingFile zoom.RecordingFile, fileResult *recordingFileResult, outcome string) {
+	if p.config.RunSummary == nil {
+		return
 	}
 
-	downloadResult, err := p.downloadManager.Download(ctx, downloadReq, nil)
-	if err != nil {
-		result.Error = fmt.Errorf("download failed for %s: %w", filename, err)
-		if logger != nil {
-			logger.ErrorWithContext(ctx, result.Error.Error())
+	entry := tracking.SummaryEntry{
+		ZoomUser:         zoomEmail,
+		MeetingTopic:     recording.Topic,
+		RecordingDate:    recording.StartTime,
+		FileName:         fileResult.FileName,
+		FileSize:         recordingFile.FileSize,
+		DownloadDuration: fileResult.DownloadDuration,
+		UploadDuration:   fileResult.UploadDuration,
+		Status:           outcome,
+	}
+	if fileResult.Error != nil {
+		entry.Error = fileResult.Error.Error()
+	}
+	if err := p.config.RunSummary.TrackFile(entry); err != nil {
+		if logger := logging.GetDefaultLogger(); logger != nil {
+			logger.WarnWithContext(ctx, fmt.Sprintf("Failed to add %s to the run summary: %v", recordingFile.ID, err))
 		}
-		return result
 	}
+}
 
-	result.Downloaded = true
-	if logger != nil {
-		logger.InfoWithContext(ctx, fmt.Sprintf("Downloaded: %s (%d bytes)", filename, downloadResult.BytesDownloaded))
+// outcome converts the result into the FileOutcome reported for the run
+func (r *recordingFileResult) outcome(recording *zoom.Recording, recordingFile zoom.RecordingFile, duration time.Duration) FileOutcome {
+	outcome := FileOutcome{
+		RecordingUUID:   recording.UUID,
+		Topic:           recording.Topic,
+		FileID:          recordingFile.ID,
+		FileType:        recordingFile.FileType,
+		FileName:        r.FileName,
+		LocalPath:       r.LocalPath,
+		Deleted:         r.Deleted,
+		BytesDownloaded: r.BytesDownloaded,
+		BytesPlanned:    r.BytesPlanned,
+		DurationSeconds: duration.Seconds(),
+		BytesUploaded:   r.BytesUploaded,
+		DownloadSeconds: r.DownloadDuration.Seconds(),
+		UploadSeconds:   r.UploadDuration.Seconds(),
+		DownloadMBps:    mbps(r.BytesDownloaded, r.DownloadDuration.Seconds()),
+		UploadMBps:      mbps(r.BytesUploaded, r.UploadDuration.Seconds()),
 	}
 
-	// Upload to Box if enabled
-	if p.config.BoxEnabled && p.boxUploadManager != nil {
-		// Upload the main file WITHOUT tracking yet (we'll track after we know the total time)
-		uploadResult, uploadErr := p.uploadToBoxWithoutTracking(ctx, filePath, zoomEmail, boxEmail, recordingFile.FileType, meetingTime)
+	switch {
+	case r.Error != nil:
+		outcome.Outcome = OutcomeFailed
+		outcome.Error = r.Error.Error()
+	case r.Uploaded:
+		outcome.Outcome = OutcomeUploaded
+	case r.Downloaded:
+		outcome.Outcome = OutcomeDownloaded
+	default:
+		outcome.Outcome = OutcomeSkipped
+	}
+	return outcome
+}
 
-		// Calculate processing time AFTER the main file upload completes
-		// This captures only the download + upload time for the main recording file (excluding metadata operations)
-		processingTime := time.Since(processingStartTime)
+// uploadMetadataFile uploads and tracks a recording's metadata JSON
+// A metadata file that is already in the destination counts as uploaded.
+func (p *userProcessorImpl) uploadMetadataFile(ctx context.Context, metadataPath, zoomEmail, boxEmail string, meetingTime time.Time) (*uploadResult, error) {
+	logger := logging.GetDefaultLogger()
+	metadataFilename := filepath.Base(metadataPath)
 
-		if uploadErr != nil {
-			result.Error = uploadErr
-			// Don't delete file if upload failed
-			return result
-		}
+	// Get file size for metadata
+	metadataFileSize := int64(0)
+	if metadataFileInfo, err := os.Stat(metadataPath); err == nil {
+		metadataFileSize = metadataFileInfo.Size()
+	}
 
-		if uploadResult.Skipped {
-			result.Skipped = true
-		} else {
-			result.Uploaded = true
+	// Use zero processing time for metadata files since they're not part of the main recording
+	metadataUploadResult, err := p.uploadAndTrack(ctx, metadataPath, boxEmail, meetingTime, 0, zoomEmail, metadataFilename, metadataFileSize)
+	if err != nil {
+		if logger != nil {
+			logger.ErrorWithContext(ctx, fmt.Sprintf("Failed to upload metadata to Box: %s - %v", metadataFilename, err))
 		}
+		return nil, err
+	}
 
-		// Now track the upload with the accurate processing time
-		p.boxUploadManager.TrackUploadWithTime(zoomEmail, filename, recordingFile.FileSize, time.Now(), processingTime)
+	if metadataUploadResult.Uploaded && logger != nil {
+		logger.InfoWithContext(ctx, fmt.Sprintf("Uploaded metadata to Box: %s", metadataFilename))
+	}
+	return metadataUploadResult, nil
+}
 
-		// Save and upload metadata file AFTER tracking the main file (for MP4 files only)
-		if recordingFile.FileType == "MP4" {
-			metadataFilename := strings.TrimSuffix(filename, filepath.Ext(filename)) + ".json"
-			metadataPath := filepath.Join(dirPath, metadataFilename)
+// recordFailure passes a failed file operation to the configured failure recorder
+func (p *userProcessorImpl) recordFailure(operation, zoomEmail, boxEmail string, recording *zoom.Recording, recordingFile zoom.RecordingFile, localPath string, err error) {
+	if p.config.FailureRecorder == nil || p.config.DryRun {
+		return
+	}
+	p.config.FailureRecorder.RecordFailure(runreport.Failure{
+		Operation:       operation,
+		ZoomEmail:       zoomEmail,
+		BoxEmail:        boxEmail,
+		Recording:       *recording,
+		RecordingFileID: recordingFile.ID,
+		LocalPath:       localPath,
+		Error:           err.Error(),
+	})
+}
 
-			// Save metadata file if it doesn't exist
-			if _, err := os.Stat(metadataPath); os.IsNotExist(err) {
-				if err := saveRecordingMetadata(ctx, recording, &recordingFile, metadataPath); err != nil {
-					if logger != nil {
-						logger.ErrorWithContext(ctx, fmt.Sprintf("Failed to save metadata %s: %v", metadataFilename, err))
-					}
-					// Don't fail the entire operation if metadata save fails
-				}
-			}
+// ReplayFailure re-executes a single failed file operation from a run report
+// Downloads are re-run from the stored recording metadata (and uploaded if a destination is enabled);
+// uploads are re-run from the local file left behind by the failed run.
+func (p *userProcessorImpl) ReplayFailure(ctx context.Context, failure runreport.Failure) error {
+	recordingFile, ok := failure.RecordingFile()
+	if !ok {
+		return fmt.Errorf("recording file %s not found in stored metadata for %s", failure.RecordingFileID, failure.Recording.UUID)
+	}
+	recording := failure.Recording
+
+	var result *recordingFileResult
+	switch failure.Operation {
+	case runreport.OperationDownload:
+		recording, recordingFile = p.refreshRecording(ctx, recording, recordingFile)
+		result = p.processRecordingFile(ctx, failure.ZoomEmail, failure.BoxEmail, &recording, recordingFile, nil)
+	case runreport.OperationUpload:
+		if !p.config.BoxEnabled || p.destination == nil {
+			return fmt.Errorf("cannot replay upload of %s: no upload destination is enabled", failure.LocalPath)
+		}
+		if _, err := os.Stat(failure.LocalPath); err != nil {
+			return fmt.Errorf("cannot replay upload of %s: %w", failure.LocalPath, err)
+		}
+		if p.config.DryRun {
+			return nil
 		}
 
-		// Upload metadata file to Box if this is an MP4 file
-		if recordingFile.FileType == "MP4" {
-			metadataFilename := strings.TrimSuffix(filename, filepath.Ext(filename)) + ".json"
-			metadataPath := filepath.Join(dirPath, metadataFilename)
-
-			// Check if metadata file exists before uploading
-			if _, err := os.Stat(metadataPath); err == nil {
-				// Get file size for metadata
-				metadataFileInfo, _ := os.Stat(metadataPath)
-				metadataFileSize := int64(0)
-				if metadataFileInfo != nil {
-					metadataFileSize = metadataFileInfo.Size()
-				}
-
-				// Use zero processing time for metadata files since they're not part of the main recording
-				metadataUploadResult, metadataUploadErr := p.uploadToBox(ctx, metadataPath, boxEmail, "JSON", meetingTime, 0, zoomEmail, metadataFilename, metadataFileSize)
-				if metadataUploadErr != nil {
-					if logger != nil {
-						logger.ErrorWithContext(ctx, fmt.Sprintf("Failed to upload metadata to Box: %s - %v", metadataFilename, metadataUploadErr))
-					}
-					// Don't fail the entire operation if metadata upload fails
-				} else if metadataUploadResult.Uploaded || metadataUploadResult.Skipped {
-					if metadataUploadResult.Uploaded && logger != nil {
-						logger.InfoWithContext(ctx, fmt.Sprintf("Uploaded metadata to Box: %s", metadataFilename))
-					}
-					// Delete metadata file after successful upload or if already in Box (if configured)
-					if p.config.DeleteAfterUpload {
-						if err := os.Remove(metadataPath); err != nil {
-							if logger != nil {
-								logger.ErrorWithContext(ctx, fmt.Sprintf("Failed to delete metadata after upload: %s - %v", metadataPath, err))
-							}
-						} else if logger != nil {
-							logger.InfoWithContext(ctx, fmt.Sprintf("Deleted local metadata after upload: %s", metadataFilename))
-						}
-					}
-				}
+		// Recordings encrypted at rest are checksummed over their plaintext, like after a download
+		var checksum string
+		if p.config.ChecksumAlgorithm != "" {
+			if file, err := atrest.Open(p.config.Encryption, failure.LocalPath); err == nil {
+				checksum, _ = download.CalculateChecksumWith(file, p.config.ChecksumAlgorithm)
+				file.Close()
 			}
 		}
 
-		// Delete local file after successful upload or if it was skipped (already in Box)
-		if p.config.DeleteAfterUpload && (uploadResult.Uploaded || uploadResult.Skipped) {
-			if err := os.Remove(filePath); err != nil {
-				if logger != nil {
-					logger.ErrorWithContext(ctx, fmt.Sprintf("Failed to delete file after upload: %s - %v", filePath, err))
-				}
-			} else {
-				result.Deleted = true
-				if logger != nil {
-					logger.InfoWithContext(ctx, fmt.Sprintf("Deleted local file after upload: %s", filename))
-				}
+		var thumbnailPath string
+		if p.config.Thumbnails && recordingFile.FileType == "MP4" {
+			candidate := strings.TrimSuffix(failure.LocalPath, filepath.Ext(failure.LocalPath)) + thumbnailExtension(recording.ThumbnailURLFor(recordingFile))
+			if _, err := os.Stat(candidate); err == nil {
+				thumbnailPath = candidate
 			}
 		}
+
+		result = &recordingFileResult{Downloaded: true}
+		p.uploadRecordingFile(ctx, result, failure.ZoomEmail, failure.BoxEmail, &recording, recordingFile, failure.LocalPath, checksum, thumbnailPath, time.Now())
+	default:
+		return fmt.Errorf("unknown operation %q", failure.Operation)
 	}
 
-	return result
+	return result.Error
 }
 
-// uploadResult represents the result of a Box upload
-type uploadResult struct {
-	Uploaded bool
-	Skipped  bool
-	Error    error
+// refreshRecording looks a failed recording up in Zoom again before it is downloaded
+// Stored download URLs and tokens expire, and recordings whose UUID needs double encoding could
+// not be looked up before it was applied. The stored metadata is used when the lookup fails.
+func (p *userProcessorImpl) refreshRecording(ctx context.Context, recording zoom.Recording, recordingFile zoom.RecordingFile) (zoom.Recording, zoom.RecordingFile) {
+	getter, ok := p.zoomClient.(MeetingRecordingGetter)
+	if !ok {
+		return recording, recordingFile
+	}
+
+	logger := logging.GetDefaultLogger()
+	current, err := getter.GetMeetingRecordings(ctx, recording.UUID)
+	if err != nil || current == nil {
+		if err != nil && logger != nil {
+			logger.WarnWithContext(ctx, fmt.Sprintf("Could not refresh recording %s from Zoom, using the stored metadata: %v", recording.UUID, err))
+		}
+		return recording, recordingFile
+	}
+	for _, file := range current.RecordingFiles {
+		if file.ID == recordingFile.ID {
+			if logger != nil {
+				logger.InfoWithContext(ctx, fmt.Sprintf("Refreshed recording %s from Zoom", recording.UUID))
+			}
+			return *current, file
+		}
+	}
+	return recording, recordingFile
 }
 
-// uploadToBoxWithoutTracking uploads a file to Box without tracking (tracking done by caller)
-func (p *userProcessorImpl) uploadToBoxWithoutTracking(ctx context.Context, localPath, zoomEmail, boxEmail, fileType string, recordingTime time.Time) (*uploadResult, error) {
+// downloadThumbnail downloads the recording's thumbnail next to the video file
+// It returns the local thumbnail path, or "" if there is no thumbnail or the download failed.
+// Thumbnails are best effort: failures are logged but never fail the recording.
+func (p *userProcessorImpl) downloadThumbnail(ctx context.Context, recording *zoom.Recording, recordingFile zoom.RecordingFile, videoPath string, headers map[string]string) string {
 	logger := logging.GetDefaultLogger()
-	result := &uploadResult{}
 
-	// Get Box client from upload manager
-	boxClient := p.boxUploadManager.GetBoxClient()
+	thumbnailURL := recording.ThumbnailURLFor(recordingFile)
+	if thumbnailURL == "" {
+		if p.config.Verbose && logger != nil {
+			logger.InfoWithContext(ctx, fmt.Sprintf("No thumbnail available for: %s", filepath.Base(videoPath)))
+		}
+		return ""
+	}
 
-	// Find the user's zoom folder in Box using their email
-	zoomFolder, err := boxClient.FindZoomFolderByOwner(boxEmail)
+	thumbnailPath := strings.TrimSuffix(videoPath, filepath.Ext(videoPath)) + thumbnailExtension(thumbnailURL)
+	_, err := p.downloadManager.Download(ctx, download.DownloadRequest{
+		ID:          fmt.Sprintf("%s-%s-thumbnail", recording.UUID, recordingFile.ID),
+		URL:         thumbnailURL,
+		Destination: thumbnailPath,
+		Headers:     headers,
+		Metadata: map[string]interface{}{
+			"meeting_id": recording.UUID,
+			"file_type":  "THUMBNAIL",
+			"filename":   filepath.Base(thumbnailPath),
+		},
+	}, nil)
 	if err != nil {
-		result.Error = fmt.Errorf("failed to find zoom folder for user %s: %w", boxEmail, err)
 		if logger != nil {
-			logger.ErrorWithContext(ctx, result.Error.Error())
+			logger.WarnWithContext(ctx, fmt.Sprintf("Failed to download thumbnail for %s: %v", filepath.Base(videoPath), err))
 		}
-		return result, result.Error
+		return ""
 	}
 
 	if logger != nil {
-		logger.InfoWithContext(ctx, fmt.Sprintf("Found zoom folder for %s: %s", boxEmail, zoomFolder.ID))
+		logger.InfoWithContext(ctx, fmt.Sprintf("Downloaded thumbnail: %s", filepath.Base(thumbnailPath)))
 	}
+	return thumbnailPath
+}
 
-	// Set the upload manager's base folder to the user's zoom folder
-	p.boxUploadManager.SetBaseFolderID(zoomFolder.ID)
+// uploadThumbnail uploads a downloaded thumbnail and removes it locally if configured
+func (p *userProcessorImpl) uploadThumbnail(ctx context.Context, thumbnailPath, zoomEmail, boxEmail string, recordingTime time.Time) {
+	logger := logging.GetDefaultLogger()
+	thumbnailFilename := filepath.Base(thumbnailPath)
 
-	// Use recording time (from Zoom metadata) to create folder structure
-	folderPath := fmt.Sprintf("%04d/%02d/%02d",
-		recordingTime.Year(),
-		int(recordingTime.Month()),
-		recordingTime.Day())
+	var thumbnailSize int64
+	if info, err := os.Stat(thumbnailPath); err == nil {
+		thumbnailSize = info.Size()
+	}
 
-	// Create/get the folder structure using the user's zoom folder as parent
-	folder, err := box.CreateFolderPath(boxClient, folderPath, zoomFolder.ID)
+	result, err := p.uploadAndTrack(ctx, thumbnailPath, boxEmail, recordingTime, 0, zoomEmail, thumbnailFilename, thumbnailSize)
 	if err != nil {
-		result.Error = fmt.Errorf("failed to create Box folder structure: %w", err)
 		if logger != nil {
-			logger.ErrorWithContext(ctx, result.Error.Error())
+			logger.ErrorWithContext(ctx, fmt.Sprintf("Failed to upload thumbnail to %s: %s - %v", p.destination.Name(), thumbnailFilename, err))
 		}
-		return result, result.Error
+		return
 	}
 
-	baseFileName := filepath.Base(localPath)
-
-	// Check if file already exists in Box (check-before-upload)
-	existingFile, err := boxClient.FindFileByName(folder.ID, baseFileName)
-	if err == nil && existingFile != nil {
-		// File already exists in Box - skip upload (tracking done by caller)
-		result.Skipped = true
-		if logger != nil {
-			logger.InfoWithContext(ctx, fmt.Sprintf("Skipped Box upload (file already exists): %s", baseFileName))
+	if p.config.DeleteAfterUpload && (result.Uploaded || result.Skipped) && p.deletionVerified(ctx, result, thumbnailPath) {
+		if err := os.Remove(thumbnailPath); err != nil {
+			if logger != nil {
+				logger.ErrorWithContext(ctx, fmt.Sprintf("Failed to delete thumbnail after upload: %s - %v", thumbnailPath, err))
+			}
+		} else if logger != nil {
+			logger.InfoWithContext(ctx, fmt.Sprintf("Deleted local thumbnail after upload: %s", thumbnailFilename))
 		}
-		return result, nil
 	}
+}
 
-	// File doesn't exist - proceed with upload (without tracking - tracking done by caller)
-	uploadResult, err := p.boxUploadManager.UploadFileWithEmailMapping(ctx, localPath, zoomEmail, boxEmail, fmt.Sprintf("upload-%s", baseFileName), nil)
-	if err != nil {
-		result.Error = fmt.Errorf("Box upload failed for %s: %w", baseFileName, err)
-		if logger != nil {
-			logger.ErrorWithContext(ctx, result.Error.Error())
+// thumbnailExtension returns the image extension from a thumbnail URL, defaulting to .jpg
+func thumbnailExtension(thumbnailURL string) string {
+	if parsed, err := url.Parse(thumbnailURL); err == nil {
+		switch ext := strings.ToLower(path.Ext(parsed.Path)); ext {
+		case ".jpg", ".jpeg", ".png", ".webp":
+			return ext
 		}
-		return result, result.Error
 	}
+	return ".jpg"
+}
 
-	result.Uploaded = true
-	if logger != nil {
-		logger.InfoWithContext(ctx, fmt.Sprintf("Uploaded to Box: %s (file ID: %s)", baseFileName, uploadResult.FileID))
-	}
+// uploadResult represents the result of a Box upload
+type uploadResult struct {
+	Uploaded bool
+	Skipped  bool
+	Error    error
 
-	return result, nil
+	FileID   string // ID of the file in the destination
+	Verified bool   // The destination confirmed the stored file's size and checksum
 }
 
-// uploadToBox uploads a file to Box with check-before-upload logic (kept for metadata uploads)
-// Uses the recording time (from Zoom metadata) to determine the Box folder structure
-func (p *userProcessorImpl) uploadToBox(ctx context.Context, localPath, boxEmail, fileType string, recordingTime time.Time, processingTime time.Duration, zoomEmail, fileName string, fileSize int64) (*uploadResult, error) {
+// uploadToDestination uploads a file without tracking (tracking done by caller)
+// Uses the recording time (from Zoom metadata) to determine the folder structure
+// progress receives upload progress (nil = not reported)
+func (p *userProcessorImpl) uploadToDestination(ctx context.Context, localPath, zoomEmail, boxEmail string, recordingTime time.Time, progress storage.ProgressFunc) (*uploadResult, error) {
+	folderPath, err := p.directoryLayout.FolderPath(email.ExtractUsername(boxEmail), recordingTime)
+	if err != nil {
+		return &uploadResult{Error: err}, err
+	}
+	return p.uploadToFolder(ctx, localPath, zoomEmail, boxEmail, folderPath, progress)
+}
+
+// uploadToFolder uploads a file into folderPath below the user's destination folder without tracking
+func (p *userProcessorImpl) uploadToFolder(ctx context.Context, localPath, zoomEmail, boxEmail, folderPath string, progress storage.ProgressFunc) (*uploadResult, error) {
 	logger := logging.GetDefaultLogger()
 	result := &uploadResult{}
+	baseFileName := filepath.Base(localPath)
 
-	// Get Box client from upload manager
-	boxClient := p.boxUploadManager.GetBoxClient()
-
-	// Find the user's zoom folder in Box using their email
-	zoomFolder, err := boxClient.FindZoomFolderByOwner(boxEmail)
+	uploaded, err := p.destination.UploadFile(ctx, storage.UploadRequest{
+		LocalPath:  localPath,
+		ZoomEmail:  zoomEmail,
+		UserEmail:  boxEmail,
+		FolderPath: folderPath,
+		FileName:   baseFileName,
+		Progress:   progress,
+	})
 	if err != nil {
-		result.Error = fmt.Errorf("failed to find zoom folder for user %s: %w", boxEmail, err)
+		result.Error = fmt.Errorf("%s upload failed for %s: %w", p.destination.Name(), baseFileName, err)
 		if logger != nil {
 			logger.ErrorWithContext(ctx, result.Error.Error())
 		}
 		return result, result.Error
 	}
 
-	if logger != nil {
-		logger.InfoWithContext(ctx, fmt.Sprintf("Found zoom folder for %s: %s", boxEmail, zoomFolder.ID))
+	result.FileID = uploaded.FileID
+	result.Verified = uploaded.Verified
+	if uploaded.Skipped {
+		result.Skipped = true
+		if logger != nil {
+			logger.InfoWithContext(ctx, fmt.Sprintf("Skipped %s upload (file already exists): %s", p.destination.Name(), baseFileName))
+		}
+		return result, nil
 	}
 
-	// Set the upload manager's base folder to the user's zoom folder
-	// This ensures files are uploaded to: zoomFolder/<year>/<month>/<day>/
-	p.boxUploadManager.SetBaseFolderID(zoomFolder.ID)
+	result.Uploaded = true
+	if logger != nil {
+		logger.InfoWithContext(ctx, fmt.Sprintf("Uploaded to %s: %s (file ID: %s)", p.destination.Name(), baseFileName, uploaded.FileID))
+	}
 
-	// Use recording time (from Zoom metadata) to create folder structure
-	// Create folder path: <year>/<month>/<day> (within user's zoom folder)
-	folderPath := fmt.Sprintf("%04d/%02d/%02d",
-		recordingTime.Year(),
-		int(recordingTime.Month()),
-		recordingTime.Day())
+	return result, nil
+}
 
-	// Create/get the folder structure using the user's zoom folder as parent
-	folder, err := box.CreateFolderPath(boxClient, folderPath, zoomFolder.ID)
-	if err != nil {
-		result.Error = fmt.Errorf("failed to create Box folder structure: %w", err)
-		if logger != nil {
-			logger.ErrorWithContext(ctx, result.Error.Error())
-		}
-		return result, result.Error
+// deletionVerified reports whether a local file may be deleted after its upload
+// With DeleteRequiresVerification, the destination must have confirmed the stored file's size
+// and checksum; files it skipped as already present are verified now. Unverified files are kept.
+func (p *userProcessorImpl) deletionVerified(ctx context.Context, result *uploadResult, localPath string) bool {
+	if !p.config.DeleteRequiresVerification || result.Verified {
+		return true
 	}
+	logger := logging.GetDefaultLogger()
 
-	baseFileName := filepath.Base(localPath)
-
-	// Check if file already exists in Box (check-before-upload)
-	existingFile, err := boxClient.FindFileByName(folder.ID, baseFileName)
-	if err == nil && existingFile != nil {
-		// File already exists in Box - skip upload but still track it with processing time
-		result.Skipped = true
+	verifier, ok := p.destination.(storage.UploadVerifier)
+	if !ok || result.FileID == "" {
 		if logger != nil {
-			logger.InfoWithContext(ctx, fmt.Sprintf("Skipped Box upload (file already exists): %s", baseFileName))
+			logger.WarnWithContext(ctx, fmt.Sprintf("Keeping local file %s: %s uploads cannot be verified", filepath.Base(localPath), p.destination.Name()))
 		}
-
-		// Track the skipped upload with processing time
-		p.boxUploadManager.TrackUploadWithTime(zoomEmail, fileName, fileSize, time.Now(), processingTime)
-
-		return result, nil
+		return false
 	}
-
-	// File doesn't exist - proceed with upload
-	// The upload manager will use the baseFolderID (zoomFolder.ID) we set above
-	uploadResult, err := p.boxUploadManager.UploadFileWithEmailMappingWithTime(ctx, localPath, zoomEmail, boxEmail, fmt.Sprintf("upload-%s", baseFileName), nil, processingTime, zoomEmail, fileSize)
-	if err != nil {
-		result.Error = fmt.Errorf("Box upload failed for %s: %w", baseFileName, err)
+	if err := verifier.VerifyUpload(ctx, result.FileID, localPath); err != nil {
 		if logger != nil {
-			logger.ErrorWithContext(ctx, result.Error.Error())
+			logger.WarnWithContext(ctx, fmt.Sprintf("Keeping local file %s: %s copy could not be verified: %v", filepath.Base(localPath), p.destination.Name(), err))
 		}
-		return result, result.Error
+		return false
 	}
+	result.Verified = true
+	return true
+}
 
-	result.Uploaded = true
-	if logger != nil {
-		logger.InfoWithContext(ctx, fmt.Sprintf("Uploaded to Box: %s (file ID: %s)", baseFileName, uploadResult.FileID))
+// uploadAndTrack uploads a file and tracks it with the given processing time (kept for metadata uploads)
+// Skipped files are tracked as well since they are already present in the destination
+func (p *userProcessorImpl) uploadAndTrack(ctx context.Context, localPath, boxEmail string, recordingTime time.Time, processingTime time.Duration, zoomEmail, fileName string, fileSize int64) (*uploadResult, error) {
+	result, err := p.uploadToDestination(ctx, localPath, zoomEmail, boxEmail, recordingTime, nil)
+	if err != nil {
+		return result, err
 	}
 
+	p.destination.TrackUploadWithTime(zoomEmail, fileName, fileSize, timefmt.Now(), processingTime)
 	return result, nil
 }
 
@@ -711,24 +1309,48 @@ func (p *userProcessorImpl) ProcessAllUsers(ctx context.Context, usersFile *user
 	// Get incomplete users
 	incompleteUsers := usersFile.GetIncompleteUsers()
 	summary.TotalUsers = len(incompleteUsers)
+	p.routeUsers(ctx, incompleteUsers)
+	if p.config.AccountRecordings && len(incompleteUsers) > 1 {
+		p.listAccountRecordings(ctx)
+		defer p.clearAccountRecordings()
+	}
+
+	// Users that failed on transient errors, processed again once the others are done
+	var transient []transientUser
 
 	if logger != nil {
 		logger.InfoWithContext(ctx, fmt.Sprintf("Processing %d incomplete users", summary.TotalUsers))
 	}
+	if p.config.Progress != nil {
+		p.config.Progress.StartRun(summary.TotalUsers)
+	}
 
 	// Process each user serially
 	for _, userEntry := range incompleteUsers {
 		select {
 		case <-ctx.Done():
+			summary.Interrupted = true
+			summary.Duration = time.Since(startTime)
 			return summary, ctx.Err()
 		default:
 		}
 
+		if p.deadlineReached() {
+			summary.TimeBoxed = true
+			if logger != nil {
+				logger.InfoWithContext(ctx, fmt.Sprintf("Run deadline reached, leaving %d users for the next run", summary.TotalUsers-len(summary.UserResults)))
+			}
+			break
+		}
+
 		if logger != nil {
 			logger.InfoWithContext(ctx, fmt.Sprintf("Processing user: %s → %s", userEntry.ZoomEmail, userEntry.BoxEmail))
 		}
 
 		// Process the user
+		if p.config.Progress != nil {
+			p.config.Progress.NextUser()
+		}
 		userResult, err := p.ProcessUser(ctx, userEntry.ZoomEmail, userEntry.BoxEmail)
 		summary.UserResults = append(summary.UserResults, userResult)
 
@@ -738,6 +1360,41 @@ func (p *userProcessorImpl) ProcessAllUsers(ctx context.Context, usersFile *user
 		summary.TotalSkipped += userResult.SkippedCount
 		summary.TotalErrors += userResult.ErrorCount
 		summary.TotalDeleted += userResult.DeletedCount
+		summary.TotalBytesDownloaded += userResult.BytesDownloaded
+		summary.TotalBytesPlanned += userResult.BytesPlanned
+
+		if userResult.Interrupted || ctx.Err() != nil {
+			// Leave upload_complete=false so the next run resumes this user
+			summary.Interrupted = true
+			if logger != nil {
+				logger.WarnWithContext(ctx, fmt.Sprintf("User %s was interrupted and stays incomplete", userEntry.ZoomEmail))
+			}
+			summary.Duration = time.Since(startTime)
+			return summary, ctx.Err()
+		}
+
+		if userResult.DiskFull {
+			// Leave upload_complete=false so the next run resumes this user
+			summary.DiskFull = true
+			summary.FailedUsers++
+			if logger != nil {
+				logger.ErrorWithContext(ctx, fmt.Sprintf("Output disk is full, leaving %s and %d more users for the next run",
+					userEntry.ZoomEmail, summary.TotalUsers-len(summary.UserResults)))
+			}
+			break
+		}
+
+		if userResult.TimeBoxed && err == nil {
+			// Leave upload_complete=false so the next run resumes this user
+			summary.TimeBoxed = true
+			if userResult.ErrorCount > 0 {
+				summary.FailedUsers++
+			}
+			if logger != nil {
+				logger.InfoWithContext(ctx, fmt.Sprintf("User %s was not finished before the run deadline and stays incomplete", userEntry.ZoomEmail))
+			}
+			break
+		}
 
 		if err != nil || userResult.ErrorCount > 0 {
 			summary.FailedUsers++
@@ -754,6 +1411,9 @@ func (p *userProcessorImpl) ProcessAllUsers(ctx context.Context, usersFile *user
 					logger.ErrorWithContext(ctx, fmt.Sprintf("Failed to update user status for %s: %v", userEntry.ZoomEmail, markErr))
 				}
 			}
+			if p.config.TransientRetries > 0 && transientFailure(userResult, err) {
+				transient = append(transient, transientUser{entry: userEntry, index: len(summary.UserResults) - 1})
+			}
 		} else {
 			summary.ProcessedUsers++
 
@@ -770,6 +1430,13 @@ func (p *userProcessorImpl) ProcessAllUsers(ctx context.Context, usersFile *user
 		}
 	}
 
+	if len(transient) > 0 && !summary.TimeBoxed && !summary.DiskFull {
+		if err := p.retryTransientUsers(ctx, usersFile, summary, transient); err != nil {
+			summary.Duration = time.Since(startTime)
+			return summary, err
+		}
+	}
+
 	summary.Duration = time.Since(startTime)
 
 	if logger != nil {
@@ -780,8 +1447,8 @@ func (p *userProcessorImpl) ProcessAllUsers(ctx context.Context, usersFile *user
 	return summary, nil
 }
 
-// uploadUserCSVToBox uploads the user's uploads.csv file to their Box zoom folder
-func (p *userProcessorImpl) uploadUserCSVToBox(ctx context.Context, zoomEmail, boxEmail string) error {
+// uploadUserCSV uploads the user's uploads.csv file to the root of their zoom folder
+func (p *userProcessorImpl) uploadUserCSV(ctx context.Context, zoomEmail, boxEmail string) error {
 	logger := logging.GetDefaultLogger()
 
 	// Extract username from Box email
@@ -798,35 +1465,29 @@ func (p *userProcessorImpl) uploadUserCSVToBox(ctx context.Context, zoomEmail, b
 	if _, err := os.Stat(csvFilePath); os.IsNotExist(err) {
 		// CSV file doesn't exist, nothing to upload
 		if logger != nil {
-			logger.InfoWithContext(ctx, fmt.Sprintf("No uploads.csv found for user %s, skipping upload to Box", zoomEmail))
+			logger.InfoWithContext(ctx, fmt.Sprintf("No uploads.csv found for user %s, skipping upload to %s", zoomEmail, p.destination.Name()))
 		}
 		return nil
 	}
 
 	if logger != nil {
-		logger.InfoWithContext(ctx, fmt.Sprintf("Uploading uploads.csv to Box for user %s", zoomEmail))
-	}
-
-	// Get the base folder ID (should be the user's zoom folder)
-	baseFolderID := p.boxUploadManager.GetBaseFolderID()
-	if baseFolderID == "" || baseFolderID == box.RootFolderID {
-		return fmt.Errorf("base folder ID not set for Box uploads")
+		logger.InfoWithContext(ctx, fmt.Sprintf("Uploading uploads.csv to %s for user %s", p.destination.Name(), zoomEmail))
 	}
 
 	// Upload the CSV file to the zoom folder root (not in date subfolders)
-	boxClient := p.boxUploadManager.GetBoxClient()
-	if boxClient == nil {
-		return fmt.Errorf("box client not available")
-	}
-
-	// Upload the file
-	file, err := boxClient.UploadFileWithProgress(csvFilePath, baseFolderID, "uploads.csv", nil)
+	file, err := p.destination.UploadFile(ctx, storage.UploadRequest{
+		LocalPath: csvFilePath,
+		ZoomEmail: zoomEmail,
+		UserEmail: boxEmail,
+		FileName:  "uploads.csv",
+		Overwrite: true,
+	})
 	if err != nil {
 		return fmt.Errorf("failed to upload uploads.csv: %w", err)
 	}
 
 	if logger != nil {
-		logger.InfoWithContext(ctx, fmt.Sprintf("Successfully uploaded uploads.csv to Box for user %s (file ID: %s)", zoomEmail, file.ID))
+		logger.InfoWithContext(ctx, fmt.Sprintf("Successfully uploaded uploads.csv to %s for user %s (file ID: %s)", p.destination.Name(), zoomEmail, file.FileID))
 	}
 
 	return nil
@@ -834,9 +1495,87 @@ func (p *userProcessorImpl) uploadUserCSVToBox(ctx context.Context, zoomEmail, b
 
 // Helper functions
 
-// saveRecordingMetadata saves the recording metadata as a JSON file
+// existsInDestination reports whether a file is already in the destination and, for destinations
+// that report sizes, whether its size differs from expectedSize (0 = size unknown, not compared)
+func (p *userProcessorImpl) existsInDestination(ctx context.Context, boxEmail, folderPath, filename string, expectedSize int64) (exists bool, sizeMismatch bool) {
+	if sizer, ok := p.destination.(storage.FileSizer); ok && expectedSize > 0 {
+		size, exists, err := sizer.ExistingFileSize(ctx, boxEmail, folderPath, filename)
+		if err != nil || !exists {
+			return false, false
+		}
+		return true, size != expectedSize
+	}
+
+	exists, err := p.destination.FileExists(ctx, boxEmail, folderPath, filename)
+	return err == nil && exists, false
+}
+
+// planDestination looks up a file for a dry run without creating anything in the destination
+// The user's root folder and each folder that would be created are logged once per run.
+func (p *userProcessorImpl) planDestination(ctx context.Context, planner storage.UploadPlanner, boxEmail, folderPath, filename string, expectedSize int64) (exists bool, sizeMismatch bool) {
+	logger := logging.GetDefaultLogger()
+
+	plan, err := planner.PlanUpload(ctx, boxEmail, folderPath, filename)
+	if err != nil {
+		if logger != nil {
+			logger.WarnWithContext(ctx, fmt.Sprintf("Could not plan %s upload of %s: %v", p.destination.Name(), filename, err))
+		}
+		return false, false
+	}
+
+	if p.plannedFolders == nil {
+		p.plannedFolders = make(map[string]bool)
+	}
+	if rootKey := boxEmail + "/"; !p.plannedFolders[rootKey] {
+		p.plannedFolders[rootKey] = true
+		if logger != nil {
+			logger.InfoWithContext(ctx, fmt.Sprintf("%s folder for %s: %s", p.destination.Name(), boxEmail, plan.RootFolder))
+		}
+	}
+	for _, folder := range plan.MissingFolders {
+		key := boxEmail + "/" + folder
+		if p.plannedFolders[key] {
+			continue
+		}
+		p.plannedFolders[key] = true
+		if logger != nil {
+			logger.InfoWithContext(ctx, fmt.Sprintf("Would create %s folder: %s", p.destination.Name(), folder))
+		}
+	}
+
+	if !plan.Exists {
+		return false, false
+	}
+	return true, expectedSize > 0 && plan.ExistingSize != expectedSize
+}
+
+// previewByteRange estimates the leading bytes of a recording file covering the first minutes,
+// assuming a constant bitrate; returns 0 when the file size or duration is unknown
+func previewByteRange(recording *zoom.Recording, recordingFile zoom.RecordingFile, minutes int) int64 {
+	if recordingFile.FileSize <= 0 {
+		return 0
+	}
+
+	duration := recordingFile.RecordingEnd.Sub(recordingFile.RecordingStart)
+	if duration <= 0 {
+		duration = time.Duration(recording.Duration) * time.Minute
+	}
+	if duration <= 0 {
+		return 0
+	}
+
+	preview := time.Duration(minutes) * time.Minute
+	if preview >= duration {
+		return recordingFile.FileSize
+	}
+	return int64(float64(recordingFile.FileSize) * preview.Seconds() / duration.Seconds())
+}
+
+// saveRecordingMetadata saves the recording metadata in the serializer's format
 // This includes both the meeting/recording details and the specific file information
-func saveRecordingMetadata(ctx context.Context, recording *zoom.Recording, recordingFile *zoom.RecordingFile, metadataPath string) error {
+// A non-empty checksum is recorded along with the algorithm that produced it, and a
+// non-empty thumbnailFile references the poster image stored next to the recording
+func saveRecordingMetadata(ctx context.Context, serializer MetadataSerializer, recording *zoom.Recording, recordingFile *zoom.RecordingFile, checksum, thumbnailFile string, details *meetingDetails, metadataPath string) error {
 	logger := logging.GetDefaultLogger()
 
 	// Create metadata structure that combines recording and file details
@@ -867,14 +1606,28 @@ func saveRecordingMetadata(ctx context.Context, recording *zoom.Recording, recor
 		},
 	}
 
-	// Marshal to JSON with pretty printing
-	jsonData, err := json.MarshalIndent(metadata, "", "  ")
+	if checksum != "" {
+		algorithm, _ := download.SplitChecksum(checksum)
+		fileInfo := metadata["recording_file"].(map[string]interface{})
+		fileInfo["checksum"] = checksum
+		fileInfo["checksum_algorithm"] = string(algorithm)
+	}
+
+	if thumbnailFile != "" {
+		metadata["thumbnail"] = map[string]interface{}{
+			"file_name": thumbnailFile,
+			"url":       recording.ThumbnailURLFor(*recordingFile),
+		}
+	}
+
+	addMeetingDetails(metadata, details)
+
+	data, err := serializer.Marshal(metadata)
 	if err != nil {
 		return fmt.Errorf("failed to marshal recording metadata: %w", err)
 	}
 
-	// Write the JSON data to file
-	if err := os.WriteFile(metadataPath, jsonData, 0644); err != nil {
+	if err := os.WriteFile(metadataPath, data, 0644); err != nil {
 		return fmt.Errorf("failed to write metadata file %s: %w", metadataPath, err)
 	}
 
@@ -1,18 +1,30 @@
 package processor
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/curtbushko/zoom-to-box/internal/atrest"
 	"github.com/curtbushko/zoom-to-box/internal/box"
 	"github.com/curtbushko/zoom-to-box/internal/directory"
 	"github.com/curtbushko/zoom-to-box/internal/download"
 	"github.com/curtbushko/zoom-to-box/internal/filename"
+	"github.com/curtbushko/zoom-to-box/internal/hooks"
+	"github.com/curtbushko/zoom-to-box/internal/preflight"
+	"github.com/curtbushko/zoom-to-box/internal/runreport"
+	"github.com/curtbushko/zoom-to-box/internal/storage"
 	"github.com/curtbushko/zoom-to-box/internal/tracking"
 	"github.com/curtbushko/zoom-to-box/internal/users"
 	"github.com/curtbushko/zoom-to-box/internal/zoom"
@@ -21,9 +33,16 @@ import (
 // Mock implementations for testing
 
 type mockZoomClient struct {
+	meetings   map[string]*zoom.Recording // Returned by GetMeetingRecordings, keyed by UUID
 	recordings map[string][]*zoom.Recording
 	recordingsError error
 	lastCallParams *zoom.ListRecordingsParams // Track last call parameters
+
+	recordingsErrors map[string][]error // Returned by successive GetAllUserRecordings calls for a user
+	recordingsCalls  map[string]int     // Number of GetAllUserRecordings calls per user
+
+	fileContents      map[string]string // Written by DownloadRecordingFile by download URL
+	downloadFileError error             // Returned by DownloadRecordingFile
 }
 
 func newMockZoomClient() *mockZoomClient {
@@ -39,6 +58,15 @@ func (m *mockZoomClient) GetAllUserRecordings(ctx context.Context, userID string
 	if m.recordingsError != nil {
 		return nil, m.recordingsError
 	}
+	if m.recordingsCalls != nil {
+		m.recordingsCalls[userID]++
+	}
+	if errs := m.recordingsErrors[userID]; len(errs) > 0 {
+		m.recordingsErrors[userID] = errs[1:]
+		if errs[0] != nil {
+			return nil, errs[0]
+		}
+	}
 	return m.recordings[userID], nil
 }
 
@@ -47,11 +75,15 @@ func (m *mockZoomClient) ListUserRecordings(ctx context.Context, userID string,
 }
 
 func (m *mockZoomClient) GetMeetingRecordings(ctx context.Context, meetingID string) (*zoom.Recording, error) {
-	return nil, nil
+	return m.meetings[meetingID], nil
 }
 
 func (m *mockZoomClient) DownloadRecordingFile(ctx context.Context, downloadURL string, writer io.Writer) error {
-	return nil
+	if m.downloadFileError != nil {
+		return m.downloadFileError
+	}
+	_, err := io.WriteString(writer, m.fileContents[downloadURL])
+	return err
 }
 
 func (m *mockZoomClient) GetOAuthAccessToken(ctx context.Context) (string, error) {
@@ -62,6 +94,8 @@ type mockDownloadManager struct {
 	downloadResults   map[string]*download.DownloadResult
 	downloadError     error
 	downloadAttempted []string // Track which files were attempted to download
+	downloadDelay     time.Duration
+	requests          []download.DownloadRequest
 }
 
 func newMockDownloadManager() *mockDownloadManager {
@@ -74,6 +108,8 @@ func newMockDownloadManager() *mockDownloadManager {
 func (m *mockDownloadManager) Download(ctx context.Context, req download.DownloadRequest, progressCallback download.ProgressCallback) (*download.DownloadResult, error) {
 	// Track that download was attempted
 	m.downloadAttempted = append(m.downloadAttempted, req.Destination)
+	m.requests = append(m.requests, req)
+	time.Sleep(m.downloadDelay)
 
 	if m.downloadError != nil {
 		return nil, m.downloadError
@@ -108,7 +144,25 @@ type mockBoxClient struct {
 	findFileError       error
 	findZoomFolderError error
 	existingFiles       map[string]bool
+	existingSizes       map[string]int64 // Size of existing files by folderID/name (default: 1024)
 	deletedFiles        []string
+	versionedFiles      []string
+
+	lookupFolders map[string]bool // parentID/name of folders FindFolderByName finds; their ID is the same key
+
+	metadata map[string]map[string]interface{} // Metadata instance values applied by file ID
+
+	asUserCalls   []string // "<operation> <userID>" for every As-User call
+	collaborators []string // "<folderID> <collaboratorID>" for every collaboration added
+
+	searchResults map[string][]*box.File // Files returned by SearchFiles by name
+	searchError   error                  // Returned by SearchFiles
+	searches      int                    // Number of SearchFiles calls
+
+	streamed map[string]string // Content read by UploadStream by file name
+
+	zoomFolderLookups int // Number of FindZoomFolderByOwner calls
+	folderListings    int // Number of ListFolderItems calls
 }
 
 func newMockBoxClient() *mockBoxClient {
@@ -116,6 +170,7 @@ func newMockBoxClient() *mockBoxClient {
 		files:         make(map[string]*box.File),
 		folders:       make(map[string]*box.Folder),
 		existingFiles: make(map[string]bool),
+		existingSizes: make(map[string]int64),
 		deletedFiles:  make([]string, 0),
 	}
 }
@@ -127,11 +182,15 @@ func (m *mockBoxClient) FindFileByName(folderID string, name string) (*box.File,
 
 	key := folderID + "/" + name
 	if m.existingFiles[key] {
+		size := int64(1024)
+		if existingSize, ok := m.existingSizes[key]; ok {
+			size = existingSize
+		}
 		return &box.File{
 			ID:   "file_" + key,
 			Name: name,
 			Type: box.ItemTypeFile,
-			Size: 1024,
+			Size: size,
 		}, nil
 	}
 
@@ -142,6 +201,41 @@ func (m *mockBoxClient) FindFileByName(folderID string, name string) (*box.File,
 	}
 }
 
+func (m *mockBoxClient) SearchFiles(name string, ancestorFolderID string) ([]*box.File, error) {
+	m.searches++
+	if m.searchError != nil {
+		return nil, m.searchError
+	}
+	return m.searchResults[name], nil
+}
+
+func (m *mockBoxClient) SearchFilesAsUser(name string, ancestorFolderID string, userID string) ([]*box.File, error) {
+	return m.SearchFiles(name, ancestorFolderID)
+}
+
+func (m *mockBoxClient) UploadStream(r io.Reader, size int64, parentFolderID string, fileName string, progressCallback box.ProgressCallback) (*box.File, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if m.uploadError != nil {
+		return nil, m.uploadError
+	}
+	if m.streamed == nil {
+		m.streamed = make(map[string]string)
+	}
+	m.streamed[fileName] = string(content)
+
+	file := &box.File{ID: "file_" + fileName, Name: fileName, Type: box.ItemTypeFile, Size: size}
+	m.files[file.ID] = file
+	return file, nil
+}
+
+func (m *mockBoxClient) UploadStreamAsUser(r io.Reader, size int64, parentFolderID string, fileName string, userID string, progressCallback box.ProgressCallback) (*box.File, error) {
+	m.asUserCalls = append(m.asUserCalls, "upload_stream "+userID)
+	return m.UploadStream(r, size, parentFolderID, fileName, progressCallback)
+}
+
 func (m *mockBoxClient) UploadFileWithProgress(filePath string, parentFolderID string, fileName string, progressCallback box.ProgressCallback) (*box.File, error) {
 	if m.uploadError != nil {
 		return nil, m.uploadError
@@ -157,6 +251,29 @@ func (m *mockBoxClient) UploadFileWithProgress(filePath string, parentFolderID s
 	return file, nil
 }
 
+func (m *mockBoxClient) CreateSharedLink(fileID string, access string) (*box.SharedLink, error) {
+	return &box.SharedLink{URL: "https://app.box.com/s/" + fileID, Access: access}, nil
+}
+
+func (m *mockBoxClient) ApplyMetadata(fileID string, scope string, templateKey string, values map[string]interface{}) error {
+	if m.metadata == nil {
+		m.metadata = make(map[string]map[string]interface{})
+	}
+	m.metadata[fileID] = values
+	return nil
+}
+
+func (m *mockBoxClient) UploadFileVersion(fileID string, filePath string, progressCallback box.ProgressCallback) (*box.File, error) {
+	if m.uploadError != nil {
+		return nil, m.uploadError
+	}
+
+	m.versionedFiles = append(m.versionedFiles, fileID)
+	file := &box.File{ID: fileID, Name: filepath.Base(filePath), Type: box.ItemTypeFile, Size: 1024}
+	m.files[file.ID] = file
+	return file, nil
+}
+
 func (m *mockBoxClient) DeleteFile(fileID string) error {
 	m.deletedFiles = append(m.deletedFiles, fileID)
 	delete(m.files, fileID)
@@ -180,8 +297,17 @@ func (m *mockBoxClient) CreateFolder(name string, parentID string) (*box.Folder,
 	return folder, nil
 }
 func (m *mockBoxClient) CreateFolderAsUser(name string, parentID string, userID string) (*box.Folder, error) {
+	m.asUserCalls = append(m.asUserCalls, "create_folder "+userID)
+	if name == box.ZoomFolderName && parentID == box.RootFolderID {
+		// FindZoomFolderByOwner finds the new zoom folder from now on
+		m.findZoomFolderError = nil
+	}
 	return m.CreateFolder(name, parentID)
 }
+func (m *mockBoxClient) AddFolderCollaboratorAsUser(folderID string, collaboratorID string, role string, userID string) error {
+	m.collaborators = append(m.collaborators, folderID+" "+collaboratorID)
+	return nil
+}
 func (m *mockBoxClient) GetFolder(folderID string) (*box.Folder, error) {
 	if folder, exists := m.folders[folderID]; exists {
 		return folder, nil
@@ -189,16 +315,26 @@ func (m *mockBoxClient) GetFolder(folderID string) (*box.Folder, error) {
 	return &box.Folder{ID: folderID, Type: box.ItemTypeFolder}, nil
 }
 func (m *mockBoxClient) ListFolderItems(folderID string) (*box.FolderItems, error) {
+	m.folderListings++
 	return &box.FolderItems{Entries: []box.Item{}}, nil
 }
 func (m *mockBoxClient) ListFolderItemsAsUser(folderID string, userID string) (*box.FolderItems, error) {
+	m.asUserCalls = append(m.asUserCalls, "list "+userID)
+	if folderID == box.RootFolderID {
+		// Every user has a zoom folder in their own root
+		return &box.FolderItems{Entries: []box.Item{{ID: "zoom-folder-" + userID, Type: box.ItemTypeFolder, Name: "zoom"}}}, nil
+	}
 	return m.ListFolderItems(folderID)
 }
 func (m *mockBoxClient) FindZoomFolder() (string, error)                        { return "zoom-folder-id", nil }
 func (m *mockBoxClient) FindFolderByName(parentID string, name string) (*box.Folder, error) {
+	if key := parentID + "/" + name; m.lookupFolders[key] {
+		return &box.Folder{ID: key, Name: name, Type: box.ItemTypeFolder}, nil
+	}
 	return nil, &box.BoxError{StatusCode: 404, Code: box.ErrorCodeItemNotFound}
 }
 func (m *mockBoxClient) FindZoomFolderByOwner(ownerEmail string) (*box.Folder, error) {
+	m.zoomFolderLookups++
 	if m.findZoomFolderError != nil {
 		return nil, m.findZoomFolderError
 	}
@@ -212,6 +348,7 @@ func (m *mockBoxClient) UploadFile(filePath string, parentFolderID string, fileN
 	return m.UploadFileWithProgress(filePath, parentFolderID, fileName, nil)
 }
 func (m *mockBoxClient) UploadFileAsUser(filePath string, parentFolderID string, fileName string, userID string, progressCallback box.ProgressCallback) (*box.File, error) {
+	m.asUserCalls = append(m.asUserCalls, "upload "+userID)
 	return m.UploadFileWithProgress(filePath, parentFolderID, fileName, progressCallback)
 }
 
@@ -228,6 +365,10 @@ func (m *mockBoxClient) CommitUploadSession(sessionID string, parts []box.Upload
 	return nil, fmt.Errorf("not implemented in mock")
 }
 
+func (m *mockBoxClient) DeleteFolder(folderID string) error {
+	return nil
+}
+
 func (m *mockBoxClient) AbortUploadSession(sessionID string) error {
 	return fmt.Errorf("not implemented in mock")
 }
@@ -238,6 +379,13 @@ type mockUploadManager struct {
 	baseFolderID   string
 	uploadError    error
 	uploadedFiles  []string
+
+	uploadErrorExt string // Only uploads with this extension fail ("" = all)
+	verifyError    error  // Returned by VerifyUploadedFileChecksum
+
+	tracked []tracking.UploadEntry // Entries recorded through TrackUpload
+
+	folderCache *box.FolderCache // Returned by FolderCache (nil = no caching)
 }
 
 func newMockUploadManager(boxClient *mockBoxClient) *mockUploadManager {
@@ -261,7 +409,7 @@ func (m *mockUploadManager) UploadWithResume(ctx context.Context, localPath, vid
 }
 
 func (m *mockUploadManager) UploadFileWithEmailMapping(ctx context.Context, localPath, zoomEmail, boxEmail, downloadID string, progressCallback box.UploadProgressCallback) (*box.UploadResult, error) {
-	if m.uploadError != nil {
+	if m.uploadError != nil && (m.uploadErrorExt == "" || filepath.Ext(localPath) == m.uploadErrorExt) {
 		return &box.UploadResult{Success: false, Error: m.uploadError}, m.uploadError
 	}
 
@@ -281,10 +429,18 @@ func (m *mockUploadManager) UploadPendingFiles(ctx context.Context, statusTracke
 	return &box.UploadSummary{}, nil
 }
 
+func (m *mockUploadManager) RetryFailedUploads(ctx context.Context, statusTracker download.StatusTracker, options box.RetryOptions) (*box.UploadSummary, error) {
+	return &box.UploadSummary{}, nil
+}
+
 func (m *mockUploadManager) ValidateUploadedFile(ctx context.Context, fileID string, expectedSize int64) (bool, error) {
 	return true, nil
 }
 
+func (m *mockUploadManager) VerifyUploadedFileChecksum(ctx context.Context, fileID, localPath string) error {
+	return m.verifyError
+}
+
 func (m *mockUploadManager) SetBaseFolderID(folderID string) {
 	m.baseFolderID = folderID
 }
@@ -293,6 +449,10 @@ func (m *mockUploadManager) GetBaseFolderID() string {
 	return m.baseFolderID
 }
 
+func (m *mockUploadManager) FolderCache() *box.FolderCache {
+	return m.folderCache
+}
+
 func (m *mockUploadManager) GetBoxClient() box.BoxClient {
 	return m.boxClient
 }
@@ -309,6 +469,10 @@ func (m *mockUploadManager) TrackUploadWithTime(zoomUser, fileName string, fileS
 	// Mock implementation - no-op
 }
 
+func (m *mockUploadManager) TrackUpload(entry tracking.UploadEntry) {
+	m.tracked = append(m.tracked, entry)
+}
+
 func (m *mockUploadManager) UploadFileWithEmailMappingWithTime(ctx context.Context, localPath, zoomEmail, boxEmail, downloadID string, progressCallback box.UploadProgressCallback, processingTime time.Duration, trackingZoomEmail string, fileSize int64) (*box.UploadResult, error) {
 	// Delegate to the regular upload method
 	return m.UploadFileWithEmailMapping(ctx, localPath, zoomEmail, boxEmail, downloadID, progressCallback)
@@ -590,6 +754,74 @@ func TestUserProcessor_BoxFolderAccessFails(t *testing.T) {
 }
 
 // Test: User processor marks user inactive in ProcessAllUsers when Box access fails
+// TestUserProcessor_ProcessAllUsers_RetriesTransientFailures verifies that users who failed on
+// transient errors are processed again at the end of the run, and other failures are not
+func TestUserProcessor_ProcessAllUsers_RetriesTransientFailures(t *testing.T) {
+	tmpDir := t.TempDir()
+	activeUsersPath := filepath.Join(tmpDir, "active_users.txt")
+	if err := os.WriteFile(activeUsersPath, []byte("jane@example.com,jane@example.com,false\nbob@example.com,bob@example.com,false\n"), 0644); err != nil {
+		t.Fatalf("Failed to create active users file: %v", err)
+	}
+	usersFile, err := users.LoadActiveUsersFile(activeUsersPath)
+	if err != nil {
+		t.Fatalf("Failed to load active users file: %v", err)
+	}
+
+	zoomClient := newMockZoomClient()
+	zoomClient.recordingsCalls = make(map[string]int)
+	zoomClient.recordingsErrors = map[string][]error{
+		"jane@example.com": {&zoom.HTTPError{StatusCode: 503, Status: "503 Service Unavailable"}},
+		"bob@example.com":  {&zoom.HTTPError{StatusCode: 404, Status: "404 Not Found"}},
+	}
+	zoomClient.recordings["jane@example.com"] = []*zoom.Recording{
+		{
+			UUID:      "jane-uuid",
+			Topic:     "Standup",
+			StartTime: time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
+			RecordingFiles: []zoom.RecordingFile{
+				{ID: "file-1", FileType: "MP4", DownloadURL: "https://zoom.us/download/jane.mp4", FileSize: 1024},
+			},
+		},
+	}
+
+	processor := NewUserProcessor(
+		zoomClient,
+		newMockDownloadManager(),
+		nil,
+		filename.NewFileSanitizer(filename.FileSanitizerOptions{}),
+		nil,
+		ProcessorConfig{BaseDownloadDir: tmpDir, ContinueOnError: true, TransientRetries: 2},
+	)
+
+	summary, err := processor.ProcessAllUsers(context.Background(), usersFile)
+	if err != nil {
+		t.Fatalf("ProcessAllUsers failed: %v", err)
+	}
+
+	if summary.ProcessedUsers != 1 || summary.FailedUsers != 1 || summary.RetriedUsers != 1 {
+		t.Errorf("Expected 1 processed, 1 failed and 1 retried user, got %d, %d and %d",
+			summary.ProcessedUsers, summary.FailedUsers, summary.RetriedUsers)
+	}
+	if summary.TotalDownloads != 1 || summary.TotalErrors != 1 {
+		t.Errorf("Expected 1 download and bob's error only, got %d downloads and %d errors", summary.TotalDownloads, summary.TotalErrors)
+	}
+	if zoomClient.recordingsCalls["jane@example.com"] != 2 || zoomClient.recordingsCalls["bob@example.com"] != 1 {
+		t.Errorf("Expected jane to be retried and bob not, got %v", zoomClient.recordingsCalls)
+	}
+	if result := summary.UserResults[0]; result.ZoomEmail != "jane@example.com" || result.ErrorCount != 0 || result.DownloadedCount != 1 {
+		t.Errorf("Expected jane's result to be replaced by the retry, got %+v", result)
+	}
+
+	updated, err := users.LoadActiveUsersFile(activeUsersPath)
+	if err != nil {
+		t.Fatalf("Failed to reload active users file: %v", err)
+	}
+	incomplete := updated.GetIncompleteUsers()
+	if len(incomplete) != 1 || incomplete[0].ZoomEmail != "bob@example.com" {
+		t.Errorf("Expected only bob to stay incomplete, got %+v", incomplete)
+	}
+}
+
 func TestUserProcessor_ProcessAllUsers_BoxFolderAccessFails(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -790,6 +1022,59 @@ func TestUserProcessor_GetAllRecordings(t *testing.T) {
 	}
 }
 
+func TestUserProcessor_CustomDateRange(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	zoomClient := newMockZoomClient()
+	downloadManager := newMockDownloadManager()
+	boxClient := newMockBoxClient()
+	boxUploadManager := newMockUploadManager(boxClient)
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC)
+
+	config := ProcessorConfig{
+		BaseDownloadDir: tmpDir,
+		BoxEnabled:      true,
+		FromDate:        &from,
+		ToDate:          &to,
+	}
+
+	userManager, _ := users.NewActiveUserManager(users.ActiveUserConfig{
+		FilePath:      "",
+		CaseSensitive: false,
+		WatchFile:     false,
+	})
+
+	dirManager := directory.NewDirectoryManager(directory.DirectoryConfig{
+		BaseDirectory: tmpDir,
+		CreateDirs:    true,
+	}, userManager)
+
+	processor := NewUserProcessor(
+		zoomClient,
+		downloadManager,
+		dirManager,
+		filename.NewFileSanitizer(filename.FileSanitizerOptions{}),
+		boxUploadManager,
+		config,
+	)
+
+	if _, err := processor.ProcessUser(context.Background(), "john.doe@example.com", "john.doe@example.com"); err != nil {
+		t.Fatalf("ProcessUser failed: %v", err)
+	}
+
+	if zoomClient.lastCallParams == nil {
+		t.Fatal("GetAllUserRecordings was not called")
+	}
+	if zoomClient.lastCallParams.From == nil || !zoomClient.lastCallParams.From.Equal(from) {
+		t.Errorf("Expected From to be %v, got: %v", from, zoomClient.lastCallParams.From)
+	}
+	if zoomClient.lastCallParams.To == nil || !zoomClient.lastCallParams.To.Equal(to) {
+		t.Errorf("Expected To to be %v, got: %v", to, zoomClient.lastCallParams.To)
+	}
+}
+
 // Helper function to check if a string contains a substring
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || len(substr) == 0 ||
@@ -841,6 +1126,7 @@ func TestUserProcessor_SkipDownloadIfFileExistsInBox(t *testing.T) {
 	expectedFolderID := "folder_15" // Based on how CreateFolderPath works in mock
 	expectedFileName := "test-meeting-1030.mp4"
 	boxClient.existingFiles[expectedFolderID+"/"+expectedFileName] = true
+	boxClient.existingSizes[expectedFolderID+"/"+expectedFileName] = 1024000
 
 	// Create user processor with Box enabled
 	config := ProcessorConfig{
@@ -900,90 +1186,2198 @@ func TestUserProcessor_SkipDownloadIfFileExistsInBox(t *testing.T) {
 	}
 }
 
-// TestUserProcessor_DownloadIfFileNotInBox verifies that when Box is enabled
-// and a file does NOT exist in Box, we proceed with download and upload
-func TestUserProcessor_DownloadIfFileNotInBox(t *testing.T) {
-	tmpDir := t.TempDir()
+// TestUserProcessor_SizeMismatchedFileInBox verifies that a Box file with the same name but a
+// different size is not skipped: the recording is downloaded again and the conflict policy applied
+func TestUserProcessor_SizeMismatchedFileInBox(t *testing.T) {
+	testTime := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	existingKey := "folder_15/test-meeting-1030.mp4"
+
+	tests := []struct {
+		name          string
+		policy        storage.ConflictPolicy
+		wantUploaded  int
+		wantErrors    int
+		wantVersioned int
+		wantDeleted   int
+	}{
+		{name: "version", policy: storage.ConflictVersion, wantUploaded: 1, wantVersioned: 1},
+		{name: "replace", policy: storage.ConflictReplace, wantUploaded: 1, wantDeleted: 1},
+		{name: "report", policy: storage.ConflictReport, wantErrors: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			zoomClient := newMockZoomClient()
+			zoomClient.recordings["john.doe@example.com"] = []*zoom.Recording{
+				{
+					UUID:      "test-uuid-123",
+					Topic:     "Test Meeting",
+					StartTime: testTime,
+					RecordingFiles: []zoom.RecordingFile{
+						{ID: "file-123", FileType: "MP4", DownloadURL: "https://zoom.us/download/test.mp4", FileSize: 1024000},
+					},
+					DownloadAccessToken: "test-token",
+				},
+			}
+
+			// A truncated earlier upload
+			boxClient := newMockBoxClient()
+			boxClient.existingFiles[existingKey] = true
+			boxClient.existingSizes[existingKey] = 4096
+
+			downloadManager := newMockDownloadManager()
+			processor := NewUserProcessorWithDestination(
+				zoomClient,
+				downloadManager,
+				nil,
+				filename.NewFileSanitizer(filename.FileSanitizerOptions{}),
+				storage.NewBoxDestinationWithConflictPolicy(newMockUploadManager(boxClient), tt.policy),
+				ProcessorConfig{BaseDownloadDir: t.TempDir(), BoxEnabled: true, ContinueOnError: true},
+			)
+
+			result, err := processor.ProcessUser(context.Background(), "john.doe@example.com", "john.doe@example.com")
+			if err != nil {
+				t.Fatalf("ProcessUser failed: %v", err)
+			}
+
+			if len(downloadManager.downloadAttempted) != 1 || result.SkippedCount != 0 {
+				t.Errorf("Expected the mismatched file to be downloaded again, got %d downloads and %d skipped",
+					len(downloadManager.downloadAttempted), result.SkippedCount)
+			}
+			if result.UploadedCount != tt.wantUploaded || result.ErrorCount != tt.wantErrors {
+				t.Errorf("Expected %d uploaded and %d errors, got %d and %d",
+					tt.wantUploaded, tt.wantErrors, result.UploadedCount, result.ErrorCount)
+			}
+			if len(boxClient.versionedFiles) != tt.wantVersioned || len(boxClient.deletedFiles) != tt.wantDeleted {
+				t.Errorf("Expected %d versions and %d deletions, got %v and %v",
+					tt.wantVersioned, tt.wantDeleted, boxClient.versionedFiles, boxClient.deletedFiles)
+			}
+		})
+	}
+}
 
-	// Create mock clients
+// TestUserProcessor_UploadAsUser verifies that with As-User uploads every folder and file is
+// created as the Box user resolved from the upload email
+func TestUserProcessor_UploadAsUser(t *testing.T) {
 	zoomClient := newMockZoomClient()
-	downloadManager := newMockDownloadManager()
-	boxClient := newMockBoxClient()
-	boxUploadManager := newMockUploadManager(boxClient)
-
-	// Add test recording
-	testTime := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
-	zoomClient.recordings["jane.smith@example.com"] = []*zoom.Recording{
+	zoomClient.recordings["john.doe@example.com"] = []*zoom.Recording{
 		{
-			UUID:      "test-uuid-456",
-			Topic:     "New Meeting",
-			StartTime: testTime,
+			UUID:      "test-uuid-123",
+			Topic:     "Test Meeting",
+			StartTime: time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
 			RecordingFiles: []zoom.RecordingFile{
-				{
-					ID:          "file-456",
-					FileType:    "MP4",
-					DownloadURL: "https://zoom.us/download/new.mp4",
-					FileSize:    2048000,
-				},
+				{ID: "file-123", FileType: "MP4", DownloadURL: "https://zoom.us/download/test.mp4", FileSize: 1024000},
 			},
 			DownloadAccessToken: "test-token",
 		},
 	}
 
-	// File does NOT exist in Box (don't mark it in existingFiles)
+	boxClient := newMockBoxClient()
+	uploadManager := newMockUploadManager(boxClient)
+	processor := NewUserProcessorWithDestination(
+		zoomClient,
+		newMockDownloadManager(),
+		nil,
+		filename.NewFileSanitizer(filename.FileSanitizerOptions{}),
+		storage.NewBoxDestinationWithOptions(uploadManager, storage.BoxDestinationOptions{UploadAsUser: true}),
+		ProcessorConfig{BaseDownloadDir: t.TempDir(), BoxEnabled: true},
+	)
 
-	// Create user processor with Box enabled
-	config := ProcessorConfig{
-		BaseDownloadDir:   tmpDir,
-		BoxEnabled:        true,
-		DeleteAfterUpload: false,
-		ContinueOnError:   false,
+	result, err := processor.ProcessUser(context.Background(), "john.doe@example.com", "jdoe@box.example.com")
+	if err != nil {
+		t.Fatalf("ProcessUser failed: %v", err)
+	}
+	if result.UploadedCount != 1 {
+		t.Fatalf("Expected 1 upload, got %d (errors: %v)", result.UploadedCount, result.Errors)
 	}
 
-	userManager, _ := users.NewActiveUserManager(users.ActiveUserConfig{
-		FilePath:      "",
-		CaseSensitive: false,
-		WatchFile:     false,
-	})
-
-	dirManager := directory.NewDirectoryManager(directory.DirectoryConfig{
-		BaseDirectory: tmpDir,
-		CreateDirs:    true,
-	}, userManager)
+	// The service account upload manager is bypassed
+	if len(uploadManager.uploadedFiles) != 0 {
+		t.Errorf("Expected no uploads through the service account, got %v", uploadManager.uploadedFiles)
+	}
 
-	filenameSanitizer := filename.NewFileSanitizer(filename.FileSanitizerOptions{})
+	wantUserID := "user_jdoe@box.example.com"
+	var uploads, folders int
+	for _, call := range boxClient.asUserCalls {
+		operation, userID, _ := strings.Cut(call, " ")
+		if userID != wantUserID {
+			t.Errorf("Expected As-User calls for %s, got %q", wantUserID, call)
+		}
+		switch operation {
+		case "upload":
+			uploads++
+		case "create_folder":
+			folders++
+		}
+	}
+	if uploads == 0 || folders == 0 {
+		t.Errorf("Expected the uploads and date folders to be created as the user, got %v", boxClient.asUserCalls)
+	}
+}
 
-	processor := NewUserProcessor(
-		zoomClient,
-		downloadManager,
-		dirManager,
-		filenameSanitizer,
-		boxUploadManager,
-		config,
-	)
+// TestUserProcessor_CreateZoomFolderIfMissing verifies that a missing zoom folder fails the user
+// unless it may be created, in which case it is created as the user and shared with the service account
+func TestUserProcessor_CreateZoomFolderIfMissing(t *testing.T) {
+	tests := []struct {
+		name        string
+		create      bool
+		dryRun      bool
+		wantErrors  int
+		wantCreated bool
+	}{
+		{name: "missing folder fails the user", wantErrors: 1},
+		{name: "missing folder is created", create: true, wantCreated: true},
+		{name: "dry run creates nothing", create: true, dryRun: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			zoomClient := newMockZoomClient()
+			zoomClient.recordings["john.doe@example.com"] = []*zoom.Recording{
+				{
+					UUID:      "test-uuid-123",
+					Topic:     "Test Meeting",
+					StartTime: time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
+					RecordingFiles: []zoom.RecordingFile{
+						{ID: "file-123", FileType: "MP4", DownloadURL: "https://zoom.us/download/test.mp4", FileSize: 1024000},
+					},
+					DownloadAccessToken: "test-token",
+				},
+			}
+
+			boxClient := newMockBoxClient()
+			boxClient.findZoomFolderError = &box.BoxError{StatusCode: 404, Code: box.ErrorCodeItemNotFound}
+			processor := NewUserProcessorWithDestination(
+				zoomClient,
+				newMockDownloadManager(),
+				nil,
+				filename.NewFileSanitizer(filename.FileSanitizerOptions{}),
+				storage.NewBoxDestinationWithOptions(newMockUploadManager(boxClient), storage.BoxDestinationOptions{
+					CreateZoomFolderIfMissing: tt.create,
+					DryRun:                    tt.dryRun,
+				}),
+				ProcessorConfig{BaseDownloadDir: t.TempDir(), BoxEnabled: true, ContinueOnError: true, DryRun: tt.dryRun},
+			)
+
+			result, err := processor.ProcessUser(context.Background(), "john.doe@example.com", "john.doe@example.com")
+			if err != nil {
+				t.Fatalf("ProcessUser failed: %v", err)
+			}
+			if result.ErrorCount != tt.wantErrors {
+				t.Errorf("Expected %d errors, got %d: %v", tt.wantErrors, result.ErrorCount, result.Errors)
+			}
+
+			created := len(boxClient.asUserCalls) > 0 && boxClient.asUserCalls[0] == "create_folder user_john.doe@example.com"
+			if created != tt.wantCreated {
+				t.Errorf("Expected zoom folder created as the user: %v, got As-User calls %v", tt.wantCreated, boxClient.asUserCalls)
+			}
+			if tt.wantCreated && (len(boxClient.collaborators) != 1 || boxClient.collaborators[0] != "folder_zoom 12345") {
+				t.Errorf("Expected the zoom folder to be shared with the service account, got %v", boxClient.collaborators)
+			}
+			if tt.wantCreated && result.UploadedCount != 1 {
+				t.Errorf("Expected the recording to be uploaded into the new folder, got %d uploads", result.UploadedCount)
+			}
+		})
+	}
+}
 
-	// Process user
-	ctx := context.Background()
-	result, err := processor.ProcessUser(ctx, "jane.smith@example.com", "jane.smith@example.com")
+// TestUserProcessor_DestinationFolderID verifies that with a destination folder a missing user
+// folder is created in it instead of failing the user, except in dry runs
+func TestUserProcessor_DestinationFolderID(t *testing.T) {
+	for _, dryRun := range []bool{false, true} {
+		t.Run(fmt.Sprintf("dry run %v", dryRun), func(t *testing.T) {
+			zoomClient := newMockZoomClient()
+			zoomClient.recordings["john.doe@example.com"] = []*zoom.Recording{
+				{
+					UUID:      "test-uuid-123",
+					Topic:     "Test Meeting",
+					StartTime: time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
+					RecordingFiles: []zoom.RecordingFile{
+						{ID: "file-123", FileType: "MP4", DownloadURL: "https://zoom.us/download/test.mp4", FileSize: 1024000},
+					},
+					DownloadAccessToken: "test-token",
+				},
+			}
+
+			boxClient := newMockBoxClient()
+			boxClient.findZoomFolderError = &box.BoxError{StatusCode: 404, Code: box.ErrorCodeItemNotFound}
+			processor := NewUserProcessorWithDestination(
+				zoomClient,
+				newMockDownloadManager(),
+				nil,
+				filename.NewFileSanitizer(filename.FileSanitizerOptions{}),
+				storage.NewBoxDestinationWithOptions(newMockUploadManager(boxClient), storage.BoxDestinationOptions{
+					DestinationFolderID: "999",
+					DryRun:              dryRun,
+				}),
+				ProcessorConfig{BaseDownloadDir: t.TempDir(), BoxEnabled: true, ContinueOnError: true, DryRun: dryRun},
+			)
+
+			result, err := processor.ProcessUser(context.Background(), "john.doe@example.com", "john.doe@example.com")
+			if err != nil {
+				t.Fatalf("ProcessUser failed: %v", err)
+			}
+			if result.ErrorCount != 0 {
+				t.Errorf("Expected no errors, got %v", result.Errors)
+			}
+
+			_, created := boxClient.folders["folder_john.doe"]
+			if created == dryRun {
+				t.Errorf("Expected user folder created: %v, got folders %v", !dryRun, boxClient.folders)
+			}
+			if len(boxClient.asUserCalls) > 0 || len(boxClient.collaborators) > 0 {
+				t.Errorf("Expected no zoom folder to be created, got As-User calls %v", boxClient.asUserCalls)
+			}
+			if !dryRun && result.UploadedCount != 1 {
+				t.Errorf("Expected the recording to be uploaded into the user folder, got %d uploads", result.UploadedCount)
+			}
+		})
+	}
+}
 
+// TestUserProcessor_UserDestination verifies that a user with a destination column in the active
+// users file is uploaded into that folder without a zoom folder
+func TestUserProcessor_UserDestination(t *testing.T) {
+	tmpDir := t.TempDir()
+	activeUsersPath := filepath.Join(tmpDir, "active_users.txt")
+	if err := os.WriteFile(activeUsersPath, []byte("john.doe@example.com,john.doe@example.com,false,123456789\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	usersFile, err := users.LoadActiveUsersFile(activeUsersPath)
 	if err != nil {
-		t.Fatalf("ProcessUser failed: %v", err)
+		t.Fatal(err)
 	}
 
-	// VERIFY: Download SHOULD have been attempted since file doesn't exist in Box
-	if len(downloadManager.downloadAttempted) != 1 {
-		t.Errorf("Expected 1 download attempt (file not in Box), but got %d",
-			len(downloadManager.downloadAttempted))
+	zoomClient := newMockZoomClient()
+	zoomClient.recordings["john.doe@example.com"] = []*zoom.Recording{
+		{
+			UUID:      "test-uuid-123",
+			Topic:     "Test Meeting",
+			StartTime: time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
+			RecordingFiles: []zoom.RecordingFile{
+				{ID: "file-123", FileType: "MP4", DownloadURL: "https://zoom.us/download/test.mp4", FileSize: 1024000},
+			},
+			DownloadAccessToken: "test-token",
+		},
 	}
 
-	// VERIFY: File should be downloaded and uploaded
-	if result.DownloadedCount != 1 {
-		t.Errorf("Expected 1 download, got %d", result.DownloadedCount)
+	boxClient := newMockBoxClient()
+	boxClient.findZoomFolderError = &box.BoxError{StatusCode: 404, Code: box.ErrorCodeItemNotFound}
+	uploadManager := newMockUploadManager(boxClient)
+	processor := NewUserProcessorWithDestination(
+		zoomClient,
+		newMockDownloadManager(),
+		nil,
+		filename.NewFileSanitizer(filename.FileSanitizerOptions{}),
+		storage.NewBoxDestination(uploadManager),
+		ProcessorConfig{BaseDownloadDir: filepath.Join(tmpDir, "downloads"), BoxEnabled: true, ContinueOnError: true},
+	)
+
+	summary, err := processor.ProcessAllUsers(context.Background(), usersFile)
+	if err != nil {
+		t.Fatalf("ProcessAllUsers failed: %v", err)
 	}
-	if result.UploadedCount != 1 {
-		t.Errorf("Expected 1 upload, got %d", result.UploadedCount)
+	if summary.TotalErrors != 0 || summary.TotalUploads != 1 {
+		t.Errorf("Expected 1 upload without errors, got %d uploads and %d errors", summary.TotalUploads, summary.TotalErrors)
 	}
-	if result.SkippedCount != 0 {
-		t.Errorf("Expected 0 skipped files, got %d", result.SkippedCount)
+	if uploadManager.baseFolderID != "123456789" {
+		t.Errorf("Expected the upload below folder 123456789, got %s", uploadManager.baseFolderID)
+	}
+}
+
+// TestUserProcessor_FolderCache verifies that the zoom folder and the date folders are looked up
+// once per run instead of for every file
+func TestUserProcessor_FolderCache(t *testing.T) {
+	zoomClient := newMockZoomClient()
+	zoomClient.recordings["john.doe@example.com"] = []*zoom.Recording{
+		{
+			UUID:      "test-uuid-123",
+			Topic:     "Standup",
+			StartTime: time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC),
+			RecordingFiles: []zoom.RecordingFile{
+				{ID: "file-1", FileType: "MP4", DownloadURL: "https://zoom.us/download/1.mp4", FileSize: 1024},
+			},
+		},
+		{
+			UUID:      "test-uuid-456",
+			Topic:     "Planning",
+			StartTime: time.Date(2024, 1, 15, 14, 0, 0, 0, time.UTC),
+			RecordingFiles: []zoom.RecordingFile{
+				{ID: "file-2", FileType: "MP4", DownloadURL: "https://zoom.us/download/2.mp4", FileSize: 1024},
+			},
+		},
+	}
+
+	boxClient := newMockBoxClient()
+	uploadManager := newMockUploadManager(boxClient)
+	uploadManager.folderCache = box.NewFolderCache()
+	processor := NewUserProcessorWithDestination(
+		zoomClient,
+		newMockDownloadManager(),
+		nil,
+		filename.NewFileSanitizer(filename.FileSanitizerOptions{}),
+		storage.NewBoxDestination(uploadManager),
+		ProcessorConfig{BaseDownloadDir: t.TempDir(), BoxEnabled: true, ContinueOnError: true},
+	)
+
+	result, err := processor.ProcessUser(context.Background(), "john.doe@example.com", "john.doe@example.com")
+	if err != nil {
+		t.Fatalf("ProcessUser failed: %v", err)
+	}
+	if result.UploadedCount != 2 {
+		t.Fatalf("Expected 2 uploads, got %d: %v", result.UploadedCount, result.Errors)
+	}
+	if boxClient.zoomFolderLookups != 1 {
+		t.Errorf("Expected 1 zoom folder lookup, got %d", boxClient.zoomFolderLookups)
+	}
+	// One listing for each of 2024, 01 and 15
+	if boxClient.folderListings != 3 {
+		t.Errorf("Expected 3 folder listings, got %d", boxClient.folderListings)
+	}
+}
+
+// TestUserProcessor_SearchExisting verifies that existing files are found with the Box search API,
+// and with folder listings when search fails
+func TestUserProcessor_SearchExisting(t *testing.T) {
+	const folderPath = "2024/01/15"
+	existingKey := "folder_15/test-meeting-1030.mp4"
+
+	tests := []struct {
+		name        string
+		searchPath  string // Folder path of the search result below the zoom folder ("" = no result)
+		searchError error
+		listed      bool // The file is found by listing its folder
+		wantSkipped int
+	}{
+		{name: "found by search", searchPath: folderPath, wantSkipped: 1},
+		{name: "search result in another folder", searchPath: "2023/01/15"},
+		{name: "search fails and listing finds it", searchError: errors.New("search unavailable"), listed: true, wantSkipped: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			zoomClient := newMockZoomClient()
+			zoomClient.recordings["john.doe@example.com"] = []*zoom.Recording{
+				{
+					UUID:      "test-uuid-123",
+					Topic:     "Test Meeting",
+					StartTime: time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
+					RecordingFiles: []zoom.RecordingFile{
+						{ID: "file-123", FileType: "MP4", DownloadURL: "https://zoom.us/download/test.mp4", FileSize: 1024},
+					},
+					DownloadAccessToken: "test-token",
+				},
+			}
+
+			boxClient := newMockBoxClient()
+			boxClient.searchError = tt.searchError
+			if tt.searchPath != "" {
+				path := &box.Path{Entries: []*box.Folder{{ID: "0", Name: "All Files"}, {ID: "zoom-folder-john.doe@example.com", Name: "zoom"}}}
+				for _, part := range strings.Split(tt.searchPath, "/") {
+					path.Entries = append(path.Entries, &box.Folder{ID: "folder_" + part, Name: part})
+				}
+				boxClient.searchResults = map[string][]*box.File{
+					"test-meeting-1030.mp4": {{ID: "found", Type: box.ItemTypeFile, Name: "test-meeting-1030.mp4", Size: 1024, PathCollection: path}},
+				}
+			}
+			boxClient.existingFiles[existingKey] = tt.listed
+
+			downloadManager := newMockDownloadManager()
+			processor := NewUserProcessorWithDestination(
+				zoomClient,
+				downloadManager,
+				nil,
+				filename.NewFileSanitizer(filename.FileSanitizerOptions{}),
+				storage.NewBoxDestinationWithOptions(newMockUploadManager(boxClient), storage.BoxDestinationOptions{SearchExisting: true}),
+				ProcessorConfig{BaseDownloadDir: t.TempDir(), BoxEnabled: true},
+			)
+
+			result, err := processor.ProcessUser(context.Background(), "john.doe@example.com", "john.doe@example.com")
+			if err != nil {
+				t.Fatalf("ProcessUser failed: %v", err)
+			}
+			if boxClient.searches == 0 {
+				t.Error("Expected the Box search API to be used")
+			}
+			if result.SkippedCount != tt.wantSkipped || len(downloadManager.downloadAttempted) != 1-tt.wantSkipped {
+				t.Errorf("Expected %d skipped, got %d skipped and %d downloads",
+					tt.wantSkipped, result.SkippedCount, len(downloadManager.downloadAttempted))
+			}
+		})
+	}
+}
+
+func TestUserProcessor_Stream(t *testing.T) {
+	const downloadURL = "https://zoom.us/download/test.mp4"
+	const contents = "\x00\x00\x00\x20ftypisom recording"
+	newRecording := func(size int64) *zoom.Recording {
+		return &zoom.Recording{
+			UUID:      "test-uuid-123",
+			Topic:     "Test Meeting",
+			StartTime: time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
+			RecordingFiles: []zoom.RecordingFile{
+				{ID: "file-123", FileType: "MP4", DownloadURL: downloadURL, FileSize: size},
+			},
+			DownloadAccessToken: "test-token",
+		}
+	}
+
+	tests := []struct {
+		name          string
+		stream        bool
+		fileSize      int64
+		existingSize  int64 // Size of a file already in Box (0 = none)
+		downloadError error
+		wantStreamed  bool
+		wantDownloads int
+		wantError     bool
+	}{
+		{name: "streamed", stream: true, fileSize: box.MinChunkedUploadSize, wantStreamed: true},
+		{name: "stream mode off", fileSize: box.MinChunkedUploadSize, wantDownloads: 1},
+		{name: "too small to stream", stream: true, fileSize: 1024, wantDownloads: 1},
+		{name: "version of an existing file needs a local copy", stream: true, fileSize: box.MinChunkedUploadSize, existingSize: 1024, wantDownloads: 1},
+		{name: "zoom download fails", stream: true, fileSize: box.MinChunkedUploadSize, downloadError: errors.New("connection reset"), wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			zoomClient := newMockZoomClient()
+			zoomClient.recordings["john.doe@example.com"] = []*zoom.Recording{newRecording(tt.fileSize)}
+			zoomClient.fileContents = map[string]string{downloadURL: contents}
+			zoomClient.downloadFileError = tt.downloadError
+
+			boxClient := newMockBoxClient()
+			if tt.existingSize > 0 {
+				boxClient.existingFiles["folder_15/test-meeting-1030.mp4"] = true
+				boxClient.existingSizes["folder_15/test-meeting-1030.mp4"] = tt.existingSize
+			}
+
+			baseDir := t.TempDir()
+			downloadManager := newMockDownloadManager()
+			processor := NewUserProcessor(
+				zoomClient,
+				downloadManager,
+				nil,
+				filename.NewFileSanitizer(filename.FileSanitizerOptions{}),
+				newMockUploadManager(boxClient),
+				ProcessorConfig{BaseDownloadDir: baseDir, BoxEnabled: true, ContinueOnError: true, Stream: tt.stream},
+			)
+
+			result, err := processor.ProcessUser(context.Background(), "john.doe@example.com", "john.doe@example.com")
+			if err != nil {
+				t.Fatalf("ProcessUser failed: %v", err)
+			}
+
+			if got := boxClient.streamed["test-meeting-1030.mp4"] == contents; got != tt.wantStreamed {
+				t.Errorf("Expected streamed %v, got %v", tt.wantStreamed, boxClient.streamed)
+			}
+			if len(downloadManager.requests) != tt.wantDownloads {
+				t.Errorf("Expected %d downloads, got %d", tt.wantDownloads, len(downloadManager.requests))
+			}
+			if (result.ErrorCount > 0) != tt.wantError {
+				t.Errorf("Expected error %v, got %d errors", tt.wantError, result.ErrorCount)
+			}
+			if tt.wantStreamed {
+				if result.UploadedCount != 1 {
+					t.Errorf("Expected the streamed file to count as uploaded, got %d uploads", result.UploadedCount)
+				}
+				if _, err := os.Stat(filepath.Join(baseDir, "john.doe", "2024", "01", "15", "test-meeting-1030.mp4")); !os.IsNotExist(err) {
+					t.Errorf("Expected no local copy of a streamed file, got %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestMediaSignatureWriter(t *testing.T) {
+	tests := []struct {
+		name    string
+		writes  []string
+		wantErr bool
+	}{
+		{name: "mp4 in small writes", writes: []string{"\x00\x00", "\x00\x20ftyp", "isom", strings.Repeat("v", 600)}},
+		{name: "short mp4", writes: []string{"\x00\x00\x00\x20ftypisom"}},
+		{name: "login page", writes: []string{"<!DOCTYPE html><html>", strings.Repeat("x", 600)}, wantErr: true},
+		{name: "short json error", writes: []string{`{"code": 3001}`}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			writer := &mediaSignatureWriter{w: &out}
+			var err error
+			for _, data := range tt.writes {
+				if _, err = writer.Write([]byte(data)); err != nil {
+					break
+				}
+			}
+			if err == nil {
+				err = writer.flush()
+			}
+			if tt.wantErr {
+				if err == nil || out.Len() != 0 {
+					t.Errorf("Expected the stream rejected before anything was passed on, got %v and %d bytes", err, out.Len())
+				}
+				return
+			}
+			if err != nil || out.String() != strings.Join(tt.writes, "") {
+				t.Errorf("Expected the stream passed on unchanged, got %v", err)
+			}
+		})
+	}
+}
+
+// TestUserProcessor_DownloadIfFileNotInBox verifies that when Box is enabled
+// and a file does NOT exist in Box, we proceed with download and upload
+func TestUserProcessor_DownloadIfFileNotInBox(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// Create mock clients
+	zoomClient := newMockZoomClient()
+	downloadManager := newMockDownloadManager()
+	boxClient := newMockBoxClient()
+	boxUploadManager := newMockUploadManager(boxClient)
+
+	// Add test recording
+	testTime := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	zoomClient.recordings["jane.smith@example.com"] = []*zoom.Recording{
+		{
+			UUID:      "test-uuid-456",
+			Topic:     "New Meeting",
+			StartTime: testTime,
+			RecordingFiles: []zoom.RecordingFile{
+				{
+					ID:          "file-456",
+					FileType:    "MP4",
+					DownloadURL: "https://zoom.us/download/new.mp4",
+					FileSize:    2048000,
+				},
+			},
+			DownloadAccessToken: "test-token",
+		},
+	}
+
+	// File does NOT exist in Box (don't mark it in existingFiles)
+
+	// Create user processor with Box enabled
+	config := ProcessorConfig{
+		BaseDownloadDir:   tmpDir,
+		BoxEnabled:        true,
+		DeleteAfterUpload: false,
+		ContinueOnError:   false,
+	}
+
+	userManager, _ := users.NewActiveUserManager(users.ActiveUserConfig{
+		FilePath:      "",
+		CaseSensitive: false,
+		WatchFile:     false,
+	})
+
+	dirManager := directory.NewDirectoryManager(directory.DirectoryConfig{
+		BaseDirectory: tmpDir,
+		CreateDirs:    true,
+	}, userManager)
+
+	filenameSanitizer := filename.NewFileSanitizer(filename.FileSanitizerOptions{})
+
+	processor := NewUserProcessor(
+		zoomClient,
+		downloadManager,
+		dirManager,
+		filenameSanitizer,
+		boxUploadManager,
+		config,
+	)
+
+	// Process user
+	ctx := context.Background()
+	result, err := processor.ProcessUser(ctx, "jane.smith@example.com", "jane.smith@example.com")
+
+	if err != nil {
+		t.Fatalf("ProcessUser failed: %v", err)
+	}
+
+	// VERIFY: Download SHOULD have been attempted since file doesn't exist in Box
+	if len(downloadManager.downloadAttempted) != 1 {
+		t.Errorf("Expected 1 download attempt (file not in Box), but got %d",
+			len(downloadManager.downloadAttempted))
+	}
+
+	// VERIFY: File should be downloaded and uploaded
+	if result.DownloadedCount != 1 {
+		t.Errorf("Expected 1 download, got %d", result.DownloadedCount)
+	}
+	if result.UploadedCount != 1 {
+		t.Errorf("Expected 1 upload, got %d", result.UploadedCount)
+	}
+	if result.SkippedCount != 0 {
+		t.Errorf("Expected 0 skipped files, got %d", result.SkippedCount)
+	}
+}
+
+// TestUserProcessor_SharedLinks verifies that uploaded MP4s are shared and the link is recorded
+// in the upload tracking and the metadata file
+func TestUserProcessor_SharedLinks(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	zoomClient := newMockZoomClient()
+	downloadManager := newMockDownloadManager()
+	boxUploadManager := newMockUploadManager(newMockBoxClient())
+
+	zoomClient.recordings["jane.smith@example.com"] = []*zoom.Recording{
+		{
+			UUID:      "test-uuid-456",
+			Topic:     "Shared Meeting",
+			StartTime: time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
+			RecordingFiles: []zoom.RecordingFile{
+				{ID: "file-456", FileType: "MP4", DownloadURL: "https://zoom.us/download/shared.mp4", FileSize: 2048000},
+			},
+			DownloadAccessToken: "test-token",
+		},
+	}
+
+	userManager, _ := users.NewActiveUserManager(users.ActiveUserConfig{})
+	dirManager := directory.NewDirectoryManager(directory.DirectoryConfig{
+		BaseDirectory: tmpDir,
+		CreateDirs:    true,
+	}, userManager)
+
+	processor := NewUserProcessor(
+		zoomClient,
+		downloadManager,
+		dirManager,
+		filename.NewFileSanitizer(filename.FileSanitizerOptions{}),
+		boxUploadManager,
+		ProcessorConfig{
+			BaseDownloadDir:  tmpDir,
+			BoxEnabled:       true,
+			SharedLinkAccess: box.SharedLinkAccessCompany,
+		},
+	)
+
+	result, err := processor.ProcessUser(context.Background(), "jane.smith@example.com", "jane.smith@example.com")
+	if err != nil {
+		t.Fatalf("ProcessUser failed: %v", err)
+	}
+	if result.UploadedCount != 1 {
+		t.Fatalf("Expected 1 upload, got %d", result.UploadedCount)
+	}
+
+	var link string
+	for _, entry := range boxUploadManager.tracked {
+		if filepath.Ext(entry.FileName) == ".mp4" {
+			link = entry.SharedLink
+		}
+	}
+	if !strings.HasPrefix(link, "https://app.box.com/s/") {
+		t.Errorf("Expected the recording tracked with its shared link, got %+v", boxUploadManager.tracked)
+	}
+
+	var metadataPath string
+	for _, uploaded := range boxUploadManager.uploadedFiles {
+		if filepath.Ext(uploaded) == ".json" {
+			metadataPath = uploaded
+		}
+	}
+	data, err := os.ReadFile(metadataPath)
+	if err != nil {
+		t.Fatalf("Failed to read metadata: %v", err)
+	}
+	var metadata map[string]map[string]interface{}
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		t.Fatalf("Failed to parse metadata: %v", err)
+	}
+	if metadata["shared_link"]["url"] != link || metadata["shared_link"]["access"] != "company" {
+		t.Errorf("Expected the shared link in the metadata, got %v", metadata["shared_link"])
+	}
+}
+
+func TestUserProcessor_Hooks(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	var events []hooks.Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event hooks.Event
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			t.Errorf("Failed to decode hook event: %v", err)
+		}
+		events = append(events, event)
+	}))
+	defer server.Close()
+	hookRunner, err := hooks.New(hooks.Config{URL: server.URL})
+	if err != nil {
+		t.Fatalf("hooks.New failed: %v", err)
+	}
+
+	zoomClient := newMockZoomClient()
+	zoomClient.recordings["jane.smith@example.com"] = []*zoom.Recording{
+		{
+			UUID:      "hook-uuid",
+			Topic:     "Hooked Meeting",
+			StartTime: time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
+			RecordingFiles: []zoom.RecordingFile{
+				{ID: "file-hook", FileType: "MP4", DownloadURL: "https://zoom.us/download/hook.mp4", FileSize: 2048000},
+			},
+			DownloadAccessToken: "test-token",
+		},
+	}
+
+	userManager, _ := users.NewActiveUserManager(users.ActiveUserConfig{})
+	dirManager := directory.NewDirectoryManager(directory.DirectoryConfig{
+		BaseDirectory: tmpDir,
+		CreateDirs:    true,
+	}, userManager)
+
+	processor := NewUserProcessor(
+		zoomClient,
+		newMockDownloadManager(),
+		dirManager,
+		filename.NewFileSanitizer(filename.FileSanitizerOptions{}),
+		newMockUploadManager(newMockBoxClient()),
+		ProcessorConfig{
+			BaseDownloadDir: tmpDir,
+			BoxEnabled:      true,
+			Hooks:           hookRunner,
+		},
+	)
+
+	if _, err := processor.ProcessUser(context.Background(), "jane.smith@example.com", "jane.smith@example.com"); err != nil {
+		t.Fatalf("ProcessUser failed: %v", err)
+	}
+
+	if len(events) != 2 {
+		t.Fatalf("Expected an upload and a user event, got %+v", events)
+	}
+	upload, user := events[0], events[1]
+	if upload.Event != hooks.EventUpload || filepath.Ext(upload.File) != ".mp4" || upload.Folder != "2024/01/15" ||
+		upload.MeetingUUID != "hook-uuid" || upload.Topic != "Hooked Meeting" || upload.Path == "" {
+		t.Errorf("Unexpected upload event: %+v", upload)
+	}
+	if user.Event != hooks.EventUser || user.User != "jane.smith@example.com" || user.Uploaded != 1 || user.Errors != 0 {
+		t.Errorf("Unexpected user event: %+v", user)
+	}
+}
+
+func TestUserProcessor_WindowsSafePaths(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	zoomClient := newMockZoomClient()
+	zoomClient.recordings["jane.smith@example.com"] = []*zoom.Recording{
+		{
+			UUID:      "con-uuid",
+			Topic:     "CON",
+			StartTime: time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
+			RecordingFiles: []zoom.RecordingFile{
+				{ID: "file-con", FileType: "MP4", DownloadURL: "https://zoom.us/download/con.mp4", FileSize: 2048000},
+			},
+			DownloadAccessToken: "test-token",
+		},
+	}
+
+	userManager, _ := users.NewActiveUserManager(users.ActiveUserConfig{})
+	dirManager := directory.NewDirectoryManager(directory.DirectoryConfig{
+		BaseDirectory: tmpDir,
+		CreateDirs:    true,
+	}, userManager)
+	sanitizer := filename.NewFileSanitizer(filename.FileSanitizerOptions{WindowsSafe: true})
+	template, err := filename.NewTemplate("{{.Topic}}", sanitizer)
+	if err != nil {
+		t.Fatalf("NewTemplate failed: %v", err)
+	}
+	boxUploadManager := newMockUploadManager(newMockBoxClient())
+
+	processor := NewUserProcessor(
+		zoomClient,
+		newMockDownloadManager(),
+		dirManager,
+		sanitizer,
+		boxUploadManager,
+		ProcessorConfig{
+			BaseDownloadDir:  tmpDir,
+			BoxEnabled:       true,
+			FilenameTemplate: template,
+			WindowsSafePaths: true,
+		},
+	)
+
+	result, err := processor.ProcessUser(context.Background(), "jane.smith@example.com", "jane.smith@example.com")
+	if err != nil {
+		t.Fatalf("ProcessUser failed: %v", err)
+	}
+	if result.UploadedCount != 1 {
+		t.Fatalf("Expected 1 upload, got %d", result.UploadedCount)
+	}
+	found := false
+	for _, uploaded := range boxUploadManager.uploadedFiles {
+		if filepath.Base(uploaded) == "con_.mp4" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected the reserved name mangled to con_.mp4, got %v", boxUploadManager.uploadedFiles)
+	}
+}
+
+func TestSaveRecordingMetadata_RecordsChecksum(t *testing.T) {
+	metadataPath := filepath.Join(t.TempDir(), "meeting.json")
+	recording := &zoom.Recording{UUID: "test-uuid", Topic: "Checksum Meeting"}
+	recordingFile := &zoom.RecordingFile{ID: "file-1", FileType: "MP4"}
+
+	if err := saveRecordingMetadata(context.Background(), jsonMetadata{}, recording, recordingFile, "blake3:abc123", "", nil, metadataPath); err != nil {
+		t.Fatalf("saveRecordingMetadata failed: %v", err)
+	}
+
+	data, err := os.ReadFile(metadataPath)
+	if err != nil {
+		t.Fatalf("Failed to read metadata: %v", err)
+	}
+
+	var metadata map[string]map[string]interface{}
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		t.Fatalf("Failed to parse metadata: %v", err)
+	}
+
+	fileInfo := metadata["recording_file"]
+	if fileInfo["checksum"] != "blake3:abc123" {
+		t.Errorf("Expected checksum blake3:abc123, got %v", fileInfo["checksum"])
+	}
+	if fileInfo["checksum_algorithm"] != "blake3" {
+		t.Errorf("Expected checksum_algorithm blake3, got %v", fileInfo["checksum_algorithm"])
+	}
+}
+
+// TestUserProcessor_Thumbnails verifies that thumbnails are downloaded next to the MP4,
+// referenced in the metadata file and uploaded with the recording
+func TestUserProcessor_Thumbnails(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	zoomClient := newMockZoomClient()
+	downloadManager := newMockDownloadManager()
+	boxClient := newMockBoxClient()
+	boxUploadManager := newMockUploadManager(boxClient)
+
+	testTime := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	zoomClient.recordings["jane.smith@example.com"] = []*zoom.Recording{
+		{
+			UUID:      "thumb-uuid",
+			Topic:     "Screen Share",
+			StartTime: testTime,
+			RecordingFiles: []zoom.RecordingFile{
+				{
+					ID:            "file-1",
+					FileType:      "MP4",
+					RecordingType: "shared_screen_with_speaker_view",
+					DownloadURL:   "https://zoom.us/download/screen.mp4",
+					ThumbnailURL:  "https://zoom.us/thumbnail/screen.png?token=abc",
+					FileSize:      1024,
+				},
+			},
+			DownloadAccessToken: "test-token",
+		},
+	}
+
+	processor := NewUserProcessor(
+		zoomClient,
+		downloadManager,
+		nil,
+		filename.NewFileSanitizer(filename.FileSanitizerOptions{}),
+		boxUploadManager,
+		ProcessorConfig{
+			BaseDownloadDir: tmpDir,
+			BoxEnabled:      true,
+			Thumbnails:      true,
+		},
+	)
+
+	if _, err := processor.ProcessUser(context.Background(), "jane.smith@example.com", "jane.smith@example.com"); err != nil {
+		t.Fatalf("ProcessUser failed: %v", err)
+	}
+
+	if len(downloadManager.downloadAttempted) != 2 {
+		t.Fatalf("Expected video and thumbnail downloads, got %v", downloadManager.downloadAttempted)
+	}
+	thumbnailPath := downloadManager.downloadAttempted[1]
+	if filepath.Ext(thumbnailPath) != ".png" {
+		t.Errorf("Expected .png thumbnail, got %s", thumbnailPath)
+	}
+	if strings.TrimSuffix(thumbnailPath, ".png") != strings.TrimSuffix(downloadManager.downloadAttempted[0], ".mp4") {
+		t.Errorf("Expected thumbnail next to the video, got %s", thumbnailPath)
+	}
+
+	found := false
+	for _, uploaded := range boxUploadManager.uploadedFiles {
+		if uploaded == thumbnailPath {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected thumbnail to be uploaded, got %v", boxUploadManager.uploadedFiles)
+	}
+
+	data, err := os.ReadFile(strings.TrimSuffix(thumbnailPath, ".png") + ".json")
+	if err != nil {
+		t.Fatalf("Failed to read metadata: %v", err)
+	}
+	var metadata map[string]map[string]interface{}
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		t.Fatalf("Failed to parse metadata: %v", err)
+	}
+	if metadata["thumbnail"]["file_name"] != filepath.Base(thumbnailPath) {
+		t.Errorf("Expected metadata to reference %s, got %v", filepath.Base(thumbnailPath), metadata["thumbnail"])
+	}
+}
+
+func TestUserProcessor_ProcessRecordings(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	zoomClient := newMockZoomClient()
+	zoomClient.recordingsError = fmt.Errorf("recordings should not be listed")
+	downloadManager := newMockDownloadManager()
+	boxUploadManager := newMockUploadManager(newMockBoxClient())
+
+	recording := &zoom.Recording{
+		UUID:      "webhook-uuid",
+		Topic:     "Webhook Meeting",
+		StartTime: time.Date(2024, 3, 1, 14, 0, 0, 0, time.UTC),
+		RecordingFiles: []zoom.RecordingFile{
+			{ID: "file-1", FileType: "MP4", DownloadURL: "https://zoom.us/download/webhook.mp4", FileSize: 1024},
+		},
+	}
+
+	processor := NewUserProcessor(
+		zoomClient,
+		downloadManager,
+		nil,
+		filename.NewFileSanitizer(filename.FileSanitizerOptions{}),
+		boxUploadManager,
+		ProcessorConfig{
+			BaseDownloadDir: tmpDir,
+			BoxEnabled:      true,
+		},
+	)
+
+	result, err := processor.ProcessRecordings(context.Background(), "jane.smith@example.com", "jane@box.example.com", []*zoom.Recording{recording})
+	if err != nil {
+		t.Fatalf("ProcessRecordings failed: %v", err)
+	}
+
+	if zoomClient.lastCallParams != nil {
+		t.Error("Expected recordings not to be listed from the Zoom API")
+	}
+	if result.DownloadedCount != 1 || result.UploadedCount != 1 || result.ErrorCount != 0 {
+		t.Errorf("Expected 1 download and 1 upload, got %+v", result)
+	}
+	if result.BoxEmail != "jane@box.example.com" {
+		t.Errorf("Expected Box email to be kept, got %s", result.BoxEmail)
+	}
+}
+
+func TestUserProcessor_RecordAndReplayFailures(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	downloadManager := newMockDownloadManager()
+	boxUploadManager := newMockUploadManager(newMockBoxClient())
+	boxUploadManager.uploadError = fmt.Errorf("box unavailable")
+	report := runreport.New()
+
+	recording := &zoom.Recording{
+		UUID:      "replay-uuid",
+		Topic:     "Replay Meeting",
+		StartTime: time.Date(2024, 3, 1, 14, 0, 0, 0, time.UTC),
+		RecordingFiles: []zoom.RecordingFile{
+			{ID: "file-1", FileType: "MP4", DownloadURL: "https://zoom.us/download/replay.mp4", FileSize: 1024},
+		},
+	}
+
+	processor := NewUserProcessor(
+		newMockZoomClient(),
+		downloadManager,
+		nil,
+		filename.NewFileSanitizer(filename.FileSanitizerOptions{}),
+		boxUploadManager,
+		ProcessorConfig{
+			BaseDownloadDir: tmpDir,
+			BoxEnabled:      true,
+			ContinueOnError: true,
+			FailureRecorder: report,
+		},
+	)
+
+	result, err := processor.ProcessRecordings(context.Background(), "jane.smith@example.com", "jane@box.example.com", []*zoom.Recording{recording})
+	if err != nil {
+		t.Fatalf("ProcessRecordings failed: %v", err)
+	}
+	if result.ErrorCount != 1 {
+		t.Fatalf("Expected the upload to fail, got %+v", result)
+	}
+
+	if len(report.Failures) != 1 {
+		t.Fatalf("Expected 1 recorded failure, got %d", len(report.Failures))
+	}
+	failure := report.Failures[0]
+	if failure.Operation != runreport.OperationUpload || failure.RecordingFileID != "file-1" || failure.LocalPath == "" {
+		t.Fatalf("Unexpected failure: %+v", failure)
+	}
+
+	// Fix the environment and replay; the file must not be downloaded again
+	boxUploadManager.uploadError = nil
+	attempts := len(downloadManager.downloadAttempted)
+
+	summary := runreport.Replay(context.Background(), report, processor)
+	if summary.Succeeded != 1 || len(summary.Remaining.Failures) != 0 {
+		t.Errorf("Expected replay to succeed, got %+v", summary)
+	}
+	if len(downloadManager.downloadAttempted) != attempts {
+		t.Error("Expected replayed upload not to download again")
+	}
+	if len(boxUploadManager.uploadedFiles) == 0 || boxUploadManager.uploadedFiles[0] != failure.LocalPath {
+		t.Errorf("Expected %s to be uploaded, got %v", failure.LocalPath, boxUploadManager.uploadedFiles)
+	}
+}
+
+func TestUserProcessor_MetadataUploadOrder(t *testing.T) {
+	newRecording := func() *zoom.Recording {
+		return &zoom.Recording{
+			UUID:      "order-uuid",
+			Topic:     "Order Meeting",
+			StartTime: time.Date(2024, 3, 1, 14, 0, 0, 0, time.UTC),
+			RecordingFiles: []zoom.RecordingFile{
+				{ID: "file-1", FileType: "MP4", DownloadURL: "https://zoom.us/download/order.mp4", FileSize: 1024},
+			},
+		}
+	}
+	newProcessor := func(uploadManager *mockUploadManager, order MetadataOrder) UserProcessor {
+		return NewUserProcessor(
+			newMockZoomClient(),
+			newMockDownloadManager(),
+			nil,
+			filename.NewFileSanitizer(filename.FileSanitizerOptions{}),
+			uploadManager,
+			ProcessorConfig{
+				BaseDownloadDir: t.TempDir(),
+				BoxEnabled:      true,
+				ContinueOnError: true,
+				MetadataOrder:   order,
+			},
+		)
+	}
+
+	tests := []struct {
+		name      string
+		order     MetadataOrder
+		wantFirst string
+	}{
+		{name: "default uploads recording first", order: "", wantFirst: ".mp4"},
+		{name: "after uploads recording first", order: MetadataAfterRecording, wantFirst: ".mp4"},
+		{name: "before uploads metadata first", order: MetadataBeforeRecording, wantFirst: ".json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uploadManager := newMockUploadManager(newMockBoxClient())
+			result, err := newProcessor(uploadManager, tt.order).ProcessRecordings(context.Background(), "jane.smith@example.com", "jane.smith@example.com", []*zoom.Recording{newRecording()})
+			if err != nil {
+				t.Fatalf("ProcessRecordings failed: %v", err)
+			}
+			if result.UploadedCount != 1 || result.ErrorCount != 0 {
+				t.Fatalf("Expected the recording to upload, got %+v", result)
+			}
+			if len(uploadManager.uploadedFiles) != 2 {
+				t.Fatalf("Expected recording and metadata uploads, got %v", uploadManager.uploadedFiles)
+			}
+			if got := filepath.Ext(uploadManager.uploadedFiles[0]); got != tt.wantFirst {
+				t.Errorf("Expected %s to be uploaded first, got %v", tt.wantFirst, uploadManager.uploadedFiles)
+			}
+		})
+	}
+
+	t.Run("failed metadata upload holds back the recording", func(t *testing.T) {
+		uploadManager := newMockUploadManager(newMockBoxClient())
+		uploadManager.uploadError = fmt.Errorf("box unavailable")
+		uploadManager.uploadErrorExt = ".json"
+
+		result, err := newProcessor(uploadManager, MetadataBeforeRecording).ProcessRecordings(context.Background(), "jane.smith@example.com", "jane.smith@example.com", []*zoom.Recording{newRecording()})
+		if err != nil {
+			t.Fatalf("ProcessRecordings failed: %v", err)
+		}
+		if result.ErrorCount != 1 || result.UploadedCount != 0 {
+			t.Errorf("Expected the recording to fail, got %+v", result)
+		}
+		if len(uploadManager.uploadedFiles) != 0 {
+			t.Errorf("Expected no uploads, got %v", uploadManager.uploadedFiles)
+		}
+	})
+
+	t.Run("failed metadata upload after recording is not fatal", func(t *testing.T) {
+		uploadManager := newMockUploadManager(newMockBoxClient())
+		uploadManager.uploadError = fmt.Errorf("box unavailable")
+		uploadManager.uploadErrorExt = ".json"
+
+		result, err := newProcessor(uploadManager, MetadataAfterRecording).ProcessRecordings(context.Background(), "jane.smith@example.com", "jane.smith@example.com", []*zoom.Recording{newRecording()})
+		if err != nil {
+			t.Fatalf("ProcessRecordings failed: %v", err)
+		}
+		if result.ErrorCount != 0 || result.UploadedCount != 1 {
+			t.Errorf("Expected the recording to upload, got %+v", result)
+		}
+	})
+}
+
+func TestUserProcessor_UploadChecksumMismatch(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	boxClient := newMockBoxClient()
+	uploadManager := newMockUploadManager(boxClient)
+	uploadManager.verifyError = fmt.Errorf("%w: test", box.ErrChecksumMismatch)
+
+	recording := &zoom.Recording{
+		UUID:      "checksum-uuid",
+		Topic:     "Checksum Meeting",
+		StartTime: time.Date(2024, 3, 1, 14, 0, 0, 0, time.UTC),
+		RecordingFiles: []zoom.RecordingFile{
+			{ID: "file-1", FileType: "MP4", DownloadURL: "https://zoom.us/download/checksum.mp4", FileSize: 1024},
+		},
+	}
+
+	processor := NewUserProcessor(
+		newMockZoomClient(),
+		newMockDownloadManager(),
+		nil,
+		filename.NewFileSanitizer(filename.FileSanitizerOptions{}),
+		uploadManager,
+		ProcessorConfig{
+			BaseDownloadDir:   tmpDir,
+			BoxEnabled:        true,
+			DeleteAfterUpload: true,
+			ContinueOnError:   true,
+		},
+	)
+
+	result, err := processor.ProcessRecordings(context.Background(), "jane.smith@example.com", "jane.smith@example.com", []*zoom.Recording{recording})
+	if err != nil {
+		t.Fatalf("ProcessRecordings failed: %v", err)
+	}
+	if result.ErrorCount != 1 || result.UploadedCount != 0 || result.DeletedCount != 0 {
+		t.Errorf("Expected the mismatched upload to fail without deleting, got %+v", result)
+	}
+	if len(boxClient.deletedFiles) != 1 {
+		t.Errorf("Expected the corrupt Box file to be deleted, got %v", boxClient.deletedFiles)
+	}
+	if len(result.Files) != 1 || result.Files[0].Outcome != OutcomeFailed || result.Files[0].LocalPath == "" {
+		t.Fatalf("Expected a failed file outcome, got %+v", result.Files)
+	}
+	if _, err := os.Stat(result.Files[0].LocalPath); err != nil {
+		t.Errorf("Expected the local recording to be kept: %v", err)
+	}
+}
+
+func TestUserProcessor_ReplayDownloadFailure(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	downloadManager := newMockDownloadManager()
+	processor := NewUserProcessor(
+		newMockZoomClient(),
+		downloadManager,
+		nil,
+		filename.NewFileSanitizer(filename.FileSanitizerOptions{}),
+		nil,
+		ProcessorConfig{BaseDownloadDir: tmpDir},
+	)
+
+	failure := runreport.Failure{
+		Operation: runreport.OperationDownload,
+		ZoomEmail: "jane.smith@example.com",
+		BoxEmail:  "jane.smith@example.com",
+		Recording: zoom.Recording{
+			UUID:      "replay-uuid",
+			Topic:     "Replay Meeting",
+			StartTime: time.Date(2024, 3, 1, 14, 0, 0, 0, time.UTC),
+			RecordingFiles: []zoom.RecordingFile{
+				{ID: "file-1", FileType: "MP4", DownloadURL: "https://zoom.us/download/one.mp4"},
+				{ID: "file-2", FileType: "MP4", DownloadURL: "https://zoom.us/download/two.mp4"},
+			},
+		},
+		RecordingFileID: "file-1",
+	}
+
+	if err := processor.ReplayFailure(context.Background(), failure); err != nil {
+		t.Fatalf("ReplayFailure failed: %v", err)
+	}
+	if len(downloadManager.downloadAttempted) != 1 {
+		t.Errorf("Expected only the failed file to be downloaded, got %v", downloadManager.downloadAttempted)
+	}
+
+	failure.RecordingFileID = "missing"
+	if err := processor.ReplayFailure(context.Background(), failure); err == nil {
+		t.Error("Expected an error for a recording file missing from the stored metadata")
+	}
+}
+
+func TestUserProcessor_ReplayRefreshesRecording(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// A UUID that needs double encoding, so its stored metadata may predate a working lookup
+	uuid := "/ajXp112QmuoKj48548=="
+	stored := zoom.Recording{
+		UUID:      uuid,
+		Topic:     "Slash Meeting",
+		StartTime: time.Date(2024, 3, 1, 14, 0, 0, 0, time.UTC),
+		RecordingFiles: []zoom.RecordingFile{
+			{ID: "file-1", FileType: "MP4", DownloadURL: "https://zoom.us/download/expired.mp4"},
+		},
+	}
+	current := stored
+	current.RecordingFiles = []zoom.RecordingFile{
+		{ID: "file-1", FileType: "MP4", DownloadURL: "https://zoom.us/download/fresh.mp4"},
+	}
+
+	zoomClient := newMockZoomClient()
+	zoomClient.meetings = map[string]*zoom.Recording{uuid: &current}
+	downloadManager := newMockDownloadManager()
+	processor := NewUserProcessor(
+		zoomClient,
+		downloadManager,
+		nil,
+		filename.NewFileSanitizer(filename.FileSanitizerOptions{}),
+		nil,
+		ProcessorConfig{BaseDownloadDir: tmpDir},
+	)
+
+	failure := runreport.Failure{
+		Operation:       runreport.OperationDownload,
+		ZoomEmail:       "jane.smith@example.com",
+		BoxEmail:        "jane.smith@example.com",
+		Recording:       stored,
+		RecordingFileID: "file-1",
+	}
+	if err := processor.ReplayFailure(context.Background(), failure); err != nil {
+		t.Fatalf("ReplayFailure failed: %v", err)
+	}
+	if len(downloadManager.requests) != 1 || downloadManager.requests[0].URL != "https://zoom.us/download/fresh.mp4" {
+		t.Errorf("Expected the refreshed download URL, got %+v", downloadManager.requests)
+	}
+}
+
+func TestUserProcessor_RunDeadline(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	activeUsersPath := filepath.Join(tmpDir, "active_users.txt")
+	if err := os.WriteFile(activeUsersPath, []byte("first@example.com\nsecond@example.com\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	usersFile, err := users.LoadActiveUsersFile(activeUsersPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	zoomClient := newMockZoomClient()
+	for _, user := range []string{"first@example.com", "second@example.com"} {
+		zoomClient.recordings[user] = []*zoom.Recording{
+			{
+				UUID:      "uuid-" + user,
+				Topic:     "Meeting",
+				StartTime: time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
+				RecordingFiles: []zoom.RecordingFile{
+					{ID: "file-1", FileType: "MP4", DownloadURL: "https://zoom.us/download/1.mp4"},
+					{ID: "file-2", FileType: "MP4", DownloadURL: "https://zoom.us/download/2.mp4"},
+				},
+			},
+		}
+	}
+
+	// The first file is already in flight when the deadline passes; it must finish
+	downloadManager := newMockDownloadManager()
+	downloadManager.downloadDelay = 100 * time.Millisecond
+
+	processor := NewUserProcessor(
+		zoomClient,
+		downloadManager,
+		nil,
+		filename.NewFileSanitizer(filename.FileSanitizerOptions{}),
+		nil,
+		ProcessorConfig{
+			BaseDownloadDir: tmpDir,
+			ContinueOnError: true,
+			Deadline:        time.Now().Add(50 * time.Millisecond),
+		},
+	)
+
+	summary, err := processor.ProcessAllUsers(context.Background(), usersFile)
+	if err != nil {
+		t.Fatalf("ProcessAllUsers failed: %v", err)
+	}
+
+	if !summary.TimeBoxed {
+		t.Error("Expected the run to be time-boxed")
+	}
+	if len(downloadManager.downloadAttempted) != 1 || summary.TotalDownloads != 1 {
+		t.Errorf("Expected only the in-flight download to complete, got %v", downloadManager.downloadAttempted)
+	}
+	if len(summary.UserResults) != 1 || !summary.UserResults[0].TimeBoxed {
+		t.Errorf("Expected the second user not to be started, got %d results", len(summary.UserResults))
+	}
+
+	reloaded, err := users.LoadActiveUsersFile(activeUsersPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if incomplete := reloaded.GetIncompleteUsers(); len(incomplete) != 2 {
+		t.Errorf("Expected both users to stay incomplete for the next run, got %d", len(incomplete))
+	}
+}
+
+func TestUserProcessor_StopChannel(t *testing.T) {
+	zoomClient := newMockZoomClient()
+	zoomClient.recordings["jane.smith@example.com"] = []*zoom.Recording{
+		{
+			UUID:      "uuid-stop",
+			Topic:     "Meeting",
+			StartTime: time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
+			RecordingFiles: []zoom.RecordingFile{
+				{ID: "file-1", FileType: "MP4", DownloadURL: "https://zoom.us/download/1.mp4"},
+			},
+		},
+	}
+
+	stop := make(chan struct{})
+	close(stop)
+
+	downloadManager := newMockDownloadManager()
+	processor := NewUserProcessor(
+		zoomClient,
+		downloadManager,
+		nil,
+		filename.NewFileSanitizer(filename.FileSanitizerOptions{}),
+		nil,
+		ProcessorConfig{
+			BaseDownloadDir: t.TempDir(),
+			Stop:            stop,
+		},
+	)
+
+	result, err := processor.ProcessUser(context.Background(), "jane.smith@example.com", "jane.smith@example.com")
+	if err != nil {
+		t.Fatalf("ProcessUser failed: %v", err)
+	}
+	if !result.TimeBoxed || len(downloadManager.downloadAttempted) != 0 {
+		t.Errorf("Expected a closed stop channel to stop before any download, got %+v", result)
+	}
+}
+
+func TestUserProcessor_PreviewMinutes(t *testing.T) {
+	start := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	zoomClient := newMockZoomClient()
+	zoomClient.recordings["jane.smith@example.com"] = []*zoom.Recording{
+		{
+			UUID:      "uuid-preview",
+			Topic:     "Quarterly Review",
+			StartTime: start,
+			Duration:  60,
+			RecordingFiles: []zoom.RecordingFile{
+				{ID: "video", FileType: "MP4", FileSize: 6000, RecordingStart: start, RecordingEnd: start.Add(60 * time.Minute), DownloadURL: "https://zoom.us/download/1.mp4"},
+			},
+		},
+	}
+
+	downloadManager := newMockDownloadManager()
+	processor := NewUserProcessor(
+		zoomClient,
+		downloadManager,
+		nil,
+		filename.NewFileSanitizer(filename.FileSanitizerOptions{}),
+		nil,
+		ProcessorConfig{
+			BaseDownloadDir: t.TempDir(),
+			PreviewMinutes:  5,
+		},
+	)
+
+	if _, err := processor.ProcessUser(context.Background(), "jane.smith@example.com", "jane.smith@example.com"); err != nil {
+		t.Fatalf("ProcessUser failed: %v", err)
+	}
+
+	if len(downloadManager.requests) != 1 {
+		t.Fatalf("Expected 1 download, got %d", len(downloadManager.requests))
+	}
+	req := downloadManager.requests[0]
+	if req.MaxBytes != 500 || !strings.HasSuffix(req.Destination, "-preview.mp4") {
+		t.Errorf("Expected the first 5 of 60 minutes in a preview file, got %d bytes to %s", req.MaxBytes, req.Destination)
+	}
+}
+
+func TestPreviewByteRange(t *testing.T) {
+	start := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		recording zoom.Recording
+		file      zoom.RecordingFile
+		minutes   int
+		want      int64
+	}{
+		{name: "file duration", file: zoom.RecordingFile{FileSize: 1200, RecordingStart: start, RecordingEnd: start.Add(20 * time.Minute)}, minutes: 5, want: 300},
+		{name: "meeting duration fallback", recording: zoom.Recording{Duration: 10}, file: zoom.RecordingFile{FileSize: 1000}, minutes: 1, want: 100},
+		{name: "preview longer than recording", recording: zoom.Recording{Duration: 3}, file: zoom.RecordingFile{FileSize: 1000}, minutes: 5, want: 1000},
+		{name: "unknown duration", file: zoom.RecordingFile{FileSize: 1000}, minutes: 5, want: 0},
+		{name: "unknown size", recording: zoom.Recording{Duration: 10}, minutes: 5, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := previewByteRange(&tt.recording, tt.file, tt.minutes); got != tt.want {
+				t.Errorf("previewByteRange() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+// mockSummaryTracker collects the run summary rows of a processor
+type mockSummaryTracker struct {
+	entries []tracking.SummaryEntry
+}
+
+func (m *mockSummaryTracker) TrackFile(entry tracking.SummaryEntry) error {
+	m.entries = append(m.entries, entry)
+	return nil
+}
+
+func TestUserProcessor_RecordingFilter(t *testing.T) {
+	downloadManager := newMockDownloadManager()
+	summary := &mockSummaryTracker{}
+	filter, err := NewRecordingFilter(5, "", "(?i)standup", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	processor := NewUserProcessor(
+		newMockZoomClient(),
+		downloadManager,
+		nil,
+		filename.NewFileSanitizer(filename.FileSanitizerOptions{}),
+		nil,
+		ProcessorConfig{BaseDownloadDir: t.TempDir(), Filter: filter, RunSummary: summary},
+	)
+
+	startTime := time.Date(2024, 3, 1, 14, 0, 0, 0, time.UTC)
+	recordings := []*zoom.Recording{
+		{UUID: "short", Topic: "Test Call", Duration: 2, StartTime: startTime, RecordingFiles: []zoom.RecordingFile{{ID: "a", FileType: "MP4", DownloadURL: "https://zoom.us/a.mp4"}}},
+		{UUID: "standup", Topic: "Daily Standup", Duration: 15, StartTime: startTime, RecordingFiles: []zoom.RecordingFile{{ID: "b", FileType: "MP4", DownloadURL: "https://zoom.us/b.mp4"}}},
+		{UUID: "review", Topic: "Design Review", Duration: 60, StartTime: startTime, RecordingFiles: []zoom.RecordingFile{{ID: "c", FileType: "MP4", DownloadURL: "https://zoom.us/c.mp4"}}},
+	}
+
+	result, err := processor.ProcessRecordings(context.Background(), "jane@example.com", "jane@example.com", recordings)
+	if err != nil {
+		t.Fatalf("ProcessRecordings failed: %v", err)
+	}
+
+	if result.DownloadedCount != 1 || result.SkippedCount != 2 {
+		t.Errorf("Expected 1 download and 2 filtered recordings, got %+v", result)
+	}
+	if len(downloadManager.downloadAttempted) != 1 || !strings.Contains(downloadManager.downloadAttempted[0], "design-review") {
+		t.Errorf("Expected only the design review to be downloaded, got %v", downloadManager.downloadAttempted)
+	}
+	if len(result.Files) != 3 || result.Files[0].Outcome != OutcomeFiltered || result.Files[2].Outcome != OutcomeDownloaded {
+		t.Errorf("Expected per-file outcomes for every recording, got %+v", result.Files)
+	}
+	if len(summary.entries) != 3 || summary.entries[0].Status != OutcomeFiltered || summary.entries[2].Status != OutcomeDownloaded ||
+		summary.entries[2].MeetingTopic != "Design Review" || summary.entries[2].ZoomUser != "jane@example.com" || summary.entries[2].FileName == "" {
+		t.Errorf("Expected a run summary row for every recording, got %+v", summary.entries)
+	}
+}
+
+// sizedDownloadManager writes the number of bytes set for each URL
+type sizedDownloadManager struct {
+	sizes map[string]int
+}
+
+func (m *sizedDownloadManager) Download(ctx context.Context, req download.DownloadRequest, progressCallback download.ProgressCallback) (*download.DownloadResult, error) {
+	if err := os.MkdirAll(filepath.Dir(req.Destination), 0755); err != nil {
+		return nil, err
+	}
+	size := m.sizes[req.URL]
+	if err := os.WriteFile(req.Destination, make([]byte, size), 0644); err != nil {
+		return nil, err
+	}
+	return &download.DownloadResult{Success: true, BytesDownloaded: int64(size)}, nil
+}
+
+func TestUserProcessor_FileSizeChecks(t *testing.T) {
+	downloadManager := &sizedDownloadManager{sizes: map[string]int{
+		"https://zoom.us/empty.mp4":     0,
+		"https://zoom.us/truncated.mp4": 1000,
+		"https://zoom.us/complete.mp4":  2040,
+	}}
+	processor := NewUserProcessor(
+		newMockZoomClient(),
+		downloadManager,
+		nil,
+		filename.NewFileSanitizer(filename.FileSanitizerOptions{}),
+		nil,
+		ProcessorConfig{BaseDownloadDir: t.TempDir(), Filter: RecordingFilter{MinFileSize: 1024}, SizeTolerancePercent: 1, ContinueOnError: true},
+	)
+
+	startTime := time.Date(2024, 3, 1, 14, 0, 0, 0, time.UTC)
+	recording := func(uuid string, fileSize int64) *zoom.Recording {
+		return &zoom.Recording{UUID: uuid, Topic: uuid, StartTime: startTime, RecordingFiles: []zoom.RecordingFile{
+			{ID: uuid + "-file", FileType: "MP4", DownloadURL: "https://zoom.us/" + uuid + ".mp4", FileSize: fileSize},
+		}}
+	}
+	recordings := []*zoom.Recording{
+		recording("stub", 0),
+		recording("empty", 2048),
+		recording("truncated", 2048),
+		recording("complete", 2048),
+	}
+
+	result, err := processor.ProcessRecordings(context.Background(), "jane@example.com", "jane@example.com", recordings)
+	if err != nil {
+		t.Fatalf("ProcessRecordings failed: %v", err)
+	}
+
+	if result.DownloadedCount != 1 || result.SkippedCount != 1 || result.ErrorCount != 2 {
+		t.Fatalf("Expected 1 download, 1 filtered and 2 rejected files, got %+v", result)
+	}
+	if result.Files[0].Outcome != OutcomeFiltered || result.Files[3].Outcome != OutcomeDownloaded {
+		t.Errorf("Expected the stub filtered and the complete file downloaded, got %+v", result.Files)
+	}
+	if !errors.Is(result.Errors[0], ErrEmptyDownload) || !errors.Is(result.Errors[1], ErrSizeMismatch) {
+		t.Errorf("Expected the empty and truncated downloads rejected, got %v", result.Errors)
+	}
+	for _, file := range result.Files[1:3] {
+		if _, err := os.Stat(file.LocalPath); file.LocalPath == "" || !os.IsNotExist(err) {
+			t.Errorf("Expected the rejected download %s to be removed", file.LocalPath)
+		}
+	}
+}
+
+// mockWebinarZoomClient is a mockZoomClient that also lists webinar recordings
+type mockWebinarZoomClient struct {
+	*mockZoomClient
+	webinarRecordings map[string][]*zoom.Recording
+}
+
+func (m *mockWebinarZoomClient) GetAllUserWebinarRecordings(ctx context.Context, userID string, params zoom.ListRecordingsParams) ([]*zoom.Recording, error) {
+	return m.webinarRecordings[userID], nil
+}
+
+func TestUserProcessor_IncludeWebinars(t *testing.T) {
+	start := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	recording := func(uuid string) *zoom.Recording {
+		return &zoom.Recording{
+			UUID:      uuid,
+			Topic:     "Topic " + uuid,
+			StartTime: start,
+			RecordingFiles: []zoom.RecordingFile{
+				{ID: uuid + "-video", FileType: "MP4", FileSize: 1000, DownloadURL: "https://zoom.us/download/" + uuid + ".mp4"},
+			},
+		}
+	}
+
+	zoomClient := &mockWebinarZoomClient{
+		mockZoomClient: newMockZoomClient(),
+		webinarRecordings: map[string][]*zoom.Recording{
+			"host@example.com": {recording("meeting"), recording("webinar")},
+		},
+	}
+	zoomClient.recordings["host@example.com"] = []*zoom.Recording{recording("meeting")}
+
+	for _, includeWebinars := range []bool{false, true} {
+		downloadManager := newMockDownloadManager()
+		processor := NewUserProcessor(
+			zoomClient,
+			downloadManager,
+			nil,
+			filename.NewFileSanitizer(filename.FileSanitizerOptions{}),
+			nil,
+			ProcessorConfig{
+				BaseDownloadDir: t.TempDir(),
+				IncludeWebinars: includeWebinars,
+			},
+		)
+
+		if _, err := processor.ProcessUser(context.Background(), "host@example.com", "host@example.com"); err != nil {
+			t.Fatalf("ProcessUser failed: %v", err)
+		}
+
+		want := 1
+		if includeWebinars {
+			want = 2 // The meeting listed by both endpoints is downloaded once
+		}
+		if len(downloadManager.requests) != want {
+			t.Errorf("IncludeWebinars=%v: expected %d downloads, got %d", includeWebinars, want, len(downloadManager.requests))
+		}
+	}
+}
+
+// mockTrashZoomClient is a mockZoomClient that also lists and restores recordings in the trash
+type mockTrashZoomClient struct {
+	*mockZoomClient
+	trashRecordings map[string][]*zoom.Recording
+	restored        []string
+}
+
+func (m *mockTrashZoomClient) GetAllUserTrashRecordings(ctx context.Context, userID string, params zoom.ListRecordingsParams) ([]*zoom.Recording, error) {
+	return m.trashRecordings[userID], nil
+}
+
+func (m *mockTrashZoomClient) RecoverMeetingRecordings(ctx context.Context, meetingUUID string) error {
+	m.restored = append(m.restored, meetingUUID)
+	return nil
+}
+
+func TestUserProcessor_IncludeTrash(t *testing.T) {
+	start := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	recording := func(uuid string, minutes int) *zoom.Recording {
+		return &zoom.Recording{
+			UUID:      uuid,
+			Topic:     "Topic " + uuid,
+			StartTime: start,
+			Duration:  minutes,
+			RecordingFiles: []zoom.RecordingFile{
+				{ID: uuid + "-video", FileType: "MP4", FileSize: 1000, DownloadURL: "https://zoom.us/download/" + uuid + ".mp4"},
+			},
+		}
+	}
+
+	tests := []struct {
+		name         string
+		includeTrash bool
+		restoreTrash bool
+		dryRun       bool
+		wantFiles    int
+		wantRestored []string
+	}{
+		{name: "trash excluded", wantFiles: 1},
+		{name: "trash included", includeTrash: true, wantFiles: 3},
+		{name: "trash restored", includeTrash: true, restoreTrash: true, wantFiles: 3, wantRestored: []string{"deleted"}},
+		{name: "dry run does not restore", includeTrash: true, restoreTrash: true, dryRun: true, wantFiles: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			zoomClient := &mockTrashZoomClient{
+				mockZoomClient: newMockZoomClient(),
+				trashRecordings: map[string][]*zoom.Recording{
+					// The short recording is filtered out and not restored
+					"host@example.com": {recording("meeting", 30), recording("deleted", 30), recording("short", 1)},
+				},
+			}
+			zoomClient.recordings["host@example.com"] = []*zoom.Recording{recording("meeting", 30)}
+
+			downloadManager := newMockDownloadManager()
+			processor := NewUserProcessor(
+				zoomClient,
+				downloadManager,
+				nil,
+				filename.NewFileSanitizer(filename.FileSanitizerOptions{}),
+				nil,
+				ProcessorConfig{
+					BaseDownloadDir: t.TempDir(),
+					IncludeTrash:    tt.includeTrash,
+					RestoreTrash:    tt.restoreTrash,
+					DryRun:          tt.dryRun,
+					Filter:          RecordingFilter{MinDurationMinutes: 5},
+				},
+			)
+
+			result, err := processor.ProcessUser(context.Background(), "host@example.com", "host@example.com")
+			if err != nil {
+				t.Fatalf("ProcessUser failed: %v", err)
+			}
+
+			if len(result.Files) != tt.wantFiles {
+				t.Errorf("Expected %d files considered, got %d", tt.wantFiles, len(result.Files))
+			}
+			if strings.Join(zoomClient.restored, ",") != strings.Join(tt.wantRestored, ",") {
+				t.Errorf("Expected %v to be restored, got %v", tt.wantRestored, zoomClient.restored)
+			}
+		})
+	}
+}
+
+func TestUserProcessor_DryRunPlansBoxUploads(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	zoomClient := newMockZoomClient()
+	downloadManager := newMockDownloadManager()
+	boxClient := newMockBoxClient()
+	boxUploadManager := newMockUploadManager(boxClient)
+
+	newRecording := func(uuid string, start time.Time, size int64) *zoom.Recording {
+		return &zoom.Recording{
+			UUID:      uuid,
+			Topic:     "Weekly Sync",
+			StartTime: start,
+			RecordingFiles: []zoom.RecordingFile{
+				{ID: uuid + "-mp4", FileType: "MP4", DownloadURL: "https://zoom.us/download/" + uuid, FileSize: size},
+			},
+			DownloadAccessToken: "test-token",
+		}
+	}
+	zoomClient.recordings["jane@example.com"] = []*zoom.Recording{
+		newRecording("uploaded", time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC), 4096),
+		newRecording("new-day", time.Date(2024, 1, 16, 9, 0, 0, 0, time.UTC), 8192),
+	}
+
+	// 2024/01/15 exists in Box with the first recording already uploaded; 2024/01/16 does not
+	zoomFolder := "zoom-folder-jane@example.com"
+	boxClient.lookupFolders = map[string]bool{
+		zoomFolder + "/2024":       true,
+		zoomFolder + "/2024/01":    true,
+		zoomFolder + "/2024/01/15": true,
+	}
+	existingKey := zoomFolder + "/2024/01/15/weekly-sync-1030.mp4"
+	boxClient.existingFiles[existingKey] = true
+	boxClient.existingSizes[existingKey] = 4096
+
+	userManager, _ := users.NewActiveUserManager(users.ActiveUserConfig{})
+	dirManager := directory.NewDirectoryManager(directory.DirectoryConfig{BaseDirectory: tmpDir}, userManager)
+	processor := NewUserProcessor(zoomClient, downloadManager, dirManager,
+		filename.NewFileSanitizer(filename.FileSanitizerOptions{}), boxUploadManager,
+		ProcessorConfig{BaseDownloadDir: tmpDir, BoxEnabled: true, DryRun: true})
+
+	result, err := processor.ProcessUser(context.Background(), "jane@example.com", "jane@example.com")
+	if err != nil {
+		t.Fatalf("ProcessUser failed: %v", err)
+	}
+
+	if result.SkippedCount != 1 || result.DownloadedCount != 1 {
+		t.Errorf("Expected 1 file already in Box and 1 to transfer, got %d skipped and %d downloaded", result.SkippedCount, result.DownloadedCount)
+	}
+	if result.BytesPlanned != 8192 {
+		t.Errorf("Expected 8192 planned bytes, got %d", result.BytesPlanned)
+	}
+	if len(downloadManager.downloadAttempted) != 0 || len(boxClient.folders) != 0 || len(boxClient.files) != 0 {
+		t.Errorf("Expected a dry run to change nothing, got %d downloads, %d Box folders and %d Box files",
+			len(downloadManager.downloadAttempted), len(boxClient.folders), len(boxClient.files))
+	}
+	if entries, _ := os.ReadDir(tmpDir); len(entries) != 0 {
+		t.Errorf("Expected no local directories in a dry run, found %d entries", len(entries))
+	}
+}
+
+// unverifiedDestination hides a destination's verification, like a backend without checksums
+type unverifiedDestination struct {
+	storage.UploadDestination
+}
+
+func (d unverifiedDestination) UploadFile(ctx context.Context, req storage.UploadRequest) (*storage.UploadResult, error) {
+	result, err := d.UploadDestination.UploadFile(ctx, req)
+	if result != nil {
+		result.Verified = false
+	}
+	return result, err
+}
+
+func TestUserProcessor_DeleteRequiresVerification(t *testing.T) {
+	tests := []struct {
+		name        string
+		destination func(box.UploadManager) storage.UploadDestination
+		wantDeleted int
+	}{
+		{name: "verified upload is deleted", destination: storage.NewBoxDestination, wantDeleted: 1},
+		{name: "unverified upload is kept", destination: func(m box.UploadManager) storage.UploadDestination {
+			return unverifiedDestination{storage.NewBoxDestination(m)}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recording := &zoom.Recording{
+				UUID:      "verify-uuid",
+				Topic:     "Verify Meeting",
+				StartTime: time.Date(2024, 3, 1, 14, 0, 0, 0, time.UTC),
+				RecordingFiles: []zoom.RecordingFile{
+					{ID: "file-1", FileType: "MP4", DownloadURL: "https://zoom.us/download/verify.mp4", FileSize: 1024},
+				},
+			}
+
+			processor := NewUserProcessorWithDestination(
+				newMockZoomClient(),
+				newMockDownloadManager(),
+				nil,
+				filename.NewFileSanitizer(filename.FileSanitizerOptions{}),
+				tt.destination(newMockUploadManager(newMockBoxClient())),
+				ProcessorConfig{
+					BaseDownloadDir:            t.TempDir(),
+					BoxEnabled:                 true,
+					DeleteAfterUpload:          true,
+					DeleteRequiresVerification: true,
+				},
+			)
+
+			result, err := processor.ProcessRecordings(context.Background(), "jane.smith@example.com", "jane.smith@example.com", []*zoom.Recording{recording})
+			if err != nil {
+				t.Fatalf("ProcessRecordings failed: %v", err)
+			}
+			if result.UploadedCount != 1 || result.DeletedCount != tt.wantDeleted {
+				t.Fatalf("Expected 1 upload and %d deletions, got %+v", tt.wantDeleted, result)
+			}
+			_, statErr := os.Stat(result.Files[0].LocalPath)
+			if kept := statErr == nil; kept != (tt.wantDeleted == 0) {
+				t.Errorf("Expected local file kept=%v, stat error: %v", tt.wantDeleted == 0, statErr)
+			}
+		})
+	}
+}
+
+// capturingDestination records the local path and content of each uploaded file
+type capturingDestination struct {
+	storage.UploadDestination
+	paths    map[string]string
+	contents map[string][]byte
+	folders  map[string]string // Destination folder of each file (nil = not recorded)
+}
+
+func (d *capturingDestination) UploadFile(ctx context.Context, req storage.UploadRequest) (*storage.UploadResult, error) {
+	content, err := os.ReadFile(req.LocalPath)
+	if err != nil {
+		return nil, err
+	}
+	d.paths[req.FileName] = req.LocalPath
+	d.contents[req.FileName] = content
+	if d.folders != nil {
+		d.folders[req.FileName] = req.FolderPath
+	}
+	return d.UploadDestination.UploadFile(ctx, req)
+}
+
+func TestUserProcessor_EncryptionAtRest(t *testing.T) {
+	key := make([]byte, atrest.KeySize)
+	cipher, err := atrest.NewCipher(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recording := &zoom.Recording{
+		UUID:      "encrypt-uuid",
+		Topic:     "Encrypt Meeting",
+		StartTime: time.Date(2024, 3, 1, 14, 0, 0, 0, time.UTC),
+		RecordingFiles: []zoom.RecordingFile{
+			{ID: "file-1", FileType: "MP4", DownloadURL: "https://zoom.us/download/encrypt.mp4", FileSize: 1024},
+		},
+	}
+	destination := &capturingDestination{
+		UploadDestination: storage.NewBoxDestination(newMockUploadManager(newMockBoxClient())),
+		paths:             make(map[string]string),
+		contents:          make(map[string][]byte),
+	}
+
+	processor := NewUserProcessorWithDestination(
+		newMockZoomClient(),
+		newMockDownloadManager(),
+		nil,
+		filename.NewFileSanitizer(filename.FileSanitizerOptions{}),
+		destination,
+		ProcessorConfig{
+			BaseDownloadDir:   t.TempDir(),
+			BoxEnabled:        true,
+			Encryption:        cipher,
+			EncryptionTempDir: t.TempDir(),
+		},
+	)
+
+	result, err := processor.ProcessRecordings(context.Background(), "jane.smith@example.com", "jane.smith@example.com", []*zoom.Recording{recording})
+	if err != nil {
+		t.Fatalf("ProcessRecordings failed: %v", err)
+	}
+	if result.UploadedCount != 1 {
+		t.Fatalf("Expected 1 upload, got %+v", result)
+	}
+
+	localPath := result.Files[0].LocalPath
+	if size, encrypted, err := atrest.Inspect(localPath); err != nil || !encrypted || size != int64(len("test content")) {
+		t.Errorf("Expected the local recording to be encrypted, got size %d, encrypted %v, err %v", size, encrypted, err)
+	}
+
+	name := filepath.Base(localPath)
+	if string(destination.contents[name]) != "test content" {
+		t.Errorf("Expected the plaintext to be uploaded, got %q", destination.contents[name])
+	}
+	if uploadPath := destination.paths[name]; uploadPath == localPath {
+		t.Error("Expected a decrypted copy to be uploaded")
+	} else if _, err := os.Stat(uploadPath); !os.IsNotExist(err) {
+		t.Errorf("Expected the decrypted copy to be removed, stat error: %v", err)
+	}
+}
+
+// cancellingDownloadManager writes a partial file and cancels the run, as SIGINT would mid-download
+type cancellingDownloadManager struct {
+	*mockDownloadManager
+	cancel context.CancelFunc
+}
+
+func (m *cancellingDownloadManager) Download(ctx context.Context, req download.DownloadRequest, progressCallback download.ProgressCallback) (*download.DownloadResult, error) {
+	m.downloadAttempted = append(m.downloadAttempted, req.Destination)
+	if err := os.MkdirAll(filepath.Dir(req.Destination), 0755); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(req.Destination, []byte("partial"), 0644); err != nil {
+		return nil, err
+	}
+	m.cancel()
+	return nil, ctx.Err()
+}
+
+func TestUserProcessor_InterruptedDownload(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	recording := &zoom.Recording{
+		UUID:      "interrupt-uuid",
+		Topic:     "Interrupted Meeting",
+		StartTime: time.Date(2024, 3, 1, 14, 0, 0, 0, time.UTC),
+		RecordingFiles: []zoom.RecordingFile{
+			{ID: "file-1", FileType: "MP4", DownloadURL: "https://zoom.us/download/first.mp4", FileSize: 1024},
+			{ID: "file-2", FileType: "M4A", DownloadURL: "https://zoom.us/download/second.m4a", FileSize: 512},
+		},
+	}
+
+	downloadManager := &cancellingDownloadManager{mockDownloadManager: newMockDownloadManager(), cancel: cancel}
+	processor := NewUserProcessor(
+		newMockZoomClient(),
+		downloadManager,
+		nil,
+		filename.NewFileSanitizer(filename.FileSanitizerOptions{}),
+		nil,
+		ProcessorConfig{BaseDownloadDir: t.TempDir()},
+	)
+
+	result, err := processor.ProcessRecordings(ctx, "jane.smith@example.com", "jane.smith@example.com", []*zoom.Recording{recording})
+	if err != nil {
+		t.Fatalf("ProcessRecordings failed: %v", err)
+	}
+	if !result.Interrupted {
+		t.Error("Expected the result to be marked interrupted")
+	}
+	if result.ErrorCount != 0 || result.DownloadedCount != 0 {
+		t.Errorf("Expected no errors or downloads counted, got %+v", result)
+	}
+	if len(downloadManager.downloadAttempted) != 1 {
+		t.Fatalf("Expected the second file not to be started, got %v", downloadManager.downloadAttempted)
+	}
+	if _, err := os.Stat(downloadManager.downloadAttempted[0]); !os.IsNotExist(err) {
+		t.Errorf("Expected the partial download to be removed, stat error: %v", err)
+	}
+}
+
+func TestUserProcessor_MaxUserBytes(t *testing.T) {
+	baseDir := t.TempDir()
+	start := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	// Recordings already on disk for the user count towards the cap
+	existingDir := filepath.Join(baseDir, "jane.smith", "2024", "01", "14")
+	if err := os.MkdirAll(existingDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(existingDir, "old.mp4"), make([]byte, 60), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	zoomClient := newMockZoomClient()
+	zoomClient.recordings["jane.smith@example.com"] = []*zoom.Recording{
+		{
+			UUID:      "uuid-quota",
+			Topic:     "Meeting",
+			StartTime: start,
+			RecordingFiles: []zoom.RecordingFile{
+				{ID: "file-1", FileType: "MP4", FileSize: 30, RecordingStart: start, DownloadURL: "https://zoom.us/download/1.mp4"},
+				{ID: "file-2", FileType: "MP4", FileSize: 30, RecordingStart: start.Add(time.Hour), DownloadURL: "https://zoom.us/download/2.mp4"},
+			},
+		},
+	}
+
+	downloadManager := newMockDownloadManager()
+	processor := NewUserProcessor(
+		zoomClient,
+		downloadManager,
+		nil,
+		filename.NewFileSanitizer(filename.FileSanitizerOptions{}),
+		nil,
+		ProcessorConfig{
+			BaseDownloadDir: baseDir,
+			ContinueOnError: true,
+			MaxUserBytes:    100,
+		},
+	)
+
+	result, err := processor.ProcessUser(context.Background(), "jane.smith@example.com", "jane.smith@example.com")
+	if err != nil {
+		t.Fatalf("ProcessUser failed: %v", err)
+	}
+	if len(downloadManager.downloadAttempted) != 1 || result.DownloadedCount != 1 {
+		t.Errorf("Expected only the file within the cap to be downloaded, got %d attempts", len(downloadManager.downloadAttempted))
+	}
+	if result.ErrorCount != 1 || !errors.Is(result.Errors[0], ErrUserQuotaExceeded) {
+		t.Errorf("Expected a user quota error, got %v", result.Errors)
+	}
+}
+
+// overlappingDownloadManager holds each download until another one starts or a short wait
+// passes, so concurrent downloads overlap
+type overlappingDownloadManager struct {
+	mu        sync.Mutex
+	attempted []string
+	started   chan struct{}
+}
+
+func (m *overlappingDownloadManager) Download(ctx context.Context, req download.DownloadRequest, progressCallback download.ProgressCallback) (*download.DownloadResult, error) {
+	m.mu.Lock()
+	m.attempted = append(m.attempted, req.Destination)
+	m.mu.Unlock()
+
+	select {
+	case m.started <- struct{}{}:
+	case <-m.started:
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	if err := os.MkdirAll(filepath.Dir(req.Destination), 0755); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(req.Destination, make([]byte, req.FileSize), 0644); err != nil {
+		return nil, err
+	}
+	return &download.DownloadResult{Success: true, BytesDownloaded: req.FileSize}, nil
+}
+
+func TestUserProcessor_MaxUserBytesConcurrent(t *testing.T) {
+	start := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	zoomClient := newMockZoomClient()
+	zoomClient.recordings["jane.smith@example.com"] = []*zoom.Recording{
+		{
+			UUID:      "uuid-quota",
+			Topic:     "Meeting",
+			StartTime: start,
+			RecordingFiles: []zoom.RecordingFile{
+				{ID: "file-1", FileType: "MP4", FileSize: 60, RecordingStart: start, DownloadURL: "https://zoom.us/download/1.mp4"},
+				{ID: "file-2", FileType: "MP4", FileSize: 60, RecordingStart: start.Add(time.Hour), DownloadURL: "https://zoom.us/download/2.mp4"},
+			},
+		},
+	}
+
+	// Each file fits the cap on its own, but not both together
+	downloadManager := &overlappingDownloadManager{started: make(chan struct{})}
+	processor := NewUserProcessor(
+		zoomClient,
+		downloadManager,
+		nil,
+		filename.NewFileSanitizer(filename.FileSanitizerOptions{}),
+		nil,
+		ProcessorConfig{
+			BaseDownloadDir:     t.TempDir(),
+			ContinueOnError:     true,
+			MaxUserBytes:        100,
+			ConcurrentDownloads: 2,
+		},
+	)
+
+	result, err := processor.ProcessUser(context.Background(), "jane.smith@example.com", "jane.smith@example.com")
+	if err != nil {
+		t.Fatalf("ProcessUser failed: %v", err)
+	}
+	if len(downloadManager.attempted) != 1 || result.DownloadedCount != 1 {
+		t.Errorf("Expected only one of the concurrent files to be downloaded, got %d attempts", len(downloadManager.attempted))
+	}
+	if result.ErrorCount != 1 || !errors.Is(result.Errors[0], ErrUserQuotaExceeded) {
+		t.Errorf("Expected a user quota error, got %v", result.Errors)
+	}
+}
+
+func TestUserProcessor_DiskFull(t *testing.T) {
+	baseDir := t.TempDir()
+	zoomClient := newMockZoomClient()
+	zoomClient.recordings["jane.smith@example.com"] = []*zoom.Recording{
+		{
+			UUID:      "uuid-disk",
+			Topic:     "Meeting",
+			StartTime: time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
+			RecordingFiles: []zoom.RecordingFile{
+				{ID: "file-1", FileType: "MP4", FileSize: 1024, DownloadURL: "https://zoom.us/download/1.mp4"},
+			},
+		},
+	}
+
+	downloadManager := newMockDownloadManager()
+	processor := NewUserProcessor(
+		zoomClient,
+		downloadManager,
+		nil,
+		filename.NewFileSanitizer(filename.FileSanitizerOptions{}),
+		nil,
+		ProcessorConfig{
+			BaseDownloadDir: baseDir,
+			ContinueOnError: true,
+			// No filesystem has this much free
+			DiskGuard: preflight.NewDiskGuard(baseDir, 1<<62, 0),
+		},
+	)
+
+	result, err := processor.ProcessUser(context.Background(), "jane.smith@example.com", "jane.smith@example.com")
+	if err != nil {
+		t.Fatalf("ProcessUser failed: %v", err)
+	}
+	if !result.DiskFull || len(downloadManager.downloadAttempted) != 0 {
+		t.Errorf("Expected the run to stop before downloading, got %+v", result)
+	}
+	if result.ErrorCount != 1 || !errors.Is(result.Errors[0], preflight.ErrInsufficientSpace) {
+		t.Errorf("Expected an insufficient space error, got %v", result.Errors)
+	}
+}
+
+func TestUserProcessor_UploadLocal(t *testing.T) {
+	root := t.TempDir()
+	writeFile := func(rel, content string) {
+		t.Helper()
+		p := filepath.Join(root, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile("jane.smith/2024/01/15/meeting-1030.mp4", "video")
+	writeFile("jane.smith/2024/01/15/meeting-1030.json", "{}")
+	writeFile("jane.smith/uploads.csv", "tracked")
+	writeFile("jane.smith/2024/01/15/.meeting.mp4.enc-123", "partial")
+	writeFile("someone.else/2024/01/15/other.mp4", "not ours")
+
+	destination := &capturingDestination{
+		UploadDestination: storage.NewBoxDestination(newMockUploadManager(newMockBoxClient())),
+		paths:             make(map[string]string),
+		contents:          make(map[string][]byte),
+		folders:           make(map[string]string),
+	}
+	processor := NewUserProcessorWithDestination(
+		newMockZoomClient(),
+		newMockDownloadManager(),
+		nil,
+		filename.NewFileSanitizer(filename.FileSanitizerOptions{}),
+		destination,
+		ProcessorConfig{
+			BaseDownloadDir:   t.TempDir(),
+			BoxEnabled:        true,
+			DeleteAfterUpload: true,
+		},
+	)
+
+	summary, err := processor.UploadLocal(context.Background(), root, []users.UserEntry{
+		{ZoomEmail: "jane.smith@example.com", BoxEmail: "jane.smith@example.com"},
+	})
+	if err != nil {
+		t.Fatalf("UploadLocal failed: %v", err)
+	}
+	if summary.TotalUploads != 2 || summary.TotalErrors != 0 || summary.TotalDeleted != 2 {
+		t.Errorf("Expected 2 files uploaded and deleted, got %+v", summary)
+	}
+	if len(destination.paths) != 2 {
+		t.Errorf("Expected only the layout files to be uploaded, got %v", destination.paths)
+	}
+	if folder := destination.folders["meeting-1030.mp4"]; folder != "2024/01/15" {
+		t.Errorf("Expected the recording in 2024/01/15, got %q", folder)
+	}
+	if _, err := os.Stat(filepath.Join(root, "jane.smith", "2024", "01", "15", "meeting-1030.mp4")); !os.IsNotExist(err) {
+		t.Errorf("Expected the uploaded recording to be deleted, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "someone.else", "2024", "01", "15", "other.mp4")); err != nil {
+		t.Errorf("Expected another user's file to be left alone: %v", err)
 	}
 }
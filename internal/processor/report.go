@@ -0,0 +1,156 @@
+package processor
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/curtbushko/zoom-to-box/internal/timefmt"
+)
+
+// RunReport is the machine-readable summary of a run written with --report-file
+type RunReport struct {
+	StartedAt       time.Time     `json:"started_at"`
+	FinishedAt      time.Time     `json:"finished_at"`
+	DurationSeconds float64       `json:"duration_seconds"`
+	DryRun          bool          `json:"dry_run"`
+	TimeBoxed       bool          `json:"time_boxed"`
+	Interrupted     bool          `json:"interrupted"`
+	DiskFull        bool          `json:"disk_full,omitempty"`
+	Summary         ReportSummary `json:"summary"`
+	Users           []UserReport  `json:"users"`
+}
+
+// ReportSummary holds the run totals
+type ReportSummary struct {
+	TotalUsers      int   `json:"total_users"`
+	ProcessedUsers  int   `json:"processed_users"`
+	FailedUsers     int   `json:"failed_users"`
+	Downloads       int   `json:"downloads"`
+	Uploads         int   `json:"uploads"`
+	Skipped         int   `json:"skipped"`
+	Errors          int   `json:"errors"`
+	Deleted         int   `json:"deleted"`
+	BytesDownloaded int64 `json:"bytes_downloaded"`
+
+	Transfers *TransferStats `json:"transfers,omitempty"` // Speed statistics (nil = no file was transferred)
+}
+
+// UserReport holds the results for a single user
+type UserReport struct {
+	ZoomEmail       string        `json:"zoom_email"`
+	BoxEmail        string        `json:"box_email"`
+	Downloads       int           `json:"downloads"`
+	Uploads         int           `json:"uploads"`
+	Skipped         int           `json:"skipped"`
+	Errors          int           `json:"errors"`
+	Deleted         int           `json:"deleted"`
+	BytesDownloaded int64         `json:"bytes_downloaded"`
+	DurationSeconds float64       `json:"duration_seconds"`
+	TimeBoxed       bool          `json:"time_boxed,omitempty"`
+	TimedOut        bool          `json:"timed_out,omitempty"`
+	ErrorMessages   []string      `json:"error_messages,omitempty"`
+	Files           []FileOutcome `json:"files"`
+}
+
+// NewRunReport builds the report for a run that started at startedAt and has just finished
+func NewRunReport(summary *ProcessorSummary, startedAt time.Time, dryRun bool) *RunReport {
+	finishedAt := timefmt.Now()
+	report := &RunReport{
+		StartedAt:       timefmt.In(startedAt),
+		FinishedAt:      finishedAt,
+		DurationSeconds: finishedAt.Sub(startedAt).Seconds(),
+		DryRun:          dryRun,
+		TimeBoxed:       summary.TimeBoxed,
+		Interrupted:     summary.Interrupted,
+		DiskFull:        summary.DiskFull,
+		Summary: ReportSummary{
+			TotalUsers:      summary.TotalUsers,
+			ProcessedUsers:  summary.ProcessedUsers,
+			FailedUsers:     summary.FailedUsers,
+			Downloads:       summary.TotalDownloads,
+			Uploads:         summary.TotalUploads,
+			Skipped:         summary.TotalSkipped,
+			Errors:          summary.TotalErrors,
+			Deleted:         summary.TotalDeleted,
+			BytesDownloaded: summary.TotalBytesDownloaded,
+		},
+		Users: make([]UserReport, 0, len(summary.UserResults)),
+	}
+
+	for _, result := range summary.UserResults {
+		user := UserReport{
+			ZoomEmail:       result.ZoomEmail,
+			BoxEmail:        result.BoxEmail,
+			Downloads:       result.DownloadedCount,
+			Uploads:         result.UploadedCount,
+			Skipped:         result.SkippedCount,
+			Errors:          result.ErrorCount,
+			Deleted:         result.DeletedCount,
+			BytesDownloaded: result.BytesDownloaded,
+			DurationSeconds: result.Duration.Seconds(),
+			TimeBoxed:       result.TimeBoxed,
+			TimedOut:        result.TimedOut,
+			Files:           result.Files,
+		}
+		if user.Files == nil {
+			user.Files = []FileOutcome{}
+		}
+		for _, err := range result.Errors {
+			user.ErrorMessages = append(user.ErrorMessages, err.Error())
+		}
+		report.Users = append(report.Users, user)
+	}
+	report.Summary.Transfers = NewTransferStats(report.Users, finishedAt.Sub(startedAt))
+
+	return report
+}
+
+// SummaryForUser wraps a single-user result in a summary so it can be reported like a batch run
+func SummaryForUser(result *ProcessorResult) *ProcessorSummary {
+	summary := &ProcessorSummary{
+		TotalUsers:           1,
+		TotalDownloads:       result.DownloadedCount,
+		TotalUploads:         result.UploadedCount,
+		TotalSkipped:         result.SkippedCount,
+		TotalErrors:          result.ErrorCount,
+		TotalDeleted:         result.DeletedCount,
+		TotalBytesDownloaded: result.BytesDownloaded,
+		TotalBytesPlanned:    result.BytesPlanned,
+		Duration:             result.Duration,
+		UserResults:          []*ProcessorResult{result},
+		TimeBoxed:            result.TimeBoxed,
+		Interrupted:          result.Interrupted,
+		DiskFull:             result.DiskFull,
+	}
+	if result.ErrorCount > 0 {
+		summary.FailedUsers = 1
+	} else {
+		summary.ProcessedUsers = 1
+	}
+	return summary
+}
+
+// WriteFile writes the report as indented JSON, replacing the file atomically
+func (r *RunReport) WriteFile(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal report: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create report directory: %w", err)
+	}
+
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to replace report: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package processor
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRunReport_WriteFile(t *testing.T) {
+	result := &ProcessorResult{
+		ZoomEmail:       "jane@example.com",
+		BoxEmail:        "jane@box.example.com",
+		DownloadedCount: 1,
+		UploadedCount:   1,
+		ErrorCount:      1,
+		BytesDownloaded: 2048,
+		Duration:        3 * time.Second,
+		Errors:          []error{errors.New("Box upload failed for b.mp4: quota exceeded")},
+		Files: []FileOutcome{
+			{RecordingUUID: "uuid-1", FileID: "a", FileType: "MP4", FileName: "a.mp4", Outcome: OutcomeUploaded, BytesDownloaded: 2048},
+			{RecordingUUID: "uuid-1", FileID: "b", FileType: "MP4", FileName: "b.mp4", Outcome: OutcomeFailed, Error: "quota exceeded"},
+		},
+	}
+
+	path := filepath.Join(t.TempDir(), "reports", "run-report.json")
+	report := NewRunReport(SummaryForUser(result), time.Now().Add(-time.Minute), false)
+	if err := report.WriteFile(path); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded RunReport
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Report is not valid JSON: %v", err)
+	}
+
+	if decoded.Summary.FailedUsers != 1 || decoded.Summary.BytesDownloaded != 2048 || decoded.DurationSeconds < 60 {
+		t.Errorf("Unexpected summary: %+v (duration %v)", decoded.Summary, decoded.DurationSeconds)
+	}
+	if len(decoded.Users) != 1 {
+		t.Fatalf("Expected 1 user, got %d", len(decoded.Users))
+	}
+
+	user := decoded.Users[0]
+	if user.DurationSeconds != 3 || len(user.ErrorMessages) != 1 || len(user.Files) != 2 {
+		t.Errorf("Unexpected user report: %+v", user)
+	}
+	if user.Files[1].Outcome != OutcomeFailed || user.Files[1].Error != "quota exceeded" {
+		t.Errorf("Unexpected file outcome: %+v", user.Files[1])
+	}
+}
+
+func TestNewTransferStats(t *testing.T) {
+	users := []UserReport{{Files: []FileOutcome{
+		{FileName: "a.mp4", BytesDownloaded: 10 << 20, DownloadSeconds: 2, BytesUploaded: 10 << 20, UploadSeconds: 1},
+		{FileName: "b.mp4", BytesDownloaded: 30 << 20, DownloadSeconds: 3, BytesUploaded: 30 << 20, UploadSeconds: 5},
+		{FileName: "c.mp4", Outcome: OutcomeSkipped},
+	}}}
+
+	stats := NewTransferStats(users, 10*time.Second)
+	if stats == nil {
+		t.Fatal("Expected transfer statistics")
+	}
+	if stats.Files != 2 || stats.BytesDownloaded != 40<<20 || stats.BytesUploaded != 40<<20 {
+		t.Errorf("Unexpected totals: %+v", stats)
+	}
+	if stats.DownloadMBps != 8 || stats.UploadMBps != 6.67 || stats.ThroughputMBps != 8 {
+		t.Errorf("Unexpected speeds: %+v", stats)
+	}
+	if stats.FileSecondsP50 != 3 || stats.FileSecondsP95 != 8 || stats.FileSecondsMax != 8 {
+		t.Errorf("Unexpected file transfer times: %+v", stats)
+	}
+
+	if NewTransferStats([]UserReport{{Files: []FileOutcome{{Outcome: OutcomeSkipped}}}}, time.Second) != nil {
+		t.Error("Expected no statistics without transfers")
+	}
+}
@@ -0,0 +1,73 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/curtbushko/zoom-to-box/internal/logging"
+	"github.com/curtbushko/zoom-to-box/internal/storage"
+	"github.com/curtbushko/zoom-to-box/internal/tracking"
+)
+
+// createSharedLink creates the configured shared link to an uploaded recording and returns its URL
+// A failed link is logged only: the recording itself is in the destination, and the next run
+// shares it again when the upload is skipped as already present.
+func (p *userProcessorImpl) createSharedLink(ctx context.Context, upload *uploadResult, filename string) string {
+	if p.config.SharedLinkAccess == "" || upload == nil || upload.FileID == "" {
+		return ""
+	}
+	linker, ok := p.destination.(storage.SharedLinker)
+	if !ok {
+		return ""
+	}
+
+	logger := logging.GetDefaultLogger()
+	url, err := linker.CreateSharedLink(ctx, upload.FileID, p.config.SharedLinkAccess)
+	if err != nil {
+		if logger != nil {
+			logger.WarnWithContext(ctx, fmt.Sprintf("Failed to create shared link for %s: %v", filename, err))
+		}
+		return ""
+	}
+	if logger != nil {
+		logger.InfoWithContext(ctx, fmt.Sprintf("Created %s shared link for %s: %s", p.config.SharedLinkAccess, filename, url))
+	}
+	return url
+}
+
+// addSharedLinkToMetadata records the shared link in a recording's metadata sidecar
+func addSharedLinkToMetadata(serializer MetadataSerializer, metadataPath, url, access string) error {
+	data, err := os.ReadFile(metadataPath)
+	if err != nil {
+		return fmt.Errorf("failed to read metadata: %w", err)
+	}
+	metadata, err := serializer.Unmarshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to parse metadata: %w", err)
+	}
+
+	metadata["shared_link"] = map[string]interface{}{
+		"url":    url,
+		"access": access,
+	}
+
+	data, err = serializer.Marshal(metadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal recording metadata: %w", err)
+	}
+	if err := os.WriteFile(metadataPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write metadata file %s: %w", metadataPath, err)
+	}
+	return nil
+}
+
+// trackUpload records an uploaded recording in the CSV trackers, including its shared link
+// when the destination can record complete entries
+func (p *userProcessorImpl) trackUpload(entry tracking.UploadEntry) {
+	if tracker, ok := p.destination.(storage.EntryTracker); ok {
+		tracker.TrackUpload(entry)
+		return
+	}
+	p.destination.TrackUploadWithTime(entry.ZoomUser, entry.FileName, entry.RecordingSize, entry.UploadDate, entry.ProcessingTime)
+}
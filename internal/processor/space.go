@@ -0,0 +1,122 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+
+	"github.com/curtbushko/zoom-to-box/internal/email"
+	"github.com/curtbushko/zoom-to-box/internal/progress"
+)
+
+// ErrUserQuotaExceeded is returned when a download would take a user's local recordings
+// above ProcessorConfig.MaxUserBytes
+var ErrUserQuotaExceeded = errors.New("user local storage cap reached")
+
+// reserveSpace checks the per-user cap and the free disk space before size bytes are
+// downloaded for job, and counts size against both while the file downloads, so concurrent
+// downloads cannot together overshoot them. The returned release must be called once the
+// download has ended, with the bytes it left on disk (0 when it failed).
+func (p *userProcessorImpl) reserveSpace(ctx context.Context, job *fileJob, size int64) (func(written int64), error) {
+	size = max(size, 0)
+	username := email.ExtractUsername(job.boxEmail)
+	if p.config.MaxUserBytes > 0 {
+		if err := p.reserveUsage(username, size); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.config.DiskGuard != nil {
+		if err := p.config.DiskGuard.Reserve(ctx, size); err != nil {
+			if p.config.MaxUserBytes > 0 {
+				p.addUsage(username, -size)
+			}
+			return nil, err
+		}
+	}
+
+	return func(written int64) {
+		if p.config.MaxUserBytes > 0 {
+			p.addUsage(username, written-size)
+		}
+		if p.config.DiskGuard != nil {
+			p.config.DiskGuard.Release(size)
+		}
+	}, nil
+}
+
+// reserveUsage adds size to username's local usage unless that would take it above MaxUserBytes
+func (p *userProcessorImpl) reserveUsage(username string, size int64) error {
+	p.usageMu.Lock()
+	defer p.usageMu.Unlock()
+
+	usage, err := p.localUsage(username)
+	if err != nil {
+		return fmt.Errorf("failed to measure local usage for %s: %w", username, err)
+	}
+	if usage+size > p.config.MaxUserBytes {
+		return fmt.Errorf("%w: %s has %s downloaded or downloading and the next file needs %s, above the %s allowed by download.max_user_gb; "+
+			"upload with --delete-after-upload to free it and run again to resume",
+			ErrUserQuotaExceeded, username, progress.FormatBytes(usage), progress.FormatBytes(size),
+			progress.FormatBytes(p.config.MaxUserBytes))
+	}
+	p.userUsage[username] = usage + size
+	return nil
+}
+
+// localUsage returns the bytes held in username's recording folders, with the downloads in
+// progress; the caller holds usageMu
+// The folders are walked on first use; downloads and deletions keep the total current.
+func (p *userProcessorImpl) localUsage(username string) (int64, error) {
+	if usage, ok := p.userUsage[username]; ok {
+		return usage, nil
+	}
+
+	var usage int64
+	baseDir := p.config.BaseDownloadDir
+	err := filepath.WalkDir(baseDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		relDir, err := filepath.Rel(baseDir, filepath.Dir(path))
+		if err != nil {
+			return err
+		}
+		if _, _, ok := p.directoryLayout.Owner(relDir, []string{username}); !ok {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		usage += info.Size()
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if p.userUsage == nil {
+		p.userUsage = make(map[string]int64)
+	}
+	p.userUsage[username] = usage
+	return usage, nil
+}
+
+// addUsage adjusts username's measured local usage by delta bytes
+func (p *userProcessorImpl) addUsage(username string, delta int64) {
+	p.usageMu.Lock()
+	defer p.usageMu.Unlock()
+
+	if usage, ok := p.userUsage[username]; ok {
+		p.userUsage[username] = max(usage+delta, 0)
+	}
+}
@@ -0,0 +1,820 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/curtbushko/zoom-to-box/internal/atrest"
+	"github.com/curtbushko/zoom-to-box/internal/directory"
+	"github.com/curtbushko/zoom-to-box/internal/download"
+	"github.com/curtbushko/zoom-to-box/internal/email"
+	"github.com/curtbushko/zoom-to-box/internal/errclass"
+	"github.com/curtbushko/zoom-to-box/internal/filename"
+	"github.com/curtbushko/zoom-to-box/internal/hooks"
+	"github.com/curtbushko/zoom-to-box/internal/logging"
+	"github.com/curtbushko/zoom-to-box/internal/pipeline"
+	"github.com/curtbushko/zoom-to-box/internal/progress"
+	"github.com/curtbushko/zoom-to-box/internal/runreport"
+	"github.com/curtbushko/zoom-to-box/internal/storage"
+	"github.com/curtbushko/zoom-to-box/internal/timefmt"
+	"github.com/curtbushko/zoom-to-box/internal/tracing"
+	"github.com/curtbushko/zoom-to-box/internal/tracking"
+	"github.com/curtbushko/zoom-to-box/internal/zoom"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Stages of the recording file pipeline, in run order
+const (
+	StagePlan     = "plan"     // Resolve local and destination paths; skip files that are already present
+	StageDownload = "download" // Download the file from Zoom, checksum it, fetch its thumbnail and convert transcripts
+	StageUpload   = "upload"   // Upload the file, its metadata and thumbnail, and track the upload
+	StageVerify   = "verify"   // Remove local copies the destination has (verifiably) received
+)
+
+// Errors for downloads rejected by the post-download size check
+var (
+	ErrEmptyDownload = errors.New("downloaded file is empty")
+	ErrSizeMismatch  = errors.New("downloaded size differs from the size Zoom reported")
+)
+
+// fileJob carries a single recording file through the file pipeline
+// Each stage fills in the fields the following stages read.
+type fileJob struct {
+	zoomEmail     string
+	boxEmail      string
+	recording     *zoom.Recording
+	recordingFile zoom.RecordingFile
+	result        *recordingFileResult
+	previousDone  <-chan struct{} // Closed once the file before this one is done (nil = none); read by the wait stage
+
+	// Set by the plan stage
+	meetingTime   time.Time
+	filename      string
+	filePath      string
+	previewBytes  int64 // Preview size to download (0 = the whole file)
+	inDestination bool  // The destination already has the file
+
+	// Set by the download stage
+	startedAt       time.Time         // Start of the download, for the tracked processing time
+	streamed        bool              // The file was piped into the destination; there is no local copy
+	headers         map[string]string // Zoom download authorization headers
+	checksum        string
+	thumbnailPath   string
+	transcriptPaths []string // SRT and text sidecars converted from a WebVTT transcript
+
+	// Set by the upload stage for recordings encrypted at rest; removed by runFileJob
+	plainPath string // Decrypted temporary copy uploaded in place of filePath
+
+	// Set by the upload stage
+	upload         *uploadResult
+	metadataPath   string
+	metadataUpload *uploadResult // nil until the metadata is in the destination
+}
+
+// newFilePipeline creates the plan → download → upload → verify pipeline for recording files
+func (p *userProcessorImpl) newFilePipeline() *pipeline.Pipeline[*fileJob] {
+	return pipeline.New(
+		pipeline.NewStage(StagePlan, p.planStage),
+		pipeline.NewStage(StageDownload, p.downloadStage),
+		pipeline.NewStage(StageUpload, p.uploadStage),
+		pipeline.NewStage(StageVerify, p.verifyStage),
+	)
+}
+
+// processRecordingFile processes a single recording file (download, upload, delete)
+// observer receives the stage timings (nil = not observed).
+func (p *userProcessorImpl) processRecordingFile(ctx context.Context, zoomEmail, boxEmail string, recording *zoom.Recording, recordingFile zoom.RecordingFile, observer pipeline.Observer) *recordingFileResult {
+	job := &fileJob{
+		zoomEmail:     zoomEmail,
+		boxEmail:      boxEmail,
+		recording:     recording,
+		recordingFile: recordingFile,
+		result:        &recordingFileResult{},
+	}
+	p.runFileJob(ctx, p.filePipeline, job, observer)
+	return job.result
+}
+
+// uploadRecordingFile runs the upload and verify stages for a recording file that is already on disk
+func (p *userProcessorImpl) uploadRecordingFile(ctx context.Context, result *recordingFileResult, zoomEmail, boxEmail string, recording *zoom.Recording, recordingFile zoom.RecordingFile, filePath, checksum, thumbnailPath string, processingStartTime time.Time) {
+	job := &fileJob{
+		zoomEmail:     zoomEmail,
+		boxEmail:      boxEmail,
+		recording:     recording,
+		recordingFile: recordingFile,
+		result:        result,
+		meetingTime:   timefmt.In(recording.StartTime),
+		filename:      filepath.Base(filePath),
+		filePath:      filePath,
+		startedAt:     processingStartTime,
+		checksum:      checksum,
+		thumbnailPath: thumbnailPath,
+	}
+	uploadPipeline, err := p.filePipeline.From(StageUpload)
+	if err != nil {
+		result.Error = err
+		return
+	}
+	p.runFileJob(ctx, uploadPipeline, job, nil)
+}
+
+// runFileJob runs job through fp in a span for the recording file; stages record their failures
+// in the job result
+func (p *userProcessorImpl) runFileJob(ctx context.Context, fp *pipeline.Pipeline[*fileJob], job *fileJob, observer pipeline.Observer) {
+	ctx, span := tracing.Start(ctx, "recording_file",
+		attribute.String("zoom.email", job.zoomEmail),
+		attribute.String("recording.uuid", job.recording.UUID),
+		attribute.String("recording.topic", job.recording.Topic),
+		attribute.String("file.id", job.recordingFile.ID),
+		attribute.String("file.type", job.recordingFile.FileType),
+		attribute.Int64("file.size", job.recordingFile.FileSize))
+	if err := fp.Run(ctx, job, observer); err != nil && job.result.Error == nil {
+		job.result.Error = err
+	}
+	p.recordFileStatus(ctx, job)
+	if job.plainPath != "" {
+		if err := os.RemoveAll(filepath.Dir(job.plainPath)); err != nil {
+			logging.Warn("Failed to remove decrypted upload copy %s: %v", job.plainPath, err)
+		}
+	}
+	span.SetAttributes(
+		attribute.Bool("file.downloaded", job.result.Downloaded),
+		attribute.Bool("file.uploaded", job.result.Uploaded),
+		attribute.Bool("file.skipped", job.result.Skipped))
+	tracing.End(span, job.result.Error)
+}
+
+// planStage resolves the local path and stops for files that need no download:
+// files already on disk or in the destination, meta-only skips and dry runs
+func (p *userProcessorImpl) planStage(ctx context.Context, job *fileJob) error {
+	result := job.result
+	logger := logging.GetDefaultLogger()
+
+	// Extract username from Box email for directory structure
+	username := email.ExtractUsername(job.boxEmail)
+	if username == "" {
+		result.Error = fmt.Errorf("invalid box email format: %s", job.boxEmail)
+		if logger != nil {
+			logger.ErrorWithContext(ctx, result.Error.Error())
+		}
+		return result.Error
+	}
+
+	// Create directory path from the directory layout; the destination uses the same folders
+	meetingTime := timefmt.In(job.recording.StartTime)
+	relativeDir, err := p.directoryLayout.LocalPath(username, meetingTime)
+	if err != nil {
+		result.Error = err
+		return result.Error
+	}
+	folderPath, err := p.directoryLayout.FolderPath(username, meetingTime)
+	if err != nil {
+		result.Error = err
+		return result.Error
+	}
+	if p.config.WindowsSafePaths {
+		relativeDir = directory.SafePath(relativeDir)
+	}
+	dirPath := filepath.Join(p.config.BaseDownloadDir, relativeDir)
+
+	// Create directory if it doesn't exist; a dry run leaves the download directory untouched
+	if !p.config.DryRun {
+		if err := os.MkdirAll(p.longPath(dirPath), 0755); err != nil {
+			result.Error = fmt.Errorf("failed to create directory %s: %w", dirPath, err)
+			if logger != nil {
+				logger.ErrorWithContext(ctx, result.Error.Error())
+			}
+			return result.Error
+		}
+	}
+
+	// Generate filename from the filename template
+	hostEmail := job.recording.HostEmail
+	if hostEmail == "" {
+		hostEmail = job.zoomEmail
+	}
+	templateData := filename.NewTemplateData(p.filenameSanitizer, job.recording.Topic, meetingTime, hostEmail,
+		job.recordingFile.RecordingType, job.recordingFile.FileType, job.recording.UUID, job.recordingFile.ID)
+	baseName, err := p.filenameTemplate.Render(templateData)
+	if err != nil {
+		result.Error = err
+		return result.Error
+	}
+	// Gallery view, speaker view and shared screen files of a meeting get distinct names
+	baseName += p.filenameTemplate.Qualifier(job.recording.RecordingFiles, job.recordingFile)
+	ext := "." + strings.ToLower(job.recordingFile.FileType)
+
+	// Preview files get their own name so a later full migration does not skip the recording
+	var previewBytes int64
+	if p.config.PreviewMinutes > 0 && job.recordingFile.FileType == "MP4" {
+		previewBytes = previewByteRange(job.recording, job.recordingFile, p.config.PreviewMinutes)
+		if previewBytes <= 0 {
+			if logger != nil {
+				logger.WarnWithContext(ctx, fmt.Sprintf("Skipped (preview size unknown, no duration or file size): %s", baseName+ext))
+			}
+			result.Skipped = true
+			return pipeline.ErrStop
+		}
+		baseName += "-preview"
+	}
+
+	if p.config.WindowsSafePaths {
+		if baseName, err = directory.FitName(dirPath, baseName, len(ext)+windowsPathReserve, p.maxPathLength()); err != nil {
+			result.Error = err
+			if logger != nil {
+				logger.ErrorWithContext(ctx, result.Error.Error())
+			}
+			return result.Error
+		}
+	}
+
+	// Files that would get the same name in this folder (e.g. two meetings with the same topic
+	// starting in the same minute) are told apart with a -2, -3, ... suffix
+	filename := p.filenameTemplate.Unique(dirPath, baseName, ext, job.recording.UUID+"/"+job.recordingFile.ID)
+	filePath := p.longPath(filepath.Join(dirPath, filename))
+	result.FileName = filename
+	result.LocalPath = filePath
+
+	job.meetingTime = meetingTime
+	job.filename = filename
+	job.filePath = filePath
+	job.previewBytes = previewBytes
+
+	// A file an earlier run uploaded is skipped without checking the destination again
+	if p.completedEarlier(job) {
+		if p.config.Verbose && logger != nil {
+			logger.InfoWithContext(ctx, fmt.Sprintf("Skipped (completed in an earlier run): %s", filename))
+		}
+		result.Skipped = true
+		return pipeline.ErrStop
+	}
+
+	// Check if file already exists locally; an interrupted download is resumed instead
+	if _, err := os.Stat(filePath); err == nil {
+		if !download.IsPartial(filePath) {
+			if p.config.Verbose && logger != nil {
+				logger.InfoWithContext(ctx, fmt.Sprintf("Skipped (already exists locally): %s", filename))
+			}
+			result.Skipped = true
+			return pipeline.ErrStop
+		}
+		if logger != nil {
+			logger.InfoWithContext(ctx, fmt.Sprintf("Resuming partial download: %s", filename))
+		}
+	}
+
+	// Check if file already exists in the destination BEFORE downloading from Zoom
+	if p.config.BoxEnabled && p.destination != nil {
+		if logger != nil {
+			logger.InfoWithContext(ctx, fmt.Sprintf("Processing: %s (checking if exists in %s)", filename, p.destination.Name()))
+		}
+		expectedSize := job.recordingFile.FileSize
+		if previewBytes > 0 {
+			expectedSize = previewBytes
+		}
+		var exists, sizeMismatch bool
+		if planner, ok := p.destination.(storage.UploadPlanner); ok && p.config.DryRun {
+			exists, sizeMismatch = p.planDestination(ctx, planner, job.boxEmail, folderPath, filename, expectedSize)
+		} else {
+			exists, sizeMismatch = p.existsInDestination(ctx, job.boxEmail, folderPath, filename, expectedSize)
+		}
+		if exists && !sizeMismatch {
+			// File already exists - skip download entirely
+			job.inDestination = true
+			if logger != nil {
+				logger.InfoWithContext(ctx, fmt.Sprintf("Skipped (already exists in %s): %s", p.destination.Name(), filename))
+			}
+			result.Skipped = true
+			return pipeline.ErrStop
+		}
+		if sizeMismatch && logger != nil {
+			logger.WarnWithContext(ctx, fmt.Sprintf("%s exists in %s with a different size than Zoom reports; downloading again to resolve the conflict",
+				filename, p.destination.Name()))
+		}
+	}
+
+	// Skip if meta-only mode and this is not a metadata file
+	if p.config.MetaOnly && job.recordingFile.FileType == "MP4" {
+		if p.config.Verbose && logger != nil {
+			logger.InfoWithContext(ctx, fmt.Sprintf("Skipped (meta-only mode): %s", filename))
+		}
+		result.Skipped = true
+		return pipeline.ErrStop
+	}
+
+	// Skip download if dry run
+	if p.config.DryRun {
+		size := job.recordingFile.FileSize
+		if previewBytes > 0 {
+			size = previewBytes
+		}
+		if logger != nil {
+			if p.config.BoxEnabled && p.destination != nil {
+				logger.InfoWithContext(ctx, fmt.Sprintf("Would download and upload to %s: %s (%s)",
+					p.destination.Name(), path.Join(folderPath, filename), progress.FormatBytes(size)))
+			} else {
+				logger.InfoWithContext(ctx, fmt.Sprintf("Would download: %s (%s)", filePath, progress.FormatBytes(size)))
+			}
+		}
+		result.Downloaded = true
+		result.BytesPlanned = size
+		return pipeline.ErrStop
+	}
+
+	return nil
+}
+
+// handlePartialDownload deals with the file left by a download that stopped early
+// A partial file marked for resuming is continued next run; any other partial file is
+// removed, otherwise the next run would skip it as already downloaded.
+func (p *userProcessorImpl) handlePartialDownload(ctx context.Context, filePath, filename, what string) {
+	logger := logging.GetDefaultLogger()
+	if download.IsPartial(filePath) {
+		if logger != nil {
+			logger.WarnWithContext(ctx, fmt.Sprintf("%s, kept partial file to resume: %s", what, filename))
+		}
+		return
+	}
+	if removeErr := os.Remove(filePath); removeErr != nil && !os.IsNotExist(removeErr) && logger != nil {
+		logger.WarnWithContext(ctx, fmt.Sprintf("Failed to remove partial download %s: %v", filePath, removeErr))
+	}
+	if logger != nil {
+		logger.WarnWithContext(ctx, fmt.Sprintf("%s, removed partial file: %s", what, filename))
+	}
+}
+
+// checkDownloadSize rejects a download that left an empty file, or one whose size differs from
+// the size Zoom reported by more than SizeTolerancePercent; previews are only checked for being empty
+func (p *userProcessorImpl) checkDownloadSize(filePath string, recordingFile zoom.RecordingFile, previewBytes, downloaded int64) error {
+	if info, err := os.Stat(filePath); err == nil && info.Size() == 0 {
+		return ErrEmptyDownload
+	}
+	if previewBytes > 0 || recordingFile.FileSize <= 0 {
+		return nil
+	}
+	difference := math.Abs(float64(downloaded - recordingFile.FileSize))
+	if difference > float64(recordingFile.FileSize)*p.config.SizeTolerancePercent/100 {
+		return fmt.Errorf("%w: got %d bytes, Zoom reported %d", ErrSizeMismatch, downloaded, recordingFile.FileSize)
+	}
+	return nil
+}
+
+// downloadStage downloads the file from Zoom, checksums it and fetches its thumbnail
+// Without an upload destination the pipeline ends here.
+func (p *userProcessorImpl) downloadStage(ctx context.Context, job *fileJob) error {
+	result := job.result
+	logger := logging.GetDefaultLogger()
+	recording, recordingFile, filename, filePath := job.recording, job.recordingFile, job.filename, job.filePath
+
+	// Start timing the total process (download + upload)
+	job.startedAt = time.Now()
+
+	// Prepare download URL and headers with access token if available
+	downloadURL := recordingFile.DownloadURL
+	headers := make(map[string]string)
+
+	// Add download access token as Authorization Bearer header (not query parameter)
+	// This prevents file size limitations that occur when using query parameter tokens
+	// Use download_access_token if available, otherwise fall back to OAuth token
+	if recording.DownloadAccessToken != "" {
+		headers["Authorization"] = fmt.Sprintf("Bearer %s", recording.DownloadAccessToken)
+	} else {
+		// Fall back to OAuth access token if download_access_token is not available
+		// This happens when "View the recording content" permission is not enabled
+		oauthToken, err := p.zoomClient.GetOAuthAccessToken(ctx)
+		if err != nil {
+			result.Error = fmt.Errorf("failed to get access token for download: %w", err)
+			return result.Error
+		}
+		headers["Authorization"] = oauthToken
+	}
+	job.headers = headers
+
+	// Large recordings can be piped straight into the destination instead of being downloaded
+	if uploader, ok := p.streamUploader(job); ok {
+		if streamed, err := p.streamFile(ctx, job, uploader); streamed || err != nil {
+			return err
+		}
+	}
+
+	// Download the file
+	downloadReq := download.DownloadRequest{
+		ID:          fmt.Sprintf("%s-%s", recording.UUID, recordingFile.ID),
+		URL:         downloadURL,
+		Destination: filePath,
+		FileSize:    recordingFile.FileSize,
+		Headers:     headers,
+		MaxBytes:    job.previewBytes,
+		MediaOnly:   recordingFile.FileType == "MP4" || recordingFile.FileType == "M4A",
+		Metadata: map[string]interface{}{
+			"user_email":    job.zoomEmail,
+			"meeting_id":    recording.UUID,
+			"meeting_topic": recording.Topic,
+			"file_type":     recordingFile.FileType,
+			"filename":      filename,
+		},
+	}
+
+	downloadSize := recordingFile.FileSize
+	if job.previewBytes > 0 {
+		downloadSize = job.previewBytes
+	}
+
+	// Check there is room for the file before writing any of it
+	releaseSpace, err := p.reserveSpace(ctx, job, downloadSize)
+	if err != nil {
+		result.Error = fmt.Errorf("not downloading %s: %w", filename, err)
+		if ctx.Err() != nil {
+			return result.Error
+		}
+		if logger != nil {
+			logger.ErrorWithContext(ctx, result.Error.Error())
+		}
+		p.recordFailure(runreport.OperationDownload, job.zoomEmail, job.boxEmail, recording, recordingFile, "", result.Error)
+		return result.Error
+	}
+
+	// The progress display follows one transfer at a time; concurrent downloads only report finished files
+	var progressCallback download.ProgressCallback
+	reportTransfer := p.config.Progress != nil && p.downloadConcurrency() == 1
+	if reportTransfer {
+		p.config.Progress.StartTransfer("download", filename, downloadSize)
+		progressCallback = func(update download.ProgressUpdate) {
+			p.config.Progress.Update(update.BytesDownloaded, update.TotalBytes)
+		}
+	}
+
+	downloadCtx, cancelDownload := p.fileContext(ctx)
+	downloadStart := time.Now()
+	downloadResult, err := p.downloadManager.Download(downloadCtx, downloadReq, progressCallback)
+	if err != nil && p.config.DisablePasscodes && errclass.Of(err) == errclass.Passcode {
+		downloadResult, err = p.downloadWithoutPasscode(downloadCtx, recording, err, func() (*download.DownloadResult, error) {
+			return p.downloadManager.Download(downloadCtx, downloadReq, progressCallback)
+		})
+	}
+	result.DownloadDuration = time.Since(downloadStart)
+	fileTimedOut := errors.Is(context.Cause(downloadCtx), ErrFileTimeout)
+	cancelDownload()
+	if reportTransfer {
+		p.config.Progress.EndTransfer()
+	}
+	if err != nil {
+		releaseSpace(0)
+	}
+	if err != nil && ctx.Err() != nil {
+		result.Error = fmt.Errorf("download of %s interrupted: %w", filename, context.Cause(ctx))
+		p.handlePartialDownload(ctx, filePath, filename, "Interrupted download")
+		return result.Error
+	}
+	if err != nil && fileTimedOut {
+		err = fmt.Errorf("%w after %v", ErrFileTimeout, p.config.FileTimeout)
+		p.handlePartialDownload(ctx, filePath, filename, "Timed out download")
+	}
+	if err != nil {
+		result.Error = fmt.Errorf("download failed for %s: %w", filename, err)
+		if logger != nil {
+			logger.ErrorWithContext(ctx, result.Error.Error())
+		}
+		p.recordFailure(runreport.OperationDownload, job.zoomEmail, job.boxEmail, recording, recordingFile, "", result.Error)
+		return result.Error
+	}
+
+	// Zoom occasionally serves zero-byte or truncated stub files; they must not reach the archive
+	if err := p.checkDownloadSize(filePath, recordingFile, job.previewBytes, downloadResult.BytesDownloaded); err != nil {
+		releaseSpace(0)
+		if removeErr := os.Remove(filePath); removeErr != nil && !os.IsNotExist(removeErr) && logger != nil {
+			logger.WarnWithContext(ctx, fmt.Sprintf("Failed to remove rejected download %s: %v", filePath, removeErr))
+		}
+		result.Error = fmt.Errorf("download rejected for %s: %w", filename, err)
+		if logger != nil {
+			logger.ErrorWithContext(ctx, result.Error.Error())
+		}
+		p.recordFailure(runreport.OperationDownload, job.zoomEmail, job.boxEmail, recording, recordingFile, "", result.Error)
+		return result.Error
+	}
+
+	result.Downloaded = true
+	result.BytesDownloaded = downloadResult.BytesDownloaded
+	releaseSpace(downloadResult.BytesDownloaded)
+	if logger != nil {
+		logger.InfoWithContext(ctx, fmt.Sprintf("Downloaded: %s (%d bytes)", filename, downloadResult.BytesDownloaded))
+	}
+
+	// Checksum the downloaded file so it can be verified later
+	if p.config.ChecksumAlgorithm != "" {
+		checksum, err := download.CalculateFileChecksumWith(filePath, p.config.ChecksumAlgorithm)
+		if err != nil {
+			if logger != nil {
+				logger.WarnWithContext(ctx, fmt.Sprintf("Failed to checksum %s: %v", filename, err))
+			}
+		} else {
+			job.checksum = checksum
+			if p.config.Verbose && logger != nil {
+				logger.InfoWithContext(ctx, fmt.Sprintf("Checksum for %s: %s", filename, checksum))
+			}
+		}
+	}
+
+	// Encrypt the recording on disk once it is checksummed; uploads decrypt a temporary copy
+	if p.config.Encryption != nil && recordingFile.FileType == "MP4" {
+		if err := p.config.Encryption.EncryptFile(filePath); err != nil {
+			// The plaintext recording must not stay on disk when encryption is required
+			if removeErr := os.Remove(filePath); removeErr != nil && !os.IsNotExist(removeErr) && logger != nil {
+				logger.ErrorWithContext(ctx, fmt.Sprintf("Failed to remove unencrypted download %s: %v", filePath, removeErr))
+			}
+			result.Error = fmt.Errorf("failed to encrypt %s: %w", filename, err)
+			if logger != nil {
+				logger.ErrorWithContext(ctx, result.Error.Error())
+			}
+			return result.Error
+		}
+	}
+
+	// Fetch the poster image for video files if Zoom exposes one
+	if p.config.Thumbnails && recordingFile.FileType == "MP4" {
+		job.thumbnailPath = p.downloadThumbnail(ctx, recording, recordingFile, filePath, headers)
+	}
+
+	// Convert WebVTT transcripts and captions for platforms that only accept SRT or text
+	if len(p.config.TranscriptFormats) > 0 && isTranscript(recordingFile) {
+		job.transcriptPaths = p.convertTranscript(ctx, filePath, filename)
+	}
+
+	// Upload to the destination if enabled
+	if !p.config.BoxEnabled || p.destination == nil {
+		return pipeline.ErrStop
+	}
+	return nil
+}
+
+// uploadStage uploads a downloaded recording file with its metadata and thumbnail, then tracks it
+func (p *userProcessorImpl) uploadStage(ctx context.Context, job *fileJob) error {
+	result := job.result
+	logger := logging.GetDefaultLogger()
+	recording, recordingFile, filename, filePath, meetingTime := job.recording, job.recordingFile, job.filename, job.filePath, job.meetingTime
+	zoomEmail, boxEmail := job.zoomEmail, job.boxEmail
+
+	var thumbnailFilename string
+	if job.thumbnailPath != "" {
+		thumbnailFilename = filepath.Base(job.thumbnailPath)
+	}
+
+	// Save the metadata file next to MP4 recordings if it doesn't exist yet
+	if recordingFile.FileType == "MP4" && p.metadataSerializer != nil {
+		metadataFilename := strings.TrimSuffix(filename, filepath.Ext(filename)) + p.metadataSerializer.Extension()
+		job.metadataPath = filepath.Join(filepath.Dir(filePath), metadataFilename)
+
+		if _, err := os.Stat(job.metadataPath); os.IsNotExist(err) {
+			details := p.lookupMeetingDetails(ctx, recording)
+			if err := saveRecordingMetadata(ctx, p.metadataSerializer, recording, &recordingFile, job.checksum, thumbnailFilename, details, job.metadataPath); err != nil {
+				if logger != nil {
+					logger.ErrorWithContext(ctx, fmt.Sprintf("Failed to save metadata %s: %v", metadataFilename, err))
+				}
+				// Don't fail the entire operation if metadata save fails
+			}
+		}
+	}
+
+	// Recordings encrypted at rest are uploaded from a decrypted temporary copy
+	uploadPath, err := p.plaintextPath(job)
+	if err != nil {
+		result.Error = fmt.Errorf("failed to decrypt %s for upload: %w", filename, err)
+		if logger != nil {
+			logger.ErrorWithContext(ctx, result.Error.Error())
+		}
+		p.recordFailure(runreport.OperationUpload, zoomEmail, boxEmail, recording, recordingFile, filePath, result.Error)
+		return result.Error
+	}
+
+	// With metadata-first ordering the sidecar must land before the recording, so a failed
+	// metadata upload fails the file and the recording is left for a later run
+	metadataFirst := p.config.MetadataOrder == MetadataBeforeRecording && job.metadataPath != ""
+	if metadataFirst {
+		if _, err := os.Stat(job.metadataPath); err != nil {
+			result.Error = fmt.Errorf("metadata for %s is missing: %w", filename, err)
+			p.recordFailure(runreport.OperationUpload, zoomEmail, boxEmail, recording, recordingFile, filePath, result.Error)
+			return result.Error
+		}
+		upload, err := p.uploadMetadataFile(ctx, job.metadataPath, zoomEmail, boxEmail, meetingTime)
+		if err != nil {
+			result.Error = fmt.Errorf("metadata upload failed for %s: %w", filename, err)
+			p.recordFailure(runreport.OperationUpload, zoomEmail, boxEmail, recording, recordingFile, filePath, result.Error)
+			return result.Error
+		}
+		job.metadataUpload = upload
+	}
+
+	// Upload the main file WITHOUT tracking yet (we'll track after we know the total time);
+	// a streamed file is already in the destination
+	uploadResult := job.upload
+	var uploadErr error
+	if !job.streamed {
+		var uploadProgress storage.ProgressFunc
+		if p.config.Progress != nil {
+			if info, err := os.Stat(uploadPath); err == nil {
+				p.config.Progress.StartTransfer("upload", filename, info.Size())
+			}
+			uploadProgress = p.config.Progress.Update
+		}
+		uploadStart := time.Now()
+		uploadResult, uploadErr = p.uploadToDestination(ctx, uploadPath, zoomEmail, boxEmail, meetingTime, uploadProgress)
+		result.UploadDuration = time.Since(uploadStart)
+		if p.config.Progress != nil {
+			p.config.Progress.EndTransfer()
+		}
+	}
+
+	// Calculate processing time AFTER the main file upload completes
+	// This captures only the download + upload time for the main recording file (excluding metadata operations)
+	processingTime := time.Since(job.startedAt)
+
+	if uploadErr != nil {
+		result.Error = uploadErr
+		p.recordFailure(runreport.OperationUpload, zoomEmail, boxEmail, recording, recordingFile, filePath, uploadErr)
+		// Don't delete file if upload failed
+		return result.Error
+	}
+	job.upload = uploadResult
+
+	if uploadResult.Skipped {
+		result.Skipped = true
+	} else {
+		result.Uploaded = true
+		if job.streamed {
+			result.BytesUploaded = recordingFile.FileSize
+		} else if info, err := os.Stat(uploadPath); err == nil {
+			result.BytesUploaded = info.Size()
+		}
+	}
+
+	p.applyMetadataTemplate(ctx, uploadResult, job)
+
+	// Share MP4 recordings so people can be sent a link to them; the link goes into the metadata
+	// unless the metadata is already in the destination
+	var sharedLink string
+	if recordingFile.FileType == "MP4" {
+		sharedLink = p.createSharedLink(ctx, uploadResult, filename)
+		if sharedLink != "" && !metadataFirst && job.metadataPath != "" {
+			if err := addSharedLinkToMetadata(p.metadataSerializer, job.metadataPath, sharedLink, p.config.SharedLinkAccess); err != nil && logger != nil {
+				logger.WarnWithContext(ctx, fmt.Sprintf("Failed to add shared link to metadata %s: %v", filepath.Base(job.metadataPath), err))
+			}
+		}
+	}
+
+	// Upload metadata file after the recording (default ordering)
+	if !metadataFirst && job.metadataPath != "" {
+		// Check if metadata file exists before uploading
+		if _, err := os.Stat(job.metadataPath); err == nil {
+			if upload, err := p.uploadMetadataFile(ctx, job.metadataPath, zoomEmail, boxEmail, meetingTime); err == nil {
+				job.metadataUpload = upload
+			}
+			// Don't fail the entire operation if metadata upload fails
+		}
+	}
+
+	// Now track the upload with the accurate processing time; with metadata-first ordering
+	// both files are in the destination at this point
+	p.trackUpload(tracking.UploadEntry{
+		ZoomUser:       zoomEmail,
+		FileName:       filename,
+		RecordingSize:  recordingFile.FileSize,
+		UploadDate:     timefmt.Now(),
+		ProcessingTime: processingTime,
+		SharedLink:     sharedLink,
+	})
+
+	// Upload the thumbnail next to the recording
+	if job.thumbnailPath != "" {
+		p.uploadThumbnail(ctx, job.thumbnailPath, zoomEmail, boxEmail, meetingTime)
+	}
+
+	// Upload converted transcripts next to the original
+	for _, sidecarPath := range job.transcriptPaths {
+		p.uploadTranscriptSidecar(ctx, sidecarPath, zoomEmail, boxEmail, meetingTime)
+	}
+
+	if result.Uploaded && p.config.Hooks != nil {
+		event := hooks.Event{
+			Event:       hooks.EventUpload,
+			User:        zoomEmail,
+			BoxUser:     boxEmail,
+			File:        filename,
+			FileID:      uploadResult.FileID,
+			Size:        result.BytesUploaded,
+			Topic:       recording.Topic,
+			MeetingUUID: recording.UUID,
+			SharedLink:  sharedLink,
+		}
+		if !job.streamed {
+			event.Path = filePath
+		}
+		if folderPath, err := p.directoryLayout.FolderPath(email.ExtractUsername(boxEmail), meetingTime); err == nil {
+			event.Folder = folderPath
+		}
+		p.config.Hooks.Fire(ctx, event)
+	}
+
+	return nil
+}
+
+// plaintextPath returns the file to upload for job: the recording itself, or for recordings
+// encrypted at rest a decrypted copy in a private temporary directory
+func (p *userProcessorImpl) plaintextPath(job *fileJob) (string, error) {
+	if job.plainPath != "" {
+		return job.plainPath, nil
+	}
+	_, encrypted, err := atrest.Inspect(job.filePath)
+	if os.IsNotExist(err) {
+		// Reported by the upload itself
+		return job.filePath, nil
+	}
+	if err != nil || !encrypted {
+		return job.filePath, err
+	}
+	if p.config.Encryption == nil {
+		return "", atrest.ErrNoKey
+	}
+
+	dir, err := os.MkdirTemp(p.config.EncryptionTempDir, "zoom-to-box-upload-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create directory for the decrypted copy: %w", err)
+	}
+	plainPath := filepath.Join(dir, filepath.Base(job.filePath))
+	if err := atrest.DecryptFile(p.config.Encryption, job.filePath, plainPath); err != nil {
+		os.RemoveAll(dir)
+		return "", err
+	}
+	job.plainPath = plainPath
+	return plainPath, nil
+}
+
+// verifyStage deletes the local recording and metadata once the destination has them (if configured)
+func (p *userProcessorImpl) verifyStage(ctx context.Context, job *fileJob) error {
+	if !p.config.DeleteAfterUpload {
+		return nil
+	}
+	logger := logging.GetDefaultLogger()
+
+	// Delete metadata file after successful upload or if already in Box
+	if job.metadataUpload != nil && p.deletionVerified(ctx, job.metadataUpload, job.metadataPath) {
+		if err := os.Remove(job.metadataPath); err != nil {
+			if logger != nil {
+				logger.ErrorWithContext(ctx, fmt.Sprintf("Failed to delete metadata after upload: %s - %v", job.metadataPath, err))
+			}
+		} else if logger != nil {
+			logger.InfoWithContext(ctx, fmt.Sprintf("Deleted local metadata after upload: %s", filepath.Base(job.metadataPath)))
+		}
+	}
+
+	// Delete local file after successful upload or if it was skipped (already in Box)
+	verifyPath := job.filePath
+	if job.plainPath != "" {
+		verifyPath = job.plainPath
+	}
+	if !job.streamed && job.upload != nil && (job.upload.Uploaded || job.upload.Skipped) && p.deletionVerified(ctx, job.upload, verifyPath) {
+		var size int64
+		if info, err := os.Stat(job.filePath); err == nil {
+			size = info.Size()
+		}
+		if err := os.Remove(job.filePath); err != nil {
+			if logger != nil {
+				logger.ErrorWithContext(ctx, fmt.Sprintf("Failed to delete file after upload: %s - %v", job.filePath, err))
+			}
+		} else {
+			job.result.Deleted = true
+			p.addUsage(email.ExtractUsername(job.boxEmail), -size)
+			if logger != nil {
+				logger.InfoWithContext(ctx, fmt.Sprintf("Deleted local file after upload: %s", job.filename))
+			}
+		}
+	}
+
+	return nil
+}
+
+// windowsPathReserve is the room kept in the path budget besides the extension: a -99
+// collision suffix and the ".<name>.resume" file of a partial download
+const windowsPathReserve = 12
+
+// maxPathLength returns the longest local file path with WindowsSafePaths
+func (p *userProcessorImpl) maxPathLength() int {
+	if p.config.MaxPathLength > 0 {
+		return p.config.MaxPathLength
+	}
+	return directory.WindowsMaxPath
+}
+
+// longPath returns the Windows long path form of a local path with WindowsSafePaths
+func (p *userProcessorImpl) longPath(path string) string {
+	if !p.config.WindowsSafePaths {
+		return path
+	}
+	return directory.LongPath(path)
+}
@@ -0,0 +1,160 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/curtbushko/zoom-to-box/internal/directory"
+	"github.com/curtbushko/zoom-to-box/internal/filename"
+	"github.com/curtbushko/zoom-to-box/internal/pipeline"
+	"github.com/curtbushko/zoom-to-box/internal/zoom"
+)
+
+func newStageTestProcessor(t *testing.T, config ProcessorConfig) *userProcessorImpl {
+	t.Helper()
+	if config.BaseDownloadDir == "" {
+		config.BaseDownloadDir = t.TempDir()
+	}
+	return NewUserProcessor(
+		newMockZoomClient(),
+		newMockDownloadManager(),
+		nil,
+		filename.NewFileSanitizer(filename.FileSanitizerOptions{}),
+		nil,
+		config,
+	).(*userProcessorImpl)
+}
+
+func newStageTestJob() *fileJob {
+	return &fileJob{
+		zoomEmail: "jane.smith@example.com",
+		boxEmail:  "jane.smith@example.com",
+		recording: &zoom.Recording{
+			UUID:      "stage-uuid",
+			Topic:     "Stage Meeting",
+			StartTime: time.Date(2024, 3, 1, 14, 0, 0, 0, time.UTC),
+		},
+		recordingFile: zoom.RecordingFile{ID: "file-1", FileType: "MP4", DownloadURL: "https://zoom.us/download/stage.mp4", FileSize: 1024},
+		result:        &recordingFileResult{},
+	}
+}
+
+func TestFilePipeline_Stages(t *testing.T) {
+	p := newStageTestProcessor(t, ProcessorConfig{})
+	want := []string{StagePlan, StageDownload, StageUpload, StageVerify}
+	got := p.filePipeline.Stages()
+	if len(got) != len(want) {
+		t.Fatalf("Expected stages %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Expected stages %v, got %v", want, got)
+		}
+	}
+}
+
+func TestPlanStage(t *testing.T) {
+	t.Run("resolves the local path", func(t *testing.T) {
+		p := newStageTestProcessor(t, ProcessorConfig{})
+		job := newStageTestJob()
+
+		if err := p.planStage(context.Background(), job); err != nil {
+			t.Fatalf("planStage failed: %v", err)
+		}
+		wantDir := filepath.Join(p.config.BaseDownloadDir, "jane.smith", "2024", "03", "01")
+		if filepath.Dir(job.filePath) != wantDir || job.filename == "" {
+			t.Errorf("Expected a file in %s, got %q", wantDir, job.filePath)
+		}
+	})
+
+	t.Run("uses the directory layout", func(t *testing.T) {
+		layout, err := directory.NewLayout("{{.Year}}/{{.Month}}/{{.User}}")
+		if err != nil {
+			t.Fatalf("NewLayout failed: %v", err)
+		}
+		p := newStageTestProcessor(t, ProcessorConfig{DirectoryLayout: layout})
+		job := newStageTestJob()
+
+		if err := p.planStage(context.Background(), job); err != nil {
+			t.Fatalf("planStage failed: %v", err)
+		}
+		wantDir := filepath.Join(p.config.BaseDownloadDir, "2024", "03", "jane.smith")
+		if filepath.Dir(job.filePath) != wantDir {
+			t.Errorf("Expected a file in %s, got %q", wantDir, job.filePath)
+		}
+	})
+
+	t.Run("stops for a file already on disk", func(t *testing.T) {
+		p := newStageTestProcessor(t, ProcessorConfig{})
+		job := newStageTestJob()
+		if err := p.planStage(context.Background(), job); err != nil {
+			t.Fatalf("planStage failed: %v", err)
+		}
+		if err := os.WriteFile(job.filePath, []byte("existing"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		again := newStageTestJob()
+		if err := p.planStage(context.Background(), again); !errors.Is(err, pipeline.ErrStop) {
+			t.Fatalf("Expected ErrStop, got %v", err)
+		}
+		if !again.result.Skipped {
+			t.Error("Expected the file to be skipped")
+		}
+	})
+
+	t.Run("stops a dry run with the planned size", func(t *testing.T) {
+		p := newStageTestProcessor(t, ProcessorConfig{DryRun: true})
+		job := newStageTestJob()
+
+		if err := p.planStage(context.Background(), job); !errors.Is(err, pipeline.ErrStop) {
+			t.Fatalf("Expected ErrStop, got %v", err)
+		}
+		if job.result.BytesPlanned != 1024 {
+			t.Errorf("Expected 1024 planned bytes, got %d", job.result.BytesPlanned)
+		}
+	})
+}
+
+func TestVerifyStage(t *testing.T) {
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, "stage.mp4")
+	if err := os.WriteFile(filePath, []byte("content"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		config      ProcessorConfig
+		upload      *uploadResult
+		wantDeleted bool
+	}{
+		{name: "keeps the file without delete_after_upload", upload: &uploadResult{Uploaded: true}},
+		{name: "keeps a file that was not uploaded", config: ProcessorConfig{DeleteAfterUpload: true}},
+		{name: "deletes an uploaded file", config: ProcessorConfig{DeleteAfterUpload: true}, upload: &uploadResult{Uploaded: true}, wantDeleted: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newStageTestProcessor(t, tt.config)
+			job := newStageTestJob()
+			job.filePath = filePath
+			job.filename = filepath.Base(filePath)
+			job.upload = tt.upload
+
+			if err := p.verifyStage(context.Background(), job); err != nil {
+				t.Fatalf("verifyStage failed: %v", err)
+			}
+			if job.result.Deleted != tt.wantDeleted {
+				t.Errorf("Expected deleted=%v, got %v", tt.wantDeleted, job.result.Deleted)
+			}
+			if _, err := os.Stat(filePath); os.IsNotExist(err) != tt.wantDeleted {
+				t.Errorf("Expected file removed=%v, stat error: %v", tt.wantDeleted, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package processor
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// TransferStats summarizes the speed of a run's downloads and uploads
+// Speeds are in MB/s with binary megabytes, like the progress display.
+type TransferStats struct {
+	Files           int     `json:"files"` // Files downloaded or uploaded
+	BytesDownloaded int64   `json:"bytes_downloaded"`
+	BytesUploaded   int64   `json:"bytes_uploaded"`
+	DownloadSeconds float64 `json:"download_seconds"` // Time spent downloading, summed over files
+	UploadSeconds   float64 `json:"upload_seconds"`   // Time spent uploading, summed over files
+	DownloadMBps    float64 `json:"download_mbps"`    // Average speed of a download
+	UploadMBps      float64 `json:"upload_mbps"`      // Average speed of an upload
+	ThroughputMBps  float64 `json:"throughput_mbps"`  // Bytes downloaded and uploaded per second of the run
+
+	FileSecondsP50 float64 `json:"file_seconds_p50"` // Median transfer time of a file, download and upload together
+	FileSecondsP95 float64 `json:"file_seconds_p95"`
+	FileSecondsMax float64 `json:"file_seconds_max"`
+}
+
+// NewTransferStats computes the transfer statistics of users' files for a run that took
+// runDuration; it returns nil when no file was transferred
+func NewTransferStats(users []UserReport, runDuration time.Duration) *TransferStats {
+	stats := &TransferStats{}
+	var fileSeconds []float64
+	for _, user := range users {
+		for _, file := range user.Files {
+			if file.DownloadSeconds == 0 && file.UploadSeconds == 0 {
+				continue
+			}
+			stats.Files++
+			stats.BytesDownloaded += file.BytesDownloaded
+			stats.BytesUploaded += file.BytesUploaded
+			stats.DownloadSeconds += file.DownloadSeconds
+			stats.UploadSeconds += file.UploadSeconds
+			fileSeconds = append(fileSeconds, file.DownloadSeconds+file.UploadSeconds)
+		}
+	}
+	if stats.Files == 0 {
+		return nil
+	}
+
+	stats.DownloadMBps = mbps(stats.BytesDownloaded, stats.DownloadSeconds)
+	stats.UploadMBps = mbps(stats.BytesUploaded, stats.UploadSeconds)
+	stats.ThroughputMBps = mbps(stats.BytesDownloaded+stats.BytesUploaded, runDuration.Seconds())
+
+	sort.Float64s(fileSeconds)
+	stats.FileSecondsP50 = percentile(fileSeconds, 50)
+	stats.FileSecondsP95 = percentile(fileSeconds, 95)
+	stats.FileSecondsMax = fileSeconds[len(fileSeconds)-1]
+	return stats
+}
+
+// Lines renders the statistics for the end-of-run summary
+func (s *TransferStats) Lines() []string {
+	lines := []string{
+		fmt.Sprintf("Files transferred: %d, %.1f MB/s overall", s.Files, s.ThroughputMBps),
+	}
+	if s.BytesDownloaded > 0 {
+		lines = append(lines, fmt.Sprintf("Downloads: %.1f MB in %.1fs, %.1f MB/s", toMB(s.BytesDownloaded), s.DownloadSeconds, s.DownloadMBps))
+	}
+	if s.BytesUploaded > 0 {
+		lines = append(lines, fmt.Sprintf("Uploads: %.1f MB in %.1fs, %.1f MB/s", toMB(s.BytesUploaded), s.UploadSeconds, s.UploadMBps))
+	}
+	lines = append(lines, fmt.Sprintf("File transfer time: p50 %.1fs, p95 %.1fs, max %.1fs", s.FileSecondsP50, s.FileSecondsP95, s.FileSecondsMax))
+	return lines
+}
+
+// mbps returns the speed of transferring n bytes in seconds, rounded to two decimals
+func mbps(n int64, seconds float64) float64 {
+	if n <= 0 || seconds <= 0 {
+		return 0
+	}
+	return math.Round(toMB(n)/seconds*100) / 100
+}
+
+// toMB converts a byte count to binary megabytes
+func toMB(n int64) float64 {
+	return float64(n) / (1 << 20)
+}
+
+// percentile returns the nearest-rank p-th percentile of sorted values
+func percentile(sorted []float64, p float64) float64 {
+	rank := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return sorted[rank]
+}
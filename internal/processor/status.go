@@ -0,0 +1,154 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/curtbushko/zoom-to-box/internal/download"
+	"github.com/curtbushko/zoom-to-box/internal/logging"
+	"github.com/curtbushko/zoom-to-box/internal/storage"
+	"github.com/curtbushko/zoom-to-box/internal/zoom"
+)
+
+// fileStatusID identifies job's file in the status tracker, like the ID of its download request
+// Previews are tracked apart from the full file, so a later full migration still transfers it.
+func fileStatusID(job *fileJob) string {
+	id := recordingFileStatusID(job.recording.UUID, job.recordingFile.ID)
+	if job.previewBytes > 0 {
+		id += "-preview"
+	}
+	return id
+}
+
+// recordingFileStatusID identifies a full recording file in the status tracker
+func recordingFileStatusID(meetingUUID, fileID string) string {
+	return fmt.Sprintf("%s-%s", meetingUUID, fileID)
+}
+
+// completedEarlier reports whether the status tracker records job's file as uploaded by an
+// earlier run to the folder the user's uploads now go to, so the destination need not be
+// checked again
+func (p *userProcessorImpl) completedEarlier(job *fileJob) bool {
+	if p.config.StatusTracker == nil || !p.config.BoxEnabled || p.destination == nil {
+		return false
+	}
+	return p.uploadedEarlier(fileStatusID(job), job.boxEmail)
+}
+
+// uploadedEarlier reports whether the status tracker records the file id as uploaded to the
+// folder userEmail's uploads now go to
+func (p *userProcessorImpl) uploadedEarlier(id, userEmail string) bool {
+	p.statusMu.Lock()
+	entry, ok := p.config.StatusTracker.GetDownloadStatus(id)
+	p.statusMu.Unlock()
+	return ok && entry.Status == download.StatusCompleted && entry.Box != nil && entry.Box.Uploaded &&
+		entry.Box.Target == p.uploadTarget(userEmail)
+}
+
+// uploadTarget identifies the destination and folder that userEmail's uploads go to
+func (p *userProcessorImpl) uploadTarget(userEmail string) string {
+	if identifier, ok := p.destination.(storage.TargetIdentifier); ok {
+		return identifier.UploadTarget(userEmail)
+	}
+	return p.destination.Name()
+}
+
+// allUploadedEarlier reports whether the status tracker records every file the run would process
+// for userEmail as uploaded to their current folder, so it need not be looked up at all
+func (p *userProcessorImpl) allUploadedEarlier(userEmail string, recordings []*zoom.Recording) bool {
+	if p.config.StatusTracker == nil || p.config.PreviewMinutes > 0 {
+		return false
+	}
+
+	files := 0
+	for _, recording := range recordings {
+		if p.config.Filter.SkipReason(recording) != "" {
+			continue
+		}
+		for _, recordingFile := range recording.RecordingFiles {
+			if recordingFile.DownloadURL == "" || !p.includesFileType(recordingFile) || p.config.Filter.SkipFileReason(recordingFile) != "" {
+				continue
+			}
+			if !p.uploadedEarlier(recordingFileStatusID(recording.UUID, recordingFile.ID), userEmail) {
+				return false
+			}
+			files++
+		}
+	}
+	return files > 0
+}
+
+// recordFileStatus saves what the pipeline did with job's file in the status tracker
+// Downloaded files are recorded as completed, with their upload outcome when uploads are
+// enabled; failed uploads can then be retried with 'zoom-to-box retry-uploads'.
+func (p *userProcessorImpl) recordFileStatus(ctx context.Context, job *fileJob) {
+	if p.config.StatusTracker == nil || p.config.DryRun || job.filePath == "" {
+		return
+	}
+	result := job.result
+	uploading := p.config.BoxEnabled && p.destination != nil
+	if !result.Downloaded && !result.Uploaded && !job.inDestination {
+		return
+	}
+
+	id := fileStatusID(job)
+	now := time.Now().UTC()
+
+	p.statusMu.Lock()
+	defer p.statusMu.Unlock()
+
+	entry, _ := p.config.StatusTracker.GetDownloadStatus(id)
+	entry.Status = download.StatusCompleted
+	entry.FilePath = job.filePath
+	if relPath, err := filepath.Rel(p.config.BaseDownloadDir, job.filePath); err == nil && filepath.IsLocal(relPath) {
+		entry.FilePath = relPath
+	}
+	entry.FileSize = job.recordingFile.FileSize
+	if result.BytesDownloaded > 0 {
+		entry.DownloadedSize = result.BytesDownloaded
+	}
+	if job.checksum != "" {
+		entry.Checksum = job.checksum
+	}
+	entry.LastAttempt = now
+	entry.CompletedTime = now
+	entry.VideoOwner = job.zoomEmail
+	entry.BoxUser = job.boxEmail
+	entry.Error = ""
+	entry.Metadata = map[string]interface{}{
+		"meeting_id":    job.recording.UUID,
+		"meeting_topic": job.recording.Topic,
+		"file_type":     job.recordingFile.FileType,
+		"filename":      job.filename,
+	}
+
+	if uploading {
+		if entry.Box == nil {
+			entry.Box = &download.BoxUploadInfo{}
+		}
+		entry.Box.LastUploadAttempt = now
+		switch {
+		case result.Uploaded || job.inDestination:
+			entry.Box.Uploaded = true
+			entry.Box.UploadDate = now
+			entry.Box.UploadError = ""
+			if job.upload != nil && job.upload.FileID != "" {
+				entry.Box.FileID = job.upload.FileID
+			}
+			entry.Box.Verified = job.upload != nil && job.upload.Verified
+			entry.Box.Target = p.uploadTarget(job.boxEmail)
+		case result.Error != nil:
+			entry.Box.Uploaded = false
+			entry.Box.UploadError = result.Error.Error()
+			entry.Box.UploadRetries++
+		}
+	}
+
+	if err := p.config.StatusTracker.UpdateDownloadStatus(id, entry); err != nil {
+		if logger := logging.GetDefaultLogger(); logger != nil {
+			logger.WarnWithContext(ctx, fmt.Sprintf("Failed to record the status of %s: %v", job.filename, err))
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/curtbushko/zoom-to-box/internal/directory"
+	"github.com/curtbushko/zoom-to-box/internal/download"
+	"github.com/curtbushko/zoom-to-box/internal/filename"
+	"github.com/curtbushko/zoom-to-box/internal/storage"
+	"github.com/curtbushko/zoom-to-box/internal/users"
+	"github.com/curtbushko/zoom-to-box/internal/zoom"
+)
+
+func TestUserProcessor_ResumesFromStatusTracker(t *testing.T) {
+	tmpDir := t.TempDir()
+	statusTracker, err := download.NewStatusTracker(filepath.Join(tmpDir, ".status.json"))
+	if err != nil {
+		t.Fatalf("NewStatusTracker failed: %v", err)
+	}
+	defer statusTracker.Close()
+
+	zoomClient := newMockZoomClient()
+	zoomClient.recordings["jane.smith@example.com"] = []*zoom.Recording{
+		{
+			UUID:      "uuid-resume",
+			Topic:     "Resumed Meeting",
+			StartTime: time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC),
+			RecordingFiles: []zoom.RecordingFile{
+				{ID: "file-resume", FileType: "MP4", DownloadURL: "https://zoom.us/download/resume.mp4", FileSize: 1024},
+			},
+			DownloadAccessToken: "test-token",
+		},
+	}
+
+	run := func(downloadManager *mockDownloadManager, boxClient *mockBoxClient) *ProcessorResult {
+		userManager, _ := users.NewActiveUserManager(users.ActiveUserConfig{})
+		processor := NewUserProcessor(
+			zoomClient,
+			downloadManager,
+			directory.NewDirectoryManager(directory.DirectoryConfig{BaseDirectory: tmpDir, CreateDirs: true}, userManager),
+			filename.NewFileSanitizer(filename.FileSanitizerOptions{}),
+			newMockUploadManager(boxClient),
+			ProcessorConfig{
+				BaseDownloadDir:   tmpDir,
+				BoxEnabled:        true,
+				DeleteAfterUpload: true,
+				StatusTracker:     statusTracker,
+			},
+		)
+		result, err := processor.ProcessUser(context.Background(), "jane.smith@example.com", "jane.smith@example.com")
+		if err != nil {
+			t.Fatalf("ProcessUser failed: %v", err)
+		}
+		return result
+	}
+
+	result := run(newMockDownloadManager(), newMockBoxClient())
+	if result.UploadedCount != 1 {
+		t.Fatalf("Expected the first run to upload the file, got %d uploads", result.UploadedCount)
+	}
+	entry, ok := statusTracker.GetDownloadStatus("uuid-resume-file-resume")
+	if !ok || entry.Status != download.StatusCompleted || entry.Box == nil || !entry.Box.Uploaded {
+		t.Fatalf("Expected the upload recorded in the status tracker, got %+v", entry)
+	}
+	if filepath.IsAbs(entry.FilePath) {
+		t.Errorf("Expected a file path relative to the output directory, got %s", entry.FilePath)
+	}
+
+	// A Box lookup would now fail, so the file must be skipped from the status alone
+	boxClient := newMockBoxClient()
+	boxClient.findFileError = errors.New("box unavailable")
+	downloadManager := newMockDownloadManager()
+	result = run(downloadManager, boxClient)
+	if result.SkippedCount != 1 || result.ErrorCount != 0 || len(downloadManager.downloadAttempted) != 0 {
+		t.Errorf("Expected the completed file skipped without Box checks, got %d skipped, %d errors, %d downloads",
+			result.SkippedCount, result.ErrorCount, len(downloadManager.downloadAttempted))
+	}
+}
+
+// relocatedDestination uploads like its destination but names another upload target, like a
+// destination whose folder was changed in the config
+type relocatedDestination struct {
+	storage.UploadDestination
+	target string
+}
+
+func (d relocatedDestination) UploadTarget(userEmail string) string {
+	return d.target
+}
+
+func TestUserProcessor_UploadsAgainAfterDestinationChange(t *testing.T) {
+	tmpDir := t.TempDir()
+	statusTracker, err := download.NewStatusTracker(filepath.Join(tmpDir, ".status.json"))
+	if err != nil {
+		t.Fatalf("NewStatusTracker failed: %v", err)
+	}
+	defer statusTracker.Close()
+
+	zoomClient := newMockZoomClient()
+	zoomClient.recordings["jane.smith@example.com"] = []*zoom.Recording{
+		{
+			UUID:      "uuid-moved",
+			Topic:     "Moved Meeting",
+			StartTime: time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC),
+			RecordingFiles: []zoom.RecordingFile{
+				{ID: "file-moved", FileType: "MP4", DownloadURL: "https://zoom.us/download/moved.mp4", FileSize: 1024},
+			},
+			DownloadAccessToken: "test-token",
+		},
+	}
+
+	run := func(target string) (*ProcessorResult, *mockDownloadManager) {
+		destination := relocatedDestination{storage.NewBoxDestination(newMockUploadManager(newMockBoxClient())), target}
+		downloadManager := newMockDownloadManager()
+		processor := NewUserProcessorWithDestination(
+			zoomClient,
+			downloadManager,
+			nil,
+			filename.NewFileSanitizer(filename.FileSanitizerOptions{}),
+			destination,
+			ProcessorConfig{BaseDownloadDir: tmpDir, BoxEnabled: true, DeleteAfterUpload: true, StatusTracker: statusTracker},
+		)
+		result, err := processor.ProcessUser(context.Background(), "jane.smith@example.com", "jane.smith@example.com")
+		if err != nil {
+			t.Fatalf("ProcessUser failed: %v", err)
+		}
+		return result, downloadManager
+	}
+
+	if result, _ := run("box:zoom"); result.UploadedCount != 1 {
+		t.Fatalf("Expected the first run to upload the file, got %d uploads", result.UploadedCount)
+	}
+
+	// The user's uploads now go to a team folder, which does not have the file yet
+	result, downloadManager := run("box:folder/98765")
+	if result.UploadedCount != 1 || len(downloadManager.downloadAttempted) != 1 {
+		t.Errorf("Expected the file uploaded to the new folder, got %d uploads and %d downloads",
+			result.UploadedCount, len(downloadManager.downloadAttempted))
+	}
+	entry, _ := statusTracker.GetDownloadStatus("uuid-moved-file-moved")
+	if entry.Box == nil || entry.Box.Target != "box:folder/98765" {
+		t.Errorf("Expected the upload recorded for the new folder, got %+v", entry.Box)
+	}
+}
+
+func TestUserProcessor_SkipsUploadedUsersWithoutDestinationLookups(t *testing.T) {
+	tmpDir := t.TempDir()
+	statusTracker, err := download.NewStatusTracker(filepath.Join(tmpDir, ".status.json"))
+	if err != nil {
+		t.Fatalf("NewStatusTracker failed: %v", err)
+	}
+	defer statusTracker.Close()
+
+	zoomClient := newMockZoomClient()
+	zoomClient.recordings["jane.smith@example.com"] = []*zoom.Recording{
+		{
+			UUID:      "uuid-indexed",
+			Topic:     "Indexed Meeting",
+			StartTime: time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC),
+			RecordingFiles: []zoom.RecordingFile{
+				{ID: "file-indexed", FileType: "MP4", DownloadURL: "https://zoom.us/download/indexed.mp4", FileSize: 1024},
+			},
+			DownloadAccessToken: "test-token",
+		},
+	}
+
+	run := func(boxClient *mockBoxClient) *ProcessorResult {
+		processor := NewUserProcessor(
+			zoomClient,
+			newMockDownloadManager(),
+			nil,
+			filename.NewFileSanitizer(filename.FileSanitizerOptions{}),
+			newMockUploadManager(boxClient),
+			ProcessorConfig{BaseDownloadDir: tmpDir, BoxEnabled: true, DeleteAfterUpload: true, StatusTracker: statusTracker},
+		)
+		result, err := processor.ProcessUser(context.Background(), "jane.smith@example.com", "jane.smith@example.com")
+		if err != nil {
+			t.Fatalf("ProcessUser failed: %v", err)
+		}
+		return result
+	}
+
+	if result := run(newMockBoxClient()); result.UploadedCount != 1 {
+		t.Fatalf("Expected the first run to upload the file, got %d uploads", result.UploadedCount)
+	}
+	if entry, ok := statusTracker.GetDownloadStatus("uuid-indexed-file-indexed"); !ok || entry.Box == nil || !entry.Box.Uploaded {
+		t.Fatalf("Expected the upload recorded in the status tracker, got %+v", entry)
+	}
+
+	// Looking up the user's zoom folder would now fail the user
+	boxClient := newMockBoxClient()
+	boxClient.findZoomFolderError = errors.New("box unavailable")
+	result := run(boxClient)
+	if result.SkippedCount != 1 || result.ErrorCount != 0 {
+		t.Errorf("Expected the uploaded file skipped without Box lookups, got %d skipped and %d errors: %v",
+			result.SkippedCount, result.ErrorCount, result.Errors)
+	}
+}
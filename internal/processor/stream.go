@@ -0,0 +1,181 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/curtbushko/zoom-to-box/internal/download"
+	"github.com/curtbushko/zoom-to-box/internal/email"
+	"github.com/curtbushko/zoom-to-box/internal/logging"
+	"github.com/curtbushko/zoom-to-box/internal/runreport"
+	"github.com/curtbushko/zoom-to-box/internal/storage"
+)
+
+// streamUploader returns the destination's stream uploader when job's file is to be streamed:
+// stream mode is on, the Zoom client can stream files and the file is a full MP4 that is large
+// enough for the destination to stream.
+// With metadata-first ordering the metadata must land before the recording, so those files are
+// downloaded as usual.
+func (p *userProcessorImpl) streamUploader(job *fileJob) (storage.StreamUploader, bool) {
+	if !p.config.Stream || !p.config.BoxEnabled || p.destination == nil {
+		return nil, false
+	}
+	if job.recordingFile.FileType != "MP4" || job.previewBytes > 0 || p.config.MetadataOrder == MetadataBeforeRecording {
+		return nil, false
+	}
+	if _, ok := p.zoomClient.(RecordingFileStreamer); !ok {
+		return nil, false
+	}
+	uploader, ok := p.destination.(storage.StreamUploader)
+	if !ok || job.recordingFile.FileSize < uploader.MinStreamSize() {
+		return nil, false
+	}
+	return uploader, true
+}
+
+// streamFile pipes job's file from Zoom into the destination without writing it to disk
+// It returns false without an error when the destination needs a local copy after all, in which
+// case the file is downloaded instead.
+func (p *userProcessorImpl) streamFile(ctx context.Context, job *fileJob, uploader storage.StreamUploader) (bool, error) {
+	result := job.result
+	logger := logging.GetDefaultLogger()
+	recording, recordingFile, filename := job.recording, job.recordingFile, job.filename
+
+	folderPath, err := p.directoryLayout.FolderPath(email.ExtractUsername(job.boxEmail), job.meetingTime)
+	if err != nil {
+		result.Error = err
+		return true, result.Error
+	}
+
+	var progressFunc storage.ProgressFunc
+	reportTransfer := p.config.Progress != nil && p.downloadConcurrency() == 1
+	if reportTransfer {
+		p.config.Progress.StartTransfer("stream", filename, recordingFile.FileSize)
+		progressFunc = p.config.Progress.Update
+	}
+
+	streamCtx, cancelStream := p.fileContext(ctx)
+	reader, writer := io.Pipe()
+	downloadDone := make(chan error, 1)
+	go func() {
+		checked := &mediaSignatureWriter{w: writer}
+		err := p.zoomClient.(RecordingFileStreamer).DownloadRecordingFile(streamCtx, recordingFile.DownloadURL, checked)
+		if err == nil {
+			err = checked.flush()
+		}
+		writer.CloseWithError(err)
+		downloadDone <- err
+	}()
+
+	streamStart := time.Now()
+	uploaded, err := uploader.UploadStream(streamCtx, storage.UploadRequest{
+		ZoomEmail:  job.zoomEmail,
+		UserEmail:  job.boxEmail,
+		FolderPath: folderPath,
+		FileName:   filename,
+		Progress:   progressFunc,
+	}, reader, recordingFile.FileSize)
+	// Stop a download the upload no longer reads, e.g. after a failed part or a skipped file
+	reader.Close()
+	downloadErr := <-downloadDone
+	// Both transfers run for the whole stream
+	result.DownloadDuration = time.Since(streamStart)
+	result.UploadDuration = result.DownloadDuration
+	fileTimedOut := errors.Is(context.Cause(streamCtx), ErrFileTimeout)
+	cancelStream()
+	if reportTransfer {
+		p.config.Progress.EndTransfer()
+	}
+
+	if errors.Is(err, storage.ErrStreamUnsupported) {
+		if logger != nil {
+			logger.InfoWithContext(ctx, fmt.Sprintf("%s cannot stream %s, downloading it instead", p.destination.Name(), filename))
+		}
+		return false, nil
+	}
+	if err != nil {
+		// A failed Zoom download reaches the upload as a read error; report the download's cause
+		operation := runreport.OperationUpload
+		if downloadErr != nil && !errors.Is(downloadErr, io.ErrClosedPipe) {
+			operation, err = runreport.OperationDownload, downloadErr
+		}
+		switch {
+		case ctx.Err() != nil:
+			result.Error = fmt.Errorf("stream of %s interrupted: %w", filename, context.Cause(ctx))
+			return true, result.Error
+		case fileTimedOut:
+			err = fmt.Errorf("%w after %v", ErrFileTimeout, p.config.FileTimeout)
+		}
+		result.Error = fmt.Errorf("stream to %s failed for %s: %w", p.destination.Name(), filename, err)
+		if logger != nil {
+			logger.ErrorWithContext(ctx, result.Error.Error())
+		}
+		p.recordFailure(operation, job.zoomEmail, job.boxEmail, recording, recordingFile, "", result.Error)
+		return true, result.Error
+	}
+
+	job.streamed = true
+	job.upload = &uploadResult{
+		Uploaded: !uploaded.Skipped,
+		Skipped:  uploaded.Skipped,
+		FileID:   uploaded.FileID,
+		Verified: uploaded.Verified,
+	}
+	if uploaded.Skipped {
+		if logger != nil {
+			logger.InfoWithContext(ctx, fmt.Sprintf("Skipped %s upload (file already exists): %s", p.destination.Name(), filename))
+		}
+	} else {
+		result.Downloaded = true
+		result.BytesDownloaded = recordingFile.FileSize
+		if logger != nil {
+			logger.InfoWithContext(ctx, fmt.Sprintf("Streamed to %s: %s (%d bytes, file ID: %s)", p.destination.Name(), filename, recordingFile.FileSize, uploaded.FileID))
+		}
+	}
+
+	// The thumbnail is small, so it is still downloaded next to where the recording would be
+	if p.config.Thumbnails {
+		job.thumbnailPath = p.downloadThumbnail(ctx, recording, recordingFile, job.filePath, job.headers)
+	}
+	return true, nil
+}
+
+// mediaSignatureWriter holds back the first bytes of a streamed MP4 until they are known to start
+// an MP4 file, so a JSON error or a web page never reaches the destination
+type mediaSignatureWriter struct {
+	w       io.Writer
+	head    []byte
+	checked bool
+}
+
+func (m *mediaSignatureWriter) Write(data []byte) (int, error) {
+	if m.checked {
+		return m.w.Write(data)
+	}
+	m.head = append(m.head, data...)
+	if len(m.head) < download.MediaSniffLength {
+		return len(data), nil
+	}
+	if err := m.flush(); err != nil {
+		return 0, err
+	}
+	return len(data), nil
+}
+
+// flush checks the bytes held back and passes them on; streams shorter than the checked length
+// are flushed once they end
+func (m *mediaSignatureWriter) flush() error {
+	if m.checked {
+		return nil
+	}
+	m.checked = true
+	if err := download.CheckMediaSignature(m.head); err != nil {
+		return err
+	}
+	_, err := m.w.Write(m.head)
+	m.head = nil
+	return err
+}
@@ -0,0 +1,33 @@
+package processor
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrUserTimeout is the cause of a user's context once ProcessorConfig.UserTimeout has passed
+var ErrUserTimeout = errors.New("user processing timeout reached")
+
+// ErrFileTimeout is returned for a download that took longer than ProcessorConfig.FileTimeout
+var ErrFileTimeout = errors.New("file download timeout reached")
+
+// userContext bounds processing a user by the configured user timeout
+func (p *userProcessorImpl) userContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if p.config.UserTimeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeoutCause(ctx, p.config.UserTimeout, ErrUserTimeout)
+}
+
+// fileContext bounds downloading a single file by the configured file timeout
+func (p *userProcessorImpl) fileContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if p.config.FileTimeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeoutCause(ctx, p.config.FileTimeout, ErrFileTimeout)
+}
+
+// userTimedOut reports whether ctx was cancelled by the user timeout rather than the run stopping
+func userTimedOut(ctx context.Context) bool {
+	return errors.Is(context.Cause(ctx), ErrUserTimeout)
+}
@@ -0,0 +1,112 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/curtbushko/zoom-to-box/internal/directory"
+	"github.com/curtbushko/zoom-to-box/internal/download"
+	"github.com/curtbushko/zoom-to-box/internal/filename"
+	"github.com/curtbushko/zoom-to-box/internal/users"
+	"github.com/curtbushko/zoom-to-box/internal/zoom"
+)
+
+// hangingDownloadManager never finishes files whose ID contains "hang" until the context ends
+type hangingDownloadManager struct{}
+
+func (m *hangingDownloadManager) Download(ctx context.Context, req download.DownloadRequest, progressCallback download.ProgressCallback) (*download.DownloadResult, error) {
+	if err := os.MkdirAll(filepath.Dir(req.Destination), 0755); err != nil {
+		return nil, err
+	}
+	if strings.Contains(req.ID, "hang") {
+		if err := os.WriteFile(req.Destination, []byte("partial"), 0644); err != nil {
+			return nil, err
+		}
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	if err := os.WriteFile(req.Destination, []byte("test content"), 0644); err != nil {
+		return nil, err
+	}
+	return &download.DownloadResult{Success: true, BytesDownloaded: req.FileSize}, nil
+}
+
+// newTimeoutProcessor returns a processor for jane.smith whose first recording hangs
+func newTimeoutProcessor(t *testing.T, config ProcessorConfig) UserProcessor {
+	tmpDir := t.TempDir()
+	zoomClient := newMockZoomClient()
+	zoomClient.recordings["jane.smith@example.com"] = []*zoom.Recording{
+		{
+			UUID:      "uuid-hang",
+			Topic:     "Stuck Meeting",
+			StartTime: time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC),
+			RecordingFiles: []zoom.RecordingFile{
+				{ID: "file-hang", FileType: "MP4", DownloadURL: "https://zoom.us/download/hang.mp4", FileSize: 1024},
+			},
+			DownloadAccessToken: "test-token",
+		},
+		{
+			UUID:      "uuid-ok",
+			Topic:     "Fine Meeting",
+			StartTime: time.Date(2024, 1, 15, 11, 0, 0, 0, time.UTC),
+			RecordingFiles: []zoom.RecordingFile{
+				{ID: "file-ok", FileType: "MP4", DownloadURL: "https://zoom.us/download/ok.mp4", FileSize: 1024},
+			},
+			DownloadAccessToken: "test-token",
+		},
+	}
+
+	config.BaseDownloadDir = tmpDir
+	config.ContinueOnError = true
+	userManager, _ := users.NewActiveUserManager(users.ActiveUserConfig{})
+	return NewUserProcessor(
+		zoomClient,
+		&hangingDownloadManager{},
+		directory.NewDirectoryManager(directory.DirectoryConfig{BaseDirectory: tmpDir, CreateDirs: true}, userManager),
+		filename.NewFileSanitizer(filename.FileSanitizerOptions{}),
+		nil,
+		config,
+	)
+}
+
+func TestUserProcessor_FileTimeout(t *testing.T) {
+	processor := newTimeoutProcessor(t, ProcessorConfig{FileTimeout: 50 * time.Millisecond})
+
+	result, err := processor.ProcessUser(context.Background(), "jane.smith@example.com", "jane.smith@example.com")
+	if err != nil {
+		t.Fatalf("ProcessUser failed: %v", err)
+	}
+	if result.Interrupted || result.TimedOut {
+		t.Errorf("Expected a file timeout not to stop the user, got interrupted=%v timed out=%v", result.Interrupted, result.TimedOut)
+	}
+	if result.ErrorCount != 1 || result.DownloadedCount != 1 {
+		t.Fatalf("Expected the stuck file to fail and the next to download, got %d errors and %d downloads", result.ErrorCount, result.DownloadedCount)
+	}
+	if !errors.Is(result.Errors[0], ErrFileTimeout) {
+		t.Errorf("Expected ErrFileTimeout, got %v", result.Errors[0])
+	}
+}
+
+func TestUserProcessor_UserTimeout(t *testing.T) {
+	processor := newTimeoutProcessor(t, ProcessorConfig{UserTimeout: 50 * time.Millisecond})
+
+	ctx := context.Background()
+	result, err := processor.ProcessUser(ctx, "jane.smith@example.com", "jane.smith@example.com")
+	if err != nil {
+		t.Fatalf("ProcessUser failed: %v", err)
+	}
+	if !result.TimedOut || result.Interrupted {
+		t.Errorf("Expected the user to time out without interrupting the run, got timed out=%v interrupted=%v", result.TimedOut, result.Interrupted)
+	}
+	if result.ErrorCount != 1 || result.DownloadedCount != 0 {
+		t.Errorf("Expected one timeout error and no downloads, got %d errors and %d downloads", result.ErrorCount, result.DownloadedCount)
+	}
+	if len(result.Errors) == 0 || !errors.Is(result.Errors[0], ErrUserTimeout) {
+		t.Errorf("Expected ErrUserTimeout, got %v", result.Errors)
+	}
+}
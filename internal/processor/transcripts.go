@@ -0,0 +1,74 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/curtbushko/zoom-to-box/internal/logging"
+	"github.com/curtbushko/zoom-to-box/internal/transcript"
+	"github.com/curtbushko/zoom-to-box/internal/zoom"
+)
+
+// isTranscript reports whether recordingFile is a WebVTT audio transcript or closed captions
+func isTranscript(recordingFile zoom.RecordingFile) bool {
+	switch strings.ToUpper(recordingFile.FileType) {
+	case "TRANSCRIPT", "CC":
+		return true
+	}
+	return strings.EqualFold(recordingFile.FileExtension, "VTT")
+}
+
+// includesFileType reports whether the run processes recordingFile's type: MP4 recordings, every
+// file in meta-only runs, and transcripts when they are converted
+func (p *userProcessorImpl) includesFileType(recordingFile zoom.RecordingFile) bool {
+	return recordingFile.FileType == "MP4" || p.config.MetaOnly || (len(p.config.TranscriptFormats) > 0 && isTranscript(recordingFile))
+}
+
+// convertTranscript writes the configured sidecars of a downloaded transcript next to it
+// It returns the sidecar paths. Conversions are best effort: failures are logged but never
+// fail the transcript itself.
+func (p *userProcessorImpl) convertTranscript(ctx context.Context, filePath, filename string) []string {
+	logger := logging.GetDefaultLogger()
+
+	paths, err := transcript.ConvertFile(filePath, p.config.TranscriptFormats)
+	if err != nil && logger != nil {
+		logger.WarnWithContext(ctx, fmt.Sprintf("Failed to convert transcript %s: %v", filename, err))
+	}
+	if len(paths) > 0 && p.config.Verbose && logger != nil {
+		logger.InfoWithContext(ctx, fmt.Sprintf("Converted transcript %s to %s", filename, strings.Join(p.config.TranscriptFormats, ", ")))
+	}
+	return paths
+}
+
+// uploadTranscriptSidecar uploads a converted transcript next to the recording
+func (p *userProcessorImpl) uploadTranscriptSidecar(ctx context.Context, sidecarPath, zoomEmail, boxEmail string, recordingTime time.Time) {
+	logger := logging.GetDefaultLogger()
+	sidecarFilename := filepath.Base(sidecarPath)
+
+	var sidecarSize int64
+	if info, err := os.Stat(sidecarPath); err == nil {
+		sidecarSize = info.Size()
+	}
+
+	result, err := p.uploadAndTrack(ctx, sidecarPath, boxEmail, recordingTime, 0, zoomEmail, sidecarFilename, sidecarSize)
+	if err != nil {
+		if logger != nil {
+			logger.ErrorWithContext(ctx, fmt.Sprintf("Failed to upload transcript to %s: %s - %v", p.destination.Name(), sidecarFilename, err))
+		}
+		return
+	}
+
+	if p.config.DeleteAfterUpload && (result.Uploaded || result.Skipped) && p.deletionVerified(ctx, result, sidecarPath) {
+		if err := os.Remove(sidecarPath); err != nil {
+			if logger != nil {
+				logger.ErrorWithContext(ctx, fmt.Sprintf("Failed to delete transcript after upload: %s - %v", sidecarPath, err))
+			}
+		} else if logger != nil {
+			logger.InfoWithContext(ctx, fmt.Sprintf("Deleted local transcript after upload: %s", sidecarFilename))
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package processor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/curtbushko/zoom-to-box/internal/download"
+	"github.com/curtbushko/zoom-to-box/internal/filename"
+	"github.com/curtbushko/zoom-to-box/internal/zoom"
+)
+
+// testVTT is the WebVTT transcript vttDownloadManager writes
+const testVTT = "WEBVTT\n\n1\n00:00:01.000 --> 00:00:02.500\nJane Smith: Hello\n"
+
+// vttDownloadManager writes a WebVTT transcript for every download
+type vttDownloadManager struct{}
+
+func (m *vttDownloadManager) Download(ctx context.Context, req download.DownloadRequest, progressCallback download.ProgressCallback) (*download.DownloadResult, error) {
+	if err := os.MkdirAll(filepath.Dir(req.Destination), 0755); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(req.Destination, []byte(testVTT), 0644); err != nil {
+		return nil, err
+	}
+	return &download.DownloadResult{Success: true, BytesDownloaded: int64(len(testVTT))}, nil
+}
+
+func TestUserProcessor_ConvertsTranscripts(t *testing.T) {
+	uploadManager := newMockUploadManager(newMockBoxClient())
+	processor := NewUserProcessor(
+		newMockZoomClient(),
+		&vttDownloadManager{},
+		nil,
+		filename.NewFileSanitizer(filename.FileSanitizerOptions{}),
+		uploadManager,
+		ProcessorConfig{BaseDownloadDir: t.TempDir(), BoxEnabled: true, MetaOnly: true, TranscriptFormats: []string{"srt"}},
+	)
+
+	recordings := []*zoom.Recording{{
+		UUID:      "uuid-transcript",
+		Topic:     "Team Sync",
+		StartTime: time.Date(2024, 3, 1, 14, 0, 0, 0, time.UTC),
+		RecordingFiles: []zoom.RecordingFile{
+			{ID: "file-vtt", FileType: "TRANSCRIPT", FileExtension: "VTT", DownloadURL: "https://zoom.us/download/transcript.vtt", FileSize: int64(len(testVTT))},
+		},
+	}}
+
+	result, err := processor.ProcessRecordings(context.Background(), "jane@example.com", "jane@example.com", recordings)
+	if err != nil {
+		t.Fatalf("ProcessRecordings failed: %v", err)
+	}
+	if result.ErrorCount != 0 || len(result.Files) != 1 {
+		t.Fatalf("Expected the transcript processed without errors, got %+v", result)
+	}
+
+	srtPath := result.Files[0].LocalPath + ".srt"
+	data, err := os.ReadFile(srtPath)
+	if err != nil {
+		t.Fatalf("Expected an SRT sidecar next to the transcript: %v", err)
+	}
+	if !strings.Contains(string(data), "00:00:01,000 --> 00:00:02,500\nJane Smith: Hello") {
+		t.Errorf("Expected the cue in SRT form, got %q", data)
+	}
+
+	uploaded := false
+	for _, path := range uploadManager.uploadedFiles {
+		uploaded = uploaded || path == srtPath
+	}
+	if !uploaded {
+		t.Errorf("Expected the SRT sidecar uploaded, got %v", uploadManager.uploadedFiles)
+	}
+}
+
+func TestUserProcessor_ConvertsTranscriptsInNormalRuns(t *testing.T) {
+	recordings := []*zoom.Recording{{
+		UUID:      "uuid-transcript",
+		Topic:     "Team Sync",
+		StartTime: time.Date(2024, 3, 1, 14, 0, 0, 0, time.UTC),
+		RecordingFiles: []zoom.RecordingFile{
+			{ID: "file-mp4", FileType: "MP4", FileExtension: "MP4", DownloadURL: "https://zoom.us/download/meeting.mp4", FileSize: int64(len(testVTT))},
+			{ID: "file-vtt", FileType: "TRANSCRIPT", FileExtension: "VTT", DownloadURL: "https://zoom.us/download/transcript.vtt", FileSize: int64(len(testVTT))},
+			{ID: "file-chat", FileType: "CHAT", FileExtension: "TXT", DownloadURL: "https://zoom.us/download/chat.txt", FileSize: int64(len(testVTT))},
+		},
+	}}
+
+	for _, formats := range [][]string{nil, {"srt"}} {
+		processor := NewUserProcessor(
+			newMockZoomClient(),
+			&vttDownloadManager{},
+			nil,
+			filename.NewFileSanitizer(filename.FileSanitizerOptions{}),
+			nil,
+			ProcessorConfig{BaseDownloadDir: t.TempDir(), TranscriptFormats: formats},
+		)
+
+		result, err := processor.ProcessRecordings(context.Background(), "jane@example.com", "jane@example.com", recordings)
+		if err != nil {
+			t.Fatalf("ProcessRecordings failed: %v", err)
+		}
+
+		var transcriptPath string
+		for _, file := range result.Files {
+			if file.FileType == "TRANSCRIPT" {
+				transcriptPath = file.LocalPath
+			}
+			if file.FileType == "CHAT" {
+				t.Errorf("Expected other non-MP4 files skipped outside meta-only runs, got %+v", file)
+			}
+		}
+		if formats == nil {
+			if transcriptPath != "" || result.DownloadedCount != 1 {
+				t.Errorf("Expected only the MP4 downloaded without transcript formats, got %+v", result.Files)
+			}
+			continue
+		}
+		if transcriptPath == "" || result.DownloadedCount != 2 {
+			t.Fatalf("Expected the MP4 and the transcript downloaded, got %+v", result.Files)
+		}
+		if _, err := os.Stat(transcriptPath + ".srt"); err != nil {
+			t.Errorf("Expected an SRT sidecar next to the transcript: %v", err)
+		}
+	}
+}
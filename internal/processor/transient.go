@@ -0,0 +1,138 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/curtbushko/zoom-to-box/internal/errclass"
+	"github.com/curtbushko/zoom-to-box/internal/logging"
+	"github.com/curtbushko/zoom-to-box/internal/retry"
+	"github.com/curtbushko/zoom-to-box/internal/users"
+)
+
+// transientUser is a user whose failure may go away when the user is processed again
+type transientUser struct {
+	entry users.UserEntry
+	index int // Index of the user's result in ProcessorSummary.UserResults
+}
+
+// transientFailure reports whether a failed user failed only on transient errors, such as rate
+// limits, 5xx responses or network failures. Users stopped by the disk, a timeout or the run
+// deadline are not retried.
+func transientFailure(result *ProcessorResult, err error) bool {
+	if result == nil || result.DiskFull || result.TimedOut || result.TimeBoxed || result.Interrupted {
+		return false
+	}
+
+	errs := result.Errors
+	if err != nil {
+		errs = append(errs[:len(errs):len(errs)], err)
+	}
+	if len(errs) == 0 {
+		return false
+	}
+	for _, userErr := range errs {
+		if !errclass.IsTransient(userErr) {
+			return false
+		}
+	}
+	return true
+}
+
+// retryTransientUsers processes the users that failed on transient errors again, up to
+// TransientRetries passes, and updates the summary with the outcome. The returned error is the
+// context's when the run is cancelled.
+func (p *userProcessorImpl) retryTransientUsers(ctx context.Context, usersFile *users.ActiveUsersFile, summary *ProcessorSummary, pending []transientUser) error {
+	logger := logging.GetDefaultLogger()
+
+	for pass := 1; pass <= p.config.TransientRetries && len(pending) > 0; pass++ {
+		if logger != nil {
+			logger.InfoWithContext(ctx, fmt.Sprintf("Retrying %d users that failed on transient errors in %v (pass %d of %d)",
+				len(pending), p.config.TransientRetryDelay, pass, p.config.TransientRetries))
+		}
+		if err := retry.Sleep(ctx, p.config.TransientRetryDelay); err != nil {
+			summary.Interrupted = true
+			return err
+		}
+
+		var failed []transientUser
+		for _, user := range pending {
+			if ctx.Err() != nil {
+				summary.Interrupted = true
+				return ctx.Err()
+			}
+			if p.deadlineReached() {
+				summary.TimeBoxed = true
+				if logger != nil {
+					logger.InfoWithContext(ctx, "Run deadline reached, leaving the remaining failed users for the next run")
+				}
+				return nil
+			}
+
+			previous := summary.UserResults[user.index]
+			result, err := p.ProcessUser(ctx, user.entry.ZoomEmail, user.entry.BoxEmail)
+			if pass == 1 {
+				summary.RetriedUsers++
+			}
+
+			merged := mergeRetryResult(previous, result)
+			summary.UserResults[user.index] = merged
+			summary.TotalDownloads += result.DownloadedCount
+			summary.TotalUploads += result.UploadedCount
+			summary.TotalDeleted += result.DeletedCount
+			summary.TotalBytesDownloaded += result.BytesDownloaded
+			summary.TotalBytesPlanned += result.BytesPlanned
+			summary.TotalSkipped += merged.SkippedCount - previous.SkippedCount
+			summary.TotalErrors += result.ErrorCount - previous.ErrorCount
+
+			switch {
+			case result.Interrupted || ctx.Err() != nil:
+				summary.Interrupted = true
+				return ctx.Err()
+			case result.DiskFull:
+				summary.DiskFull = true
+				return nil
+			case err == nil && result.ErrorCount == 0 && !result.TimeBoxed:
+				summary.FailedUsers--
+				summary.ProcessedUsers++
+				if err := usersFile.MarkUserComplete(user.entry.ZoomEmail); err != nil {
+					if logger != nil {
+						logger.ErrorWithContext(ctx, fmt.Sprintf("Failed to mark user complete %s: %v", user.entry.ZoomEmail, err))
+					}
+				} else if logger != nil {
+					logger.InfoWithContext(ctx, fmt.Sprintf("Retry succeeded, marked user complete: %s", user.entry.ZoomEmail))
+				}
+			case result.TimeBoxed:
+				summary.TimeBoxed = true
+				return nil
+			case transientFailure(result, err):
+				failed = append(failed, user)
+			}
+		}
+		pending = failed
+	}
+
+	if len(pending) > 0 && logger != nil {
+		logger.WarnWithContext(ctx, fmt.Sprintf("%d users still failed after %d retry passes and stay incomplete", len(pending), p.config.TransientRetries))
+	}
+	return nil
+}
+
+// mergeRetryResult combines a user's first result with the result of processing the user
+// again. Transfers add up; errors and file outcomes are the retry's, which saw every file again.
+func mergeRetryResult(previous, retried *ProcessorResult) *ProcessorResult {
+	merged := *retried
+	merged.DownloadedCount += previous.DownloadedCount
+	merged.UploadedCount += previous.UploadedCount
+	merged.DeletedCount += previous.DeletedCount
+	merged.BytesDownloaded += previous.BytesDownloaded
+	merged.BytesPlanned += previous.BytesPlanned
+	merged.Duration += previous.Duration
+
+	// Files transferred by the first attempt are skipped by the retry; they are not skips of the run
+	merged.SkippedCount -= previous.DownloadedCount
+	if merged.SkippedCount < previous.SkippedCount {
+		merged.SkippedCount = previous.SkippedCount
+	}
+	return &merged
+}
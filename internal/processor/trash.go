@@ -0,0 +1,100 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/curtbushko/zoom-to-box/internal/logging"
+	"github.com/curtbushko/zoom-to-box/internal/zoom"
+)
+
+// TrashRecordingLister is implemented by Zoom clients that can list recordings in the trash
+type TrashRecordingLister interface {
+	GetAllUserTrashRecordings(ctx context.Context, userID string, params zoom.ListRecordingsParams) ([]*zoom.Recording, error)
+}
+
+// TrashRecoverer is implemented by Zoom clients that can restore recordings from the trash
+type TrashRecoverer interface {
+	RecoverMeetingRecordings(ctx context.Context, meetingUUID string) error
+}
+
+// appendTrashRecordings adds the user's recordings in the Zoom trash that are not already in
+// recordings. With RestoreTrash, each one that passes the recording filter is restored first so
+// its files can be downloaded. A failure to list the trash is logged and the other recordings
+// are processed on their own.
+func (p *userProcessorImpl) appendTrashRecordings(ctx context.Context, zoomEmail string, params zoom.ListRecordingsParams, recordings []*zoom.Recording) []*zoom.Recording {
+	logger := logging.GetDefaultLogger()
+
+	lister, ok := p.zoomClient.(TrashRecordingLister)
+	if !ok {
+		if logger != nil {
+			logger.WarnWithContext(ctx, "Zoom client does not support trash recordings, skipping the trash")
+		}
+		return recordings
+	}
+
+	trashRecordings, err := lister.GetAllUserTrashRecordings(ctx, zoomEmail, params)
+	if err != nil {
+		if logger != nil {
+			logger.WarnWithContext(ctx, fmt.Sprintf("Failed to get trash recordings for user %s: %v", zoomEmail, err))
+		}
+		return recordings
+	}
+
+	known := make(map[string]bool, len(recordings))
+	for _, recording := range recordings {
+		known[recording.UUID] = true
+	}
+
+	added, restored := 0, 0
+	for _, recording := range trashRecordings {
+		if known[recording.UUID] {
+			continue
+		}
+		known[recording.UUID] = true
+		recordings = append(recordings, recording)
+		added++
+
+		if p.config.RestoreTrash && p.config.Filter.SkipReason(recording) == "" && p.restoreTrashRecording(ctx, recording) {
+			restored++
+		}
+	}
+
+	if logger != nil {
+		logger.InfoWithContext(ctx, fmt.Sprintf("Zoom API returned %d trash recordings for user %s (%d not already listed, %d restored)",
+			len(trashRecordings), zoomEmail, added, restored))
+	}
+	return recordings
+}
+
+// restoreTrashRecording restores recording from the Zoom trash and reports whether it was restored
+// A dry run only reports the restore; a failed restore is logged and the download is still tried.
+func (p *userProcessorImpl) restoreTrashRecording(ctx context.Context, recording *zoom.Recording) bool {
+	logger := logging.GetDefaultLogger()
+
+	if p.config.DryRun {
+		if logger != nil {
+			logger.InfoWithContext(ctx, fmt.Sprintf("Would restore from the Zoom trash: %s (%s)", recording.Topic, recording.UUID))
+		}
+		return false
+	}
+
+	recoverer, ok := p.zoomClient.(TrashRecoverer)
+	if !ok {
+		if logger != nil {
+			logger.WarnWithContext(ctx, "Zoom client does not support restoring trash recordings")
+		}
+		return false
+	}
+
+	if err := recoverer.RecoverMeetingRecordings(ctx, recording.UUID); err != nil {
+		if logger != nil {
+			logger.WarnWithContext(ctx, fmt.Sprintf("Failed to restore %s (%s) from the Zoom trash: %v", recording.Topic, recording.UUID, err))
+		}
+		return false
+	}
+	if logger != nil {
+		logger.InfoWithContext(ctx, fmt.Sprintf("Restored %s (%s) from the Zoom trash", recording.Topic, recording.UUID))
+	}
+	return true
+}
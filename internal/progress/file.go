@@ -0,0 +1,305 @@
+package progress
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/curtbushko/zoom-to-box/internal/logging"
+	"github.com/curtbushko/zoom-to-box/internal/timefmt"
+)
+
+// DefaultFileInterval is how often a FileReporter rewrites its progress file
+const DefaultFileInterval = 5 * time.Second
+
+// Snapshot is the content of a progress file, for dashboards and scripts that poll it
+type Snapshot struct {
+	UpdatedAt    time.Time `json:"updated_at"`
+	RunStartedAt time.Time `json:"run_started_at"`
+	Finished     bool      `json:"finished"`
+
+	UsersTotal int    `json:"users_total"` // 0 when the run is not over a users file
+	UserIndex  int    `json:"user_index"`
+	User       string `json:"user,omitempty"`
+
+	FilesTotal     int     `json:"files_total"`
+	FilesDone      int     `json:"files_done"`
+	BytesTotal     int64   `json:"bytes_total"`
+	BytesDone      int64   `json:"bytes_done"`
+	Percent        int     `json:"percent"`
+	BytesPerSecond float64 `json:"bytes_per_second"`
+	ETASeconds     *int64  `json:"eta_seconds"` // null until there is enough progress to estimate
+
+	CurrentFile *FileSnapshot `json:"current_file"` // null between transfers
+}
+
+// FileSnapshot describes the transfer in progress
+type FileSnapshot struct {
+	Action     string `json:"action"` // "download" or "upload"
+	Name       string `json:"name"`
+	BytesDone  int64  `json:"bytes_done"`
+	BytesTotal int64  `json:"bytes_total"`
+	Percent    int    `json:"percent"`
+}
+
+// FileReporter is a Reporter that periodically writes a Snapshot as JSON to a file
+type FileReporter struct {
+	mu   sync.Mutex
+	path string
+	now  func() time.Time
+	state
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewFileReporter creates a FileReporter that rewrites path every interval until Close is called
+func NewFileReporter(path string, interval time.Duration) *FileReporter {
+	if interval <= 0 {
+		interval = DefaultFileInterval
+	}
+	r := &FileReporter{
+		path: path,
+		now:  timefmt.Now,
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	r.runStart = r.now()
+
+	go r.loop(interval)
+	return r
+}
+
+// loop writes the progress file every interval until stopped
+func (r *FileReporter) loop(interval time.Duration) {
+	defer close(r.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := r.write(false); err != nil {
+				logging.Warn("Failed to write progress file: %v", err)
+			}
+		case <-r.stop:
+			return
+		}
+	}
+}
+
+// Close stops the periodic writes and writes a final snapshot marked as finished
+func (r *FileReporter) Close() error {
+	close(r.stop)
+	<-r.done
+	return r.write(true)
+}
+
+// Snapshot returns the current progress
+func (r *FileReporter) Snapshot() Snapshot {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.snapshot(r.now())
+}
+
+// snapshot builds a Snapshot from the current state; callers hold r.mu
+func (r *FileReporter) snapshot(now time.Time) Snapshot {
+	done := r.userBytesDone()
+	snapshot := Snapshot{
+		UpdatedAt:    now,
+		RunStartedAt: r.runStart,
+		UsersTotal:   r.runUsers,
+		UserIndex:    r.userIndex,
+		User:         r.userEmail,
+		FilesTotal:   r.files,
+		FilesDone:    r.filesDone,
+		BytesTotal:   r.userTotal,
+		BytesDone:    done,
+		Percent:      percent(done, r.userTotal),
+	}
+
+	if r.userEmail != "" {
+		rate, eta, ok := estimate(done, r.userTotal, now.Sub(r.userStart))
+		snapshot.BytesPerSecond = rate
+		if ok {
+			seconds := int64(eta.Round(time.Second) / time.Second)
+			snapshot.ETASeconds = &seconds
+		}
+	}
+
+	if r.action != "" {
+		snapshot.CurrentFile = &FileSnapshot{
+			Action:     r.action,
+			Name:       r.name,
+			BytesDone:  r.transferred,
+			BytesTotal: r.transferTotal,
+			Percent:    percent(r.transferred, r.transferTotal),
+		}
+	}
+	return snapshot
+}
+
+// write replaces the progress file atomically, so pollers never read a partial file
+func (r *FileReporter) write(finished bool) error {
+	r.mu.Lock()
+	snapshot := r.snapshot(r.now())
+	r.mu.Unlock()
+	snapshot.Finished = finished
+
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal progress: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.path), 0755); err != nil {
+		return fmt.Errorf("failed to create progress file directory: %w", err)
+	}
+
+	tmpPath := r.path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write progress file: %w", err)
+	}
+	if err := os.Rename(tmpPath, r.path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to replace progress file: %w", err)
+	}
+	return nil
+}
+
+// StartRun begins a run over the given number of users
+func (r *FileReporter) StartRun(users int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.startRun(users, r.now())
+}
+
+// NextUser advances the run to its next user
+func (r *FileReporter) NextUser() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nextUser()
+}
+
+// StartUser begins a user
+func (r *FileReporter) StartUser(email string, files int, totalBytes int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.startUser(email, files, totalBytes, r.now())
+}
+
+// StartTransfer begins a download or upload of a single file
+func (r *FileReporter) StartTransfer(action, name string, totalBytes int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.startTransfer(action, name, totalBytes, r.now())
+}
+
+// Update reports the bytes transferred so far for the current transfer
+func (r *FileReporter) Update(transferred, totalBytes int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.update(transferred, totalBytes)
+}
+
+// EndTransfer ends the current transfer
+func (r *FileReporter) EndTransfer() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.endTransfer()
+}
+
+// FileDone counts a file toward the user's total
+func (r *FileReporter) FileDone(size int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.fileDone(size)
+}
+
+// EndUser ends the current user
+func (r *FileReporter) EndUser() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.endUser()
+}
+
+// multiReporter forwards progress to several reporters
+type multiReporter []Reporter
+
+// Multi returns a Reporter that forwards progress to each non-nil reporter
+// It returns nil when no reporters are given, so callers can keep checking for a nil Reporter.
+func Multi(reporters ...Reporter) Reporter {
+	var multi multiReporter
+	for _, reporter := range reporters {
+		if reporter != nil {
+			multi = append(multi, reporter)
+		}
+	}
+	switch len(multi) {
+	case 0:
+		return nil
+	case 1:
+		return multi[0]
+	}
+	return multi
+}
+
+func (m multiReporter) StartRun(users int) {
+	for _, r := range m {
+		r.StartRun(users)
+	}
+}
+
+func (m multiReporter) NextUser() {
+	for _, r := range m {
+		r.NextUser()
+	}
+}
+
+func (m multiReporter) StartUser(email string, files int, totalBytes int64) {
+	for _, r := range m {
+		r.StartUser(email, files, totalBytes)
+	}
+}
+
+func (m multiReporter) StartTransfer(action, name string, totalBytes int64) {
+	for _, r := range m {
+		r.StartTransfer(action, name, totalBytes)
+	}
+}
+
+func (m multiReporter) Update(transferred, totalBytes int64) {
+	for _, r := range m {
+		r.Update(transferred, totalBytes)
+	}
+}
+
+func (m multiReporter) EndTransfer() {
+	for _, r := range m {
+		r.EndTransfer()
+	}
+}
+
+func (m multiReporter) FileDone(size int64) {
+	for _, r := range m {
+		r.FileDone(size)
+	}
+}
+
+func (m multiReporter) EndUser() {
+	for _, r := range m {
+		r.EndUser()
+	}
+}
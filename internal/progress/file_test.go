@@ -0,0 +1,94 @@
+package progress
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileReporter_Snapshot(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "progress.json")
+	reporter := NewFileReporter(path, time.Hour)
+
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	reporter.now = func() time.Time { return now }
+
+	reporter.StartRun(2)
+	reporter.NextUser()
+	reporter.StartUser("jane@example.com", 2, 2000)
+	reporter.FileDone(1000)
+	reporter.StartTransfer("download", "meeting.mp4", 1000)
+	now = now.Add(10 * time.Second)
+	reporter.Update(500, 1000)
+
+	snapshot := reporter.Snapshot()
+	if snapshot.UserIndex != 1 || snapshot.UsersTotal != 2 || snapshot.User != "jane@example.com" {
+		t.Errorf("Unexpected user position: %+v", snapshot)
+	}
+	if snapshot.FilesDone != 1 || snapshot.BytesDone != 1500 || snapshot.Percent != 75 {
+		t.Errorf("Unexpected counts: %+v", snapshot)
+	}
+	if snapshot.ETASeconds == nil || *snapshot.ETASeconds != 3 {
+		t.Errorf("Expected a 3 second ETA at 150 B/s, got %v", snapshot.ETASeconds)
+	}
+	if snapshot.CurrentFile == nil || snapshot.CurrentFile.Name != "meeting.mp4" || snapshot.CurrentFile.Percent != 50 {
+		t.Errorf("Unexpected current file: %+v", snapshot.CurrentFile)
+	}
+
+	if err := reporter.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Expected a progress file: %v", err)
+	}
+	var written Snapshot
+	if err := json.Unmarshal(data, &written); err != nil {
+		t.Fatalf("Invalid progress file: %v", err)
+	}
+	if !written.Finished || written.BytesDone != 1500 {
+		t.Errorf("Expected the final snapshot to be marked finished, got %+v", written)
+	}
+}
+
+func TestFileReporter_WritesPeriodically(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "progress.json")
+	reporter := NewFileReporter(path, 10*time.Millisecond)
+	defer reporter.Close()
+
+	reporter.StartUser("jane@example.com", 1, 100)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if data, err := os.ReadFile(path); err == nil {
+			var snapshot Snapshot
+			if err := json.Unmarshal(data, &snapshot); err == nil && snapshot.User == "jane@example.com" {
+				if snapshot.Finished {
+					t.Error("Expected a periodic snapshot not to be marked finished")
+				}
+				return
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("Progress file was not written periodically")
+}
+
+func TestMulti(t *testing.T) {
+	if Multi(nil, nil) != nil {
+		t.Error("Expected no reporters to give a nil Reporter")
+	}
+
+	first := NewFileReporter(filepath.Join(t.TempDir(), "a.json"), time.Hour)
+	second := NewFileReporter(filepath.Join(t.TempDir(), "b.json"), time.Hour)
+	defer first.Close()
+	defer second.Close()
+
+	Multi(first, nil, second).StartUser("jane@example.com", 3, 300)
+	if first.Snapshot().FilesTotal != 3 || second.Snapshot().FilesTotal != 3 {
+		t.Error("Expected progress to reach every reporter")
+	}
+}
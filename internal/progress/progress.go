@@ -0,0 +1,255 @@
+// Package progress renders interactive terminal progress bars for downloads and uploads
+package progress
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Reporter receives progress from the processor as it works through users and files
+type Reporter interface {
+	// StartRun begins a run over the given number of users (0 = unknown, e.g. single user mode)
+	StartRun(users int)
+
+	// NextUser advances the run to its next user, whether or not the user has files to transfer
+	NextUser()
+
+	// StartUser begins a user with the number and total size of the files that may be transferred
+	StartUser(email string, files int, totalBytes int64)
+
+	// StartTransfer begins a download or upload of a single file
+	StartTransfer(action, name string, totalBytes int64)
+
+	// Update reports the bytes transferred so far for the current transfer
+	Update(transferred, totalBytes int64)
+
+	// EndTransfer ends the current transfer
+	EndTransfer()
+
+	// FileDone counts a file of the given size toward the user's total, whether it was
+	// transferred, skipped or failed
+	FileDone(size int64)
+
+	// EndUser ends the current user
+	EndUser()
+}
+
+// Bar and redraw settings
+const (
+	barWidth      = 24
+	maxNameLength = 40
+	redrawEvery   = 100 * time.Millisecond
+)
+
+// Display is a Reporter that draws an overall user/run bar and a per-file bar on a terminal
+// Log output written through the Display is printed above the bars, so they stay at the bottom.
+type Display struct {
+	mu  sync.Mutex
+	out io.Writer
+	now func() time.Time
+	state
+
+	lines    int // Lines currently drawn
+	lastDraw time.Time
+}
+
+// NewDisplay creates a Display that draws to out
+func NewDisplay(out io.Writer) *Display {
+	return &Display{out: out, now: time.Now}
+}
+
+// IsTerminal reports whether f is an interactive terminal
+func IsTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// Write prints p above the progress bars, so log lines and bars do not overwrite each other
+func (d *Display) Write(p []byte) (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.clear()
+	n, err := d.out.Write(p)
+	d.draw()
+	return n, err
+}
+
+// StartRun begins a run over the given number of users
+func (d *Display) StartRun(users int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.startRun(users, d.now())
+}
+
+// NextUser advances the run to its next user
+func (d *Display) NextUser() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.nextUser()
+}
+
+// StartUser begins a user
+func (d *Display) StartUser(email string, files int, totalBytes int64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.startUser(email, files, totalBytes, d.now())
+	d.redraw()
+}
+
+// StartTransfer begins a download or upload of a single file
+func (d *Display) StartTransfer(action, name string, totalBytes int64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.startTransfer(action, name, totalBytes, d.now())
+	d.redraw()
+}
+
+// Update reports the bytes transferred so far for the current transfer
+// Redraws are throttled so fast transfers do not flood the terminal.
+func (d *Display) Update(transferred, totalBytes int64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.update(transferred, totalBytes)
+	if d.now().Sub(d.lastDraw) >= redrawEvery {
+		d.redraw()
+	}
+}
+
+// EndTransfer ends the current transfer
+func (d *Display) EndTransfer() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.endTransfer()
+	d.redraw()
+}
+
+// FileDone counts a file toward the user's total
+func (d *Display) FileDone(size int64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.fileDone(size)
+	d.redraw()
+}
+
+// EndUser ends the current user and removes the bars
+func (d *Display) EndUser() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.clear()
+	d.endUser()
+}
+
+// redraw replaces the drawn bars with the current state; callers hold d.mu
+func (d *Display) redraw() {
+	d.clear()
+	d.draw()
+}
+
+// clear erases the drawn bars and leaves the cursor at the start of the first bar line
+func (d *Display) clear() {
+	for i := 0; i < d.lines; i++ {
+		fmt.Fprint(d.out, "\r\033[K")
+		if i < d.lines-1 {
+			fmt.Fprint(d.out, "\033[1A")
+		}
+	}
+	d.lines = 0
+}
+
+// draw prints the bars for the current state without a trailing newline
+func (d *Display) draw() {
+	if d.userEmail == "" {
+		return
+	}
+	now := d.now()
+	d.lastDraw = now
+
+	lines := []string{d.userLine(now)}
+	if d.action != "" {
+		lines = append(lines, d.transferLine(now))
+	}
+	fmt.Fprint(d.out, strings.Join(lines, "\n"))
+	d.lines = len(lines)
+}
+
+// userLine renders the overall bar for the current user, within the run when the user count is known
+func (d *Display) userLine(now time.Time) string {
+	label := "User " + d.userEmail
+	if d.runUsers > 0 {
+		label = fmt.Sprintf("User %d/%d %s", d.userIndex, d.runUsers, d.userEmail)
+	}
+
+	done := d.userBytesDone()
+	return fmt.Sprintf("%s  %s  %d/%d files  %s",
+		label, bar(done, d.userTotal), d.filesDone, d.files, rateAndETA(done, d.userTotal, now.Sub(d.userStart)))
+}
+
+// transferLine renders the bar for the current file
+func (d *Display) transferLine(now time.Time) string {
+	return fmt.Sprintf("  %s %s  %s  %s/%s  %s",
+		d.action, truncate(d.name, maxNameLength), bar(d.transferred, d.transferTotal),
+		FormatBytes(d.transferred), FormatBytes(d.transferTotal), rateAndETA(d.transferred, d.transferTotal, now.Sub(d.transferStart)))
+}
+
+// bar renders a fixed-width bar with a percentage; an unknown total renders an empty bar
+func bar(done, total int64) string {
+	pct := percent(done, total)
+	filled := pct * barWidth / 100
+	return fmt.Sprintf("[%s%s] %3d%%", strings.Repeat("#", filled), strings.Repeat("-", barWidth-filled), pct)
+}
+
+// rateAndETA renders the average transfer rate and the estimated time to finish
+func rateAndETA(done, total int64, elapsed time.Duration) string {
+	rate, eta, ok := estimate(done, total, elapsed)
+	switch {
+	case rate == 0:
+		return "-- /s  ETA --"
+	case !ok:
+		return fmt.Sprintf("%s/s  ETA --", FormatBytes(int64(rate)))
+	}
+	return fmt.Sprintf("%s/s  ETA %s", FormatBytes(int64(rate)), FormatDuration(eta))
+}
+
+// FormatBytes renders a byte count with a binary unit, e.g. "1.5 MB"
+func FormatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// FormatDuration renders a duration rounded to the second, e.g. "1m20s"
+func FormatDuration(d time.Duration) string {
+	return d.Round(time.Second).String()
+}
+
+// truncate shortens s to at most n runes, marking the cut with "..."
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-3]) + "..."
+}
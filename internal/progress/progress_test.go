@@ -0,0 +1,105 @@
+package progress
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestDisplay returns a Display with a controllable clock
+func newTestDisplay() (*Display, *bytes.Buffer, *time.Time) {
+	var out bytes.Buffer
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	display := NewDisplay(&out)
+	display.now = func() time.Time { return now }
+	return display, &out, &now
+}
+
+// lastFrame returns the bars most recently drawn to out
+func lastFrame(out *bytes.Buffer) string {
+	frames := strings.Split(out.String(), "\r\033[K")
+	return frames[len(frames)-1]
+}
+
+func TestDisplay_RendersUserAndTransferBars(t *testing.T) {
+	display, out, now := newTestDisplay()
+
+	display.StartRun(3)
+	display.NextUser()
+	display.StartUser("jane@example.com", 2, 2000)
+	display.FileDone(1000)
+	display.StartTransfer("download", "meeting.mp4", 1000)
+
+	*now = now.Add(10 * time.Second)
+	display.Update(500, 1000)
+
+	frame := lastFrame(out)
+	for _, want := range []string{"User 1/3 jane@example.com", "1/2 files", " 75%", "download meeting.mp4", " 50%", "500 B/1000 B", "50 B/s  ETA 10s"} {
+		if !strings.Contains(frame, want) {
+			t.Errorf("Expected frame to contain %q, got %q", want, frame)
+		}
+	}
+
+	display.EndTransfer()
+	if frame := lastFrame(out); strings.Contains(frame, "meeting.mp4") {
+		t.Errorf("Expected the transfer bar to be removed, got %q", frame)
+	}
+}
+
+func TestDisplay_UploadsDoNotCountTowardUserBytes(t *testing.T) {
+	display, out, now := newTestDisplay()
+
+	display.StartUser("jane@example.com", 1, 1000)
+	display.StartTransfer("upload", "meeting.mp4", 1000)
+	*now = now.Add(time.Second)
+	display.Update(1000, 1000)
+
+	if frame := lastFrame(out); !strings.Contains(frame, "[------------------------]   0%  0/1 files") {
+		t.Errorf("Expected the user bar to stay empty during an upload, got %q", frame)
+	}
+}
+
+func TestDisplay_WritePrintsAboveBars(t *testing.T) {
+	display, out, _ := newTestDisplay()
+
+	display.StartUser("jane@example.com", 1, 1000)
+	display.Write([]byte("log line\n"))
+
+	text := out.String()
+	logAt := strings.Index(text, "log line\n")
+	if logAt < 0 || !strings.Contains(text[logAt:], "User jane@example.com") {
+		t.Errorf("Expected the bars to be redrawn after the log line, got %q", text)
+	}
+
+	display.EndUser()
+	out.Reset()
+	display.Write([]byte("after\n"))
+	if out.String() != "after\n" {
+		t.Errorf("Expected no bars after the user ended, got %q", out.String())
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := map[int64]string{
+		0:               "0 B",
+		1023:            "1023 B",
+		1536:            "1.5 KB",
+		5 * 1024 * 1024: "5.0 MB",
+		3 << 30:         "3.0 GB",
+	}
+	for n, want := range tests {
+		if got := FormatBytes(n); got != want {
+			t.Errorf("FormatBytes(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	if got := truncate("short.mp4", 40); got != "short.mp4" {
+		t.Errorf("Expected a short name to be kept, got %q", got)
+	}
+	if got := truncate("a-very-long-meeting-name.mp4", 10); got != "a-very-..." {
+		t.Errorf("Expected a truncated name, got %q", got)
+	}
+}
@@ -0,0 +1,110 @@
+package progress
+
+import "time"
+
+// state tracks where a run is, shared by the reporters that render it
+type state struct {
+	runStart  time.Time
+	runUsers  int
+	userIndex int
+
+	userEmail string
+	files     int
+	filesDone int
+	userTotal int64
+	userDone  int64 // Bytes of finished files
+	userStart time.Time
+
+	action           string // "" when no transfer is active
+	name             string
+	transferTotal    int64
+	transferred      int64
+	transferStart    time.Time
+	transferProgress bool // The transfer counts toward the user's bytes (downloads only)
+}
+
+func (s *state) startRun(users int, now time.Time) {
+	s.runStart = now
+	s.runUsers = users
+	s.userIndex = 0
+}
+
+func (s *state) nextUser() {
+	s.userIndex++
+}
+
+func (s *state) startUser(email string, files int, totalBytes int64, now time.Time) {
+	s.userEmail = email
+	s.files = files
+	s.filesDone = 0
+	s.userTotal = totalBytes
+	s.userDone = 0
+	s.userStart = now
+	s.action = ""
+}
+
+func (s *state) startTransfer(action, name string, totalBytes int64, now time.Time) {
+	s.action = action
+	s.name = name
+	s.transferTotal = totalBytes
+	s.transferred = 0
+	s.transferStart = now
+	s.transferProgress = action == "download"
+}
+
+func (s *state) update(transferred, totalBytes int64) {
+	s.transferred = transferred
+	if totalBytes > 0 {
+		s.transferTotal = totalBytes
+	}
+}
+
+func (s *state) endTransfer() {
+	s.action = ""
+}
+
+func (s *state) fileDone(size int64) {
+	s.filesDone++
+	s.userDone += size
+}
+
+func (s *state) endUser() {
+	s.userEmail = ""
+	s.action = ""
+}
+
+// userBytesDone returns the user's finished bytes plus the current download, capped at the user's total
+func (s *state) userBytesDone() int64 {
+	done := s.userDone
+	if s.action != "" && s.transferProgress {
+		done += s.transferred
+	}
+	if done > s.userTotal {
+		done = s.userTotal
+	}
+	return done
+}
+
+// estimate returns the average rate in bytes per second and the time left for done of total bytes;
+// ok is false until there is enough progress to estimate
+func estimate(done, total int64, elapsed time.Duration) (rate float64, eta time.Duration, ok bool) {
+	if elapsed <= 0 || done <= 0 {
+		return 0, 0, false
+	}
+	rate = float64(done) / elapsed.Seconds()
+	if total < done {
+		return rate, 0, false
+	}
+	return rate, time.Duration(float64(total-done) / rate * float64(time.Second)), true
+}
+
+// percent returns done as a whole percentage of total (0 for an unknown total)
+func percent(done, total int64) int {
+	if total <= 0 {
+		return 0
+	}
+	if done >= total {
+		return 100
+	}
+	return int(done * 100 / total)
+}
@@ -0,0 +1,109 @@
+package retry
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// CircuitBreakerConfig configures when a circuit breaker pauses all requests to a service
+type CircuitBreakerConfig struct {
+	FailureThreshold int           `yaml:"failure_threshold" json:"failure_threshold"` // Consecutive failed requests that open the breaker
+	Cooldown         time.Duration `yaml:"cooldown" json:"cooldown"`                   // Pause before requests are let through again
+}
+
+// DefaultBoxCircuitBreaker pauses Box traffic for 30s after 10 failed requests in a row
+var DefaultBoxCircuitBreaker = CircuitBreakerConfig{FailureThreshold: 10, Cooldown: 30 * time.Second}
+
+// WithDefaults returns the configuration with unset (zero) fields taken from def
+func (c CircuitBreakerConfig) WithDefaults(def CircuitBreakerConfig) CircuitBreakerConfig {
+	if c.FailureThreshold == 0 {
+		c.FailureThreshold = def.FailureThreshold
+	}
+	if c.Cooldown == 0 {
+		c.Cooldown = def.Cooldown
+	}
+	return c
+}
+
+// Validate checks the configuration values; zero values are allowed and mean "use the default"
+func (c CircuitBreakerConfig) Validate() error {
+	if c.FailureThreshold < 0 {
+		return fmt.Errorf("failure_threshold must be >= 0")
+	}
+	if c.Cooldown < 0 {
+		return fmt.Errorf("cooldown must be >= 0")
+	}
+	return nil
+}
+
+// CircuitBreaker pauses every caller once FailureThreshold requests in a row have failed
+// While open, Wait blocks until the cooldown has passed. The next request then probes the
+// service: a success closes the breaker, a failure opens it for another cooldown.
+// A nil *CircuitBreaker never pauses.
+type CircuitBreaker struct {
+	config CircuitBreakerConfig
+	onOpen func(failures int, cooldown time.Duration)
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+}
+
+// NewCircuitBreaker returns a breaker for config; it returns nil, a breaker that never opens,
+// when config has no failure threshold or cooldown. onOpen, if set, is called each time the
+// breaker opens.
+func NewCircuitBreaker(config CircuitBreakerConfig, onOpen func(failures int, cooldown time.Duration)) *CircuitBreaker {
+	if config.FailureThreshold <= 0 || config.Cooldown <= 0 {
+		return nil
+	}
+	return &CircuitBreaker{config: config, onOpen: onOpen}
+}
+
+// Wait blocks while the breaker is open, returning early with the context's error if ctx is
+// done first
+func (b *CircuitBreaker) Wait(ctx context.Context) error {
+	if b == nil {
+		return ctx.Err()
+	}
+	b.mu.Lock()
+	wait := time.Until(b.openUntil)
+	b.mu.Unlock()
+	return Sleep(ctx, wait)
+}
+
+// Record counts the outcome of a request
+func (b *CircuitBreaker) Record(failed bool) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	if !failed {
+		b.failures = 0
+		b.mu.Unlock()
+		return
+	}
+
+	b.failures++
+	failures := b.failures
+	opened := failures >= b.config.FailureThreshold && !time.Now().Before(b.openUntil)
+	if opened {
+		b.openUntil = time.Now().Add(b.config.Cooldown)
+	}
+	b.mu.Unlock()
+
+	if opened && b.onOpen != nil {
+		b.onOpen(failures, b.config.Cooldown)
+	}
+}
+
+// Open reports whether the breaker is currently pausing requests
+func (b *CircuitBreaker) Open() bool {
+	if b == nil {
+		return false
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return time.Now().Before(b.openUntil)
+}
@@ -0,0 +1,61 @@
+package retry
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	var opened int
+	breaker := NewCircuitBreaker(CircuitBreakerConfig{FailureThreshold: 3, Cooldown: 50 * time.Millisecond}, func(failures int, cooldown time.Duration) {
+		opened++
+	})
+
+	breaker.Record(true)
+	breaker.Record(true)
+	breaker.Record(false)
+	breaker.Record(true)
+	breaker.Record(true)
+	if breaker.Open() {
+		t.Fatal("Expected a success to reset the failure count")
+	}
+
+	breaker.Record(true)
+	if !breaker.Open() || opened != 1 {
+		t.Fatalf("Expected the breaker to open after 3 failures in a row (opened %d times)", opened)
+	}
+
+	start := time.Now()
+	if err := breaker.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait failed: %v", err)
+	}
+	if time.Since(start) < 40*time.Millisecond {
+		t.Error("Expected Wait to pause until the cooldown passed")
+	}
+
+	// The probe after the cooldown fails, so the breaker opens again at once
+	breaker.Record(true)
+	if !breaker.Open() || opened != 2 {
+		t.Errorf("Expected a failed probe to reopen the breaker (opened %d times)", opened)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := breaker.Wait(ctx); err != context.Canceled {
+		t.Errorf("Expected context.Canceled while open, got %v", err)
+	}
+}
+
+func TestCircuitBreaker_Disabled(t *testing.T) {
+	breaker := NewCircuitBreaker(CircuitBreakerConfig{}, nil)
+	if breaker != nil {
+		t.Fatal("Expected no breaker without a threshold")
+	}
+	for i := 0; i < 100; i++ {
+		breaker.Record(true)
+	}
+	if breaker.Open() || breaker.Wait(context.Background()) != nil {
+		t.Error("Expected a nil breaker to never pause")
+	}
+}
@@ -0,0 +1,125 @@
+// Package retry defines the retry policies used for Zoom and Box requests
+package retry
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// Policy configures how many times an operation is attempted and how long to wait between attempts
+// The delay before retry n (counting from 0) is BaseDelay * Multiplier^n, capped at MaxDelay.
+type Policy struct {
+	MaxAttempts int           `yaml:"max_attempts" json:"max_attempts"` // Total attempts including the first
+	BaseDelay   time.Duration `yaml:"base_delay" json:"base_delay"`     // Delay before the first retry
+	Multiplier  float64       `yaml:"multiplier" json:"multiplier"`     // Growth factor per retry (1 = fixed delay)
+	MaxDelay    time.Duration `yaml:"max_delay" json:"max_delay"`       // Upper bound for a single delay
+
+	Jitter float64 `yaml:"jitter" json:"jitter"` // Fraction of each delay that is randomized, 0-1 (0 = none)
+}
+
+// Default policies for each class of operation
+var (
+	DefaultZoomAPI      = Policy{MaxAttempts: 4, BaseDelay: 500 * time.Millisecond, Multiplier: 2, MaxDelay: 5 * time.Second}
+	DefaultZoomDownload = Policy{MaxAttempts: 4, BaseDelay: 1 * time.Second, Multiplier: 1, MaxDelay: 1 * time.Second}
+	DefaultBoxAPI       = Policy{MaxAttempts: 3, BaseDelay: 500 * time.Millisecond, Multiplier: 2, MaxDelay: 10 * time.Second, Jitter: 0.5}
+	DefaultBoxUpload    = Policy{MaxAttempts: 3, BaseDelay: 500 * time.Millisecond, Multiplier: 2, MaxDelay: 30 * time.Second, Jitter: 0.5}
+)
+
+// WithDefaults returns the policy with unset (zero) fields taken from def
+func (p Policy) WithDefaults(def Policy) Policy {
+	if p.MaxAttempts == 0 {
+		p.MaxAttempts = def.MaxAttempts
+	}
+	if p.BaseDelay == 0 {
+		p.BaseDelay = def.BaseDelay
+	}
+	if p.Multiplier == 0 {
+		p.Multiplier = def.Multiplier
+	}
+	if p.MaxDelay == 0 {
+		p.MaxDelay = def.MaxDelay
+	}
+	if p.Jitter == 0 {
+		p.Jitter = def.Jitter
+	}
+	if p.MaxDelay < p.BaseDelay {
+		p.MaxDelay = p.BaseDelay
+	}
+	return p
+}
+
+// Validate checks the policy values; zero values are allowed and mean "use the default"
+func (p Policy) Validate() error {
+	if p.MaxAttempts < 0 {
+		return fmt.Errorf("max_attempts must be >= 0")
+	}
+	if p.BaseDelay < 0 {
+		return fmt.Errorf("base_delay must be >= 0")
+	}
+	if p.Multiplier != 0 && p.Multiplier < 1 {
+		return fmt.Errorf("multiplier must be >= 1")
+	}
+	if p.MaxDelay < 0 {
+		return fmt.Errorf("max_delay must be >= 0")
+	}
+	if p.MaxDelay != 0 && p.MaxDelay < p.BaseDelay {
+		return fmt.Errorf("max_delay must be >= base_delay")
+	}
+	if p.Jitter < 0 || p.Jitter > 1 {
+		return fmt.Errorf("jitter must be between 0 and 1")
+	}
+	return nil
+}
+
+// Retries returns the number of retries after the first attempt
+func (p Policy) Retries() int {
+	if p.MaxAttempts <= 1 {
+		return 0
+	}
+	return p.MaxAttempts - 1
+}
+
+// Delay returns the wait before retry number retry (0 for the first retry)
+func (p Policy) Delay(retry int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	delay := float64(p.BaseDelay) * math.Pow(multiplier, float64(retry))
+	if p.MaxDelay > 0 && delay > float64(p.MaxDelay) {
+		return p.MaxDelay
+	}
+	return time.Duration(delay)
+}
+
+// Backoff returns Delay(retry) with its Jitter fraction randomized, so clients that failed
+// together do not retry together
+func (p Policy) Backoff(retry int) time.Duration {
+	delay := p.Delay(retry)
+	if p.Jitter <= 0 {
+		return delay
+	}
+	jitter := math.Min(p.Jitter, 1)
+	return time.Duration(float64(delay) * (1 - jitter*rand.Float64()))
+}
+
+// Sleep waits for d, returning early with the context's error if ctx is done first
+func Sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,94 @@
+package retry
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestPolicy_Delay(t *testing.T) {
+	policy := Policy{MaxAttempts: 5, BaseDelay: 100 * time.Millisecond, Multiplier: 3, MaxDelay: time.Second}
+
+	expected := []time.Duration{100 * time.Millisecond, 300 * time.Millisecond, 900 * time.Millisecond, time.Second}
+	for retry, want := range expected {
+		if got := policy.Delay(retry); got != want {
+			t.Errorf("Delay(%d) = %v, want %v", retry, got, want)
+		}
+	}
+
+	fixed := Policy{BaseDelay: time.Second, Multiplier: 1}
+	if fixed.Delay(0) != time.Second || fixed.Delay(5) != time.Second {
+		t.Errorf("Expected fixed delay with multiplier 1, got %v and %v", fixed.Delay(0), fixed.Delay(5))
+	}
+}
+
+func TestPolicy_WithDefaults(t *testing.T) {
+	policy := Policy{MaxAttempts: 6}.WithDefaults(DefaultBoxAPI)
+
+	if policy.MaxAttempts != 6 || policy.BaseDelay != DefaultBoxAPI.BaseDelay || policy.Multiplier != DefaultBoxAPI.Multiplier || policy.MaxDelay != DefaultBoxAPI.MaxDelay {
+		t.Errorf("Unexpected policy: %+v", policy)
+	}
+	if policy.Retries() != 5 {
+		t.Errorf("Expected 5 retries, got %d", policy.Retries())
+	}
+
+	// A base delay above the default cap raises the cap
+	policy = Policy{BaseDelay: time.Minute}.WithDefaults(DefaultZoomAPI)
+	if policy.MaxDelay != time.Minute {
+		t.Errorf("Expected max delay raised to base delay, got %v", policy.MaxDelay)
+	}
+}
+
+func TestPolicy_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  Policy
+		wantErr string
+	}{
+		{"zero value", Policy{}, ""},
+		{"negative attempts", Policy{MaxAttempts: -1}, "max_attempts must be >= 0"},
+		{"shrinking delays", Policy{Multiplier: 0.5}, "multiplier must be >= 1"},
+		{"max below base", Policy{BaseDelay: time.Second, MaxDelay: time.Millisecond}, "max_delay must be >= base_delay"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.Validate()
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+				t.Errorf("Expected error %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestSleep_ContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	start := time.Now()
+	if err := Sleep(ctx, time.Minute); err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if time.Since(start) > time.Second {
+		t.Error("Sleep did not return when the context was cancelled")
+	}
+}
+
+func TestPolicy_Backoff(t *testing.T) {
+	policy := Policy{BaseDelay: time.Second, Multiplier: 2, MaxDelay: 10 * time.Second, Jitter: 0.5}
+	for retry := 0; retry < 5; retry++ {
+		delay := policy.Delay(retry)
+		for i := 0; i < 20; i++ {
+			if got := policy.Backoff(retry); got > delay || got < delay/2 {
+				t.Fatalf("Backoff(%d) = %v, want between %v and %v", retry, got, delay/2, delay)
+			}
+		}
+	}
+
+	if fixed := (Policy{BaseDelay: time.Second, Multiplier: 1}); fixed.Backoff(3) != time.Second {
+		t.Errorf("Expected no jitter without Jitter, got %v", fixed.Backoff(3))
+	}
+}
@@ -0,0 +1,95 @@
+// Package runlock keeps two batch runs from working on the same output directory at once
+// The lock is an advisory lock (flock, or LockFileEx on Windows) on a lock file that also holds
+// the owner's process ID for error messages. The operating system releases the lock when the
+// owner exits, so a crashed run never blocks the next one.
+package runlock
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/curtbushko/zoom-to-box/internal/filelock"
+)
+
+// FileName is the lock file created in the output directory
+const FileName = ".zoom-to-box.lock"
+
+// ErrLocked is returned by Acquire when another process holds the lock
+var ErrLocked = errors.New("another run is in progress")
+
+// HeldError describes the process holding the lock; it matches ErrLocked with errors.Is
+type HeldError struct {
+	Path  string
+	PID   int       // 0 when the owner has not recorded it yet or it cannot be read
+	Since time.Time // Modification time of the lock file
+}
+
+func (e *HeldError) Error() string {
+	if e.PID <= 0 {
+		return fmt.Sprintf("%v (lock file %s)", ErrLocked, e.Path)
+	}
+	return fmt.Sprintf("%v (pid %d, since %s, lock file %s)", ErrLocked, e.PID, e.Since.Format(time.RFC3339), e.Path)
+}
+
+// Is reports whether target is ErrLocked
+func (e *HeldError) Is(target error) bool {
+	return target == ErrLocked
+}
+
+// Lock is a held run lock
+type Lock struct {
+	lock *filelock.Lock
+}
+
+// Acquire locks the lock file at path without waiting, returning a HeldError while another run
+// holds it
+// The lock file is never removed: a run that opened it just before the removal could still lock
+// the removed file while the next run locks a new one.
+func Acquire(path string) (*Lock, error) {
+	lock, err := filelock.TryAcquire(path)
+	if errors.Is(err, filelock.ErrLocked) {
+		return nil, readOwner(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// The process ID is only informational; the lock is held either way
+	file := lock.File()
+	if err := file.Truncate(0); err == nil {
+		file.WriteAt([]byte(strconv.Itoa(os.Getpid())+"\n"), 0)
+	}
+	return &Lock{lock: lock}, nil
+}
+
+// readOwner describes the run holding the lock at path from the process ID it recorded
+func readOwner(path string) *HeldError {
+	held := &HeldError{Path: path}
+	file, err := os.Open(path)
+	if err != nil {
+		return held
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, 32))
+	if err != nil {
+		return held
+	}
+	if pid, err := strconv.Atoi(strings.TrimSpace(string(data))); err == nil && pid > 0 {
+		held.PID = pid
+	}
+	if info, err := file.Stat(); err == nil {
+		held.Since = info.ModTime()
+	}
+	return held
+}
+
+// Release releases the lock so the next run can take it
+func (l *Lock) Release() error {
+	return l.lock.Release()
+}
@@ -0,0 +1,67 @@
+package runlock
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAcquire(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "downloads", FileName)
+
+	lock, err := Acquire(path)
+	if err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+
+	_, err = Acquire(path)
+	var held *HeldError
+	if !errors.Is(err, ErrLocked) || !errors.As(err, &held) || held.PID != os.Getpid() {
+		t.Fatalf("Expected lock held by this process, got %v", err)
+	}
+
+	if err := lock.Release(); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+	lock, err = Acquire(path)
+	if err != nil {
+		t.Fatalf("Acquire after release failed: %v", err)
+	}
+	lock.Release()
+}
+
+func TestAcquire_LeftoverLockFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+
+	// A lock file left by a crashed run, or by a run interrupted before it wrote its process
+	// ID, is not locked and does not block the next run
+	for _, content := range []string{"2147483646\n", "not a pid\n", ""} {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		lock, err := Acquire(path)
+		if err != nil {
+			t.Fatalf("Expected leftover lock file %q to be taken over, got %v", content, err)
+		}
+		lock.Release()
+	}
+}
+
+func TestAcquire_HeldBeforePIDWritten(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+
+	// The holder locked the file but has not written its process ID yet
+	lock, err := Acquire(path)
+	if err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+	defer lock.Release()
+	if err := os.Truncate(path, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Acquire(path); !errors.Is(err, ErrLocked) {
+		t.Fatalf("Expected the lock to stay held while the file is empty, got %v", err)
+	}
+}
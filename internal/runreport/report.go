@@ -0,0 +1,162 @@
+// Package runreport records the file operations that failed during a run so they can be replayed
+package runreport
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/curtbushko/zoom-to-box/internal/timefmt"
+	"github.com/curtbushko/zoom-to-box/internal/zoom"
+)
+
+// Operations that can be recorded and replayed
+const (
+	OperationDownload = "download" // Download from Zoom, then upload if a destination is enabled
+	OperationUpload   = "upload"   // Upload of a file that was already downloaded
+)
+
+// reportVersion is written to every report so the format can evolve
+const reportVersion = 1
+
+// Failure describes a failed file operation with enough stored metadata to re-execute it
+type Failure struct {
+	Operation       string         `json:"operation"`
+	ZoomEmail       string         `json:"zoom_email"`
+	BoxEmail        string         `json:"box_email"`
+	Recording       zoom.Recording `json:"recording"`
+	RecordingFileID string         `json:"recording_file_id"`
+	LocalPath       string         `json:"local_path,omitempty"`
+	Error           string         `json:"error"`
+	FailedAt        time.Time      `json:"failed_at"`
+}
+
+// RecordingFile returns the recording file the failure refers to
+func (f Failure) RecordingFile() (zoom.RecordingFile, bool) {
+	for _, file := range f.Recording.RecordingFiles {
+		if file.ID == f.RecordingFileID {
+			return file, true
+		}
+	}
+	return zoom.RecordingFile{}, false
+}
+
+// FailureRecorder receives failed file operations while a run is in progress
+type FailureRecorder interface {
+	RecordFailure(failure Failure)
+}
+
+// Report is the persisted state of a run
+type Report struct {
+	Version    int       `json:"version"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at,omitempty"`
+	Failures   []Failure `json:"failures"`
+
+	mu sync.Mutex
+}
+
+// New creates an empty report for a run starting now
+func New() *Report {
+	return &Report{
+		Version:   reportVersion,
+		StartedAt: timefmt.Now(),
+		Failures:  make([]Failure, 0),
+	}
+}
+
+// RecordFailure adds a failed operation to the report
+func (r *Report) RecordFailure(failure Failure) {
+	if failure.FailedAt.IsZero() {
+		failure.FailedAt = timefmt.Now()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Failures = append(r.Failures, failure)
+}
+
+// Save writes the report as JSON, replacing the file atomically
+func (r *Report) Save(path string) error {
+	r.mu.Lock()
+	if r.FinishedAt.IsZero() {
+		r.FinishedAt = timefmt.Now()
+	}
+	data, err := json.MarshalIndent(r, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to marshal run report: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create run report directory: %w", err)
+	}
+
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write run report: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to replace run report: %w", err)
+	}
+	return nil
+}
+
+// Load reads a report written by Save
+func Load(path string) (*Report, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read run report: %w", err)
+	}
+
+	var report Report
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse run report %s: %w", path, err)
+	}
+	if report.Version > reportVersion {
+		return nil, fmt.Errorf("run report %s has unsupported version %d", path, report.Version)
+	}
+	return &report, nil
+}
+
+// Replayer re-executes a single failed operation
+type Replayer interface {
+	ReplayFailure(ctx context.Context, failure Failure) error
+}
+
+// ReplaySummary describes the outcome of replaying a report
+type ReplaySummary struct {
+	Attempted int
+	Succeeded int
+	Remaining *Report // Operations that failed again, with their new errors
+}
+
+// Replay re-executes every failure in the report in order
+// Operations that fail again are collected into a new report; replay stops early only if
+// ctx is cancelled, in which case the unattempted failures are carried over unchanged.
+func Replay(ctx context.Context, report *Report, replayer Replayer) *ReplaySummary {
+	summary := &ReplaySummary{Remaining: New()}
+
+	for i, failure := range report.Failures {
+		if ctx.Err() != nil {
+			summary.Remaining.Failures = append(summary.Remaining.Failures, report.Failures[i:]...)
+			break
+		}
+
+		summary.Attempted++
+		if err := replayer.ReplayFailure(ctx, failure); err != nil {
+			failure.Error = err.Error()
+			failure.FailedAt = timefmt.Now()
+			summary.Remaining.RecordFailure(failure)
+			continue
+		}
+		summary.Succeeded++
+	}
+
+	return summary
+}
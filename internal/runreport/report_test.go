@@ -0,0 +1,99 @@
+package runreport
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/curtbushko/zoom-to-box/internal/zoom"
+)
+
+func testFailure(fileID, operation string) Failure {
+	return Failure{
+		Operation: operation,
+		ZoomEmail: "jane@example.com",
+		BoxEmail:  "jane@example.com",
+		Recording: zoom.Recording{
+			UUID:  "uuid-1",
+			Topic: "Standup",
+			RecordingFiles: []zoom.RecordingFile{
+				{ID: "file-1", FileType: "MP4", DownloadURL: "https://zoom.us/rec/file-1"},
+				{ID: "file-2", FileType: "MP4", DownloadURL: "https://zoom.us/rec/file-2"},
+			},
+		},
+		RecordingFileID: fileID,
+		Error:           "connection reset",
+	}
+}
+
+func TestReport_SaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "reports", "run.json")
+
+	report := New()
+	report.RecordFailure(testFailure("file-2", OperationUpload))
+	if err := report.Save(path); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(loaded.Failures) != 1 || loaded.FinishedAt.IsZero() {
+		t.Fatalf("Unexpected report: %+v", loaded)
+	}
+
+	failure := loaded.Failures[0]
+	if failure.Operation != OperationUpload || failure.FailedAt.IsZero() {
+		t.Errorf("Unexpected failure: %+v", failure)
+	}
+	file, ok := failure.RecordingFile()
+	if !ok || file.DownloadURL != "https://zoom.us/rec/file-2" {
+		t.Errorf("Expected recording file to be reconstructed, got %+v (found: %v)", file, ok)
+	}
+}
+
+type fakeReplayer struct {
+	failing  map[string]bool
+	replayed []string
+}
+
+func (f *fakeReplayer) ReplayFailure(ctx context.Context, failure Failure) error {
+	f.replayed = append(f.replayed, failure.RecordingFileID)
+	if f.failing[failure.RecordingFileID] {
+		return errors.New("still unreachable")
+	}
+	return nil
+}
+
+func TestReplay(t *testing.T) {
+	report := New()
+	report.RecordFailure(testFailure("file-1", OperationDownload))
+	report.RecordFailure(testFailure("file-2", OperationUpload))
+
+	replayer := &fakeReplayer{failing: map[string]bool{"file-2": true}}
+	summary := Replay(context.Background(), report, replayer)
+
+	if summary.Attempted != 2 || summary.Succeeded != 1 {
+		t.Errorf("Expected 2 attempted and 1 succeeded, got %+v", summary)
+	}
+	if len(summary.Remaining.Failures) != 1 || summary.Remaining.Failures[0].Error != "still unreachable" {
+		t.Errorf("Expected the failing upload to remain with its new error, got %+v", summary.Remaining.Failures)
+	}
+}
+
+func TestReplay_CancelledContextCarriesFailuresOver(t *testing.T) {
+	report := New()
+	report.RecordFailure(testFailure("file-1", OperationDownload))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	replayer := &fakeReplayer{}
+	summary := Replay(ctx, report, replayer)
+
+	if len(replayer.replayed) != 0 || len(summary.Remaining.Failures) != 1 {
+		t.Errorf("Expected no replays and the failure carried over, got %v and %+v", replayer.replayed, summary.Remaining.Failures)
+	}
+}
@@ -0,0 +1,202 @@
+// Package schedule parses cron expressions and computes when they next fire
+// The standard five fields are supported (minute, hour, day of month, month, day of week)
+// with lists, ranges, steps and month/day names, plus the @hourly, @daily, @midnight,
+// @weekly, @monthly, @yearly and @annually shorthands.
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxSearchYears bounds Next for expressions that never fire, e.g. "0 0 30 2 *"
+const maxSearchYears = 5
+
+// Schedule is a parsed cron expression
+type Schedule struct {
+	expr string
+
+	minute, hour, dom, month, dow uint64 // Bit i set = value i matches
+
+	// Cron matches a day when either day field matches if both are restricted
+	domAny, dowAny bool
+}
+
+// field describes the allowed values of one cron field
+type field struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var (
+	minuteField = field{name: "minute", min: 0, max: 59}
+	hourField   = field{name: "hour", min: 0, max: 23}
+	domField    = field{name: "day of month", min: 1, max: 31}
+	monthField  = field{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// Day of week 7 is Sunday, like 0
+	dowField = field{name: "day of week", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+var shorthands = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// Parse parses a five-field cron expression or shorthand
+func Parse(expr string) (*Schedule, error) {
+	spec := strings.TrimSpace(expr)
+	if expanded, ok := shorthands[strings.ToLower(spec)]; ok {
+		spec = expanded
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron expression %q must have 5 fields (minute hour day-of-month month day-of-week), got %d", expr, len(fields))
+	}
+
+	s := &Schedule{expr: expr}
+	var err error
+	if s.minute, err = minuteField.parse(fields[0]); err != nil {
+		return nil, err
+	}
+	if s.hour, err = hourField.parse(fields[1]); err != nil {
+		return nil, err
+	}
+	if s.dom, err = domField.parse(fields[2]); err != nil {
+		return nil, err
+	}
+	if s.month, err = monthField.parse(fields[3]); err != nil {
+		return nil, err
+	}
+	if s.dow, err = dowField.parse(fields[4]); err != nil {
+		return nil, err
+	}
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+	s.domAny = fields[2] == "*" || fields[2] == "?"
+	s.dowAny = fields[4] == "*" || fields[4] == "?"
+
+	return s, nil
+}
+
+// String returns the expression the schedule was parsed from
+func (s *Schedule) String() string {
+	return s.expr
+}
+
+// Next returns the first time after t, in t's location, when the schedule fires
+// The zero time is returned if the schedule does not fire in the next five years.
+func (s *Schedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(maxSearchYears, 0, 0)
+
+	for t.Before(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// dayMatches applies cron's rule for the two day fields: when both are restricted,
+// a day matching either one fires
+func (s *Schedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domAny || s.dowAny {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+// parse converts a comma-separated list of values, ranges and steps to a bit set
+func (f field) parse(spec string) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(spec, ",") {
+		rangeSpec, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step in %s field %q", f.name, part)
+			}
+			rangeSpec, step = part[:i], n
+		}
+
+		var low, high int
+		switch {
+		case rangeSpec == "*" || rangeSpec == "?":
+			low, high = f.min, f.max
+		case strings.Contains(rangeSpec, "-"):
+			bounds := strings.SplitN(rangeSpec, "-", 2)
+			var err error
+			if low, err = f.value(bounds[0]); err != nil {
+				return 0, err
+			}
+			if high, err = f.value(bounds[1]); err != nil {
+				return 0, err
+			}
+			if low > high {
+				return 0, fmt.Errorf("invalid range in %s field %q", f.name, part)
+			}
+		default:
+			value, err := f.value(rangeSpec)
+			if err != nil {
+				return 0, err
+			}
+			// "5/15" means every 15 starting at 5
+			low, high = value, value
+			if step > 1 {
+				high = f.max
+			}
+		}
+
+		for v := low; v <= high; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// value parses a single number or name and checks it is within the field's bounds
+func (f field) value(s string) (int, error) {
+	if n, ok := f.names[strings.ToLower(s)]; ok {
+		return n, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q", f.name, s)
+	}
+	if n < f.min || n > f.max {
+		return 0, fmt.Errorf("%s %d out of range %d-%d", f.name, n, f.min, f.max)
+	}
+	return n, nil
+}
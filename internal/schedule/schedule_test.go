@@ -0,0 +1,77 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNext(t *testing.T) {
+	// 2024-01-15 is a Monday
+	start := time.Date(2024, 1, 15, 10, 30, 45, 0, time.UTC)
+
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"0 2 * * *", time.Date(2024, 1, 16, 2, 0, 0, 0, time.UTC)},
+		{"* * * * *", time.Date(2024, 1, 15, 10, 31, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2024, 1, 15, 10, 45, 0, 0, time.UTC)},
+		{"5/20 10 * * *", time.Date(2024, 1, 15, 10, 45, 0, 0, time.UTC)},
+		{"0 9-17 * * mon-fri", time.Date(2024, 1, 15, 11, 0, 0, 0, time.UTC)},
+		{"30 1 * * sat,sun", time.Date(2024, 1, 20, 1, 30, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2024, 1, 21, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 mar *", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		// Both day fields restricted: the 1st of the month or any Friday
+		{"0 0 1 * fri", time.Date(2024, 1, 19, 0, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2024, 1, 15, 11, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 30 2 *", time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			s, err := Parse(tt.expr)
+			if err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+			if got := s.Next(start); !got.Equal(tt.want) {
+				t.Errorf("Expected next run at %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestNext_Location(t *testing.T) {
+	loc := time.FixedZone("UTC-5", -5*60*60)
+	s, err := Parse("0 2 * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := s.Next(time.Date(2024, 1, 15, 3, 0, 0, 0, loc))
+	if want := time.Date(2024, 1, 16, 2, 0, 0, 0, loc); !got.Equal(want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"0 2 * *",
+		"0 2 * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+		"@often",
+	} {
+		if _, err := Parse(expr); err == nil {
+			t.Errorf("Expected %q to be rejected", expr)
+		}
+	}
+}
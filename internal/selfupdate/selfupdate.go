@@ -0,0 +1,333 @@
+// Package selfupdate looks up zoom-to-box releases on GitHub and replaces the running binary
+// with the release binary for the platform once its checksum and signature check out
+package selfupdate
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultAPIURL = "https://api.github.com" // GitHub REST API
+	DefaultRepo   = "curtbushko/zoom-to-box" // Repository whose releases are checked
+
+	ChecksumsAsset = "checksums.txt"     // sha256sum output listing every release binary
+	SignatureAsset = "checksums.txt.sig" // Ed25519 signature of ChecksumsAsset, raw or base64
+)
+
+// maxChecksumsSize bounds the checksums and signature downloads
+const maxChecksumsSize = 1 << 20
+
+// Release is a published GitHub release
+type Release struct {
+	TagName     string    `json:"tag_name"` // Version, e.g. v1.4.0
+	Name        string    `json:"name"`
+	URL         string    `json:"html_url"` // Release page
+	PublishedAt time.Time `json:"published_at"`
+	Assets      []Asset   `json:"assets"`
+}
+
+// Asset is a file attached to a release
+type Asset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+	Size int64  `json:"size"`
+}
+
+// Asset returns the asset named name, or nil
+func (r *Release) Asset(name string) *Asset {
+	for i := range r.Assets {
+		if r.Assets[i].Name == name {
+			return &r.Assets[i]
+		}
+	}
+	return nil
+}
+
+// Client queries the GitHub releases API
+type Client struct {
+	HTTP   *http.Client // nil = http.DefaultClient
+	APIURL string       // "" = DefaultAPIURL
+	Repo   string       // "" = DefaultRepo
+	Token  string       // GitHub token for higher rate limits ("" = anonymous)
+}
+
+// Latest returns the newest published release; drafts and pre-releases are skipped by GitHub
+func (c *Client) Latest(ctx context.Context) (*Release, error) {
+	apiURL, repo := c.APIURL, c.Repo
+	if apiURL == "" {
+		apiURL = DefaultAPIURL
+	}
+	if repo == "" {
+		repo = DefaultRepo
+	}
+
+	body, err := c.get(ctx, strings.TrimSuffix(apiURL, "/")+"/repos/"+repo+"/releases/latest", maxChecksumsSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query latest release: %w", err)
+	}
+	var release Release
+	if err := json.Unmarshal(body, &release); err != nil {
+		return nil, fmt.Errorf("failed to decode latest release: %w", err)
+	}
+	if release.TagName == "" {
+		return nil, fmt.Errorf("latest release of %s has no tag", repo)
+	}
+	return &release, nil
+}
+
+// Download fetches the release binary for this platform into a temporary file in dir, after
+// checking the signature of the release checksums with publicKey and the binary against them.
+// The caller removes or installs the returned file.
+func (c *Client) Download(ctx context.Context, release *Release, publicKey ed25519.PublicKey, dir string) (string, error) {
+	name := AssetName(runtime.GOOS, runtime.GOARCH)
+	binary := release.Asset(name)
+	if binary == nil {
+		return "", fmt.Errorf("release %s has no binary for %s/%s (%s)", release.TagName, runtime.GOOS, runtime.GOARCH, name)
+	}
+	checksums, signature := release.Asset(ChecksumsAsset), release.Asset(SignatureAsset)
+	if checksums == nil || signature == nil {
+		return "", fmt.Errorf("release %s is not signed: %s and %s are required", release.TagName, ChecksumsAsset, SignatureAsset)
+	}
+
+	sums, err := c.get(ctx, checksums.URL, maxChecksumsSize)
+	if err != nil {
+		return "", fmt.Errorf("failed to download %s: %w", ChecksumsAsset, err)
+	}
+	sig, err := c.get(ctx, signature.URL, maxChecksumsSize)
+	if err != nil {
+		return "", fmt.Errorf("failed to download %s: %w", SignatureAsset, err)
+	}
+	if err := VerifySignature(publicKey, sums, sig); err != nil {
+		return "", err
+	}
+	want, err := checksumFor(sums, name)
+	if err != nil {
+		return "", err
+	}
+
+	tmp, err := os.CreateTemp(dir, ".zoom-to-box-update-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create update file: %w", err)
+	}
+	if err := c.downloadTo(ctx, binary.URL, tmp, want); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return "", fmt.Errorf("failed to download %s: %w", name, err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return "", fmt.Errorf("failed to write update file: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0755); err != nil {
+		os.Remove(tmp.Name())
+		return "", fmt.Errorf("failed to make update executable: %w", err)
+	}
+	return tmp.Name(), nil
+}
+
+// downloadTo streams url into file and checks its SHA-256 against want
+func (c *Client) downloadTo(ctx context.Context, url string, file *os.File, want string) error {
+	resp, err := c.do(ctx, url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(file, hash), resp.Body); err != nil {
+		return err
+	}
+	if got := hex.EncodeToString(hash.Sum(nil)); got != want {
+		return fmt.Errorf("checksum mismatch: got %s, want %s", got, want)
+	}
+	return nil
+}
+
+// get returns the body of url, up to limit bytes
+func (c *Client) get(ctx context.Context, url string, limit int64) ([]byte, error) {
+	resp, err := c.do(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return io.ReadAll(io.LimitReader(resp.Body, limit))
+}
+
+// do sends a GET request and fails on non-2xx responses
+func (c *Client) do(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+
+	client := c.HTTP
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		resp.Body.Close()
+		return nil, fmt.Errorf("%s returned %s: %s", url, resp.Status, strings.TrimSpace(string(detail)))
+	}
+	return resp, nil
+}
+
+// Replace installs the binary at path as executable
+// Windows cannot overwrite a running program, so the old binary is moved to <executable>.old first.
+func Replace(executable, path string) error {
+	if runtime.GOOS == "windows" {
+		old := executable + ".old"
+		os.Remove(old)
+		if err := os.Rename(executable, old); err != nil {
+			return fmt.Errorf("failed to move %s aside: %w", executable, err)
+		}
+		if err := os.Rename(path, executable); err != nil {
+			os.Rename(old, executable)
+			return fmt.Errorf("failed to install %s: %w", executable, err)
+		}
+		return nil
+	}
+	if err := os.Rename(path, executable); err != nil {
+		return fmt.Errorf("failed to install %s: %w", executable, err)
+	}
+	return nil
+}
+
+// AssetName returns the name of the release binary for a platform, e.g. zoom-to-box-linux-amd64
+// or zoom-to-box-windows-amd64.exe
+func AssetName(goos, goarch string) string {
+	name := "zoom-to-box-" + goos + "-" + goarch
+	if goos == "windows" {
+		name += ".exe"
+	}
+	return name
+}
+
+// ParsePublicKey decodes a base64 Ed25519 public key
+func ParsePublicKey(encoded string) (ed25519.PublicKey, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid release signing key: expected a base64 Ed25519 public key")
+	}
+	return ed25519.PublicKey(key), nil
+}
+
+// VerifySignature checks an Ed25519 signature of data, given raw or base64 encoded
+func VerifySignature(publicKey ed25519.PublicKey, data, signature []byte) error {
+	if len(signature) != ed25519.SignatureSize {
+		decoded, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(signature)))
+		if err != nil {
+			return fmt.Errorf("invalid %s: not an Ed25519 signature", SignatureAsset)
+		}
+		signature = decoded
+	}
+	if len(publicKey) != ed25519.PublicKeySize || !ed25519.Verify(publicKey, data, signature) {
+		return errors.New("release checksums signature does not verify; refusing to install")
+	}
+	return nil
+}
+
+// checksumFor returns the SHA-256 that sha256sum output lists for name
+func checksumFor(sums []byte, name string) (string, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(sums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == name {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+	return "", fmt.Errorf("%s does not list %s", ChecksumsAsset, name)
+}
+
+// Newer reports whether latest is a later version than current, e.g. v1.10.0 over v1.9.2
+// Versions are major.minor.patch with an optional v prefix and -prerelease suffix; current
+// versions that are not releases, such as "dev", return an error.
+func Newer(current, latest string) (bool, error) {
+	c, err := parseVersion(current)
+	if err != nil {
+		return false, err
+	}
+	l, err := parseVersion(latest)
+	if err != nil {
+		return false, err
+	}
+	for i := 0; i < 3; i++ {
+		if l.parts[i] != c.parts[i] {
+			return l.parts[i] > c.parts[i], nil
+		}
+	}
+	// A release is newer than its pre-releases
+	switch {
+	case c.pre != "" && l.pre == "":
+		return true, nil
+	case c.pre == "" || l.pre == "":
+		return false, nil
+	default:
+		return l.pre > c.pre, nil
+	}
+}
+
+// version is a parsed release version
+type version struct {
+	parts [3]int
+	pre   string
+}
+
+// parseVersion parses v1.2.3 and 1.2.3-rc.1 style versions
+func parseVersion(s string) (version, error) {
+	var v version
+	core, pre, _ := strings.Cut(strings.TrimPrefix(strings.TrimSpace(s), "v"), "-")
+	core, _, _ = strings.Cut(core, "+")
+	fields := strings.Split(core, ".")
+	if len(fields) != 3 {
+		return v, fmt.Errorf("%q is not a release version", s)
+	}
+	for i, field := range fields {
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 0 {
+			return v, fmt.Errorf("%q is not a release version", s)
+		}
+		v.parts[i] = n
+	}
+	v.pre = pre
+	return v, nil
+}
+
+// Executable returns the path of the running binary with symlinks resolved, so the update
+// replaces the file rather than a link to it
+func Executable() (string, error) {
+	path, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate the running binary: %w", err)
+	}
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", path, err)
+	}
+	return resolved, nil
+}
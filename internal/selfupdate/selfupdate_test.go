@@ -0,0 +1,152 @@
+package selfupdate
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// releaseServer serves a v1.2.0 release of binary, with the checksums and signature when sums is set
+func releaseServer(t *testing.T, binary []byte, sums string, signature []byte) *httptest.Server {
+	t.Helper()
+	name := AssetName(runtime.GOOS, runtime.GOARCH)
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/curtbushko/zoom-to-box/releases/latest":
+			assets := []Asset{{Name: name, URL: server.URL + "/download/" + name}}
+			if sums != "" {
+				assets = append(assets,
+					Asset{Name: ChecksumsAsset, URL: server.URL + "/download/" + ChecksumsAsset},
+					Asset{Name: SignatureAsset, URL: server.URL + "/download/" + SignatureAsset})
+			}
+			json.NewEncoder(w).Encode(Release{TagName: "v1.2.0", URL: "https://github.com/curtbushko/zoom-to-box/releases/tag/v1.2.0", Assets: assets})
+		case "/download/" + name:
+			w.Write(binary)
+		case "/download/" + ChecksumsAsset:
+			w.Write([]byte(sums))
+		case "/download/" + SignatureAsset:
+			w.Write(signature)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestDownload(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	binary := []byte("#!/bin/sh\necho new release\n")
+	sum := sha256.Sum256(binary)
+	name := AssetName(runtime.GOOS, runtime.GOARCH)
+	sums := fmt.Sprintf("%s  %s\n%s  zoom-to-box-plan9-386\n", hex.EncodeToString(sum[:]), name, strings.Repeat("0", 64))
+	signature := ed25519.Sign(privateKey, []byte(sums))
+
+	tests := []struct {
+		name      string
+		binary    []byte
+		sums      string
+		signature []byte
+		wantErr   string
+	}{
+		{name: "verified release", binary: binary, sums: sums, signature: signature},
+		{name: "base64 signature", binary: binary, sums: sums, signature: []byte(base64.StdEncoding.EncodeToString(signature) + "\n")},
+		{name: "tampered binary", binary: []byte("malicious"), sums: sums, signature: signature, wantErr: "checksum mismatch"},
+		{name: "tampered checksums", binary: binary, sums: sums + "extra\n", signature: signature, wantErr: "signature does not verify"},
+		{name: "unsigned release", binary: binary, wantErr: "is not signed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := releaseServer(t, tt.binary, tt.sums, tt.signature)
+			client := &Client{APIURL: server.URL}
+			dir := t.TempDir()
+
+			release, err := client.Latest(context.Background())
+			if err != nil {
+				t.Fatalf("Latest failed: %v", err)
+			}
+			path, err := client.Download(context.Background(), release, publicKey, dir)
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+				if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+					t.Errorf("Expected no update file to be left behind, got %d files", len(entries))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Download failed: %v", err)
+			}
+			data, err := os.ReadFile(path)
+			if err != nil || string(data) != string(binary) {
+				t.Errorf("Expected the release binary, got %q (%v)", data, err)
+			}
+
+			executable := filepath.Join(dir, "zoom-to-box")
+			if err := os.WriteFile(executable, []byte("old"), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := Replace(executable, path); err != nil {
+				t.Fatalf("Replace failed: %v", err)
+			}
+			if data, _ := os.ReadFile(executable); string(data) != string(binary) {
+				t.Errorf("Expected %s to be replaced, got %q", executable, data)
+			}
+		})
+	}
+}
+
+func TestNewer(t *testing.T) {
+	tests := []struct {
+		current string
+		latest  string
+		want    bool
+		wantErr bool
+	}{
+		{current: "v1.9.2", latest: "v1.10.0", want: true},
+		{current: "v1.10.0", latest: "v1.10.0", want: false},
+		{current: "1.10.1", latest: "v1.10.0", want: false},
+		{current: "v2.0.0-rc.1", latest: "v2.0.0", want: true},
+		{current: "v2.0.0", latest: "v2.0.0-rc.1", want: false},
+		{current: "dev", latest: "v1.0.0", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := Newer(tt.current, tt.latest)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("Newer(%q, %q) = %v, %v; want %v (error: %v)", tt.current, tt.latest, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestParsePublicKey(t *testing.T) {
+	publicKey, _, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := ParsePublicKey(base64.StdEncoding.EncodeToString(publicKey))
+	if err != nil || !parsed.Equal(publicKey) {
+		t.Errorf("Expected the key to round-trip, got %v", err)
+	}
+	if _, err := ParsePublicKey("c2hvcnQ="); err == nil {
+		t.Error("Expected a short key to be rejected")
+	}
+}
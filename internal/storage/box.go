@@ -0,0 +1,587 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/curtbushko/zoom-to-box/internal/box"
+	"github.com/curtbushko/zoom-to-box/internal/logging"
+	"github.com/curtbushko/zoom-to-box/internal/tracking"
+)
+
+// boxDestination adapts the Box upload manager to the UploadDestination interface
+// The user's root folder is the "zoom" folder owned by their Box account.
+type boxDestination struct {
+	manager        box.UploadManager
+	conflictPolicy ConflictPolicy
+	uploadAsUser   bool
+	createZoomDir  bool
+	dryRun         bool
+	searchExisting bool
+	destinationID  string // Shared folder holding the user folders ("" = each user's zoom folder)
+
+	mu      sync.Mutex
+	userIDs map[string]string // Box user ID by email, resolved once per user in As-User mode
+	routes  map[string]string // Folder ID or path by lowercased email, used instead of the zoom folder
+}
+
+// BoxDestinationOptions configures a Box destination
+type BoxDestinationOptions struct {
+	ConflictPolicy ConflictPolicy // Existing file with a different size ("" = version)
+	UploadAsUser   bool           // Create folders and upload as the Box user instead of the service account
+
+	CreateZoomFolderIfMissing bool // Create the user's zoom folder, owned by the user, instead of failing the user
+	DryRun                    bool // Accept a missing zoom folder that would be created without creating it
+
+	SearchExisting bool // Check for existing files with the Box search API, listing folders only if it fails
+
+	DestinationFolderID string // Shared folder whose per-user folders, created as needed, replace the zoom folders ("" = none); the client must be created with the same ID
+}
+
+// NewBoxDestination creates an upload destination backed by a Box upload manager
+// Existing files whose size differs from the local file are uploaded as a new version.
+func NewBoxDestination(manager box.UploadManager) UploadDestination {
+	return NewBoxDestinationWithConflictPolicy(manager, ConflictVersion)
+}
+
+// NewBoxDestinationWithConflictPolicy creates a Box destination that applies policy to existing
+// files whose size differs from the local file ("" = version)
+func NewBoxDestinationWithConflictPolicy(manager box.UploadManager, policy ConflictPolicy) UploadDestination {
+	return NewBoxDestinationWithOptions(manager, BoxDestinationOptions{ConflictPolicy: policy})
+}
+
+// NewBoxDestinationWithOptions creates a Box destination configured by opts
+// With UploadAsUser the user's Box ID is looked up by email and every folder lookup, folder
+// creation and upload is made with the As-User header, so the user owns what is created.
+func NewBoxDestinationWithOptions(manager box.UploadManager, opts BoxDestinationOptions) UploadDestination {
+	policy := opts.ConflictPolicy
+	if policy == "" {
+		policy = ConflictVersion
+	}
+	return &boxDestination{
+		manager:        manager,
+		conflictPolicy: policy,
+		uploadAsUser:   opts.UploadAsUser,
+		createZoomDir:  opts.CreateZoomFolderIfMissing,
+		dryRun:         opts.DryRun,
+		searchExisting: opts.SearchExisting,
+		destinationID:  opts.DestinationFolderID,
+		userIDs:        make(map[string]string),
+		routes:         make(map[string]string),
+	}
+}
+
+// Name returns the destination name
+func (d *boxDestination) Name() string {
+	return "Box"
+}
+
+// UploadManager returns the wrapped Box upload manager
+func (d *boxDestination) UploadManager() box.UploadManager {
+	return d.manager
+}
+
+// clientFor returns the Box client that acts for userEmail
+// This is the service account's client unless uploads are made as the user.
+func (d *boxDestination) clientFor(userEmail string) (box.BoxClient, error) {
+	client := d.manager.GetBoxClient()
+	if !d.uploadAsUser {
+		return client, nil
+	}
+
+	d.mu.Lock()
+	userID, ok := d.userIDs[strings.ToLower(userEmail)]
+	d.mu.Unlock()
+	if !ok {
+		user, err := client.GetUserByEmail(userEmail)
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up Box user %s: %w", userEmail, err)
+		}
+		userID = user.ID
+		d.mu.Lock()
+		d.userIDs[strings.ToLower(userEmail)] = userID
+		d.mu.Unlock()
+	}
+	return box.NewAsUserClient(client, userID), nil
+}
+
+// RouteUser sends userEmail's uploads to a folder ID or a path below the root folder instead of
+// their zoom folder; the folder must exist
+func (d *boxDestination) RouteUser(userEmail, destination string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if destination == "" {
+		delete(d.routes, strings.ToLower(userEmail))
+		return
+	}
+	d.routes[strings.ToLower(userEmail)] = destination
+}
+
+// UploadTarget identifies the folder below which userEmail's uploads go: the folder routed to the
+// user, their folder in the destination folder, or their zoom folder
+func (d *boxDestination) UploadTarget(userEmail string) string {
+	if route, ok := d.route(userEmail); ok {
+		return "box:route/" + route
+	}
+	if d.destinationID != "" {
+		return "box:folder/" + d.destinationID
+	}
+	return "box:zoom"
+}
+
+// folders returns the upload manager's run-scoped folder cache, or nil when it keeps none
+func (d *boxDestination) folders() *box.FolderCache {
+	if cacher, ok := d.manager.(box.FolderCacher); ok {
+		return cacher.FolderCache()
+	}
+	return nil
+}
+
+// forgetDeletedFolders empties the folder cache when an upload failed because its folder no
+// longer exists, e.g. it was deleted in Box while a long-running serve process cached it
+func (d *boxDestination) forgetDeletedFolders(err error) {
+	if box.IsNotFoundError(err) {
+		d.folders().Reset()
+	}
+}
+
+// route returns the folder routed to userEmail, if any
+func (d *boxDestination) route(userEmail string) (string, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	route, ok := d.routes[strings.ToLower(userEmail)]
+	return route, ok
+}
+
+// lookupUserFolder finds the folder below which userEmail's uploads go without creating it:
+// the folder routed to the user, or their zoom folder. routed reports which one was looked up.
+func (d *boxDestination) lookupUserFolder(client box.BoxClient, userEmail string) (folder *box.Folder, routed bool, err error) {
+	route, routed := d.route(userEmail)
+	if !routed {
+		folder, err = d.folders().FindZoomFolderByOwner(client, userEmail)
+		return folder, false, err
+	}
+
+	if strings.Trim(route, "0123456789") == "" {
+		folder, err = client.GetFolder(route)
+	} else {
+		folder, err = box.FindFolderByPath(client, strings.Trim(route, "/"), box.RootFolderID)
+	}
+	if err != nil {
+		return nil, true, fmt.Errorf("failed to find destination folder %s for user %s: %w", route, userEmail, err)
+	}
+	return folder, true, nil
+}
+
+// findZoomFolder finds the user's zoom folder, creating it when it is missing and that is enabled
+// With a destination folder the user's folder in it is used instead and always created; a
+// folder routed to the user replaces both and is never created.
+func (d *boxDestination) findZoomFolder(client box.BoxClient, userEmail string) (*box.Folder, error) {
+	zoomFolder, routed, err := d.lookupUserFolder(client, userEmail)
+	if err == nil || routed || !d.createsUserFolder() || d.dryRun || !box.IsNotFoundError(err) {
+		return zoomFolder, err
+	}
+
+	if d.destinationID != "" {
+		logging.Info("No folder found for %s in destination folder %s, creating it", userEmail, d.destinationID)
+		return box.CreateDestinationUserFolder(client, d.destinationID, userEmail)
+	}
+	logging.Info("No zoom folder found for %s, creating it", userEmail)
+	return box.CreateZoomFolderForUser(client, userEmail)
+}
+
+// createsUserFolder reports whether a missing zoom folder, or destination user folder, is created
+func (d *boxDestination) createsUserFolder() bool {
+	return d.createZoomDir || d.destinationID != ""
+}
+
+// CheckUserAccess verifies the user's zoom folder can be found, creating it if enabled
+func (d *boxDestination) CheckUserAccess(ctx context.Context, userEmail string) error {
+	client, err := d.clientFor(userEmail)
+	if err != nil {
+		return err
+	}
+	_, err = d.findZoomFolder(client, userEmail)
+	if _, routed := d.route(userEmail); err != nil && !routed && d.createsUserFolder() && d.dryRun && box.IsNotFoundError(err) {
+		logging.Info("No zoom folder found for %s, it would be created", userEmail)
+		return nil
+	}
+	return err
+}
+
+// FileExists checks whether fileName exists in folderPath under the user's zoom folder
+func (d *boxDestination) FileExists(ctx context.Context, userEmail, folderPath, fileName string) (bool, error) {
+	_, exists, err := d.ExistingFileSize(ctx, userEmail, folderPath, fileName)
+	return exists, err
+}
+
+// ExistingFileSize returns the size of fileName in folderPath under the user's zoom folder
+func (d *boxDestination) ExistingFileSize(ctx context.Context, userEmail, folderPath, fileName string) (int64, bool, error) {
+	client, err := d.clientFor(userEmail)
+	if err != nil {
+		return 0, false, err
+	}
+
+	zoomFolder, err := d.findZoomFolder(client, userEmail)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to find zoom folder for user %s: %w", userEmail, err)
+	}
+
+	// One search of the zoom folder replaces listing every folder on the path
+	if d.searchExisting {
+		size, exists, err := searchExistingFile(client, zoomFolder.ID, folderPath, fileName)
+		if err == nil {
+			return size, exists, nil
+		}
+		logging.Warn("Box search for %s failed, listing folders instead: %v", fileName, err)
+	}
+
+	folder, err := d.folders().CreateFolderPath(client, folderPath, zoomFolder.ID)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to get Box folder %s: %w", folderPath, err)
+	}
+
+	existingFile, err := client.FindFileByName(folder.ID, fileName)
+	if err != nil || existingFile == nil {
+		return 0, false, nil
+	}
+	return existingFile.Size, true, nil
+}
+
+// searchExistingFile finds fileName in folderPath below the zoom folder with the Box search API
+func searchExistingFile(client box.BoxClient, zoomFolderID, folderPath, fileName string) (int64, bool, error) {
+	files, err := client.SearchFiles(fileName, zoomFolderID)
+	if err != nil {
+		return 0, false, err
+	}
+	for _, file := range files {
+		if box.FileInFolderPath(file, zoomFolderID, folderPath) {
+			return file.Size, true, nil
+		}
+	}
+	return 0, false, nil
+}
+
+// PlanUpload resolves the user's zoom folder and walks folderPath below it without creating anything
+func (d *boxDestination) PlanUpload(ctx context.Context, userEmail, folderPath, fileName string) (*UploadPlan, error) {
+	client, err := d.clientFor(userEmail)
+	if err != nil {
+		return nil, err
+	}
+
+	var plan *UploadPlan
+	var parentID string
+	zoomFolder, routed, err := d.lookupUserFolder(client, userEmail)
+	switch {
+	case err == nil:
+		plan = &UploadPlan{RootFolder: fmt.Sprintf("%s (ID %s)", zoomFolder.Name, zoomFolder.ID)}
+		parentID = zoomFolder.ID
+	case routed:
+		return nil, err
+	case d.destinationID != "" && box.IsNotFoundError(err):
+		// Every folder below a user folder that would be created is missing too
+		plan = &UploadPlan{RootFolder: box.DestinationUserFolderName(userEmail) + " (to be created)", MissingFolders: []string{}}
+	case d.createZoomDir && box.IsNotFoundError(err):
+		// Every folder below a zoom folder that would be created is missing too
+		plan = &UploadPlan{RootFolder: box.ZoomFolderName + " (to be created)", MissingFolders: []string{}}
+	default:
+		return nil, fmt.Errorf("failed to find zoom folder for user %s: %w", userEmail, err)
+	}
+
+	var walked []string
+	for _, part := range strings.Split(strings.Trim(folderPath, "/"), "/") {
+		if part == "" {
+			continue
+		}
+		walked = append(walked, part)
+		if plan.MissingFolders != nil {
+			plan.MissingFolders = append(plan.MissingFolders, strings.Join(walked, "/"))
+			continue
+		}
+
+		folder, err := client.FindFolderByName(parentID, part)
+		if err != nil {
+			var boxErr *box.BoxError
+			if !errors.As(err, &boxErr) || boxErr.StatusCode != http.StatusNotFound {
+				return nil, fmt.Errorf("failed to look up Box folder %s: %w", strings.Join(walked, "/"), err)
+			}
+			plan.MissingFolders = []string{strings.Join(walked, "/")}
+			continue
+		}
+		parentID = folder.ID
+	}
+
+	// Files cannot exist in folders that are not there yet
+	if plan.MissingFolders != nil {
+		return plan, nil
+	}
+	if existing, err := client.FindFileByName(parentID, fileName); err == nil && existing != nil {
+		plan.Exists = true
+		plan.ExistingSize = existing.Size
+	}
+	return plan, nil
+}
+
+// UploadFile uploads a file into the user's zoom folder with check-before-upload
+func (d *boxDestination) UploadFile(ctx context.Context, req UploadRequest) (*UploadResult, error) {
+	client, err := d.clientFor(req.UserEmail)
+	if err != nil {
+		return nil, err
+	}
+	fileName := req.FileName
+	if fileName == "" {
+		fileName = filepath.Base(req.LocalPath)
+	}
+
+	// Find the user's zoom folder in Box using their email
+	zoomFolder, err := d.findZoomFolder(client, req.UserEmail)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find zoom folder for user %s: %w", req.UserEmail, err)
+	}
+
+	// Files for the root folder are uploaded directly (e.g. the user's uploads.csv)
+	if req.FolderPath == "" {
+		file, err := client.UploadFileWithProgress(req.LocalPath, zoomFolder.ID, fileName, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to upload %s: %w", fileName, err)
+		}
+		return &UploadResult{FileID: file.ID, FileSize: file.Size}, nil
+	}
+
+	// Set the upload manager's base folder to the user's zoom folder
+	// This ensures files are uploaded to: zoomFolder/<year>/<month>/<day>/
+	d.manager.SetBaseFolderID(zoomFolder.ID)
+
+	folder, err := d.folders().CreateFolderPath(client, req.FolderPath, zoomFolder.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Box folder structure: %w", err)
+	}
+
+	if !req.Overwrite {
+		existingFile, err := client.FindFileByName(folder.ID, fileName)
+		if err == nil && existingFile != nil {
+			localInfo, err := os.Stat(req.LocalPath)
+			if err != nil {
+				return nil, fmt.Errorf("failed to stat %s: %w", req.LocalPath, err)
+			}
+			if existingFile.Size == localInfo.Size() {
+				return &UploadResult{FileID: existingFile.ID, FileSize: existingFile.Size, Skipped: true}, nil
+			}
+
+			// A different size means an earlier upload was truncated or the recording changed
+			logging.Warn("Box file %s/%s is %d bytes but the local file is %d bytes; applying conflict policy %q",
+				req.FolderPath, fileName, existingFile.Size, localInfo.Size(), d.conflictPolicy)
+			switch d.conflictPolicy {
+			case ConflictReport:
+				return nil, fmt.Errorf("Box file %s already exists with %d bytes, local file has %d bytes",
+					fileName, existingFile.Size, localInfo.Size())
+			case ConflictReplace:
+				if err := client.DeleteFile(existingFile.ID); err != nil {
+					return nil, fmt.Errorf("failed to delete mismatched Box file %s: %w", fileName, err)
+				}
+			default:
+				return d.uploadVersion(ctx, client, existingFile.ID, req.LocalPath, fileName, req.Progress)
+			}
+		}
+	}
+
+	var progressCallback box.UploadProgressCallback
+	if req.Progress != nil {
+		progressCallback = func(uploaded, total int64, phase box.UploadPhase) {
+			if phase == box.PhaseUploadingFile && total > 0 {
+				req.Progress(uploaded, total)
+			}
+		}
+	}
+
+	// Tracking is not done here - the caller tracks with the accurate processing time
+	// Managers that can upload into the resolved folder honour the directory layout; others
+	// derive the <year>/<month>/<day> folders from the local path
+	var uploadResult *box.UploadResult
+	if asUser, ok := client.(*box.AsUserClient); ok {
+		// The shared upload manager uploads as the service account
+		uploadResult, err = box.NewUploadManager(asUser).(box.FolderUploader).UploadFileToFolder(ctx, req.LocalPath, folder.ID, fileName, progressCallback)
+	} else if uploader, ok := d.manager.(box.FolderUploader); ok {
+		uploadResult, err = uploader.UploadFileToFolder(ctx, req.LocalPath, folder.ID, fileName, progressCallback)
+	} else {
+		uploadResult, err = d.manager.UploadFileWithEmailMapping(ctx, req.LocalPath, req.ZoomEmail, req.UserEmail, fmt.Sprintf("upload-%s", fileName), progressCallback)
+	}
+	if err != nil {
+		d.forgetDeletedFolders(err)
+		return nil, fmt.Errorf("Box upload failed for %s: %w", fileName, err)
+	}
+
+	// Only report the upload as complete once Box has the same content as the local file
+	if err := d.manager.VerifyUploadedFileChecksum(ctx, uploadResult.FileID, req.LocalPath); err != nil {
+		// Remove a corrupt copy so the next attempt uploads it again instead of skipping it
+		if errors.Is(err, box.ErrChecksumMismatch) {
+			if deleteErr := client.DeleteFile(uploadResult.FileID); deleteErr != nil {
+				logging.Warn("Failed to delete Box file %s after checksum mismatch: %v", uploadResult.FileID, deleteErr)
+			}
+		}
+		return nil, fmt.Errorf("Box checksum verification failed for %s: %w", fileName, err)
+	}
+
+	return &UploadResult{FileID: uploadResult.FileID, FileSize: uploadResult.FileSize, Verified: true}, nil
+}
+
+// MinStreamSize returns the smallest file Box accepts in a chunked upload session
+func (d *boxDestination) MinStreamSize() int64 {
+	return box.MinChunkedUploadSize
+}
+
+// UploadStream uploads size bytes read from r into the folder of req with a chunked upload session
+// Only one part is buffered at a time. Box checks the SHA-1 of the whole stream on commit, so a
+// committed file is verified. A same-sized existing file is skipped; one of a different size is
+// replaced or reported per the conflict policy, and needs a local file to add a version.
+func (d *boxDestination) UploadStream(ctx context.Context, req UploadRequest, r io.Reader, size int64) (*UploadResult, error) {
+	client, err := d.clientFor(req.UserEmail)
+	if err != nil {
+		return nil, err
+	}
+
+	zoomFolder, err := d.findZoomFolder(client, req.UserEmail)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find zoom folder for user %s: %w", req.UserEmail, err)
+	}
+	folder, err := d.folders().CreateFolderPath(client, req.FolderPath, zoomFolder.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Box folder structure: %w", err)
+	}
+
+	if !req.Overwrite {
+		existingFile, err := client.FindFileByName(folder.ID, req.FileName)
+		if err == nil && existingFile != nil {
+			if existingFile.Size == size {
+				return &UploadResult{FileID: existingFile.ID, FileSize: existingFile.Size, Skipped: true}, nil
+			}
+			switch d.conflictPolicy {
+			case ConflictReport:
+				return nil, fmt.Errorf("Box file %s already exists with %d bytes, Zoom reports %d bytes",
+					req.FileName, existingFile.Size, size)
+			case ConflictReplace:
+				logging.Warn("Box file %s/%s is %d bytes but Zoom reports %d bytes; replacing it",
+					req.FolderPath, req.FileName, existingFile.Size, size)
+				if err := client.DeleteFile(existingFile.ID); err != nil {
+					return nil, fmt.Errorf("failed to delete mismatched Box file %s: %w", req.FileName, err)
+				}
+			default:
+				return nil, ErrStreamUnsupported
+			}
+		}
+	}
+
+	file, err := client.UploadStream(r, size, folder.ID, req.FileName, box.ProgressCallback(req.Progress))
+	if err != nil {
+		d.forgetDeletedFolders(err)
+		return nil, fmt.Errorf("Box stream upload failed for %s: %w", req.FileName, err)
+	}
+	if file.Size != 0 && file.Size != size {
+		return nil, fmt.Errorf("Box stored %d bytes for %s, expected %d", file.Size, req.FileName, size)
+	}
+	return &UploadResult{FileID: file.ID, FileSize: size, Verified: true}, nil
+}
+
+// uploadVersion uploads localPath as a new version of an existing Box file and verifies it
+func (d *boxDestination) uploadVersion(ctx context.Context, client box.BoxClient, fileID, localPath, fileName string, progress ProgressFunc) (*UploadResult, error) {
+	file, err := client.UploadFileVersion(fileID, localPath, box.ProgressCallback(progress))
+	if err != nil {
+		return nil, fmt.Errorf("Box version upload failed for %s: %w", fileName, err)
+	}
+
+	// The previous version stays in Box's version history, so a mismatch is only reported
+	if err := d.manager.VerifyUploadedFileChecksum(ctx, file.ID, localPath); err != nil {
+		return nil, fmt.Errorf("Box checksum verification failed for %s: %w", fileName, err)
+	}
+
+	return &UploadResult{FileID: file.ID, FileSize: file.Size, Verified: true}, nil
+}
+
+// VerifyUpload checks the size and SHA1 that Box reports for fileID against the local file
+func (d *boxDestination) VerifyUpload(ctx context.Context, fileID, localPath string) error {
+	localInfo, err := os.Stat(localPath)
+	if err != nil {
+		return fmt.Errorf("failed to stat %s: %w", localPath, err)
+	}
+	file, err := d.manager.GetBoxClient().GetFile(fileID)
+	if err != nil {
+		return fmt.Errorf("failed to get Box file %s: %w", fileID, err)
+	}
+	if file.Size != localInfo.Size() {
+		return fmt.Errorf("Box file %s is %d bytes, local file is %d bytes", fileID, file.Size, localInfo.Size())
+	}
+	return d.manager.VerifyUploadedFileChecksum(ctx, fileID, localPath)
+}
+
+// CreateSharedLink creates a shared link to the Box file fileID, or updates the access of its existing link
+func (d *boxDestination) CreateSharedLink(ctx context.Context, fileID, access string) (string, error) {
+	link, err := d.manager.GetBoxClient().CreateSharedLink(fileID, access)
+	if err != nil {
+		return "", fmt.Errorf("failed to create Box shared link for file %s: %w", fileID, err)
+	}
+	return link.URL, nil
+}
+
+// ApplyMetadata attaches a Box metadata template instance to the Box file fileID
+func (d *boxDestination) ApplyMetadata(ctx context.Context, fileID, scope, templateKey string, values map[string]interface{}) error {
+	if err := d.manager.GetBoxClient().ApplyMetadata(fileID, scope, templateKey, values); err != nil {
+		return fmt.Errorf("failed to apply Box metadata template %s to file %s: %w", templateKey, fileID, err)
+	}
+	return nil
+}
+
+// AbortUploads aborts the chunked upload sessions still open on Box
+func (d *boxDestination) AbortUploads(ctx context.Context) (int, error) {
+	aborter, ok := d.manager.GetBoxClient().(box.UploadSessionAborter)
+	if !ok {
+		return 0, nil
+	}
+	return aborter.AbortOpenUploadSessions()
+}
+
+// CleanupEmptyFolders removes empty date folders created during the run
+// Folders are only tracked when the Box client is wrapped with box.NewFolderTrackingClient.
+func (d *boxDestination) CleanupEmptyFolders(ctx context.Context) (int, error) {
+	cleaner, ok := d.manager.GetBoxClient().(box.EmptyFolderCleaner)
+	if !ok {
+		return 0, nil
+	}
+	deleted, err := cleaner.CleanupEmptyFolders()
+	if len(deleted) > 0 {
+		// Cached date folders may be among the deleted ones
+		d.folders().Reset()
+	}
+	return len(deleted), err
+}
+
+// SetGlobalCSVTracker sets the global CSV tracker on the upload manager
+func (d *boxDestination) SetGlobalCSVTracker(tracker tracking.CSVTracker) {
+	d.manager.SetGlobalCSVTracker(tracker)
+}
+
+// SetUserCSVTracker sets the user CSV tracker on the upload manager
+func (d *boxDestination) SetUserCSVTracker(tracker tracking.CSVTracker) {
+	d.manager.SetUserCSVTracker(tracker)
+}
+
+// TrackUploadWithTime records an upload through the upload manager's trackers
+func (d *boxDestination) TrackUploadWithTime(zoomUser, fileName string, fileSize int64, uploadDate time.Time, processingTime time.Duration) {
+	d.manager.TrackUploadWithTime(zoomUser, fileName, fileSize, uploadDate, processingTime)
+}
+
+// TrackUpload records entry through the upload manager's trackers
+func (d *boxDestination) TrackUpload(entry tracking.UploadEntry) {
+	if tracker, ok := d.manager.(EntryTracker); ok {
+		tracker.TrackUpload(entry)
+		return
+	}
+	d.manager.TrackUploadWithTime(entry.ZoomUser, entry.FileName, entry.RecordingSize, entry.UploadDate, entry.ProcessingTime)
+}
@@ -0,0 +1,201 @@
+// Package storage provides upload destinations for downloaded Zoom recordings
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/curtbushko/zoom-to-box/internal/logging"
+	"github.com/curtbushko/zoom-to-box/internal/tracking"
+)
+
+// UploadDestination defines the interface for a recording upload backend
+// Each user has a root folder in the destination; recordings are stored below it
+// using the same <year>/<month>/<day> structure as the local download directory.
+type UploadDestination interface {
+	// Name returns a human readable destination name for logs (e.g. "Box")
+	Name() string
+
+	// CheckUserAccess verifies the user's root folder can be reached before any downloads start
+	CheckUserAccess(ctx context.Context, userEmail string) error
+
+	// FileExists reports whether fileName already exists in folderPath under the user's root folder
+	FileExists(ctx context.Context, userEmail, folderPath, fileName string) (bool, error)
+
+	// UploadFile uploads a local file, skipping it if a file with the same name already exists
+	UploadFile(ctx context.Context, req UploadRequest) (*UploadResult, error)
+
+	// CSV Tracking
+	SetGlobalCSVTracker(tracker tracking.CSVTracker)
+	SetUserCSVTracker(tracker tracking.CSVTracker)
+	TrackUploadWithTime(zoomUser, fileName string, fileSize int64, uploadDate time.Time, processingTime time.Duration)
+}
+
+// EmptyFolderCleaner is implemented by destinations that can remove folders
+// created during the run that were left empty by failed uploads
+type EmptyFolderCleaner interface {
+	// CleanupEmptyFolders removes empty folders created by this run and returns how many were removed
+	CleanupEmptyFolders(ctx context.Context) (int, error)
+}
+
+// FileSizer is implemented by destinations that can report the size of an existing file,
+// so a truncated earlier upload is not mistaken for a complete one
+type FileSizer interface {
+	// ExistingFileSize returns the size of fileName in folderPath under the user's root folder;
+	// exists is false when there is no such file
+	ExistingFileSize(ctx context.Context, userEmail, folderPath, fileName string) (size int64, exists bool, err error)
+}
+
+// UploadAborter is implemented by destinations that can cancel uploads still in progress,
+// so an interrupted run does not leave partial uploads behind
+type UploadAborter interface {
+	// AbortUploads cancels the uploads in progress and returns how many were aborted
+	AbortUploads(ctx context.Context) (int, error)
+}
+
+// StreamUploader is implemented by destinations that can upload a file read from a stream of
+// known size, so recordings can be piped from Zoom without a local copy
+type StreamUploader interface {
+	// MinStreamSize returns the smallest file that can be streamed; smaller files need a local copy
+	MinStreamSize() int64
+
+	// UploadStream uploads size bytes read from r as req.FileName. It returns ErrStreamUnsupported
+	// before reading from r when the upload needs a local file, e.g. to add a version.
+	UploadStream(ctx context.Context, req UploadRequest, r io.Reader, size int64) (*UploadResult, error)
+}
+
+// UserRouter is implemented by destinations that can send a user's uploads to a custom folder
+// instead of the user's root folder, e.g. a team folder named in the active users file
+type UserRouter interface {
+	// RouteUser sends userEmail's uploads to destination, a folder ID or path ("" = the user's root folder)
+	RouteUser(userEmail, destination string)
+}
+
+// ErrStreamUnsupported is returned by StreamUploader.UploadStream for uploads that need a local file
+var ErrStreamUnsupported = errors.New("upload cannot be streamed")
+
+// TargetIdentifier is implemented by destinations that can name the folder a user's uploads go to,
+// so an upload recorded for one destination or folder is not taken as done for another
+type TargetIdentifier interface {
+	// UploadTarget identifies where userEmail's uploads go, e.g. "box:folder/12345"
+	UploadTarget(userEmail string) string
+}
+
+// UploadVerifier is implemented by destinations that can check a stored file against the local copy
+type UploadVerifier interface {
+	// VerifyUpload checks that the stored file fileID has the size and checksum of localPath
+	VerifyUpload(ctx context.Context, fileID, localPath string) error
+}
+
+// UploadPlanner is implemented by destinations that can describe an upload without changing
+// anything, so dry runs can show the folders that would be created and the files already present
+type UploadPlanner interface {
+	// PlanUpload looks up folderPath and fileName under the user's root folder, creating nothing
+	PlanUpload(ctx context.Context, userEmail, folderPath, fileName string) (*UploadPlan, error)
+}
+
+// UploadPlan describes what uploading a file would do in the destination
+type UploadPlan struct {
+	RootFolder     string   // User's root folder as shown to people, e.g. "zoom (ID 12345)"
+	MissingFolders []string // Folders below the root that would be created, outermost first, e.g. "2024", "2024/01"
+	Exists         bool     // A file with the same name is already in the folder
+	ExistingSize   int64    // Size of the existing file (0 when Exists is false)
+}
+
+// SharedLinker is implemented by destinations that can create a shared link to an uploaded file,
+// so people can be sent a link to their migrated recordings
+type SharedLinker interface {
+	// CreateSharedLink creates a shared link to fileID with the given access level and returns its URL
+	CreateSharedLink(ctx context.Context, fileID, access string) (string, error)
+}
+
+// MetadataApplier is implemented by destinations that can attach structured metadata to an uploaded file
+type MetadataApplier interface {
+	// ApplyMetadata attaches an instance of the metadata template scope/templateKey with values to fileID,
+	// updating the values of an existing instance
+	ApplyMetadata(ctx context.Context, fileID, scope, templateKey string, values map[string]interface{}) error
+}
+
+// EntryTracker is implemented by destinations that can record a complete upload entry,
+// including fields such as the shared link that TrackUploadWithTime does not take
+type EntryTracker interface {
+	// TrackUpload records entry in the configured CSV trackers
+	TrackUpload(entry tracking.UploadEntry)
+}
+
+// ConflictPolicy decides what happens when a file with the same name but a different size
+// already exists in the destination
+type ConflictPolicy string
+
+// Conflict policies
+const (
+	ConflictVersion ConflictPolicy = "version" // Upload the local file as a new version of the existing file
+	ConflictReplace ConflictPolicy = "replace" // Delete the existing file and upload the local file
+	ConflictReport  ConflictPolicy = "report"  // Keep the existing file and fail the upload so it is reported
+)
+
+// UploadRequest describes a single file upload to a destination
+type UploadRequest struct {
+	LocalPath  string // Local file to upload
+	ZoomEmail  string // Zoom user that owns the recording (for logging)
+	UserEmail  string // Destination account email that owns the root folder
+	FolderPath string // Folder below the user's root, e.g. "2024/01/15" ("" = root folder)
+	FileName   string // Name in the destination (defaults to the local file name)
+	Overwrite  bool   // Upload even if a file with the same name already exists
+
+	Progress ProgressFunc // Receives upload progress where the destination supports it (nil = not reported)
+}
+
+// ProgressFunc is called with the bytes uploaded so far and the total size
+type ProgressFunc func(uploaded, total int64)
+
+// UploadResult represents the outcome of an upload to a destination
+type UploadResult struct {
+	FileID   string
+	FileSize int64
+	Skipped  bool // File already existed in the destination
+	Verified bool // The destination confirmed the stored file has the local file's size and checksum
+}
+
+// csvTrackers holds the global and per-user upload trackers shared by destinations
+type csvTrackers struct {
+	global tracking.CSVTracker
+	user   tracking.CSVTracker
+}
+
+// SetGlobalCSVTracker sets the global CSV tracker for tracking all uploads
+func (t *csvTrackers) SetGlobalCSVTracker(tracker tracking.CSVTracker) {
+	t.global = tracker
+}
+
+// SetUserCSVTracker sets the user-specific CSV tracker for tracking user uploads
+func (t *csvTrackers) SetUserCSVTracker(tracker tracking.CSVTracker) {
+	t.user = tracker
+}
+
+// TrackUploadWithTime records an upload in the configured CSV trackers
+func (t *csvTrackers) TrackUploadWithTime(zoomUser, fileName string, fileSize int64, uploadDate time.Time, processingTime time.Duration) {
+	t.TrackUpload(tracking.UploadEntry{
+		ZoomUser:       zoomUser,
+		FileName:       fileName,
+		RecordingSize:  fileSize,
+		UploadDate:     uploadDate,
+		ProcessingTime: processingTime,
+	})
+}
+
+// TrackUpload records entry in the configured CSV trackers
+func (t *csvTrackers) TrackUpload(entry tracking.UploadEntry) {
+	if t.global != nil {
+		if err := t.global.TrackUpload(entry); err != nil {
+			logging.Warn("Failed to track upload in global CSV: %v", err)
+		}
+	}
+	if t.user != nil {
+		if err := t.user.TrackUpload(entry); err != nil {
+			logging.Warn("Failed to track upload in user CSV: %v", err)
+		}
+	}
+}
@@ -0,0 +1,470 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Google Drive API endpoints and constants
+const (
+	GoogleTokenURL         = "https://oauth2.googleapis.com/token"
+	GoogleDriveAPIBaseURL  = "https://www.googleapis.com/drive/v3"
+	GoogleDriveUploadURL   = "https://www.googleapis.com/upload/drive/v3"
+	GoogleDriveScope       = "https://www.googleapis.com/auth/drive"
+	GoogleDriveFolderMime  = "application/vnd.google-apps.folder"
+	DefaultDriveRootFolder = "zoom"
+)
+
+// GoogleDriveConfig holds settings for the Google Drive destination
+type GoogleDriveConfig struct {
+	CredentialsFile string       // Service account JSON key with domain-wide delegation
+	RootFolderName  string       // Folder in each user's My Drive that holds recordings (default: zoom)
+	HTTPClient      *http.Client // Optional HTTP client
+
+	// Endpoint overrides (used by tests)
+	TokenURL      string
+	APIBaseURL    string
+	UploadBaseURL string
+}
+
+// serviceAccountKey is the subset of a Google service account key file we need
+type serviceAccountKey struct {
+	Type        string `json:"type"`
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	TokenURI    string `json:"token_uri"`
+}
+
+// driveToken is a cached access token for one impersonated user
+type driveToken struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int    `json:"expires_in"`
+	expiresAt   time.Time
+}
+
+// driveFile is the subset of Drive file fields we request
+type driveFile struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Size string `json:"size,omitempty"`
+}
+
+// driveFileList is the response of files.list
+type driveFileList struct {
+	Files []driveFile `json:"files"`
+}
+
+// googleDriveDestination uploads recordings to each user's Google Drive by
+// impersonating them through a service account with domain-wide delegation
+type googleDriveDestination struct {
+	csvTrackers
+
+	key            serviceAccountKey
+	rootFolderName string
+	httpClient     *http.Client
+	tokenURL       string
+	apiBaseURL     string
+	uploadBaseURL  string
+
+	mu          sync.Mutex
+	tokens      map[string]*driveToken // keyed by impersonated user email
+	folderCache map[string]string      // "<user>|<path>" -> folder ID
+}
+
+// NewGoogleDriveDestination creates a Google Drive upload destination
+func NewGoogleDriveDestination(cfg GoogleDriveConfig) (UploadDestination, error) {
+	if cfg.CredentialsFile == "" {
+		return nil, fmt.Errorf("google drive credentials file is required")
+	}
+
+	data, err := os.ReadFile(cfg.CredentialsFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read credentials file %s: %w", cfg.CredentialsFile, err)
+	}
+
+	var key serviceAccountKey
+	if err := json.Unmarshal(data, &key); err != nil {
+		return nil, fmt.Errorf("failed to parse service account key: %w", err)
+	}
+	if key.ClientEmail == "" || key.PrivateKey == "" {
+		return nil, fmt.Errorf("service account key must contain client_email and private_key")
+	}
+
+	d := &googleDriveDestination{
+		key:            key,
+		rootFolderName: cfg.RootFolderName,
+		httpClient:     cfg.HTTPClient,
+		tokenURL:       cfg.TokenURL,
+		apiBaseURL:     cfg.APIBaseURL,
+		uploadBaseURL:  cfg.UploadBaseURL,
+		tokens:         make(map[string]*driveToken),
+		folderCache:    make(map[string]string),
+	}
+
+	if d.rootFolderName == "" {
+		d.rootFolderName = DefaultDriveRootFolder
+	}
+	if d.httpClient == nil {
+		d.httpClient = &http.Client{Timeout: 30 * time.Minute}
+	}
+	if d.tokenURL == "" {
+		d.tokenURL = key.TokenURI
+	}
+	if d.tokenURL == "" {
+		d.tokenURL = GoogleTokenURL
+	}
+	if d.apiBaseURL == "" {
+		d.apiBaseURL = GoogleDriveAPIBaseURL
+	}
+	if d.uploadBaseURL == "" {
+		d.uploadBaseURL = GoogleDriveUploadURL
+	}
+
+	return d, nil
+}
+
+// Name returns the destination name
+func (d *googleDriveDestination) Name() string {
+	return "Google Drive"
+}
+
+// UploadTarget identifies the root folder of the user's drive that uploads go to
+func (d *googleDriveDestination) UploadTarget(userEmail string) string {
+	return "gdrive:" + d.rootFolderName
+}
+
+// CheckUserAccess impersonates the user and finds or creates their root folder
+func (d *googleDriveDestination) CheckUserAccess(ctx context.Context, userEmail string) error {
+	_, err := d.resolveFolder(ctx, userEmail, "")
+	return err
+}
+
+// FileExists checks whether fileName exists in folderPath under the user's root folder
+func (d *googleDriveDestination) FileExists(ctx context.Context, userEmail, folderPath, fileName string) (bool, error) {
+	folderID, err := d.resolveFolder(ctx, userEmail, folderPath)
+	if err != nil {
+		return false, err
+	}
+
+	file, err := d.findChild(ctx, userEmail, folderID, fileName, false)
+	if err != nil {
+		return false, err
+	}
+	return file != nil, nil
+}
+
+// UploadFile uploads a file into folderPath under the user's root folder
+// Existing files are skipped, or have their content replaced when Overwrite is set.
+func (d *googleDriveDestination) UploadFile(ctx context.Context, req UploadRequest) (*UploadResult, error) {
+	fileName := req.FileName
+	if fileName == "" {
+		fileName = filepath.Base(req.LocalPath)
+	}
+
+	folderID, err := d.resolveFolder(ctx, req.UserEmail, req.FolderPath)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := d.findChild(ctx, req.UserEmail, folderID, fileName, false)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && !req.Overwrite {
+		return &UploadResult{FileID: existing.ID, FileSize: parseDriveSize(existing.Size), Skipped: true}, nil
+	}
+
+	file, err := os.Open(req.LocalPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file info: %w", err)
+	}
+
+	var uploaded *driveFile
+	if existing != nil {
+		uploaded, err = d.updateContent(ctx, req.UserEmail, existing.ID, file, info.Size())
+	} else {
+		uploaded, err = d.createFile(ctx, req.UserEmail, folderID, fileName, file, info.Size())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Google Drive upload failed for %s: %w", fileName, err)
+	}
+
+	return &UploadResult{FileID: uploaded.ID, FileSize: info.Size()}, nil
+}
+
+// resolveFolder returns the ID of folderPath below the user's root folder, creating folders as needed
+func (d *googleDriveDestination) resolveFolder(ctx context.Context, userEmail, folderPath string) (string, error) {
+	segments := []string{d.rootFolderName}
+	for _, part := range strings.Split(strings.Trim(folderPath, "/"), "/") {
+		if part != "" {
+			segments = append(segments, part)
+		}
+	}
+
+	parentID := "root"
+	for i := range segments {
+		cacheKey := userEmail + "|" + strings.Join(segments[:i+1], "/")
+
+		d.mu.Lock()
+		cachedID, ok := d.folderCache[cacheKey]
+		d.mu.Unlock()
+		if ok {
+			parentID = cachedID
+			continue
+		}
+
+		folder, err := d.findChild(ctx, userEmail, parentID, segments[i], true)
+		if err != nil {
+			return "", err
+		}
+		if folder == nil {
+			folder, err = d.createFolder(ctx, userEmail, parentID, segments[i])
+			if err != nil {
+				return "", err
+			}
+		}
+
+		d.mu.Lock()
+		d.folderCache[cacheKey] = folder.ID
+		d.mu.Unlock()
+		parentID = folder.ID
+	}
+
+	return parentID, nil
+}
+
+// findChild looks up a file or folder by name in a parent folder
+func (d *googleDriveDestination) findChild(ctx context.Context, userEmail, parentID, name string, folder bool) (*driveFile, error) {
+	query := fmt.Sprintf("name = '%s' and '%s' in parents and trashed = false", escapeDriveQuery(name), parentID)
+	if folder {
+		query += fmt.Sprintf(" and mimeType = '%s'", GoogleDriveFolderMime)
+	} else {
+		query += fmt.Sprintf(" and mimeType != '%s'", GoogleDriveFolderMime)
+	}
+
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("fields", "files(id,name,size)")
+	params.Set("pageSize", "1")
+	params.Set("supportsAllDrives", "true")
+	params.Set("includeItemsFromAllDrives", "true")
+
+	req, err := http.NewRequestWithContext(ctx, "GET", d.apiBaseURL+"/files?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Drive list request: %w", err)
+	}
+
+	var list driveFileList
+	if err := d.doJSON(userEmail, req, &list); err != nil {
+		return nil, fmt.Errorf("failed to search Drive for %s: %w", name, err)
+	}
+	if len(list.Files) == 0 {
+		return nil, nil
+	}
+	return &list.Files[0], nil
+}
+
+// createFolder creates a folder in a parent folder
+func (d *googleDriveDestination) createFolder(ctx context.Context, userEmail, parentID, name string) (*driveFile, error) {
+	body, _ := json.Marshal(map[string]interface{}{
+		"name":     name,
+		"mimeType": GoogleDriveFolderMime,
+		"parents":  []string{parentID},
+	})
+
+	req, err := http.NewRequestWithContext(ctx, "POST", d.apiBaseURL+"/files?fields=id,name&supportsAllDrives=true", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Drive folder request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	var folder driveFile
+	if err := d.doJSON(userEmail, req, &folder); err != nil {
+		return nil, fmt.Errorf("failed to create Drive folder %s: %w", name, err)
+	}
+	return &folder, nil
+}
+
+// createFile uploads a new file using a resumable upload session
+// The file is streamed in a single request, so memory use does not grow with file size.
+func (d *googleDriveDestination) createFile(ctx context.Context, userEmail, parentID, name string, content io.Reader, size int64) (*driveFile, error) {
+	metadata, _ := json.Marshal(map[string]interface{}{
+		"name":    name,
+		"parents": []string{parentID},
+	})
+
+	req, err := http.NewRequestWithContext(ctx, "POST", d.uploadBaseURL+"/files?uploadType=resumable&supportsAllDrives=true", bytes.NewReader(metadata))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create upload session request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
+	req.Header.Set("X-Upload-Content-Length", fmt.Sprintf("%d", size))
+
+	resp, err := d.do(userEmail, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start upload session: %w", err)
+	}
+	resp.Body.Close()
+
+	sessionURL := resp.Header.Get("Location")
+	if sessionURL == "" {
+		return nil, fmt.Errorf("upload session response did not include a Location header")
+	}
+
+	uploadReq, err := http.NewRequestWithContext(ctx, "PUT", sessionURL, content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create upload request: %w", err)
+	}
+	uploadReq.ContentLength = size
+
+	var file driveFile
+	if err := d.doJSON(userEmail, uploadReq, &file); err != nil {
+		return nil, err
+	}
+	return &file, nil
+}
+
+// updateContent replaces the content of an existing file
+func (d *googleDriveDestination) updateContent(ctx context.Context, userEmail, fileID string, content io.Reader, size int64) (*driveFile, error) {
+	req, err := http.NewRequestWithContext(ctx, "PATCH", fmt.Sprintf("%s/files/%s?uploadType=media&supportsAllDrives=true", d.uploadBaseURL, fileID), content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create update request: %w", err)
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	var file driveFile
+	if err := d.doJSON(userEmail, req, &file); err != nil {
+		return nil, err
+	}
+	return &file, nil
+}
+
+// doJSON performs an authenticated request and decodes the JSON response
+func (d *googleDriveDestination) doJSON(userEmail string, req *http.Request, out interface{}) error {
+	resp, err := d.do(userEmail, req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode Drive response: %w", err)
+	}
+	return nil
+}
+
+// do performs a request as the impersonated user and converts error statuses to errors
+func (d *googleDriveDestination) do(userEmail string, req *http.Request) (*http.Response, error) {
+	token, err := d.accessToken(req.Context(), userEmail)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("Drive request failed: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		return nil, fmt.Errorf("Drive API error %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return resp, nil
+}
+
+// accessToken returns a cached or newly minted access token impersonating userEmail
+func (d *googleDriveDestination) accessToken(ctx context.Context, userEmail string) (string, error) {
+	d.mu.Lock()
+	cached := d.tokens[userEmail]
+	d.mu.Unlock()
+	if cached != nil && time.Now().Add(time.Minute).Before(cached.expiresAt) {
+		return cached.AccessToken, nil
+	}
+
+	privateKey, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(d.key.PrivateKey))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse service account private key: %w", err)
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":   d.key.ClientEmail,
+		"sub":   userEmail,
+		"scope": GoogleDriveScope,
+		"aud":   d.tokenURL,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	}
+	assertion, err := jwt.NewWithClaims(jwt.SigningMethodRS256, claims).SignedString(privateKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign service account assertion: %w", err)
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "urn:ietf:params:oauth:grant-type:jwt-bearer")
+	form.Set("assertion", assertion)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", d.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to get Google access token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("failed to get Google access token for %s: status %d: %s", userEmail, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var token driveToken
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("failed to parse token response: %w", err)
+	}
+	token.expiresAt = now.Add(time.Duration(token.ExpiresIn) * time.Second)
+
+	d.mu.Lock()
+	d.tokens[userEmail] = &token
+	d.mu.Unlock()
+
+	return token.AccessToken, nil
+}
+
+// escapeDriveQuery escapes a value for use inside a single-quoted Drive query string
+func escapeDriveQuery(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	return strings.ReplaceAll(value, `'`, `\'`)
+}
+
+// parseDriveSize converts Drive's string-encoded file size
+func parseDriveSize(size string) int64 {
+	var n int64
+	fmt.Sscanf(size, "%d", &n)
+	return n
+}
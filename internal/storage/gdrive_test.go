@@ -0,0 +1,262 @@
+package storage
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// fakeDrive is a minimal in-memory Google Drive API used by the tests
+type fakeDrive struct {
+	mu       sync.Mutex
+	nextID   int
+	files    map[string]fakeDriveFile // id -> file
+	subjects []string                 // impersonated users seen by the token endpoint
+	uploads  map[string][]byte        // id -> uploaded content
+	server   *httptest.Server
+}
+
+type fakeDriveFile struct {
+	Name     string
+	Parent   string
+	IsFolder bool
+}
+
+func newFakeDrive(t *testing.T) *fakeDrive {
+	fd := &fakeDrive{
+		files:   make(map[string]fakeDriveFile),
+		uploads: make(map[string][]byte),
+	}
+	fd.server = httptest.NewServer(http.HandlerFunc(fd.handle))
+	t.Cleanup(fd.server.Close)
+	return fd
+}
+
+func (fd *fakeDrive) handle(w http.ResponseWriter, r *http.Request) {
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+
+	switch {
+	case r.URL.Path == "/token":
+		r.ParseForm()
+		token, _, err := jwt.NewParser().ParseUnverified(r.Form.Get("assertion"), jwt.MapClaims{})
+		if err != nil {
+			http.Error(w, "bad assertion", http.StatusBadRequest)
+			return
+		}
+		sub, _ := token.Claims.(jwt.MapClaims)["sub"].(string)
+		fd.subjects = append(fd.subjects, sub)
+		fmt.Fprintf(w, `{"access_token":"token-%s","token_type":"Bearer","expires_in":3600}`, sub)
+
+	case r.Method == "GET" && r.URL.Path == "/api/files":
+		query := r.URL.Query().Get("q")
+		wantFolder := strings.Contains(query, "mimeType = ")
+		var matches []map[string]string
+		for id, f := range fd.files {
+			nameClause := fmt.Sprintf("name = '%s'", escapeDriveQuery(f.Name))
+			parentClause := fmt.Sprintf("'%s' in parents", f.Parent)
+			if strings.Contains(query, nameClause) && strings.Contains(query, parentClause) && f.IsFolder == wantFolder {
+				matches = append(matches, map[string]string{"id": id, "name": f.Name, "size": fmt.Sprint(len(fd.uploads[id]))})
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"files": matches})
+
+	case r.Method == "POST" && r.URL.Path == "/api/files":
+		var body struct {
+			Name    string   `json:"name"`
+			Parents []string `json:"parents"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		id := fd.newID()
+		fd.files[id] = fakeDriveFile{Name: body.Name, Parent: body.Parents[0], IsFolder: true}
+		json.NewEncoder(w).Encode(map[string]string{"id": id, "name": body.Name})
+
+	case r.Method == "POST" && r.URL.Path == "/upload/files":
+		var body struct {
+			Name    string   `json:"name"`
+			Parents []string `json:"parents"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		id := fd.newID()
+		fd.files[id] = fakeDriveFile{Name: body.Name, Parent: body.Parents[0]}
+		w.Header().Set("Location", fd.server.URL+"/session/"+id)
+
+	case r.Method == "PUT" && strings.HasPrefix(r.URL.Path, "/session/"):
+		id := strings.TrimPrefix(r.URL.Path, "/session/")
+		fd.uploads[id], _ = io.ReadAll(r.Body)
+		json.NewEncoder(w).Encode(map[string]string{"id": id, "name": fd.files[id].Name})
+
+	case r.Method == "PATCH" && strings.HasPrefix(r.URL.Path, "/upload/files/"):
+		id := strings.TrimPrefix(r.URL.Path, "/upload/files/")
+		fd.uploads[id], _ = io.ReadAll(r.Body)
+		json.NewEncoder(w).Encode(map[string]string{"id": id, "name": fd.files[id].Name})
+
+	default:
+		http.Error(w, "unexpected request "+r.Method+" "+r.URL.Path, http.StatusNotFound)
+	}
+}
+
+func (fd *fakeDrive) newID() string {
+	fd.nextID++
+	return fmt.Sprintf("id-%d", fd.nextID)
+}
+
+// pathOf returns the slash-separated folder path of a file id
+func (fd *fakeDrive) pathOf(id string) string {
+	var parts []string
+	for id != "root" && id != "" {
+		f := fd.files[id]
+		parts = append([]string{f.Name}, parts...)
+		id = f.Parent
+	}
+	return strings.Join(parts, "/")
+}
+
+func writeServiceAccountKey(t *testing.T, tokenURL string) string {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	pemKey := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	data, _ := json.Marshal(map[string]string{
+		"type":         "service_account",
+		"client_email": "uploader@project.iam.gserviceaccount.com",
+		"private_key":  string(pemKey),
+		"token_uri":    tokenURL,
+	})
+	path := filepath.Join(t.TempDir(), "service-account.json")
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatalf("Failed to write key: %v", err)
+	}
+	return path
+}
+
+func newTestDriveDestination(t *testing.T, fd *fakeDrive) UploadDestination {
+	dest, err := NewGoogleDriveDestination(GoogleDriveConfig{
+		CredentialsFile: writeServiceAccountKey(t, fd.server.URL+"/token"),
+		APIBaseURL:      fd.server.URL + "/api",
+		UploadBaseURL:   fd.server.URL + "/upload",
+	})
+	if err != nil {
+		t.Fatalf("NewGoogleDriveDestination failed: %v", err)
+	}
+	return dest
+}
+
+func TestGoogleDriveDestination_UploadMirrorsFolderStructure(t *testing.T) {
+	fd := newFakeDrive(t)
+	dest := newTestDriveDestination(t, fd)
+	ctx := context.Background()
+
+	localFile := filepath.Join(t.TempDir(), "standup-0900.mp4")
+	if err := os.WriteFile(localFile, []byte("video bytes"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := dest.CheckUserAccess(ctx, "jane@example.com"); err != nil {
+		t.Fatalf("CheckUserAccess failed: %v", err)
+	}
+
+	result, err := dest.UploadFile(ctx, UploadRequest{
+		LocalPath:  localFile,
+		ZoomEmail:  "jane@example.com",
+		UserEmail:  "jane@example.com",
+		FolderPath: "2024/01/15",
+	})
+	if err != nil {
+		t.Fatalf("UploadFile failed: %v", err)
+	}
+	if result.Skipped {
+		t.Fatal("Expected first upload not to be skipped")
+	}
+
+	if got := fd.pathOf(fd.files[result.FileID].Parent); got != "zoom/2024/01/15" {
+		t.Errorf("Expected file in zoom/2024/01/15, got %s", got)
+	}
+	if string(fd.uploads[result.FileID]) != "video bytes" {
+		t.Errorf("Unexpected uploaded content: %q", fd.uploads[result.FileID])
+	}
+
+	exists, err := dest.FileExists(ctx, "jane@example.com", "2024/01/15", "standup-0900.mp4")
+	if err != nil {
+		t.Fatalf("FileExists failed: %v", err)
+	}
+	if !exists {
+		t.Error("Expected uploaded file to exist")
+	}
+
+	// A second upload of the same file is skipped
+	again, err := dest.UploadFile(ctx, UploadRequest{
+		LocalPath:  localFile,
+		UserEmail:  "jane@example.com",
+		FolderPath: "2024/01/15",
+	})
+	if err != nil {
+		t.Fatalf("Second UploadFile failed: %v", err)
+	}
+	if !again.Skipped || again.FileID != result.FileID {
+		t.Errorf("Expected second upload to be skipped, got %+v", again)
+	}
+
+	// Tokens are minted once per impersonated user and cached
+	if len(fd.subjects) != 1 || fd.subjects[0] != "jane@example.com" {
+		t.Errorf("Expected a single token for jane@example.com, got %v", fd.subjects)
+	}
+}
+
+func TestGoogleDriveDestination_OverwriteReplacesContent(t *testing.T) {
+	fd := newFakeDrive(t)
+	dest := newTestDriveDestination(t, fd)
+	ctx := context.Background()
+
+	csvFile := filepath.Join(t.TempDir(), "uploads.csv")
+	os.WriteFile(csvFile, []byte("v1"), 0644)
+
+	first, err := dest.UploadFile(ctx, UploadRequest{LocalPath: csvFile, UserEmail: "jane@example.com", Overwrite: true})
+	if err != nil {
+		t.Fatalf("UploadFile failed: %v", err)
+	}
+
+	os.WriteFile(csvFile, []byte("v2"), 0644)
+	second, err := dest.UploadFile(ctx, UploadRequest{LocalPath: csvFile, UserEmail: "jane@example.com", Overwrite: true})
+	if err != nil {
+		t.Fatalf("Overwrite UploadFile failed: %v", err)
+	}
+
+	if second.FileID != first.FileID {
+		t.Errorf("Expected overwrite to update file %s, got new file %s", first.FileID, second.FileID)
+	}
+	if string(fd.uploads[first.FileID]) != "v2" {
+		t.Errorf("Expected content v2, got %q", fd.uploads[first.FileID])
+	}
+	if got := fd.pathOf(fd.files[first.FileID].Parent); got != "zoom" {
+		t.Errorf("Expected uploads.csv in the root folder, got %s", got)
+	}
+}
+
+func TestNewGoogleDriveDestination_InvalidCredentials(t *testing.T) {
+	if _, err := NewGoogleDriveDestination(GoogleDriveConfig{}); err == nil {
+		t.Error("Expected error without credentials file")
+	}
+
+	path := filepath.Join(t.TempDir(), "bad.json")
+	os.WriteFile(path, []byte(`{"type":"service_account"}`), 0600)
+	if _, err := NewGoogleDriveDestination(GoogleDriveConfig{CredentialsFile: path}); err == nil {
+		t.Error("Expected error for key without client_email/private_key")
+	}
+}
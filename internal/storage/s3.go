@@ -0,0 +1,252 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+
+	"github.com/curtbushko/zoom-to-box/internal/email"
+)
+
+// S3 destination defaults
+const (
+	DefaultS3KeyTemplate       = "{user}/{year}/{month}/{day}/{filename}"
+	DefaultS3PartSize          = 16 * 1024 * 1024 // Multipart part size for large files
+	DefaultS3UploadConcurrency = 4
+)
+
+// Supported S3 server-side encryption modes
+const (
+	S3EncryptionAES256 = "AES256"
+	S3EncryptionKMS    = "aws:kms"
+)
+
+// S3Config holds settings for the Amazon S3 destination
+// Credentials and (unless set) the region come from the standard AWS chain:
+// environment variables, shared config/credentials files, SSO, web identity and instance roles.
+type S3Config struct {
+	Bucket               string
+	Region               string // Overrides the region from the AWS chain
+	KeyTemplate          string // Object key template (default: {user}/{year}/{month}/{day}/{filename})
+	ServerSideEncryption string // "", AES256 or aws:kms
+	KMSKeyID             string // KMS key for aws:kms encryption (default: the AWS managed key)
+	PartSize             int64  // Multipart part size in bytes (default: 16MB)
+	UploadConcurrency    int    // Parts uploaded in parallel per file (default: 4)
+
+	// Endpoint overrides for S3-compatible services (and tests)
+	Endpoint     string
+	UsePathStyle bool
+}
+
+// s3Destination uploads recordings to an S3 bucket
+// Each user's "root folder" is the key prefix produced by the key template.
+type s3Destination struct {
+	csvTrackers
+
+	client      *s3.Client
+	uploader    *manager.Uploader
+	bucket      string
+	keyTemplate string
+	encryption  types.ServerSideEncryption
+	kmsKeyID    string
+}
+
+// NewS3Destination creates an S3 upload destination
+func NewS3Destination(ctx context.Context, cfg S3Config) (UploadDestination, error) {
+	if cfg.Bucket == "" {
+		return nil, fmt.Errorf("s3 bucket is required")
+	}
+
+	switch cfg.ServerSideEncryption {
+	case "", S3EncryptionAES256, S3EncryptionKMS:
+	default:
+		return nil, fmt.Errorf("unsupported s3 server-side encryption %q", cfg.ServerSideEncryption)
+	}
+	if cfg.KMSKeyID != "" && cfg.ServerSideEncryption != S3EncryptionKMS {
+		return nil, fmt.Errorf("s3 kms key id requires %s server-side encryption", S3EncryptionKMS)
+	}
+
+	var loadOptions []func(*awsconfig.LoadOptions) error
+	if cfg.Region != "" {
+		loadOptions = append(loadOptions, awsconfig.WithRegion(cfg.Region))
+	}
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx, loadOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS configuration: %w", err)
+	}
+
+	client := s3.NewFromConfig(awsCfg, func(o *s3.Options) {
+		if cfg.Endpoint != "" {
+			o.BaseEndpoint = aws.String(cfg.Endpoint)
+			// S3-compatible services often don't support the default request checksums
+			o.RequestChecksumCalculation = aws.RequestChecksumCalculationWhenRequired
+		}
+		o.UsePathStyle = cfg.UsePathStyle
+	})
+
+	partSize := cfg.PartSize
+	if partSize <= 0 {
+		partSize = DefaultS3PartSize
+	}
+	concurrency := cfg.UploadConcurrency
+	if concurrency <= 0 {
+		concurrency = DefaultS3UploadConcurrency
+	}
+
+	keyTemplate := cfg.KeyTemplate
+	if keyTemplate == "" {
+		keyTemplate = DefaultS3KeyTemplate
+	}
+
+	return &s3Destination{
+		client: client,
+		uploader: manager.NewUploader(client, func(u *manager.Uploader) {
+			u.PartSize = partSize
+			u.Concurrency = concurrency
+		}),
+		bucket:      cfg.Bucket,
+		keyTemplate: keyTemplate,
+		encryption:  types.ServerSideEncryption(cfg.ServerSideEncryption),
+		kmsKeyID:    cfg.KMSKeyID,
+	}, nil
+}
+
+// Name returns the destination name
+func (d *s3Destination) Name() string {
+	return "S3"
+}
+
+// UploadTarget identifies the bucket and key layout uploads go to
+func (d *s3Destination) UploadTarget(userEmail string) string {
+	return fmt.Sprintf("s3:%s/%s", d.bucket, d.keyTemplate)
+}
+
+// CheckUserAccess verifies the bucket is reachable with the configured credentials
+// S3 has no per-user folders to look up, so the check is the same for every user.
+func (d *s3Destination) CheckUserAccess(ctx context.Context, userEmail string) error {
+	if _, err := d.client.HeadBucket(ctx, &s3.HeadBucketInput{Bucket: aws.String(d.bucket)}); err != nil {
+		return fmt.Errorf("cannot access S3 bucket %s: %w", d.bucket, err)
+	}
+	return nil
+}
+
+// FileExists checks whether the object for fileName in folderPath exists
+func (d *s3Destination) FileExists(ctx context.Context, userEmail, folderPath, fileName string) (bool, error) {
+	head, err := d.headObject(ctx, d.objectKey(userEmail, folderPath, fileName))
+	if err != nil {
+		return false, err
+	}
+	return head != nil, nil
+}
+
+// UploadFile uploads a file to the key rendered from the key template
+// Large files are sent as multipart uploads with parts uploaded in parallel.
+func (d *s3Destination) UploadFile(ctx context.Context, req UploadRequest) (*UploadResult, error) {
+	fileName := req.FileName
+	if fileName == "" {
+		fileName = filepath.Base(req.LocalPath)
+	}
+	key := d.objectKey(req.UserEmail, req.FolderPath, fileName)
+
+	if !req.Overwrite {
+		head, err := d.headObject(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		if head != nil {
+			return &UploadResult{FileID: key, FileSize: aws.ToInt64(head.ContentLength), Skipped: true}, nil
+		}
+	}
+
+	file, err := os.Open(req.LocalPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file info: %w", err)
+	}
+
+	input := &s3.PutObjectInput{
+		Bucket: aws.String(d.bucket),
+		Key:    aws.String(key),
+		Body:   file,
+	}
+	if d.encryption != "" {
+		input.ServerSideEncryption = d.encryption
+	}
+	if d.kmsKeyID != "" {
+		input.SSEKMSKeyId = aws.String(d.kmsKeyID)
+	}
+
+	if _, err := d.uploader.Upload(ctx, input); err != nil {
+		return nil, fmt.Errorf("failed to upload %s to s3://%s/%s: %w", fileName, d.bucket, key, err)
+	}
+
+	return &UploadResult{FileID: key, FileSize: info.Size()}, nil
+}
+
+// headObject returns the object's metadata, or nil if the object does not exist
+func (d *s3Destination) headObject(ctx context.Context, key string) (*s3.HeadObjectOutput, error) {
+	head, err := d.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(d.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		var notFound *types.NotFound
+		var respErr *awshttp.ResponseError
+		if errors.As(err, &notFound) || (errors.As(err, &respErr) && respErr.HTTPStatusCode() == http.StatusNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to check s3://%s/%s: %w", d.bucket, key, err)
+	}
+	return head, nil
+}
+
+// objectKey renders the key template for a file
+// folderPath is the <year>/<month>/<day> path used by the other destinations; files for the
+// user's root folder (empty folderPath) drop the date segments from the key.
+func (d *s3Destination) objectKey(userEmail, folderPath, fileName string) string {
+	return RenderS3Key(d.keyTemplate, userEmail, folderPath, fileName)
+}
+
+// RenderS3Key expands a key template. Supported placeholders are {user} (the part of the
+// email before @), {email}, {year}, {month}, {day} and {filename}. Empty path segments
+// are removed so root-level files such as uploads.csv land directly under the user prefix.
+func RenderS3Key(template, userEmail, folderPath, fileName string) string {
+	var year, month, day string
+	if parts := strings.Split(folderPath, "/"); len(parts) == 3 {
+		year, month, day = parts[0], parts[1], parts[2]
+	}
+
+	key := strings.NewReplacer(
+		"{user}", email.ExtractUsername(userEmail),
+		"{email}", userEmail,
+		"{year}", year,
+		"{month}", month,
+		"{day}", day,
+		"{filename}", fileName,
+	).Replace(template)
+
+	segments := strings.Split(key, "/")
+	kept := segments[:0]
+	for _, segment := range segments {
+		if segment != "" {
+			kept = append(kept, segment)
+		}
+	}
+	return strings.Join(kept, "/")
+}
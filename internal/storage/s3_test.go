@@ -0,0 +1,234 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeS3 is a minimal path-style S3 API supporting single and multipart uploads
+type fakeS3 struct {
+	mu         sync.Mutex
+	objects    map[string][]byte            // key -> content
+	headers    map[string]http.Header       // key -> PUT/CreateMultipartUpload headers
+	multiparts map[string]map[string][]byte // upload id -> part number -> content
+	nextID     int
+	server     *httptest.Server
+}
+
+func newFakeS3(t *testing.T) *fakeS3 {
+	fs := &fakeS3{
+		objects:    make(map[string][]byte),
+		headers:    make(map[string]http.Header),
+		multiparts: make(map[string]map[string][]byte),
+	}
+	fs.server = httptest.NewServer(http.HandlerFunc(fs.handle))
+	t.Cleanup(fs.server.Close)
+	return fs
+}
+
+func (fs *fakeS3) handle(w http.ResponseWriter, r *http.Request) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	// Path style: /<bucket>/<key>
+	path := strings.TrimPrefix(r.URL.Path, "/")
+	bucket, key, _ := strings.Cut(path, "/")
+	if bucket != "recordings" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	query := r.URL.Query()
+
+	switch {
+	case r.Method == "HEAD" && key == "":
+		w.WriteHeader(http.StatusOK)
+
+	case r.Method == "HEAD":
+		content, ok := fs.objects[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Length", fmt.Sprint(len(content)))
+		w.WriteHeader(http.StatusOK)
+
+	case r.Method == "POST" && query.Has("uploads"):
+		fs.nextID++
+		uploadID := fmt.Sprintf("upload-%d", fs.nextID)
+		fs.multiparts[uploadID] = make(map[string][]byte)
+		fs.headers[key] = r.Header.Clone()
+		fmt.Fprintf(w, `<InitiateMultipartUploadResult><Bucket>%s</Bucket><Key>%s</Key><UploadId>%s</UploadId></InitiateMultipartUploadResult>`, bucket, key, uploadID)
+
+	case r.Method == "PUT" && query.Has("uploadId"):
+		body, _ := io.ReadAll(r.Body)
+		fs.multiparts[query.Get("uploadId")][query.Get("partNumber")] = body
+		w.Header().Set("ETag", fmt.Sprintf(`"etag-%s"`, query.Get("partNumber")))
+		w.WriteHeader(http.StatusOK)
+
+	case r.Method == "POST" && query.Has("uploadId"):
+		parts := fs.multiparts[query.Get("uploadId")]
+		numbers := make([]int, 0, len(parts))
+		for number := range parts {
+			n, _ := strconv.Atoi(number)
+			numbers = append(numbers, n)
+		}
+		sort.Ints(numbers)
+		var content bytes.Buffer
+		for _, number := range numbers {
+			content.Write(parts[strconv.Itoa(number)])
+		}
+		fs.objects[key] = content.Bytes()
+		fmt.Fprintf(w, `<CompleteMultipartUploadResult><Bucket>%s</Bucket><Key>%s</Key><ETag>"done"</ETag></CompleteMultipartUploadResult>`, bucket, key)
+
+	case r.Method == "PUT":
+		fs.objects[key], _ = io.ReadAll(r.Body)
+		fs.headers[key] = r.Header.Clone()
+		w.Header().Set("ETag", `"etag"`)
+		w.WriteHeader(http.StatusOK)
+
+	default:
+		http.Error(w, "unexpected request "+r.Method+" "+r.URL.String(), http.StatusBadRequest)
+	}
+}
+
+func newTestS3Destination(t *testing.T, fs *fakeS3, cfg S3Config) UploadDestination {
+	t.Setenv("AWS_ACCESS_KEY_ID", "test-access-key")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "test-secret-key")
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(t.TempDir(), "config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(t.TempDir(), "credentials"))
+
+	cfg.Bucket = "recordings"
+	cfg.Region = "us-east-1"
+	cfg.Endpoint = fs.server.URL
+	cfg.UsePathStyle = true
+
+	dest, err := NewS3Destination(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("NewS3Destination failed: %v", err)
+	}
+	return dest
+}
+
+func TestS3Destination_UploadUsesKeyTemplate(t *testing.T) {
+	fs := newFakeS3(t)
+	dest := newTestS3Destination(t, fs, S3Config{
+		KeyTemplate:          "archive/{user}/{year}-{month}/{day}/{filename}",
+		ServerSideEncryption: S3EncryptionKMS,
+		KMSKeyID:             "alias/recordings",
+	})
+	ctx := context.Background()
+
+	if err := dest.CheckUserAccess(ctx, "jane@example.com"); err != nil {
+		t.Fatalf("CheckUserAccess failed: %v", err)
+	}
+
+	localFile := filepath.Join(t.TempDir(), "standup-0900.mp4")
+	os.WriteFile(localFile, []byte("video bytes"), 0644)
+
+	result, err := dest.UploadFile(ctx, UploadRequest{
+		LocalPath:  localFile,
+		UserEmail:  "jane@example.com",
+		FolderPath: "2024/01/15",
+	})
+	if err != nil {
+		t.Fatalf("UploadFile failed: %v", err)
+	}
+
+	expectedKey := "archive/jane/2024-01/15/standup-0900.mp4"
+	if result.FileID != expectedKey || result.Skipped {
+		t.Errorf("Expected upload to %s, got %+v", expectedKey, result)
+	}
+	if string(fs.objects[expectedKey]) != "video bytes" {
+		t.Errorf("Unexpected object content: %q", fs.objects[expectedKey])
+	}
+	if got := fs.headers[expectedKey].Get("X-Amz-Server-Side-Encryption"); got != "aws:kms" {
+		t.Errorf("Expected aws:kms encryption header, got %q", got)
+	}
+	if got := fs.headers[expectedKey].Get("X-Amz-Server-Side-Encryption-Aws-Kms-Key-Id"); got != "alias/recordings" {
+		t.Errorf("Expected KMS key header, got %q", got)
+	}
+
+	exists, err := dest.FileExists(ctx, "jane@example.com", "2024/01/15", "standup-0900.mp4")
+	if err != nil || !exists {
+		t.Errorf("Expected uploaded file to exist, got %v (err: %v)", exists, err)
+	}
+
+	again, err := dest.UploadFile(ctx, UploadRequest{LocalPath: localFile, UserEmail: "jane@example.com", FolderPath: "2024/01/15"})
+	if err != nil {
+		t.Fatalf("Second UploadFile failed: %v", err)
+	}
+	if !again.Skipped || again.FileSize != int64(len("video bytes")) {
+		t.Errorf("Expected second upload to be skipped, got %+v", again)
+	}
+}
+
+func TestS3Destination_MultipartUpload(t *testing.T) {
+	fs := newFakeS3(t)
+	dest := newTestS3Destination(t, fs, S3Config{PartSize: 5 * 1024 * 1024})
+
+	content := bytes.Repeat([]byte("0123456789"), 1100*1024) // ~11MB, three parts
+	localFile := filepath.Join(t.TempDir(), "long-meeting.mp4")
+	os.WriteFile(localFile, content, 0644)
+
+	result, err := dest.UploadFile(context.Background(), UploadRequest{
+		LocalPath:  localFile,
+		UserEmail:  "jane@example.com",
+		FolderPath: "2024/02/01",
+	})
+	if err != nil {
+		t.Fatalf("UploadFile failed: %v", err)
+	}
+
+	if len(fs.multiparts) != 1 {
+		t.Errorf("Expected one multipart upload, got %d", len(fs.multiparts))
+	}
+	if !bytes.Equal(fs.objects[result.FileID], content) {
+		t.Errorf("Reassembled object does not match (got %d bytes, want %d)", len(fs.objects[result.FileID]), len(content))
+	}
+}
+
+func TestRenderS3Key(t *testing.T) {
+	tests := []struct {
+		name       string
+		template   string
+		folderPath string
+		fileName   string
+		expected   string
+	}{
+		{"default template", DefaultS3KeyTemplate, "2024/01/15", "a.mp4", "jane/2024/01/15/a.mp4"},
+		{"root file drops date segments", DefaultS3KeyTemplate, "", "uploads.csv", "jane/uploads.csv"},
+		{"email placeholder", "zoom/{email}/{filename}", "2024/01/15", "a.mp4", "zoom/jane@example.com/a.mp4"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RenderS3Key(tt.template, "jane@example.com", tt.folderPath, tt.fileName); got != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestNewS3Destination_InvalidConfig(t *testing.T) {
+	ctx := context.Background()
+	if _, err := NewS3Destination(ctx, S3Config{}); err == nil {
+		t.Error("Expected error without bucket")
+	}
+	if _, err := NewS3Destination(ctx, S3Config{Bucket: "b", ServerSideEncryption: "rot13"}); err == nil {
+		t.Error("Expected error for unsupported encryption")
+	}
+	if _, err := NewS3Destination(ctx, S3Config{Bucket: "b", KMSKeyID: "key"}); err == nil {
+		t.Error("Expected error for KMS key without aws:kms encryption")
+	}
+}
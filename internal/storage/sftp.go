@@ -0,0 +1,265 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/curtbushko/zoom-to-box/internal/email"
+)
+
+// SFTP destination defaults
+const (
+	DefaultSFTPPort     = 22
+	DefaultSFTPRootPath = "zoom"
+	DefaultSFTPCommand  = "sftp"
+)
+
+// SFTPConfig holds settings for the SFTP destination
+// Transfers run through the OpenSSH sftp client in batch mode: the server's host key must already be
+// in the known hosts file and only key-based authentication is attempted.
+type SFTPConfig struct {
+	Host           string
+	Port           int    // SSH port (default: 22)
+	User           string // Remote login
+	PrivateKeyFile string // Private key used to log in
+	KnownHostsFile string // Known hosts file the server's host key is verified against (default: ~/.ssh/known_hosts)
+	RootPath       string // Remote directory holding the user folders, relative to the login directory unless absolute (default: zoom)
+	Command        string // sftp client to run (default: sftp)
+}
+
+// sftpDestination uploads recordings to an SFTP server, e.g. an on-prem NAS
+// Each user's root folder is <root_path>/<username> with <year>/<month>/<day> folders below it.
+type sftpDestination struct {
+	csvTrackers
+
+	cfg SFTPConfig
+
+	// run executes an sftp batch script and returns its output (replaced by tests)
+	run func(ctx context.Context, batch string) (string, error)
+}
+
+// NewSFTPDestination creates an SFTP upload destination
+func NewSFTPDestination(cfg SFTPConfig) (UploadDestination, error) {
+	if cfg.Host == "" {
+		return nil, fmt.Errorf("sftp host is required")
+	}
+	if cfg.User == "" {
+		return nil, fmt.Errorf("sftp user is required")
+	}
+	if cfg.PrivateKeyFile == "" {
+		return nil, fmt.Errorf("sftp private key file is required")
+	}
+	if _, err := os.Stat(cfg.PrivateKeyFile); err != nil {
+		return nil, fmt.Errorf("cannot read sftp private key file: %w", err)
+	}
+	if cfg.KnownHostsFile != "" {
+		if _, err := os.Stat(cfg.KnownHostsFile); err != nil {
+			return nil, fmt.Errorf("cannot read sftp known hosts file: %w", err)
+		}
+	}
+
+	if cfg.Port == 0 {
+		cfg.Port = DefaultSFTPPort
+	}
+	if cfg.RootPath == "" {
+		cfg.RootPath = DefaultSFTPRootPath
+	}
+	if cfg.Command == "" {
+		cfg.Command = DefaultSFTPCommand
+	}
+	if _, err := exec.LookPath(cfg.Command); err != nil {
+		return nil, fmt.Errorf("sftp client %q not found: %w", cfg.Command, err)
+	}
+
+	d := &sftpDestination{cfg: cfg}
+	d.run = d.runBatch
+	return d, nil
+}
+
+// Name returns the destination name
+func (d *sftpDestination) Name() string {
+	return "SFTP"
+}
+
+// UploadTarget identifies the server and root path uploads go to
+func (d *sftpDestination) UploadTarget(userEmail string) string {
+	return fmt.Sprintf("sftp:%s@%s:%d/%s", d.cfg.User, d.cfg.Host, d.cfg.Port, d.cfg.RootPath)
+}
+
+// CheckUserAccess creates the user's root folder if needed and checks it can be entered,
+// which also verifies the host key and login
+func (d *sftpDestination) CheckUserAccess(ctx context.Context, userEmail string) error {
+	userRoot, err := d.remotePath(userEmail, "", "")
+	if err != nil {
+		return err
+	}
+
+	batch := d.mkdirCommands(userEmail, "") + "cd " + sftpQuote(userRoot) + "\n"
+	if _, err := d.run(ctx, batch); err != nil {
+		return fmt.Errorf("cannot access SFTP folder %s: %w", userRoot, err)
+	}
+	return nil
+}
+
+// FileExists checks whether fileName exists in folderPath under the user's root folder
+func (d *sftpDestination) FileExists(ctx context.Context, userEmail, folderPath, fileName string) (bool, error) {
+	_, exists, err := d.ExistingFileSize(ctx, userEmail, folderPath, fileName)
+	return exists, err
+}
+
+// ExistingFileSize returns the size of fileName in folderPath under the user's root folder
+func (d *sftpDestination) ExistingFileSize(ctx context.Context, userEmail, folderPath, fileName string) (int64, bool, error) {
+	remote, err := d.remotePath(userEmail, folderPath, fileName)
+	if err != nil {
+		return 0, false, err
+	}
+
+	// A leading "-" keeps the batch going when the file does not exist
+	output, err := d.run(ctx, "-ls -ln "+sftpQuote(remote)+"\n")
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to look up %s: %w", remote, err)
+	}
+	size, exists := parseSFTPListing(output)
+	return size, exists, nil
+}
+
+// UploadFile creates the date folders and uploads the file under a temporary name, then renames it,
+// so an interrupted transfer never leaves a partial file under the final name
+func (d *sftpDestination) UploadFile(ctx context.Context, req UploadRequest) (*UploadResult, error) {
+	fileName := req.FileName
+	if fileName == "" {
+		fileName = filepath.Base(req.LocalPath)
+	}
+	remote, err := d.remotePath(req.UserEmail, req.FolderPath, fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	if !req.Overwrite {
+		size, exists, err := d.ExistingFileSize(ctx, req.UserEmail, req.FolderPath, fileName)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			return &UploadResult{FileID: remote, FileSize: size, Skipped: true}, nil
+		}
+	}
+
+	info, err := os.Stat(req.LocalPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file info: %w", err)
+	}
+
+	partial := remote + ".partial"
+	var batch strings.Builder
+	batch.WriteString(d.mkdirCommands(req.UserEmail, req.FolderPath))
+	batch.WriteString("put " + sftpQuote(req.LocalPath) + " " + sftpQuote(partial) + "\n")
+	if req.Overwrite {
+		batch.WriteString("-rm " + sftpQuote(remote) + "\n")
+	}
+	batch.WriteString("rename " + sftpQuote(partial) + " " + sftpQuote(remote) + "\n")
+
+	if _, err := d.run(ctx, batch.String()); err != nil {
+		return nil, fmt.Errorf("failed to upload %s to SFTP: %w", fileName, err)
+	}
+	if req.Progress != nil {
+		req.Progress(info.Size(), info.Size())
+	}
+
+	return &UploadResult{FileID: remote, FileSize: info.Size()}, nil
+}
+
+// mkdirCommands returns batch commands creating the user's root folder and each folder of folderPath;
+// existing folders are ignored
+func (d *sftpDestination) mkdirCommands(userEmail, folderPath string) string {
+	dir := d.cfg.RootPath
+	var commands strings.Builder
+	commands.WriteString("-mkdir " + sftpQuote(dir) + "\n")
+
+	segments := append([]string{email.ExtractUsername(userEmail)}, strings.Split(folderPath, "/")...)
+	for _, segment := range segments {
+		if segment == "" {
+			continue
+		}
+		dir = path.Join(dir, segment)
+		commands.WriteString("-mkdir " + sftpQuote(dir) + "\n")
+	}
+	return commands.String()
+}
+
+// remotePath returns the remote path of fileName in folderPath under the user's root folder
+func (d *sftpDestination) remotePath(userEmail, folderPath, fileName string) (string, error) {
+	username := email.ExtractUsername(userEmail)
+	if username == "" {
+		return "", fmt.Errorf("invalid user email: %s", userEmail)
+	}
+	return path.Join(d.cfg.RootPath, username, folderPath, fileName), nil
+}
+
+// args returns the sftp client arguments: batch commands from stdin, strict host key checking
+// and key-only authentication
+func (d *sftpDestination) args() []string {
+	args := []string{
+		"-b", "-",
+		"-P", strconv.Itoa(d.cfg.Port),
+		"-i", d.cfg.PrivateKeyFile,
+		"-o", "IdentitiesOnly=yes",
+		"-o", "BatchMode=yes",
+		"-o", "PasswordAuthentication=no",
+		"-o", "StrictHostKeyChecking=yes",
+	}
+	if d.cfg.KnownHostsFile != "" {
+		args = append(args, "-o", "UserKnownHostsFile="+d.cfg.KnownHostsFile)
+	}
+	return append(args, d.cfg.User+"@"+d.cfg.Host)
+}
+
+// runBatch runs the sftp client with batch on stdin
+func (d *sftpDestination) runBatch(ctx context.Context, batch string) (string, error) {
+	cmd := exec.CommandContext(ctx, d.cfg.Command, d.args()...)
+	cmd.Stdin = strings.NewReader(batch)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return stdout.String(), fmt.Errorf("%w: %s", err, message)
+		}
+		return stdout.String(), err
+	}
+	return stdout.String(), nil
+}
+
+// parseSFTPListing returns the size of the regular file in "ls -ln" batch output
+func parseSFTPListing(output string) (int64, bool) {
+	for _, line := range strings.Split(output, "\n") {
+		if strings.HasPrefix(line, "sftp>") {
+			continue // Batch mode echoes each command
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 5 || !strings.HasPrefix(fields[0], "-") {
+			continue
+		}
+		size, err := strconv.ParseInt(fields[4], 10, 64)
+		if err != nil {
+			continue
+		}
+		return size, true
+	}
+	return 0, false
+}
+
+// sftpQuote quotes a path for an sftp batch command
+func sftpQuote(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `"`, `\"`)
+	return `"` + value + `"`
+}
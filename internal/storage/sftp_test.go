@@ -0,0 +1,107 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newTestSFTPDestination(run func(ctx context.Context, batch string) (string, error)) *sftpDestination {
+	return &sftpDestination{
+		cfg: SFTPConfig{
+			Host:           "nas.example.com",
+			Port:           2222,
+			User:           "archiver",
+			PrivateKeyFile: "/keys/id_ed25519",
+			KnownHostsFile: "/keys/known_hosts",
+			RootPath:       "/srv/zoom",
+			Command:        DefaultSFTPCommand,
+		},
+		run: run,
+	}
+}
+
+func TestSFTPDestination_Args(t *testing.T) {
+	args := strings.Join(newTestSFTPDestination(nil).args(), " ")
+	for _, want := range []string{
+		"-b -",
+		"-P 2222",
+		"-i /keys/id_ed25519",
+		"BatchMode=yes",
+		"PasswordAuthentication=no",
+		"StrictHostKeyChecking=yes",
+		"UserKnownHostsFile=/keys/known_hosts",
+		"archiver@nas.example.com",
+	} {
+		if !strings.Contains(args, want) {
+			t.Errorf("Expected sftp arguments to contain %q, got %s", want, args)
+		}
+	}
+}
+
+func TestSFTPDestination_UploadFile(t *testing.T) {
+	localPath := filepath.Join(t.TempDir(), "meeting.mp4")
+	if err := os.WriteFile(localPath, []byte("recording"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var batches []string
+	dest := newTestSFTPDestination(func(ctx context.Context, batch string) (string, error) {
+		batches = append(batches, batch)
+		return "sftp> -ls -ln \"/srv/zoom/jane.smith/2024/01/15/meeting.mp4\"\n", nil
+	})
+
+	result, err := dest.UploadFile(context.Background(), UploadRequest{
+		LocalPath:  localPath,
+		UserEmail:  "jane.smith@example.com",
+		FolderPath: "2024/01/15",
+	})
+	if err != nil {
+		t.Fatalf("UploadFile failed: %v", err)
+	}
+	const remote = "/srv/zoom/jane.smith/2024/01/15/meeting.mp4"
+	if result.FileID != remote || result.FileSize != int64(len("recording")) || result.Skipped {
+		t.Errorf("Expected a new upload to %s, got %+v", remote, result)
+	}
+
+	if len(batches) != 2 {
+		t.Fatalf("Expected an existence check and an upload batch, got %d batches", len(batches))
+	}
+	upload := batches[1]
+	for _, want := range []string{
+		`-mkdir "/srv/zoom/jane.smith/2024/01/15"`,
+		`put "` + localPath + `" "` + remote + `.partial"`,
+		`rename "` + remote + `.partial" "` + remote + `"`,
+	} {
+		if !strings.Contains(upload, want) {
+			t.Errorf("Expected the upload batch to contain %q, got:\n%s", want, upload)
+		}
+	}
+}
+
+func TestSFTPDestination_SkipsExistingFile(t *testing.T) {
+	dest := newTestSFTPDestination(func(ctx context.Context, batch string) (string, error) {
+		return "sftp> -ls -ln \"/srv/zoom/jane.smith/2024/01/15/meeting.mp4\"\n" +
+			"-rw-r--r--    1 1000     1000         4096 Jan 15 10:00 /srv/zoom/jane.smith/2024/01/15/meeting.mp4\n", nil
+	})
+
+	result, err := dest.UploadFile(context.Background(), UploadRequest{
+		LocalPath:  "meeting.mp4",
+		UserEmail:  "jane.smith@example.com",
+		FolderPath: "2024/01/15",
+	})
+	if err != nil {
+		t.Fatalf("UploadFile failed: %v", err)
+	}
+	if !result.Skipped || result.FileSize != 4096 {
+		t.Errorf("Expected the existing 4096 byte file to be skipped, got %+v", result)
+	}
+}
+
+func TestSFTPQuote(t *testing.T) {
+	if got, want := sftpQuote(`Team "Sync" \ 1.mp4`), `"Team \"Sync\" \\ 1.mp4"`; got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+}
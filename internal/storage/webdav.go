@@ -0,0 +1,251 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/curtbushko/zoom-to-box/internal/email"
+)
+
+// WebDAVConfig holds settings for the WebDAV destination
+type WebDAVConfig struct {
+	URL        string       // Collection that holds the user folders, e.g. https://nas.example.com/remote.php/dav/files/zoom
+	Username   string       // Basic auth user ("" = no authentication)
+	Password   string       // Basic auth password
+	HTTPClient *http.Client // Optional HTTP client
+}
+
+// webDAVDestination uploads recordings to a WebDAV server, e.g. an on-prem NAS
+// Each user's root folder is <url>/<username> with <year>/<month>/<day> folders below it.
+type webDAVDestination struct {
+	csvTrackers
+
+	baseURL    *url.URL
+	username   string
+	password   string
+	httpClient *http.Client
+
+	mu      sync.Mutex
+	created map[string]bool // Collections known to exist, keyed by path
+}
+
+// NewWebDAVDestination creates a WebDAV upload destination
+func NewWebDAVDestination(cfg WebDAVConfig) (UploadDestination, error) {
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("webdav url is required")
+	}
+	baseURL, err := url.Parse(cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid webdav url %q: %w", cfg.URL, err)
+	}
+	if baseURL.Scheme != "http" && baseURL.Scheme != "https" {
+		return nil, fmt.Errorf("webdav url must use http or https: %s", cfg.URL)
+	}
+	baseURL.Path = strings.TrimSuffix(baseURL.Path, "/")
+
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	return &webDAVDestination{
+		baseURL:    baseURL,
+		username:   cfg.Username,
+		password:   cfg.Password,
+		httpClient: httpClient,
+		created:    make(map[string]bool),
+	}, nil
+}
+
+// Name returns the destination name
+func (d *webDAVDestination) Name() string {
+	return "WebDAV"
+}
+
+// UploadTarget identifies the collection uploads go to
+func (d *webDAVDestination) UploadTarget(userEmail string) string {
+	return "webdav:" + d.baseURL.Redacted()
+}
+
+// CheckUserAccess creates the user's root folder if needed, which also checks the credentials
+func (d *webDAVDestination) CheckUserAccess(ctx context.Context, userEmail string) error {
+	if err := d.ensureCollections(ctx, userEmail, ""); err != nil {
+		return fmt.Errorf("cannot access WebDAV folder for %s: %w", userEmail, err)
+	}
+	return nil
+}
+
+// FileExists checks whether fileName exists in folderPath under the user's root folder
+func (d *webDAVDestination) FileExists(ctx context.Context, userEmail, folderPath, fileName string) (bool, error) {
+	_, exists, err := d.ExistingFileSize(ctx, userEmail, folderPath, fileName)
+	return exists, err
+}
+
+// ExistingFileSize returns the size of fileName in folderPath under the user's root folder
+func (d *webDAVDestination) ExistingFileSize(ctx context.Context, userEmail, folderPath, fileName string) (int64, bool, error) {
+	if email.ExtractUsername(userEmail) == "" {
+		return 0, false, fmt.Errorf("invalid user email: %s", userEmail)
+	}
+	req, err := d.newRequest(ctx, http.MethodHead, d.userPath(userEmail, folderPath, fileName), nil)
+	if err != nil {
+		return 0, false, err
+	}
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return 0, false, fmt.Errorf("webdav HEAD %s failed: %w", req.URL.Path, err)
+	}
+	resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return 0, false, nil
+	case resp.StatusCode >= 300:
+		return 0, false, fmt.Errorf("webdav HEAD %s returned %s", req.URL.Path, resp.Status)
+	}
+	return resp.ContentLength, true, nil
+}
+
+// UploadFile creates the date folders and PUTs the file, skipping it if it already exists
+func (d *webDAVDestination) UploadFile(ctx context.Context, req UploadRequest) (*UploadResult, error) {
+	fileName := req.FileName
+	if fileName == "" {
+		fileName = filepath.Base(req.LocalPath)
+	}
+
+	if !req.Overwrite {
+		size, exists, err := d.ExistingFileSize(ctx, req.UserEmail, req.FolderPath, fileName)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			return &UploadResult{FileID: d.userPath(req.UserEmail, req.FolderPath, fileName), FileSize: size, Skipped: true}, nil
+		}
+	}
+
+	if err := d.ensureCollections(ctx, req.UserEmail, req.FolderPath); err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(req.LocalPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file info: %w", err)
+	}
+
+	var body io.Reader = file
+	if req.Progress != nil {
+		body = &progressReader{reader: file, total: info.Size(), progress: req.Progress}
+	}
+
+	filePath := d.userPath(req.UserEmail, req.FolderPath, fileName)
+	putReq, err := d.newRequest(ctx, http.MethodPut, filePath, body)
+	if err != nil {
+		return nil, err
+	}
+	putReq.ContentLength = info.Size()
+
+	resp, err := d.httpClient.Do(putReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload %s to WebDAV: %w", fileName, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("failed to upload %s to WebDAV: PUT returned %s", fileName, resp.Status)
+	}
+
+	return &UploadResult{FileID: filePath, FileSize: info.Size()}, nil
+}
+
+// ensureCollections creates the user's root folder and each folder of folderPath that is missing
+func (d *webDAVDestination) ensureCollections(ctx context.Context, userEmail, folderPath string) error {
+	if email.ExtractUsername(userEmail) == "" {
+		return fmt.Errorf("invalid user email: %s", userEmail)
+	}
+	collection := d.userPath(userEmail, "", "")
+	parts := []string{collection}
+	for _, segment := range strings.Split(folderPath, "/") {
+		if segment == "" {
+			continue
+		}
+		collection = path.Join(collection, segment)
+		parts = append(parts, collection)
+	}
+
+	for _, collection := range parts {
+		d.mu.Lock()
+		known := d.created[collection]
+		d.mu.Unlock()
+		if known {
+			continue
+		}
+
+		req, err := d.newRequest(ctx, "MKCOL", collection+"/", nil)
+		if err != nil {
+			return err
+		}
+		resp, err := d.httpClient.Do(req)
+		if err != nil {
+			return fmt.Errorf("webdav MKCOL %s failed: %w", collection, err)
+		}
+		resp.Body.Close()
+
+		// 405 Method Not Allowed means the collection already exists
+		if resp.StatusCode >= 300 && resp.StatusCode != http.StatusMethodNotAllowed {
+			return fmt.Errorf("webdav MKCOL %s returned %s", collection, resp.Status)
+		}
+
+		d.mu.Lock()
+		d.created[collection] = true
+		d.mu.Unlock()
+	}
+	return nil
+}
+
+// userPath returns the URL path of fileName in folderPath under the user's root folder
+func (d *webDAVDestination) userPath(userEmail, folderPath, fileName string) string {
+	return path.Join(d.baseURL.Path, "/", email.ExtractUsername(userEmail), folderPath, fileName)
+}
+
+// newRequest creates an authenticated request for the given URL path
+func (d *webDAVDestination) newRequest(ctx context.Context, method, urlPath string, body io.Reader) (*http.Request, error) {
+	target := *d.baseURL
+	target.Path = urlPath
+	req, err := http.NewRequestWithContext(ctx, method, target.String(), body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create webdav request: %w", err)
+	}
+	if d.username != "" {
+		req.SetBasicAuth(d.username, d.password)
+	}
+	return req, nil
+}
+
+// progressReader reports the bytes read so far to an upload ProgressFunc
+type progressReader struct {
+	reader   io.Reader
+	read     int64
+	total    int64
+	progress ProgressFunc
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	if n > 0 {
+		r.read += int64(n)
+		r.progress(r.read, r.total)
+	}
+	return n, err
+}
@@ -0,0 +1,138 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeWebDAV is a minimal WebDAV server supporting MKCOL, HEAD and PUT below /dav
+type fakeWebDAV struct {
+	mu          sync.Mutex
+	collections map[string]bool
+	files       map[string][]byte
+	server      *httptest.Server
+}
+
+func newFakeWebDAV(t *testing.T) *fakeWebDAV {
+	fw := &fakeWebDAV{
+		collections: map[string]bool{"/dav": true},
+		files:       make(map[string][]byte),
+	}
+	fw.server = httptest.NewServer(http.HandlerFunc(fw.handle))
+	t.Cleanup(fw.server.Close)
+	return fw
+}
+
+func (fw *fakeWebDAV) handle(w http.ResponseWriter, r *http.Request) {
+	fw.mu.Lock()
+	defer fw.mu.Unlock()
+
+	if user, pass, ok := r.BasicAuth(); !ok || user != "archiver" || pass != "secret" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	name := strings.TrimSuffix(r.URL.Path, "/")
+	switch r.Method {
+	case "MKCOL":
+		switch {
+		case fw.collections[name]:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		case !fw.collections[path.Dir(name)]:
+			w.WriteHeader(http.StatusConflict)
+		default:
+			fw.collections[name] = true
+			w.WriteHeader(http.StatusCreated)
+		}
+	case http.MethodHead:
+		content, ok := fw.files[name]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+		w.WriteHeader(http.StatusOK)
+	case http.MethodPut:
+		if !fw.collections[path.Dir(name)] {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		fw.files[name] = body
+		w.WriteHeader(http.StatusCreated)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func TestWebDAVDestination_UploadUsesUserDateLayout(t *testing.T) {
+	fw := newFakeWebDAV(t)
+	dest, err := NewWebDAVDestination(WebDAVConfig{URL: fw.server.URL + "/dav/", Username: "archiver", Password: "secret"})
+	if err != nil {
+		t.Fatalf("NewWebDAVDestination failed: %v", err)
+	}
+
+	ctx := context.Background()
+	if err := dest.CheckUserAccess(ctx, "jane.smith@example.com"); err != nil {
+		t.Fatalf("CheckUserAccess failed: %v", err)
+	}
+
+	localPath := filepath.Join(t.TempDir(), "meeting.mp4")
+	if err := os.WriteFile(localPath, []byte("recording"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var reported int64
+	result, err := dest.UploadFile(ctx, UploadRequest{
+		LocalPath:  localPath,
+		UserEmail:  "jane.smith@example.com",
+		FolderPath: "2024/01/15",
+		Progress:   func(uploaded, total int64) { reported = uploaded },
+	})
+	if err != nil {
+		t.Fatalf("UploadFile failed: %v", err)
+	}
+	const want = "/dav/jane.smith/2024/01/15/meeting.mp4"
+	if result.FileID != want || result.Skipped {
+		t.Errorf("Expected a new upload to %s, got %+v", want, result)
+	}
+	if string(fw.files[want]) != "recording" {
+		t.Errorf("Expected the file content on the server, got %q", fw.files[want])
+	}
+	if reported != int64(len("recording")) {
+		t.Errorf("Expected progress to reach %d bytes, got %d", len("recording"), reported)
+	}
+
+	size, exists, err := dest.(FileSizer).ExistingFileSize(ctx, "jane.smith@example.com", "2024/01/15", "meeting.mp4")
+	if err != nil || !exists || size != int64(len("recording")) {
+		t.Errorf("Expected the uploaded file to exist with its size, got size=%d exists=%v err=%v", size, exists, err)
+	}
+
+	again, err := dest.UploadFile(ctx, UploadRequest{LocalPath: localPath, UserEmail: "jane.smith@example.com", FolderPath: "2024/01/15"})
+	if err != nil {
+		t.Fatalf("Second UploadFile failed: %v", err)
+	}
+	if !again.Skipped {
+		t.Error("Expected the second upload to be skipped")
+	}
+}
+
+func TestWebDAVDestination_RejectsBadCredentials(t *testing.T) {
+	fw := newFakeWebDAV(t)
+	dest, err := NewWebDAVDestination(WebDAVConfig{URL: fw.server.URL + "/dav", Username: "archiver", Password: "wrong"})
+	if err != nil {
+		t.Fatalf("NewWebDAVDestination failed: %v", err)
+	}
+	if err := dest.CheckUserAccess(context.Background(), "jane.smith@example.com"); err == nil {
+		t.Error("Expected CheckUserAccess to fail with bad credentials")
+	}
+}
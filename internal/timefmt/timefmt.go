@@ -0,0 +1,107 @@
+// Package timefmt formats timestamps consistently across logs, CSVs, reports and filenames
+// One timezone and one layout are configured at startup; every component that shows a
+// time to people goes through this package so entries from different outputs line up.
+package timefmt
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Named layouts accepted by ParseLayout
+var namedLayouts = map[string]string{
+	"rfc3339":     time.RFC3339,
+	"rfc3339nano": time.RFC3339Nano,
+	"datetime":    "2006-01-02 15:04:05",
+	"datetime-tz": "2006-01-02 15:04:05 MST",
+}
+
+var (
+	mu       sync.RWMutex
+	location = time.UTC
+	layout   = time.RFC3339
+)
+
+// Configure sets the timezone and layout used by In, Now and Format
+// timezone is "UTC" (default), "Local" or an IANA name such as "America/Toronto";
+// format is a name from ParseLayout or a Go time layout.
+func Configure(timezone, format string) error {
+	loc, err := LoadLocation(timezone)
+	if err != nil {
+		return err
+	}
+	l, err := ParseLayout(format)
+	if err != nil {
+		return err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	location = loc
+	layout = l
+	return nil
+}
+
+// LoadLocation resolves a configured timezone name ("" = UTC)
+func LoadLocation(timezone string) (*time.Location, error) {
+	switch strings.ToLower(timezone) {
+	case "", "utc":
+		return time.UTC, nil
+	case "local":
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("unknown timezone %q: %w", timezone, err)
+	}
+	return loc, nil
+}
+
+// ParseLayout resolves a configured format: rfc3339 (default), rfc3339nano, datetime,
+// datetime-tz or a Go layout such as "02 Jan 2006 15:04"
+func ParseLayout(format string) (string, error) {
+	if format == "" {
+		return time.RFC3339, nil
+	}
+	if l, ok := namedLayouts[strings.ToLower(format)]; ok {
+		return l, nil
+	}
+	// A custom layout must at least carry the year so timestamps stay unambiguous
+	if !strings.Contains(format, "2006") {
+		return "", fmt.Errorf("timestamp format %q is neither a known name (rfc3339, rfc3339nano, datetime, datetime-tz) nor a Go layout with a 2006 year", format)
+	}
+	return format, nil
+}
+
+// Location returns the configured timezone
+func Location() *time.Location {
+	mu.RLock()
+	defer mu.RUnlock()
+	return location
+}
+
+// In returns t in the configured timezone
+func In(t time.Time) time.Time {
+	return t.In(Location())
+}
+
+// Now returns the current time in the configured timezone
+func Now() time.Time {
+	return In(time.Now())
+}
+
+// Format renders t in the configured timezone and layout
+func Format(t time.Time) string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return t.In(location).Format(layout)
+}
+
+// Parse reads a timestamp written by Format with the current settings
+func Parse(value string) (time.Time, error) {
+	mu.RLock()
+	defer mu.RUnlock()
+	return time.ParseInLocation(layout, value, location)
+}
@@ -0,0 +1,62 @@
+package timefmt
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormat(t *testing.T) {
+	defer Configure("UTC", "rfc3339")
+
+	meeting := time.Date(2024, 3, 10, 15, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		timezone string
+		format   string
+		want     string
+	}{
+		{name: "defaults", want: "2024-03-10T15:30:00Z"},
+		{name: "named layout", timezone: "UTC", format: "datetime", want: "2024-03-10 15:30:00"},
+		{name: "IANA timezone", timezone: "America/Toronto", format: "rfc3339", want: "2024-03-10T11:30:00-04:00"},
+		{name: "custom layout", timezone: "Asia/Tokyo", format: "02 Jan 2006 15:04", want: "11 Mar 2024 00:30"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Configure(tt.timezone, tt.format); err != nil {
+				t.Fatalf("Configure failed: %v", err)
+			}
+			if got := Format(meeting); got != tt.want {
+				t.Errorf("Format() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIn(t *testing.T) {
+	defer Configure("UTC", "rfc3339")
+
+	if err := Configure("Asia/Tokyo", ""); err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+	// 23:30 UTC is already the next day in Tokyo, which decides the date folder
+	meeting := time.Date(2024, 3, 10, 23, 30, 0, 0, time.UTC)
+	if got := In(meeting); got.Day() != 11 || got.Hour() != 8 {
+		t.Errorf("Expected the meeting in Tokyo time, got %v", got)
+	}
+}
+
+func TestConfigureRejectsInvalidSettings(t *testing.T) {
+	defer Configure("UTC", "rfc3339")
+
+	if err := Configure("Mars/Olympus", ""); err == nil {
+		t.Error("Expected an unknown timezone to be rejected")
+	}
+	if err := Configure("UTC", "15:04"); err == nil {
+		t.Error("Expected a layout without a year to be rejected")
+	}
+	if got := Format(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)); got != "2024-01-02T03:04:05Z" {
+		t.Errorf("Expected rejected settings to leave the defaults, got %q", got)
+	}
+}
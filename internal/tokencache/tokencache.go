@@ -0,0 +1,226 @@
+// Package tokencache stores OAuth access tokens between runs
+// Repeated runs, and long-running modes such as serve, reuse a token until it expires instead of
+// requesting a new one from the token endpoint every time.
+//
+// Tokens are kept in a JSON file readable only by its owner. Each entry is encrypted with
+// AES-GCM using a key derived from the client secret that obtained it, so the file alone does
+// not reveal usable tokens, and rotating the secret simply invalidates the cached entries.
+package tokencache
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// DefaultExpiryBuffer is how long before expiry a cached token is no longer handed out
+const DefaultExpiryBuffer = 5 * time.Minute
+
+// Token is a cached OAuth access token
+type Token struct {
+	AccessToken string    `json:"access_token"`
+	TokenType   string    `json:"token_type"`
+	Scope       string    `json:"scope,omitempty"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// Valid reports whether the token can still be used for at least buffer
+func (t *Token) Valid(buffer time.Duration) bool {
+	return t != nil && t.AccessToken != "" && time.Now().Add(buffer).Before(t.ExpiresAt)
+}
+
+// entry is a sealed token as stored in the cache file
+type entry struct {
+	Nonce      string    `json:"nonce"`
+	Ciphertext string    `json:"ciphertext"`
+	ExpiresAt  time.Time `json:"expires_at"` // Unencrypted so expired entries can be pruned
+}
+
+// FileCache stores tokens in a JSON file
+type FileCache struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileCache creates a token cache backed by the file at path
+// The file and its directory are created on the first Save.
+func NewFileCache(path string) *FileCache {
+	return &FileCache{path: path}
+}
+
+// Path returns the cache file path
+func (c *FileCache) Path() string {
+	return c.path
+}
+
+// Load returns the token cached under name, or nil when there is none, it has expired, or it
+// was sealed with a different secret
+func (c *FileCache) Load(name, secret string) (*Token, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entries, err := c.read()
+	if err != nil {
+		return nil, err
+	}
+	sealed, ok := entries[name]
+	if !ok || !time.Now().Add(DefaultExpiryBuffer).Before(sealed.ExpiresAt) {
+		return nil, nil
+	}
+
+	token, err := open(sealed, name, secret)
+	if err != nil {
+		// Typically a rotated client secret: treat it as a cache miss
+		return nil, nil
+	}
+	return token, nil
+}
+
+// Save caches token under name, sealed with secret, and prunes expired entries
+func (c *FileCache) Save(name, secret string, token Token) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entries, err := c.read()
+	if err != nil {
+		// An unreadable cache is replaced rather than blocking authentication
+		entries = make(map[string]entry)
+	}
+
+	sealed, err := seal(token, name, secret)
+	if err != nil {
+		return err
+	}
+	entries[name] = sealed
+
+	now := time.Now()
+	for key, existing := range entries {
+		if !now.Before(existing.ExpiresAt) {
+			delete(entries, key)
+		}
+	}
+	return c.write(entries)
+}
+
+// Delete removes the token cached under name
+func (c *FileCache) Delete(name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entries, err := c.read()
+	if err != nil {
+		return err
+	}
+	if _, ok := entries[name]; !ok {
+		return nil
+	}
+	delete(entries, name)
+	return c.write(entries)
+}
+
+// read loads the cache file; a missing file is an empty cache
+func (c *FileCache) read() (map[string]entry, error) {
+	data, err := os.ReadFile(c.path)
+	if os.IsNotExist(err) {
+		return make(map[string]entry), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token cache: %w", err)
+	}
+
+	entries := make(map[string]entry)
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse token cache %s: %w", c.path, err)
+	}
+	return entries, nil
+}
+
+// write replaces the cache file atomically with owner-only permissions
+func (c *FileCache) write(entries map[string]entry) error {
+	if err := os.MkdirAll(filepath.Dir(c.path), 0700); err != nil {
+		return fmt.Errorf("failed to create token cache directory: %w", err)
+	}
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode token cache: %w", err)
+	}
+
+	tmpPath := c.path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write token cache: %w", err)
+	}
+	if err := os.Rename(tmpPath, c.path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to replace token cache: %w", err)
+	}
+	return nil
+}
+
+// newAEAD derives the AES-GCM cipher for secret
+func newAEAD(secret string) (cipher.AEAD, error) {
+	key := sha256.Sum256([]byte("zoom-to-box token cache\x00" + secret))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal encrypts token; name is authenticated so entries cannot be swapped between names
+func seal(token Token, name, secret string) (entry, error) {
+	aead, err := newAEAD(secret)
+	if err != nil {
+		return entry{}, fmt.Errorf("failed to create token cache cipher: %w", err)
+	}
+	plaintext, err := json.Marshal(token)
+	if err != nil {
+		return entry{}, fmt.Errorf("failed to encode token: %w", err)
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return entry{}, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	return entry{
+		Nonce:      base64.StdEncoding.EncodeToString(nonce),
+		Ciphertext: base64.StdEncoding.EncodeToString(aead.Seal(nil, nonce, plaintext, []byte(name))),
+		ExpiresAt:  token.ExpiresAt,
+	}, nil
+}
+
+// open decrypts a sealed entry
+func open(sealed entry, name, secret string) (*Token, error) {
+	aead, err := newAEAD(secret)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := base64.StdEncoding.DecodeString(sealed.Nonce)
+	if err != nil {
+		return nil, err
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(sealed.Ciphertext)
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != aead.NonceSize() {
+		return nil, fmt.Errorf("invalid nonce")
+	}
+	plaintext, err := aead.Open(nil, nonce, ciphertext, []byte(name))
+	if err != nil {
+		return nil, err
+	}
+
+	var token Token
+	if err := json.Unmarshal(plaintext, &token); err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
@@ -0,0 +1,107 @@
+package tokencache
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFileCache(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		cache := NewFileCache(filepath.Join(t.TempDir(), "cache", "tokens.json"))
+		token := Token{AccessToken: "access-123", TokenType: "bearer", ExpiresAt: time.Now().Add(time.Hour)}
+
+		if err := cache.Save("zoom:acct:client", "secret", token); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+		loaded, err := cache.Load("zoom:acct:client", "secret")
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+		if loaded == nil || loaded.AccessToken != "access-123" || loaded.TokenType != "bearer" {
+			t.Errorf("Expected cached token, got %+v", loaded)
+		}
+	})
+
+	t.Run("missing file is a miss", func(t *testing.T) {
+		cache := NewFileCache(filepath.Join(t.TempDir(), "tokens.json"))
+		loaded, err := cache.Load("zoom:acct:client", "secret")
+		if err != nil || loaded != nil {
+			t.Errorf("Expected miss, got %+v, %v", loaded, err)
+		}
+	})
+
+	t.Run("wrong secret or name is a miss", func(t *testing.T) {
+		cache := NewFileCache(filepath.Join(t.TempDir(), "tokens.json"))
+		token := Token{AccessToken: "access-123", ExpiresAt: time.Now().Add(time.Hour)}
+		if err := cache.Save("box:ent:client", "old-secret", token); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+
+		if loaded, _ := cache.Load("box:ent:client", "new-secret"); loaded != nil {
+			t.Errorf("Expected miss for a rotated secret, got %+v", loaded)
+		}
+		if loaded, _ := cache.Load("box:other:client", "old-secret"); loaded != nil {
+			t.Errorf("Expected miss for another name, got %+v", loaded)
+		}
+	})
+
+	t.Run("tokens near expiry are a miss and pruned", func(t *testing.T) {
+		cache := NewFileCache(filepath.Join(t.TempDir(), "tokens.json"))
+		if err := cache.Save("soon", "secret", Token{AccessToken: "a", ExpiresAt: time.Now().Add(time.Minute)}); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+		if loaded, _ := cache.Load("soon", "secret"); loaded != nil {
+			t.Errorf("Expected miss within the expiry buffer, got %+v", loaded)
+		}
+
+		if err := cache.Save("expired", "secret", Token{AccessToken: "b", ExpiresAt: time.Now().Add(-time.Minute)}); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+		entries, err := cache.read()
+		if err != nil {
+			t.Fatalf("read failed: %v", err)
+		}
+		if _, ok := entries["expired"]; ok {
+			t.Error("Expected expired entry to be pruned")
+		}
+		if _, ok := entries["soon"]; !ok {
+			t.Error("Expected unexpired entry to be kept")
+		}
+	})
+
+	t.Run("file is private and does not contain the token", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "tokens.json")
+		cache := NewFileCache(path)
+		if err := cache.Save("zoom:acct:client", "secret", Token{AccessToken: "plain-access-token", ExpiresAt: time.Now().Add(time.Hour)}); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("Stat failed: %v", err)
+		}
+		if perm := info.Mode().Perm(); perm != 0600 {
+			t.Errorf("Expected mode 0600, got %o", perm)
+		}
+		data, _ := os.ReadFile(path)
+		if strings.Contains(string(data), "plain-access-token") {
+			t.Error("Expected token to be encrypted in the cache file")
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		cache := NewFileCache(filepath.Join(t.TempDir(), "tokens.json"))
+		if err := cache.Save("name", "secret", Token{AccessToken: "a", ExpiresAt: time.Now().Add(time.Hour)}); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+		if err := cache.Delete("name"); err != nil {
+			t.Fatalf("Delete failed: %v", err)
+		}
+		if loaded, _ := cache.Load("name", "secret"); loaded != nil {
+			t.Errorf("Expected miss after delete, got %+v", loaded)
+		}
+	})
+}
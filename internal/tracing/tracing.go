@@ -0,0 +1,128 @@
+// Package tracing provides OpenTelemetry spans for the download and upload pipeline
+// Spans cover each user, each recording file, each pipeline stage, each download and each
+// HTTP call to Zoom and Box, and are exported over OTLP/HTTP so a long run can be inspected
+// in Jaeger, Tempo or any other OTLP backend. Until Setup is called with tracing enabled, the
+// global no-op tracer is used and instrumentation costs next to nothing.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/curtbushko/zoom-to-box/internal/config"
+)
+
+// TracerName identifies the spans created by zoom-to-box
+const TracerName = "github.com/curtbushko/zoom-to-box"
+
+// Setup installs the global tracer provider that exports spans to the configured OTLP endpoint
+// The returned function flushes the spans still buffered and must be called before exiting.
+// With tracing disabled nothing is installed and the returned function does nothing.
+func Setup(ctx context.Context, cfg config.TracingConfig, version string) (func(context.Context) error, error) {
+	if !cfg.Enabled {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	var opts []otlptracehttp.Option
+	if cfg.Endpoint != "" {
+		// Without an endpoint the exporter reads OTEL_EXPORTER_OTLP_ENDPOINT (default localhost:4318)
+		opts = append(opts, otlptracehttp.WithEndpointURL(cfg.Endpoint))
+	}
+	if len(cfg.Headers) > 0 {
+		opts = append(opts, otlptracehttp.WithHeaders(cfg.Headers))
+	}
+	exporter, err := otlptracehttp.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(cfg.ServiceName),
+		semconv.ServiceVersion(version),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	return provider.Shutdown, nil
+}
+
+// Start starts a span named name as a child of the span in ctx
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(TracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End records err on span, if any, and ends it
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// transport creates a client span for every HTTP request
+type transport struct {
+	base http.RoundTripper
+}
+
+// NewTransport wraps base (nil = http.DefaultTransport) so each request gets its own span
+// The span is a child of the span in the request context, and the trace context is sent
+// along in the traceparent header. Query strings are left out of the span, since they can
+// carry tokens.
+func NewTransport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &transport{base: base}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, span := otel.Tracer(TracerName).Start(req.Context(), "HTTP "+req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.HTTPRequestMethodKey.String(req.Method),
+			semconv.ServerAddress(req.URL.Hostname()),
+			semconv.URLPath(req.URL.Path),
+		))
+
+	req = req.Clone(ctx)
+	// http.Client times out requests to transports it does not know through the deprecated Cancel
+	// channel as well as the context deadline. Leaving the base transport only the context keeps a
+	// timeout reported as context.DeadlineExceeded rather than "request canceled".
+	req.Cancel = nil
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		End(span, err)
+		return nil, err
+	}
+
+	span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode))
+	if resp.StatusCode >= http.StatusBadRequest {
+		span.SetStatus(codes.Error, resp.Status)
+	}
+	span.End()
+	return resp, nil
+}
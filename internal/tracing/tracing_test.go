@@ -0,0 +1,103 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+
+	"github.com/curtbushko/zoom-to-box/internal/config"
+)
+
+func TestSetup_Disabled(t *testing.T) {
+	shutdown, err := Setup(context.Background(), config.TracingConfig{}, "test")
+	if err != nil {
+		t.Fatalf("Setup failed: %v", err)
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Errorf("Shutdown failed: %v", err)
+	}
+}
+
+func TestTransport(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer otel.SetTracerProvider(sdktrace.NewTracerProvider())
+
+	var traceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	ctx, parent := Start(context.Background(), "parent")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/users/me?access_token=secret", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := (&http.Client{Transport: NewTransport(nil)}).Do(req)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	resp.Body.Close()
+	parent.End()
+
+	if traceparent == "" {
+		t.Error("Expected traceparent header to be sent")
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("Expected 2 spans, got %d", len(spans))
+	}
+	span := spans[0]
+	if span.Name() != "HTTP GET" {
+		t.Errorf("Expected span name HTTP GET, got %s", span.Name())
+	}
+	if span.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Error("Expected HTTP span to be a child of the request context span")
+	}
+	attrs := make(map[string]string)
+	for _, attr := range span.Attributes() {
+		attrs[string(attr.Key)] = attr.Value.Emit()
+	}
+	if attrs[string(semconv.HTTPResponseStatusCodeKey)] != "404" {
+		t.Errorf("Expected status code 404, got %q", attrs[string(semconv.HTTPResponseStatusCodeKey)])
+	}
+	if attrs[string(semconv.URLPathKey)] != "/users/me" {
+		t.Errorf("Expected path without query, got %q", attrs[string(semconv.URLPathKey)])
+	}
+}
+
+func TestTransport_KeepsTimeoutError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer server.Close()
+
+	client := &http.Client{Timeout: 50 * time.Millisecond, Transport: NewTransport(nil)}
+	for i := 0; i < 5; i++ {
+		_, err := client.Get(server.URL)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("Expected context.DeadlineExceeded, got %v", err)
+		}
+		if !strings.Contains(err.Error(), "context deadline exceeded") {
+			t.Fatalf("Expected the timeout in the error message, got %v", err)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package tracking
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/curtbushko/zoom-to-box/internal/timefmt"
+)
+
+// SummaryEntry represents one recording file processed by a run
+type SummaryEntry struct {
+	ZoomUser         string
+	MeetingTopic     string
+	RecordingDate    time.Time
+	FileName         string
+	FileSize         int64
+	DownloadDuration time.Duration
+	UploadDuration   time.Duration
+	Status           string // downloaded, uploaded, skipped, filtered or failed
+	Error            string
+}
+
+// summaryHeader is the header of the run summary CSV file
+var summaryHeader = []string{"user", "meeting_topic", "recording_date", "file_name", "file_size", "download_seconds", "upload_seconds", "status", "error"}
+
+// SummaryTracker defines the interface for recording the files of a run
+type SummaryTracker interface {
+	// TrackFile records a processed recording file
+	TrackFile(entry SummaryEntry) error
+}
+
+// RunSummaryCSVTracker manages the consolidated run-summary.csv file
+type RunSummaryCSVTracker struct {
+	filePath string
+	mu       sync.Mutex
+}
+
+// NewRunSummaryCSVTracker creates a run summary tracker writing to filePath
+// The file is started over with a header unless appendRows is set and it already exists,
+// in which case the rows of this run follow those of earlier runs.
+func NewRunSummaryCSVTracker(filePath string, appendRows bool) (*RunSummaryCSVTracker, error) {
+	tracker := &RunSummaryCSVTracker{
+		filePath: filePath,
+	}
+
+	if appendRows {
+		if _, err := os.Stat(filePath); err == nil {
+			return tracker, nil
+		} else if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to check file: %w", err)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create directory: %w", err)
+	}
+	if err := tracker.writeHeader(); err != nil {
+		return nil, fmt.Errorf("failed to write header: %w", err)
+	}
+
+	return tracker, nil
+}
+
+// TrackFile appends a recording file to the run summary CSV file
+func (t *RunSummaryCSVTracker) TrackFile(entry SummaryEntry) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	file, err := os.OpenFile(t.filePath, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open file for append: %w", err)
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	defer writer.Flush()
+
+	var recordingDate string
+	if !entry.RecordingDate.IsZero() {
+		recordingDate = timefmt.Format(entry.RecordingDate)
+	}
+	record := []string{
+		entry.ZoomUser,
+		entry.MeetingTopic,
+		recordingDate,
+		entry.FileName,
+		fmt.Sprintf("%d", entry.FileSize),
+		fmt.Sprintf("%d", int64(entry.DownloadDuration.Seconds())),
+		fmt.Sprintf("%d", int64(entry.UploadDuration.Seconds())),
+		entry.Status,
+		entry.Error,
+	}
+
+	if err := writer.Write(record); err != nil {
+		return fmt.Errorf("failed to write record: %w", err)
+	}
+
+	return writer.Error()
+}
+
+// writeHeader creates the run summary file with only the CSV header
+func (t *RunSummaryCSVTracker) writeHeader() error {
+	file, err := os.Create(t.filePath)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	defer writer.Flush()
+
+	if err := writer.Write(summaryHeader); err != nil {
+		return fmt.Errorf("failed to write header: %w", err)
+	}
+
+	return writer.Error()
+}
@@ -0,0 +1,70 @@
+package tracking
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunSummaryCSVTracker(t *testing.T) {
+	csvPath := filepath.Join(t.TempDir(), "reports", "run-summary.csv")
+	header := "user,meeting_topic,recording_date,file_name,file_size,download_seconds,upload_seconds,status,error\n"
+
+	entry := SummaryEntry{
+		ZoomUser:         "john.doe@company.com",
+		MeetingTopic:     "Team Standup, Weekly",
+		RecordingDate:    time.Date(2024, 1, 15, 15, 0, 0, 0, time.UTC),
+		FileName:         "team-standup-weekly-1500.mp4",
+		FileSize:         1048576,
+		DownloadDuration: 30 * time.Second,
+		UploadDuration:   12 * time.Second,
+		Status:           "uploaded",
+	}
+	row := "john.doe@company.com,\"Team Standup, Weekly\",2024-01-15T15:00:00Z,team-standup-weekly-1500.mp4,1048576,30,12,uploaded,\n"
+	failed := SummaryEntry{ZoomUser: "jane.smith@company.com", Status: "failed", Error: "download failed"}
+	failedRow := "jane.smith@company.com,,,,0,0,0,failed,download failed\n"
+
+	tracker, err := NewRunSummaryCSVTracker(csvPath, false)
+	if err != nil {
+		t.Fatalf("NewRunSummaryCSVTracker failed: %v", err)
+	}
+	if err := tracker.TrackFile(entry); err != nil {
+		t.Fatalf("TrackFile failed: %v", err)
+	}
+	assertFile(t, csvPath, header+row)
+
+	t.Run("appends across runs", func(t *testing.T) {
+		tracker, err := NewRunSummaryCSVTracker(csvPath, true)
+		if err != nil {
+			t.Fatalf("NewRunSummaryCSVTracker failed: %v", err)
+		}
+		if err := tracker.TrackFile(failed); err != nil {
+			t.Fatalf("TrackFile failed: %v", err)
+		}
+		assertFile(t, csvPath, header+row+failedRow)
+	})
+
+	t.Run("overwrites earlier runs", func(t *testing.T) {
+		tracker, err := NewRunSummaryCSVTracker(csvPath, false)
+		if err != nil {
+			t.Fatalf("NewRunSummaryCSVTracker failed: %v", err)
+		}
+		if err := tracker.TrackFile(failed); err != nil {
+			t.Fatalf("TrackFile failed: %v", err)
+		}
+		assertFile(t, csvPath, header+failedRow)
+	})
+}
+
+func assertFile(t *testing.T, path, want string) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", path, err)
+	}
+	if got := string(data); got != want {
+		t.Errorf("Expected:\n%s\ngot:\n%s", want, strings.TrimSpace(got))
+	}
+}
@@ -5,8 +5,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"sync"
 	"time"
+
+	"github.com/curtbushko/zoom-to-box/internal/timefmt"
 )
 
 // UploadEntry represents a single upload record
@@ -16,8 +19,12 @@ type UploadEntry struct {
 	RecordingSize  int64
 	UploadDate     time.Time
 	ProcessingTime time.Duration
+	SharedLink     string // Shared link to the uploaded file ("" = none)
 }
 
+// csvHeader is the header of the global and per-user uploads CSV files
+var csvHeader = []string{"user", "file_name", "recording_size", "upload_date", "processing_time_seconds", "shared_link"}
+
 // CSVTracker defines the interface for tracking uploads to CSV files
 type CSVTracker interface {
 	// TrackUpload records an upload entry to the CSV file
@@ -59,6 +66,8 @@ func NewGlobalCSVTracker(filePath string) (*GlobalCSVTracker, error) {
 		}
 	} else if err != nil {
 		return nil, fmt.Errorf("failed to check file: %w", err)
+	} else if err := upgradeHeader(filePath); err != nil {
+		return nil, err
 	}
 
 	return tracker, nil
@@ -88,6 +97,8 @@ func NewUserCSVTracker(userDir string, zoomUser string) (*UserCSVTracker, error)
 		}
 	} else if err != nil {
 		return nil, fmt.Errorf("failed to check file: %w", err)
+	} else if err := upgradeHeader(filePath); err != nil {
+		return nil, err
 	}
 
 	return tracker, nil
@@ -120,8 +131,7 @@ func (t *GlobalCSVTracker) writeHeader() error {
 	writer := csv.NewWriter(file)
 	defer writer.Flush()
 
-	header := []string{"user", "file_name", "recording_size", "upload_date", "processing_time_seconds"}
-	if err := writer.Write(header); err != nil {
+	if err := writer.Write(csvHeader); err != nil {
 		return fmt.Errorf("failed to write header: %w", err)
 	}
 
@@ -139,8 +149,7 @@ func (t *UserCSVTracker) writeHeader() error {
 	writer := csv.NewWriter(file)
 	defer writer.Flush()
 
-	header := []string{"user", "file_name", "recording_size", "upload_date", "processing_time_seconds"}
-	if err := writer.Write(header); err != nil {
+	if err := writer.Write(csvHeader); err != nil {
 		return fmt.Errorf("failed to write header: %w", err)
 	}
 
@@ -162,8 +171,9 @@ func (t *GlobalCSVTracker) appendEntry(entry UploadEntry) error {
 		entry.ZoomUser,
 		entry.FileName,
 		fmt.Sprintf("%d", entry.RecordingSize),
-		entry.UploadDate.Format(time.RFC3339),
+		timefmt.Format(entry.UploadDate),
 		fmt.Sprintf("%d", int64(entry.ProcessingTime.Seconds())),
+		entry.SharedLink,
 	}
 
 	if err := writer.Write(record); err != nil {
@@ -188,8 +198,9 @@ func (t *UserCSVTracker) appendEntry(entry UploadEntry) error {
 		entry.ZoomUser,
 		entry.FileName,
 		fmt.Sprintf("%d", entry.RecordingSize),
-		entry.UploadDate.Format(time.RFC3339),
+		timefmt.Format(entry.UploadDate),
 		fmt.Sprintf("%d", int64(entry.ProcessingTime.Seconds())),
+		entry.SharedLink,
 	}
 
 	if err := writer.Write(record); err != nil {
@@ -198,3 +209,89 @@ func (t *UserCSVTracker) appendEntry(entry UploadEntry) error {
 
 	return writer.Error()
 }
+
+// ReadUploads reads the entries of a global or per-user uploads CSV file
+// Upload dates written with a different timestamp format than the current one are left zero.
+func ReadUploads(filePath string) ([]UploadEntry, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open uploads file: %w", err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read uploads file %s: %w", filePath, err)
+	}
+
+	var entries []UploadEntry
+	for i, record := range records {
+		if i == 0 || len(record) < 3 {
+			continue // Header or malformed line
+		}
+		entry := UploadEntry{ZoomUser: record[0], FileName: record[1]}
+		entry.RecordingSize, _ = strconv.ParseInt(record[2], 10, 64)
+		if len(record) > 3 {
+			entry.UploadDate, _ = timefmt.Parse(record[3])
+		}
+		if len(record) > 4 {
+			seconds, _ := strconv.ParseInt(record[4], 10, 64)
+			entry.ProcessingTime = time.Duration(seconds) * time.Second
+		}
+		if len(record) > 5 {
+			entry.SharedLink = record[5]
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// upgradeHeader adds the columns introduced since filePath was created, so rows appended by this
+// version line up with the header. Existing rows get empty values for the new columns.
+func upgradeHeader(filePath string) error {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to open file: %w", err)
+	}
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	file.Close()
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", filePath, err)
+	}
+	if len(records) == 0 || len(records[0]) >= len(csvHeader) {
+		return nil
+	}
+
+	records[0] = csvHeader
+	for i := 1; i < len(records); i++ {
+		for len(records[i]) < len(csvHeader) {
+			records[i] = append(records[i], "")
+		}
+	}
+
+	tmpPath := filePath + ".tmp"
+	out, err := os.Create(tmpPath)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	writer := csv.NewWriter(out)
+	writer.WriteAll(records)
+	if err := writer.Error(); err != nil {
+		out.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to upgrade header of %s: %w", filePath, err)
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to upgrade header of %s: %w", filePath, err)
+	}
+	if err := os.Rename(tmpPath, filePath); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to upgrade header of %s: %w", filePath, err)
+	}
+	return nil
+}
@@ -26,7 +26,7 @@ func TestNewGlobalCSVTracker(t *testing.T) {
 		t.Fatalf("Failed to read CSV file: %v", err)
 	}
 
-	expected := "user,file_name,recording_size,upload_date,processing_time_seconds\n"
+	expected := "user,file_name,recording_size,upload_date,processing_time_seconds,shared_link\n"
 	if string(data) != expected {
 		t.Errorf("Expected header %q, got %q", expected, string(data))
 	}
@@ -62,7 +62,7 @@ func TestGlobalCSVTracker_TrackUpload(t *testing.T) {
 		t.Fatalf("Failed to read CSV file: %v", err)
 	}
 
-	expectedContent := "user,file_name,recording_size,upload_date,processing_time_seconds\njohn.doe@company.com,team-standup-meeting-1500.mp4,1048576,2024-01-15T15:00:00Z,45\n"
+	expectedContent := "user,file_name,recording_size,upload_date,processing_time_seconds,shared_link\njohn.doe@company.com,team-standup-meeting-1500.mp4,1048576,2024-01-15T15:00:00Z,45,\n"
 	if string(data) != expectedContent {
 		t.Errorf("Expected content:\n%s\nGot:\n%s", expectedContent, string(data))
 	}
@@ -145,7 +145,7 @@ func TestNewUserCSVTracker(t *testing.T) {
 		t.Fatalf("Failed to read CSV file: %v", err)
 	}
 
-	expected := "user,file_name,recording_size,upload_date,processing_time_seconds\n"
+	expected := "user,file_name,recording_size,upload_date,processing_time_seconds,shared_link\n"
 	if string(data) != expected {
 		t.Errorf("Expected header %q, got %q", expected, string(data))
 	}
@@ -186,7 +186,7 @@ func TestUserCSVTracker_TrackUpload(t *testing.T) {
 		t.Fatalf("Failed to read CSV file: %v", err)
 	}
 
-	expectedContent := "user,file_name,recording_size,upload_date,processing_time_seconds\njohn.doe@company.com,team-standup-meeting-1500.mp4,1048576,2024-01-15T15:00:00Z,52\n"
+	expectedContent := "user,file_name,recording_size,upload_date,processing_time_seconds,shared_link\njohn.doe@company.com,team-standup-meeting-1500.mp4,1048576,2024-01-15T15:00:00Z,52,\n"
 	if string(data) != expectedContent {
 		t.Errorf("Expected content:\n%s\nGot:\n%s", expectedContent, string(data))
 	}
@@ -367,3 +367,76 @@ func countLines(s string) int {
 	}
 	return count
 }
+
+func TestReadUploads(t *testing.T) {
+	csvPath := filepath.Join(t.TempDir(), "all-uploads.csv")
+	tracker, err := NewGlobalCSVTracker(csvPath)
+	if err != nil {
+		t.Fatalf("NewGlobalCSVTracker failed: %v", err)
+	}
+	uploadTime := time.Date(2024, 1, 15, 15, 0, 0, 0, time.UTC)
+	entry := UploadEntry{
+		ZoomUser:       "john.doe@company.com",
+		FileName:       "team-standup-meeting-1500.mp4",
+		RecordingSize:  1048576,
+		UploadDate:     uploadTime,
+		ProcessingTime: 45 * time.Second,
+	}
+	if err := tracker.TrackUpload(entry); err != nil {
+		t.Fatalf("TrackUpload failed: %v", err)
+	}
+
+	entries, err := ReadUploads(csvPath)
+	if err != nil {
+		t.Fatalf("ReadUploads failed: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(entries))
+	}
+	if got := entries[0]; got.ZoomUser != entry.ZoomUser || got.FileName != entry.FileName ||
+		got.RecordingSize != entry.RecordingSize || !got.UploadDate.Equal(uploadTime) || got.ProcessingTime != entry.ProcessingTime {
+		t.Errorf("Expected %+v, got %+v", entry, got)
+	}
+
+	if _, err := ReadUploads(filepath.Join(t.TempDir(), "missing.csv")); err == nil {
+		t.Error("Expected error for a missing file")
+	}
+}
+
+func TestCSVTracker_UpgradesHeader(t *testing.T) {
+	csvPath := filepath.Join(t.TempDir(), "all-uploads.csv")
+	old := "user,file_name,recording_size,upload_date,processing_time_seconds\njohn.doe@company.com,a.mp4,10,2024-01-15T15:00:00Z,45\n"
+	if err := os.WriteFile(csvPath, []byte(old), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tracker, err := NewGlobalCSVTracker(csvPath)
+	if err != nil {
+		t.Fatalf("NewGlobalCSVTracker failed: %v", err)
+	}
+	entry := UploadEntry{
+		ZoomUser:   "john.doe@company.com",
+		FileName:   "b.mp4",
+		UploadDate: time.Date(2024, 1, 16, 9, 0, 0, 0, time.UTC),
+		SharedLink: "https://app.box.com/s/abc",
+	}
+	if err := tracker.TrackUpload(entry); err != nil {
+		t.Fatalf("TrackUpload failed: %v", err)
+	}
+
+	data, err := os.ReadFile(csvPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "user,file_name,recording_size,upload_date,processing_time_seconds,shared_link\n" +
+		"john.doe@company.com,a.mp4,10,2024-01-15T15:00:00Z,45,\n" +
+		"john.doe@company.com,b.mp4,0,2024-01-16T09:00:00Z,0,https://app.box.com/s/abc\n"
+	if string(data) != expected {
+		t.Errorf("Expected content:\n%s\nGot:\n%s", expected, string(data))
+	}
+
+	entries, err := ReadUploads(csvPath)
+	if err != nil || len(entries) != 2 || entries[1].SharedLink != entry.SharedLink {
+		t.Errorf("Expected the shared link read back, got %+v (%v)", entries, err)
+	}
+}
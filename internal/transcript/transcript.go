@@ -0,0 +1,216 @@
+// Package transcript converts the WebVTT transcripts and captions Zoom records into SRT and
+// plain text sidecars, for video platforms and archives that do not accept WebVTT.
+package transcript
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Sidecar formats a transcript can be converted to
+const (
+	FormatSRT  = "srt" // SubRip subtitles
+	FormatText = "txt" // One line of plain text per cue
+)
+
+// Cue is a single timed caption of a transcript
+type Cue struct {
+	Start time.Duration
+	End   time.Duration
+	Text  string // Caption lines, joined by newlines
+}
+
+// ValidFormat reports whether format is a sidecar format ConvertFile writes
+func ValidFormat(format string) bool {
+	switch strings.ToLower(format) {
+	case FormatSRT, FormatText:
+		return true
+	}
+	return false
+}
+
+// ParseVTT reads the cues of a WebVTT file
+// NOTE, STYLE and REGION blocks are skipped, as are cue identifiers and cue settings.
+func ParseVTT(r io.Reader) ([]Cue, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read transcript: %w", err)
+		}
+		return nil, fmt.Errorf("transcript is empty")
+	}
+	header := strings.TrimPrefix(scanner.Text(), "\ufeff")
+	if !strings.HasPrefix(header, "WEBVTT") {
+		return nil, fmt.Errorf("transcript is not WebVTT (missing WEBVTT header)")
+	}
+
+	var cues []Cue
+	var block []string
+	lineNum, blockStart := 1, 0
+	flush := func() error {
+		defer func() { block = block[:0] }()
+		if len(block) == 0 {
+			return nil
+		}
+		// The timing line is first, or second after a cue identifier
+		timing := 0
+		if !strings.Contains(block[0], "-->") {
+			if len(block) < 2 || !strings.Contains(block[1], "-->") {
+				return nil // NOTE, STYLE or REGION block
+			}
+			timing = 1
+		}
+		start, end, err := parseTiming(block[timing])
+		if err != nil {
+			return fmt.Errorf("line %d: %w", blockStart+timing, err)
+		}
+		cues = append(cues, Cue{Start: start, End: end, Text: strings.Join(block[timing+1:], "\n")})
+		return nil
+	}
+
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(line) == "" {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if len(block) == 0 {
+			blockStart = lineNum
+		}
+		block = append(block, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read transcript: %w", err)
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	return cues, nil
+}
+
+// parseTiming parses a cue timing line such as "00:00:01.230 --> 00:00:04.560 align:start"
+func parseTiming(line string) (start, end time.Duration, err error) {
+	fields := strings.Fields(line)
+	if len(fields) < 3 || fields[1] != "-->" {
+		return 0, 0, fmt.Errorf("invalid cue timing %q", line)
+	}
+	if start, err = parseTimestamp(fields[0]); err != nil {
+		return 0, 0, err
+	}
+	if end, err = parseTimestamp(fields[2]); err != nil {
+		return 0, 0, err
+	}
+	return start, end, nil
+}
+
+// parseTimestamp parses a WebVTT timestamp, hh:mm:ss.ttt or mm:ss.ttt
+func parseTimestamp(value string) (time.Duration, error) {
+	clock, millis, ok := strings.Cut(value, ".")
+	if !ok || len(millis) != 3 {
+		return 0, fmt.Errorf("invalid timestamp %q", value)
+	}
+	parts := strings.Split(clock, ":")
+	if len(parts) == 2 {
+		parts = append([]string{"0"}, parts...)
+	}
+	if len(parts) != 3 {
+		return 0, fmt.Errorf("invalid timestamp %q", value)
+	}
+
+	var total time.Duration
+	units := []time.Duration{time.Hour, time.Minute, time.Second}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid timestamp %q", value)
+		}
+		total += time.Duration(n) * units[i]
+	}
+	ms, err := strconv.Atoi(millis)
+	if err != nil {
+		return 0, fmt.Errorf("invalid timestamp %q", value)
+	}
+	return total + time.Duration(ms)*time.Millisecond, nil
+}
+
+// WriteSRT writes cues as SubRip subtitles
+func WriteSRT(w io.Writer, cues []Cue) error {
+	bw := bufio.NewWriter(w)
+	for i, cue := range cues {
+		if i > 0 {
+			bw.WriteString("\n")
+		}
+		fmt.Fprintf(bw, "%d\n%s --> %s\n%s\n", i+1, srtTimestamp(cue.Start), srtTimestamp(cue.End), cue.Text)
+	}
+	return bw.Flush()
+}
+
+// srtTimestamp formats d as hh:mm:ss,mmm
+func srtTimestamp(d time.Duration) string {
+	ms := d.Milliseconds()
+	return fmt.Sprintf("%02d:%02d:%02d,%03d", ms/3_600_000, ms/60_000%60, ms/1000%60, ms%1000)
+}
+
+// WriteText writes the text of each cue on its own line, e.g. "Jane Smith: Hello everyone"
+func WriteText(w io.Writer, cues []Cue) error {
+	bw := bufio.NewWriter(w)
+	for _, cue := range cues {
+		bw.WriteString(strings.ReplaceAll(cue.Text, "\n", " "))
+		bw.WriteString("\n")
+	}
+	return bw.Flush()
+}
+
+// SidecarPath returns where ConvertFile writes the format sidecar of vttPath
+// The format is appended to the full name, so a transcript and the captions of the same
+// recording get distinct sidecars.
+func SidecarPath(vttPath, format string) string {
+	return vttPath + "." + strings.ToLower(format)
+}
+
+// ConvertFile converts the WebVTT file at vttPath into a sidecar per format next to it
+// It returns the paths of the sidecars written.
+func ConvertFile(vttPath string, formats []string) ([]string, error) {
+	data, err := os.ReadFile(vttPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read transcript: %w", err)
+	}
+	cues, err := ParseVTT(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, format := range formats {
+		var buf bytes.Buffer
+		switch strings.ToLower(format) {
+		case FormatSRT:
+			err = WriteSRT(&buf, cues)
+		case FormatText:
+			err = WriteText(&buf, cues)
+		default:
+			return paths, fmt.Errorf("unknown transcript format %q", format)
+		}
+		if err != nil {
+			return paths, fmt.Errorf("failed to convert transcript to %s: %w", format, err)
+		}
+
+		path := SidecarPath(vttPath, format)
+		if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+			return paths, fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
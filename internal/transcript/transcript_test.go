@@ -0,0 +1,71 @@
+package transcript
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const zoomVTT = "\ufeffWEBVTT\r\n\r\n" +
+	"NOTE recorded by Zoom\r\n\r\n" +
+	"1\r\n00:00:01.230 --> 00:00:04.560\r\nJane Smith: Hello everyone\r\n\r\n" +
+	"2\r\n01:02:03.004 --> 01:02:05.000 align:start\r\nJohn Doe: Two lines\r\nof caption\r\n"
+
+func TestParseVTT(t *testing.T) {
+	cues, err := ParseVTT(strings.NewReader(zoomVTT))
+	if err != nil {
+		t.Fatalf("ParseVTT failed: %v", err)
+	}
+	want := []Cue{
+		{Start: 1230 * time.Millisecond, End: 4560 * time.Millisecond, Text: "Jane Smith: Hello everyone"},
+		{Start: time.Hour + 2*time.Minute + 3*time.Second + 4*time.Millisecond, End: time.Hour + 2*time.Minute + 5*time.Second, Text: "John Doe: Two lines\nof caption"},
+	}
+	if len(cues) != len(want) {
+		t.Fatalf("Expected %d cues, got %+v", len(want), cues)
+	}
+	for i := range want {
+		if cues[i] != want[i] {
+			t.Errorf("Cue %d: expected %+v, got %+v", i, want[i], cues[i])
+		}
+	}
+
+	if _, err := ParseVTT(strings.NewReader("1\n00:00:01,000 --> 00:00:02,000\nSRT\n")); err == nil {
+		t.Error("Expected a file without the WEBVTT header to fail")
+	}
+	if _, err := ParseVTT(strings.NewReader("WEBVTT\n\n00:00:01 --> 00:00:02.000\nbad\n")); err == nil || !strings.Contains(err.Error(), "line 3") {
+		t.Errorf("Expected an invalid timestamp to fail with its line, got %v", err)
+	}
+}
+
+func TestConvertFile(t *testing.T) {
+	vttPath := filepath.Join(t.TempDir(), "team-sync-1500.transcript")
+	if err := os.WriteFile(vttPath, []byte(zoomVTT), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	paths, err := ConvertFile(vttPath, []string{"srt", "TXT"})
+	if err != nil {
+		t.Fatalf("ConvertFile failed: %v", err)
+	}
+	if len(paths) != 2 || paths[0] != vttPath+".srt" || paths[1] != vttPath+".txt" {
+		t.Fatalf("Expected SRT and text sidecars, got %v", paths)
+	}
+
+	srt, _ := os.ReadFile(paths[0])
+	wantSRT := "1\n00:00:01,230 --> 00:00:04,560\nJane Smith: Hello everyone\n\n" +
+		"2\n01:02:03,004 --> 01:02:05,000\nJohn Doe: Two lines\nof caption\n"
+	if string(srt) != wantSRT {
+		t.Errorf("Expected SRT:\n%s\ngot:\n%s", wantSRT, srt)
+	}
+
+	text, _ := os.ReadFile(paths[1])
+	if want := "Jane Smith: Hello everyone\nJohn Doe: Two lines of caption\n"; string(text) != want {
+		t.Errorf("Expected text %q, got %q", want, text)
+	}
+
+	if _, err := ConvertFile(vttPath, []string{"docx"}); err == nil {
+		t.Error("Expected an unknown format to fail")
+	}
+}
@@ -21,9 +21,10 @@ type UserEmailMapping struct {
 
 // UserEntry represents a user with upload tracking information
 type UserEntry struct {
-	ZoomEmail      string // Zoom account email
+	ZoomEmail      string // Zoom account email, or the Zoom user ID for users whose email lookups fail
 	BoxEmail       string // Box account email (may differ from Zoom email)
 	UploadComplete bool   // Whether uploads for this user are complete
+	Destination    string // Box folder ID or path to upload to instead of the user's zoom folder ("" = default)
 	LineNumber     int    // Original line number in file for updates
 }
 
@@ -69,6 +70,9 @@ type activeUserManagerImpl struct {
 // Email validation regex (basic validation) - allows underscores in domain
 var emailRegex = regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9._-]+\.[a-zA-Z]{2,}$`)
 
+// Zoom user IDs are opaque URL-safe strings, e.g. KDcuGIm1QgePTO8WbOqwIQ
+var zoomUserIDRegex = regexp.MustCompile(`^[a-zA-Z0-9_-]{16,32}$`)
+
 // NewActiveUserManager creates a new active user manager
 func NewActiveUserManager(config ActiveUserConfig) (ActiveUserManager, error) {
 	manager := &activeUserManagerImpl{
@@ -244,7 +248,7 @@ func (m *activeUserManagerImpl) loadUserList() error {
 			boxEmail = strings.TrimSpace(parts[1])
 			
 			// Validate both emails
-			if !isValidEmail(zoomEmail) || !isValidEmail(boxEmail) {
+			if !isValidZoomUser(zoomEmail) || !isValidEmail(boxEmail) {
 				// Skip invalid email mappings
 				continue
 			}
@@ -377,6 +381,16 @@ func isValidEmail(email string) bool {
 	return emailRegex.MatchString(email)
 }
 
+// IsZoomUserID reports whether s is a Zoom user ID rather than an email address
+func IsZoomUserID(s string) bool {
+	return zoomUserIDRegex.MatchString(s)
+}
+
+// isValidZoomUser reports whether s identifies a Zoom user by email or by user ID
+func isValidZoomUser(s string) bool {
+	return isValidEmail(s) || IsZoomUserID(s)
+}
+
 // ActiveUsersFile represents a file containing users with upload tracking
 type ActiveUsersFile struct {
 	FilePath string
@@ -429,7 +443,7 @@ func LoadActiveUsersFile(filePath string) (*ActiveUsersFile, error) {
 func parseUserEntry(line string, lineNumber int) (UserEntry, error) {
 	parts := strings.Split(line, ",")
 
-	var zoomEmail, boxEmail string
+	var zoomEmail, boxEmail, destination string
 	var uploadComplete bool
 
 	switch len(parts) {
@@ -452,23 +466,28 @@ func parseUserEntry(line string, lineNumber int) (UserEntry, error) {
 			boxEmail = zoomEmail
 		}
 
-		if !isValidEmail(zoomEmail) || !isValidEmail(boxEmail) {
+		// zoom_email may be a Zoom user ID; box_email is always an email
+		if !isValidZoomUser(zoomEmail) || !isValidEmail(boxEmail) {
 			return UserEntry{}, fmt.Errorf("invalid email")
 		}
 		uploadComplete = false
 
-	case 3:
+	case 3, 4:
 		// 3-column format: zoom_email,box_email,upload_complete
+		// 4-column format: zoom_email,box_email,upload_complete,destination
 		zoomEmail = strings.TrimSpace(parts[0])
 		boxEmail = strings.TrimSpace(parts[1])
 		uploadCompleteStr := strings.TrimSpace(parts[2])
+		if len(parts) == 4 {
+			destination = strings.TrimSpace(parts[3])
+		}
 
 		// If box_email is empty, use zoom_email
 		if boxEmail == "" {
 			boxEmail = zoomEmail
 		}
 
-		if !isValidEmail(zoomEmail) || !isValidEmail(boxEmail) {
+		if !isValidZoomUser(zoomEmail) || !isValidEmail(boxEmail) {
 			return UserEntry{}, fmt.Errorf("invalid email")
 		}
 
@@ -476,17 +495,28 @@ func parseUserEntry(line string, lineNumber int) (UserEntry, error) {
 		uploadComplete = parseBool(uploadCompleteStr)
 
 	default:
-		return UserEntry{}, fmt.Errorf("invalid format: expected 1-3 columns")
+		return UserEntry{}, fmt.Errorf("invalid format: expected 1-4 columns")
 	}
 
 	return UserEntry{
 		ZoomEmail:      zoomEmail,
 		BoxEmail:       boxEmail,
 		UploadComplete: uploadComplete,
+		Destination:    destination,
 		LineNumber:     lineNumber,
 	}, nil
 }
 
+// formatUserEntry formats entry as a users file line; the destination column is only written
+// when the entry has one
+func formatUserEntry(entry UserEntry) string {
+	line := fmt.Sprintf("%s,%s,%t", entry.ZoomEmail, entry.BoxEmail, entry.UploadComplete)
+	if entry.Destination != "" {
+		line += "," + entry.Destination
+	}
+	return line
+}
+
 // parseBool parses a boolean value from string (case-insensitive)
 // Supports: true/false, yes/no, 1/0
 func parseBool(s string) bool {
@@ -535,7 +565,7 @@ func (f *ActiveUsersFile) UpdateUserStatus(zoomEmail string, complete bool) erro
 	}
 
 	// Write updates to file atomically
-	return f.writeToFileAtomic()
+	return f.writeUserStatus(zoomEmail, complete)
 }
 
 // MarkUserComplete marks a user's uploads as complete
@@ -543,76 +573,40 @@ func (f *ActiveUsersFile) MarkUserComplete(zoomEmail string) error {
 	return f.UpdateUserStatus(zoomEmail, true)
 }
 
-// writeToFileAtomic writes the file content atomically using temp file + rename
-func (f *ActiveUsersFile) writeToFileAtomic() error {
-	// Create temporary file
-	tempFile := f.FilePath + ".tmp"
-	file, err := os.OpenFile(tempFile, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+// writeUserStatus sets upload_complete of zoomEmail's lines in the file while holding the file
+// lock. The file is read again under the lock, so changes other processes made since it was
+// loaded are kept.
+func (f *ActiveUsersFile) writeUserStatus(zoomEmail string, complete bool) error {
+	lock, err := lockActiveUsersFile(f.FilePath)
 	if err != nil {
-		return fmt.Errorf("failed to create temp file: %w", err)
+		return err
 	}
+	defer lock.Release()
 
-	// Read original file to preserve comments and empty lines
-	originalLines, err := readFileLines(f.FilePath)
+	lines, err := readFileLines(f.FilePath)
 	if err != nil {
-		file.Close()
-		os.Remove(tempFile)
 		return fmt.Errorf("failed to read original file: %w", err)
 	}
 
-	// Create a map of line numbers to updated entries
-	updates := make(map[int]UserEntry)
-	for _, entry := range f.Entries {
-		updates[entry.LineNumber] = entry
-	}
-
-	// Write file with preserved comments and updated entries
-	writer := bufio.NewWriter(file)
-	lineNumber := 0
-
-	for _, line := range originalLines {
-		lineNumber++
-
-		// Check if this line should be updated
-		if entry, exists := updates[lineNumber]; exists {
-			// Write updated entry
-			_, err := writer.WriteString(fmt.Sprintf("%s,%s,%t\n",
-				entry.ZoomEmail, entry.BoxEmail, entry.UploadComplete))
-			if err != nil {
-				file.Close()
-				os.Remove(tempFile)
-				return fmt.Errorf("failed to write entry: %w", err)
-			}
-		} else {
-			// Preserve original line (comment or empty line)
-			_, err := writer.WriteString(line + "\n")
-			if err != nil {
-				file.Close()
-				os.Remove(tempFile)
-				return fmt.Errorf("failed to write line: %w", err)
-			}
+	found := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
 		}
+		entry, err := parseUserEntry(trimmed, i+1)
+		if err != nil || entry.ZoomEmail != zoomEmail {
+			continue
+		}
+		entry.UploadComplete = complete
+		lines[i] = formatUserEntry(entry)
+		found = true
 	}
-
-	// Flush and close
-	if err := writer.Flush(); err != nil {
-		file.Close()
-		os.Remove(tempFile)
-		return fmt.Errorf("failed to flush writer: %w", err)
-	}
-
-	if err := file.Close(); err != nil {
-		os.Remove(tempFile)
-		return fmt.Errorf("failed to close temp file: %w", err)
-	}
-
-	// Atomic rename
-	if err := os.Rename(tempFile, f.FilePath); err != nil {
-		os.Remove(tempFile)
-		return fmt.Errorf("failed to rename temp file: %w", err)
+	if !found {
+		return fmt.Errorf("user %s is no longer in %s", zoomEmail, f.FilePath)
 	}
 
-	return nil
+	return writeLinesAtomic(f.FilePath, lines)
 }
 
 // readFileLines reads all lines from a file
@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -569,6 +570,16 @@ user2@zoom.com,user2@box.com`,
 			},
 			expectedIncomplete: 2,
 		},
+		{
+			name: "Zoom user IDs in place of Zoom emails",
+			fileContent: `KDcuGIm1QgePTO8WbOqwIQ,jane.doe@box.com
+z8yAAAAA8bbbbbbbbbbbbb,john.doe@box.com,true`,
+			expectedEntries: []UserEntry{
+				{ZoomEmail: "KDcuGIm1QgePTO8WbOqwIQ", BoxEmail: "jane.doe@box.com", UploadComplete: false, LineNumber: 1},
+				{ZoomEmail: "z8yAAAAA8bbbbbbbbbbbbb", BoxEmail: "john.doe@box.com", UploadComplete: true, LineNumber: 2},
+			},
+			expectedIncomplete: 1,
+		},
 		{
 			name: "1-column backward compatibility",
 			fileContent: `user@example.com
@@ -789,6 +800,92 @@ user2@zoom.com,user2@box.com,true`
 	}
 }
 
+// TestUpdateUserStatus_ConcurrentFiles tests that updates through separately loaded copies of
+// the file, as two processes would make them, do not undo each other
+func TestUpdateUserStatus_ConcurrentFiles(t *testing.T) {
+	userListFile := filepath.Join(t.TempDir(), "active_users.txt")
+	initialContent := "# team\nuser1@zoom.com,user1@box.com,false\nuser2@zoom.com,user2@box.com,false\n"
+	if err := os.WriteFile(userListFile, []byte(initialContent), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	first, err := LoadActiveUsersFile(userListFile)
+	if err != nil {
+		t.Fatalf("Failed to load users file: %v", err)
+	}
+	second, err := LoadActiveUsersFile(userListFile)
+	if err != nil {
+		t.Fatalf("Failed to load users file: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for _, update := range []struct {
+		file *ActiveUsersFile
+		user string
+	}{{first, "user1@zoom.com"}, {second, "user2@zoom.com"}} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := update.file.MarkUserComplete(update.user); err != nil {
+				t.Errorf("Failed to mark %s complete: %v", update.user, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	data, err := os.ReadFile(userListFile)
+	if err != nil {
+		t.Fatalf("Failed to read users file: %v", err)
+	}
+	want := "# team\nuser1@zoom.com,user1@box.com,true\nuser2@zoom.com,user2@box.com,true\n"
+	if string(data) != want {
+		t.Errorf("Expected both updates to be kept, got:\n%s", data)
+	}
+}
+
+// TestUserEntryDestination tests the optional destination column and that status updates keep it
+func TestUserEntryDestination(t *testing.T) {
+	tempDir := t.TempDir()
+	userListFile := filepath.Join(tempDir, "active_users.txt")
+
+	initialContent := `user1@zoom.com,user1@box.com,false,123456789
+user2@zoom.com,user2@box.com,false, Shared/Recordings/Sales
+user3@zoom.com,user3@box.com,false,`
+
+	if err := os.WriteFile(userListFile, []byte(initialContent), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	usersFile, err := LoadActiveUsersFile(userListFile)
+	if err != nil {
+		t.Fatalf("Failed to load users file: %v", err)
+	}
+	expected := []string{"123456789", "Shared/Recordings/Sales", ""}
+	if len(usersFile.Entries) != len(expected) {
+		t.Fatalf("Expected %d entries, got %d", len(expected), len(usersFile.Entries))
+	}
+	for i, destination := range expected {
+		if usersFile.Entries[i].Destination != destination {
+			t.Errorf("Entry %d: expected Destination %q, got %q", i, destination, usersFile.Entries[i].Destination)
+		}
+	}
+
+	if err := usersFile.MarkUserComplete("user1@zoom.com"); err != nil {
+		t.Fatalf("Failed to mark user complete: %v", err)
+	}
+
+	data, err := os.ReadFile(userListFile)
+	if err != nil {
+		t.Fatalf("Failed to read users file: %v", err)
+	}
+	want := "user1@zoom.com,user1@box.com,true,123456789\n" +
+		"user2@zoom.com,user2@box.com,false, Shared/Recordings/Sales\n" +
+		"user3@zoom.com,user3@box.com,false,\n"
+	if string(data) != want {
+		t.Errorf("Unexpected file content:\n%s", data)
+	}
+}
+
 // TestGetIncompleteUsers tests filtering incomplete users
 func TestGetIncompleteUsersFiltering(t *testing.T) {
 	tempDir := t.TempDir()
@@ -1000,7 +1097,7 @@ func TestEdgeCasesFileLoading(t *testing.T) {
 			name:          "extra columns ignored",
 			fileContent:   "user1@zoom.com,user1@box.com,false,extra,data",
 			expectedCount: 0,
-			description:   "Should reject lines with more than 3 columns",
+			description:   "Should reject lines with more than 4 columns",
 		},
 		{
 			name:          "mixed valid and invalid emails",
@@ -1345,7 +1442,7 @@ func TestEdgeCasesMalformedLines(t *testing.T) {
 		},
 		{
 			name:          "multiple commas in a row",
-			fileContent:   "user1@example.com,,,false",
+			fileContent:   "user1@example.com,,,,false",
 			expectedCount: 0,
 		},
 		{
@@ -1363,6 +1460,11 @@ func TestEdgeCasesMalformedLines(t *testing.T) {
 			fileContent:   "user@example",
 			expectedCount: 0,
 		},
+		{
+			name:          "Zoom user ID without a Box email",
+			fileContent:   "KDcuGIm1QgePTO8WbOqwIQ",
+			expectedCount: 0,
+		},
 		{
 			name:          "mixed case boolean with typo",
 			fileContent:   "user@example.com,user@box.com,Tru",
@@ -0,0 +1,147 @@
+package users
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/curtbushko/zoom-to-box/internal/download"
+	"github.com/curtbushko/zoom-to-box/internal/runreport"
+)
+
+// maxStatusErrorLength is the longest error shown in the status table before it is shortened
+const maxStatusErrorLength = 60
+
+// UserStatus summarizes the progress of a user in the active users file from the tracking
+// data written by earlier runs
+type UserStatus struct {
+	ZoomEmail      string
+	BoxEmail       string
+	UploadComplete bool
+	Recordings     int       // Meetings with files in the status file or the run report
+	Downloaded     int       // Files downloaded
+	Uploaded       int       // Files uploaded to the destination
+	LastError      string    // Most recent download or upload error ("" = none)
+	LastRun        time.Time // Most recent download or upload attempt (zero = never processed)
+
+	lastErrorAt time.Time
+}
+
+// Statuses returns the status of every user in the file, in file order, from the download
+// status file entries and the failures of a run report (nil = no run report)
+func (f *ActiveUsersFile) Statuses(downloads map[string]download.DownloadEntry, failures []runreport.Failure) []UserStatus {
+	f.mu.RLock()
+	statuses := make([]UserStatus, len(f.Entries))
+	index := make(map[string]int, len(f.Entries))
+	for i, entry := range f.Entries {
+		statuses[i] = UserStatus{ZoomEmail: entry.ZoomEmail, BoxEmail: entry.BoxEmail, UploadComplete: entry.UploadComplete}
+		index[strings.ToLower(entry.ZoomEmail)] = i
+	}
+	f.mu.RUnlock()
+
+	meetings := make([]map[string]bool, len(statuses))
+	addMeeting := func(i int, meetingID string) {
+		if meetings[i] == nil {
+			meetings[i] = make(map[string]bool)
+		}
+		meetings[i][meetingID] = true
+	}
+
+	for id, entry := range downloads {
+		i, ok := index[strings.ToLower(download.GetZoomEmailForEntry(entry))]
+		if !ok {
+			continue
+		}
+		status := &statuses[i]
+
+		meetingID, _ := entry.Metadata["meeting_id"].(string)
+		if meetingID == "" {
+			meetingID = id
+		}
+		addMeeting(i, meetingID)
+
+		if entry.DownloadedSize > 0 {
+			status.Downloaded++
+		}
+		status.seen(entry.LastAttempt)
+		status.seen(entry.CompletedTime)
+		status.failed(entry.Error, entry.LastAttempt)
+		if entry.Box != nil {
+			if entry.Box.Uploaded {
+				status.Uploaded++
+			}
+			status.seen(entry.Box.LastUploadAttempt)
+			status.failed(entry.Box.UploadError, entry.Box.LastUploadAttempt)
+		}
+	}
+
+	for _, failure := range failures {
+		i, ok := index[strings.ToLower(failure.ZoomEmail)]
+		if !ok {
+			continue
+		}
+		addMeeting(i, failure.Recording.UUID)
+		statuses[i].seen(failure.FailedAt)
+		statuses[i].failed(failure.Error, failure.FailedAt)
+	}
+
+	for i := range statuses {
+		statuses[i].Recordings = len(meetings[i])
+	}
+	return statuses
+}
+
+// seen records an attempt at t
+func (s *UserStatus) seen(t time.Time) {
+	if t.After(s.LastRun) {
+		s.LastRun = t
+	}
+}
+
+// failed records an error that happened at t, keeping the most recent one
+func (s *UserStatus) failed(message string, t time.Time) {
+	if message == "" || (s.LastError != "" && t.Before(s.lastErrorAt)) {
+		return
+	}
+	s.LastError = message
+	s.lastErrorAt = t
+}
+
+// WriteUserStatusTable writes the statuses as an aligned table
+func WriteUserStatusTable(w io.Writer, statuses []UserStatus) error {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "USER\tUPLOAD_COMPLETE\tRECORDINGS\tDOWNLOADED\tUPLOADED\tLAST_ERROR\tLAST_RUN")
+	for _, status := range statuses {
+		user := status.ZoomEmail
+		if status.BoxEmail != "" && !strings.EqualFold(status.BoxEmail, status.ZoomEmail) {
+			user += " -> " + status.BoxEmail
+		}
+		lastError := "-"
+		if status.LastError != "" {
+			lastError = shortenError(status.LastError)
+		}
+		lastRun := "never"
+		if !status.LastRun.IsZero() {
+			lastRun = formatReportTime(status.LastRun)
+		}
+		fmt.Fprintf(table, "%s\t%s\t%d\t%d\t%d\t%s\t%s\n",
+			user, strconv.FormatBool(status.UploadComplete), status.Recordings, status.Downloaded, status.Uploaded, lastError, lastRun)
+	}
+	if err := table.Flush(); err != nil {
+		return fmt.Errorf("failed to write user status table: %w", err)
+	}
+	return nil
+}
+
+// shortenError returns the first line of message, cut to maxStatusErrorLength characters
+func shortenError(message string) string {
+	message, _, _ = strings.Cut(strings.TrimSpace(message), "\n")
+	message = strings.ReplaceAll(message, "\t", " ")
+	if runes := []rune(message); len(runes) > maxStatusErrorLength {
+		return string(runes[:maxStatusErrorLength-3]) + "..."
+	}
+	return message
+}
@@ -0,0 +1,88 @@
+package users
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/curtbushko/zoom-to-box/internal/download"
+	"github.com/curtbushko/zoom-to-box/internal/runreport"
+	"github.com/curtbushko/zoom-to-box/internal/zoom"
+)
+
+func TestActiveUsersFile_Statuses(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "active_users.txt")
+	if err := os.WriteFile(filePath, []byte("jane@example.com,jane@box.example.com,true\nbob@example.com,bob@example.com,false\namy@example.com\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	usersFile, err := LoadActiveUsersFile(filePath)
+	if err != nil {
+		t.Fatalf("LoadActiveUsersFile failed: %v", err)
+	}
+
+	day1 := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	day2 := day1.Add(24 * time.Hour)
+	downloads := map[string]download.DownloadEntry{
+		"m1-f1": {Status: download.StatusCompleted, DownloadedSize: 100, LastAttempt: day1, VideoOwner: "Jane@example.com",
+			Metadata: map[string]interface{}{"meeting_id": "m1"}, Box: &download.BoxUploadInfo{Uploaded: true, LastUploadAttempt: day1}},
+		"m1-f2": {Status: download.StatusCompleted, DownloadedSize: 50, LastAttempt: day1, VideoOwner: "jane@example.com",
+			Metadata: map[string]interface{}{"meeting_id": "m1"}, Box: &download.BoxUploadInfo{Uploaded: true, LastUploadAttempt: day1}},
+		"m2-f1": {Status: download.StatusCompleted, DownloadedSize: 100, LastAttempt: day1, VideoOwner: "bob@example.com",
+			Metadata: map[string]interface{}{"meeting_id": "m2"}, Box: &download.BoxUploadInfo{UploadError: "Box API error: quota", LastUploadAttempt: day1}},
+		"m9-f1": {Status: download.StatusCompleted, DownloadedSize: 100, LastAttempt: day2, VideoOwner: "someone-else@example.com"},
+	}
+	failures := []runreport.Failure{
+		{Operation: runreport.OperationDownload, ZoomEmail: "bob@example.com", Recording: zoom.Recording{UUID: "m3"}, Error: "HTTP error: 404 Not Found", FailedAt: day2},
+	}
+
+	statuses := usersFile.Statuses(downloads, failures)
+	if len(statuses) != 3 {
+		t.Fatalf("Expected 3 statuses, got %d", len(statuses))
+	}
+
+	jane := statuses[0]
+	if jane.ZoomEmail != "jane@example.com" || !jane.UploadComplete || jane.Recordings != 1 || jane.Downloaded != 2 || jane.Uploaded != 2 || jane.LastError != "" || !jane.LastRun.Equal(day1) {
+		t.Errorf("Unexpected status for jane: %+v", jane)
+	}
+	bob := statuses[1]
+	if bob.UploadComplete || bob.Recordings != 2 || bob.Downloaded != 1 || bob.Uploaded != 0 || !bob.LastRun.Equal(day2) {
+		t.Errorf("Unexpected status for bob: %+v", bob)
+	}
+	if bob.LastError != "HTTP error: 404 Not Found" {
+		t.Errorf("Expected the most recent error for bob, got %q", bob.LastError)
+	}
+	amy := statuses[2]
+	if amy.Recordings != 0 || !amy.LastRun.IsZero() {
+		t.Errorf("Expected amy to be unprocessed, got %+v", amy)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteUserStatusTable(&buf, statuses); err != nil {
+		t.Fatalf("WriteUserStatusTable failed: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[0], "USER") {
+		t.Fatalf("Expected a header and 3 rows, got %q", buf.String())
+	}
+	if !strings.Contains(lines[1], "jane@example.com -> jane@box.example.com") || !strings.Contains(lines[1], "true") {
+		t.Errorf("Unexpected row for jane: %q", lines[1])
+	}
+	if !strings.Contains(lines[2], "HTTP error: 404 Not Found") {
+		t.Errorf("Expected bob's last error, got %q", lines[2])
+	}
+	if !strings.HasSuffix(lines[3], "never") {
+		t.Errorf("Expected amy to have never run, got %q", lines[3])
+	}
+}
+
+func TestShortenError(t *testing.T) {
+	if got := shortenError("first line\nsecond line"); got != "first line" {
+		t.Errorf("Expected the first line, got %q", got)
+	}
+	if got := shortenError(strings.Repeat("x", 100)); len(got) != maxStatusErrorLength || !strings.HasSuffix(got, "...") {
+		t.Errorf("Expected a shortened error, got %q", got)
+	}
+}
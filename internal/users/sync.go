@@ -0,0 +1,173 @@
+package users
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/curtbushko/zoom-to-box/internal/filelock"
+)
+
+// activeUsersHeader is written at the top of active users files created by a sync
+const activeUsersHeader = "# zoom_email,box_email,upload_complete"
+
+// SyncOptions controls how a list of Zoom users is merged into an active users file
+type SyncOptions struct {
+	Prune  bool // Remove entries for users that are no longer in the list
+	DryRun bool // Compute the result without writing the file
+}
+
+// SyncResult describes the changes made by SyncActiveUsersFile
+type SyncResult struct {
+	Added   []string // Zoom emails appended to the file
+	Removed []string // Zoom emails removed because they are no longer in the list (Prune only)
+	Kept    int      // Existing entries left in place with their Box email and upload_complete flag
+}
+
+// SyncActiveUsersFile writes zoomEmails to the 3-column active users file at filePath
+// Existing entries keep their Box email and upload_complete flag, and comments, blank lines
+// and unparseable lines are preserved. New users are appended with their Zoom email as the
+// Box email and upload_complete=false. Emails are compared case-insensitively.
+func SyncActiveUsersFile(filePath string, zoomEmails []string, opts SyncOptions) (*SyncResult, error) {
+	result := &SyncResult{}
+
+	wanted := make(map[string]bool, len(zoomEmails))
+	for _, email := range zoomEmails {
+		wanted[strings.ToLower(strings.TrimSpace(email))] = true
+	}
+
+	// Hold the lock from reading to writing so status updates of a concurrent run are not lost
+	if !opts.DryRun {
+		lock, err := lockActiveUsersFile(filePath)
+		if err != nil {
+			return nil, err
+		}
+		defer lock.Release()
+	}
+
+	existingLines, err := readFileLines(filePath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to read active users file: %w", err)
+		}
+		existingLines = []string{activeUsersHeader}
+	}
+
+	lines := make([]string, 0, len(existingLines)+len(zoomEmails))
+	present := make(map[string]bool)
+
+	for i, line := range existingLines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			lines = append(lines, line)
+			continue
+		}
+
+		entry, err := parseUserEntry(trimmed, i+1)
+		if err != nil {
+			lines = append(lines, line)
+			continue
+		}
+
+		key := strings.ToLower(entry.ZoomEmail)
+		if opts.Prune && !wanted[key] {
+			result.Removed = append(result.Removed, entry.ZoomEmail)
+			continue
+		}
+
+		present[key] = true
+		result.Kept++
+		lines = append(lines, formatUserEntry(entry))
+	}
+
+	for _, email := range zoomEmails {
+		email = strings.TrimSpace(email)
+		key := strings.ToLower(email)
+		if present[key] || !isValidEmail(email) {
+			continue
+		}
+		present[key] = true
+		result.Added = append(result.Added, email)
+		lines = append(lines, fmt.Sprintf("%s,%s,%t", email, email, false))
+	}
+
+	if opts.DryRun {
+		return result, nil
+	}
+
+	if err := writeLinesAtomic(filePath, lines); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// lockActiveUsersFile takes the advisory lock that serializes updates of the active users file
+// at filePath between processes
+func lockActiveUsersFile(filePath string) (*filelock.Lock, error) {
+	lock, err := filelock.Acquire(filePath + ".lock")
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock active users file: %w", err)
+	}
+	return lock, nil
+}
+
+// writeLinesAtomic replaces filePath with lines using a uniquely named temp file + rename, so
+// readers see either the old or the new file and concurrent writers never share a temp file
+func writeLinesAtomic(filePath string, lines []string) error {
+	dir := filepath.Dir(filePath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(filePath); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	file, err := os.CreateTemp(dir, filepath.Base(filePath)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	tempFile := file.Name()
+
+	writer := bufio.NewWriter(file)
+	for _, line := range lines {
+		if _, err := writer.WriteString(line + "\n"); err != nil {
+			file.Close()
+			os.Remove(tempFile)
+			return fmt.Errorf("failed to write line: %w", err)
+		}
+	}
+
+	if err := writer.Flush(); err != nil {
+		file.Close()
+		os.Remove(tempFile)
+		return fmt.Errorf("failed to flush writer: %w", err)
+	}
+
+	// Make the content durable before the rename makes it visible
+	if err := file.Sync(); err != nil {
+		file.Close()
+		os.Remove(tempFile)
+		return fmt.Errorf("failed to sync temp file: %w", err)
+	}
+
+	if err := file.Close(); err != nil {
+		os.Remove(tempFile)
+		return fmt.Errorf("failed to close temp file: %w", err)
+	}
+
+	if err := os.Chmod(tempFile, mode); err != nil {
+		os.Remove(tempFile)
+		return fmt.Errorf("failed to set temp file permissions: %w", err)
+	}
+
+	if err := os.Rename(tempFile, filePath); err != nil {
+		os.Remove(tempFile)
+		return fmt.Errorf("failed to rename temp file: %w", err)
+	}
+
+	return nil
+}
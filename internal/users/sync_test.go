@@ -0,0 +1,76 @@
+package users
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSyncActiveUsersFile_NewFile(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "active_users.txt")
+
+	result, err := SyncActiveUsersFile(filePath, []string{"a@example.com", "b@example.com", "not-an-email"}, SyncOptions{})
+	if err != nil {
+		t.Fatalf("SyncActiveUsersFile failed: %v", err)
+	}
+	if len(result.Added) != 2 || result.Kept != 0 {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+
+	usersFile, err := LoadActiveUsersFile(filePath)
+	if err != nil {
+		t.Fatalf("Failed to load synced file: %v", err)
+	}
+	if len(usersFile.Entries) != 2 || usersFile.Entries[1].BoxEmail != "b@example.com" || usersFile.Entries[1].UploadComplete {
+		t.Errorf("Unexpected entries: %+v", usersFile.Entries)
+	}
+}
+
+func TestSyncActiveUsersFile_MergePreservesState(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "active_users.txt")
+	original := "# team list\n" +
+		"done@example.com,done@box.example.com,true\n" +
+		"gone@example.com\n" +
+		"\n" +
+		"Pending@Example.com,pending@box.example.com\n"
+	if err := os.WriteFile(filePath, []byte(original), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	zoomUsers := []string{"done@example.com", "pending@example.com", "new@example.com"}
+
+	t.Run("merge", func(t *testing.T) {
+		result, err := SyncActiveUsersFile(filePath, zoomUsers, SyncOptions{DryRun: true})
+		if err != nil {
+			t.Fatalf("SyncActiveUsersFile failed: %v", err)
+		}
+		if len(result.Added) != 1 || result.Added[0] != "new@example.com" || result.Kept != 3 || len(result.Removed) != 0 {
+			t.Errorf("Unexpected result: %+v", result)
+		}
+
+		data, _ := os.ReadFile(filePath)
+		if string(data) != original {
+			t.Error("Dry run should not modify the file")
+		}
+	})
+
+	t.Run("prune", func(t *testing.T) {
+		result, err := SyncActiveUsersFile(filePath, zoomUsers, SyncOptions{Prune: true})
+		if err != nil {
+			t.Fatalf("SyncActiveUsersFile failed: %v", err)
+		}
+		if len(result.Removed) != 1 || result.Removed[0] != "gone@example.com" {
+			t.Errorf("Expected gone@example.com to be removed, got %+v", result)
+		}
+
+		data, _ := os.ReadFile(filePath)
+		expected := "# team list\n" +
+			"done@example.com,done@box.example.com,true\n" +
+			"\n" +
+			"Pending@Example.com,pending@box.example.com,false\n" +
+			"new@example.com,new@example.com,false\n"
+		if string(data) != expected {
+			t.Errorf("Unexpected file content:\n%s", data)
+		}
+	})
+}
@@ -0,0 +1,99 @@
+package users
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/curtbushko/zoom-to-box/internal/timefmt"
+)
+
+// UnmanagedHost summarizes the cloud recordings of a Zoom user who is not in the active users file
+type UnmanagedHost struct {
+	Email      string    // Zoom email of the host
+	Status     string    // Zoom user status, e.g. "active" or "inactive"
+	Recordings int       // Number of meetings with cloud recordings
+	TotalSize  int64     // Total size of the recordings in bytes
+	Oldest     time.Time // Start time of the oldest recording
+	Newest     time.Time // Start time of the newest recording
+}
+
+// AddRecording adds a recording that started at start with size bytes to the summary
+func (h *UnmanagedHost) AddRecording(start time.Time, size int64) {
+	h.Recordings++
+	h.TotalSize += size
+	if h.Oldest.IsZero() || start.Before(h.Oldest) {
+		h.Oldest = start
+	}
+	if start.After(h.Newest) {
+		h.Newest = start
+	}
+}
+
+// MissingEmails returns the emails in zoomEmails that have no entry in the file, sorted and
+// lowercased; emails are compared case-insensitively
+func (f *ActiveUsersFile) MissingEmails(zoomEmails []string) []string {
+	f.mu.RLock()
+	known := make(map[string]bool, len(f.Entries))
+	for _, entry := range f.Entries {
+		known[strings.ToLower(entry.ZoomEmail)] = true
+	}
+	f.mu.RUnlock()
+
+	seen := make(map[string]bool)
+	var missing []string
+	for _, email := range zoomEmails {
+		email = strings.ToLower(strings.TrimSpace(email))
+		if email == "" || known[email] || seen[email] {
+			continue
+		}
+		seen[email] = true
+		missing = append(missing, email)
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+// WriteUnmanagedHostsCSV writes the unmanaged hosts report, oldest recordings first so the
+// content closest to Zoom's retention limit is at the top
+func WriteUnmanagedHostsCSV(w io.Writer, hosts []UnmanagedHost) error {
+	sorted := append([]UnmanagedHost(nil), hosts...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Oldest.Before(sorted[j].Oldest)
+	})
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"zoom_email", "status", "recordings", "total_size_bytes", "oldest_recording", "newest_recording"}); err != nil {
+		return fmt.Errorf("failed to write unmanaged hosts report: %w", err)
+	}
+	for _, host := range sorted {
+		record := []string{
+			host.Email,
+			host.Status,
+			strconv.Itoa(host.Recordings),
+			strconv.FormatInt(host.TotalSize, 10),
+			formatReportTime(host.Oldest),
+			formatReportTime(host.Newest),
+		}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("failed to write unmanaged hosts report: %w", err)
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write unmanaged hosts report: %w", err)
+	}
+	return nil
+}
+
+// formatReportTime formats t with the configured timestamp format ("" for the zero time)
+func formatReportTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return timefmt.Format(t)
+}
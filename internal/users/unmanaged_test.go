@@ -0,0 +1,54 @@
+package users
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestActiveUsersFile_MissingEmails(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "active_users.txt")
+	if err := os.WriteFile(filePath, []byte("Jane@Example.com,jane@box.example.com,true\nbob@example.com\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	usersFile, err := LoadActiveUsersFile(filePath)
+	if err != nil {
+		t.Fatalf("LoadActiveUsersFile failed: %v", err)
+	}
+
+	missing := usersFile.MissingEmails([]string{"jane@example.com", "Zed@example.com", "amy@example.com", "zed@example.com", "BOB@example.com"})
+	if want := []string{"amy@example.com", "zed@example.com"}; strings.Join(missing, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected %v, got %v", want, missing)
+	}
+}
+
+func TestWriteUnmanagedHostsCSV(t *testing.T) {
+	recent := UnmanagedHost{Email: "recent@example.com", Status: "active"}
+	recent.AddRecording(time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC), 100)
+
+	old := UnmanagedHost{Email: "old@example.com", Status: "inactive"}
+	old.AddRecording(time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC), 200)
+	old.AddRecording(time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC), 300)
+
+	var buf bytes.Buffer
+	if err := WriteUnmanagedHostsCSV(&buf, []UnmanagedHost{recent, old}); err != nil {
+		t.Fatalf("WriteUnmanagedHostsCSV failed: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected a header and 2 rows, got %q", buf.String())
+	}
+	if lines[0] != "zoom_email,status,recordings,total_size_bytes,oldest_recording,newest_recording" {
+		t.Errorf("Unexpected header %q", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], "old@example.com,inactive,2,500,2024-01-01") || !strings.Contains(lines[1], ",2024-03-01") {
+		t.Errorf("Expected the host with the oldest recordings first, got %q", lines[1])
+	}
+	if !strings.HasPrefix(lines[2], "recent@example.com,active,1,100,") {
+		t.Errorf("Unexpected row %q", lines[2])
+	}
+}
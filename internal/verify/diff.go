@@ -0,0 +1,276 @@
+package verify
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/curtbushko/zoom-to-box/internal/box"
+	"github.com/curtbushko/zoom-to-box/internal/directory"
+	"github.com/curtbushko/zoom-to-box/internal/email"
+	"github.com/curtbushko/zoom-to-box/internal/filename"
+	"github.com/curtbushko/zoom-to-box/internal/timefmt"
+	"github.com/curtbushko/zoom-to-box/internal/zoom"
+)
+
+// KindMissingInZoom is a recording in Box that matches no Zoom recording in the date range
+const KindMissingInZoom Kind = "missing_in_zoom"
+
+// RecordingLister lists a user's Zoom cloud recordings
+type RecordingLister interface {
+	GetAllUserRecordings(ctx context.Context, userID string, params zoom.ListRecordingsParams) ([]*zoom.Recording, error)
+}
+
+// DiffOptions controls a comparison of Zoom recordings with Box
+type DiffOptions struct {
+	Layout    *directory.Layout      // Folder layout below the Box zoom folders
+	Template  *filename.Template     // Names the recording files
+	Sanitizer filename.FileSanitizer // Sanitizer the template was created with
+	Users     []User
+
+	From *time.Time // Recordings compared (nil = Zoom's default range); Box folders outside it are ignored
+	To   *time.Time
+
+	PageSize int // Recordings per Zoom list recordings page (0 = 300)
+
+	Skip func(recording *zoom.Recording) bool // Recordings excluded by the recording filters (nil = none)
+}
+
+// DiffEntry is a recording file found on only one side, or on both with different sizes
+type DiffEntry struct {
+	Kind          Kind // KindMissingInBox, KindMissingInZoom, KindSizeMismatch or KindLookupFailed
+	ZoomEmail     string
+	BoxEmail      string
+	BoxPath       string // Path below the user's zoom folder, e.g. "2024/01/15/standup.mp4"
+	RecordingUUID string // "" for files only in Box
+	FileID        string
+	Topic         string
+	ZoomSize      int64
+	BoxSize       int64
+	Detail        string
+}
+
+// DiffReport is the outcome of a comparison
+type DiffReport struct {
+	ZoomFiles int // Zoom recording files compared
+	BoxFiles  int // Box recordings compared
+	Matched   int // Files in both with the same size
+	Entries   []DiffEntry
+}
+
+// Diff compares each user's Zoom MP4 recordings with the recordings in their Box zoom folder
+// Zoom files are expected where a run would upload them: the directory.layout folder of the
+// recording date and the filename.template name. Box MP4s in the folders of the compared
+// dates that no Zoom recording maps to are reported as missing in Zoom; previews and other
+// files are ignored. Nothing is downloaded, uploaded or changed.
+func Diff(ctx context.Context, zoomClient RecordingLister, boxClient box.BoxClient, opts DiffOptions) (*DiffReport, error) {
+	report := &DiffReport{}
+	verifier := NewVerifier(boxClient, Options{Layout: opts.Layout})
+	pageSize := opts.PageSize
+	if pageSize == 0 {
+		pageSize = 300
+	}
+
+	for _, user := range opts.Users {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		recordings, err := zoomClient.GetAllUserRecordings(ctx, user.ZoomEmail, zoom.ListRecordingsParams{From: opts.From, To: opts.To, PageSize: pageSize})
+		if err != nil {
+			report.Entries = append(report.Entries, DiffEntry{Kind: KindLookupFailed, ZoomEmail: user.ZoomEmail, BoxEmail: user.BoxEmail,
+				Detail: fmt.Sprintf("failed to list Zoom recordings: %v", err)})
+			continue
+		}
+		expected, err := expectedFiles(opts, user, recordings)
+		if err != nil {
+			return nil, err
+		}
+		report.ZoomFiles += len(expected)
+
+		var boxFiles map[string]box.Item
+		zoomFolder, err := boxClient.FindZoomFolderByOwner(user.BoxEmail)
+		if err == nil {
+			boxFiles, err = verifier.pathTree(zoomFolder.ID)
+		}
+		if err != nil {
+			report.Entries = append(report.Entries, DiffEntry{Kind: KindLookupFailed, ZoomEmail: user.ZoomEmail, BoxEmail: user.BoxEmail,
+				Detail: fmt.Sprintf("failed to list Box zoom folder: %v", err)})
+			continue
+		}
+
+		folders, err := dateFolders(opts, user, expected)
+		if err != nil {
+			return nil, err
+		}
+		for boxPath, item := range boxFiles {
+			if !isComparedRecording(boxPath) || (folders != nil && !folders[path.Dir(boxPath)]) {
+				continue
+			}
+			report.BoxFiles++
+			if _, ok := expected[boxPath]; ok {
+				continue
+			}
+			report.Entries = append(report.Entries, DiffEntry{Kind: KindMissingInZoom, ZoomEmail: user.ZoomEmail, BoxEmail: user.BoxEmail,
+				BoxPath: boxPath, FileID: item.ID, BoxSize: item.Size})
+		}
+
+		for boxPath, entry := range expected {
+			item, ok := boxFiles[boxPath]
+			switch {
+			case !ok:
+				entry.Kind = KindMissingInBox
+			case entry.ZoomSize > 0 && item.Size != entry.ZoomSize:
+				entry.Kind, entry.BoxSize = KindSizeMismatch, item.Size
+			default:
+				report.Matched++
+				continue
+			}
+			report.Entries = append(report.Entries, entry)
+		}
+	}
+
+	sort.SliceStable(report.Entries, func(i, j int) bool {
+		a, b := report.Entries[i], report.Entries[j]
+		if a.ZoomEmail != b.ZoomEmail {
+			return a.ZoomEmail < b.ZoomEmail
+		}
+		return a.BoxPath < b.BoxPath
+	})
+	return report, nil
+}
+
+// expectedFiles returns the Box path of each of user's MP4 recording files
+func expectedFiles(opts DiffOptions, user User, recordings []*zoom.Recording) (map[string]DiffEntry, error) {
+	username := email.ExtractUsername(user.BoxEmail)
+	expected := make(map[string]DiffEntry)
+	for _, recording := range recordings {
+		if opts.Skip != nil && opts.Skip(recording) {
+			continue
+		}
+		meetingTime := timefmt.In(recording.StartTime)
+		folderPath, err := opts.Layout.FolderPath(username, meetingTime)
+		if err != nil {
+			return nil, err
+		}
+		hostEmail := recording.HostEmail
+		if hostEmail == "" {
+			hostEmail = user.ZoomEmail
+		}
+
+		for _, recordingFile := range recording.RecordingFiles {
+			if recordingFile.FileType != "MP4" || recordingFile.DownloadURL == "" {
+				continue
+			}
+			baseName, err := opts.Template.Render(filename.NewTemplateData(opts.Sanitizer, recording.Topic, meetingTime, hostEmail,
+				recordingFile.RecordingType, recordingFile.FileType, recording.UUID, recordingFile.ID))
+			if err != nil {
+				return nil, err
+			}
+			baseName += opts.Template.Qualifier(recording.RecordingFiles, recordingFile)
+			name := opts.Template.Unique(folderPath, baseName, ".mp4", recording.UUID+"/"+recordingFile.ID)
+			boxPath := path.Join(folderPath, name)
+			expected[boxPath] = DiffEntry{
+				ZoomEmail:     user.ZoomEmail,
+				BoxEmail:      user.BoxEmail,
+				BoxPath:       boxPath,
+				RecordingUUID: recording.UUID,
+				FileID:        recordingFile.ID,
+				Topic:         recording.Topic,
+				ZoomSize:      recordingFile.FileSize,
+			}
+		}
+	}
+	return expected, nil
+}
+
+// dateFolders returns the layout folders of every day in the compared date range, so Box
+// recordings from other dates are not reported as missing in Zoom. Without a complete range
+// it returns nil and every Box folder is compared.
+func dateFolders(opts DiffOptions, user User, expected map[string]DiffEntry) (map[string]bool, error) {
+	if opts.From == nil || opts.To == nil {
+		return nil, nil
+	}
+	username := email.ExtractUsername(user.BoxEmail)
+	folders := make(map[string]bool)
+	from, to := timefmt.In(*opts.From), timefmt.In(*opts.To)
+	for day := time.Date(from.Year(), from.Month(), from.Day(), 12, 0, 0, 0, from.Location()); !day.After(to); day = day.AddDate(0, 0, 1) {
+		folderPath, err := opts.Layout.FolderPath(username, day)
+		if err != nil {
+			return nil, err
+		}
+		folders[folderPath] = true
+	}
+	// Recordings Zoom returned are compared even if their local date falls just outside the range
+	for boxPath := range expected {
+		folders[path.Dir(boxPath)] = true
+	}
+	return folders, nil
+}
+
+// isComparedRecording reports whether a Box file is a recording Diff compares
+func isComparedRecording(boxPath string) bool {
+	name := strings.ToLower(path.Base(boxPath))
+	return strings.HasSuffix(name, ".mp4") && !strings.HasSuffix(name, "-preview.mp4")
+}
+
+// pathTree returns every file below a zoom folder by its path relative to the folder
+func (v *Verifier) pathTree(zoomFolderID string) (map[string]box.Item, error) {
+	files := make(map[string]box.Item)
+	type folder struct{ id, path string }
+	pending := []folder{{id: zoomFolderID}}
+	for len(pending) > 0 {
+		current := pending[0]
+		pending = pending[1:]
+		items, err := v.client.ListFolderItems(current.id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list Box folder %s: %w", current.id, err)
+		}
+		for _, item := range items.Entries {
+			itemPath := path.Join(current.path, item.Name)
+			switch item.Type {
+			case box.ItemTypeFile:
+				files[itemPath] = item
+			case box.ItemTypeFolder:
+				pending = append(pending, folder{id: item.ID, path: itemPath})
+			}
+		}
+	}
+	return files, nil
+}
+
+// WriteDiffCSV writes the diff report
+func WriteDiffCSV(w io.Writer, entries []DiffEntry) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"kind", "zoom_email", "box_email", "box_path", "recording_uuid", "file_id", "topic", "zoom_size_bytes", "box_size_bytes", "detail"}); err != nil {
+		return fmt.Errorf("failed to write diff report: %w", err)
+	}
+	for _, e := range entries {
+		record := []string{
+			string(e.Kind),
+			e.ZoomEmail,
+			e.BoxEmail,
+			e.BoxPath,
+			e.RecordingUUID,
+			e.FileID,
+			e.Topic,
+			strconv.FormatInt(e.ZoomSize, 10),
+			strconv.FormatInt(e.BoxSize, 10),
+			e.Detail,
+		}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("failed to write diff report: %w", err)
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write diff report: %w", err)
+	}
+	return nil
+}
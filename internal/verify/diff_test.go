@@ -0,0 +1,116 @@
+package verify
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/curtbushko/zoom-to-box/internal/directory"
+	"github.com/curtbushko/zoom-to-box/internal/filename"
+	"github.com/curtbushko/zoom-to-box/internal/zoom"
+)
+
+// fakeZoomClient returns fixed recordings per user
+type fakeZoomClient struct {
+	recordings map[string][]*zoom.Recording
+}
+
+func (c *fakeZoomClient) GetAllUserRecordings(ctx context.Context, userID string, params zoom.ListRecordingsParams) ([]*zoom.Recording, error) {
+	return c.recordings[userID], nil
+}
+
+func TestDiff(t *testing.T) {
+	start := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	recording := func(uuid, topic string, size int64) *zoom.Recording {
+		return &zoom.Recording{UUID: uuid, Topic: topic, StartTime: start, Duration: 30, RecordingFiles: []zoom.RecordingFile{
+			{ID: uuid + "-mp4", FileType: "MP4", FileSize: size, DownloadURL: "https://zoom.example.com/" + uuid},
+			{ID: uuid + "-json", FileType: "TIMELINE", FileSize: 10, DownloadURL: "https://zoom.example.com/" + uuid + ".json"},
+		}}
+	}
+	zoomClient := &fakeZoomClient{recordings: map[string][]*zoom.Recording{
+		"jane.smith@zoom.example.com": {
+			recording("u1", "Standup", 9),
+			recording("u2", "Planning", 9),
+			recording("u3", "Review", 9),
+			recording("u4", "Short", 9),
+		},
+	}}
+
+	client := newFakeBoxClient()
+	client.zoomFolders["jane.smith@box.example.com"] = "zoom"
+	client.addFolder("zoom", "y2024", "2024")
+	client.addFolder("y2024", "m01", "01")
+	client.addFolder("m01", "d15", "15")
+	client.addFolder("y2024", "m03", "03")
+	client.addFile("d15", "f1", "standup-1030.mp4", []byte("recording"))
+	client.addFile("d15", "f2", "planning-1030.mp4", []byte("recording"))
+	client.addFile("d15", "f3", "short-1030.mp4", []byte("rec"))
+	client.addFile("d15", "f4", "deleted-1030.mp4", []byte("recording"))
+	client.addFile("d15", "f5", "standup-1030.json", []byte("{}"))
+	client.addFile("d15", "f6", "standup-1030-preview.mp4", []byte("rec"))
+	client.addFile("m03", "f7", "outside-range.mp4", []byte("recording"))
+	for _, items := range client.items {
+		for i := range items {
+			if file, ok := client.files[items[i].ID]; ok {
+				items[i].Size = file.Size
+			}
+		}
+	}
+
+	layout, err := directory.NewLayout("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sanitizer := filename.NewFileSanitizer(filename.FileSanitizerOptions{})
+	template, err := filename.NewTemplate(filename.DefaultTemplate, sanitizer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	from, to := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	report, err := Diff(context.Background(), zoomClient, client, DiffOptions{
+		Layout:    layout,
+		Template:  template,
+		Sanitizer: sanitizer,
+		Users:     []User{{ZoomEmail: "jane.smith@zoom.example.com", BoxEmail: "jane.smith@box.example.com"}},
+		From:      &from,
+		To:        &to,
+		Skip:      func(recording *zoom.Recording) bool { return recording.Topic == "Planning" },
+	})
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+
+	got := make(map[string]Kind)
+	for _, entry := range report.Entries {
+		got[entry.BoxPath] = entry.Kind
+	}
+	want := map[string]Kind{
+		"2024/01/15/review-1030.mp4":   KindMissingInBox,
+		"2024/01/15/short-1030.mp4":    KindSizeMismatch,
+		"2024/01/15/planning-1030.mp4": KindMissingInZoom, // Filtered out, so not expected in Box
+		"2024/01/15/deleted-1030.mp4":  KindMissingInZoom,
+	}
+	if len(got) != len(want) {
+		t.Errorf("Expected %d differences, got %d: %v", len(want), len(got), got)
+	}
+	for boxPath, kind := range want {
+		if got[boxPath] != kind {
+			t.Errorf("Expected %s to be %s, got %q", boxPath, kind, got[boxPath])
+		}
+	}
+	if report.ZoomFiles != 3 || report.BoxFiles != 4 || report.Matched != 1 {
+		t.Errorf("Expected 3 Zoom files, 4 Box files and 1 match, got %d, %d and %d", report.ZoomFiles, report.BoxFiles, report.Matched)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteDiffCSV(&buf, report.Entries); err != nil {
+		t.Fatalf("WriteDiffCSV failed: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if !strings.HasPrefix(lines[0], "kind,zoom_email,box_email,box_path,") || len(lines) != len(want)+1 {
+		t.Errorf("Unexpected report:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,494 @@
+// Package verify audits downloaded recordings against their copies in Box
+// It walks the local download tree and the upload tracking CSVs, looks every file up in the
+// user's Box zoom folder without changing anything, and reports the discrepancies: files
+// missing in Box, size or SHA1 mismatches, and local files that belong to no known user.
+package verify
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/curtbushko/zoom-to-box/internal/atrest"
+	"github.com/curtbushko/zoom-to-box/internal/box"
+	"github.com/curtbushko/zoom-to-box/internal/directory"
+	"github.com/curtbushko/zoom-to-box/internal/email"
+	"github.com/curtbushko/zoom-to-box/internal/tracking"
+)
+
+// Kind classifies a discrepancy
+type Kind string
+
+// Discrepancy kinds
+const (
+	KindMissingInBox     Kind = "missing_in_box"    // No file with the name in the expected Box folder
+	KindSizeMismatch     Kind = "size_mismatch"     // Box has the file with a different size
+	KindChecksumMismatch Kind = "checksum_mismatch" // Box has the file with a different SHA1
+	KindOrphanedLocal    Kind = "orphaned_local"    // Local file outside the directory.layout folders of the known users
+	KindLookupFailed     Kind = "lookup_failed"     // Box could not be queried for the file
+)
+
+// User is a user whose recordings are verified
+type User struct {
+	ZoomEmail string
+	BoxEmail  string
+}
+
+// Options controls what is verified
+type Options struct {
+	OutputDir string            // Local download directory
+	Layout    *directory.Layout // Folder layout below OutputDir and the Box zoom folders
+	Users     []User            // Users to verify; local files of other users are reported as orphaned
+
+	Local     bool // Walk the local download tree
+	Tracked   bool // Check the uploads recorded in the tracking CSVs whose local copy is gone
+	Checksums bool // Compare SHA1 checksums of local files, not just sizes
+
+	Cipher *atrest.Cipher // Decrypts local files encrypted at rest for checksums (nil = not configured)
+}
+
+// Discrepancy is a file whose local and Box copies disagree
+type Discrepancy struct {
+	Kind      Kind
+	ZoomEmail string
+	BoxEmail  string
+	LocalPath string // "" for tracked uploads without a local copy
+	BoxPath   string // Path below the user's zoom folder, e.g. "2024/01/15/standup.mp4"
+	LocalSize int64  // Local size, or the size recorded in the tracking CSV
+	BoxSize   int64  // Size in Box (0 when missing)
+	Detail    string
+}
+
+// Report is the outcome of a verification
+type Report struct {
+	LocalFiles    int // Local files checked
+	TrackedFiles  int // Tracked uploads without a local copy checked
+	Discrepancies []Discrepancy
+}
+
+// Verifier compares local files and tracked uploads with Box
+type Verifier struct {
+	client box.BoxClient
+	opts   Options
+
+	folderIDs map[string]string            // "<zoom folder ID>/<folder path>" -> folder ID ("" = missing)
+	listings  map[string]map[string]string // folder ID -> file name -> file ID
+	trees     map[string]map[string][]string
+}
+
+// NewVerifier creates a verifier that looks files up with client
+func NewVerifier(client box.BoxClient, opts Options) *Verifier {
+	return &Verifier{
+		client:    client,
+		opts:      opts,
+		folderIDs: make(map[string]string),
+		listings:  make(map[string]map[string]string),
+		trees:     make(map[string]map[string][]string),
+	}
+}
+
+// localFile is a downloaded file mapped to its place in Box
+type localFile struct {
+	path       string
+	folderPath string
+	size       int64
+}
+
+// Run verifies the selected sources and returns the discrepancies, orphaned files first and
+// then by user
+func (v *Verifier) Run(ctx context.Context) (*Report, error) {
+	report := &Report{}
+
+	byUsername := make(map[string]User)
+	byZoomEmail := make(map[string]User)
+	var usernames []string
+	for _, user := range v.opts.Users {
+		username := email.ExtractUsername(user.BoxEmail)
+		if username == "" {
+			continue
+		}
+		byUsername[username] = user
+		byZoomEmail[strings.ToLower(user.ZoomEmail)] = user
+		usernames = append(usernames, username)
+	}
+
+	localFiles := make(map[string][]localFile)
+	if v.opts.Local {
+		orphans, err := v.walkLocal(ctx, usernames, localFiles)
+		if err != nil {
+			return nil, err
+		}
+		report.Discrepancies = append(report.Discrepancies, orphans...)
+		report.LocalFiles += len(orphans)
+	}
+
+	var tracked map[string][]tracking.UploadEntry
+	if v.opts.Tracked {
+		var err error
+		if tracked, err = v.readTracked(usernames, byZoomEmail); err != nil {
+			return nil, err
+		}
+	}
+
+	sort.Strings(usernames)
+	for _, username := range usernames {
+		user := byUsername[username]
+		files := localFiles[username]
+		entries := tracked[username]
+		if len(files) == 0 && len(entries) == 0 {
+			continue
+		}
+
+		zoomFolder, err := v.client.FindZoomFolderByOwner(user.BoxEmail)
+		if err != nil {
+			for _, file := range files {
+				report.Discrepancies = append(report.Discrepancies, *v.discrepancy(KindLookupFailed, user, file.path, path.Join(file.folderPath, filepath.Base(file.path)), file.size, 0,
+					fmt.Sprintf("failed to find zoom folder: %v", err)))
+			}
+			for _, entry := range entries {
+				report.Discrepancies = append(report.Discrepancies, *v.discrepancy(KindLookupFailed, user, "", entry.FileName, entry.RecordingSize, 0,
+					fmt.Sprintf("failed to find zoom folder: %v", err)))
+			}
+			report.LocalFiles += len(files)
+			report.TrackedFiles += len(entries)
+			continue
+		}
+
+		localNames := make(map[string]bool, len(files))
+		for _, file := range files {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			report.LocalFiles++
+			localNames[filepath.Base(file.path)] = true
+			if d := v.checkLocalFile(user, zoomFolder.ID, file); d != nil {
+				report.Discrepancies = append(report.Discrepancies, *d)
+			}
+		}
+
+		for _, entry := range entries {
+			if localNames[entry.FileName] {
+				continue // Already checked at its exact location
+			}
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			report.TrackedFiles++
+			if d := v.checkTracked(user, zoomFolder.ID, entry); d != nil {
+				report.Discrepancies = append(report.Discrepancies, *d)
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// walkLocal maps the files in the download tree to users and returns the orphaned ones
+func (v *Verifier) walkLocal(ctx context.Context, usernames []string, files map[string][]localFile) ([]Discrepancy, error) {
+	var orphans []Discrepancy
+	err := filepath.WalkDir(v.opts.OutputDir, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if entry.IsDir() || !entry.Type().IsRegular() || isBookkeeping(entry.Name()) {
+			return nil
+		}
+
+		relDir, err := filepath.Rel(v.opts.OutputDir, filepath.Dir(filePath))
+		if err != nil {
+			return err
+		}
+		// Reports, status files and the global uploads CSV live in the download directory itself
+		if relDir == "." {
+			return nil
+		}
+
+		// Files encrypted at rest are compared by the size of their plaintext, as uploaded
+		size, _, err := atrest.Inspect(filePath)
+		if err != nil {
+			return err
+		}
+		username, folderPath, ok := v.opts.Layout.Owner(relDir, usernames)
+		if !ok {
+			orphans = append(orphans, Discrepancy{
+				Kind:      KindOrphanedLocal,
+				LocalPath: filePath,
+				LocalSize: size,
+				Detail:    "not in a directory.layout folder of a known user",
+			})
+			return nil
+		}
+		files[username] = append(files[username], localFile{path: filePath, folderPath: folderPath, size: size})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk %s: %w", v.opts.OutputDir, err)
+	}
+	return orphans, nil
+}
+
+// isBookkeeping reports whether a file in the download tree is written by zoom-to-box itself
+// rather than downloaded from Zoom
+func isBookkeeping(name string) bool {
+	return name == "uploads.csv" || strings.HasPrefix(name, ".") || strings.HasSuffix(name, ".tmp")
+}
+
+// checkLocalFile looks a local file up at its layout location below the zoom folder
+func (v *Verifier) checkLocalFile(user User, zoomFolderID string, file localFile) *Discrepancy {
+	name := filepath.Base(file.path)
+	boxPath := path.Join(file.folderPath, name)
+
+	folderID, err := v.folderID(zoomFolderID, file.folderPath)
+	if err != nil {
+		return v.discrepancy(KindLookupFailed, user, file.path, boxPath, file.size, 0, err.Error())
+	}
+	if folderID == "" {
+		return v.discrepancy(KindMissingInBox, user, file.path, boxPath, file.size, 0, fmt.Sprintf("folder %s does not exist", file.folderPath))
+	}
+	listing, err := v.listing(folderID)
+	if err != nil {
+		return v.discrepancy(KindLookupFailed, user, file.path, boxPath, file.size, 0, err.Error())
+	}
+	fileID, ok := listing[name]
+	if !ok {
+		return v.discrepancy(KindMissingInBox, user, file.path, boxPath, file.size, 0, "")
+	}
+
+	boxFile, err := v.client.GetFile(fileID)
+	if err != nil {
+		return v.discrepancy(KindLookupFailed, user, file.path, boxPath, file.size, 0, fmt.Sprintf("failed to get Box file %s: %v", fileID, err))
+	}
+	if boxFile.Size != file.size {
+		return v.discrepancy(KindSizeMismatch, user, file.path, boxPath, file.size, boxFile.Size, "")
+	}
+
+	if v.opts.Checksums && boxFile.SHA1 != "" {
+		localSum, err := fileSHA1(v.opts.Cipher, file.path)
+		if err != nil {
+			return v.discrepancy(KindLookupFailed, user, file.path, boxPath, file.size, boxFile.Size, err.Error())
+		}
+		if !strings.EqualFold(localSum, boxFile.SHA1) {
+			return v.discrepancy(KindChecksumMismatch, user, file.path, boxPath, file.size, boxFile.Size,
+				fmt.Sprintf("Box SHA1 %s, local SHA1 %s", boxFile.SHA1, localSum))
+		}
+	}
+	return nil
+}
+
+// checkTracked looks a tracked upload whose local copy is gone up anywhere below the zoom folder
+// The tracking CSVs record file names but not folders, so the whole zoom folder is searched.
+func (v *Verifier) checkTracked(user User, zoomFolderID string, entry tracking.UploadEntry) *Discrepancy {
+	tree, err := v.tree(zoomFolderID)
+	if err != nil {
+		return v.discrepancy(KindLookupFailed, user, "", entry.FileName, entry.RecordingSize, 0, err.Error())
+	}
+	fileIDs := tree[entry.FileName]
+	if len(fileIDs) == 0 {
+		return v.discrepancy(KindMissingInBox, user, "", entry.FileName, entry.RecordingSize, 0, "recorded in the uploads CSV")
+	}
+
+	var boxSize int64
+	for _, fileID := range fileIDs {
+		boxFile, err := v.client.GetFile(fileID)
+		if err != nil {
+			return v.discrepancy(KindLookupFailed, user, "", entry.FileName, entry.RecordingSize, 0, fmt.Sprintf("failed to get Box file %s: %v", fileID, err))
+		}
+		if entry.RecordingSize == 0 || boxFile.Size == entry.RecordingSize {
+			return nil
+		}
+		boxSize = boxFile.Size
+	}
+	return v.discrepancy(KindSizeMismatch, user, "", entry.FileName, entry.RecordingSize, boxSize, "size recorded in the uploads CSV")
+}
+
+// readTracked reads the tracking CSVs and groups the entries of the known users by username
+// The global all-uploads.csv is used when present, otherwise each user's uploads.csv.
+// Later entries for the same file replace earlier ones.
+func (v *Verifier) readTracked(usernames []string, byZoomEmail map[string]User) (map[string][]tracking.UploadEntry, error) {
+	var entries []tracking.UploadEntry
+	globalPath := filepath.Join(v.opts.OutputDir, "all-uploads.csv")
+	if _, err := os.Stat(globalPath); err == nil {
+		if entries, err = tracking.ReadUploads(globalPath); err != nil {
+			return nil, err
+		}
+	} else {
+		for _, username := range usernames {
+			userPath := filepath.Join(v.opts.OutputDir, username, "uploads.csv")
+			if _, err := os.Stat(userPath); err != nil {
+				continue
+			}
+			userEntries, err := tracking.ReadUploads(userPath)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, userEntries...)
+		}
+	}
+
+	latest := make(map[string]int)
+	grouped := make(map[string][]tracking.UploadEntry)
+	for _, entry := range entries {
+		user, ok := byZoomEmail[strings.ToLower(entry.ZoomUser)]
+		if !ok {
+			continue
+		}
+		username := email.ExtractUsername(user.BoxEmail)
+		key := username + "/" + entry.FileName
+		if i, seen := latest[key]; seen {
+			grouped[username][i] = entry
+			continue
+		}
+		latest[key] = len(grouped[username])
+		grouped[username] = append(grouped[username], entry)
+	}
+	return grouped, nil
+}
+
+// folderID resolves folderPath below the zoom folder without creating anything ("" = missing)
+func (v *Verifier) folderID(zoomFolderID, folderPath string) (string, error) {
+	parentID := zoomFolderID
+	walked := zoomFolderID
+	for _, part := range strings.Split(folderPath, "/") {
+		if part == "" {
+			continue
+		}
+		walked += "/" + part
+		if id, ok := v.folderIDs[walked]; ok {
+			if id == "" {
+				return "", nil
+			}
+			parentID = id
+			continue
+		}
+
+		folder, err := v.client.FindFolderByName(parentID, part)
+		if err != nil {
+			var boxErr *box.BoxError
+			if errors.As(err, &boxErr) && boxErr.StatusCode == http.StatusNotFound {
+				v.folderIDs[walked] = ""
+				return "", nil
+			}
+			return "", fmt.Errorf("failed to look up Box folder %s: %w", folderPath, err)
+		}
+		v.folderIDs[walked] = folder.ID
+		parentID = folder.ID
+	}
+	return parentID, nil
+}
+
+// listing returns the files in a Box folder by name
+func (v *Verifier) listing(folderID string) (map[string]string, error) {
+	if files, ok := v.listings[folderID]; ok {
+		return files, nil
+	}
+	items, err := v.client.ListFolderItems(folderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Box folder %s: %w", folderID, err)
+	}
+	files := make(map[string]string)
+	for _, item := range items.Entries {
+		if item.Type == box.ItemTypeFile {
+			files[item.Name] = item.ID
+		}
+	}
+	v.listings[folderID] = files
+	return files, nil
+}
+
+// tree returns the IDs of every file below a zoom folder by name
+func (v *Verifier) tree(zoomFolderID string) (map[string][]string, error) {
+	if files, ok := v.trees[zoomFolderID]; ok {
+		return files, nil
+	}
+	files := make(map[string][]string)
+	pending := []string{zoomFolderID}
+	for len(pending) > 0 {
+		folderID := pending[0]
+		pending = pending[1:]
+		items, err := v.client.ListFolderItems(folderID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list Box folder %s: %w", folderID, err)
+		}
+		for _, item := range items.Entries {
+			switch item.Type {
+			case box.ItemTypeFile:
+				files[item.Name] = append(files[item.Name], item.ID)
+			case box.ItemTypeFolder:
+				pending = append(pending, item.ID)
+			}
+		}
+	}
+	v.trees[zoomFolderID] = files
+	return files, nil
+}
+
+// discrepancy builds a discrepancy for user's file
+func (v *Verifier) discrepancy(kind Kind, user User, localPath, boxPath string, localSize, boxSize int64, detail string) *Discrepancy {
+	return &Discrepancy{
+		Kind:      kind,
+		ZoomEmail: user.ZoomEmail,
+		BoxEmail:  user.BoxEmail,
+		LocalPath: localPath,
+		BoxPath:   boxPath,
+		LocalSize: localSize,
+		BoxSize:   boxSize,
+		Detail:    detail,
+	}
+}
+
+// fileSHA1 returns the hex SHA1 of a local file's plaintext, as Box reports it
+func fileSHA1(c *atrest.Cipher, filePath string) (string, error) {
+	file, err := atrest.Open(c, filePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to open %s: %w", filePath, err)
+	}
+	defer file.Close()
+
+	hash := sha1.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", fmt.Errorf("failed to checksum %s: %w", filePath, err)
+	}
+	return fmt.Sprintf("%x", hash.Sum(nil)), nil
+}
+
+// WriteCSV writes the discrepancy report
+func WriteCSV(w io.Writer, discrepancies []Discrepancy) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"kind", "zoom_email", "box_email", "local_path", "box_path", "local_size_bytes", "box_size_bytes", "detail"}); err != nil {
+		return fmt.Errorf("failed to write verify report: %w", err)
+	}
+	for _, d := range discrepancies {
+		record := []string{
+			string(d.Kind),
+			d.ZoomEmail,
+			d.BoxEmail,
+			d.LocalPath,
+			d.BoxPath,
+			strconv.FormatInt(d.LocalSize, 10),
+			strconv.FormatInt(d.BoxSize, 10),
+			d.Detail,
+		}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("failed to write verify report: %w", err)
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write verify report: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+package verify
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/curtbushko/zoom-to-box/internal/box"
+	"github.com/curtbushko/zoom-to-box/internal/directory"
+	"github.com/curtbushko/zoom-to-box/internal/tracking"
+)
+
+// fakeBoxClient serves a fixed folder tree; unused BoxClient methods panic through the nil interface
+type fakeBoxClient struct {
+	box.BoxClient
+	zoomFolders map[string]string     // owner email -> zoom folder ID
+	items       map[string][]box.Item // folder ID -> items
+	files       map[string]*box.File  // file ID -> file
+}
+
+func newFakeBoxClient() *fakeBoxClient {
+	return &fakeBoxClient{
+		zoomFolders: make(map[string]string),
+		items:       make(map[string][]box.Item),
+		files:       make(map[string]*box.File),
+	}
+}
+
+func (c *fakeBoxClient) addFolder(parentID, id, name string) {
+	c.items[parentID] = append(c.items[parentID], box.Item{ID: id, Type: box.ItemTypeFolder, Name: name})
+}
+
+func (c *fakeBoxClient) addFile(folderID, id, name string, content []byte) {
+	c.items[folderID] = append(c.items[folderID], box.Item{ID: id, Type: box.ItemTypeFile, Name: name})
+	c.files[id] = &box.File{ID: id, Name: name, Size: int64(len(content)), SHA1: fmt.Sprintf("%x", sha1.Sum(content))}
+}
+
+func (c *fakeBoxClient) FindZoomFolderByOwner(ownerEmail string) (*box.Folder, error) {
+	id, ok := c.zoomFolders[ownerEmail]
+	if !ok {
+		return nil, &box.BoxError{StatusCode: http.StatusNotFound, Message: "zoom folder not found"}
+	}
+	return &box.Folder{ID: id, Name: "zoom"}, nil
+}
+
+func (c *fakeBoxClient) FindFolderByName(parentID, name string) (*box.Folder, error) {
+	for _, item := range c.items[parentID] {
+		if item.Type == box.ItemTypeFolder && item.Name == name {
+			return &box.Folder{ID: item.ID, Name: item.Name}, nil
+		}
+	}
+	return nil, &box.BoxError{StatusCode: http.StatusNotFound, Message: "folder not found"}
+}
+
+func (c *fakeBoxClient) ListFolderItems(folderID string) (*box.FolderItems, error) {
+	return &box.FolderItems{Entries: c.items[folderID], TotalCount: len(c.items[folderID])}, nil
+}
+
+func (c *fakeBoxClient) GetFile(fileID string) (*box.File, error) {
+	file, ok := c.files[fileID]
+	if !ok {
+		return nil, &box.BoxError{StatusCode: http.StatusNotFound, Message: "file not found"}
+	}
+	return file, nil
+}
+
+func writeLocal(t *testing.T, filePath string, content []byte) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filePath, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestVerifier_Run(t *testing.T) {
+	outputDir := t.TempDir()
+	dayDir := filepath.Join(outputDir, "jane.smith", "2024", "01", "15")
+	writeLocal(t, filepath.Join(dayDir, "ok.mp4"), []byte("recording"))
+	writeLocal(t, filepath.Join(dayDir, "missing.mp4"), []byte("recording"))
+	writeLocal(t, filepath.Join(dayDir, "short.mp4"), []byte("recording"))
+	writeLocal(t, filepath.Join(dayDir, "changed.mp4"), []byte("recording"))
+	writeLocal(t, filepath.Join(outputDir, "jane.smith", "uploads.csv"), []byte("user,file_name\n"))
+	writeLocal(t, filepath.Join(outputDir, "status.json"), []byte("{}"))
+	writeLocal(t, filepath.Join(outputDir, "former.user", "2024", "01", "15", "old.mp4"), []byte("old"))
+
+	// A tracked upload deleted locally, one missing in Box and one of an unknown user
+	tracker, err := tracking.NewGlobalCSVTracker(filepath.Join(outputDir, "all-uploads.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range []tracking.UploadEntry{
+		{ZoomUser: "Jane.Smith@zoom.example.com", FileName: "deleted.mp4", RecordingSize: 7, UploadDate: time.Now()},
+		{ZoomUser: "jane.smith@zoom.example.com", FileName: "gone.mp4", RecordingSize: 5, UploadDate: time.Now()},
+		{ZoomUser: "jane.smith@zoom.example.com", FileName: "ok.mp4", RecordingSize: 9, UploadDate: time.Now()},
+		{ZoomUser: "someone@zoom.example.com", FileName: "other.mp4", RecordingSize: 1, UploadDate: time.Now()},
+	} {
+		if err := tracker.TrackUpload(entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	client := newFakeBoxClient()
+	client.zoomFolders["jane.smith@box.example.com"] = "zoom"
+	client.addFolder("zoom", "y2024", "2024")
+	client.addFolder("y2024", "m01", "01")
+	client.addFolder("m01", "d15", "15")
+	client.addFolder("m01", "d16", "16")
+	client.addFile("d15", "f1", "ok.mp4", []byte("recording"))
+	client.addFile("d15", "f2", "short.mp4", []byte("rec"))
+	client.addFile("d15", "f3", "changed.mp4", []byte("RECORDING"))
+	client.addFile("d16", "f4", "deleted.mp4", []byte("deleted"))
+
+	layout, err := directory.NewLayout("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifier := NewVerifier(client, Options{
+		OutputDir: outputDir,
+		Layout:    layout,
+		Users:     []User{{ZoomEmail: "jane.smith@zoom.example.com", BoxEmail: "jane.smith@box.example.com"}},
+		Local:     true,
+		Tracked:   true,
+		Checksums: true,
+	})
+
+	report, err := verifier.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	got := make(map[string]Kind)
+	for _, d := range report.Discrepancies {
+		got[filepath.Base(d.LocalPath)+"|"+d.BoxPath] = d.Kind
+	}
+	want := map[string]Kind{
+		"old.mp4|":                           KindOrphanedLocal,
+		"missing.mp4|2024/01/15/missing.mp4": KindMissingInBox,
+		"short.mp4|2024/01/15/short.mp4":     KindSizeMismatch,
+		"changed.mp4|2024/01/15/changed.mp4": KindChecksumMismatch,
+		".|gone.mp4":                         KindMissingInBox,
+	}
+	if len(got) != len(want) {
+		t.Errorf("Expected %d discrepancies, got %d: %v", len(want), len(got), got)
+	}
+	for key, kind := range want {
+		if got[key] != kind {
+			t.Errorf("Expected %s to be %s, got %q", key, kind, got[key])
+		}
+	}
+	if report.LocalFiles != 5 || report.TrackedFiles != 2 {
+		t.Errorf("Expected 5 local and 2 tracked files checked, got %d and %d", report.LocalFiles, report.TrackedFiles)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, report.Discrepancies); err != nil {
+		t.Fatalf("WriteCSV failed: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if lines[0] != "kind,zoom_email,box_email,local_path,box_path,local_size_bytes,box_size_bytes,detail" || len(lines) != len(want)+1 {
+		t.Errorf("Unexpected report:\n%s", buf.String())
+	}
+}
+
+func TestVerifier_MissingZoomFolder(t *testing.T) {
+	outputDir := t.TempDir()
+	writeLocal(t, filepath.Join(outputDir, "jane.smith", "2024", "01", "15", "a.mp4"), []byte("a"))
+
+	layout, err := directory.NewLayout("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err := NewVerifier(newFakeBoxClient(), Options{
+		OutputDir: outputDir,
+		Layout:    layout,
+		Users:     []User{{ZoomEmail: "jane.smith@zoom.example.com", BoxEmail: "jane.smith@box.example.com"}},
+		Local:     true,
+	}).Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if len(report.Discrepancies) != 1 || report.Discrepancies[0].Kind != KindLookupFailed {
+		t.Errorf("Expected one lookup failure, got %+v", report.Discrepancies)
+	}
+}
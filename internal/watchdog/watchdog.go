@@ -0,0 +1,194 @@
+// Package watchdog samples the process's resource usage during long runs
+// Memory, goroutine and open file descriptor counts are logged periodically; samples that
+// exceed configured limits, or that grow steadily enough to suggest a leak, are reported
+// so the run can checkpoint and restart before the container is OOM-killed.
+package watchdog
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/curtbushko/zoom-to-box/internal/logging"
+)
+
+// DefaultGrowthSamples is the number of consecutive increases reported as possible leaks
+const DefaultGrowthSamples = 6
+
+// Sample is a snapshot of the process's resource usage
+type Sample struct {
+	Time       time.Time
+	HeapAlloc  uint64 // Bytes of allocated heap objects
+	Sys        uint64 // Bytes of memory obtained from the OS
+	Goroutines int
+	OpenFiles  int // Open file descriptors (-1 when not available on this platform)
+}
+
+// String formats the sample for logs
+func (s Sample) String() string {
+	openFiles := "n/a"
+	if s.OpenFiles >= 0 {
+		openFiles = fmt.Sprintf("%d", s.OpenFiles)
+	}
+	return fmt.Sprintf("heap=%dMB sys=%dMB goroutines=%d open_files=%s",
+		s.HeapAlloc/(1<<20), s.Sys/(1<<20), s.Goroutines, openFiles)
+}
+
+// TakeSample reads the current resource usage
+func TakeSample() Sample {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	return Sample{
+		Time:       time.Now(),
+		HeapAlloc:  mem.HeapAlloc,
+		Sys:        mem.Sys,
+		Goroutines: runtime.NumGoroutine(),
+		OpenFiles:  countOpenFiles(),
+	}
+}
+
+// countOpenFiles counts the entries of /proc/self/fd, returning -1 where it does not exist
+func countOpenFiles() int {
+	entries, err := os.ReadDir("/proc/self/fd")
+	if err != nil {
+		return -1
+	}
+	// The directory handle used to read /proc/self/fd is included in the listing
+	return len(entries) - 1
+}
+
+// Config controls sampling and the limits that trigger OnLimit
+type Config struct {
+	Interval      time.Duration // Time between samples
+	MaxHeapBytes  uint64        // Heap allocation limit (0 = no limit)
+	MaxGoroutines int           // Goroutine limit (0 = no limit)
+	MaxOpenFiles  int           // Open file descriptor limit (0 = no limit)
+	GrowthSamples int           // Consecutive increases reported as a possible leak (default: 6)
+
+	// OnLimit is called once, with the reason, when a limit is exceeded (nil = only logged)
+	OnLimit func(reason string)
+}
+
+// Watchdog periodically samples resource usage and checks it against the configured limits
+type Watchdog struct {
+	config Config
+	sample func() Sample
+
+	mu      sync.Mutex
+	history []Sample
+	tripped bool
+}
+
+// New creates a watchdog; call Run to start sampling
+func New(config Config) *Watchdog {
+	if config.GrowthSamples <= 0 {
+		config.GrowthSamples = DefaultGrowthSamples
+	}
+	return &Watchdog{config: config, sample: TakeSample}
+}
+
+// Run samples resource usage every interval until ctx is cancelled
+func (w *Watchdog) Run(ctx context.Context) {
+	if w.config.Interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(w.config.Interval)
+	defer ticker.Stop()
+
+	w.Check()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.Check()
+		}
+	}
+}
+
+// Check takes a sample, logs it and reports exceeded limits and suspected leaks
+func (w *Watchdog) Check() Sample {
+	sample := w.sample()
+	logging.Info("Resource usage: %s", sample)
+
+	w.mu.Lock()
+	w.history = append(w.history, sample)
+	if len(w.history) > w.config.GrowthSamples+1 {
+		w.history = w.history[len(w.history)-w.config.GrowthSamples-1:]
+	}
+	growing := w.growing()
+	w.mu.Unlock()
+
+	if len(growing) > 0 {
+		logging.Warn("Possible resource leak: %s increased in each of the last %d samples (%s)",
+			strings.Join(growing, ", "), w.config.GrowthSamples, sample)
+	}
+
+	if reason := w.exceeded(sample); reason != "" {
+		logging.Warn("Resource limit exceeded: %s", reason)
+		w.trip(reason)
+	}
+	return sample
+}
+
+// growing returns the resources that increased in every sample of the growth window
+// Must be called with w.mu held.
+func (w *Watchdog) growing() []string {
+	if len(w.history) <= w.config.GrowthSamples {
+		return nil
+	}
+
+	heap, goroutines, files := true, true, true
+	for i := 1; i < len(w.history); i++ {
+		prev, cur := w.history[i-1], w.history[i]
+		heap = heap && cur.HeapAlloc > prev.HeapAlloc
+		goroutines = goroutines && cur.Goroutines > prev.Goroutines
+		files = files && cur.OpenFiles >= 0 && cur.OpenFiles > prev.OpenFiles
+	}
+
+	var growing []string
+	if heap {
+		growing = append(growing, "heap")
+	}
+	if goroutines {
+		growing = append(growing, "goroutines")
+	}
+	if files {
+		growing = append(growing, "open files")
+	}
+	return growing
+}
+
+// exceeded returns why the sample is over a configured limit, or "" if it is within limits
+func (w *Watchdog) exceeded(sample Sample) string {
+	switch {
+	case w.config.MaxHeapBytes > 0 && sample.HeapAlloc > w.config.MaxHeapBytes:
+		return fmt.Sprintf("heap %dMB exceeds %dMB", sample.HeapAlloc/(1<<20), w.config.MaxHeapBytes/(1<<20))
+	case w.config.MaxGoroutines > 0 && sample.Goroutines > w.config.MaxGoroutines:
+		return fmt.Sprintf("%d goroutines exceed %d", sample.Goroutines, w.config.MaxGoroutines)
+	case w.config.MaxOpenFiles > 0 && sample.OpenFiles > w.config.MaxOpenFiles:
+		return fmt.Sprintf("%d open files exceed %d", sample.OpenFiles, w.config.MaxOpenFiles)
+	}
+	return ""
+}
+
+// trip calls OnLimit the first time a limit is exceeded
+func (w *Watchdog) trip(reason string) {
+	w.mu.Lock()
+	if w.tripped {
+		w.mu.Unlock()
+		return
+	}
+	w.tripped = true
+	w.mu.Unlock()
+
+	if w.config.OnLimit != nil {
+		w.config.OnLimit(reason)
+	}
+}
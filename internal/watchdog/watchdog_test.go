@@ -0,0 +1,116 @@
+package watchdog
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// sequence returns a sample function that yields samples in order, repeating the last one
+func sequence(samples ...Sample) func() Sample {
+	i := 0
+	return func() Sample {
+		s := samples[i]
+		if i < len(samples)-1 {
+			i++
+		}
+		return s
+	}
+}
+
+func TestTakeSample(t *testing.T) {
+	sample := TakeSample()
+	if sample.Goroutines < 1 || sample.HeapAlloc == 0 || sample.Sys == 0 {
+		t.Errorf("Unexpected sample: %+v", sample)
+	}
+	if sample.OpenFiles == 0 {
+		t.Errorf("Expected open files to be counted or -1, got 0")
+	}
+}
+
+func TestWatchdog_Limits(t *testing.T) {
+	tests := []struct {
+		name       string
+		config     Config
+		sample     Sample
+		wantReason bool
+	}{
+		{name: "within limits", config: Config{MaxHeapBytes: 100 << 20, MaxGoroutines: 50, MaxOpenFiles: 20}, sample: Sample{HeapAlloc: 10 << 20, Goroutines: 10, OpenFiles: 5}},
+		{name: "no limits configured", config: Config{}, sample: Sample{HeapAlloc: 10 << 30, Goroutines: 10000, OpenFiles: 5000}},
+		{name: "heap over limit", config: Config{MaxHeapBytes: 100 << 20}, sample: Sample{HeapAlloc: 200 << 20}, wantReason: true},
+		{name: "goroutines over limit", config: Config{MaxGoroutines: 50}, sample: Sample{Goroutines: 51}, wantReason: true},
+		{name: "open files over limit", config: Config{MaxOpenFiles: 20}, sample: Sample{OpenFiles: 21}, wantReason: true},
+		{name: "open files unavailable", config: Config{MaxOpenFiles: 20}, sample: Sample{OpenFiles: -1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var reasons []string
+			tt.config.OnLimit = func(reason string) { reasons = append(reasons, reason) }
+			w := New(tt.config)
+			w.sample = sequence(tt.sample)
+
+			w.Check()
+			w.Check()
+
+			if tt.wantReason && len(reasons) != 1 {
+				t.Errorf("Expected OnLimit to be called once, got %v", reasons)
+			}
+			if !tt.wantReason && len(reasons) != 0 {
+				t.Errorf("Expected OnLimit not to be called, got %v", reasons)
+			}
+		})
+	}
+}
+
+func TestWatchdog_Growth(t *testing.T) {
+	w := New(Config{GrowthSamples: 3})
+	w.sample = sequence(
+		Sample{HeapAlloc: 10, Goroutines: 5, OpenFiles: 3},
+		Sample{HeapAlloc: 20, Goroutines: 6, OpenFiles: 3},
+		Sample{HeapAlloc: 30, Goroutines: 7, OpenFiles: 4},
+		Sample{HeapAlloc: 40, Goroutines: 8, OpenFiles: 5},
+	)
+
+	for i := 0; i < 3; i++ {
+		w.Check()
+		w.mu.Lock()
+		growing := w.growing()
+		w.mu.Unlock()
+		if len(growing) != 0 {
+			t.Fatalf("Expected no growth before the window is full, got %v", growing)
+		}
+	}
+
+	w.Check()
+	w.mu.Lock()
+	growing := w.growing()
+	w.mu.Unlock()
+	if len(growing) != 2 || growing[0] != "heap" || growing[1] != "goroutines" {
+		t.Errorf("Expected heap and goroutines to be growing, got %v", growing)
+	}
+}
+
+func TestWatchdog_RunStopsWithContext(t *testing.T) {
+	w := New(Config{Interval: time.Millisecond})
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		w.Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Run did not return after the context was cancelled")
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.history) == 0 {
+		t.Error("Expected at least one sample to be taken")
+	}
+}
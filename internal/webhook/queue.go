@@ -0,0 +1,109 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/curtbushko/zoom-to-box/internal/logging"
+)
+
+// DefaultQueueSize is the number of recordings that can wait for processing
+const DefaultQueueSize = 100
+
+// Queue errors
+var (
+	ErrQueueFull   = errors.New("recording queue is full")
+	ErrQueueClosed = errors.New("recording queue is closed")
+)
+
+// JobProcessor downloads and uploads a queued recording
+type JobProcessor func(ctx context.Context, job RecordingJob) error
+
+// Queue buffers recording jobs between the webhook handler and a single worker
+// Jobs are processed one at a time because the upload destination keeps per-user
+// tracking state. A recording that is already waiting is not queued twice, so Zoom's
+// redeliveries of the same event are absorbed.
+type Queue struct {
+	jobs    chan RecordingJob
+	process JobProcessor
+
+	mu      sync.Mutex
+	pending map[string]bool // Recording UUIDs waiting in the queue
+	closed  bool
+}
+
+// NewQueue creates a queue holding up to size jobs (default: 100)
+func NewQueue(size int, process JobProcessor) *Queue {
+	if size <= 0 {
+		size = DefaultQueueSize
+	}
+	return &Queue{
+		jobs:    make(chan RecordingJob, size),
+		process: process,
+		pending: make(map[string]bool),
+	}
+}
+
+// Enqueue adds a job without blocking
+func (q *Queue) Enqueue(job RecordingJob) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return ErrQueueClosed
+	}
+
+	key := job.Recording.UUID
+	if key != "" && q.pending[key] {
+		return nil
+	}
+
+	select {
+	case q.jobs <- job:
+		if key != "" {
+			q.pending[key] = true
+		}
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Close stops accepting jobs; Run returns once the queued jobs are processed
+func (q *Queue) Close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if !q.closed {
+		q.closed = true
+		close(q.jobs)
+	}
+}
+
+// Run processes jobs until the queue is closed and drained, or ctx is cancelled
+func (q *Queue) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case job, ok := <-q.jobs:
+			if !ok {
+				return
+			}
+
+			q.mu.Lock()
+			delete(q.pending, job.Recording.UUID)
+			q.mu.Unlock()
+
+			if err := q.process(ctx, job); err != nil {
+				logging.Error("Failed to process recording %s for %s: %v", job.Recording.UUID, job.HostEmail, err)
+			}
+		}
+	}
+}
+
+// Len returns the number of jobs waiting to be processed
+func (q *Queue) Len() int {
+	return len(q.jobs)
+}
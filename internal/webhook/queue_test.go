@@ -0,0 +1,51 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	"github.com/curtbushko/zoom-to-box/internal/zoom"
+)
+
+func newJob(uuid string) RecordingJob {
+	return RecordingJob{HostEmail: "jane@example.com", Recording: &zoom.Recording{UUID: uuid}}
+}
+
+func TestQueue_ProcessesJobsInOrder(t *testing.T) {
+	var processed []string
+	q := NewQueue(10, func(ctx context.Context, job RecordingJob) error {
+		processed = append(processed, job.Recording.UUID)
+		return nil
+	})
+
+	for _, uuid := range []string{"a", "b", "a", "c"} {
+		if err := q.Enqueue(newJob(uuid)); err != nil {
+			t.Fatalf("Enqueue(%s) failed: %v", uuid, err)
+		}
+	}
+	if q.Len() != 3 {
+		t.Errorf("Expected duplicate pending recording to be dropped, queue has %d jobs", q.Len())
+	}
+
+	q.Close()
+	q.Run(context.Background())
+
+	if len(processed) != 3 || processed[0] != "a" || processed[1] != "b" || processed[2] != "c" {
+		t.Errorf("Unexpected processing order: %v", processed)
+	}
+
+	if err := q.Enqueue(newJob("d")); err != ErrQueueClosed {
+		t.Errorf("Expected ErrQueueClosed after Close, got %v", err)
+	}
+}
+
+func TestQueue_Full(t *testing.T) {
+	q := NewQueue(1, func(ctx context.Context, job RecordingJob) error { return nil })
+
+	if err := q.Enqueue(newJob("a")); err != nil {
+		t.Fatalf("First Enqueue failed: %v", err)
+	}
+	if err := q.Enqueue(newJob("b")); err != ErrQueueFull {
+		t.Errorf("Expected ErrQueueFull, got %v", err)
+	}
+}
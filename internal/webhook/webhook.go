@@ -0,0 +1,214 @@
+// Package webhook receives Zoom webhook events so new recordings can be processed in near-real-time
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/curtbushko/zoom-to-box/internal/logging"
+	"github.com/curtbushko/zoom-to-box/internal/zoom"
+)
+
+// Zoom webhook headers and event types
+const (
+	SignatureHeader = "x-zm-signature"
+	TimestampHeader = "x-zm-request-timestamp"
+
+	EventURLValidation      = "endpoint.url_validation"
+	EventRecordingCompleted = "recording.completed"
+)
+
+// Handler defaults
+const (
+	DefaultMaxClockSkew = 5 * time.Minute // Zoom recommends rejecting requests older than this
+	maxBodySize         = 1 << 20         // Recording payloads are small; anything bigger is not from Zoom
+)
+
+// Event is the envelope of a Zoom webhook request
+type Event struct {
+	Event         string          `json:"event"`
+	EventTS       int64           `json:"event_ts"`
+	Payload       json.RawMessage `json:"payload"`
+	DownloadToken string          `json:"download_token,omitempty"`
+}
+
+// recordingPayload is the payload of a recording.completed event
+type recordingPayload struct {
+	AccountID string `json:"account_id"`
+	Object    struct {
+		zoom.Recording
+		HostEmail string `json:"host_email"`
+	} `json:"object"`
+}
+
+// urlValidationPayload is the payload of an endpoint.url_validation event
+type urlValidationPayload struct {
+	PlainToken string `json:"plainToken"`
+}
+
+// RecordingJob is a completed recording waiting to be downloaded and uploaded
+type RecordingJob struct {
+	HostEmail     string
+	Recording     *zoom.Recording
+	DownloadToken string // Short-lived token Zoom includes when the app requests it
+	ReceivedAt    time.Time
+}
+
+// EnqueueFunc accepts a job for asynchronous processing
+// Returning an error makes the handler respond 503 so Zoom redelivers the event later.
+type EnqueueFunc func(job RecordingJob) error
+
+// handler validates Zoom webhook requests and enqueues completed recordings
+type handler struct {
+	secretToken  string
+	enqueue      EnqueueFunc
+	maxClockSkew time.Duration
+	now          func() time.Time
+}
+
+// NewHandler creates an http.Handler for Zoom webhook events signed with secretToken
+func NewHandler(secretToken string, enqueue EnqueueFunc) http.Handler {
+	return &handler{
+		secretToken:  secretToken,
+		enqueue:      enqueue,
+		maxClockSkew: DefaultMaxClockSkew,
+		now:          time.Now,
+	}
+}
+
+// ServeHTTP handles a single webhook delivery
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize+1))
+	if err != nil {
+		http.Error(w, "failed to read request body", http.StatusBadRequest)
+		return
+	}
+	if len(body) > maxBodySize {
+		http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	if err := h.verifySignature(r.Header, body); err != nil {
+		logging.Warn("Rejected webhook request from %s: %v", r.RemoteAddr, err)
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	var event Event
+	if err := json.Unmarshal(body, &event); err != nil {
+		http.Error(w, "invalid event payload", http.StatusBadRequest)
+		return
+	}
+
+	switch event.Event {
+	case EventURLValidation:
+		h.handleURLValidation(w, event)
+	case EventRecordingCompleted:
+		h.handleRecordingCompleted(w, event)
+	default:
+		// Acknowledge events we don't act on so Zoom doesn't keep retrying them
+		logging.Debug("Ignoring webhook event %s", event.Event)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// verifySignature checks the v0 HMAC-SHA256 signature and request timestamp
+func (h *handler) verifySignature(header http.Header, body []byte) error {
+	timestamp := header.Get(TimestampHeader)
+	signature := header.Get(SignatureHeader)
+	if timestamp == "" || signature == "" {
+		return errors.New("missing signature headers")
+	}
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid timestamp %q", timestamp)
+	}
+	skew := h.now().Sub(time.Unix(seconds, 0))
+	if skew < 0 {
+		skew = -skew
+	}
+	if skew > h.maxClockSkew {
+		return fmt.Errorf("timestamp is %v away from the current time", skew.Round(time.Second))
+	}
+
+	expected := "v0=" + h.sign("v0:"+timestamp+":"+string(body))
+	if !hmac.Equal([]byte(signature), []byte(expected)) {
+		return errors.New("signature mismatch")
+	}
+	return nil
+}
+
+// handleURLValidation answers Zoom's challenge-response check for the endpoint URL
+func (h *handler) handleURLValidation(w http.ResponseWriter, event Event) {
+	var payload urlValidationPayload
+	if err := json.Unmarshal(event.Payload, &payload); err != nil || payload.PlainToken == "" {
+		http.Error(w, "invalid url validation payload", http.StatusBadRequest)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{
+		"plainToken":     payload.PlainToken,
+		"encryptedToken": h.sign(payload.PlainToken),
+	})
+}
+
+// handleRecordingCompleted enqueues the recording for download and upload
+func (h *handler) handleRecordingCompleted(w http.ResponseWriter, event Event) {
+	var payload recordingPayload
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		http.Error(w, "invalid recording payload", http.StatusBadRequest)
+		return
+	}
+
+	hostEmail := strings.TrimSpace(payload.Object.HostEmail)
+	if hostEmail == "" {
+		http.Error(w, "recording payload has no host_email", http.StatusBadRequest)
+		return
+	}
+
+	recording := payload.Object.Recording
+	job := RecordingJob{
+		HostEmail:     hostEmail,
+		Recording:     &recording,
+		DownloadToken: event.DownloadToken,
+		ReceivedAt:    h.now(),
+	}
+	if err := h.enqueue(job); err != nil {
+		logging.Warn("Could not enqueue recording %s for %s: %v", recording.UUID, hostEmail, err)
+		http.Error(w, "recording queue unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	logging.Info("Queued recording %q (%s) for %s", recording.Topic, recording.UUID, hostEmail)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// sign returns the hex-encoded HMAC-SHA256 of message keyed with the secret token
+func (h *handler) sign(message string) string {
+	mac := hmac.New(sha256.New, []byte(h.secretToken))
+	mac.Write([]byte(message))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// writeJSON writes v as a JSON response
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
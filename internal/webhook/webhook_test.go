@@ -0,0 +1,162 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testSecret = "webhook-secret-token"
+
+func signRequest(t *testing.T, body string, timestamp time.Time) *http.Request {
+	t.Helper()
+	ts := strconv.FormatInt(timestamp.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(testSecret))
+	mac.Write([]byte("v0:" + ts + ":" + body))
+
+	req := httptest.NewRequest(http.MethodPost, "/zoom/webhook", strings.NewReader(body))
+	req.Header.Set(TimestampHeader, ts)
+	req.Header.Set(SignatureHeader, "v0="+hex.EncodeToString(mac.Sum(nil)))
+	return req
+}
+
+const recordingCompletedBody = `{
+	"event": "recording.completed",
+	"event_ts": 1705312800000,
+	"download_token": "download-token-123",
+	"payload": {
+		"account_id": "acct",
+		"object": {
+			"uuid": "abc==",
+			"id": 123456789,
+			"host_id": "host-1",
+			"host_email": "jane@example.com",
+			"topic": "Weekly Standup",
+			"start_time": "2024-01-15T09:00:00Z",
+			"recording_files": [
+				{"id": "file-1", "file_type": "MP4", "file_size": 1024, "download_url": "https://zoom.us/rec/download/file-1", "status": "completed"}
+			]
+		}
+	}
+}`
+
+func TestHandler_RecordingCompleted(t *testing.T) {
+	var jobs []RecordingJob
+	h := NewHandler(testSecret, func(job RecordingJob) error {
+		jobs = append(jobs, job)
+		return nil
+	})
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, signRequest(t, recordingCompletedBody, time.Now()))
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("Expected 204, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if len(jobs) != 1 {
+		t.Fatalf("Expected 1 queued job, got %d", len(jobs))
+	}
+
+	job := jobs[0]
+	if job.HostEmail != "jane@example.com" || job.DownloadToken != "download-token-123" {
+		t.Errorf("Unexpected job: %+v", job)
+	}
+	if job.Recording.UUID != "abc==" || job.Recording.Topic != "Weekly Standup" {
+		t.Errorf("Unexpected recording: %+v", job.Recording)
+	}
+	if len(job.Recording.RecordingFiles) != 1 || job.Recording.RecordingFiles[0].DownloadURL == "" {
+		t.Errorf("Expected recording files to be parsed, got %+v", job.Recording.RecordingFiles)
+	}
+}
+
+func TestHandler_RejectsInvalidRequests(t *testing.T) {
+	h := NewHandler(testSecret, func(job RecordingJob) error {
+		t.Error("No job should be enqueued")
+		return nil
+	})
+
+	// Signed for the original body but delivered with a different host
+	tampered := signRequest(t, recordingCompletedBody, time.Now())
+	tampered.Body = io.NopCloser(strings.NewReader(strings.Replace(recordingCompletedBody, "jane", "mallory", 1)))
+
+	unsigned := httptest.NewRequest(http.MethodPost, "/zoom/webhook", strings.NewReader(recordingCompletedBody))
+
+	tests := []struct {
+		name   string
+		req    *http.Request
+		status int
+	}{
+		{"tampered body", tampered, http.StatusUnauthorized},
+		{"missing signature", unsigned, http.StatusUnauthorized},
+		{"stale timestamp", signRequest(t, recordingCompletedBody, time.Now().Add(-10*time.Minute)), http.StatusUnauthorized},
+		{"wrong method", httptest.NewRequest(http.MethodGet, "/zoom/webhook", nil), http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, tt.req)
+			if rec.Code != tt.status {
+				t.Errorf("Expected %d, got %d", tt.status, rec.Code)
+			}
+		})
+	}
+}
+
+func TestHandler_URLValidation(t *testing.T) {
+	h := NewHandler(testSecret, func(job RecordingJob) error { return nil })
+
+	body := `{"event":"endpoint.url_validation","payload":{"plainToken":"qgg8vlvZRS6UYooatFL8Aw"}}`
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, signRequest(t, body, time.Now()))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", rec.Code)
+	}
+
+	var response map[string]string
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Invalid response: %v", err)
+	}
+
+	mac := hmac.New(sha256.New, []byte(testSecret))
+	mac.Write([]byte("qgg8vlvZRS6UYooatFL8Aw"))
+	if response["plainToken"] != "qgg8vlvZRS6UYooatFL8Aw" || response["encryptedToken"] != hex.EncodeToString(mac.Sum(nil)) {
+		t.Errorf("Unexpected validation response: %v", response)
+	}
+}
+
+func TestHandler_QueueUnavailable(t *testing.T) {
+	h := NewHandler(testSecret, func(job RecordingJob) error { return ErrQueueFull })
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, signRequest(t, recordingCompletedBody, time.Now()))
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503 so Zoom retries, got %d", rec.Code)
+	}
+}
+
+func TestHandler_IgnoresOtherEvents(t *testing.T) {
+	h := NewHandler(testSecret, func(job RecordingJob) error {
+		t.Error("No job should be enqueued")
+		return nil
+	})
+
+	body := fmt.Sprintf(`{"event":"meeting.started","event_ts":%d,"payload":{}}`, time.Now().UnixMilli())
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, signRequest(t, body, time.Now()))
+
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("Expected 204, got %d", rec.Code)
+	}
+}
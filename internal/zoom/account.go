@@ -0,0 +1,30 @@
+package zoom
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// AccountMe identifies the account of the Server-to-Server OAuth app in account API paths
+const AccountMe = "me"
+
+// AccountRecordingClient defines the interface for listing the recordings of a whole account
+type AccountRecordingClient interface {
+	ListAccountRecordings(ctx context.Context, accountID string, params ListRecordingsParams) (*ListRecordingsResponse, error)
+	GetAllAccountRecordings(ctx context.Context, accountID string, params ListRecordingsParams) ([]*Recording, error)
+}
+
+// ListAccountRecordings retrieves a page of the cloud recordings of every user in the account
+// Requires the recording:read:admin scope; recordings carry the host ID, not the host email.
+func (c *ZoomClient) ListAccountRecordings(ctx context.Context, accountID string, params ListRecordingsParams) (*ListRecordingsResponse, error) {
+	return c.listRecordings(ctx, fmt.Sprintf("%s/accounts/%s/recordings", c.baseURL, url.PathEscape(accountID)), params)
+}
+
+// GetAllAccountRecordings retrieves every cloud recording in the account for the date range,
+// querying it in 30-day chunks like GetAllUserRecordings
+func (c *ZoomClient) GetAllAccountRecordings(ctx context.Context, accountID string, params ListRecordingsParams) ([]*Recording, error) {
+	return c.getAllRecordings(ctx, "account "+accountID, func(ctx context.Context, params ListRecordingsParams) (*ListRecordingsResponse, error) {
+		return c.ListAccountRecordings(ctx, accountID, params)
+	}, params)
+}
@@ -0,0 +1,51 @@
+package zoom
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestGetAllAccountRecordings(t *testing.T) {
+	var windows []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/accounts/me/recordings" {
+			t.Errorf("Unexpected request %s", r.URL.Path)
+		}
+		query := r.URL.Query()
+		windows = append(windows, query.Get("from")+".."+query.Get("to"))
+		w.Header().Set("Content-Type", "application/json")
+		if query.Get("next_page_token") == "" {
+			w.Write([]byte(`{"next_page_token": "page-2", "meetings": [{"uuid": "m1", "host_id": "u1"}]}`))
+			return
+		}
+		w.Write([]byte(`{"meetings": [{"uuid": "m2", "host_id": "u2"}]}`))
+	}))
+	defer server.Close()
+
+	retryClient := NewRetryHTTPClient(HTTPClientConfig{Timeout: 5 * time.Second})
+	client := NewZoomClient(NewAuthenticatedRetryClient(retryClient, staticAuth{}), server.URL)
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 2, 15, 0, 0, 0, 0, time.UTC)
+	recordings, err := client.GetAllAccountRecordings(context.Background(), AccountMe, ListRecordingsParams{From: &from, To: &to})
+	if err != nil {
+		t.Fatalf("GetAllAccountRecordings failed: %v", err)
+	}
+
+	// Two 30-day chunks of two pages each
+	if len(recordings) != 4 || recordings[0].HostID != "u1" || recordings[1].HostID != "u2" {
+		t.Errorf("Expected the recordings of every page and chunk, got %d", len(recordings))
+	}
+	want := []string{"2024-01-01..2024-01-31", "2024-01-01..2024-01-31", "2024-02-01..2024-02-15", "2024-02-01..2024-02-15"}
+	if len(windows) != len(want) {
+		t.Fatalf("Expected requests for %v, got %v", want, windows)
+	}
+	for i := range want {
+		if windows[i] != want[i] {
+			t.Errorf("Request %d: expected %s, got %s", i, want[i], windows[i])
+		}
+	}
+}
@@ -4,6 +4,7 @@ package zoom
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -11,6 +12,10 @@ import (
 	"time"
 
 	"github.com/curtbushko/zoom-to-box/internal/config"
+	"github.com/curtbushko/zoom-to-box/internal/errclass"
+	"github.com/curtbushko/zoom-to-box/internal/logging"
+	"github.com/curtbushko/zoom-to-box/internal/tokencache"
+	"github.com/curtbushko/zoom-to-box/internal/tracing"
 	"github.com/golang-jwt/jwt/v5"
 )
 
@@ -52,37 +57,160 @@ func (e *AuthError) Error() string {
 	return fmt.Sprintf("auth error %s: %s", e.Type, e.Reason)
 }
 
+// ErrorClass returns the class of the underlying error, e.g. errclass.Network when the token
+// endpoint could not be reached, and errclass.Auth otherwise
+func (e *AuthError) ErrorClass() errclass.Class {
+	if class := errclass.Of(e.Err); class != errclass.Unknown {
+		return class
+	}
+	return errclass.Auth
+}
+
+func (e *AuthError) Unwrap() error {
+	return e.Err
+}
+
 // Authenticator defines the interface for Zoom API authentication
 type Authenticator interface {
 	GetAccessToken(ctx context.Context) (*AccessToken, error)
 	ValidateScopes(token *AccessToken, requiredScopes []string) error
 }
 
+// zoomTokenURL is the Zoom OAuth token endpoint used when zoom.oauth_url is not set
+const zoomTokenURL = "https://zoom.us/oauth/token"
+
+// Client secret labels used when logging which credential authenticated
+const (
+	secretPrimary = "client_secret"
+	secretNext    = "client_secret_next"
+)
+
 // ServerToServerAuth implements Server-to-Server OAuth authentication for Zoom
 type ServerToServerAuth struct {
 	config      config.ZoomConfig
 	client      *http.Client
 	cachedToken *AccessToken
+	tokenURL    string
+	preferNext  bool // client_secret_next was accepted after client_secret was rejected
+
+	tokenCache *tokencache.FileCache // Persists tokens between runs (nil = in memory only)
 }
 
 // NewServerToServerAuth creates a new Server-to-Server OAuth authenticator
+// Tokens are requested from cfg.OAuthURL, e.g. the Zoom for Government token endpoint.
 func NewServerToServerAuth(cfg config.ZoomConfig) *ServerToServerAuth {
+	tokenURL := cfg.OAuthURL
+	if tokenURL == "" {
+		tokenURL = zoomTokenURL
+	}
 	return &ServerToServerAuth{
 		config: cfg,
 		client: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout:   30 * time.Second,
+			Transport: tracing.NewTransport(nil),
 		},
+		tokenURL: tokenURL,
 	}
 }
 
+// SetTokenCache persists access tokens in cache so later runs reuse them until they expire
+func (s *ServerToServerAuth) SetTokenCache(cache *tokencache.FileCache) {
+	s.tokenCache = cache
+}
+
+// tokenCacheName identifies this account and app in the token cache
+func (s *ServerToServerAuth) tokenCacheName() string {
+	return "zoom:" + s.config.AccountID + ":" + s.config.ClientID
+}
+
 // GetAccessToken obtains or refreshes an access token using Server-to-Server OAuth
+// When the current client secret is rejected and client_secret_next is configured,
+// the request is retried with the other secret so secret rotations don't interrupt runs.
 func (s *ServerToServerAuth) GetAccessToken(ctx context.Context) (*AccessToken, error) {
 	if s.cachedToken != nil && !s.cachedToken.IsExpired(5*time.Minute) {
 		return s.cachedToken, nil
 	}
 
+	if s.tokenCache != nil {
+		cached, err := s.tokenCache.Load(s.tokenCacheName(), s.config.ClientSecret)
+		if err != nil {
+			logging.Warn("Ignoring Zoom token cache: %v", err)
+		}
+		if cached != nil {
+			s.cachedToken = &AccessToken{
+				AccessToken: cached.AccessToken,
+				TokenType:   cached.TokenType,
+				ExpiresIn:   int(time.Until(cached.ExpiresAt).Seconds()),
+				Scopes:      strings.Fields(cached.Scope),
+				ExpiresAt:   cached.ExpiresAt,
+			}
+			logging.RegisterSecret(cached.AccessToken)
+			return s.cachedToken, nil
+		}
+	}
+
+	labels := []string{secretPrimary}
+	if s.config.ClientSecretNext != "" {
+		labels = append(labels, secretNext)
+		if s.preferNext {
+			labels = []string{secretNext, secretPrimary}
+		}
+	}
+
+	var lastErr error
+	for _, label := range labels {
+		token, err := s.requestToken(ctx, s.secret(label))
+		if err == nil {
+			s.preferNext = label == secretNext
+			if len(labels) > 1 {
+				logging.Info("Zoom access token obtained using %s", label)
+			}
+			s.cachedToken = token
+			logging.RegisterSecret(token.AccessToken)
+			s.saveToken(token)
+			return token, nil
+		}
+
+		lastErr = err
+		if !isCredentialRejection(err) {
+			break
+		}
+		if len(labels) > 1 {
+			logging.Warn("Zoom rejected %s: %v", label, err)
+		}
+	}
+
+	return nil, lastErr
+}
+
+// saveToken stores token in the token cache, if one is set; failures only cost a token request later
+func (s *ServerToServerAuth) saveToken(token *AccessToken) {
+	if s.tokenCache == nil {
+		return
+	}
+	err := s.tokenCache.Save(s.tokenCacheName(), s.config.ClientSecret, tokencache.Token{
+		AccessToken: token.AccessToken,
+		TokenType:   token.TokenType,
+		Scope:       strings.Join(token.Scopes, " "),
+		ExpiresAt:   token.ExpiresAt,
+	})
+	if err != nil {
+		logging.Warn("Failed to save Zoom token to cache: %v", err)
+	}
+}
+
+// secret returns the client secret for a label
+func (s *ServerToServerAuth) secret(label string) string {
+	if label == secretNext {
+		return s.config.ClientSecretNext
+	}
+	return s.config.ClientSecret
+}
+
+// requestToken requests a new access token signed with the given client secret
+func (s *ServerToServerAuth) requestToken(ctx context.Context, clientSecret string) (*AccessToken, error) {
 	// Generate JWT token
-	jwtToken, err := s.generateJWT()
+	jwtToken, err := s.generateJWTWithSecret(clientSecret)
 	if err != nil {
 		return nil, &AuthError{
 			Type:   "jwt_generation",
@@ -92,12 +220,11 @@ func (s *ServerToServerAuth) GetAccessToken(ctx context.Context) (*AccessToken,
 	}
 
 	// Prepare OAuth request
-	tokenURL := "https://zoom.us/oauth/token"
 	data := url.Values{}
 	data.Set("grant_type", "account_credentials")
 	data.Set("account_id", s.config.AccountID)
 
-	req, err := http.NewRequestWithContext(ctx, "POST", tokenURL, strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", s.tokenURL, strings.NewReader(data.Encode()))
 	if err != nil {
 		return nil, &AuthError{
 			Type:   "request_creation",
@@ -158,12 +285,30 @@ func (s *ServerToServerAuth) GetAccessToken(ctx context.Context) (*AccessToken,
 		token.Scopes = strings.Fields(tokenResponse.Scope)
 	}
 
-	s.cachedToken = token
 	return token, nil
 }
 
-// generateJWT generates a JWT token for Server-to-Server OAuth
+// isCredentialRejection reports whether the token endpoint answered and refused the credentials,
+// as opposed to the request never reaching Zoom
+func isCredentialRejection(err error) bool {
+	var authErr *AuthError
+	if !errors.As(err, &authErr) {
+		return false
+	}
+	switch authErr.Type {
+	case "jwt_generation", "request_creation", "request_failed", "response_parsing":
+		return false
+	}
+	return true
+}
+
+// generateJWT generates a JWT token for Server-to-Server OAuth signed with the primary client secret
 func (s *ServerToServerAuth) generateJWT() (string, error) {
+	return s.generateJWTWithSecret(s.config.ClientSecret)
+}
+
+// generateJWTWithSecret generates a JWT token signed with the given client secret
+func (s *ServerToServerAuth) generateJWTWithSecret(clientSecret string) (string, error) {
 	now := time.Now()
 	claims := jwt.MapClaims{
 		"iss": s.config.ClientID,                // Issuer (Client ID)
@@ -176,7 +321,7 @@ func (s *ServerToServerAuth) generateJWT() (string, error) {
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(s.config.ClientSecret))
+	return token.SignedString([]byte(clientSecret))
 }
 
 // ValidateScopes validates that the token has all required scopes
@@ -513,4 +513,65 @@ func TestErrorHandling(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+// TestClientSecretRotation tests falling back to client_secret_next when client_secret is rejected
+func TestClientSecretRotation(t *testing.T) {
+	var signedWith []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assertion := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		for _, secret := range []string{"old_secret", "new_secret"} {
+			if _, err := jwt.Parse(assertion, func(*jwt.Token) (interface{}, error) { return []byte(secret), nil }); err == nil {
+				signedWith = append(signedWith, secret)
+				break
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if len(signedWith) == 0 || signedWith[len(signedWith)-1] != "new_secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error": "invalid_client", "reason": "Invalid client_id or client_secret"}`))
+			return
+		}
+		w.Write([]byte(`{"access_token": "rotated_token", "token_type": "Bearer", "expires_in": 3600}`))
+	}))
+	defer server.Close()
+
+	auth := NewServerToServerAuth(config.ZoomConfig{
+		AccountID:        "test_account",
+		ClientID:         "test_client",
+		ClientSecret:     "old_secret",
+		ClientSecretNext: "new_secret",
+	})
+	auth.tokenURL = server.URL + "/oauth/token"
+	ctx := context.Background()
+
+	token, err := auth.GetAccessToken(ctx)
+	if err != nil {
+		t.Fatalf("Expected fallback to client_secret_next, got error: %v", err)
+	}
+	if token.AccessToken != "rotated_token" {
+		t.Errorf("Expected access token 'rotated_token', got %s", token.AccessToken)
+	}
+
+	// Once the next secret is accepted it is tried first
+	auth.cachedToken = nil
+	if _, err := auth.GetAccessToken(ctx); err != nil {
+		t.Fatalf("Failed to get token with remembered secret: %v", err)
+	}
+
+	expected := []string{"old_secret", "new_secret", "new_secret"}
+	if strings.Join(signedWith, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected token requests signed with %v, got %v", expected, signedWith)
+	}
+
+	// Without a next secret the rejection is returned as-is
+	auth = NewServerToServerAuth(config.ZoomConfig{
+		AccountID:    "test_account",
+		ClientID:     "test_client",
+		ClientSecret: "old_secret",
+	})
+	auth.tokenURL = server.URL + "/oauth/token"
+	if _, err := auth.GetAccessToken(ctx); err == nil || !strings.Contains(err.Error(), "invalid_client") {
+		t.Errorf("Expected invalid_client error, got %v", err)
+	}
+}
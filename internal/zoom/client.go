@@ -4,6 +4,7 @@ package zoom
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -11,6 +12,8 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/curtbushko/zoom-to-box/internal/logging"
 )
 
 // CloudRecordingClient defines the interface for Zoom Cloud Recording API operations
@@ -50,9 +53,11 @@ func NewZoomClient(httpClient *AuthenticatedRetryClient, baseURL string) *ZoomCl
 
 // ListUserRecordings retrieves cloud recordings for a user
 func (c *ZoomClient) ListUserRecordings(ctx context.Context, userID string, params ListRecordingsParams) (*ListRecordingsResponse, error) {
-	// Build URL
-	endpoint := fmt.Sprintf("%s/users/%s/recordings", c.baseURL, url.PathEscape(userID))
-	
+	return c.listRecordings(ctx, fmt.Sprintf("%s/users/%s/recordings", c.baseURL, url.PathEscape(userID)), params)
+}
+
+// listRecordings retrieves a page of cloud recordings from a recordings list endpoint
+func (c *ZoomClient) listRecordings(ctx context.Context, endpoint string, params ListRecordingsParams) (*ListRecordingsResponse, error) {
 	// Build query parameters
 	queryParams := url.Values{}
 	
@@ -111,11 +116,25 @@ func (c *ZoomClient) ListUserRecordings(ctx context.Context, userID string, para
 	return &result, nil
 }
 
+// EncodeMeetingUUID encodes a meeting ID or UUID for use in an API path
+// Zoom decodes path segments before routing, so a UUID that starts with "/" or contains "//"
+// must be encoded twice; any other UUID is encoded once.
+func EncodeMeetingUUID(uuid string) string {
+	encoded := url.QueryEscape(uuid)
+	if NeedsDoubleEncoding(uuid) {
+		encoded = url.QueryEscape(encoded)
+	}
+	return encoded
+}
+
+// NeedsDoubleEncoding reports whether uuid must be encoded twice in API paths
+func NeedsDoubleEncoding(uuid string) bool {
+	return strings.HasPrefix(uuid, "/") || strings.Contains(uuid, "//")
+}
+
 // GetMeetingRecordings retrieves recordings for a specific meeting
 func (c *ZoomClient) GetMeetingRecordings(ctx context.Context, meetingID string) (*Recording, error) {
-	// Build URL - URL encode the meeting ID to handle UUIDs and special characters
-	// Use QueryEscape to properly encode special characters including forward slashes
-	endpoint := fmt.Sprintf("%s/meetings/%s/recordings?include_fields=download_access_token", c.baseURL, url.QueryEscape(meetingID))
+	endpoint := fmt.Sprintf("%s/meetings/%s/recordings?include_fields=download_access_token", c.baseURL, EncodeMeetingUUID(meetingID))
 
 	// Create request
 	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
@@ -182,11 +201,22 @@ func (c *ZoomClient) GetOAuthAccessToken(ctx context.Context) (string, error) {
 // and handles the Zoom API's 30-day maximum date range limit by splitting
 // the query into 30-day chunks
 func (c *ZoomClient) GetAllUserRecordings(ctx context.Context, userID string, params ListRecordingsParams) ([]*Recording, error) {
+	return c.getAllRecordings(ctx, "user "+userID, func(ctx context.Context, params ListRecordingsParams) (*ListRecordingsResponse, error) {
+		return c.ListUserRecordings(ctx, userID, params)
+	}, params)
+}
+
+// recordingsPager retrieves one page of a recordings list
+type recordingsPager func(ctx context.Context, params ListRecordingsParams) (*ListRecordingsResponse, error)
+
+// getAllRecordings retrieves every page of a recordings list for owner (used in log messages),
+// querying the date range in 30-day chunks
+func (c *ZoomClient) getAllRecordings(ctx context.Context, owner string, list recordingsPager, params ListRecordingsParams) ([]*Recording, error) {
 	var allRecordings []*Recording
 
 	// If no date range specified, use defaults
 	if params.From == nil || params.To == nil {
-		return c.getAllRecordingsForDateRange(ctx, userID, params)
+		return c.getAllRecordingsForDateRange(ctx, owner, list, params)
 	}
 
 	// Split date range into 30-day chunks to comply with Zoom API limit
@@ -208,34 +238,43 @@ func (c *ZoomClient) GetAllUserRecordings(ctx context.Context, userID string, pa
 		chunkParams.To = &currentTo
 		chunkParams.NextPageToken = "" // Reset pagination for each chunk
 
-		fmt.Printf("[DEBUG] Zoom API querying chunk %d for user %s: from=%s to=%s\n",
-			chunkNum, userID, currentFrom.Format("2006-01-02"), currentTo.Format("2006-01-02"))
+		logging.Debug("Zoom API querying chunk %d for %s: from=%s to=%s",
+			chunkNum, owner, currentFrom.Format("2006-01-02"), currentTo.Format("2006-01-02"))
 
-		recordings, err := c.getAllRecordingsForDateRange(ctx, userID, chunkParams)
+		recordings, err := c.getAllRecordingsForDateRange(ctx, owner, list, chunkParams)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get recordings for chunk %d (%s to %s): %w",
 				chunkNum, currentFrom.Format("2006-01-02"), currentTo.Format("2006-01-02"), err)
 		}
 
 		allRecordings = append(allRecordings, recordings...)
-		fmt.Printf("[DEBUG] Zoom API chunk %d complete: fetched %d recordings\n", chunkNum, len(recordings))
+		logging.Debug("Zoom API chunk %d complete: fetched %d recordings", chunkNum, len(recordings))
 
 		// Move to next 30-day period
 		currentFrom = currentTo.AddDate(0, 0, 1) // Add 1 day to avoid overlap
 		chunkNum++
 	}
 
-	fmt.Printf("[DEBUG] Zoom API total for user %s: fetched %d recordings across %d chunks\n",
-		userID, len(allRecordings), chunkNum-1)
+	logging.Debug("Zoom API total for %s: fetched %d recordings across %d chunks",
+		owner, len(allRecordings), chunkNum-1)
 
 	return allRecordings, nil
 }
 
-// getAllRecordingsForDateRange retrieves all recordings for a single date range using pagination
-func (c *ZoomClient) getAllRecordingsForDateRange(ctx context.Context, userID string, params ListRecordingsParams) ([]*Recording, error) {
+// maxPageTokenRestarts is how many times a date range is listed again from its first page
+// after Zoom rejects an expired next_page_token
+const maxPageTokenRestarts = 3
+
+// getAllRecordingsForDateRange retrieves all recordings for a single date range using pagination.
+// Zoom's next_page_token expires after 15 minutes, which a slow run over a user with thousands
+// of recordings can outlast; the range is then listed again from its first page rather than
+// returning the pages listed so far.
+func (c *ZoomClient) getAllRecordingsForDateRange(ctx context.Context, owner string, list recordingsPager, params ListRecordingsParams) ([]*Recording, error) {
 	var recordings []*Recording
 	nextPageToken := params.NextPageToken
 	pageNum := 1
+	restarts := 0
+	totalRecords := 0
 
 	for {
 		// Update params with current page token
@@ -243,14 +282,26 @@ func (c *ZoomClient) getAllRecordingsForDateRange(ctx context.Context, userID st
 		currentParams.NextPageToken = nextPageToken
 
 		// Get page of recordings
-		response, err := c.ListUserRecordings(ctx, userID, currentParams)
+		response, err := list(ctx, currentParams)
 		if err != nil {
+			if nextPageToken != "" && isExpiredPageToken(err) && restarts < maxPageTokenRestarts {
+				restarts++
+				logging.Warn("Zoom next_page_token for %s expired on page %d, listing the date range again (attempt %d of %d)",
+					owner, pageNum, restarts, maxPageTokenRestarts)
+				recordings = nil
+				nextPageToken = ""
+				pageNum = 1
+				continue
+			}
 			return nil, fmt.Errorf("failed to list recordings (page %d, token: %s): %w", pageNum, nextPageToken, err)
 		}
 
 		// Log the API response details for debugging
-		fmt.Printf("[DEBUG] Zoom API page %d for user %s: total_records=%d, page_count=%d, page_size=%d, meetings_in_response=%d, next_page_token=%s\n",
-			pageNum, userID, response.TotalRecords, response.PageCount, response.PageSize, len(response.Meetings), response.NextPageToken)
+		logging.Debug("Zoom API page %d for %s: total_records=%d, page_count=%d, page_size=%d, meetings_in_response=%d, next_page_token=%s",
+			pageNum, owner, response.TotalRecords, response.PageCount, response.PageSize, len(response.Meetings), response.NextPageToken)
+		if response.TotalRecords > totalRecords {
+			totalRecords = response.TotalRecords
+		}
 
 		// Add recordings to result
 		for _, meeting := range response.Meetings {
@@ -261,9 +312,45 @@ func (c *ZoomClient) getAllRecordingsForDateRange(ctx context.Context, userID st
 		if response.NextPageToken == "" {
 			break
 		}
+		if response.NextPageToken == nextPageToken {
+			logging.Warn("Zoom returned the same next_page_token twice for %s on page %d, stopping pagination", owner, pageNum)
+			break
+		}
 		nextPageToken = response.NextPageToken
 		pageNum++
 	}
 
+	if len(recordings) < totalRecords {
+		logging.Warn("Zoom listed %d of %d recordings for %s (from=%s to=%s); the listing may be incomplete",
+			len(recordings), totalRecords, owner, formatDate(params.From), formatDate(params.To))
+	}
+
 	return recordings, nil
+}
+
+// isExpiredPageToken reports whether err is Zoom rejecting an invalid or expired next_page_token
+func isExpiredPageToken(err error) bool {
+	var zoomErr *ZoomAPIError
+	if errors.As(err, &zoomErr) {
+		return mentionsPageToken(zoomErr.Message)
+	}
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode == http.StatusBadRequest && mentionsPageToken(httpErr.Body)
+	}
+	return false
+}
+
+// mentionsPageToken reports whether a Zoom error message is about the next page token
+func mentionsPageToken(message string) bool {
+	message = strings.ToLower(message)
+	return strings.Contains(message, "next_page_token") || strings.Contains(message, "next page token")
+}
+
+// formatDate formats an optional date of a recordings query for log messages
+func formatDate(date *time.Time) string {
+	if date == nil {
+		return "default"
+	}
+	return date.Format("2006-01-02")
 }
\ No newline at end of file
@@ -768,4 +768,84 @@ func parseTime(t *testing.T, dateStr string) *time.Time {
 		t.Fatalf("Failed to parse date %s: %v", dateStr, err)
 	}
 	return &date
-}
\ No newline at end of file
+}
+
+func TestEncodeMeetingUUID(t *testing.T) {
+	tests := []struct {
+		uuid     string
+		expected string
+	}{
+		{uuid: "4444AAAiAAAAAiAiAiiAii==", expected: "4444AAAiAAAAAiAiAiiAii%3D%3D"},
+		{uuid: "abc/def+g==", expected: "abc%2Fdef%2Bg%3D%3D"},
+		{uuid: "/ajXp112QmuoKj4854875==", expected: "%252FajXp112QmuoKj4854875%253D%253D"},
+		{uuid: "abc//def==", expected: "abc%252F%252Fdef%253D%253D"},
+	}
+
+	for _, tt := range tests {
+		if got := EncodeMeetingUUID(tt.uuid); got != tt.expected {
+			t.Errorf("EncodeMeetingUUID(%q) = %q, expected %q", tt.uuid, got, tt.expected)
+		}
+	}
+}
+
+func TestPaginationTokenExpiry(t *testing.T) {
+	const expired = `{"code": 300, "message": "The next page token is invalid or expired."}`
+
+	tests := []struct {
+		name         string
+		secondPage   []string // Responses to the second page request, in turn (empty = success)
+		wantErr      bool
+		wantRequests int
+	}{
+		{name: "expired token lists the range again", secondPage: []string{expired}, wantRequests: 4},
+		{name: "token keeps expiring", secondPage: []string{expired, expired, expired, expired}, wantErr: true, wantRequests: 8},
+		{name: "other bad requests are not retried", secondPage: []string{`{"code": 300, "message": "Invalid page_size"}`}, wantErr: true, wantRequests: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := 0
+			secondPage := tt.secondPage
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				if got := r.URL.Query().Get("page_size"); got != "100" {
+					t.Errorf("Expected page_size 100, got %q", got)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				if r.URL.Query().Get("next_page_token") == "" {
+					w.Write([]byte(`{"total_records": 2, "next_page_token": "page-2", "meetings": [{"uuid": "m1"}]}`))
+					return
+				}
+				if len(secondPage) > 0 {
+					body := secondPage[0]
+					secondPage = secondPage[1:]
+					w.WriteHeader(http.StatusBadRequest)
+					w.Write([]byte(body))
+					return
+				}
+				w.Write([]byte(`{"total_records": 2, "meetings": [{"uuid": "m2"}]}`))
+			}))
+			defer server.Close()
+
+			retryClient := NewRetryHTTPClient(HTTPClientConfig{Timeout: 5 * time.Second})
+			client := NewZoomClient(NewAuthenticatedRetryClient(retryClient, staticAuth{}), server.URL)
+
+			recordings, err := client.GetAllUserRecordings(context.Background(), "user@example.com", ListRecordingsParams{PageSize: 100})
+			if requests != tt.wantRequests {
+				t.Errorf("Expected %d requests, got %d", tt.wantRequests, requests)
+			}
+			if tt.wantErr {
+				if err == nil {
+					t.Error("Expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetAllUserRecordings failed: %v", err)
+			}
+			if len(recordings) != 2 || recordings[0].UUID != "m1" || recordings[1].UUID != "m2" {
+				t.Errorf("Expected m1 and m2 once each, got %d recordings", len(recordings))
+			}
+		})
+	}
+}
@@ -14,6 +14,9 @@ import (
 	"time"
 
 	"github.com/curtbushko/zoom-to-box/internal/config"
+	"github.com/curtbushko/zoom-to-box/internal/errclass"
+	"github.com/curtbushko/zoom-to-box/internal/retry"
+	"github.com/curtbushko/zoom-to-box/internal/tracing"
 )
 
 // HTTPClientConfig holds configuration for the retry HTTP client
@@ -22,9 +25,12 @@ type HTTPClientConfig struct {
 	MaxRetries      int           // Maximum number of retries
 	RetryWaitMin    time.Duration // Minimum wait time between retries
 	RetryWaitMax    time.Duration // Maximum wait time between retries
+	RetryMultiplier float64       // Backoff growth factor per retry (default: 2)
 	RetryableStatus []int         // HTTP status codes that should trigger retries
 	FollowRedirects bool          // Whether to follow redirects
 	MaxRedirects    int           // Maximum number of redirects to follow
+
+	RateLimits RateLimits // Request pacing and concurrency (zero = unlimited)
 }
 
 // HTTPClientConfigFromDownloadConfig creates HTTPClientConfig from DownloadConfig
@@ -40,10 +46,20 @@ func HTTPClientConfigFromDownloadConfig(cfg config.DownloadConfig) HTTPClientCon
 	}
 }
 
+// WithRetryPolicy returns a copy of the configuration using the given retry policy
+func (c HTTPClientConfig) WithRetryPolicy(policy retry.Policy) HTTPClientConfig {
+	c.MaxRetries = policy.Retries()
+	c.RetryWaitMin = policy.BaseDelay
+	c.RetryWaitMax = policy.MaxDelay
+	c.RetryMultiplier = policy.Multiplier
+	return c
+}
+
 // RetryHTTPClient is an HTTP client with retry logic and exponential backoff
 type RetryHTTPClient struct {
 	client *http.Client
 	config HTTPClientConfig
+	pacer  *pacer
 }
 
 // NewRetryHTTPClient creates a new HTTP client with retry logic
@@ -55,6 +71,9 @@ func NewRetryHTTPClient(config HTTPClientConfig) *RetryHTTPClient {
 	if config.RetryWaitMax == 0 {
 		config.RetryWaitMax = 5 * time.Second
 	}
+	if config.RetryMultiplier < 1 {
+		config.RetryMultiplier = 2
+	}
 	if len(config.RetryableStatus) == 0 {
 		config.RetryableStatus = []int{429, 500, 502, 503, 504}
 	}
@@ -63,7 +82,8 @@ func NewRetryHTTPClient(config HTTPClientConfig) *RetryHTTPClient {
 	}
 
 	client := &http.Client{
-		Timeout: config.Timeout,
+		Timeout:   config.Timeout,
+		Transport: tracing.NewTransport(nil),
 	}
 
 	// Configure redirect policy
@@ -90,9 +110,15 @@ func NewRetryHTTPClient(config HTTPClientConfig) *RetryHTTPClient {
 	return &RetryHTTPClient{
 		client: client,
 		config: config,
+		pacer:  newPacer(config.RateLimits),
 	}
 }
 
+// SetRateLimits changes the request pacing and concurrency, e.g. once the account's rate tier is known
+func (c *RetryHTTPClient) SetRateLimits(limits RateLimits) {
+	c.pacer.setLimits(limits)
+}
+
 // ZoomAPIError represents a Zoom API error response
 type ZoomAPIError struct {
 	Code    int    `json:"code"`
@@ -104,6 +130,16 @@ func (e *ZoomAPIError) Error() string {
 	return fmt.Sprintf("zoom API error %d: %s", e.Code, e.Message)
 }
 
+// ErrorClass classifies the error by its HTTP status
+func (e *ZoomAPIError) ErrorClass() errclass.Class {
+	return errclass.FromStatus(e.Status)
+}
+
+// IsRetryable reports whether the request failed with a status that may succeed on retry
+func (e *ZoomAPIError) IsRetryable() bool {
+	return isRetryableStatus(e.Status)
+}
+
 // HTTPError represents a general HTTP error
 type HTTPError struct {
 	StatusCode int
@@ -115,12 +151,33 @@ func (e *HTTPError) Error() string {
 	return fmt.Sprintf("HTTP error %d: %s", e.StatusCode, e.Status)
 }
 
+// ErrorClass classifies the error by its HTTP status
+func (e *HTTPError) ErrorClass() errclass.Class {
+	return errclass.FromStatus(e.StatusCode)
+}
+
+// IsRetryable reports whether the request failed with a status that may succeed on retry
+func (e *HTTPError) IsRetryable() bool {
+	return isRetryableStatus(e.StatusCode)
+}
+
 // Do executes an HTTP request with retry logic
 func (c *RetryHTTPClient) Do(req *http.Request) (*http.Response, error) {
 	var resp *http.Response
 	var err error
 
+	release, err := c.pacer.acquire(req.Context())
+	if err != nil {
+		return nil, fmt.Errorf("request cancelled while waiting for a rate limit slot: %w", err)
+	}
+	defer release()
+
 	for attempt := 0; attempt <= c.config.MaxRetries; attempt++ {
+		// Stay within the account's rate limit
+		if err := c.pacer.wait(req.Context()); err != nil {
+			return nil, fmt.Errorf("request cancelled while pacing: %w", err)
+		}
+
 		// Clone the request for retry attempts
 		reqClone := c.cloneRequest(req)
 
@@ -134,6 +191,8 @@ func (c *RetryHTTPClient) Do(req *http.Request) (*http.Response, error) {
 			return nil, fmt.Errorf("request failed after %d attempts: %w", attempt+1, err)
 		}
 
+		c.pacer.observe(resp.StatusCode)
+
 		// Check if we should retry based on status code
 		if c.shouldRetry(resp.StatusCode) {
 			// Read response body for error details
@@ -184,6 +243,12 @@ func (c *RetryHTTPClient) Do(req *http.Request) (*http.Response, error) {
 // cloneRequest creates a copy of the HTTP request for retries
 func (c *RetryHTTPClient) cloneRequest(req *http.Request) *http.Request {
 	reqClone := req.Clone(req.Context())
+	// Each attempt needs a fresh copy of the body, which the previous attempt consumed
+	if req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			reqClone.Body = body
+		}
+	}
 	return reqClone
 }
 
@@ -253,9 +318,9 @@ func (c *RetryHTTPClient) waitForRetry(attempt int, retryAfter time.Duration, re
 			waitTime = c.config.RetryWaitMax
 		}
 	} else {
-		// Exponential backoff: 2^attempt * base + jitter
+		// Exponential backoff: multiplier^attempt * base + jitter
 		base := float64(c.config.RetryWaitMin)
-		exponential := base * math.Pow(2, float64(attempt))
+		exponential := base * math.Pow(c.config.RetryMultiplier, float64(attempt))
 		
 		// Add jitter (±25% of the calculated time)
 		jitter := exponential * 0.25 * (rand.Float64()*2 - 1)
@@ -355,6 +420,16 @@ func (c *AuthenticatedRetryClient) Do(req *http.Request) (*http.Response, error)
 	return c.retryClient.Do(req)
 }
 
+// isRetryableStatus reports whether a response status is worth retrying
+func isRetryableStatus(statusCode int) bool {
+	switch statusCode {
+	case 429, 500, 502, 503, 504:
+		return true
+	default:
+		return false
+	}
+}
+
 // IsRetryableError checks if an error is retryable
 func IsRetryableError(err error) bool {
 	if err == nil {
@@ -363,24 +438,12 @@ func IsRetryableError(err error) bool {
 
 	// Check for Zoom API errors that are retryable
 	if zoomErr, ok := err.(*ZoomAPIError); ok {
-		retryableCodes := []int{429, 500, 502, 503, 504}
-		for _, code := range retryableCodes {
-			if zoomErr.Status == code {
-				return true
-			}
-		}
-		return false
+		return zoomErr.IsRetryable()
 	}
 
 	// Check for HTTP errors that are retryable
 	if httpErr, ok := err.(*HTTPError); ok {
-		retryableCodes := []int{429, 500, 502, 503, 504}
-		for _, code := range retryableCodes {
-			if httpErr.StatusCode == code {
-				return true
-			}
-		}
-		return false
+		return httpErr.IsRetryable()
 	}
 
 	// Network errors are generally retryable
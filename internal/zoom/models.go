@@ -20,6 +20,7 @@ type RecordingFile struct {
 	FilePath       string     `json:"file_path,omitempty"`
 	RecordingType  string     `json:"recording_type,omitempty"`
 	DeletedTime    *time.Time `json:"deleted_time,omitempty"`
+	ThumbnailURL   string     `json:"thumbnail_url,omitempty"`
 }
 
 // ParticipantAudioFile represents an individual participant's audio recording
@@ -41,6 +42,7 @@ type Recording struct {
 	ID                       int64                  `json:"id"`
 	AccountID                string                 `json:"account_id"`
 	HostID                   string                 `json:"host_id"`
+	HostEmail                string                 `json:"host_email,omitempty"`
 	Topic                    string                 `json:"topic"`
 	Type                     int                    `json:"type"`
 	StartTime                time.Time              `json:"start_time"`
@@ -51,10 +53,54 @@ type Recording struct {
 	DownloadAccessToken      string                 `json:"download_access_token,omitempty"`
 	AutoDelete               bool                   `json:"auto_delete,omitempty"`
 	AutoDeleteDate           string                 `json:"auto_delete_date,omitempty"`
+	ThumbnailURL             string                 `json:"thumbnail_url,omitempty"`
 	RecordingFiles           []RecordingFile        `json:"recording_files"`
 	ParticipantAudioFiles    []ParticipantAudioFile `json:"participant_audio_files,omitempty"`
 }
 
+// ThumbnailURLFor returns the poster image URL for a recording file
+// A file-level thumbnail (e.g. of a shared screen view) takes precedence over the meeting thumbnail.
+// An empty string means Zoom did not expose a thumbnail.
+func (r *Recording) ThumbnailURLFor(file RecordingFile) string {
+	if file.ThumbnailURL != "" {
+		return file.ThumbnailURL
+	}
+	return r.ThumbnailURL
+}
+
+// User represents a Zoom user from the users API
+type User struct {
+	ID        string   `json:"id"`
+	Email     string   `json:"email"`
+	FirstName string   `json:"first_name,omitempty"`
+	LastName  string   `json:"last_name,omitempty"`
+	Type      int      `json:"type"`
+	RoleID    string   `json:"role_id,omitempty"`
+	Status    string   `json:"status,omitempty"`
+	GroupIDs  []string `json:"group_ids,omitempty"`
+
+	DisplayName string `json:"display_name,omitempty"` // Returned by the get user API
+}
+
+// InGroup reports whether the user belongs to the group with the given ID
+func (u User) InGroup(groupID string) bool {
+	for _, id := range u.GroupIDs {
+		if id == groupID {
+			return true
+		}
+	}
+	return false
+}
+
+// ListUsersResponse represents the response from the list users API endpoint
+type ListUsersResponse struct {
+	PageCount     int    `json:"page_count"`
+	PageSize      int    `json:"page_size"`
+	TotalRecords  int    `json:"total_records"`
+	NextPageToken string `json:"next_page_token,omitempty"`
+	Users         []User `json:"users"`
+}
+
 // ListRecordingsResponse represents the response from the list recordings API endpoint
 type ListRecordingsResponse struct {
 	From          string      `json:"from"`
@@ -64,4 +110,55 @@ type ListRecordingsResponse struct {
 	TotalRecords  int         `json:"total_records"`
 	NextPageToken string      `json:"next_page_token,omitempty"`
 	Meetings      []Recording `json:"meetings"`
-}
\ No newline at end of file
+}
+// Webinar represents a webinar returned by the list webinars API
+type Webinar struct {
+	UUID      string    `json:"uuid"`
+	ID        int64     `json:"id"`
+	HostID    string    `json:"host_id"`
+	Topic     string    `json:"topic"`
+	Type      int       `json:"type"`
+	StartTime time.Time `json:"start_time"`
+	Duration  int       `json:"duration"`
+}
+
+// ListWebinarsResponse represents the response from the list webinars API endpoint
+type ListWebinarsResponse struct {
+	PageCount     int       `json:"page_count"`
+	PageSize      int       `json:"page_size"`
+	TotalRecords  int       `json:"total_records"`
+	NextPageToken string    `json:"next_page_token,omitempty"`
+	Webinars      []Webinar `json:"webinars"`
+}
+
+// WebinarInstance represents a single past occurrence of a webinar
+type WebinarInstance struct {
+	UUID      string    `json:"uuid"`
+	StartTime time.Time `json:"start_time"`
+}
+
+// ListWebinarInstancesResponse represents the response from the past webinar instances API endpoint
+type ListWebinarInstancesResponse struct {
+	Webinars []WebinarInstance `json:"webinars"`
+}
+
+// Participant represents an attendee of a past meeting from the past meeting participants API
+// Zoom lists a participant once per join, so someone who rejoined appears more than once.
+type Participant struct {
+	ID        string    `json:"id,omitempty"`
+	UserID    string    `json:"user_id,omitempty"`
+	Name      string    `json:"name"`
+	UserEmail string    `json:"user_email,omitempty"`
+	JoinTime  time.Time `json:"join_time"`
+	LeaveTime time.Time `json:"leave_time"`
+	Duration  int       `json:"duration"` // Seconds in the meeting
+}
+
+// ListParticipantsResponse represents the response from the past meeting participants API endpoint
+type ListParticipantsResponse struct {
+	PageCount     int           `json:"page_count"`
+	PageSize      int           `json:"page_size"`
+	TotalRecords  int           `json:"total_records"`
+	NextPageToken string        `json:"next_page_token,omitempty"`
+	Participants  []Participant `json:"participants"`
+}
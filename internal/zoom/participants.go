@@ -0,0 +1,59 @@
+package zoom
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// maxParticipantsPageSize is the largest page size the past meeting participants API accepts
+const maxParticipantsPageSize = 300
+
+// ParticipantClient defines the interface for looking up who hosted and attended a meeting
+type ParticipantClient interface {
+	ListPastMeetingParticipants(ctx context.Context, meetingUUID string) ([]Participant, error)
+	GetUser(ctx context.Context, userID string) (*User, error)
+}
+
+// ListPastMeetingParticipants retrieves every participant of a past meeting instance
+// Requires the report:read or meeting:read:past_participant scope and a Pro or higher plan.
+func (c *ZoomClient) ListPastMeetingParticipants(ctx context.Context, meetingUUID string) ([]Participant, error) {
+	var participants []Participant
+	nextPageToken := ""
+	pageNum := 1
+
+	for {
+		queryParams := url.Values{}
+		queryParams.Set("page_size", strconv.Itoa(maxParticipantsPageSize))
+		if nextPageToken != "" {
+			queryParams.Set("next_page_token", nextPageToken)
+		}
+		endpoint := fmt.Sprintf("%s/past_meetings/%s/participants?%s", c.baseURL, EncodeMeetingUUID(meetingUUID), queryParams.Encode())
+
+		var response ListParticipantsResponse
+		if err := c.getJSON(ctx, endpoint, &response); err != nil {
+			return nil, fmt.Errorf("failed to list participants (page %d): %w", pageNum, err)
+		}
+		participants = append(participants, response.Participants...)
+
+		if response.NextPageToken == "" || response.NextPageToken == nextPageToken {
+			break
+		}
+		nextPageToken = response.NextPageToken
+		pageNum++
+	}
+
+	return participants, nil
+}
+
+// GetUser retrieves a single user by ID or email address
+func (c *ZoomClient) GetUser(ctx context.Context, userID string) (*User, error) {
+	endpoint := fmt.Sprintf("%s/users/%s", c.baseURL, url.PathEscape(userID))
+
+	var user User
+	if err := c.getJSON(ctx, endpoint, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
@@ -0,0 +1,50 @@
+package zoom
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestListPastMeetingParticipants(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.EscapedPath() {
+		case "/past_meetings/%252Fabc%252F%252Bdef%253D%253D/participants":
+			if r.URL.Query().Get("next_page_token") == "" {
+				w.Write([]byte(`{"next_page_token": "page_2", "participants": [{"name": "Jane Smith", "user_email": "jane@example.com", "duration": 1800}]}`))
+				return
+			}
+			w.Write([]byte(`{"participants": [{"name": "Guest Caller", "duration": 60}]}`))
+		case "/users/host-id":
+			w.Write([]byte(`{"id": "host-id", "email": "host@example.com", "display_name": "Host Person"}`))
+		default:
+			t.Errorf("Unexpected request %s", r.URL.EscapedPath())
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"code": 3001, "message": "Meeting does not exist"}`))
+		}
+	}))
+	defer server.Close()
+
+	retryClient := NewRetryHTTPClient(HTTPClientConfig{Timeout: 5 * time.Second})
+	client := NewZoomClient(NewAuthenticatedRetryClient(retryClient, staticAuth{}), server.URL)
+
+	participants, err := client.ListPastMeetingParticipants(context.Background(), "/abc/+def==")
+	if err != nil {
+		t.Fatalf("ListPastMeetingParticipants failed: %v", err)
+	}
+	if len(participants) != 2 || participants[0].UserEmail != "jane@example.com" || participants[1].Name != "Guest Caller" {
+		t.Errorf("Expected both pages of participants, got %+v", participants)
+	}
+
+	user, err := client.GetUser(context.Background(), "host-id")
+	if err != nil {
+		t.Fatalf("GetUser failed: %v", err)
+	}
+	if user.Email != "host@example.com" || user.DisplayName != "Host Person" {
+		t.Errorf("Expected the host user, got %+v", user)
+	}
+}
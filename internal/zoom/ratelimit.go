@@ -0,0 +1,217 @@
+package zoom
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RateTier is the Zoom plan tier that determines an app's API rate limits
+type RateTier string
+
+// Rate tiers; business covers Business, Business Plus, Education and Enterprise accounts
+const (
+	RateTierAuto     RateTier = "auto"
+	RateTierFree     RateTier = "free"
+	RateTierPro      RateTier = "pro"
+	RateTierBusiness RateTier = "business"
+)
+
+// DefaultRateTier is assumed when the tier is auto and Zoom does not report it
+const DefaultRateTier = RateTierPro
+
+// RateLimits holds the request pacing and concurrency used for the Zoom API
+type RateLimits struct {
+	RequestsPerSecond     float64 // Requests started per second (0 = unpaced)
+	MaxConcurrentRequests int     // Requests in flight at once (0 = unlimited)
+}
+
+// Limits returns safe defaults for the tier: 80% of Zoom's per-second limit for Medium
+// category APIs (list recordings, list users), leaving headroom for other apps on the account
+func (t RateTier) Limits() RateLimits {
+	switch t {
+	case RateTierFree:
+		return RateLimits{RequestsPerSecond: 1.6, MaxConcurrentRequests: 1}
+	case RateTierBusiness:
+		return RateLimits{RequestsPerSecond: 48, MaxConcurrentRequests: 8}
+	default:
+		return RateLimits{RequestsPerSecond: 16, MaxConcurrentRequests: 4}
+	}
+}
+
+// ParseRateTier validates a configured tier name
+func ParseRateTier(value string) (RateTier, error) {
+	switch tier := RateTier(strings.ToLower(value)); tier {
+	case "":
+		return RateTierAuto, nil
+	case RateTierAuto, RateTierFree, RateTierPro, RateTierBusiness:
+		return tier, nil
+	}
+	return "", fmt.Errorf("unknown Zoom rate tier %q (expected auto, free, pro or business)", value)
+}
+
+// RateTierFromHeaders derives the tier from the per-second limit Zoom reports for a Medium category API
+// ok is false when the response does not carry a per-second Medium limit.
+func RateTierFromHeaders(header http.Header) (tier RateTier, ok bool) {
+	if !strings.EqualFold(header.Get("X-RateLimit-Category"), "Medium") ||
+		!strings.EqualFold(header.Get("X-RateLimit-Type"), "QPS") {
+		return "", false
+	}
+	limit, err := strconv.Atoi(header.Get("X-RateLimit-Limit"))
+	if err != nil || limit <= 0 {
+		return "", false
+	}
+
+	switch {
+	case limit <= 2:
+		return RateTierFree, true
+	case limit <= 20:
+		return RateTierPro, true
+	default:
+		return RateTierBusiness, true
+	}
+}
+
+// DetectRateTier asks Zoom for the account's rate tier with a minimal Medium category request
+func (c *ZoomClient) DetectRateTier(ctx context.Context) (RateTier, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/users?page_size=1", nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("request failed: %w", err)
+	}
+	resp.Body.Close()
+
+	tier, ok := RateTierFromHeaders(resp.Header)
+	if !ok {
+		return "", fmt.Errorf("zoom did not report a per-second rate limit")
+	}
+	return tier, nil
+}
+
+// SetRateLimits changes the pacing and concurrency of the client's API requests
+func (c *ZoomClient) SetRateLimits(limits RateLimits) {
+	c.httpClient.retryClient.SetRateLimits(limits)
+}
+
+// Adaptive pacing bounds: a 429 doubles the request interval up to maxSlowdown times the
+// configured interval, and each success shrinks it by recoveryFactor back toward the configured one
+const (
+	maxSlowdown    = 8
+	recoveryFactor = 0.95
+)
+
+// pacer spaces requests to a rate, caps how many are in flight and slows down after 429s
+type pacer struct {
+	mu       sync.Mutex
+	base     time.Duration // Configured interval between request starts (0 = unpaced)
+	interval time.Duration // Current interval, raised after 429 responses
+	next     time.Time     // Earliest start of the next request
+	slots    chan struct{} // Concurrency slots (nil = unlimited)
+}
+
+// newPacer creates a pacer for the given limits
+func newPacer(limits RateLimits) *pacer {
+	p := &pacer{}
+	p.setLimits(limits)
+	return p
+}
+
+// setLimits replaces the pacing and concurrency; requests already in flight keep their slots
+func (p *pacer) setLimits(limits RateLimits) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.base = 0
+	if limits.RequestsPerSecond > 0 {
+		p.base = time.Duration(float64(time.Second) / limits.RequestsPerSecond)
+	}
+	p.interval = p.base
+
+	p.slots = nil
+	if limits.MaxConcurrentRequests > 0 {
+		p.slots = make(chan struct{}, limits.MaxConcurrentRequests)
+	}
+}
+
+// acquire waits for a concurrency slot and returns the function that releases it
+func (p *pacer) acquire(ctx context.Context) (func(), error) {
+	p.mu.Lock()
+	slots := p.slots
+	p.mu.Unlock()
+
+	if slots == nil {
+		return func() {}, nil
+	}
+	select {
+	case slots <- struct{}{}:
+		return func() { <-slots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// wait blocks until the next request may start
+func (p *pacer) wait(ctx context.Context) error {
+	p.mu.Lock()
+	if p.interval == 0 {
+		p.mu.Unlock()
+		return nil
+	}
+	now := time.Now()
+	start := p.next
+	if start.Before(now) {
+		start = now
+	}
+	p.next = start.Add(p.interval)
+	p.mu.Unlock()
+
+	delay := time.Until(start)
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// observe adapts the pace to a response status: slower after a 429, gradually back after successes
+func (p *pacer) observe(statusCode int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.base == 0 {
+		return
+	}
+	switch {
+	case statusCode == http.StatusTooManyRequests:
+		p.interval *= 2
+		if p.interval > p.base*maxSlowdown {
+			p.interval = p.base * maxSlowdown
+		}
+	case statusCode < 400 && p.interval > p.base:
+		p.interval = time.Duration(float64(p.interval) * recoveryFactor)
+		if p.interval < p.base {
+			p.interval = p.base
+		}
+	}
+}
+
+// currentInterval returns the interval between request starts
+func (p *pacer) currentInterval() time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.interval
+}
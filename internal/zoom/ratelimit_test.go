@@ -0,0 +1,162 @@
+package zoom
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRateTierFromHeaders(t *testing.T) {
+	tests := []struct {
+		name     string
+		category string
+		limitTyp string
+		limit    string
+		want     RateTier
+		wantOK   bool
+	}{
+		{name: "free", category: "Medium", limitTyp: "QPS", limit: "2", want: RateTierFree, wantOK: true},
+		{name: "pro", category: "Medium", limitTyp: "QPS", limit: "20", want: RateTierPro, wantOK: true},
+		{name: "business", category: "medium", limitTyp: "qps", limit: "60", want: RateTierBusiness, wantOK: true},
+		{name: "daily limit", category: "Medium", limitTyp: "Daily-limit", limit: "60000"},
+		{name: "other category", category: "Heavy", limitTyp: "QPS", limit: "10"},
+		{name: "no headers"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			if tt.category != "" {
+				header.Set("X-RateLimit-Category", tt.category)
+				header.Set("X-RateLimit-Type", tt.limitTyp)
+				header.Set("X-RateLimit-Limit", tt.limit)
+			}
+			tier, ok := RateTierFromHeaders(header)
+			if tier != tt.want || ok != tt.wantOK {
+				t.Errorf("RateTierFromHeaders() = %q, %v, want %q, %v", tier, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestParseRateTier(t *testing.T) {
+	if tier, err := ParseRateTier(""); err != nil || tier != RateTierAuto {
+		t.Errorf("Expected an empty tier to mean auto, got %q, %v", tier, err)
+	}
+	if tier, err := ParseRateTier("Business"); err != nil || tier != RateTierBusiness {
+		t.Errorf("Expected business, got %q, %v", tier, err)
+	}
+	if _, err := ParseRateTier("platinum"); err == nil {
+		t.Error("Expected an unknown tier to be rejected")
+	}
+}
+
+func TestDetectRateTier(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/users" || r.URL.Query().Get("page_size") != "1" {
+			t.Errorf("Unexpected probe request %s", r.URL.String())
+		}
+		w.Header().Set("X-RateLimit-Category", "Medium")
+		w.Header().Set("X-RateLimit-Type", "QPS")
+		w.Header().Set("X-RateLimit-Limit", "60")
+		w.Write([]byte(`{"users": []}`))
+	}))
+	defer server.Close()
+
+	retryClient := NewRetryHTTPClient(HTTPClientConfig{Timeout: 5 * time.Second})
+	client := NewZoomClient(NewAuthenticatedRetryClient(retryClient, staticAuth{}), server.URL)
+
+	tier, err := client.DetectRateTier(context.Background())
+	if err != nil || tier != RateTierBusiness {
+		t.Errorf("Expected the business tier, got %q, %v", tier, err)
+	}
+}
+
+func TestRetryHTTPClient_PacesRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	client := NewRetryHTTPClient(HTTPClientConfig{
+		Timeout:    5 * time.Second,
+		RateLimits: RateLimits{RequestsPerSecond: 50},
+	})
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		req, _ := http.NewRequest("GET", server.URL, nil)
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+		resp.Body.Close()
+	}
+
+	// Five requests at 50/s need at least four 20ms intervals
+	if elapsed := time.Since(start); elapsed < 80*time.Millisecond {
+		t.Errorf("Expected paced requests to take at least 80ms, took %v", elapsed)
+	}
+}
+
+func TestRetryHTTPClient_LimitsConcurrency(t *testing.T) {
+	var inFlight, maxInFlight int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			seen := atomic.LoadInt32(&maxInFlight)
+			if current <= seen || atomic.CompareAndSwapInt32(&maxInFlight, seen, current) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	client := NewRetryHTTPClient(HTTPClientConfig{Timeout: 5 * time.Second})
+	client.SetRateLimits(RateLimits{MaxConcurrentRequests: 2})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, _ := http.NewRequest("GET", server.URL, nil)
+			if resp, err := client.Do(req); err == nil {
+				resp.Body.Close()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if maxInFlight > 2 {
+		t.Errorf("Expected at most 2 requests in flight, saw %d", maxInFlight)
+	}
+}
+
+func TestPacer_AdaptsToRateLimitResponses(t *testing.T) {
+	p := newPacer(RateLimits{RequestsPerSecond: 10})
+	base := p.currentInterval()
+
+	p.observe(http.StatusTooManyRequests)
+	if got := p.currentInterval(); got != 2*base {
+		t.Errorf("Expected a 429 to double the interval to %v, got %v", 2*base, got)
+	}
+
+	for i := 0; i < 10; i++ {
+		p.observe(http.StatusTooManyRequests)
+	}
+	if got := p.currentInterval(); got != maxSlowdown*base {
+		t.Errorf("Expected the slowdown to be capped at %v, got %v", maxSlowdown*base, got)
+	}
+
+	for i := 0; i < 100; i++ {
+		p.observe(http.StatusOK)
+	}
+	if got := p.currentInterval(); got != base {
+		t.Errorf("Expected successes to recover the configured interval %v, got %v", base, got)
+	}
+}
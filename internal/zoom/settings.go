@@ -0,0 +1,70 @@
+package zoom
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// RecordingSettings are the sharing settings of a meeting's cloud recordings
+type RecordingSettings struct {
+	ShareRecording          string `json:"share_recording"`          // publicly, internally or none
+	RecordingAuthentication bool   `json:"recording_authentication"` // Only authenticated users can view
+	Password                string `json:"password"`                 // Passcode viewers must enter ("" = none)
+	OnDemand                bool   `json:"on_demand"`                // Registration is required to view
+	ViewerDownload          bool   `json:"viewer_download"`          // Viewers can download the recording
+}
+
+// RecordingSettingsClient defines the interface for the settings of a meeting's recordings
+type RecordingSettingsClient interface {
+	GetRecordingSettings(ctx context.Context, meetingUUID string) (*RecordingSettings, error)
+	SetRecordingPassword(ctx context.Context, meetingUUID, password string) error
+}
+
+// GetRecordingSettings retrieves the settings of a meeting's cloud recordings
+// Requires the recording:read:admin scope.
+func (c *ZoomClient) GetRecordingSettings(ctx context.Context, meetingUUID string) (*RecordingSettings, error) {
+	endpoint := fmt.Sprintf("%s/meetings/%s/recordings/settings", c.baseURL, EncodeMeetingUUID(meetingUUID))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recording settings of meeting %s: %w", meetingUUID, err)
+	}
+	defer resp.Body.Close()
+
+	var settings RecordingSettings
+	if err := json.NewDecoder(resp.Body).Decode(&settings); err != nil {
+		return nil, fmt.Errorf("failed to decode recording settings: %w", err)
+	}
+	return &settings, nil
+}
+
+// SetRecordingPassword sets the passcode of a meeting's cloud recordings; "" removes it
+// Requires the recording:write:admin scope.
+func (c *ZoomClient) SetRecordingPassword(ctx context.Context, meetingUUID, password string) error {
+	endpoint := fmt.Sprintf("%s/meetings/%s/recordings/settings", c.baseURL, EncodeMeetingUUID(meetingUUID))
+
+	body, err := json.Marshal(map[string]string{"password": password})
+	if err != nil {
+		return fmt.Errorf("failed to encode recording settings: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPatch, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to update recording settings of meeting %s: %w", meetingUUID, err)
+	}
+	resp.Body.Close()
+	return nil
+}
@@ -0,0 +1,44 @@
+package zoom
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// TrashTypeMeetingRecordings lists meetings whose recordings were moved to the trash as a whole
+const TrashTypeMeetingRecordings = "meeting_recordings"
+
+// TrashClient defines the interface for Zoom recordings in the trash
+type TrashClient interface {
+	GetAllUserTrashRecordings(ctx context.Context, userID string, params ListRecordingsParams) ([]*Recording, error)
+	RecoverMeetingRecordings(ctx context.Context, meetingUUID string) error
+}
+
+// GetAllUserTrashRecordings retrieves the user's meeting recordings that are in the trash
+// Zoom keeps deleted recordings there for 30 days before removing them for good.
+func (c *ZoomClient) GetAllUserTrashRecordings(ctx context.Context, userID string, params ListRecordingsParams) ([]*Recording, error) {
+	params.Trash = true
+	params.TrashType = TrashTypeMeetingRecordings
+	return c.GetAllUserRecordings(ctx, userID, params)
+}
+
+// RecoverMeetingRecordings restores a meeting's recordings from the trash
+// Requires the recording:write:admin scope.
+func (c *ZoomClient) RecoverMeetingRecordings(ctx context.Context, meetingUUID string) error {
+	endpoint := fmt.Sprintf("%s/meetings/%s/recordings/status", c.baseURL, EncodeMeetingUUID(meetingUUID))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, endpoint, strings.NewReader(`{"action":"recover"}`))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to recover recordings of meeting %s: %w", meetingUUID, err)
+	}
+	resp.Body.Close()
+	return nil
+}
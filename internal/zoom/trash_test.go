@@ -0,0 +1,71 @@
+package zoom
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestGetAllUserTrashRecordings(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/users/host@example.com/recordings" {
+			t.Errorf("Unexpected request %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("trash") != "true" || r.URL.Query().Get("trash_type") != TrashTypeMeetingRecordings {
+			t.Errorf("Expected a trash query, got %s", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"meetings": [{"uuid": "deleted-1", "topic": "Deleted", "recording_files": [{"id": "f1", "file_type": "MP4"}]}]}`))
+	}))
+	defer server.Close()
+
+	retryClient := NewRetryHTTPClient(HTTPClientConfig{Timeout: 5 * time.Second})
+	client := NewZoomClient(NewAuthenticatedRetryClient(retryClient, staticAuth{}), server.URL)
+
+	recordings, err := client.GetAllUserTrashRecordings(context.Background(), "host@example.com", ListRecordingsParams{})
+	if err != nil {
+		t.Fatalf("GetAllUserTrashRecordings failed: %v", err)
+	}
+	if len(recordings) != 1 || recordings[0].UUID != "deleted-1" {
+		t.Fatalf("Expected the trash recording, got %+v", recordings)
+	}
+}
+
+func TestRecoverMeetingRecordings(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if r.Method != http.MethodPut || r.URL.EscapedPath() != "/meetings/"+EncodeMeetingUUID("/abc==")+"/recordings/status" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.EscapedPath())
+		}
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != `{"action":"recover"}` {
+			t.Errorf("Expected a recover action on attempt %d, got %q", attempts, body)
+		}
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	retryClient := NewRetryHTTPClient(HTTPClientConfig{
+		Timeout:         5 * time.Second,
+		MaxRetries:      1,
+		RetryWaitMin:    time.Millisecond,
+		RetryWaitMax:    time.Millisecond,
+		RetryableStatus: []int{http.StatusServiceUnavailable},
+	})
+	client := NewZoomClient(NewAuthenticatedRetryClient(retryClient, staticAuth{}), server.URL)
+
+	if err := client.RecoverMeetingRecordings(context.Background(), "/abc=="); err != nil {
+		t.Fatalf("RecoverMeetingRecordings failed: %v", err)
+	}
+	if attempts != 2 {
+		t.Errorf("Expected the request to be retried once, got %d attempts", attempts)
+	}
+}
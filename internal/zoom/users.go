@@ -0,0 +1,101 @@
+package zoom
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// User statuses accepted by the list users API
+const (
+	UserStatusActive   = "active"
+	UserStatusInactive = "inactive"
+	UserStatusPending  = "pending"
+)
+
+// maxUsersPageSize is the largest page size the list users API accepts
+const maxUsersPageSize = 300
+
+// UserClient defines the interface for Zoom user API operations
+type UserClient interface {
+	ListUsers(ctx context.Context, params ListUsersParams) (*ListUsersResponse, error)
+	GetAllUsers(ctx context.Context, params ListUsersParams) ([]User, error)
+}
+
+// ListUsersParams holds parameters for listing users
+type ListUsersParams struct {
+	Status        string // User status: active, inactive or pending (default: active)
+	RoleID        string // Only return users with this role
+	PageSize      int    // Number of records per page (default: 300, max: 300)
+	NextPageToken string // Next page token for pagination
+}
+
+// ListUsers retrieves a single page of users in the account
+func (c *ZoomClient) ListUsers(ctx context.Context, params ListUsersParams) (*ListUsersResponse, error) {
+	queryParams := url.Values{}
+
+	status := params.Status
+	if status == "" {
+		status = UserStatusActive
+	}
+	queryParams.Set("status", status)
+
+	pageSize := params.PageSize
+	if pageSize <= 0 || pageSize > maxUsersPageSize {
+		pageSize = maxUsersPageSize
+	}
+	queryParams.Set("page_size", strconv.Itoa(pageSize))
+
+	if params.RoleID != "" {
+		queryParams.Set("role_id", params.RoleID)
+	}
+	if params.NextPageToken != "" {
+		queryParams.Set("next_page_token", params.NextPageToken)
+	}
+
+	endpoint := fmt.Sprintf("%s/users?%s", c.baseURL, queryParams.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result ListUsersResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &result, nil
+}
+
+// GetAllUsers retrieves every user matching params by following next_page_token
+func (c *ZoomClient) GetAllUsers(ctx context.Context, params ListUsersParams) ([]User, error) {
+	var users []User
+	pageNum := 1
+
+	for {
+		response, err := c.ListUsers(ctx, params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list users (page %d): %w", pageNum, err)
+		}
+
+		users = append(users, response.Users...)
+
+		if response.NextPageToken == "" || response.NextPageToken == params.NextPageToken {
+			break
+		}
+		params.NextPageToken = response.NextPageToken
+		pageNum++
+	}
+
+	return users, nil
+}
@@ -0,0 +1,72 @@
+package zoom
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// staticAuth returns a fixed token without contacting the Zoom OAuth endpoint
+type staticAuth struct{}
+
+func (staticAuth) GetAccessToken(ctx context.Context) (*AccessToken, error) {
+	return &AccessToken{AccessToken: "test_token", TokenType: "Bearer"}, nil
+}
+
+func (staticAuth) ValidateScopes(token *AccessToken, requiredScopes []string) error {
+	return nil
+}
+
+func TestGetAllUsers(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		query := r.URL.Query()
+
+		if r.URL.Path != "/users" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		if query.Get("status") != "inactive" || query.Get("role_id") != "2" || query.Get("page_size") != "300" {
+			t.Errorf("Unexpected query: %s", r.URL.RawQuery)
+		}
+		if r.Header.Get("Authorization") != "Bearer test_token" {
+			t.Errorf("Expected authorization header, got %q", r.Header.Get("Authorization"))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		switch query.Get("next_page_token") {
+		case "":
+			w.Write([]byte(`{"page_size": 300, "total_records": 3, "next_page_token": "page_2", "users": [
+				{"id": "u1", "email": "one@example.com", "type": 1, "role_id": "2", "status": "inactive", "group_ids": ["g1"]},
+				{"id": "u2", "email": "two@example.com", "type": 2, "role_id": "2", "status": "inactive"}
+			]}`))
+		case "page_2":
+			w.Write([]byte(`{"page_size": 300, "total_records": 3, "users": [
+				{"id": "u3", "email": "three@example.com", "type": 1, "role_id": "2", "status": "inactive", "group_ids": ["g2", "g1"]}
+			]}`))
+		default:
+			t.Errorf("Unexpected page token %s", query.Get("next_page_token"))
+		}
+	}))
+	defer server.Close()
+
+	retryClient := NewRetryHTTPClient(HTTPClientConfig{Timeout: 5 * time.Second})
+	client := NewZoomClient(NewAuthenticatedRetryClient(retryClient, staticAuth{}), server.URL)
+
+	users, err := client.GetAllUsers(context.Background(), ListUsersParams{Status: UserStatusInactive, RoleID: "2"})
+	if err != nil {
+		t.Fatalf("GetAllUsers failed: %v", err)
+	}
+
+	if requests != 2 {
+		t.Errorf("Expected 2 requests, got %d", requests)
+	}
+	if len(users) != 3 || users[2].Email != "three@example.com" {
+		t.Fatalf("Unexpected users: %+v", users)
+	}
+	if !users[2].InGroup("g1") || users[1].InGroup("g1") {
+		t.Errorf("Unexpected group membership: %+v", users)
+	}
+}
@@ -0,0 +1,149 @@
+package zoom
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// maxWebinarsPageSize is the largest page size the list webinars API accepts
+const maxWebinarsPageSize = 300
+
+// WebinarClient defines the interface for Zoom webinar recording operations
+type WebinarClient interface {
+	ListUserWebinars(ctx context.Context, userID string, nextPageToken string) (*ListWebinarsResponse, error)
+	ListWebinarInstances(ctx context.Context, webinarID int64) ([]WebinarInstance, error)
+	GetAllUserWebinarRecordings(ctx context.Context, userID string, params ListRecordingsParams) ([]*Recording, error)
+}
+
+// ListUserWebinars retrieves a single page of webinars hosted by a user
+func (c *ZoomClient) ListUserWebinars(ctx context.Context, userID string, nextPageToken string) (*ListWebinarsResponse, error) {
+	queryParams := url.Values{}
+	queryParams.Set("page_size", strconv.Itoa(maxWebinarsPageSize))
+	if nextPageToken != "" {
+		queryParams.Set("next_page_token", nextPageToken)
+	}
+
+	endpoint := fmt.Sprintf("%s/users/%s/webinars?%s", c.baseURL, url.PathEscape(userID), queryParams.Encode())
+
+	var result ListWebinarsResponse
+	if err := c.getJSON(ctx, endpoint, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ListWebinarInstances retrieves the past occurrences of a webinar
+// A recurring webinar has one instance, each with its own recording, per occurrence.
+func (c *ZoomClient) ListWebinarInstances(ctx context.Context, webinarID int64) ([]WebinarInstance, error) {
+	endpoint := fmt.Sprintf("%s/past_webinars/%d/instances", c.baseURL, webinarID)
+
+	var result ListWebinarInstancesResponse
+	if err := c.getJSON(ctx, endpoint, &result); err != nil {
+		return nil, err
+	}
+	return result.Webinars, nil
+}
+
+// GetAllUserWebinarRecordings retrieves the recordings of every past webinar instance hosted by a user
+// Instances outside params.From and params.To are skipped, as are instances that were not recorded.
+func (c *ZoomClient) GetAllUserWebinarRecordings(ctx context.Context, userID string, params ListRecordingsParams) ([]*Recording, error) {
+	var webinars []Webinar
+	nextPageToken := ""
+	pageNum := 1
+
+	for {
+		response, err := c.ListUserWebinars(ctx, userID, nextPageToken)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list webinars (page %d): %w", pageNum, err)
+		}
+
+		webinars = append(webinars, response.Webinars...)
+
+		if response.NextPageToken == "" || response.NextPageToken == nextPageToken {
+			break
+		}
+		nextPageToken = response.NextPageToken
+		pageNum++
+	}
+
+	var recordings []*Recording
+	seen := make(map[string]bool)
+
+	for _, webinar := range webinars {
+		instances, err := c.ListWebinarInstances(ctx, webinar.ID)
+		if err != nil {
+			if isNotFound(err) {
+				continue // The webinar has not taken place yet
+			}
+			return nil, fmt.Errorf("failed to list instances of webinar %d: %w", webinar.ID, err)
+		}
+
+		for _, instance := range instances {
+			if seen[instance.UUID] || !inDateRange(instance.StartTime, params) {
+				continue
+			}
+			seen[instance.UUID] = true
+
+			recording, err := c.GetMeetingRecordings(ctx, instance.UUID)
+			if err != nil {
+				if isNotFound(err) {
+					continue // The instance was not recorded to the cloud
+				}
+				return nil, fmt.Errorf("failed to get recordings for webinar %d instance %s: %w", webinar.ID, instance.UUID, err)
+			}
+			recordings = append(recordings, recording)
+		}
+	}
+
+	return recordings, nil
+}
+
+// getJSON performs an authenticated GET request and decodes the JSON response into result
+func (c *ZoomClient) getJSON(ctx context.Context, endpoint string, result interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// isNotFound reports whether err is a Zoom 404 response
+func isNotFound(err error) bool {
+	var zoomErr *ZoomAPIError
+	if errors.As(err, &zoomErr) {
+		return zoomErr.Status == http.StatusNotFound
+	}
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode == http.StatusNotFound
+	}
+	return false
+}
+
+// inDateRange reports whether t falls within the From and To dates of params
+// To is inclusive of the whole day; a nil bound is open.
+func inDateRange(t time.Time, params ListRecordingsParams) bool {
+	if params.From != nil && t.Before(*params.From) {
+		return false
+	}
+	if params.To != nil && !t.Before(params.To.AddDate(0, 0, 1)) {
+		return false
+	}
+	return true
+}
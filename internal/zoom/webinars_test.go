@@ -0,0 +1,56 @@
+package zoom
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestGetAllUserWebinarRecordings(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/users/host@example.com/webinars":
+			if r.URL.Query().Get("next_page_token") == "" {
+				w.Write([]byte(`{"next_page_token": "page_2", "webinars": [{"uuid": "w1", "id": 111, "topic": "Weekly"}]}`))
+				return
+			}
+			w.Write([]byte(`{"webinars": [{"uuid": "w2", "id": 222, "topic": "Upcoming"}]}`))
+		case "/past_webinars/111/instances":
+			w.Write([]byte(`{"webinars": [
+				{"uuid": "inst-a", "start_time": "2024-03-05T15:00:00Z"},
+				{"uuid": "inst-b", "start_time": "2024-03-12T15:00:00Z"},
+				{"uuid": "inst-old", "start_time": "2023-01-01T15:00:00Z"}
+			]}`))
+		case "/past_webinars/222/instances":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"code": 3001, "message": "Webinar does not exist"}`))
+		case "/meetings/inst-a/recordings":
+			w.Write([]byte(`{"uuid": "inst-a", "id": 111, "topic": "Weekly", "type": 5, "recording_files": [{"id": "f1", "file_type": "MP4"}]}`))
+		case "/meetings/inst-b/recordings":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"code": 3301, "message": "There is no recording for this meeting"}`))
+		default:
+			t.Errorf("Unexpected request %s", r.URL.Path)
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	retryClient := NewRetryHTTPClient(HTTPClientConfig{Timeout: 5 * time.Second})
+	client := NewZoomClient(NewAuthenticatedRetryClient(retryClient, staticAuth{}), server.URL)
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+	recordings, err := client.GetAllUserWebinarRecordings(context.Background(), "host@example.com", ListRecordingsParams{From: &from, To: &to})
+	if err != nil {
+		t.Fatalf("GetAllUserWebinarRecordings failed: %v", err)
+	}
+
+	if len(recordings) != 1 || recordings[0].UUID != "inst-a" || len(recordings[0].RecordingFiles) != 1 {
+		t.Fatalf("Expected only the recorded instance in range, got %+v", recordings)
+	}
+}